@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package main
@@ -7,6 +7,8 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path"
+	"time"
 
 	kzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	// Uncomment the following line to load the gcp plugin (only required to authenticate against GKE clusters).
@@ -15,6 +17,7 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/metricsexporter"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/vmo"
 	"go.uber.org/zap"
@@ -31,6 +34,18 @@ var (
 	buildDate      string
 	certdir        string
 	port           string
+	printManifests string
+	metricsTLS     bool
+	metricsCAFile  string
+	workers        int
+	reconcileRBAC  bool
+	clusterDomain  string
+	strictQuorum   bool
+	fixDatasources bool
+	devSkipHealth  bool
+	nodeTopology   bool
+	nodeDebounce   time.Duration
+	leaderElection bool
 	zapOptions     = kzap.Options{}
 )
 
@@ -42,6 +57,12 @@ func main() {
 	if namespace == "" {
 		zap.S().Fatalf("A namespace must be specified")
 	}
+	if workers < 1 {
+		zap.S().Fatalf("The number of workers must be at least 1, got %d", workers)
+	}
+	if err := resources.SetClusterDomain(clusterDomain); err != nil {
+		zap.S().Fatalf("Error setting the cluster domain: %s", err.Error())
+	}
 
 	// Initialize the images to use
 	err := config.InitComponentDetails()
@@ -49,11 +70,37 @@ func main() {
 		zap.S().Fatalf("Error identifying docker images: %s", err.Error())
 	}
 
+	if printManifests != "" {
+		if err := runPrintManifests(printManifests); err != nil {
+			zap.S().Fatalf("Error printing manifests: %s", err.Error())
+		}
+		return
+	}
+
 	zap.S().Debugf("Creating new controller in namespace %s.", namespace)
 	controller, err := vmo.NewController(namespace, configmapName, buildVersion, kubeconfig, masterURL, watchNamespace, watchVmi)
 	if err != nil {
 		zap.S().Fatalf("Error creating the controller: %s", err.Error())
 	}
+	if reconcileRBAC {
+		controller.EnableOperatorRBACReconcile()
+	}
+	if strictQuorum {
+		controller.EnableStrictMasterQuorum()
+	}
+	if fixDatasources {
+		controller.EnableGrafanaDatasourceCorrection()
+	}
+	if nodeTopology {
+		controller.EnableNodeTopologyReconcile(nodeDebounce)
+	}
+	if leaderElection {
+		controller.EnableLeaderElection()
+	}
+	if devSkipHealth {
+		zap.S().Warn("The OpenSearch health checks are skipped, the OpenSearch cluster is not protected from unsafe updates. This is for development only and must never be used in production")
+		controller.SkipOpenSearchHealthChecks()
+	}
 
 	_, err = vmo.CreateCertificates(certdir)
 	if err != nil {
@@ -63,13 +110,35 @@ func main() {
 
 	vmo.StartHTTPServer(controller, certdir, port)
 
-	metricsexporter.StartMetricsServer()
+	var metricsServerTLS *metricsexporter.MetricsServerTLS
+	if metricsTLS {
+		metricsServerTLS = &metricsexporter.MetricsServerTLS{
+			CertFile:     path.Join(certdir, "tls.crt"),
+			KeyFile:      path.Join(certdir, "tls.key"),
+			ClientCAFile: metricsCAFile,
+		}
+	}
+	metricsexporter.StartMetricsServer(metricsServerTLS)
 
-	if err = controller.Run(1); err != nil {
+	if err = controller.Run(workers); err != nil {
 		zap.S().Fatalf("Error running controller: %s", err.Error())
 	}
 }
 
+// runPrintManifests prints the manifests generated for the VMI in the given file, or read from stdin if the file is "-"
+func runPrintManifests(file string) error {
+	reader := os.Stdin
+	if file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		reader = f
+	}
+	return vmo.PrintManifests(reader, os.Stdout, config.NewDefaultConfig())
+}
+
 func init() {
 	flag.StringVar(&kubeconfig, "kubeconfig", "", "Path to a kubeconfig. Only required if out-of-cluster.")
 	flag.StringVar(&masterURL, "master", "", "The address of the Kubernetes API server. Overrides any value in kubeconfig. Only required if out-of-cluster.")
@@ -79,6 +148,18 @@ func init() {
 	flag.StringVar(&configmapName, "configmapName", config.DefaultOperatorConfigmapName, "The configmap name containing the operator config")
 	flag.StringVar(&certdir, "certdir", "/etc/certs", "the directory to initalize certificates into")
 	flag.StringVar(&port, "port", "8080", "VMO server HTTP port")
+	flag.BoolVar(&metricsTLS, "metricsTLS", false, "Serve the metrics over TLS, using the certificates in certdir. The metrics are served in plaintext if not set.")
+	flag.StringVar(&metricsCAFile, "metricsClientCAFile", "", "Optionally, a CA bundle file. When serving the metrics over TLS, scrapers must present a client certificate signed by this CA.")
+	flag.StringVar(&printManifests, "printManifests", "", "Optionally, a file containing a VMI ('-' for stdin). The manifests generated for the VMI are printed without applying them, and the operator exits.")
+	flag.IntVar(&workers, "workers", 1, "The number of VMIs reconciled concurrently, at least 1. More workers reduce the reconcile latency with many VMIs, at the cost of more load on the API server and OpenSearch.")
+	flag.BoolVar(&reconcileRBAC, "reconcileOperatorRBAC", false, "Reconcile the ClusterRole and ClusterRoleBinding of the operator itself when the operator starts and periodically, correcting them if they drifted. The operator must be allowed to manage them, by applying the verrazzano-monitoring-operator-rbac-reconcile.yaml overlay of the manifest. Leave unset if the RBAC of the operator is managed externally.")
+	flag.StringVar(&clusterDomain, "clusterDomain", constants.DefaultClusterDomain, "The DNS domain of the cluster, used to build the fully qualified names of the services.")
+	flag.BoolVar(&strictQuorum, "strictMasterQuorum", false, "Refuse to create or update an OpenSearch cluster with fewer than 3 or an even number of master nodes. Only a warning event is recorded if not set.")
+	flag.BoolVar(&fixDatasources, "correctGrafanaDatasources", false, "Correct the Prometheus datasources of the Grafana datasources configmaps which do not point to the expected Prometheus service. Only a warning event is recorded if not set.")
+	flag.BoolVar(&nodeTopology, "reconcileOnNodeTopology", false, "Reconcile the VMIs with OpenSearch enabled when the zone or availability domain labels of a node change, so the placement of the data nodes is re-evaluated.")
+	flag.DurationVar(&nodeDebounce, "nodeTopologyDebounce", 30*time.Second, "The delay after a node topology change before the VMIs are reconciled, so the changes of many nodes are reconciled together.")
+	flag.BoolVar(&leaderElection, "enable-leader-election", false, "Acquire a leader election Lease in the operator namespace before reconciling the VMIs, so that only one of several replicas of the operator reconciles them.")
+	flag.BoolVar(&devSkipHealth, "devSkipOSHealth", false, "Development only: skip the OpenSearch health checks gating updates, for clusters without a real OpenSearch. Never set in production.")
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "%s version %s\n", os.Args[0], buildVersion)
 		fmt.Fprintf(os.Stderr, "built %s\n", buildDate)
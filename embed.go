@@ -1,7 +1,8 @@
 // Copyright (c) 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
-// Package verrazzanomonitoringoperator is used to reference the embedded OpenSearch ISM policy files in the binary.
+// Package verrazzanomonitoringoperator is used to reference the embedded OpenSearch ISM policy files and the VMO
+// manifest in the binary.
 package verrazzanomonitoringoperator
 
 import (
@@ -11,7 +12,15 @@ import (
 //go:embed k8s/manifests/opensearch
 var openSearchISMPolicyFS embed.FS
 
+//go:embed k8s/manifests/verrazzano-monitoring-operator.yaml
+var operatorManifest []byte
+
 // GetEmbeddedISMPolicy returns the embedded openSearch ISM policies file system.
 func GetEmbeddedISMPolicy() embed.FS {
 	return openSearchISMPolicyFS
 }
+
+// GetEmbeddedOperatorManifest returns the embedded VMO manifest, which defines the RBAC of the VMO.
+func GetEmbeddedOperatorManifest() []byte {
+	return operatorManifest
+}
@@ -12,12 +12,16 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
+// +kubebuilder:validation:Enum=master;data;ingest;remote_cluster_client;transform;search
 type NodeRole string
 
 const (
-	MasterRole NodeRole = "master"
-	DataRole   NodeRole = "data"
-	IngestRole NodeRole = "ingest"
+	MasterRole              NodeRole = "master"
+	DataRole                NodeRole = "data"
+	IngestRole              NodeRole = "ingest"
+	RemoteClusterClientRole NodeRole = "remote_cluster_client"
+	TransformRole           NodeRole = "transform"
+	SearchRole              NodeRole = "search"
 	// OpportunisticStartTLS means that SMTP transactions are encrypted if STARTTLS is supported by the SMTP server.
 	// Otherwise, messages are sent in the clear.
 	OpportunisticStartTLS StartTLSType = "OpportunisticStartTLS"
@@ -28,6 +32,15 @@ const (
 	NoStartTLS StartTLSType = "NoStartTLS"
 )
 
+const (
+	// TCPLivenessProbe checks that the OpenSearch transport port is open
+	TCPLivenessProbe LivenessProbeType = "tcp"
+	// HTTPLivenessProbe checks the local OpenSearch cluster health with an HTTP GET
+	HTTPLivenessProbe LivenessProbeType = "http"
+	// ExecLivenessProbe checks the local OpenSearch cluster health by running curl within the container
+	ExecLivenessProbe LivenessProbeType = "exec"
+)
+
 type (
 
 	// VerrazzanoMonitoringInstanceSpec defines the attributes a user can specify when creating a VerrazzanoMonitoringInstance
@@ -115,6 +128,38 @@ type (
 		Replicas             int32     `json:"replicas,omitempty"`
 		Database             *Database `json:"database,omitempty"`
 		SMTP                 *SMTPInfo `json:"smtp,omitempty"`
+		// Scheme of the Grafana root URL, either http or https. Defaults to https
+		// +kubebuilder:validation:Enum=http;https
+		RootURLScheme string `json:"rootURLScheme,omitempty"`
+		// Name of the HTTP header holding the user name set by the auth proxy. Defaults to X-WEBAUTH-USER
+		AuthProxyHeaderName string `json:"authProxyHeaderName,omitempty"`
+		// Session affinity of the Grafana service, either None or ClientIP. Defaults to None
+		// +kubebuilder:validation:Enum=None;ClientIP
+		SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+		// DashboardFolders groups the dashboards provisioned from ConfigMaps into Grafana folders. If not set, the dashboards
+		// are in the General folder
+		DashboardFolders *DashboardFolders `json:"dashboardFolders,omitempty"`
+		// Additional hosts of the Grafana ingress. Each host gets its own rule and TLS secret
+		IngressHosts []IngressHost `json:"ingressHosts,omitempty"`
+		// UID the Grafana container runs as, which cannot be root. Defaults to the Grafana user (472)
+		// +kubebuilder:validation:Minimum:=1
+		RunAsUser *int64 `json:"runAsUser,omitempty"`
+		// GID owning the volumes of the Grafana pod, which the Grafana container also runs as. Defaults to the Grafana
+		// group (472)
+		// +kubebuilder:validation:Minimum:=0
+		FSGroup *int64 `json:"fsGroup,omitempty"`
+		// Send the Grafana cookies only over HTTPS. Defaults to true when Grafana is exposed over HTTPS, i.e. the VMI has
+		// a URI and the root URL scheme is https
+		CookieSecure *bool `json:"cookieSecure,omitempty"`
+		// SameSite attribute of the Grafana cookies, one of lax, strict, none or disabled. None requires secure cookies.
+		// If not set, the Grafana default is used
+		// +kubebuilder:validation:Enum=lax;strict;none;disabled
+		CookieSameSite string `json:"cookieSameSite,omitempty"`
+		// Grafana service account whose token is provisioned in a Secret, e.g. for automation. Not provisioned if not set
+		ServiceAccountToken *GrafanaServiceAccountToken `json:"serviceAccountToken,omitempty"`
+		// Plugins installed by the Grafana image from the GF_INSTALL_PLUGINS env var before Grafana starts, each a plugin ID
+		// optionally followed by a space and a version, e.g. grafana-piechart-panel 1.6.4
+		Plugins []string `json:"plugins,omitempty"`
 	}
 
 	// Prometheus details
@@ -152,6 +197,101 @@ type (
 		Nodes                []ElasticsearchNode     `json:"nodes,omitempty"`
 		Plugins              OpenSearchPlugins       `json:"plugins,omitempty"`
 		DisableDefaultPolicy bool                    `json:"disableDefaultPolicy,omitempty"`
+		// Retain the PVCs of removed data nodes for manual cleanup, defaults to true. Otherwise, they are deleted once the
+		// cluster is green, including the PVCs retained earlier
+		RetainOrphanedPVCs *bool `json:"retainOrphanedPVCs,omitempty"`
+		// Pause reconciling of the OpenSearch cluster, while the other components are still reconciled
+		Paused *bool `json:"paused,omitempty"`
+		// Log levels of OpenSearch loggers, keyed by logger name, e.g. org.opensearch.discovery: debug
+		Loggers map[string]string `json:"loggers,omitempty"`
+		// Restrict ingress to the OpenSearch ports with a NetworkPolicy, no NetworkPolicy is created if not set
+		NetworkPolicy *OpenSearchNetworkPolicy `json:"networkPolicy,omitempty"`
+		// Ingest pipelines managed by the VMO, pipelines removed from this list are deleted
+		IngestPipelines []IngestPipeline `json:"ingestPipelines,omitempty"`
+		// Name of the ingest pipeline run by default on the documents indexed into any index, the index.default_pipeline
+		// setting of new indices. The pipeline must be one of the ingest pipelines of the VMI or exist in OpenSearch
+		DefaultPipeline string `json:"defaultPipeline,omitempty"`
+		// Default settings of new indices, the OpenSearch defaults are used if not set
+		IndexDefaults *IndexDefaults `json:"indexDefaults,omitempty"`
+		// Limits of the mappings of new indices, the OpenSearch defaults are used if not set
+		MappingLimits *OpenSearchMappingLimits `json:"mappingLimits,omitempty"`
+		// Override the user and groups the OpenSearch pods run as
+		PodSecurityContext *OpenSearchPodSecurityContext `json:"podSecurityContext,omitempty"`
+		// Limits of the OpenSearch circuit breakers, the OpenSearch defaults are used if not set
+		CircuitBreakers *OpenSearchCircuitBreakers `json:"circuitBreakers,omitempty"`
+		// Seconds an OpenSearch master pod must be ready before it is considered available during a rolling restart.
+		// Defaults to 0
+		// +kubebuilder:validation:Minimum:=0
+		MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+		// Roll out updates of the OpenSearch master StatefulSets one pod at a time, the next pod is only updated when the
+		// cluster is green. Defaults to false, letting the StatefulSet controller roll out all master pods
+		StagedMasterRollout bool `json:"stagedMasterRollout,omitempty"`
+		// Component templates managed by the VMO, component templates removed from this list are deleted
+		ComponentTemplates []ComponentTemplate `json:"componentTemplates,omitempty"`
+		// Search backpressure settings, the OpenSearch defaults are used if not set
+		SearchBackpressure *OpenSearchSearchBackpressure `json:"searchBackpressure,omitempty"`
+		// Session affinity of the OpenSearch ingest and data service, either None or ClientIP. Defaults to None
+		// +kubebuilder:validation:Enum=None;ClientIP
+		SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+		// Type of the liveness probe of the OpenSearch nodes, either tcp, http or exec. Defaults to tcp for the
+		// master nodes and http for the other nodes
+		LivenessProbeType LivenessProbeType `json:"livenessProbeType,omitempty"`
+		// Startup probe of the OpenSearch nodes, the liveness probe only begins once the startup probe succeeded
+		StartupProbe *OpenSearchStartupProbe `json:"startupProbe,omitempty"`
+		// Shared filesystem snapshot repository of the OpenSearch nodes, mounted on every node and set as path.repo
+		SnapshotRepository *OpenSearchSnapshotRepository `json:"snapshotRepository,omitempty"`
+		// Alerting monitors managed by the VMO, monitors removed from this list are deleted
+		Monitors []AlertingMonitor `json:"monitors,omitempty"`
+		// Index aliases managed by the VMO, e.g. to give the dashboards of a tenant a stable name. Aliases removed from
+		// this list are removed from their indices
+		Aliases []IndexAlias `json:"aliases,omitempty"`
+		// Indices which can be created automatically, set as action.auto_create_index. Either true, false, or a comma
+		// separated list of index patterns prefixed with + to allow or - to deny, e.g. +verrazzano-*,-*.
+		// The OpenSearch default is used if not set
+		// +kubebuilder:validation:Pattern:=`^[+-]?[^\s,]+(,[+-]?[^\s,]+)*$`
+		AutoCreateIndex string `json:"autoCreateIndex,omitempty"`
+		// Extra volumes of the OpenSearch pods, e.g. a ConfigMap with a synonyms file
+		ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+		// Extra volume mounts of the OpenSearch container, mounting the extra volumes
+		ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty" patchStrategy:"merge" patchMergeKey:"mountPath"`
+		// Name of a Secret with a pre-populated keystore under the opensearch.keystore key, which is mounted as the
+		// keystore of the OpenSearch nodes instead of updating the keystore with the object store credentials
+		KeystoreSecret string `json:"keystoreSecret,omitempty"`
+		// Shard allocation awareness of the OpenSearch cluster, which spreads the replicas of a shard across the values
+		// of node attributes, e.g. zones. Allocation awareness is not configured if not set
+		AllocationAwareness *OpenSearchAllocationAwareness `json:"allocationAwareness,omitempty"`
+		// Disk usage watermarks of the OpenSearch data nodes, which stop the allocation of shards to full nodes. The
+		// OpenSearch defaults are used if not set
+		DiskWatermarks *OpenSearchDiskWatermarks `json:"diskWatermarks,omitempty"`
+		// Gateway settings of the OpenSearch master nodes, which control when the cluster is recovered after a full
+		// cluster restart. Not configured if not set
+		GatewaySettings *OpenSearchGatewaySettings `json:"gatewaySettings,omitempty"`
+		// Enable the JVM garbage collection logs of the OpenSearch nodes, which are written to a gc-logs volume.
+		// Defaults to false
+		EnableGCLogging *bool `json:"enableGCLogging,omitempty"`
+		// Shell snippet run in the OpenSearch containers before OpenSearch is started, e.g. to debug the start of the nodes.
+		// The snippet runs in a subshell, so its failures, exec or exit do not prevent OpenSearch from starting. The
+		// snippet cannot call the OpenSearch entrypoint
+		PreStartScript string `json:"preStartScript,omitempty"`
+		// Enable the query and request caches of new indices, which speeds up read-heavy dashboards. The settings are applied
+		// by the index defaults template, the OpenSearch defaults are used if not set
+		QueryCacheEnabled *bool `json:"queryCacheEnabled,omitempty"`
+		// Sort of the new indices matching its index patterns, e.g. time-series indices sorted by @timestamp, which speeds up
+		// the queries sorted by the same field. The sort is applied by its own component and index templates, removed with
+		// the sort
+		DefaultIndexSort *IndexSort `json:"defaultIndexSort,omitempty"`
+		// Additional hosts of the OpenSearch ingest ingress, e.g. to expose the read and write endpoints with distinct host
+		// names. Each host gets its own rule and TLS secret
+		IngressHosts []IngressHost `json:"ingressHosts,omitempty"`
+		// Compression of the transport traffic between the OpenSearch nodes, which reduces the cost of the cross-zone
+		// traffic: none, indexing_data to only compress the indexing data, or true to compress all the traffic.
+		// The OpenSearch default is used if not set
+		// +kubebuilder:validation:Enum=none;indexing_data;true
+		TransportCompression string `json:"transportCompression,omitempty"`
+		// Maximum number of clauses of a bool query, the indices.query.bool.max_clause_count setting. This is a static
+		// setting of the nodes, so changing it restarts the OpenSearch nodes. The OpenSearch default is used if not set
+		// +kubebuilder:validation:Minimum:=1
+		MaxClauseCount *int32 `json:"maxClauseCount,omitempty"`
 	}
 
 	// Opensearch details
@@ -165,6 +305,101 @@ type (
 		Nodes                []ElasticsearchNode     `json:"nodes,omitempty"`
 		Plugins              OpenSearchPlugins       `json:"plugins,omitempty"`
 		DisableDefaultPolicy bool                    `json:"disableDefaultPolicy,omitempty"`
+		// Retain the PVCs of removed data nodes for manual cleanup, defaults to true. Otherwise, they are deleted once the
+		// cluster is green, including the PVCs retained earlier
+		RetainOrphanedPVCs *bool `json:"retainOrphanedPVCs,omitempty"`
+		// Pause reconciling of the OpenSearch cluster, while the other components are still reconciled
+		Paused *bool `json:"paused,omitempty"`
+		// Log levels of OpenSearch loggers, keyed by logger name, e.g. org.opensearch.discovery: debug
+		Loggers map[string]string `json:"loggers,omitempty"`
+		// Restrict ingress to the OpenSearch ports with a NetworkPolicy, no NetworkPolicy is created if not set
+		NetworkPolicy *OpenSearchNetworkPolicy `json:"networkPolicy,omitempty"`
+		// Ingest pipelines managed by the VMO, pipelines removed from this list are deleted
+		IngestPipelines []IngestPipeline `json:"ingestPipelines,omitempty"`
+		// Name of the ingest pipeline run by default on the documents indexed into any index, the index.default_pipeline
+		// setting of new indices. The pipeline must be one of the ingest pipelines of the VMI or exist in OpenSearch
+		DefaultPipeline string `json:"defaultPipeline,omitempty"`
+		// Default settings of new indices, the OpenSearch defaults are used if not set
+		IndexDefaults *IndexDefaults `json:"indexDefaults,omitempty"`
+		// Limits of the mappings of new indices, the OpenSearch defaults are used if not set
+		MappingLimits *OpenSearchMappingLimits `json:"mappingLimits,omitempty"`
+		// Override the user and groups the OpenSearch pods run as
+		PodSecurityContext *OpenSearchPodSecurityContext `json:"podSecurityContext,omitempty"`
+		// Limits of the OpenSearch circuit breakers, the OpenSearch defaults are used if not set
+		CircuitBreakers *OpenSearchCircuitBreakers `json:"circuitBreakers,omitempty"`
+		// Seconds an OpenSearch master pod must be ready before it is considered available during a rolling restart.
+		// Defaults to 0
+		// +kubebuilder:validation:Minimum:=0
+		MinReadySeconds int32 `json:"minReadySeconds,omitempty"`
+		// Roll out updates of the OpenSearch master StatefulSets one pod at a time, the next pod is only updated when the
+		// cluster is green. Defaults to false, letting the StatefulSet controller roll out all master pods
+		StagedMasterRollout bool `json:"stagedMasterRollout,omitempty"`
+		// Component templates managed by the VMO, component templates removed from this list are deleted
+		ComponentTemplates []ComponentTemplate `json:"componentTemplates,omitempty"`
+		// Search backpressure settings, the OpenSearch defaults are used if not set
+		SearchBackpressure *OpenSearchSearchBackpressure `json:"searchBackpressure,omitempty"`
+		// Session affinity of the OpenSearch ingest and data service, either None or ClientIP. Defaults to None
+		// +kubebuilder:validation:Enum=None;ClientIP
+		SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+		// Type of the liveness probe of the OpenSearch nodes, either tcp, http or exec. Defaults to tcp for the
+		// master nodes and http for the other nodes
+		LivenessProbeType LivenessProbeType `json:"livenessProbeType,omitempty"`
+		// Startup probe of the OpenSearch nodes, the liveness probe only begins once the startup probe succeeded
+		StartupProbe *OpenSearchStartupProbe `json:"startupProbe,omitempty"`
+		// Shared filesystem snapshot repository of the OpenSearch nodes, mounted on every node and set as path.repo
+		SnapshotRepository *OpenSearchSnapshotRepository `json:"snapshotRepository,omitempty"`
+		// Alerting monitors managed by the VMO, monitors removed from this list are deleted
+		Monitors []AlertingMonitor `json:"monitors,omitempty"`
+		// Index aliases managed by the VMO, e.g. to give the dashboards of a tenant a stable name. Aliases removed from
+		// this list are removed from their indices
+		Aliases []IndexAlias `json:"aliases,omitempty"`
+		// Indices which can be created automatically, set as action.auto_create_index. Either true, false, or a comma
+		// separated list of index patterns prefixed with + to allow or - to deny, e.g. +verrazzano-*,-*.
+		// The OpenSearch default is used if not set
+		// +kubebuilder:validation:Pattern:=`^[+-]?[^\s,]+(,[+-]?[^\s,]+)*$`
+		AutoCreateIndex string `json:"autoCreateIndex,omitempty"`
+		// Extra volumes of the OpenSearch pods, e.g. a ConfigMap with a synonyms file
+		ExtraVolumes []corev1.Volume `json:"extraVolumes,omitempty" patchStrategy:"merge" patchMergeKey:"name"`
+		// Extra volume mounts of the OpenSearch container, mounting the extra volumes
+		ExtraVolumeMounts []corev1.VolumeMount `json:"extraVolumeMounts,omitempty" patchStrategy:"merge" patchMergeKey:"mountPath"`
+		// Name of a Secret with a pre-populated keystore under the opensearch.keystore key, which is mounted as the
+		// keystore of the OpenSearch nodes instead of updating the keystore with the object store credentials
+		KeystoreSecret string `json:"keystoreSecret,omitempty"`
+		// Shard allocation awareness of the OpenSearch cluster, which spreads the replicas of a shard across the values
+		// of node attributes, e.g. zones. Allocation awareness is not configured if not set
+		AllocationAwareness *OpenSearchAllocationAwareness `json:"allocationAwareness,omitempty"`
+		// Disk usage watermarks of the OpenSearch data nodes, which stop the allocation of shards to full nodes. The
+		// OpenSearch defaults are used if not set
+		DiskWatermarks *OpenSearchDiskWatermarks `json:"diskWatermarks,omitempty"`
+		// Gateway settings of the OpenSearch master nodes, which control when the cluster is recovered after a full
+		// cluster restart. Not configured if not set
+		GatewaySettings *OpenSearchGatewaySettings `json:"gatewaySettings,omitempty"`
+		// Enable the JVM garbage collection logs of the OpenSearch nodes, which are written to a gc-logs volume.
+		// Defaults to false
+		EnableGCLogging *bool `json:"enableGCLogging,omitempty"`
+		// Shell snippet run in the OpenSearch containers before OpenSearch is started, e.g. to debug the start of the nodes.
+		// The snippet runs in a subshell, so its failures, exec or exit do not prevent OpenSearch from starting. The
+		// snippet cannot call the OpenSearch entrypoint
+		PreStartScript string `json:"preStartScript,omitempty"`
+		// Enable the query and request caches of new indices, which speeds up read-heavy dashboards. The settings are applied
+		// by the index defaults template, the OpenSearch defaults are used if not set
+		QueryCacheEnabled *bool `json:"queryCacheEnabled,omitempty"`
+		// Sort of the new indices matching its index patterns, e.g. time-series indices sorted by @timestamp, which speeds up
+		// the queries sorted by the same field. The sort is applied by its own component and index templates, removed with
+		// the sort
+		DefaultIndexSort *IndexSort `json:"defaultIndexSort,omitempty"`
+		// Additional hosts of the OpenSearch ingest ingress, e.g. to expose the read and write endpoints with distinct host
+		// names. Each host gets its own rule and TLS secret
+		IngressHosts []IngressHost `json:"ingressHosts,omitempty"`
+		// Compression of the transport traffic between the OpenSearch nodes, which reduces the cost of the cross-zone
+		// traffic: none, indexing_data to only compress the indexing data, or true to compress all the traffic.
+		// The OpenSearch default is used if not set
+		// +kubebuilder:validation:Enum=none;indexing_data;true
+		TransportCompression string `json:"transportCompression,omitempty"`
+		// Maximum number of clauses of a bool query, the indices.query.bool.max_clause_count setting. This is a static
+		// setting of the nodes, so changing it restarts the OpenSearch nodes. The OpenSearch default is used if not set
+		// +kubebuilder:validation:Minimum:=1
+		MaxClauseCount *int32 `json:"maxClauseCount,omitempty"`
 	}
 
 	// ElasticsearchNode Type details
@@ -175,6 +410,234 @@ type (
 		Resources Resources  `json:"resources,omitempty"`
 		Storage   *Storage   `json:"storage,omitempty"`
 		Roles     []NodeRole `json:"roles,omitempty"`
+		// Storage class of the PVCs of the node, overrides the storage class of the VMI
+		StorageClass *string `json:"storageClass,omitempty"`
+		// Prefix of the OpenSearch node names of a master node, followed by the ordinal of the pod, e.g. master-0, so that
+		// the node names are stable across clusters, e.g. for snapshot restores. The names of the pods are used if not set.
+		// Only applies to the master nodes
+		// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`
+		NodeNamePrefix string `json:"nodeNamePrefix,omitempty"`
+		// Container image of the OpenSearch nodes of the node group, e.g. an image with additional analyzers. The
+		// OpenSearch image of the operator config is used if not set
+		Image string `json:"image,omitempty"`
+	}
+
+	// OpenSearchCircuitBreakers Defines the limits of the OpenSearch circuit breakers, either as a percentage of the
+	// JVM heap, e.g. 70%, or as a byte size, e.g. 2gb
+	OpenSearchCircuitBreakers struct {
+		// Limit of the parent circuit breaker, indices.breaker.total.limit
+		Total string `json:"total,omitempty"`
+		// Limit of the request circuit breaker, indices.breaker.request.limit
+		Request string `json:"request,omitempty"`
+		// Limit of the field data circuit breaker, indices.breaker.fielddata.limit
+		Fielddata string `json:"fielddata,omitempty"`
+	}
+
+	// OpenSearchPodSecurityContext Defines the user and groups the OpenSearch pods run as, the OpenSearch user and
+	// group (1000) are used if not set
+	OpenSearchPodSecurityContext struct {
+		// UID the OpenSearch containers run as
+		// +kubebuilder:validation:Minimum:=0
+		RunAsUser *int64 `json:"runAsUser,omitempty"`
+		// GID the OpenSearch containers run as
+		// +kubebuilder:validation:Minimum:=0
+		RunAsGroup *int64 `json:"runAsGroup,omitempty"`
+		// GID owning the volumes of the OpenSearch pods
+		// +kubebuilder:validation:Minimum:=0
+		FSGroup *int64 `json:"fsGroup,omitempty"`
+	}
+
+	// OpenSearchSearchBackpressure Defines the search backpressure settings, which cancel resource intensive search
+	// tasks when a node is under duress. Requires OpenSearch 2.4 or later.
+	OpenSearchSearchBackpressure struct {
+		// Mode of search backpressure, search_backpressure.mode: monitor_only only logs the tasks which would be
+		// cancelled, enforced cancels them, and disabled turns search backpressure off
+		// +kubebuilder:validation:Enum=monitor_only;enforced;disabled
+		Mode string `json:"mode,omitempty"`
+		// CPU usage ratio of a node above which it is under duress, e.g. 0.9, search_backpressure.node_duress.cpu_threshold
+		// +kubebuilder:validation:Pattern:=^(0(\.[0-9]+)?|1(\.0+)?)$
+		CPUThreshold string `json:"cpuThreshold,omitempty"`
+		// Heap usage ratio of a node above which it is under duress, e.g. 0.7, search_backpressure.node_duress.heap_threshold
+		// +kubebuilder:validation:Pattern:=^(0(\.[0-9]+)?|1(\.0+)?)$
+		HeapThreshold string `json:"heapThreshold,omitempty"`
+		// Number of successive resource usage breaches before a node is under duress,
+		// search_backpressure.node_duress.num_successive_breaches
+		// +kubebuilder:validation:Minimum:=1
+		NumSuccessiveBreaches int32 `json:"numSuccessiveBreaches,omitempty"`
+	}
+
+	// OpenSearchStartupProbe Defines the startup probe of the OpenSearch nodes, which checks the node like the liveness
+	// probe. A node is restarted if it has not started within FailureThreshold * PeriodSeconds seconds.
+	OpenSearchStartupProbe struct {
+		// Number of failed checks before the node is restarted. Defaults to 90
+		// +kubebuilder:validation:Minimum:=1
+		FailureThreshold int32 `json:"failureThreshold,omitempty"`
+		// Number of seconds between checks. Defaults to 10
+		// +kubebuilder:validation:Minimum:=1
+		PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+	}
+
+	// OpenSearchAllocationAwareness Defines the node attributes the replicas of a shard are spread across,
+	// cluster.routing.allocation.awareness.attributes
+	OpenSearchAllocationAwareness struct {
+		Attributes []OpenSearchAwarenessAttribute `json:"attributes,omitempty"`
+	}
+
+	// OpenSearchAwarenessAttribute Defines an allocation awareness attribute, which is set as the node.attr.<name>
+	// attribute of the OpenSearch nodes
+	OpenSearchAwarenessAttribute struct {
+		// Name of the node attribute, e.g. zone
+		// +kubebuilder:validation:Pattern:=`^[a-zA-Z0-9_-]+$`
+		Name string `json:"name"`
+		// Values of the node attribute keyed by node name, e.g. es-data-zone1: zone1. Nodes without a value have the
+		// value None
+		NodeValues map[string]string `json:"nodeValues,omitempty"`
+		// Forced awareness values, cluster.routing.allocation.awareness.force.<name>.values. The replicas of a shard
+		// are not all allocated to the remaining values when the nodes with a value are unavailable
+		ForcedValues []string `json:"forcedValues,omitempty"`
+	}
+
+	// OpenSearchDiskWatermarks Defines the disk usage watermarks of the OpenSearch data nodes, either as a percentage
+	// of used disk, e.g. 85%, or as an amount of free disk, e.g. 50gb. All the watermarks must use the same format
+	OpenSearchDiskWatermarks struct {
+		// Watermark above which no new shard is allocated to a node, cluster.routing.allocation.disk.watermark.low
+		// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?%|[0-9]+(b|kb|mb|gb|tb|pb))$`
+		Low string `json:"low,omitempty"`
+		// Watermark above which the shards of a node are relocated to other nodes,
+		// cluster.routing.allocation.disk.watermark.high
+		// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?%|[0-9]+(b|kb|mb|gb|tb|pb))$`
+		High string `json:"high,omitempty"`
+		// Watermark above which the indices with a shard on a node are made read-only,
+		// cluster.routing.allocation.disk.watermark.flood_stage
+		// +kubebuilder:validation:Pattern:=`^([0-9]+(\.[0-9]+)?%|[0-9]+(b|kb|mb|gb|tb|pb))$`
+		Flood string `json:"flood,omitempty"`
+	}
+
+	// OpenSearchGatewaySettings Defines when the OpenSearch master nodes start recovering the cluster state and the shards
+	// after a full cluster restart, instead of recovering as soon as a master is elected
+	OpenSearchGatewaySettings struct {
+		// Number of nodes which must have joined the cluster before the recovery starts, gateway.recover_after_nodes.
+		// Nodes of every role are counted. Defaults to a majority of the master nodes, and cannot exceed the expected
+		// nodes
+		// +kubebuilder:validation:Minimum:=1
+		RecoverAfterNodes *int32 `json:"recoverAfterNodes,omitempty"`
+		// Number of nodes expected in the cluster, the recovery starts without waiting for the recover after time as soon
+		// as they joined, gateway.expected_nodes. Nodes of every role are counted. Defaults to the number of master
+		// nodes, and cannot exceed the number of nodes of the cluster
+		// +kubebuilder:validation:Minimum:=1
+		ExpectedNodes *int32 `json:"expectedNodes,omitempty"`
+		// Time to wait for the expected nodes once the recover after nodes joined, e.g. 5m, gateway.recover_after_time.
+		// The OpenSearch default is used if not set
+		// +kubebuilder:validation:Pattern:=`^[0-9]+(ms|s|m|h|d)$`
+		RecoverAfterTime string `json:"recoverAfterTime,omitempty"`
+	}
+
+	// OpenSearchSnapshotRepository Defines a shared filesystem for the snapshots of an fs snapshot repository
+	OpenSearchSnapshotRepository struct {
+		// Path the shared filesystem is mounted at on the OpenSearch nodes, e.g. /mnt/snapshots
+		// +kubebuilder:validation:Pattern:=`^/`
+		Path string `json:"path"`
+		// Name of the PVC of the shared filesystem, which must support the ReadWriteMany access mode
+		ClaimName string `json:"claimName"`
+	}
+
+	// IndexDefaults Defines the default settings of new indices. The settings are applied by a component template composed
+	// first into the composable index templates, e.g. of data streams, and by a legacy index template matching all
+	// indices, so they do not apply to indices matched by an index template which sets them too.
+	IndexDefaults struct {
+		// Deprecated: TotalFieldsLimit has been replaced by the total fields of the mapping limits, which take precedence
+		// +kubebuilder:validation:Minimum:=1
+		// +optional
+		TotalFieldsLimit *int32 `json:"totalFieldsLimit,omitempty"`
+		// Number of primary shards of an index
+		// +kubebuilder:validation:Minimum:=1
+		NumberOfShards *int32 `json:"numberOfShards,omitempty"`
+		// Number of replicas of each primary shard of an index
+		// +kubebuilder:validation:Minimum:=0
+		NumberOfReplicas *int32 `json:"numberOfReplicas,omitempty"`
+		// Interval between the refreshes of an index, which make the recent changes visible to search, e.g. 30s.
+		// A longer interval reduces the segment churn of heavy ingest, -1 disables the refreshes
+		// +kubebuilder:validation:Pattern:=^(-1|[0-9]+(d|h|m|s|ms|micros|nanos))$
+		RefreshInterval *string `json:"refreshInterval,omitempty"`
+		// Maximum number of shards of an index allocated to a single node, to prevent hotspots on the nodes
+		// +kubebuilder:validation:Minimum:=1
+		TotalShardsPerNode *int32 `json:"totalShardsPerNode,omitempty"`
+		// Compression codec of the stored fields of an index, best_compression trades indexing and search speed for a
+		// smaller disk footprint. The codec only applies to new indices
+		// +kubebuilder:validation:Enum=default;best_compression
+		Codec *string `json:"codec,omitempty"`
+	}
+
+	// OpenSearchMappingLimits Defines the limits of the mappings of new indices, which guard against mapping explosions
+	// caused by complex documents. The limits are applied by the index template of the index defaults.
+	OpenSearchMappingLimits struct {
+		// Maximum number of fields in an index, index.mapping.total_fields.limit. Takes precedence over the deprecated
+		// total fields limit of the index defaults
+		// +kubebuilder:validation:Minimum:=1
+		TotalFields *int32 `json:"totalFields,omitempty"`
+		// Maximum number of distinct nested mappings in an index, index.mapping.nested_fields.limit
+		// +kubebuilder:validation:Minimum:=1
+		NestedFields *int32 `json:"nestedFields,omitempty"`
+		// Maximum number of nested objects across all nested fields of a single document,
+		// index.mapping.nested_objects.limit
+		// +kubebuilder:validation:Minimum:=1
+		NestedObjects *int32 `json:"nestedObjects,omitempty"`
+	}
+
+	// IndexSort Defines the sort of new indices. The indices are sorted when they are created, so the sort field must be
+	// mapped when they are created, e.g. by another index template, or their creation fails.
+	IndexSort struct {
+		// Field the indices are sorted by, e.g. @timestamp
+		// +kubebuilder:validation:MinLength:=1
+		Field string `json:"field"`
+		// Order of the sort, either asc or desc. Defaults to asc
+		// +kubebuilder:validation:Enum=asc;desc
+		Order string `json:"order,omitempty"`
+		// Patterns of the names of the sorted indices, e.g. verrazzano-data-*
+		// +kubebuilder:validation:MinItems:=1
+		IndexPatterns []string `json:"indexPatterns"`
+	}
+
+	// IngestPipeline Defines an OpenSearch ingest pipeline
+	IngestPipeline struct {
+		// Name of the pipeline
+		Name string `json:"name"`
+		// Processors of the pipeline, as a JSON array of processor objects, e.g. [{"rename": {"field": "a", "target_field": "b"}}]
+		Processors string `json:"processors"`
+	}
+
+	// ComponentTemplate Defines an OpenSearch component template, which composable index templates can be composed of
+	ComponentTemplate struct {
+		// Name of the component template
+		Name string `json:"name"`
+		// Template of the component template, as a JSON object with optional settings, mappings and aliases,
+		// e.g. {"settings": {"number_of_replicas": 1}}
+		Template string `json:"template"`
+	}
+
+	// IndexAlias Defines an OpenSearch alias of the indices matching an index pattern. The alias is added to the indices
+	// created after it on the next reconcile
+	IndexAlias struct {
+		// Name of the alias
+		// +kubebuilder:validation:MinLength:=1
+		Name string `json:"name"`
+		// Index pattern of the indices the alias points to, e.g. tenant-a-archive-*. Data streams cannot have aliases,
+		// so an alias whose index pattern matches data streams, e.g. the verrazzano-application-* data streams, is
+		// not added
+		// +kubebuilder:validation:MinLength:=1
+		Index string `json:"index"`
+		// Optional filter of the documents visible through the alias, as a JSON object of the query DSL,
+		// e.g. {"term": {"kubernetes.namespace_name": "tenant-a"}}
+		Filter string `json:"filter,omitempty"`
+	}
+
+	// AlertingMonitor Defines an OpenSearch alerting monitor
+	AlertingMonitor struct {
+		// Name of the monitor
+		Name string `json:"name"`
+		// Monitor definition, as a JSON object of the alerting monitor API, e.g. {"monitor_type": "query_level_monitor",
+		// "schedule": {"period": {"interval": 1, "unit": "MINUTES"}}, "inputs": [...], "triggers": [...]}
+		Monitor string `json:"monitor"`
 	}
 
 	//IndexManagementPolicy Defines a policy for managing indices
@@ -187,6 +650,11 @@ type (
 		// +kubebuilder:validation:Pattern:=^[0-9]+(d|h|m|s|ms|micros|nanos)$
 		MinIndexAge *string        `json:"minIndexAge,omitempty"`
 		Rollover    RolloverPolicy `json:"rollover,omitempty"`
+		// Minimum age of an index before it is closed, which frees the heap used by the index until it is deleted.
+		// Must not be less than the rollover minimum index age and must be less than the minimum index age, indices
+		// are not closed if not set
+		// +kubebuilder:validation:Pattern:=^[0-9]+(d|h|m|s|ms|micros|nanos)$
+		MinCloseAge *string `json:"minCloseAge,omitempty"`
 	}
 
 	//RolloverPolicy Settings for Index Management rollover
@@ -204,18 +672,90 @@ type (
 
 	// Deprecated: Kibana type has been replaced by OpensearchDashboards
 	Kibana struct {
-		Enabled   bool                        `json:"enabled" yaml:"enabled"`
-		Resources Resources                   `json:"resources,omitempty"`
-		Replicas  int32                       `json:"replicas,omitempty"`
-		Plugins   OpenSearchDashboardsPlugins `json:"plugins,omitempty"`
+		Enabled   bool      `json:"enabled" yaml:"enabled"`
+		Resources Resources `json:"resources,omitempty"`
+		// Replicas is the number of OpenSearch Dashboards replicas, the default replicas of the operator config if not set.
+		// Use ScaleToZero to scale OpenSearch Dashboards to zero replicas.
+		Replicas int32 `json:"replicas,omitempty"`
+		// ScaleToZero scales OpenSearch Dashboards to zero replicas, which disables OpenSearch Dashboards, whatever its
+		// replicas. Its last replica is only removed after the dashboards scale down grace period of the operator config,
+		// so that in-flight requests, e.g. index migrations, are drained.
+		ScaleToZero bool                        `json:"scaleToZero,omitempty"`
+		Plugins     OpenSearchDashboardsPlugins `json:"plugins,omitempty"`
+		// ServerName is the name used by OpenSearch Dashboards to identify this instance. If not set, the OpenSearch Dashboards default is used.
+		ServerName string `json:"serverName,omitempty"`
+		// DefaultRoute is the route OpenSearch Dashboards redirects to when the base URL is accessed. If not set, the OpenSearch Dashboards default is used.
+		DefaultRoute string `json:"defaultRoute,omitempty"`
+		// Managed tells whether the OpenSearch Dashboards deployment is managed by the VMO. When false, the OpenSearch Dashboards
+		// deployment is neither created, updated nor deleted, so that it can be managed by another operator. Defaults to true.
+		Managed *bool `json:"managed,omitempty"`
+		// Session affinity of the OpenSearch Dashboards service, either None or ClientIP. Defaults to None
+		// +kubebuilder:validation:Enum=None;ClientIP
+		SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+		// SavedObjectsConfigMap is the name of a ConfigMap in the VMI namespace whose values are NDJSON saved objects, e.g. index
+		// patterns and visualizations, which are imported into OpenSearch Dashboards once it is ready. The saved objects are
+		// imported again only when the content of the ConfigMap changes, existing saved objects with the same IDs are overwritten.
+		SavedObjectsConfigMap string `json:"savedObjectsConfigMap,omitempty"`
+		// Additional hosts of the OpenSearch Dashboards ingress. Each host gets its own rule and TLS secret
+		IngressHosts []IngressHost `json:"ingressHosts,omitempty"`
+		// Time OpenSearch Dashboards waits for the responses of OpenSearch, as a duration, e.g. 90s or 2m. If not set, the
+		// OpenSearch Dashboards default is used
+		RequestTimeout string `json:"requestTimeout,omitempty"`
+		// Maximum size in bytes of the payloads of the requests to OpenSearch Dashboards. If not set, the OpenSearch
+		// Dashboards default is used
+		// +kubebuilder:validation:Minimum:=1
+		MaxPayloadBytes *int64 `json:"maxPayloadBytes,omitempty"`
+		// SecurityEnabled keeps the security plugin of OpenSearch Dashboards, which must be set when the OpenSearch security
+		// plugin is enabled. Defaults to false, disabling the OpenSearch Dashboards security plugin
+		SecurityEnabled bool `json:"securityEnabled,omitempty"`
+		// TLS makes OpenSearch Dashboards serve HTTPS with the certificate and key of a Secret in the VMI namespace. The
+		// operator and the ingress then reach OpenSearch Dashboards over HTTPS, so the certificate must be valid for the
+		// OpenSearch Dashboards service and trusted by the operator. If not set, OpenSearch Dashboards serves HTTP
+		TLS *OpenSearchDashboardsTLS `json:"tls,omitempty"`
 	}
 
 	// OpenSearch Dashboards details
 	OpensearchDashboards struct {
-		Enabled   bool                        `json:"enabled" yaml:"enabled"`
-		Resources Resources                   `json:"resources,omitempty"`
-		Replicas  int32                       `json:"replicas,omitempty"`
-		Plugins   OpenSearchDashboardsPlugins `json:"plugins,omitempty"`
+		Enabled   bool      `json:"enabled" yaml:"enabled"`
+		Resources Resources `json:"resources,omitempty"`
+		// Replicas is the number of OpenSearch Dashboards replicas, the default replicas of the operator config if not set.
+		// Use ScaleToZero to scale OpenSearch Dashboards to zero replicas.
+		Replicas int32 `json:"replicas,omitempty"`
+		// ScaleToZero scales OpenSearch Dashboards to zero replicas, which disables OpenSearch Dashboards, whatever its
+		// replicas. Its last replica is only removed after the dashboards scale down grace period of the operator config,
+		// so that in-flight requests, e.g. index migrations, are drained.
+		ScaleToZero bool                        `json:"scaleToZero,omitempty"`
+		Plugins     OpenSearchDashboardsPlugins `json:"plugins,omitempty"`
+		// ServerName is the name used by OpenSearch Dashboards to identify this instance. If not set, the OpenSearch Dashboards default is used.
+		ServerName string `json:"serverName,omitempty"`
+		// DefaultRoute is the route OpenSearch Dashboards redirects to when the base URL is accessed. If not set, the OpenSearch Dashboards default is used.
+		DefaultRoute string `json:"defaultRoute,omitempty"`
+		// Managed tells whether the OpenSearch Dashboards deployment is managed by the VMO. When false, the OpenSearch Dashboards
+		// deployment is neither created, updated nor deleted, so that it can be managed by another operator. Defaults to true.
+		Managed *bool `json:"managed,omitempty"`
+		// Session affinity of the OpenSearch Dashboards service, either None or ClientIP. Defaults to None
+		// +kubebuilder:validation:Enum=None;ClientIP
+		SessionAffinity corev1.ServiceAffinity `json:"sessionAffinity,omitempty"`
+		// SavedObjectsConfigMap is the name of a ConfigMap in the VMI namespace whose values are NDJSON saved objects, e.g. index
+		// patterns and visualizations, which are imported into OpenSearch Dashboards once it is ready. The saved objects are
+		// imported again only when the content of the ConfigMap changes, existing saved objects with the same IDs are overwritten.
+		SavedObjectsConfigMap string `json:"savedObjectsConfigMap,omitempty"`
+		// Additional hosts of the OpenSearch Dashboards ingress. Each host gets its own rule and TLS secret
+		IngressHosts []IngressHost `json:"ingressHosts,omitempty"`
+		// Time OpenSearch Dashboards waits for the responses of OpenSearch, as a duration, e.g. 90s or 2m. If not set, the
+		// OpenSearch Dashboards default is used
+		RequestTimeout string `json:"requestTimeout,omitempty"`
+		// Maximum size in bytes of the payloads of the requests to OpenSearch Dashboards. If not set, the OpenSearch
+		// Dashboards default is used
+		// +kubebuilder:validation:Minimum:=1
+		MaxPayloadBytes *int64 `json:"maxPayloadBytes,omitempty"`
+		// SecurityEnabled keeps the security plugin of OpenSearch Dashboards, which must be set when the OpenSearch security
+		// plugin is enabled. Defaults to false, disabling the OpenSearch Dashboards security plugin
+		SecurityEnabled bool `json:"securityEnabled,omitempty"`
+		// TLS makes OpenSearch Dashboards serve HTTPS with the certificate and key of a Secret in the VMI namespace. The
+		// operator and the ingress then reach OpenSearch Dashboards over HTTPS, so the certificate must be valid for the
+		// OpenSearch Dashboards service and trusted by the operator. If not set, OpenSearch Dashboards serves HTTP
+		TLS *OpenSearchDashboardsTLS `json:"tls,omitempty"`
 	}
 
 	// OpenSearchPlugins Enable to add 3rd Party / Custom plugins not offered in the default OpenSearch image
@@ -223,9 +763,32 @@ type (
 		// To enable or disable the non-bundled plugins installation.
 		Enabled bool `json:"enabled" yaml:"enabled"`
 		// InstallList could be the list of plugin names, URLs to the plugin zip file or Maven coordinates.
+		// A URL may be followed by the checksum of the plugin zip file, e.g. https://example.com/plugin.zip#sha256=<checksum>,
+		// in which case the plugin is only installed if its checksum matches. The sha256 and sha512 algorithms are supported.
 		InstallList []string `json:"installList,omitempty"`
 	}
 
+	// OpenSearchNetworkPolicy defines the peers allowed to access the OpenSearch HTTP and transport ports,
+	// in addition to the components of the VMI, and the VMO itself, the auth proxy and Fluentd in the VMO namespace
+	OpenSearchNetworkPolicy struct {
+		// Namespaces whose pods are allowed to access OpenSearch
+		AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+		// Selectors of the pods in the VMI namespace which are allowed to access OpenSearch
+		AllowedPodSelectors []metav1.LabelSelector `json:"allowedPodSelectors,omitempty"`
+	}
+
+	// OpenSearchDashboardsTLS defines the Secret holding the certificate and key OpenSearch Dashboards serves HTTPS with
+	OpenSearchDashboardsTLS struct {
+		// Name of the Secret in the VMI namespace holding the certificate and key
+		SecretName string `json:"secretName"`
+		// The key in the Secret containing the PEM encoded certificate. Defaults to tls.crt
+		// +optional
+		CertKey string `json:"certKey,omitempty"`
+		// The key in the Secret containing the PEM encoded private key of the certificate. Defaults to tls.key
+		// +optional
+		KeyKey string `json:"keyKey,omitempty"`
+	}
+
 	// OpenSearchDashboardsPlugins is an alias of OpenSearchPlugins as both have the same properties.
 	// Enable to add 3rd Party / Custom plugins not offered in the default OpenSearch-Dashboards image
 	OpenSearchDashboardsPlugins OpenSearchPlugins
@@ -233,6 +796,14 @@ type (
 	// API details
 	API struct {
 		Replicas int32 `json:"replicas,omitempty"`
+		// Additional command-line arguments of the API server container
+		ExtraArgs []string `json:"extraArgs,omitempty"`
+		// Additional environment variables of the API server container
+		ExtraEnv []corev1.EnvVar `json:"extraEnv,omitempty"`
+		// Report the API server ready only once Prometheus is reachable, so no traffic is routed to the API server while
+		// its Prometheus backend is unreachable. The readiness probe then gets the API server health check with
+		// prometheus=true. Defaults to false
+		ReadinessDependsOnPrometheus bool `json:"readinessDependsOnPrometheus,omitempty"`
 	}
 
 	// VerrazzanoMonitoringInstanceStatus Object tracks the current running VerrazzanoMonitoringInstance state
@@ -242,6 +813,32 @@ type (
 		State        string       `json:"state" yaml:"state"`
 		CreationTime *metav1.Time `json:"creationTime,omitempty" yaml:"creationTime"`
 		Hash         uint32       `json:"hash"`
+		// Conditions of the VMI, e.g. Degraded when a resource quota rejects the resources of the VMI
+		Conditions []metav1.Condition `json:"conditions,omitempty"`
+		// Alerting monitors created by the VMO
+		Monitors []AlertingMonitorStatus `json:"monitors,omitempty"`
+		// Index aliases added by the VMO
+		Aliases []IndexAliasStatus `json:"aliases,omitempty"`
+		// Checksum of the content of the saved objects ConfigMap last imported into OpenSearch Dashboards
+		SavedObjectsChecksum string `json:"savedObjectsChecksum,omitempty"`
+	}
+
+	// AlertingMonitorStatus Tracks an OpenSearch alerting monitor created by the VMO
+	AlertingMonitorStatus struct {
+		// Name of the monitor
+		Name string `json:"name"`
+		// ID of the monitor in OpenSearch
+		ID string `json:"id"`
+		// Checksum of the monitor definition last applied
+		Checksum string `json:"checksum,omitempty"`
+	}
+
+	// IndexAliasStatus Tracks an OpenSearch index alias added by the VMO
+	IndexAliasStatus struct {
+		// Name of the alias
+		Name string `json:"name"`
+		// Index pattern of the indices the alias was added to
+		Index string `json:"index"`
 	}
 
 	// Storage details
@@ -269,6 +866,37 @@ type (
 		MinSizeDisk string `json:"minSizeDisk,omitempty" yaml:"minSizeDisk,omitempty"`
 	}
 
+	// DashboardFolders details of the Grafana folders of the dashboards provisioned from ConfigMaps. The dashboards of a
+	// ConfigMap are in the folder named by the folder annotation of the ConfigMap. The Grafana dashboard provider must
+	// enable foldersFromFilesStructure for the folders to be created.
+	DashboardFolders struct {
+		// Annotation of the dashboard ConfigMaps whose value is the folder of their dashboards. Defaults to grafana_folder
+		Annotation string `json:"annotation,omitempty"`
+	}
+
+	// GrafanaServiceAccountToken Defines a Grafana service account whose token is written to a Secret. The token is only
+	// created again when the token of the Secret is no longer valid.
+	GrafanaServiceAccountToken struct {
+		// Name of the Grafana service account
+		// +kubebuilder:validation:MinLength:=1
+		ServiceAccountName string `json:"serviceAccountName"`
+		// Role of the service account in the Grafana organization, one of Viewer, Editor or Admin. Defaults to Viewer
+		// +kubebuilder:validation:Enum=Viewer;Editor;Admin
+		Role string `json:"role,omitempty"`
+		// Name of the Secret, in the namespace of the VMI, whose token key holds the token of the service account
+		// +kubebuilder:validation:MinLength:=1
+		SecretName string `json:"secretName"`
+	}
+
+	// IngressHost Defines an additional host of the ingress of a component, routed to the same backend as the default host
+	IngressHost struct {
+		// Fully qualified host name
+		// +kubebuilder:validation:MinLength:=1
+		Host string `json:"host"`
+		// Name of the TLS secret of the host. Defaults to <VMI name>-tls-<component>-<host with dots replaced by dashes>
+		SecretName string `json:"secretName,omitempty"`
+	}
+
 	// Database details
 	Database struct {
 		PasswordSecret string `json:"passwordSecret,omitempty"`
@@ -351,6 +979,10 @@ type (
 		Items           []VerrazzanoMonitoringInstance `json:"items"`
 	}
 
+	// LivenessProbeType is the type of the liveness probe of the OpenSearch nodes
+	// +kubebuilder:validation:Enum=tcp;http;exec
+	LivenessProbeType string
+
 	// StartTLSType is the type of protocol command used to inform the email server that the email client wants to upgrade from
 	// an insecure connection to a secure one using TLS or SSL.
 	StartTLSType string
@@ -10,12 +10,25 @@ package v1
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *API) DeepCopyInto(out *API) {
 	*out = *in
+	if in.ExtraArgs != nil {
+		in, out := &in.ExtraArgs, &out.ExtraArgs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraEnv != nil {
+		in, out := &in.ExtraEnv, &out.ExtraEnv
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -46,6 +59,54 @@ func (in *AlertManager) DeepCopy() *AlertManager {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertingMonitor) DeepCopyInto(out *AlertingMonitor) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertingMonitor.
+func (in *AlertingMonitor) DeepCopy() *AlertingMonitor {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertingMonitor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertingMonitorStatus) DeepCopyInto(out *AlertingMonitorStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertingMonitorStatus.
+func (in *AlertingMonitorStatus) DeepCopy() *AlertingMonitorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertingMonitorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ComponentTemplate) DeepCopyInto(out *ComponentTemplate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ComponentTemplate.
+func (in *ComponentTemplate) DeepCopy() *ComponentTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ComponentTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerConfig) DeepCopyInto(out *ContainerConfig) {
 	*out = *in
@@ -135,6 +196,22 @@ func (in *ContainerSpec) DeepCopy() *ContainerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardFolders) DeepCopyInto(out *DashboardFolders) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardFolders.
+func (in *DashboardFolders) DeepCopy() *DashboardFolders {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardFolders)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Database) DeepCopyInto(out *Database) {
 	*out = *in
@@ -173,6 +250,137 @@ func (in *Elasticsearch) DeepCopyInto(out *Elasticsearch) {
 		}
 	}
 	in.Plugins.DeepCopyInto(&out.Plugins)
+	if in.RetainOrphanedPVCs != nil {
+		in, out := &in.RetainOrphanedPVCs, &out.RetainOrphanedPVCs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Loggers != nil {
+		in, out := &in.Loggers, &out.Loggers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(OpenSearchNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngestPipelines != nil {
+		in, out := &in.IngestPipelines, &out.IngestPipelines
+		*out = make([]IngestPipeline, len(*in))
+		copy(*out, *in)
+	}
+	if in.IndexDefaults != nil {
+		in, out := &in.IndexDefaults, &out.IndexDefaults
+		*out = new(IndexDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MappingLimits != nil {
+		in, out := &in.MappingLimits, &out.MappingLimits
+		*out = new(OpenSearchMappingLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentTemplates != nil {
+		in, out := &in.ComponentTemplates, &out.ComponentTemplates
+		*out = make([]ComponentTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchBackpressure != nil {
+		in, out := &in.SearchBackpressure, &out.SearchBackpressure
+		*out = new(OpenSearchSearchBackpressure)
+		**out = **in
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(OpenSearchStartupProbe)
+		**out = **in
+	}
+	if in.SnapshotRepository != nil {
+		in, out := &in.SnapshotRepository, &out.SnapshotRepository
+		*out = new(OpenSearchSnapshotRepository)
+		**out = **in
+	}
+	if in.Monitors != nil {
+		in, out := &in.Monitors, &out.Monitors
+		*out = make([]AlertingMonitor, len(*in))
+		copy(*out, *in)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]IndexAlias, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllocationAwareness != nil {
+		in, out := &in.AllocationAwareness, &out.AllocationAwareness
+		*out = new(OpenSearchAllocationAwareness)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskWatermarks != nil {
+		in, out := &in.DiskWatermarks, &out.DiskWatermarks
+		*out = new(OpenSearchDiskWatermarks)
+		**out = **in
+	}
+	if in.GatewaySettings != nil {
+		in, out := &in.GatewaySettings, &out.GatewaySettings
+		*out = new(OpenSearchGatewaySettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableGCLogging != nil {
+		in, out := &in.EnableGCLogging, &out.EnableGCLogging
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QueryCacheEnabled != nil {
+		in, out := &in.QueryCacheEnabled, &out.QueryCacheEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IngressHosts != nil {
+		in, out := &in.IngressHosts, &out.IngressHosts
+		*out = make([]IngressHost, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(OpenSearchPodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreakers != nil {
+		in, out := &in.CircuitBreakers, &out.CircuitBreakers
+		*out = new(OpenSearchCircuitBreakers)
+		**out = **in
+	}
+	if in.DefaultIndexSort != nil {
+		in, out := &in.DefaultIndexSort, &out.DefaultIndexSort
+		*out = new(IndexSort)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxClauseCount != nil {
+		in, out := &in.MaxClauseCount, &out.MaxClauseCount
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -200,6 +408,11 @@ func (in *ElasticsearchNode) DeepCopyInto(out *ElasticsearchNode) {
 		*out = make([]NodeRole, len(*in))
 		copy(*out, *in)
 	}
+	if in.StorageClass != nil {
+		in, out := &in.StorageClass, &out.StorageClass
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -228,6 +441,41 @@ func (in *Grafana) DeepCopyInto(out *Grafana) {
 		*out = new(SMTPInfo)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DashboardFolders != nil {
+		in, out := &in.DashboardFolders, &out.DashboardFolders
+		*out = new(DashboardFolders)
+		**out = **in
+	}
+	if in.IngressHosts != nil {
+		in, out := &in.IngressHosts, &out.IngressHosts
+		*out = make([]IngressHost, len(*in))
+		copy(*out, *in)
+	}
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CookieSecure != nil {
+		in, out := &in.CookieSecure, &out.CookieSecure
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ServiceAccountToken != nil {
+		in, out := &in.ServiceAccountToken, &out.ServiceAccountToken
+		*out = new(GrafanaServiceAccountToken)
+		**out = **in
+	}
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -241,6 +489,22 @@ func (in *Grafana) DeepCopy() *Grafana {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GrafanaServiceAccountToken) DeepCopyInto(out *GrafanaServiceAccountToken) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GrafanaServiceAccountToken.
+func (in *GrafanaServiceAccountToken) DeepCopy() *GrafanaServiceAccountToken {
+	if in == nil {
+		return nil
+	}
+	out := new(GrafanaServiceAccountToken)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HTTPSpec) DeepCopyInto(out *HTTPSpec) {
 	*out = *in
@@ -257,6 +521,84 @@ func (in *HTTPSpec) DeepCopy() *HTTPSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexAlias) DeepCopyInto(out *IndexAlias) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexAlias.
+func (in *IndexAlias) DeepCopy() *IndexAlias {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexAlias)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexAliasStatus) DeepCopyInto(out *IndexAliasStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexAliasStatus.
+func (in *IndexAliasStatus) DeepCopy() *IndexAliasStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexAliasStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexDefaults) DeepCopyInto(out *IndexDefaults) {
+	*out = *in
+	if in.TotalFieldsLimit != nil {
+		in, out := &in.TotalFieldsLimit, &out.TotalFieldsLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumberOfShards != nil {
+		in, out := &in.NumberOfShards, &out.NumberOfShards
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NumberOfReplicas != nil {
+		in, out := &in.NumberOfReplicas, &out.NumberOfReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(string)
+		**out = **in
+	}
+	if in.TotalShardsPerNode != nil {
+		in, out := &in.TotalShardsPerNode, &out.TotalShardsPerNode
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Codec != nil {
+		in, out := &in.Codec, &out.Codec
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexDefaults.
+func (in *IndexDefaults) DeepCopy() *IndexDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IndexManagementPolicy) DeepCopyInto(out *IndexManagementPolicy) {
 	*out = *in
@@ -266,6 +608,11 @@ func (in *IndexManagementPolicy) DeepCopyInto(out *IndexManagementPolicy) {
 		**out = **in
 	}
 	in.Rollover.DeepCopyInto(&out.Rollover)
+	if in.MinCloseAge != nil {
+		in, out := &in.MinCloseAge, &out.MinCloseAge
+		*out = new(string)
+		**out = **in
+	}
 	return
 }
 
@@ -279,11 +626,84 @@ func (in *IndexManagementPolicy) DeepCopy() *IndexManagementPolicy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IndexSort) DeepCopyInto(out *IndexSort) {
+	*out = *in
+	if in.IndexPatterns != nil {
+		in, out := &in.IndexPatterns, &out.IndexPatterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IndexSort.
+func (in *IndexSort) DeepCopy() *IndexSort {
+	if in == nil {
+		return nil
+	}
+	out := new(IndexSort)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngestPipeline) DeepCopyInto(out *IngestPipeline) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngestPipeline.
+func (in *IngestPipeline) DeepCopy() *IngestPipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(IngestPipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IngressHost) DeepCopyInto(out *IngressHost) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IngressHost.
+func (in *IngressHost) DeepCopy() *IngressHost {
+	if in == nil {
+		return nil
+	}
+	out := new(IngressHost)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Kibana) DeepCopyInto(out *Kibana) {
 	*out = *in
 	out.Resources = in.Resources
 	in.Plugins.DeepCopyInto(&out.Plugins)
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IngressHosts != nil {
+		in, out := &in.IngressHosts, &out.IngressHosts
+		*out = make([]IngressHost, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxPayloadBytes != nil {
+		in, out := &in.MaxPayloadBytes, &out.MaxPayloadBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(OpenSearchDashboardsTLS)
+		**out = **in
+	}
 	return
 }
 
@@ -349,6 +769,268 @@ func (in *OpenSearchDashboardsPlugins) DeepCopy() *OpenSearchDashboardsPlugins {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchDashboardsTLS) DeepCopyInto(out *OpenSearchDashboardsTLS) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchDashboardsTLS.
+func (in *OpenSearchDashboardsTLS) DeepCopy() *OpenSearchDashboardsTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchDashboardsTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchAllocationAwareness) DeepCopyInto(out *OpenSearchAllocationAwareness) {
+	*out = *in
+	if in.Attributes != nil {
+		in, out := &in.Attributes, &out.Attributes
+		*out = make([]OpenSearchAwarenessAttribute, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchAllocationAwareness.
+func (in *OpenSearchAllocationAwareness) DeepCopy() *OpenSearchAllocationAwareness {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchAllocationAwareness)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchAwarenessAttribute) DeepCopyInto(out *OpenSearchAwarenessAttribute) {
+	*out = *in
+	if in.NodeValues != nil {
+		in, out := &in.NodeValues, &out.NodeValues
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.ForcedValues != nil {
+		in, out := &in.ForcedValues, &out.ForcedValues
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchAwarenessAttribute.
+func (in *OpenSearchAwarenessAttribute) DeepCopy() *OpenSearchAwarenessAttribute {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchAwarenessAttribute)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchCircuitBreakers) DeepCopyInto(out *OpenSearchCircuitBreakers) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchCircuitBreakers.
+func (in *OpenSearchCircuitBreakers) DeepCopy() *OpenSearchCircuitBreakers {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchCircuitBreakers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchDiskWatermarks) DeepCopyInto(out *OpenSearchDiskWatermarks) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchDiskWatermarks.
+func (in *OpenSearchDiskWatermarks) DeepCopy() *OpenSearchDiskWatermarks {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchDiskWatermarks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchGatewaySettings) DeepCopyInto(out *OpenSearchGatewaySettings) {
+	*out = *in
+	if in.RecoverAfterNodes != nil {
+		in, out := &in.RecoverAfterNodes, &out.RecoverAfterNodes
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExpectedNodes != nil {
+		in, out := &in.ExpectedNodes, &out.ExpectedNodes
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchGatewaySettings.
+func (in *OpenSearchGatewaySettings) DeepCopy() *OpenSearchGatewaySettings {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchGatewaySettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchNetworkPolicy) DeepCopyInto(out *OpenSearchNetworkPolicy) {
+	*out = *in
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedPodSelectors != nil {
+		in, out := &in.AllowedPodSelectors, &out.AllowedPodSelectors
+		*out = make([]metav1.LabelSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchNetworkPolicy.
+func (in *OpenSearchNetworkPolicy) DeepCopy() *OpenSearchNetworkPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchNetworkPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchMappingLimits) DeepCopyInto(out *OpenSearchMappingLimits) {
+	*out = *in
+	if in.TotalFields != nil {
+		in, out := &in.TotalFields, &out.TotalFields
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NestedFields != nil {
+		in, out := &in.NestedFields, &out.NestedFields
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NestedObjects != nil {
+		in, out := &in.NestedObjects, &out.NestedObjects
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchMappingLimits.
+func (in *OpenSearchMappingLimits) DeepCopy() *OpenSearchMappingLimits {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchMappingLimits)
+	in.DeepCopyInto(out)
+	return out
+}
+
+func (in *OpenSearchPodSecurityContext) DeepCopyInto(out *OpenSearchPodSecurityContext) {
+	*out = *in
+	if in.RunAsUser != nil {
+		in, out := &in.RunAsUser, &out.RunAsUser
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RunAsGroup != nil {
+		in, out := &in.RunAsGroup, &out.RunAsGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchPodSecurityContext.
+func (in *OpenSearchPodSecurityContext) DeepCopy() *OpenSearchPodSecurityContext {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchPodSecurityContext)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchSearchBackpressure) DeepCopyInto(out *OpenSearchSearchBackpressure) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchSearchBackpressure.
+func (in *OpenSearchSearchBackpressure) DeepCopy() *OpenSearchSearchBackpressure {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchSearchBackpressure)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchSnapshotRepository) DeepCopyInto(out *OpenSearchSnapshotRepository) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchSnapshotRepository.
+func (in *OpenSearchSnapshotRepository) DeepCopy() *OpenSearchSnapshotRepository {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchSnapshotRepository)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenSearchStartupProbe) DeepCopyInto(out *OpenSearchStartupProbe) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenSearchStartupProbe.
+func (in *OpenSearchStartupProbe) DeepCopy() *OpenSearchStartupProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenSearchStartupProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenSearchPlugins) DeepCopyInto(out *OpenSearchPlugins) {
 	*out = *in
@@ -392,6 +1074,137 @@ func (in *Opensearch) DeepCopyInto(out *Opensearch) {
 		}
 	}
 	in.Plugins.DeepCopyInto(&out.Plugins)
+	if in.RetainOrphanedPVCs != nil {
+		in, out := &in.RetainOrphanedPVCs, &out.RetainOrphanedPVCs
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Paused != nil {
+		in, out := &in.Paused, &out.Paused
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Loggers != nil {
+		in, out := &in.Loggers, &out.Loggers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(OpenSearchNetworkPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IngestPipelines != nil {
+		in, out := &in.IngestPipelines, &out.IngestPipelines
+		*out = make([]IngestPipeline, len(*in))
+		copy(*out, *in)
+	}
+	if in.IndexDefaults != nil {
+		in, out := &in.IndexDefaults, &out.IndexDefaults
+		*out = new(IndexDefaults)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MappingLimits != nil {
+		in, out := &in.MappingLimits, &out.MappingLimits
+		*out = new(OpenSearchMappingLimits)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ComponentTemplates != nil {
+		in, out := &in.ComponentTemplates, &out.ComponentTemplates
+		*out = make([]ComponentTemplate, len(*in))
+		copy(*out, *in)
+	}
+	if in.SearchBackpressure != nil {
+		in, out := &in.SearchBackpressure, &out.SearchBackpressure
+		*out = new(OpenSearchSearchBackpressure)
+		**out = **in
+	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(OpenSearchStartupProbe)
+		**out = **in
+	}
+	if in.SnapshotRepository != nil {
+		in, out := &in.SnapshotRepository, &out.SnapshotRepository
+		*out = new(OpenSearchSnapshotRepository)
+		**out = **in
+	}
+	if in.Monitors != nil {
+		in, out := &in.Monitors, &out.Monitors
+		*out = make([]AlertingMonitor, len(*in))
+		copy(*out, *in)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]IndexAlias, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExtraVolumes != nil {
+		in, out := &in.ExtraVolumes, &out.ExtraVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExtraVolumeMounts != nil {
+		in, out := &in.ExtraVolumeMounts, &out.ExtraVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AllocationAwareness != nil {
+		in, out := &in.AllocationAwareness, &out.AllocationAwareness
+		*out = new(OpenSearchAllocationAwareness)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiskWatermarks != nil {
+		in, out := &in.DiskWatermarks, &out.DiskWatermarks
+		*out = new(OpenSearchDiskWatermarks)
+		**out = **in
+	}
+	if in.GatewaySettings != nil {
+		in, out := &in.GatewaySettings, &out.GatewaySettings
+		*out = new(OpenSearchGatewaySettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableGCLogging != nil {
+		in, out := &in.EnableGCLogging, &out.EnableGCLogging
+		*out = new(bool)
+		**out = **in
+	}
+	if in.QueryCacheEnabled != nil {
+		in, out := &in.QueryCacheEnabled, &out.QueryCacheEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IngressHosts != nil {
+		in, out := &in.IngressHosts, &out.IngressHosts
+		*out = make([]IngressHost, len(*in))
+		copy(*out, *in)
+	}
+	if in.PodSecurityContext != nil {
+		in, out := &in.PodSecurityContext, &out.PodSecurityContext
+		*out = new(OpenSearchPodSecurityContext)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CircuitBreakers != nil {
+		in, out := &in.CircuitBreakers, &out.CircuitBreakers
+		*out = new(OpenSearchCircuitBreakers)
+		**out = **in
+	}
+	if in.DefaultIndexSort != nil {
+		in, out := &in.DefaultIndexSort, &out.DefaultIndexSort
+		*out = new(IndexSort)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxClauseCount != nil {
+		in, out := &in.MaxClauseCount, &out.MaxClauseCount
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -410,6 +1223,26 @@ func (in *OpensearchDashboards) DeepCopyInto(out *OpensearchDashboards) {
 	*out = *in
 	out.Resources = in.Resources
 	in.Plugins.DeepCopyInto(&out.Plugins)
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(bool)
+		**out = **in
+	}
+	if in.IngressHosts != nil {
+		in, out := &in.IngressHosts, &out.IngressHosts
+		*out = make([]IngressHost, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxPayloadBytes != nil {
+		in, out := &in.MaxPayloadBytes, &out.MaxPayloadBytes
+		*out = new(int64)
+		**out = **in
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(OpenSearchDashboardsTLS)
+		**out = **in
+	}
 	return
 }
 
@@ -652,7 +1485,7 @@ func (in *VerrazzanoMonitoringInstanceSpec) DeepCopyInto(out *VerrazzanoMonitori
 		(*in).DeepCopyInto(*out)
 	}
 	in.OpensearchDashboards.DeepCopyInto(&out.OpensearchDashboards)
-	out.API = in.API
+	in.API.DeepCopyInto(&out.API)
 	if in.NatGatewayIPs != nil {
 		in, out := &in.NatGatewayIPs, &out.NatGatewayIPs
 		*out = make([]string, len(*in))
@@ -683,6 +1516,23 @@ func (in *VerrazzanoMonitoringInstanceStatus) DeepCopyInto(out *VerrazzanoMonito
 		in, out := &in.CreationTime, &out.CreationTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Monitors != nil {
+		in, out := &in.Monitors, &out.Monitors
+		*out = make([]AlertingMonitorStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Aliases != nil {
+		in, out := &in.Aliases, &out.Aliases
+		*out = make([]IndexAliasStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
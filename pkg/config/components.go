@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package config
@@ -21,6 +21,7 @@ type ComponentDetails struct {
 	ImagePullPolicy   corev1.PullPolicy
 	Port              int
 	DataDir           string
+	DataDirEnvName    string
 	LivenessHTTPPath  string
 	ReadinessHTTPPath string
 	Privileged        bool
@@ -188,6 +189,8 @@ var ElasticsearchMaster = ComponentDetails{
 	EnvName:         "OPENSEARCH_IMAGE",
 	ImagePullPolicy: constants.DefaultImagePullPolicy,
 	Port:            constants.OSTransportPort,
+	DataDir:         "/usr/share/opensearch/data",
+	DataDirEnvName:  "OPENSEARCH_DATA_DIR",
 	Privileged:      false,
 }
 
@@ -198,6 +201,7 @@ var ElasticsearchData = ComponentDetails{
 	ImagePullPolicy:   constants.DefaultImagePullPolicy,
 	Port:              constants.OSHTTPPort,
 	DataDir:           "/usr/share/opensearch/data",
+	DataDirEnvName:    "OPENSEARCH_DATA_DIR",
 	LivenessHTTPPath:  "/_cluster/health",
 	ReadinessHTTPPath: "/_cluster/health",
 	Privileged:        false,
@@ -248,6 +252,12 @@ func InitComponentDetails() error {
 				component.Disabled = true
 			}
 		}
+		// Custom images may store their data in a different directory
+		if len(component.DataDirEnvName) > 0 {
+			if dataDir := os.Getenv(component.DataDirEnvName); len(dataDir) > 0 {
+				component.DataDir = dataDir
+			}
+		}
 		for i, sidecar := range component.Sidecars {
 			if len(component.EnvName) == 0 {
 				zap.S().Infof("The environment variable is missing for sidecar %s. Marking sidecar disabled.", sidecar.Name)
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package config
@@ -40,6 +40,26 @@ func TestOptionalImages(t *testing.T) {
 	testImages(t, components, optionalComponents)
 }
 
+// TestDataDirFromEnv tests overriding the OpenSearch data directory
+// GIVEN the OpenSearch data directory environment variable is set
+//
+//	WHEN I call InitComponentDetails
+//	THEN the data directory of the OpenSearch master and data components is the configured value
+func TestDataDirFromEnv(t *testing.T) {
+	defaultMasterDataDir := ElasticsearchMaster.DataDir
+	defaultDataDataDir := ElasticsearchData.DataDir
+	defer func() {
+		ElasticsearchMaster.DataDir = defaultMasterDataDir
+		ElasticsearchData.DataDir = defaultDataDataDir
+	}()
+	createEnvVars(t, AllComponentDetails)
+	t.Setenv(ElasticsearchData.DataDirEnvName, "/opt/opensearch/data")
+	testImages(t, AllComponentDetails, nil)
+	assert.Equal(t, "/opt/opensearch/data", ElasticsearchMaster.DataDir)
+	assert.Equal(t, "/opt/opensearch/data", ElasticsearchData.DataDir)
+	assert.Equal(t, "/var/lib/grafana", Grafana.DataDir)
+}
+
 func createEnvVars(t *testing.T, components []*ComponentDetails) {
 	// Create environment variable for each component
 	for _, component := range components {
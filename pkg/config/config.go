@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package config
@@ -36,6 +36,13 @@ func NewConfigFromConfigMap(configMap *corev1.ConfigMap) (*OperatorConfig, error
 	return &config, nil
 }
 
+// NewDefaultConfig creates a new OperatorConfig with all values set to their defaults
+func NewDefaultConfig() *OperatorConfig {
+	var config OperatorConfig
+	setConfigDefaults(&config)
+	return &config
+}
+
 // Sets defaults for the given OperatorConfig object.
 func setConfigDefaults(config *OperatorConfig) {
 
@@ -45,6 +52,14 @@ func setConfigDefaults(config *OperatorConfig) {
 	if config.MetricsPort == nil {
 		config.MetricsPort = newIntVal(defaultMetricsPort)
 	}
+	if config.MaxReconcileDuration == nil || *config.MaxReconcileDuration <= 0 {
+		maxReconcileDuration := defaultMaxReconcileDuration
+		config.MaxReconcileDuration = &maxReconcileDuration
+	}
+	if config.DashboardsScaleDownGracePeriod == nil || *config.DashboardsScaleDownGracePeriod < 0 {
+		gracePeriod := defaultDashboardsScaleDownGracePeriod
+		config.DashboardsScaleDownGracePeriod = &gracePeriod
+	}
 
 }
 
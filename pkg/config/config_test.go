@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package config
@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -43,3 +44,35 @@ func CreateConfigFromStr(configStr string) (*OperatorConfig, error) {
 	operatorConfig, err := NewConfigFromConfigMap(&configMap)
 	return operatorConfig, err
 }
+
+// TestMaxReconcileDuration Tests the maximum reconcile duration of the operator config
+// GIVEN an operator config with or without a maximum reconcile duration
+// WHEN the config is created
+// THEN the maximum reconcile duration is the configured one, or the default
+func TestMaxReconcileDuration(t *testing.T) {
+	operatorConfig, err := CreateConfigFromStr(`envName: testenv`)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultMaxReconcileDuration, *operatorConfig.MaxReconcileDuration)
+
+	operatorConfig, err = CreateConfigFromStr("envName: testenv\nmaxReconcileDuration: 90s")
+	assert.NoError(t, err)
+	assert.Equal(t, 90*time.Second, *operatorConfig.MaxReconcileDuration)
+}
+
+// TestDashboardsScaleDownGracePeriod Tests the OpenSearch Dashboards scale down grace period of the operator config
+// GIVEN an operator config with or without a scale down grace period
+// WHEN the config is created
+// THEN the scale down grace period is the configured one, or the default
+func TestDashboardsScaleDownGracePeriod(t *testing.T) {
+	operatorConfig, err := CreateConfigFromStr(`envName: testenv`)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultDashboardsScaleDownGracePeriod, *operatorConfig.DashboardsScaleDownGracePeriod)
+
+	operatorConfig, err = CreateConfigFromStr("envName: testenv\ndashboardsScaleDownGracePeriod: 0s")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), *operatorConfig.DashboardsScaleDownGracePeriod)
+
+	operatorConfig, err = CreateConfigFromStr("envName: testenv\ndashboardsScaleDownGracePeriod: 2m")
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, *operatorConfig.DashboardsScaleDownGracePeriod)
+}
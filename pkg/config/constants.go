@@ -1,8 +1,10 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package config
 
+import "time"
+
 // OperatorConfig type for operator configuration
 type OperatorConfig struct {
 	EnvName                        string   `yaml:"envName"`
@@ -11,6 +13,13 @@ type OperatorConfig struct {
 	MetricsPort                    *int     `yaml:"metricsPort"`
 	NatGatewayIPs                  []string `yaml:"natGatewayIPs"`
 	Pvcs                           Pvcs     `yaml:"pvcs"`
+	// Disable the validation of the OpenSearch cluster before adding data nodes
+	DisableDataScaleUpValidation bool `yaml:"disableDataScaleUpValidation,omitempty"`
+	// The maximum duration of the reconcile of a VMI, after which the reconcile is cancelled and the VMI requeued
+	MaxReconcileDuration *time.Duration `yaml:"maxReconcileDuration,omitempty"`
+	// The time to wait for the in-flight requests of OpenSearch Dashboards to drain before removing its last replica,
+	// when OpenSearch Dashboards is scaled to zero replicas
+	DashboardsScaleDownGracePeriod *time.Duration `yaml:"dashboardsScaleDownGracePeriod,omitempty"`
 }
 
 // Pvcs type for storage
@@ -25,3 +34,5 @@ const DefaultOperatorConfigmapName = "verrazzano-monitoring-operator-config"
 const configKeyValue = "config"
 const defaultSimpleComponentReplicas = 1
 const defaultMetricsPort = 8090
+const defaultMaxReconcileDuration = 10 * time.Minute
+const defaultDashboardsScaleDownGracePeriod = 30 * time.Second
@@ -50,9 +50,78 @@ const RoleBindingForVMOInstance = "verrazzano-monitoring-operator"
 // ClusterRoleForVMOInstances clusterrole name for VMO instance
 const ClusterRoleForVMOInstances = "vmi-cluster-role"
 
+// ClusterRoleForVMO clusterrole name prefix for the VMO itself, suffixed by the namespace of the VMO
+const ClusterRoleForVMO = "verrazzano-monitoring-operator-cluster-role"
+
+// ClusterRoleBindingForVMO clusterrolebinding name prefix for the VMO itself, suffixed by the namespace of the VMO
+const ClusterRoleBindingForVMO = "verrazzano-monitoring-operator-cluster-role-binding"
+
 // ResyncPeriod (re-list time period) for VMO Controller
 const ResyncPeriod = 30 * time.Second
 
+// InformerSyncCheckPeriod is the period at which the informer cache sync status is re-checked
+const InformerSyncCheckPeriod = 60 * time.Second
+
+// OperatorRBACReconcilePeriod is the period at which the ClusterRole and ClusterRoleBinding of the VMO are reconciled,
+// when enabled
+const OperatorRBACReconcilePeriod = 5 * time.Minute
+
+// LeaderElectionLeaseName is the name of the Lease held by the leader of the VMO replicas
+const LeaderElectionLeaseName = "verrazzano-monitoring-operator-leader"
+
+// LeaderElectionLeaseDuration is the time the other VMO replicas wait before taking over a Lease which is not renewed
+const LeaderElectionLeaseDuration = 15 * time.Second
+
+// LeaderElectionRenewDeadline is the time the leader retries to renew its Lease before giving up the leadership
+const LeaderElectionRenewDeadline = 10 * time.Second
+
+// LeaderElectionRetryPeriod is the period at which the VMO replicas try to acquire or renew the Lease
+const LeaderElectionRetryPeriod = 2 * time.Second
+
+// DeploymentUpdateMaxFailures is the number of consecutive failures of the same deployment update before it is no longer retried
+const DeploymentUpdateMaxFailures = 5
+
+// QuotaExceededInitialBackoff is the time the creation of the resources of a VMI is not retried after a resource quota rejected one
+const QuotaExceededInitialBackoff = 30 * time.Second
+
+// QuotaExceededMaxBackoff is the maximum time the creation of the resources of a VMI is not retried, while resource quotas keep rejecting them
+const QuotaExceededMaxBackoff = 10 * time.Minute
+
+// OpenSearchStartupProbeDefaultPeriodSeconds is the default number of seconds between the startup checks of an OpenSearch node
+const OpenSearchStartupProbeDefaultPeriodSeconds = 10
+
+// OpenSearchStartupProbeDefaultFailureThreshold is the default number of failed startup checks before an OpenSearch node is restarted,
+// which gives a node 15 minutes to recover its shards
+const OpenSearchStartupProbeDefaultFailureThreshold = 90
+
+// OpenSearchSnapshotRepositoryVolumeName is the name of the volume of the shared filesystem snapshot repository
+const OpenSearchSnapshotRepositoryVolumeName = "snapshot-repository"
+
+// OpenSearchKeystoreVolumeName is the name of the volume of the pre-populated OpenSearch keystore Secret
+const OpenSearchKeystoreVolumeName = "opensearch-keystore"
+
+// OpenSearchNodeNamePrefixEnv is the env var holding the prefix of the node names of an OpenSearch master node
+const OpenSearchNodeNamePrefixEnv = "NODE_NAME_PREFIX"
+
+// OpenSearchKeystoreMountPath is the path the pre-populated OpenSearch keystore Secret is mounted at
+const OpenSearchKeystoreMountPath = "/mnt/opensearch-keystore"
+
+// OpenSearchKeystoreKey is the key of the keystore in the pre-populated OpenSearch keystore Secret
+const OpenSearchKeystoreKey = "opensearch.keystore"
+
+// OpenSearchGCLogsVolumeName is the name of the volume the JVM garbage collection logs of OpenSearch are written to
+const OpenSearchGCLogsVolumeName = "gc-logs"
+
+// OpenSearchGCLogsMountPath is the path the JVM garbage collection logs volume of OpenSearch is mounted at
+const OpenSearchGCLogsMountPath = "/usr/share/opensearch/logs/gc"
+
+// OpenSearchGCLoggingJavaOpts are the JVM options enabling the garbage collection logs of OpenSearch, with the log
+// files rotated to bound the size of the logs volume
+const OpenSearchGCLoggingJavaOpts = "-Xlog:gc*,gc+age=trace,safepoint:file=" + OpenSearchGCLogsMountPath + "/gc.log:utctime,pid,tags:filecount=8,filesize=64m"
+
+// OpenSearchAwarenessAttributeDefaultValue is the allocation awareness attribute value of the nodes without a value
+const OpenSearchAwarenessAttributeDefaultValue = "None"
+
 // VMOServiceNamePrefix to be applied to all VMO services
 const VMOServiceNamePrefix = "vmi-"
 
@@ -68,6 +137,9 @@ const StorageVolumeName = "storage-volume"
 // DefaultNamespace constant for default namespace
 const DefaultNamespace = "default"
 
+// DefaultClusterDomain constant for the default DNS domain of the cluster
+const DefaultClusterDomain = "cluster.local"
+
 // ServiceAppLabel label name for service app
 const ServiceAppLabel = "app"
 
@@ -157,6 +229,13 @@ const K8sDefaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-
 // K8sDefaultStorageClassBetaAnnotation annotation for default storage class beta flavor
 const K8sDefaultStorageClassBetaAnnotation = "storageclass.beta.kubernetes.io/is-default-class"
 
+// RetainedPVCAnnotation annotation for PVCs of removed OpenSearch data nodes that are retained for manual cleanup
+const RetainedPVCAnnotation = VMOGroup + "/retained-pvc"
+
+// AllowOpenSearchDowngradeAnnotation annotation of a VMI allowing its OpenSearch images to be an older major version
+// than the running OpenSearch cluster, for intentional reindex based downgrades
+const AllowOpenSearchDowngradeAnnotation = VMOGroup + "/allow-opensearch-downgrade"
+
 // MonitoringNamespace Monitoring namespace
 const MonitoringNamespace = "monitoring"
 
@@ -188,6 +267,13 @@ const (
 	ObjectStoreCustomerKey        = "object_store_secret_key"
 	DisableSecurityPluginOS       = "DISABLE_SECURITY_PLUGIN"
 	DisableSecurityPluginOSD      = "DISABLE_SECURITY_DASHBOARDS_PLUGIN"
+	OSDServerNameEnv              = "SERVER_NAME"
+	OSDDefaultRouteEnv            = "SERVER_DEFAULTROUTE"
+	OSDRequestTimeoutEnv          = "OPENSEARCH_REQUESTTIMEOUT"
+	OSDMaxPayloadBytesEnv         = "SERVER_MAXPAYLOADBYTES"
+	OSDServerSSLEnabledEnv        = "SERVER_SSL_ENABLED"
+	OSDServerSSLCertificateEnv    = "SERVER_SSL_CERTIFICATE"
+	OSDServerSSLKeyEnv            = "SERVER_SSL_KEY"
 )
 
 // ComponentLabel - the label for a specific component
@@ -217,8 +303,36 @@ const TimeStamp = "@timestamp"
 // HoldAppUntilProxyStarts used to hold the application until Istio sidecar proxy starts
 const HoldAppUntilProxyStarts = "true"
 
+// OSDTLSVolumeName is the name of the volume of the TLS Secret of OpenSearch Dashboards
+const OSDTLSVolumeName = "osd-tls"
+
+// OSDTLSMountPath is the path the TLS Secret of OpenSearch Dashboards is mounted at
+const OSDTLSMountPath = "/usr/share/opensearch-dashboards/config/tls"
+
 // GrafanaSMTPConfigVolumeName is the name of volume created for SMTP configurations in Grafana deployment.
 const GrafanaSMTPConfigVolumeName = "smtp-config"
 
 // GrafanaSMTPConfigVolumePath is the mount path of volume created for SMTP configurations in Grafana deployment.
 const GrafanaSMTPConfigVolumePath = "/etc/grafana/smtp-config"
+
+// GrafanaDefaultRootURLScheme is the scheme of the Grafana root URL if none is specified
+const GrafanaDefaultRootURLScheme = "https"
+
+// GrafanaDefaultAuthProxyHeaderName is the name of the auth proxy user header if none is specified
+const GrafanaDefaultAuthProxyHeaderName = "X-WEBAUTH-USER"
+
+// GrafanaDefaultUID is the UID and GID of the grafana user and group of the Grafana image
+const GrafanaDefaultUID = 472
+
+// GrafanaDefaultServiceAccountRole is the role of the provisioned Grafana service account if none is specified
+const GrafanaDefaultServiceAccountRole = "Viewer"
+
+// GrafanaServiceAccountTokenKey is the key of the token in the Secret of the provisioned Grafana service account
+const GrafanaServiceAccountTokenKey = "token"
+
+// GrafanaServiceAccountRoleAnnotation is the annotation of the Secret of the provisioned Grafana service account recording
+// the role of the service account
+const GrafanaServiceAccountRoleAnnotation = "verrazzano.io/grafana-service-account-role"
+
+// GrafanaDefaultDashboardFolderAnnotation is the annotation of the dashboard ConfigMaps naming the folder of their dashboards if none is specified
+const GrafanaDefaultDashboardFolderAnnotation = "grafana_folder"
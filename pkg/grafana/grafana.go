@@ -0,0 +1,91 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package grafana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// requestTimeout bounds the Grafana API calls, so that an unresponsive Grafana does not block the reconcile
+const requestTimeout = 30 * time.Second
+
+type (
+	// Client calls the HTTP API of Grafana
+	Client struct {
+		httpClient *http.Client
+		DoHTTP     func(request *http.Request) (*http.Response, error)
+	}
+
+	// AdminCredentials are the credentials of the Grafana admin user. When Grafana is behind the auth proxy, the
+	// admin user name is also sent in the auth proxy header.
+	AdminCredentials struct {
+		Username            string
+		Password            string
+		AuthProxyHeaderName string
+	}
+)
+
+// NewClient creates a client of the Grafana HTTP API, whose requests time out after the request timeout
+func NewClient() *Client {
+	g := &Client{
+		httpClient: &http.Client{Timeout: requestTimeout},
+	}
+	g.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		return g.httpClient.Do(request)
+	}
+	return g
+}
+
+// IsReady returns true if the Grafana health API succeeds
+func (g *Client) IsReady(ctx context.Context, grafanaEndpoint string) bool {
+	req, err := http.NewRequestWithContext(ctx, "GET", grafanaEndpoint+"/api/health", nil)
+	if err != nil {
+		return false
+	}
+	resp, err := g.DoHTTP(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// doAdminRequest calls the Grafana API as the admin user, decoding the JSON response into result if not nil
+func (g *Client) doAdminRequest(ctx context.Context, credentials *AdminCredentials, method, url string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/json")
+	req.SetBasicAuth(credentials.Username, credentials.Password)
+	if credentials.AuthProxyHeaderName != "" {
+		req.Header.Add(credentials.AuthProxyHeaderName, credentials.Username)
+	}
+	resp, err := g.DoHTTP(req)
+	if err != nil {
+		return fmt.Errorf("failed to call Grafana %s %s: %v", method, req.URL.Path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("got status code %d when calling Grafana %s %s", resp.StatusCode, method, req.URL.Path)
+	}
+	if result == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
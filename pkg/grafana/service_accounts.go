@@ -0,0 +1,98 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package grafana
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+type (
+	// ServiceAccount is a Grafana service account
+	ServiceAccount struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+		Role string `json:"role"`
+	}
+
+	// serviceAccountSearch is the response of the service account search API
+	serviceAccountSearch struct {
+		ServiceAccounts []ServiceAccount `json:"serviceAccounts"`
+	}
+
+	// serviceAccountToken is the response of the service account token creation API
+	serviceAccountToken struct {
+		Key string `json:"key"`
+	}
+)
+
+// EnsureServiceAccount returns the Grafana service account with the given name, after creating it if it does not exist
+// or updating its role if it differs from the given role
+func (g *Client) EnsureServiceAccount(ctx context.Context, grafanaEndpoint string, credentials *AdminCredentials, name, role string) (*ServiceAccount, error) {
+	search := &serviceAccountSearch{}
+	searchURL := fmt.Sprintf("%s/api/serviceaccounts/search?query=%s", grafanaEndpoint, url.QueryEscape(name))
+	if err := g.doAdminRequest(ctx, credentials, "GET", searchURL, nil, search); err != nil {
+		return nil, err
+	}
+	for i := range search.ServiceAccounts {
+		serviceAccount := &search.ServiceAccounts[i]
+		// the search matches names containing the query
+		if serviceAccount.Name != name {
+			continue
+		}
+		if serviceAccount.Role == role {
+			return serviceAccount, nil
+		}
+		updateURL := fmt.Sprintf("%s/api/serviceaccounts/%d", grafanaEndpoint, serviceAccount.ID)
+		if err := g.doAdminRequest(ctx, credentials, http.MethodPatch, updateURL, map[string]string{"role": role}, nil); err != nil {
+			return nil, err
+		}
+		serviceAccount.Role = role
+		return serviceAccount, nil
+	}
+	serviceAccount := &ServiceAccount{}
+	createURL := fmt.Sprintf("%s/api/serviceaccounts", grafanaEndpoint)
+	if err := g.doAdminRequest(ctx, credentials, "POST", createURL, map[string]interface{}{"name": name, "role": role, "isDisabled": false}, serviceAccount); err != nil {
+		return nil, err
+	}
+	return serviceAccount, nil
+}
+
+// CreateServiceAccountToken creates a token with the given name for the Grafana service account, and returns its key
+func (g *Client) CreateServiceAccountToken(ctx context.Context, grafanaEndpoint string, credentials *AdminCredentials, serviceAccountID int64, tokenName string) (string, error) {
+	token := &serviceAccountToken{}
+	createURL := fmt.Sprintf("%s/api/serviceaccounts/%d/tokens", grafanaEndpoint, serviceAccountID)
+	if err := g.doAdminRequest(ctx, credentials, "POST", createURL, map[string]string{"name": tokenName}, token); err != nil {
+		return "", err
+	}
+	if token.Key == "" {
+		return "", fmt.Errorf("Grafana returned no key for the token %s of the service account %d", tokenName, serviceAccountID)
+	}
+	return token.Key, nil
+}
+
+// IsTokenValid returns true if Grafana accepts the token, and false if Grafana rejects it, e.g. because the token or its
+// service account were deleted
+func (g *Client) IsTokenValid(ctx context.Context, grafanaEndpoint, token string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", grafanaEndpoint+"/api/org", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Add("Authorization", "Bearer "+token)
+	resp, err := g.DoHTTP(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to validate the Grafana token: %v", err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized:
+		return false, nil
+	default:
+		return false, fmt.Errorf("got status code %d when validating the Grafana token", resp.StatusCode)
+	}
+}
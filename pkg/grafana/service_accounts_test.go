@@ -0,0 +1,150 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package grafana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testGrafanaEndpoint = "http://grafana:3000"
+
+var testCredentials = &AdminCredentials{Username: "admin", Password: "secret", AuthProxyHeaderName: "X-WEBAUTH-USER"}
+
+// mockGrafana is a Grafana holding service accounts and tokens in memory
+type mockGrafana struct {
+	serviceAccounts []ServiceAccount
+	tokens          map[string]int64
+	requests        []string
+}
+
+// createMockGrafanaClient creates a Client calling the mock Grafana, which checks the admin credentials of the requests
+func createMockGrafanaClient(t *testing.T, grafana *mockGrafana) *Client {
+	g := NewClient()
+	g.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		grafana.requests = append(grafana.requests, request.Method+" "+request.URL.Path)
+		respond := func(code int, body interface{}) (*http.Response, error) {
+			payload, _ := json.Marshal(body)
+			return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(string(payload)))}, nil
+		}
+		if request.URL.Path == "/api/org" {
+			if _, ok := grafana.tokens[strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")]; ok {
+				return respond(http.StatusOK, map[string]interface{}{"id": 1})
+			}
+			return respond(http.StatusUnauthorized, map[string]string{"message": "invalid API key"})
+		}
+		username, password, ok := request.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, testCredentials.Username, username)
+		assert.Equal(t, testCredentials.Password, password)
+		assert.Equal(t, testCredentials.Username, request.Header.Get("X-WEBAUTH-USER"))
+		body := map[string]interface{}{}
+		if request.Body != nil {
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+		}
+		switch {
+		case request.Method == "GET" && request.URL.Path == "/api/serviceaccounts/search":
+			var found []ServiceAccount
+			for _, serviceAccount := range grafana.serviceAccounts {
+				if strings.Contains(serviceAccount.Name, request.URL.Query().Get("query")) {
+					found = append(found, serviceAccount)
+				}
+			}
+			return respond(http.StatusOK, map[string]interface{}{"totalCount": len(found), "serviceAccounts": found})
+		case request.Method == "POST" && request.URL.Path == "/api/serviceaccounts":
+			serviceAccount := ServiceAccount{ID: int64(len(grafana.serviceAccounts) + 1), Name: body["name"].(string), Role: body["role"].(string)}
+			grafana.serviceAccounts = append(grafana.serviceAccounts, serviceAccount)
+			return respond(http.StatusCreated, serviceAccount)
+		}
+		for i := range grafana.serviceAccounts {
+			serviceAccount := &grafana.serviceAccounts[i]
+			switch {
+			case request.Method == http.MethodPatch && request.URL.Path == fmt.Sprintf("/api/serviceaccounts/%d", serviceAccount.ID):
+				serviceAccount.Role = body["role"].(string)
+				return respond(http.StatusOK, serviceAccount)
+			case request.Method == "POST" && request.URL.Path == fmt.Sprintf("/api/serviceaccounts/%d/tokens", serviceAccount.ID):
+				key := fmt.Sprintf("glsa_%d_%s", serviceAccount.ID, body["name"])
+				grafana.tokens[key] = serviceAccount.ID
+				return respond(http.StatusOK, map[string]interface{}{"id": len(grafana.tokens), "name": body["name"], "key": key})
+			}
+		}
+		return respond(http.StatusNotFound, map[string]string{"message": "not found"})
+	}
+	return g
+}
+
+// TestEnsureServiceAccount Tests ensuring a Grafana service account
+// GIVEN a Grafana with a service account whose name contains the name of the ensured service account
+// WHEN I call EnsureServiceAccount to ensure a service account, again with the same role, then with another role
+// THEN the service account is created once, and its role is updated when it differs
+func TestEnsureServiceAccount(t *testing.T) {
+	grafana := &mockGrafana{serviceAccounts: []ServiceAccount{{ID: 1, Name: "automation-old", Role: "Admin"}}, tokens: map[string]int64{}}
+	g := createMockGrafanaClient(t, grafana)
+
+	serviceAccount, err := g.EnsureServiceAccount(context.TODO(), testGrafanaEndpoint, testCredentials, "automation", "Viewer")
+	assert.NoError(t, err)
+	assert.Equal(t, &ServiceAccount{ID: 2, Name: "automation", Role: "Viewer"}, serviceAccount)
+
+	serviceAccount, err = g.EnsureServiceAccount(context.TODO(), testGrafanaEndpoint, testCredentials, "automation", "Viewer")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), serviceAccount.ID)
+	assert.Len(t, grafana.serviceAccounts, 2)
+
+	serviceAccount, err = g.EnsureServiceAccount(context.TODO(), testGrafanaEndpoint, testCredentials, "automation", "Editor")
+	assert.NoError(t, err)
+	assert.Equal(t, "Editor", serviceAccount.Role)
+	assert.Equal(t, "Editor", grafana.serviceAccounts[1].Role)
+	assert.Equal(t, "Admin", grafana.serviceAccounts[0].Role)
+}
+
+// TestCreateServiceAccountToken Tests creating and validating a token of a Grafana service account
+// GIVEN a Grafana with a service account
+// WHEN I call CreateServiceAccountToken, then IsTokenValid with the created token and with an unknown token
+// THEN the key of the created token is returned, and only the created token is valid
+func TestCreateServiceAccountToken(t *testing.T) {
+	grafana := &mockGrafana{serviceAccounts: []ServiceAccount{{ID: 1, Name: "automation", Role: "Viewer"}}, tokens: map[string]int64{}}
+	g := createMockGrafanaClient(t, grafana)
+
+	token, err := g.CreateServiceAccountToken(context.TODO(), testGrafanaEndpoint, testCredentials, 1, "automation-token")
+	assert.NoError(t, err)
+	assert.Equal(t, "glsa_1_automation-token", token)
+
+	valid, err := g.IsTokenValid(context.TODO(), testGrafanaEndpoint, token)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	valid, err = g.IsTokenValid(context.TODO(), testGrafanaEndpoint, "glsa_unknown")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	_, err = g.CreateServiceAccountToken(context.TODO(), testGrafanaEndpoint, testCredentials, 2, "automation-token")
+	assert.ErrorContains(t, err, "404")
+}
+
+// TestNewClient Tests that the Grafana API calls are bounded
+// GIVEN a new Grafana client
+// WHEN I call the Grafana API with a context
+// THEN the HTTP client has a timeout and the requests carry the context
+func TestNewClient(t *testing.T) {
+	g := NewClient()
+	assert.Equal(t, requestTimeout, g.httpClient.Timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	g.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		assert.Equal(t, ctx, request.Context())
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+	}
+	assert.True(t, g.IsReady(ctx, testGrafanaEndpoint))
+	valid, err := g.IsTokenValid(ctx, testGrafanaEndpoint, "glsa_token")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
@@ -1,10 +1,11 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package metricsexporter
 
 import (
 	"strconv"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -21,13 +22,16 @@ const (
 	NamesDeploymentDeleteError   metricName = "deploymentDeleteErrorCounter"
 	NamesDeploymentUpdateCounter metricName = "deploymentUpdateCounter"
 	NamesConfigMap               metricName = "configMap"
+	NamesConfigMapDeleted        metricName = "configMapDeleted"
 	NamesServicesCreated         metricName = "servicesCreated"
 	NamesServices                metricName = "services"
 	NamesRoleBindings            metricName = "roleBindings"
+	NamesNetworkPolicies         metricName = "networkPolicies"
 	NamesIngress                 metricName = "ingress"
 	NamesIngressDeleted          metricName = "ingressDeleted"
 	NamesVMOUpdate               metricName = "vmoupdate"
 	NamesQueue                   metricName = "queue"
+	NamesInformerSynced          metricName = "informerSynced"
 )
 
 type metricsExporter struct {
@@ -48,6 +52,7 @@ type data struct {
 	functionMetricsMap     map[metricName]*FunctionMetrics
 	simpleCounterMetricMap map[metricName]*CounterMetric
 	simpleGaugeMetricMap   map[metricName]*GaugeMetric
+	labeledGaugeMetricMap  map[metricName]*LabeledGaugeMetric
 	durationMetricMap      map[metricName]*DurationMetric
 	timestampMetricMap     map[metricName]*TimestampMetric
 	errorMetricMap         map[metricName]*ErrorMetric
@@ -67,17 +72,18 @@ type FunctionMetrics struct {
 	index         int64
 }
 
-// Method to call at the start of the tracked function. Starts the duration timer and increments the total count
-func (f *FunctionMetrics) LogStart() {
+// Method to call at the start of the tracked function. Starts the duration timer and increments the total count.
+// Returns the duration timer of the call to pass to LogEnd, as the tracked function may be called concurrently.
+func (f *FunctionMetrics) LogStart() *prometheus.Timer {
 	f.callsTotal.metric.Inc()
-	f.index = f.index + 1
-	f.durationMetric.TimerStart()
+	atomic.AddInt64(&f.index, 1)
+	return prometheus.NewTimer(f.durationMetric.metric)
 }
 
-// Method to defer to the end of the tracked function. Stops the duration timer, sets the lastCallTimestamp. Pass in an argument of true to set an error for the current function call.
-func (f *FunctionMetrics) LogEnd(errorObserved bool) {
-	label := (*f.labelFunction)(f.index)
-	f.durationMetric.TimerStop()
+// Method to defer to the end of the tracked function. Stops the duration timer of the call, sets the lastCallTimestamp. Pass in an argument of true to set an error for the current function call.
+func (f *FunctionMetrics) LogEnd(timer *prometheus.Timer, errorObserved bool) {
+	label := f.GetLabel()
+	timer.ObserveDuration()
 	f.lastCallTimestamp.SetLastTimeWithLabel(label)
 	if errorObserved {
 		f.errorTotal.IncWithLabel(label)
@@ -90,7 +96,7 @@ func (f *FunctionMetrics) IncError() {
 
 // Invokes the supplied labelFunction to return the string which would be used as a label. The label can be dynamic and may change depending on the labelFunctions behavior (i.e. a timestamp string)
 func (f *FunctionMetrics) GetLabel() string {
-	return (*f.labelFunction)(f.index)
+	return (*f.labelFunction)(atomic.LoadInt64(&f.index))
 }
 
 // Type to count events such as the number fo function calls.
@@ -101,19 +107,19 @@ type CounterMetric struct {
 
 // Inc increases the counterMetric by one
 func (c *CounterMetric) Inc() {
-	c.index = c.index + 1
+	atomic.AddInt64(&c.index, 1)
 	c.metric.Inc()
 }
 
 // Add increases the counter metric by the argument value
 func (c *CounterMetric) Add(num float64) {
-	c.index = c.index + int64(num)
+	atomic.AddInt64(&c.index, int64(num))
 	c.metric.Add(num)
 }
 
 // GetLabel returns the current value of the counter as a string
 func (c *CounterMetric) GetLabel() string {
-	return strconv.FormatInt(c.index, 10)
+	return strconv.FormatInt(atomic.LoadInt64(&c.index), 10)
 }
 
 type GaugeMetric struct {
@@ -135,6 +141,21 @@ func (g *GaugeMetric) Add(num float64) {
 	g.metric.Add(num)
 }
 
+// Type to track a gauge value per label, such as the sync status of each informer.
+type LabeledGaugeMetric struct {
+	metric *prometheus.GaugeVec
+}
+
+// SetWithLabel sets the value of the gauge metric for the given label
+func (g *LabeledGaugeMetric) SetWithLabel(label string, num float64) {
+	gaugeMetric, err := g.metric.GetMetricWithLabelValues(label)
+	if err != nil {
+		zap.S().Errorf("Failed to get metric label %s: %v", label, err)
+	} else {
+		gaugeMetric.Set(num)
+	}
+}
+
 // Type to track length of a function call. Method to start and stop the duration timer are available.
 type DurationMetric struct {
 	metric prometheus.Summary
@@ -272,6 +293,9 @@ func initCounterMetricMap() map[metricName]*CounterMetric {
 		NamesConfigMap: {
 			metric: prometheus.NewCounter(prometheus.CounterOpts{Name: "vz_monitoring_operator_configmap_total", Help: "Tracks how many times the configMap functionality is invoked"}),
 		},
+		NamesConfigMapDeleted: {
+			metric: prometheus.NewCounter(prometheus.CounterOpts{Name: "vz_monitoring_operator_configmap_delete_total", Help: "Tracks how many configmaps are deleted"}),
+		},
 		NamesServices: {
 			metric: prometheus.NewCounter(prometheus.CounterOpts{Name: "vz_monitoring_operator_services_total", Help: "Tracks how many times the services functionality is invoked"}),
 		},
@@ -281,6 +305,9 @@ func initCounterMetricMap() map[metricName]*CounterMetric {
 		NamesRoleBindings: {
 			metric: prometheus.NewCounter(prometheus.CounterOpts{Name: "vz_monitoring_operator_rolebindings_total", Help: "Tracks how many times the rolebindings functionality is invoked"}),
 		},
+		NamesNetworkPolicies: {
+			metric: prometheus.NewCounter(prometheus.CounterOpts{Name: "vz_monitoring_operator_networkpolicies_total", Help: "Tracks how many times the networkpolicies functionality is invoked"}),
+		},
 		NamesVMOUpdate: {
 			metric: prometheus.NewCounter(prometheus.CounterOpts{Name: "vz_monitoring_operator_updates_total", Help: "Tracks how many times the update functionality is invoked"}),
 		},
@@ -296,6 +323,15 @@ func initGaugeMetricMap() map[metricName]*GaugeMetric {
 	}
 }
 
+// initLabeledGaugeMetricMap returns a map of labeled gauge metrics to be used in the data struct, add additional metrics here
+func initLabeledGaugeMetricMap() map[metricName]*LabeledGaugeMetric {
+	return map[metricName]*LabeledGaugeMetric{
+		NamesInformerSynced: {
+			metric: prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "vmo_informer_synced", Help: "Tracks whether each informer cache of the VMO controller has synced, 1 if synced and 0 otherwise"}, []string{"informer"}),
+		},
+	}
+}
+
 // initDurationMetricMap returns a map of duration metrics to be used in the data struct, add additional metrics here
 func initDurationMetricMap() map[metricName]*DurationMetric {
 	return map[metricName]*DurationMetric{}
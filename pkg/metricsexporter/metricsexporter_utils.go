@@ -4,9 +4,13 @@
 package metricsexporter
 
 import (
+	"crypto/tls"
+	"crypto/x509"
 	"fmt"
 	"net/http"
+	"os"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -29,6 +33,7 @@ func RequiredInitialization() {
 			functionMetricsMap:     initFunctionMetricsMap(),
 			simpleCounterMetricMap: initCounterMetricMap(),
 			simpleGaugeMetricMap:   initGaugeMetricMap(),
+			labeledGaugeMetricMap:  initLabeledGaugeMetricMap(),
 			durationMetricMap:      initDurationMetricMap(),
 			timestampMetricMap:     initTimestampMetricMap(),
 			errorMetricMap:         initErrorMetricMap(),
@@ -51,20 +56,69 @@ func RegisterMetrics() {
 	go MetricsExp.internalMetricsDelegate.RegisterMetricsHandlers(true) // begin the retry process
 }
 
-func StartMetricsServer() {
+// MetricsServerTLS configures serving the metrics over TLS
+type MetricsServerTLS struct {
+	// CertFile and KeyFile are the files of the server certificate and key
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the file of the CA bundle verifying client certificates, client certificates are only required if set
+	ClientCAFile string
+}
+
+var handleMetricsOnce sync.Once
+
+// StartMetricsServer starts the metrics server, the metrics are served in plaintext unless serverTLS is set
+func StartMetricsServer(serverTLS *MetricsServerTLS) {
+	handleMetrics()
 	go wait.Until(func() {
-		http.Handle("/metrics", promhttp.Handler())
-		server := &http.Server{
-			Addr:              ":9100",
-			ReadHeaderTimeout: 3 * time.Second,
+		server, err := newMetricsServer(":9100", serverTLS)
+		if err != nil {
+			zap.S().Errorf("Failed to create metrics server for VMO: %v", err)
+			return
+		}
+		if serverTLS != nil {
+			err = server.ListenAndServeTLS(serverTLS.CertFile, serverTLS.KeyFile)
+		} else {
+			err = server.ListenAndServe()
 		}
-		err := server.ListenAndServe()
 		if err != nil {
 			zap.S().Errorf("Failed to start metrics server for VMO: %v", err)
 		}
 	}, time.Second*3, wait.NeverStop)
 }
 
+// handleMetrics registers the metrics handler, a handler can only be registered once for a path
+func handleMetrics() {
+	handleMetricsOnce.Do(func() {
+		http.Handle("/metrics", promhttp.Handler())
+	})
+}
+
+// newMetricsServer creates the metrics server, requiring client certificates signed by the client CA if one is configured
+func newMetricsServer(addr string, serverTLS *MetricsServerTLS) (*http.Server, error) {
+	server := &http.Server{
+		Addr:              addr,
+		ReadHeaderTimeout: 3 * time.Second,
+	}
+	if serverTLS == nil {
+		return server, nil
+	}
+	server.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	if serverTLS.ClientCAFile != "" {
+		clientCA, err := os.ReadFile(serverTLS.ClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA file %s: %v", serverTLS.ClientCAFile, err)
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(clientCA) {
+			return nil, fmt.Errorf("no certificates found in client CA file %s", serverTLS.ClientCAFile)
+		}
+		server.TLSConfig.ClientCAs = clientCAs
+		server.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return server, nil
+}
+
 // InitializeAllMetricsArray internal function used to add all metrics from the metrics maps to the allMetrics array
 func (md *metricsDelegate) InitializeAllMetricsArray() {
 	// loop through all metrics declarations in metric maps
@@ -86,6 +140,9 @@ func (md *metricsDelegate) InitializeAllMetricsArray() {
 	for _, value := range MetricsExp.internalData.simpleGaugeMetricMap {
 		MetricsExp.internalConfig.allMetrics = append(MetricsExp.internalConfig.allMetrics, value.metric)
 	}
+	for _, value := range MetricsExp.internalData.labeledGaugeMetricMap {
+		MetricsExp.internalConfig.allMetrics = append(MetricsExp.internalConfig.allMetrics, value.metric)
+	}
 }
 
 // RegisterMetricsHandlers loops through the failedMetrics map until all metrics are registered successfully
@@ -118,6 +175,10 @@ func (md *metricsDelegate) GetGaugeMetrics(name metricName) prometheus.Gauge {
 	return MetricsExp.internalData.simpleGaugeMetricMap[name].metric
 }
 
+func (md *metricsDelegate) GetLabeledGaugeMetric(name metricName) *prometheus.GaugeVec {
+	return MetricsExp.internalData.labeledGaugeMetricMap[name].metric
+}
+
 func (md *metricsDelegate) GetTimestampMetric(name metricName) *prometheus.GaugeVec {
 	return MetricsExp.internalData.timestampMetricMap[name].metric
 }
@@ -165,6 +226,15 @@ func GetGaugeMetrics(name metricName) (*GaugeMetric, error) {
 	return returnVal, nil
 }
 
+// GetLabeledGaugeMetrics returns a labeledGaugeMetric for use if it exists, otherwise returns nil.
+func GetLabeledGaugeMetrics(name metricName) (*LabeledGaugeMetric, error) {
+	returnVal, found := MetricsExp.internalData.labeledGaugeMetricMap[name]
+	if !found {
+		return returnVal, fmt.Errorf("%v is not a valid labeled gauge metric, it is not in the labeledGaugeMetric map", name)
+	}
+	return returnVal, nil
+}
+
 // GetErrorMetrics returns a ErrorMetric for use if it exists, otherwise returns nil.
 func GetErrorMetrics(name metricName) (*ErrorMetric, error) {
 	returnVal, found := MetricsExp.internalData.errorMetricMap[name]
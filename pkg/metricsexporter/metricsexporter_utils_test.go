@@ -0,0 +1,145 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package metricsexporter
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestCertificate writes a self-signed certificate for 127.0.0.1, usable as a server certificate, a client
+// certificate and a CA, and returns the certificate and the paths of the certificate and key files
+func writeTestCertificate(t *testing.T) (tls.Certificate, string, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "127.0.0.1"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.NoError(t, err)
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "tls.crt")
+	keyFile := filepath.Join(dir, "tls.key")
+	assert.NoError(t, os.WriteFile(certFile, certPEM, 0600))
+	assert.NoError(t, os.WriteFile(keyFile, keyPEM, 0600))
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	assert.NoError(t, err)
+	cert.Leaf, err = x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert, certFile, keyFile
+}
+
+// startTestMetricsServer starts a metrics server on a random local port and returns its address
+func startTestMetricsServer(t *testing.T, serverTLS *MetricsServerTLS) string {
+	handleMetrics()
+	server, err := newMetricsServer("127.0.0.1:0", serverTLS)
+	assert.NoError(t, err)
+	listener, err := net.Listen("tcp", server.Addr)
+	assert.NoError(t, err)
+	go func() {
+		_ = server.ServeTLS(listener, serverTLS.CertFile, serverTLS.KeyFile)
+	}()
+	t.Cleanup(func() { server.Close() })
+	return listener.Addr().String()
+}
+
+// newTestClient returns an HTTP client trusting the given certificate, presenting it as client certificate if requested
+func newTestClient(cert tls.Certificate, presentClientCert bool) *http.Client {
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(cert.Leaf)
+	tlsConfig := &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	if presentClientCert {
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}, Timeout: 5 * time.Second}
+}
+
+// TestMetricsServerTLS tests serving the metrics over TLS
+// GIVEN a metrics server configured with a server certificate
+// WHEN the metrics are scraped
+// THEN TLS is negotiated and the metrics are returned, and plaintext requests are rejected
+func TestMetricsServerTLS(t *testing.T) {
+	cert, certFile, keyFile := writeTestCertificate(t)
+	addr := startTestMetricsServer(t, &MetricsServerTLS{CertFile: certFile, KeyFile: keyFile})
+
+	resp, err := newTestClient(cert, false).Get("https://" + addr + "/metrics")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NotNil(t, resp.TLS)
+	assert.GreaterOrEqual(t, resp.TLS.Version, uint16(tls.VersionTLS12))
+
+	plainResp, err := http.Get("http://" + addr + "/metrics")
+	assert.NoError(t, err)
+	defer plainResp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, plainResp.StatusCode)
+}
+
+// TestMetricsServerTLSClientAuth tests requiring client certificates to scrape the metrics
+// GIVEN a metrics server configured with a server certificate and a client CA
+// WHEN the metrics are scraped with and without a client certificate
+// THEN the metrics are only returned when a client certificate signed by the client CA is presented
+func TestMetricsServerTLSClientAuth(t *testing.T) {
+	cert, certFile, keyFile := writeTestCertificate(t)
+	addr := startTestMetricsServer(t, &MetricsServerTLS{CertFile: certFile, KeyFile: keyFile, ClientCAFile: certFile})
+
+	_, err := newTestClient(cert, false).Get("https://" + addr + "/metrics")
+	assert.Error(t, err)
+
+	resp, err := newTestClient(cert, true).Get("https://" + addr + "/metrics")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Len(t, resp.TLS.PeerCertificates, 1)
+}
+
+// TestNewMetricsServer tests creating the metrics server
+// GIVEN no TLS configuration, and a TLS configuration with an invalid client CA file
+// WHEN I call newMetricsServer
+// THEN a plaintext server is created without TLS configuration, and an error is returned for the invalid client CA
+func TestNewMetricsServer(t *testing.T) {
+	server, err := newMetricsServer(":9100", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, server.TLSConfig)
+
+	invalidCAFile := filepath.Join(t.TempDir(), "ca.crt")
+	assert.NoError(t, os.WriteFile(invalidCAFile, []byte("not a certificate"), 0600))
+	_, err = newMetricsServer(":9100", &MetricsServerTLS{ClientCAFile: invalidCAFile})
+	assert.Error(t, err)
+	_, err = newMetricsServer(":9100", &MetricsServerTLS{ClientCAFile: filepath.Join(t.TempDir(), "missing.crt")})
+	assert.Error(t, err)
+}
+
+// TestGetLabeledGaugeMetricsUnknown tests getting a labeled gauge metric which does not exist
+// GIVEN the name of a metric which is not a labeled gauge metric
+// WHEN I call GetLabeledGaugeMetrics
+// THEN an error naming the labeled gauge metric is returned
+func TestGetLabeledGaugeMetricsUnknown(t *testing.T) {
+	_, err := GetLabeledGaugeMetrics("unknown")
+	assert.EqualError(t, err, "unknown is not a valid labeled gauge metric, it is not in the labeledGaugeMetric map")
+}
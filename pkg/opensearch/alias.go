@@ -0,0 +1,185 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+// AliasesResult is the result of configuring the index aliases of a VMI
+type AliasesResult struct {
+	// Synced is true if the index aliases were synced, in which case Aliases are the aliases added by the VMO
+	Synced  bool
+	Aliases []vmcontrollerv1.IndexAliasStatus
+	Err     error
+}
+
+// ConfigureAliases adds the index aliases of the VMI to the indices matching their index pattern, and removes the
+// aliases added by the VMO which were removed from the VMI. The added aliases are identified by the aliases tracked in
+// the VMI status. The aliases are added on every reconcile, so that they also point to the indices created since.
+// The returned channel should be read for exactly one response, which has the aliases to track in the VMI status.
+func (o *OSClient) ConfigureAliases(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan AliasesResult {
+	ch := make(chan AliasesResult)
+	// the VMI status is updated with the result, so the goroutine works on a copy
+	aliases := append([]vmcontrollerv1.IndexAlias{}, vmi.Spec.Opensearch.Aliases...)
+	trackedAliases := append([]vmcontrollerv1.IndexAliasStatus{}, vmi.Status.Aliases...)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- AliasesResult{}
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- AliasesResult{}
+			return
+		}
+
+		statuses, err := o.syncAliases(resources.GetOpenSearchHTTPEndpoint(vmi), aliases, trackedAliases)
+		ch <- AliasesResult{Synced: true, Aliases: statuses, Err: err}
+	}()
+
+	return ch
+}
+
+// PendingAliases returns the aliases to track in the VMI status before the aliases are synced, which are the tracked
+// aliases and the expected aliases. The VMI status must track them before OpenSearch is changed, so that an alias
+// added by a reconcile whose VMI status update failed is still removed once it is no longer expected.
+func PendingAliases(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) []vmcontrollerv1.IndexAliasStatus {
+	pending := map[vmcontrollerv1.IndexAliasStatus]bool{}
+	for _, status := range vmi.Status.Aliases {
+		pending[status] = true
+	}
+	for _, alias := range vmi.Spec.Opensearch.Aliases {
+		pending[vmcontrollerv1.IndexAliasStatus{Name: alias.Name, Index: alias.Index}] = true
+	}
+	return toAliasStatuses(pending)
+}
+
+// syncAliases adds the expected aliases, and removes the tracked aliases which are no longer expected. An alias is
+// identified by its name and index pattern, so an alias whose index pattern changed is removed from the indices of
+// its former index pattern. The aliases tracked once synced are returned, also if an error occurred, so the aliases
+// added before the error are still removed once they are no longer expected.
+// Data streams cannot have aliases, so an alias whose index pattern matches data streams is skipped and an error is
+// returned once the other aliases are synced.
+func (o *OSClient) syncAliases(opensearchEndpoint string, aliases []vmcontrollerv1.IndexAlias, trackedAliases []vmcontrollerv1.IndexAliasStatus) ([]vmcontrollerv1.IndexAliasStatus, error) {
+	tracked := map[vmcontrollerv1.IndexAliasStatus]bool{}
+	for _, status := range trackedAliases {
+		tracked[status] = true
+	}
+
+	expectedAliasMap := map[vmcontrollerv1.IndexAliasStatus]bool{}
+	var skippedAliases []string
+	for _, alias := range aliases {
+		action, err := toAddAliasAction(alias)
+		if err != nil {
+			return toAliasStatuses(tracked), err
+		}
+		status := vmcontrollerv1.IndexAliasStatus{Name: alias.Name, Index: alias.Index}
+		// OpenSearch rejects the actions of an index pattern matching data streams, so a skipped alias is neither
+		// added nor removed, and is no longer tracked
+		dataStreamsExist, err := o.dataStreamsExist(opensearchEndpoint, alias.Index)
+		if err != nil {
+			return toAliasStatuses(tracked), err
+		}
+		if dataStreamsExist {
+			delete(tracked, status)
+			skippedAliases = append(skippedAliases, alias.Name)
+			continue
+		}
+		if err := o.updateAliases(opensearchEndpoint, action, "adding", alias.Name); err != nil {
+			return toAliasStatuses(tracked), err
+		}
+		expectedAliasMap[status] = true
+		tracked[status] = true
+	}
+
+	for status := range tracked {
+		if expectedAliasMap[status] {
+			continue
+		}
+		action := map[string]interface{}{
+			"remove": map[string]interface{}{"index": status.Index, "alias": status.Name},
+		}
+		if err := o.updateAliases(opensearchEndpoint, action, "removing", status.Name); err != nil {
+			return toAliasStatuses(tracked), err
+		}
+		delete(tracked, status)
+	}
+	if len(skippedAliases) > 0 {
+		return toAliasStatuses(tracked), fmt.Errorf("index aliases %s were not added, their index patterns match data streams, which cannot have aliases", strings.Join(skippedAliases, ", "))
+	}
+	return toAliasStatuses(tracked), nil
+}
+
+// updateAliases runs an action of the _aliases API. OpenSearch responds with not found if no index matches the index
+// pattern of the action, or if the alias to remove does not exist, in which case there is nothing to update.
+func (o *OSClient) updateAliases(opensearchEndpoint string, action map[string]interface{}, verb, name string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"actions": []interface{}{action},
+	})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/_aliases", opensearchEndpoint)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when %s alias %s", resp.StatusCode, verb, name)
+	}
+	return nil
+}
+
+// toAddAliasAction creates the add action of the _aliases API of a VMI index alias
+func toAddAliasAction(alias vmcontrollerv1.IndexAlias) (map[string]interface{}, error) {
+	if alias.Name == "" || alias.Index == "" {
+		return nil, fmt.Errorf("index alias name and index must be specified")
+	}
+	add := map[string]interface{}{
+		"index": alias.Index,
+		"alias": alias.Name,
+	}
+	if alias.Filter != "" {
+		var filter map[string]interface{}
+		if err := json.Unmarshal([]byte(alias.Filter), &filter); err != nil || filter == nil {
+			return nil, fmt.Errorf("filter of index alias %s is not a JSON object", alias.Name)
+		}
+		add["filter"] = filter
+	}
+	return map[string]interface{}{"add": add}, nil
+}
+
+// toAliasStatuses returns the tracked aliases sorted by name and index pattern, or nil if no alias is tracked
+func toAliasStatuses(tracked map[vmcontrollerv1.IndexAliasStatus]bool) []vmcontrollerv1.IndexAliasStatus {
+	if len(tracked) == 0 {
+		return nil
+	}
+	var statuses []vmcontrollerv1.IndexAliasStatus
+	for status := range tracked {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Name != statuses[j].Name {
+			return statuses[i].Name < statuses[j].Name
+		}
+		return statuses[i].Index < statuses[j].Index
+	})
+	return statuses
+}
@@ -0,0 +1,203 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// createAliasesOSClient creates an OSClient responding with the given status code to the _aliases requests,
+// recording the actions it receives. The given index patterns match data streams
+func createAliasesOSClient(t *testing.T, statusCode int, actions *[]map[string]interface{}, dataStreamPatterns ...string) *OSClient {
+	return createMockOSClient(t, nil, func(method, path string, body []byte) (int, string) {
+		if method == "GET" {
+			for _, pattern := range dataStreamPatterns {
+				if path == "/_data_stream/"+pattern {
+					return http.StatusOK, `{"data_streams": [{"name": "` + strings.TrimSuffix(pattern, "*") + `"}]}`
+				}
+			}
+			assert.True(t, strings.HasPrefix(path, "/_data_stream/"))
+			return http.StatusNotFound, `{}`
+		}
+		assert.Equal(t, "POST", method)
+		assert.Equal(t, "/_aliases", path)
+		var aliases struct {
+			Actions []map[string]interface{} `json:"actions"`
+		}
+		assert.NoError(t, json.Unmarshal(body, &aliases))
+		*actions = append(*actions, aliases.Actions...)
+		return statusCode, `{"acknowledged": true}`
+	})
+}
+
+// TestConfigureAliasesDisabled Tests that index aliases are not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureAliases
+// THEN OpenSearch is not called and the aliases are not synced
+func TestConfigureAliasesDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	result := <-o.ConfigureAliases(&vmcontrollerv1.VerrazzanoMonitoringInstance{})
+	assert.NoError(t, result.Err)
+	assert.False(t, result.Synced)
+}
+
+// TestConfigureAliases Tests adding the index aliases of a VMI
+// GIVEN a VMI with OpenSearch ready and an alias with a filter
+// WHEN I call ConfigureAliases
+// THEN the alias is added with its filter through the _aliases API and tracked
+func TestConfigureAliases(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	o := createReadyOSClient(http.StatusOK, &requests, &bodies)
+	vmi := testvmo.DeepCopy()
+	vmi.Spec.Opensearch.Aliases = []vmcontrollerv1.IndexAlias{
+		{Name: "tenant-a", Index: "tenant-a-archive-*", Filter: `{"term": {"kubernetes.namespace_name": "tenant-a"}}`},
+	}
+
+	result := <-o.ConfigureAliases(vmi)
+	assert.NoError(t, result.Err)
+	assert.True(t, result.Synced)
+	assert.Equal(t, []vmcontrollerv1.IndexAliasStatus{{Name: "tenant-a", Index: "tenant-a-archive-*"}}, result.Aliases)
+	assert.Len(t, requests, 2)
+	assert.Equal(t, "GET", requests[0].Method)
+	assert.Equal(t, "/_data_stream/tenant-a-archive-*", requests[0].URL.Path)
+	assert.Equal(t, "POST", requests[1].Method)
+	assert.Equal(t, "/_aliases", requests[1].URL.Path)
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(bodies[1]), &body))
+	assert.Equal(t, map[string]interface{}{
+		"actions": []interface{}{
+			map[string]interface{}{
+				"add": map[string]interface{}{
+					"index":  "tenant-a-archive-*",
+					"alias":  "tenant-a",
+					"filter": map[string]interface{}{"term": map[string]interface{}{"kubernetes.namespace_name": "tenant-a"}},
+				},
+			},
+		},
+	}, body)
+}
+
+// TestSyncAliases Tests syncing the index aliases of a VMI
+// GIVEN a new alias, a tracked alias, a tracked alias whose index pattern changed and a tracked alias removed from the VMI
+// WHEN I call syncAliases
+// THEN the expected aliases are added, the alias is removed from its former index pattern, the removed alias is
+// removed, and the expected aliases are tracked
+func TestSyncAliases(t *testing.T) {
+	var actions []map[string]interface{}
+	o := createAliasesOSClient(t, http.StatusOK, &actions)
+
+	statuses, err := o.syncAliases("http://localhost:9200", []vmcontrollerv1.IndexAlias{
+		{Name: "new", Index: "new-*"},
+		{Name: "tracked", Index: "tracked-*"},
+		{Name: "moved", Index: "moved-v2-*"},
+	}, []vmcontrollerv1.IndexAliasStatus{
+		{Name: "tracked", Index: "tracked-*"},
+		{Name: "moved", Index: "moved-v1-*"},
+		{Name: "removed", Index: "removed-*"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"add": map[string]interface{}{"index": "new-*", "alias": "new"}},
+		{"add": map[string]interface{}{"index": "tracked-*", "alias": "tracked"}},
+		{"add": map[string]interface{}{"index": "moved-v2-*", "alias": "moved"}},
+	}, actions[:3])
+	assert.ElementsMatch(t, []map[string]interface{}{
+		{"remove": map[string]interface{}{"index": "moved-v1-*", "alias": "moved"}},
+		{"remove": map[string]interface{}{"index": "removed-*", "alias": "removed"}},
+	}, actions[3:])
+	assert.Equal(t, []vmcontrollerv1.IndexAliasStatus{
+		{Name: "moved", Index: "moved-v2-*"},
+		{Name: "new", Index: "new-*"},
+		{Name: "tracked", Index: "tracked-*"},
+	}, statuses)
+}
+
+// TestSyncAliasesNoMatchingIndex Tests syncing index aliases whose index pattern matches no index
+// GIVEN a new alias and a tracked alias removed from the VMI, and OpenSearch responding with not found
+// WHEN I call syncAliases
+// THEN no error is returned, the new alias is tracked and the removed alias is no longer tracked
+func TestSyncAliasesNoMatchingIndex(t *testing.T) {
+	var actions []map[string]interface{}
+	o := createAliasesOSClient(t, http.StatusNotFound, &actions)
+
+	statuses, err := o.syncAliases("http://localhost:9200", []vmcontrollerv1.IndexAlias{{Name: "new", Index: "new-*"}},
+		[]vmcontrollerv1.IndexAliasStatus{{Name: "removed", Index: "removed-*"}})
+	assert.NoError(t, err)
+	assert.Len(t, actions, 2)
+	assert.Equal(t, []vmcontrollerv1.IndexAliasStatus{{Name: "new", Index: "new-*"}}, statuses)
+}
+
+// TestSyncAliasesFailed Tests syncing the index aliases of a VMI when OpenSearch rejects an action or an alias is invalid
+// GIVEN a new alias and a tracked alias removed from the VMI, with OpenSearch responding with an error, then an alias
+// with a filter which is not a JSON object
+// WHEN I call syncAliases
+// THEN an error is returned and the tracked aliases are unchanged
+func TestSyncAliasesFailed(t *testing.T) {
+	var actions []map[string]interface{}
+	o := createAliasesOSClient(t, http.StatusBadRequest, &actions)
+	tracked := []vmcontrollerv1.IndexAliasStatus{{Name: "removed", Index: "removed-*"}}
+
+	statuses, err := o.syncAliases("http://localhost:9200", []vmcontrollerv1.IndexAlias{{Name: "new", Index: "new-*"}}, tracked)
+	assert.ErrorContains(t, err, "got status code 400 when adding alias new")
+	assert.Equal(t, tracked, statuses)
+
+	statuses, err = o.syncAliases("http://localhost:9200", []vmcontrollerv1.IndexAlias{{Name: "filtered", Index: "new-*", Filter: "[]"}}, tracked)
+	assert.ErrorContains(t, err, "filter of index alias filtered is not a JSON object")
+	assert.Equal(t, tracked, statuses)
+	assert.Len(t, actions, 1)
+}
+
+// TestSyncAliasesDataStreams Tests syncing an index alias whose index pattern matches data streams
+// GIVEN an alias of an index pattern matching data streams, which was tracked, and an alias of indices
+// WHEN I call syncAliases
+// THEN the alias of the data streams is neither added nor removed and no longer tracked, the alias of the indices is
+// added, and an error names the skipped alias
+func TestSyncAliasesDataStreams(t *testing.T) {
+	var actions []map[string]interface{}
+	o := createAliasesOSClient(t, http.StatusOK, &actions, "verrazzano-application-tenant-a*")
+
+	statuses, err := o.syncAliases("http://localhost:9200", []vmcontrollerv1.IndexAlias{
+		{Name: "tenant-a", Index: "verrazzano-application-tenant-a*"},
+		{Name: "archive", Index: "tenant-a-archive-*"},
+	}, []vmcontrollerv1.IndexAliasStatus{
+		{Name: "tenant-a", Index: "verrazzano-application-tenant-a*"},
+	})
+	assert.EqualError(t, err, "index aliases tenant-a were not added, their index patterns match data streams, which cannot have aliases")
+	assert.Equal(t, []map[string]interface{}{
+		{"add": map[string]interface{}{"index": "tenant-a-archive-*", "alias": "archive"}},
+	}, actions)
+	assert.Equal(t, []vmcontrollerv1.IndexAliasStatus{{Name: "archive", Index: "tenant-a-archive-*"}}, statuses)
+}
+
+// TestPendingAliases Tests the aliases to track before the aliases are synced
+// GIVEN a VMI with a tracked alias removed from its spec, a tracked alias and a new alias
+// WHEN I call PendingAliases
+// THEN the tracked aliases and the new alias are returned
+func TestPendingAliases(t *testing.T) {
+	vmi := testvmo.DeepCopy()
+	vmi.Spec.Opensearch.Aliases = []vmcontrollerv1.IndexAlias{
+		{Name: "new", Index: "new-*"},
+		{Name: "tracked", Index: "tracked-*"},
+	}
+	vmi.Status.Aliases = []vmcontrollerv1.IndexAliasStatus{
+		{Name: "removed", Index: "removed-*"},
+		{Name: "tracked", Index: "tracked-*"},
+	}
+	assert.Equal(t, []vmcontrollerv1.IndexAliasStatus{
+		{Name: "new", Index: "new-*"},
+		{Name: "removed", Index: "removed-*"},
+		{Name: "tracked", Index: "tracked-*"},
+	}, PendingAliases(vmi))
+}
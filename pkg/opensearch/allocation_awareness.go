@@ -0,0 +1,116 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	"strings"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+const (
+	awarenessAttributesSetting    = "cluster.routing.allocation.awareness.attributes"
+	awarenessForceSettingPrefix   = "cluster.routing.allocation.awareness.force."
+	awarenessForceValuesSuffix    = ".values"
+	awarenessSettingValuesDivider = ","
+)
+
+// ConfigureAllocationAwareness sets the persistent shard allocation awareness cluster settings of the VMI, and resets
+// the awareness settings which are not set in the VMI to the OpenSearch defaults. The settings are only updated if
+// they differ from the persistent cluster settings.
+// The returned channel should be read for exactly one response, which tells whether allocation awareness was configured.
+func (o *OSClient) ConfigureAllocationAwareness(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		ch <- o.syncAllocationAwareness(resources.GetOpenSearchHTTPEndpoint(vmi), vmi.Spec.Opensearch.AllocationAwareness)
+	}()
+
+	return ch
+}
+
+// syncAllocationAwareness puts the allocation awareness settings which differ from the persistent cluster settings,
+// the awareness settings which are no longer expected are reset to their defaults
+func (o *OSClient) syncAllocationAwareness(opensearchEndpoint string, awareness *vmcontrollerv1.OpenSearchAllocationAwareness) error {
+	expected, err := toAllocationAwarenessSettings(awareness)
+	if err != nil {
+		return err
+	}
+	settings, err := o.getClusterSettings(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	changed := map[string]interface{}{}
+	for name := range settings.Persistent {
+		if !isAllocationAwarenessSetting(name) {
+			continue
+		}
+		if _, isExpected := expected[name]; !isExpected {
+			changed[name] = nil
+		}
+	}
+	for name, value := range expected {
+		current, isSet := settings.Persistent[name]
+		if !isSet || toAllocationAwarenessSettingValue(current) != value {
+			changed[name] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return o.putClusterSettings(opensearchEndpoint, changed, true)
+}
+
+// toAllocationAwarenessSettings returns the flat cluster settings of the allocation awareness of the VMI,
+// with the attributes and the forced values as comma separated lists
+func toAllocationAwarenessSettings(awareness *vmcontrollerv1.OpenSearchAllocationAwareness) (map[string]string, error) {
+	settings := map[string]string{}
+	if awareness == nil || len(awareness.Attributes) == 0 {
+		return settings, nil
+	}
+	var names []string
+	for _, attribute := range awareness.Attributes {
+		if attribute.Name == "" {
+			return nil, fmt.Errorf("allocation awareness attribute name must be specified")
+		}
+		names = append(names, attribute.Name)
+		if len(attribute.ForcedValues) > 0 {
+			settings[awarenessForceSettingPrefix+attribute.Name+awarenessForceValuesSuffix] = strings.Join(attribute.ForcedValues, awarenessSettingValuesDivider)
+		}
+	}
+	settings[awarenessAttributesSetting] = strings.Join(names, awarenessSettingValuesDivider)
+	return settings, nil
+}
+
+// isAllocationAwarenessSetting returns true if the flat cluster setting is an allocation awareness setting
+func isAllocationAwarenessSetting(name string) bool {
+	return name == awarenessAttributesSetting ||
+		(strings.HasPrefix(name, awarenessForceSettingPrefix) && strings.HasSuffix(name, awarenessForceValuesSuffix))
+}
+
+// toAllocationAwarenessSettingValue returns a list setting value as a comma separated list, list settings are
+// returned as arrays when they were put as arrays
+func toAllocationAwarenessSettingValue(value interface{}) string {
+	values, ok := value.([]interface{})
+	if !ok {
+		return fmt.Sprintf("%v", value)
+	}
+	var items []string
+	for _, item := range values {
+		items = append(items, fmt.Sprintf("%v", item))
+	}
+	return strings.Join(items, awarenessSettingValuesDivider)
+}
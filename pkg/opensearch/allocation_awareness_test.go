@@ -0,0 +1,89 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// TestConfigureAllocationAwarenessDisabled Tests that allocation awareness is not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureAllocationAwareness
+// THEN OpenSearch is not called and no error is returned
+func TestConfigureAllocationAwarenessDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-o.ConfigureAllocationAwareness(&vmcontrollerv1.VerrazzanoMonitoringInstance{}))
+}
+
+// TestSyncAllocationAwareness Tests syncing the allocation awareness settings of a VMI
+// GIVEN allocation awareness attributes of a VMI, and a cluster with a changed attributes setting, an unchanged and
+// a removed forced values setting
+// WHEN I call syncAllocationAwareness
+// THEN only the changed and the new settings are put, and the removed setting is reset
+func TestSyncAllocationAwareness(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{
+  "cluster.routing.allocation.awareness.attributes": "zone",
+  "cluster.routing.allocation.awareness.force.zone.values": ["zone1", "zone2"],
+  "cluster.routing.allocation.awareness.force.rack.values": "rack1,rack2",
+  "cluster.routing.allocation.enable": "all"
+}`, &updates)
+
+	err := o.syncAllocationAwareness("http://localhost:9200", &vmcontrollerv1.OpenSearchAllocationAwareness{
+		Attributes: []vmcontrollerv1.OpenSearchAwarenessAttribute{
+			{Name: "zone", ForcedValues: []string{"zone1", "zone2"}},
+			{Name: "availability_domain", ForcedValues: []string{"AD-1", "AD-2", "AD-3"}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []clusterSettingsUpdate{{"persistent": {
+		"cluster.routing.allocation.awareness.attributes":                       "zone,availability_domain",
+		"cluster.routing.allocation.awareness.force.availability_domain.values": "AD-1,AD-2,AD-3",
+		"cluster.routing.allocation.awareness.force.rack.values":                nil,
+	}}}, updates)
+}
+
+// TestSyncAllocationAwarenessUnchanged Tests syncing unchanged allocation awareness settings of a VMI
+// GIVEN allocation awareness attributes of a VMI which are the persistent cluster settings, and a VMI without
+// allocation awareness for a cluster without allocation awareness settings
+// WHEN I call syncAllocationAwareness
+// THEN the cluster settings are not updated
+func TestSyncAllocationAwarenessUnchanged(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{"cluster.routing.allocation.awareness.attributes": "zone"}`, &updates)
+	err := o.syncAllocationAwareness("http://localhost:9200", &vmcontrollerv1.OpenSearchAllocationAwareness{
+		Attributes: []vmcontrollerv1.OpenSearchAwarenessAttribute{{Name: "zone"}},
+	})
+	assert.NoError(t, err)
+
+	o = createClusterSettingsOSClient(t, `{}`, &updates)
+	assert.NoError(t, o.syncAllocationAwareness("http://localhost:9200", nil))
+	assert.Empty(t, updates)
+}
+
+// TestSyncAllocationAwarenessRemoved Tests syncing the allocation awareness removed from a VMI
+// GIVEN a VMI without allocation awareness, and a cluster with allocation awareness settings
+// WHEN I call syncAllocationAwareness
+// THEN the allocation awareness settings are reset to the OpenSearch defaults
+func TestSyncAllocationAwarenessRemoved(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{
+  "cluster.routing.allocation.awareness.attributes": "zone",
+  "cluster.routing.allocation.awareness.force.zone.values": "zone1,zone2"
+}`, &updates)
+
+	assert.NoError(t, o.syncAllocationAwareness("http://localhost:9200", nil))
+	assert.Equal(t, []clusterSettingsUpdate{{"persistent": {
+		"cluster.routing.allocation.awareness.attributes":        nil,
+		"cluster.routing.allocation.awareness.force.zone.values": nil,
+	}}}, updates)
+}
@@ -0,0 +1,73 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	"regexp"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+const autoCreateIndexSetting = "action.auto_create_index"
+
+// autoCreateIndexPattern matches true, false, or a comma separated list of index patterns optionally prefixed with + or -
+var autoCreateIndexPattern = regexp.MustCompile(`^[+-]?[^\s,]+(,[+-]?[^\s,]+)*$`)
+
+// ConfigureAutoCreateIndex sets the persistent action.auto_create_index cluster setting of the VMI, and resets the
+// setting to the OpenSearch default when it is removed from the VMI. The setting is only updated if it differs
+// from the persistent cluster setting.
+// The returned channel should be read for exactly one response, which tells whether the setting was configured.
+func (o *OSClient) ConfigureAutoCreateIndex(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		ch <- o.syncAutoCreateIndex(resources.GetOpenSearchHTTPEndpoint(vmi), vmi.Spec.Opensearch.AutoCreateIndex)
+	}()
+
+	return ch
+}
+
+// syncAutoCreateIndex puts the action.auto_create_index setting if it differs from the persistent cluster setting,
+// an empty value resets the setting to its default
+func (o *OSClient) syncAutoCreateIndex(opensearchEndpoint, autoCreateIndex string) error {
+	if err := validateAutoCreateIndex(autoCreateIndex); err != nil {
+		return err
+	}
+	settings, err := o.getClusterSettings(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	current, isSet := settings.Persistent[autoCreateIndexSetting]
+	if autoCreateIndex == "" {
+		if !isSet {
+			return nil
+		}
+		return o.putClusterSettings(opensearchEndpoint, map[string]interface{}{autoCreateIndexSetting: nil}, true)
+	}
+	if isSet && fmt.Sprintf("%v", current) == autoCreateIndex {
+		return nil
+	}
+	return o.putClusterSettings(opensearchEndpoint, map[string]interface{}{autoCreateIndexSetting: autoCreateIndex}, true)
+}
+
+// validateAutoCreateIndex returns an error if the value is not empty, true, false, or a comma separated list of
+// index patterns
+func validateAutoCreateIndex(autoCreateIndex string) error {
+	if autoCreateIndex == "" || autoCreateIndexPattern.MatchString(autoCreateIndex) {
+		return nil
+	}
+	return fmt.Errorf("invalid autoCreateIndex %q, must be true, false or a comma separated list of index patterns, e.g. +verrazzano-*,-*", autoCreateIndex)
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// TestConfigureAutoCreateIndexDisabled Tests that index auto creation is not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureAutoCreateIndex
+// THEN OpenSearch is not called and no error is returned
+func TestConfigureAutoCreateIndexDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-o.ConfigureAutoCreateIndex(&vmcontrollerv1.VerrazzanoMonitoringInstance{}))
+}
+
+// TestSyncAutoCreateIndex Tests syncing the action.auto_create_index setting of a VMI
+// GIVEN an auto create index value which differs from the persistent cluster setting, and a cluster without the setting
+// WHEN I call syncAutoCreateIndex
+// THEN the setting is put
+func TestSyncAutoCreateIndex(t *testing.T) {
+	for _, persistentSettings := range []string{`{"action.auto_create_index": "true"}`, `{}`} {
+		var updates []clusterSettingsUpdate
+		o := createClusterSettingsOSClient(t, persistentSettings, &updates)
+		assert.NoError(t, o.syncAutoCreateIndex("http://localhost:9200", "+verrazzano-*,-*"))
+		assert.Equal(t, []clusterSettingsUpdate{{"persistent": {autoCreateIndexSetting: "+verrazzano-*,-*"}}}, updates)
+	}
+}
+
+// TestSyncAutoCreateIndexUnchanged Tests syncing an unchanged action.auto_create_index setting of a VMI
+// GIVEN an auto create index value which is the persistent cluster setting, and a VMI without the setting for a
+// cluster without the setting
+// WHEN I call syncAutoCreateIndex
+// THEN the setting is not updated
+func TestSyncAutoCreateIndexUnchanged(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{"action.auto_create_index": "false"}`, &updates)
+	assert.NoError(t, o.syncAutoCreateIndex("http://localhost:9200", "false"))
+
+	o = createClusterSettingsOSClient(t, `{}`, &updates)
+	assert.NoError(t, o.syncAutoCreateIndex("http://localhost:9200", ""))
+	assert.Empty(t, updates)
+}
+
+// TestSyncAutoCreateIndexRemoved Tests syncing the action.auto_create_index setting removed from a VMI
+// GIVEN a VMI without the setting, and a cluster with the setting
+// WHEN I call syncAutoCreateIndex
+// THEN the setting is reset to the OpenSearch default
+func TestSyncAutoCreateIndexRemoved(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{"action.auto_create_index": "false"}`, &updates)
+	assert.NoError(t, o.syncAutoCreateIndex("http://localhost:9200", ""))
+	assert.Equal(t, []clusterSettingsUpdate{{"persistent": {autoCreateIndexSetting: nil}}}, updates)
+}
+
+// TestSyncAutoCreateIndexInvalid Tests syncing an invalid action.auto_create_index setting
+// GIVEN invalid auto create index values
+// WHEN I call syncAutoCreateIndex
+// THEN an error is returned and OpenSearch is not called
+func TestSyncAutoCreateIndexInvalid(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	for _, value := range []string{"logs-*, metrics-*", "logs-*,,metrics-*", ",logs-*", "logs-*,"} {
+		assert.Error(t, o.syncAutoCreateIndex("http://localhost:9200", value), value)
+	}
+}
@@ -0,0 +1,378 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+type (
+	// ComponentTemplate is the document of an OpenSearch component template
+	ComponentTemplate struct {
+		Template map[string]interface{} `json:"template"`
+		Meta     ComponentTemplateMeta  `json:"_meta"`
+	}
+
+	// ComponentTemplateMeta is the metadata of a component template, used to identify the VMI managed
+	// component templates and whether they have changed
+	ComponentTemplateMeta struct {
+		ManagedBy string `json:"managed_by,omitempty"`
+		Checksum  string `json:"checksum,omitempty"`
+	}
+
+	// ComponentTemplates is the response of OpenSearch listing the component templates
+	ComponentTemplates struct {
+		ComponentTemplates []NamedComponentTemplate `json:"component_templates"`
+	}
+
+	NamedComponentTemplate struct {
+		Name              string            `json:"name"`
+		ComponentTemplate ComponentTemplate `json:"component_template"`
+	}
+
+	// composableIndexTemplates is the response of OpenSearch listing the composable index templates. The index
+	// templates are kept as is, so they are put back unchanged but for the component templates they are composed of.
+	composableIndexTemplates struct {
+		IndexTemplates []struct {
+			Name          string                 `json:"name"`
+			IndexTemplate map[string]interface{} `json:"index_template"`
+		} `json:"index_templates"`
+	}
+)
+
+const (
+	// Identifies component templates as being managed by the VMI
+	vmiManagedComponentTemplate = "__vmi-managed__"
+	// Identifies the component templates holding the index settings of the VMI, e.g. the index defaults. They are not
+	// component templates of the VMI spec, so they are not deleted with the component templates removed from the VMI.
+	vmiIndexSettingsComponentTemplate = "__vmi-index-settings__"
+
+	composedOfKey = "composed_of"
+)
+
+// indexTemplatesMutex serializes the updates of the composable index templates, which are read and put back whole when
+// the component templates holding the index settings of the VMI are composed into them concurrently
+var indexTemplatesMutex sync.Mutex
+
+// ConfigureComponentTemplates creates or updates the component templates of the VMI, and deletes the VMI managed
+// component templates which were removed from the VMI. The component templates can be referenced by the composable
+// index templates, to share settings, mappings and aliases between them.
+// The returned channel should be read for exactly one response, which tells whether the component templates were configured.
+func (o *OSClient) ConfigureComponentTemplates(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		ch <- o.syncComponentTemplates(resources.GetOpenSearchHTTPEndpoint(vmi), vmi.Spec.Opensearch.ComponentTemplates)
+	}()
+
+	return ch
+}
+
+// syncComponentTemplates puts the component templates which do not exist or have changed, and deletes the VMI managed
+// component templates which are no longer expected
+func (o *OSClient) syncComponentTemplates(opensearchEndpoint string, templates []vmcontrollerv1.ComponentTemplate) error {
+	existingTemplates, err := o.getAllComponentTemplates(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+
+	expectedTemplateMap := map[string]bool{}
+	for _, template := range templates {
+		expectedTemplateMap[template.Name] = true
+		componentTemplate, err := toComponentTemplate(template)
+		if err != nil {
+			return err
+		}
+		// OpenSearch normalizes the settings of the template, so the checksum of the VMI template tells whether it changed
+		if existingTemplate, ok := existingTemplates[template.Name]; ok && existingTemplate.Meta == componentTemplate.Meta {
+			continue
+		}
+		if err := o.putComponentTemplate(opensearchEndpoint, template.Name, componentTemplate); err != nil {
+			return err
+		}
+	}
+
+	// A component template is eligible for deletion if it is marked as VMI managed, but the VMI no longer
+	// has a component template entry for it
+	for name, template := range existingTemplates {
+		if template.Meta.ManagedBy == vmiManagedComponentTemplate && !expectedTemplateMap[name] {
+			if err := o.deleteComponentTemplate(opensearchEndpoint, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getAllComponentTemplates returns the component templates of the cluster, keyed by name
+func (o *OSClient) getAllComponentTemplates(opensearchEndpoint string) (map[string]ComponentTemplate, error) {
+	url := fmt.Sprintf("%s/_component_template", opensearchEndpoint)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	templates := map[string]ComponentTemplate{}
+	// OpenSearch responds with not found if there are no component templates
+	if resp.StatusCode == http.StatusNotFound {
+		return templates, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d when querying component templates", resp.StatusCode)
+	}
+	componentTemplates := &ComponentTemplates{}
+	if err := json.NewDecoder(resp.Body).Decode(componentTemplates); err != nil {
+		return nil, err
+	}
+	for _, template := range componentTemplates.ComponentTemplates {
+		templates[template.Name] = template.ComponentTemplate
+	}
+	return templates, nil
+}
+
+func (o *OSClient) putComponentTemplate(opensearchEndpoint, name string, template *ComponentTemplate) error {
+	body, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/_component_template/%s", opensearchEndpoint, name)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when putting component template %s", resp.StatusCode, name)
+	}
+	return nil
+}
+
+func (o *OSClient) deleteComponentTemplate(opensearchEndpoint, name string) error {
+	url := fmt.Sprintf("%s/_component_template/%s", opensearchEndpoint, name)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// OpenSearch rejects the deletion of a component template which is still referenced by an index template
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting component template %s", resp.StatusCode, name)
+	}
+	return nil
+}
+
+// toComponentTemplate creates the component template document of a VMI component template, marked as VMI managed
+func toComponentTemplate(template vmcontrollerv1.ComponentTemplate) (*ComponentTemplate, error) {
+	if template.Name == "" {
+		return nil, fmt.Errorf("component template name must be specified")
+	}
+	var t map[string]interface{}
+	if err := json.Unmarshal([]byte(template.Template), &t); err != nil {
+		return nil, fmt.Errorf("template of component template %s is not a JSON object: %v", template.Name, err)
+	}
+	checksum := sha256.Sum256([]byte(template.Template))
+	return &ComponentTemplate{
+		Template: t,
+		Meta: ComponentTemplateMeta{
+			ManagedBy: vmiManagedComponentTemplate,
+			Checksum:  hex.EncodeToString(checksum[:]),
+		},
+	}, nil
+}
+
+// newIndexSettingsComponentTemplate creates the component template document holding the given index settings of the VMI
+func newIndexSettingsComponentTemplate(settings map[string]interface{}) (*ComponentTemplate, error) {
+	// the keys of maps are marshalled in order, so the checksum only changes with the settings
+	body, err := json.Marshal(settings)
+	if err != nil {
+		return nil, err
+	}
+	checksum := sha256.Sum256(body)
+	return &ComponentTemplate{
+		Template: map[string]interface{}{"settings": settings},
+		Meta: ComponentTemplateMeta{
+			ManagedBy: vmiIndexSettingsComponentTemplate,
+			Checksum:  hex.EncodeToString(checksum[:]),
+		},
+	}, nil
+}
+
+// syncIndexSettingsComponentTemplate puts the component template holding index settings of the VMI if it does not exist
+// or has changed, and composes it into the composable index templates matching the given index patterns. Legacy index
+// templates are ignored for the indices matched by a composable index template, e.g. the backing indices of data
+// streams, so the index settings only apply to them through the composable index template. The component template is
+// composed first, so that the settings of the index template and of its other component templates override it.
+// If the component template is nil, it is removed from the composable index templates and deleted if it exists.
+func (o *OSClient) syncIndexSettingsComponentTemplate(opensearchEndpoint, name string, template *ComponentTemplate, indexPatterns []string) error {
+	existingTemplates, err := o.getAllComponentTemplates(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	existingTemplate, exists := existingTemplates[name]
+	if template != nil && (!exists || existingTemplate.Meta != template.Meta) {
+		// the component template must exist before an index template is composed of it
+		if err := o.putComponentTemplate(opensearchEndpoint, name, template); err != nil {
+			return err
+		}
+	}
+	if template == nil && !exists {
+		return nil
+	}
+
+	if err := o.composeIndexTemplates(opensearchEndpoint, name, template != nil, indexPatterns); err != nil {
+		return err
+	}
+	if template == nil {
+		// OpenSearch rejects the deletion of a component template which is still referenced by an index template
+		return o.deleteComponentTemplate(opensearchEndpoint, name)
+	}
+	return nil
+}
+
+// composeIndexTemplates adds the component template to the composable index templates matching the index patterns, and
+// removes it from the other composable index templates. Only the index templates whose composition changes are put.
+// The index templates of the system indices, e.g. the one put by SetAutoExpandIndices, are left alone.
+func (o *OSClient) composeIndexTemplates(opensearchEndpoint, name string, compose bool, indexPatterns []string) error {
+	indexTemplatesMutex.Lock()
+	defer indexTemplatesMutex.Unlock()
+	templates, err := o.getAllComposableIndexTemplates(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	for _, template := range templates.IndexTemplates {
+		var patterns []string
+		if templatePatterns, ok := template.IndexTemplate["index_patterns"].([]interface{}); ok {
+			for _, pattern := range templatePatterns {
+				if p, ok := pattern.(string); ok {
+					patterns = append(patterns, p)
+				}
+			}
+		}
+		if isSystemIndexTemplate(patterns) {
+			continue
+		}
+		expected := false
+		for _, pattern := range patterns {
+			if compose && templateMatches(indexPatterns, pattern) {
+				expected = true
+				break
+			}
+		}
+		var composedOf []interface{}
+		composed := false
+		if components, ok := template.IndexTemplate[composedOfKey].([]interface{}); ok {
+			for _, component := range components {
+				if component == name {
+					composed = true
+					continue
+				}
+				composedOf = append(composedOf, component)
+			}
+		}
+		if composed == expected {
+			continue
+		}
+		if expected {
+			composedOf = append([]interface{}{name}, composedOf...)
+		}
+		if composedOf == nil {
+			composedOf = []interface{}{}
+		}
+		template.IndexTemplate[composedOfKey] = composedOf
+		if err := o.putComposableIndexTemplate(opensearchEndpoint, template.Name, template.IndexTemplate); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// isSystemIndexTemplate returns true if the index patterns of an index template only match system indices, whose name
+// starts with a dot
+func isSystemIndexTemplate(patterns []string) bool {
+	for _, pattern := range patterns {
+		if !strings.HasPrefix(pattern, ".") {
+			return false
+		}
+	}
+	return len(patterns) > 0
+}
+
+// getAllComposableIndexTemplates returns the composable index templates of the cluster
+func (o *OSClient) getAllComposableIndexTemplates(opensearchEndpoint string) (*composableIndexTemplates, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/_index_template", opensearchEndpoint), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	templates := &composableIndexTemplates{}
+	// OpenSearch responds with not found if there are no composable index templates
+	if resp.StatusCode == http.StatusNotFound {
+		return templates, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d when querying composable index templates", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(templates); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
+
+func (o *OSClient) putComposableIndexTemplate(opensearchEndpoint, name string, template map[string]interface{}) error {
+	body, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("PUT", fmt.Sprintf("%s/_index_template/%s", opensearchEndpoint, name), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when putting composable index template %s", resp.StatusCode, name)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+const testReplicasTemplate = `{"settings": {"number_of_replicas": 1}}`
+
+// testExistingComponentTemplates returns the component templates of a cluster with an unchanged and a removed VMI
+// managed component template, and a component template not managed by the VMI
+func testExistingComponentTemplates(t *testing.T) string {
+	unchanged, err := toComponentTemplate(vmcontrollerv1.ComponentTemplate{Name: "replicas", Template: testReplicasTemplate})
+	assert.NoError(t, err)
+	meta, err := json.Marshal(unchanged.Meta)
+	assert.NoError(t, err)
+	return fmt.Sprintf(`{
+  "component_templates": [
+    {
+      "name": "replicas",
+      "component_template": {"template": {"settings": {"index": {"number_of_replicas": "1"}}}, "_meta": %s}
+    },
+    {
+      "name": "removed",
+      "component_template": {"template": {"mappings": {}}, "_meta": {"managed_by": "__vmi-managed__", "checksum": "abc"}}
+    },
+    {
+      "name": "user-template",
+      "component_template": {"template": {"mappings": {}}}
+    }
+  ]
+}`, meta)
+}
+
+// TestConfigureComponentTemplatesDisabled Tests that component templates are not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureComponentTemplates
+// THEN OpenSearch is not called and no error is returned
+func TestConfigureComponentTemplatesDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-o.ConfigureComponentTemplates(&vmcontrollerv1.VerrazzanoMonitoringInstance{}))
+}
+
+// TestSyncComponentTemplates Tests syncing the component templates of a VMI
+// GIVEN an unchanged, a new and a removed VMI managed component template, and a component template not managed by the VMI
+// WHEN I call syncComponentTemplates
+// THEN only the new component template is put, only the removed component template is deleted, and the other
+// component templates are left untouched
+func TestSyncComponentTemplates(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	existingTemplates := testExistingComponentTemplates(t)
+	var puts, deletes []string
+	var putTemplate ComponentTemplate
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		switch request.Method {
+		case "GET":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(existingTemplates)),
+			}, nil
+		case "PUT":
+			puts = append(puts, request.URL.Path)
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&putTemplate))
+		case "DELETE":
+			deletes = append(deletes, request.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+		}, nil
+	}
+
+	err := o.syncComponentTemplates("http://localhost:9200", []vmcontrollerv1.ComponentTemplate{
+		{Name: "replicas", Template: testReplicasTemplate},
+		{Name: "keyword-mappings", Template: `{"mappings": {"dynamic_templates": []}}`},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/_component_template/keyword-mappings"}, puts)
+	assert.Equal(t, vmiManagedComponentTemplate, putTemplate.Meta.ManagedBy)
+	assert.NotEmpty(t, putTemplate.Meta.Checksum)
+	assert.Contains(t, putTemplate.Template, "mappings")
+	assert.Equal(t, []string{"/_component_template/removed"}, deletes)
+}
+
+// TestSyncComponentTemplatesChanged Tests syncing a changed component template of a VMI
+// GIVEN a VMI managed component template whose template has changed, and a cluster without component templates
+// WHEN I call syncComponentTemplates
+// THEN the component template is put
+func TestSyncComponentTemplatesChanged(t *testing.T) {
+	for _, existingTemplates := range []string{testExistingComponentTemplates(t), ""} {
+		o := NewOSClient(statefulSetLister)
+		var puts []string
+		o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+			if request.Method == "GET" {
+				if existingTemplates == "" {
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       io.NopCloser(strings.NewReader("{}")),
+					}, nil
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(existingTemplates)),
+				}, nil
+			}
+			if request.Method == "PUT" {
+				puts = append(puts, request.URL.Path)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+			}, nil
+		}
+
+		err := o.syncComponentTemplates("http://localhost:9200", []vmcontrollerv1.ComponentTemplate{
+			{Name: "replicas", Template: `{"settings": {"number_of_replicas": 2}}`},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"/_component_template/replicas"}, puts)
+	}
+}
+
+// TestSyncComponentTemplatesInvalid Tests syncing an invalid component template
+// GIVEN a VMI component template whose template is not a JSON object, and a component template which is rejected by OpenSearch
+// WHEN I call syncComponentTemplates
+// THEN an error is returned
+func TestSyncComponentTemplatesInvalid(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		if request.Method == "GET" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`{"component_templates": []}`)),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+
+	err := o.syncComponentTemplates("http://localhost:9200", []vmcontrollerv1.ComponentTemplate{
+		{Name: "invalid", Template: `[{"settings": {}}]`},
+	})
+	assert.Error(t, err)
+
+	err = o.syncComponentTemplates("http://localhost:9200", []vmcontrollerv1.ComponentTemplate{
+		{Name: "rejected", Template: `{"settings": {"unknown": true}}`},
+	})
+	assert.Error(t, err)
+}
+
+// createIndexSettingsOSClient creates an OSClient for a cluster with the given component templates and composable index
+// templates, recording the method and path of the write requests and the composition of the index templates put
+func createIndexSettingsOSClient(t *testing.T, componentTemplates, indexTemplates string, writes *[]string, composedOf map[string][]interface{}) *OSClient {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		body := `{"acknowledged": true}`
+		switch {
+		case request.Method == "GET" && request.URL.Path == "/_component_template":
+			body = componentTemplates
+		case request.Method == "GET" && request.URL.Path == "/_index_template":
+			body = indexTemplates
+		default:
+			*writes = append(*writes, request.Method+" "+request.URL.Path)
+		}
+		if request.Method == "PUT" && strings.HasPrefix(request.URL.Path, "/_index_template/") {
+			var template map[string]interface{}
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&template))
+			composedOf[strings.TrimPrefix(request.URL.Path, "/_index_template/")] = template[composedOfKey].([]interface{})
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	return o
+}
+
+// TestSyncIndexSettingsComponentTemplate Tests composing the component template of index settings into the index templates
+// GIVEN composable index templates of data streams matching the index patterns of the settings, one of them already
+// composed of the component template, an index template not matching them, and an index template of system indices
+// WHEN I call syncIndexSettingsComponentTemplate with the component template, then without it
+// THEN the component template is put and composed first into the matching index template which is not composed of it
+// yet, then removed from the index templates composed of it and deleted, the other index templates are left untouched
+func TestSyncIndexSettingsComponentTemplate(t *testing.T) {
+	componentTemplate, err := newIndexSettingsComponentTemplate(map[string]interface{}{codecSetting: "best_compression"})
+	assert.NoError(t, err)
+	meta, err := json.Marshal(componentTemplate.Meta)
+	assert.NoError(t, err)
+	indexTemplates := `{
+  "index_templates": [
+    {
+      "name": "verrazzano-data-stream",
+      "index_template": {"index_patterns": ["verrazzano-system"], "composed_of": ["mappings"], "priority": 100, "data_stream": {}}
+    },
+    {
+      "name": "verrazzano-application",
+      "index_template": {"index_patterns": ["verrazzano-application*"], "composed_of": ["vmi-index-sort", "mappings"], "data_stream": {}}
+    },
+    {
+      "name": "other",
+      "index_template": {"index_patterns": ["other-*"], "composed_of": []}
+    },
+    {
+      "name": "ism-plugin-template",
+      "index_template": {"index_patterns": [".opendistro*"], "priority": 0}
+    }
+  ]
+}`
+
+	var writes []string
+	composedOf := map[string][]interface{}{}
+	o := createIndexSettingsOSClient(t, `{"component_templates": []}`, indexTemplates, &writes, composedOf)
+	assert.NoError(t, o.syncIndexSettingsComponentTemplate("", indexSortTemplateName, componentTemplate, []string{"verrazzano-*"}))
+	assert.Equal(t, []string{"PUT /_component_template/vmi-index-sort", "PUT /_index_template/verrazzano-data-stream"}, writes)
+	assert.Equal(t, []interface{}{"vmi-index-sort", "mappings"}, composedOf["verrazzano-data-stream"])
+
+	// the unchanged component template is not put again
+	writes = nil
+	componentTemplates := fmt.Sprintf(`{"component_templates": [{"name": "vmi-index-sort", "component_template": {"template": {}, "_meta": %s}}]}`, meta)
+	o = createIndexSettingsOSClient(t, componentTemplates, indexTemplates, &writes, composedOf)
+	assert.NoError(t, o.syncIndexSettingsComponentTemplate("", indexSortTemplateName, componentTemplate, []string{"verrazzano-*"}))
+	assert.Equal(t, []string{"PUT /_index_template/verrazzano-data-stream"}, writes)
+
+	// the removed component template is no longer composed into the index templates before being deleted
+	writes = nil
+	assert.NoError(t, o.syncIndexSettingsComponentTemplate("", indexSortTemplateName, nil, nil))
+	assert.Equal(t, []string{"PUT /_index_template/verrazzano-application", "DELETE /_component_template/vmi-index-sort"}, writes)
+	assert.Equal(t, []interface{}{"mappings"}, composedOf["verrazzano-application"])
+
+	// nothing is done if the removed component template does not exist
+	writes = nil
+	o = createIndexSettingsOSClient(t, `{"component_templates": []}`, indexTemplates, &writes, composedOf)
+	assert.NoError(t, o.syncIndexSettingsComponentTemplate("", indexSortTemplateName, nil, nil))
+	assert.Empty(t, writes)
+}
@@ -0,0 +1,161 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+const (
+	diskWatermarkLowSetting   = "cluster.routing.allocation.disk.watermark.low"
+	diskWatermarkHighSetting  = "cluster.routing.allocation.disk.watermark.high"
+	diskWatermarkFloodSetting = "cluster.routing.allocation.disk.watermark.flood_stage"
+)
+
+// diskWatermarkSettings are all the disk watermark settings managed by the VMI, from the lowest to the highest watermark
+var diskWatermarkSettings = []string{
+	diskWatermarkLowSetting,
+	diskWatermarkHighSetting,
+	diskWatermarkFloodSetting,
+}
+
+var (
+	diskWatermarkPercentRegex = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?)%$`)
+	diskWatermarkBytesRegex   = regexp.MustCompile(`^([0-9]+)(b|kb|mb|gb|tb|pb)$`)
+)
+
+// diskWatermarkByteUnits are the multipliers of the byte units of the disk watermarks
+var diskWatermarkByteUnits = map[string]float64{
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+	"pb": 1 << 50,
+}
+
+// ConfigureDiskWatermarks sets the persistent disk watermark cluster settings of the VMI, and resets the watermarks
+// which are not set in the VMI to the OpenSearch defaults. The settings are only updated if they differ from the
+// persistent cluster settings.
+// The returned channel should be read for exactly one response, which tells whether the disk watermarks were configured.
+func (o *OSClient) ConfigureDiskWatermarks(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		ch <- o.syncDiskWatermarks(resources.GetOpenSearchHTTPEndpoint(vmi), vmi.Spec.Opensearch.DiskWatermarks)
+	}()
+
+	return ch
+}
+
+// syncDiskWatermarks puts the disk watermark settings which differ from the persistent cluster settings, the
+// watermarks which are no longer set are reset to their defaults
+func (o *OSClient) syncDiskWatermarks(opensearchEndpoint string, watermarks *vmcontrollerv1.OpenSearchDiskWatermarks) error {
+	expected, err := toDiskWatermarkSettings(watermarks)
+	if err != nil {
+		return err
+	}
+	settings, err := o.getClusterSettings(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	changed := map[string]interface{}{}
+	for _, name := range diskWatermarkSettings {
+		current, isSet := settings.Persistent[name]
+		value, isExpected := expected[name]
+		if !isExpected {
+			if isSet {
+				changed[name] = nil
+			}
+			continue
+		}
+		if !isSet || fmt.Sprintf("%v", current) != value {
+			changed[name] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return o.putClusterSettings(opensearchEndpoint, changed, true)
+}
+
+// toDiskWatermarkSettings returns the flat cluster settings of the disk watermarks of the VMI, only the configured
+// watermarks are included. The watermarks must all be percentages or all be byte values, and must be ordered from the
+// low to the flood watermark.
+func toDiskWatermarkSettings(watermarks *vmcontrollerv1.OpenSearchDiskWatermarks) (map[string]string, error) {
+	settings := map[string]string{}
+	if watermarks == nil {
+		return settings, nil
+	}
+	values := map[string]string{
+		diskWatermarkLowSetting:   watermarks.Low,
+		diskWatermarkHighSetting:  watermarks.High,
+		diskWatermarkFloodSetting: watermarks.Flood,
+	}
+	var percentages, byteValues bool
+	var previousName string
+	var previous float64
+	for _, name := range diskWatermarkSettings {
+		value := strings.TrimSpace(values[name])
+		if value == "" {
+			continue
+		}
+		usage, isPercentage, err := parseDiskWatermark(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid disk watermark %s: %v", name, err)
+		}
+		percentages = percentages || isPercentage
+		byteValues = byteValues || !isPercentage
+		if percentages && byteValues {
+			return nil, fmt.Errorf("disk watermarks must all be percentages or all be byte values")
+		}
+		if previousName != "" && usage < previous {
+			return nil, fmt.Errorf("disk watermark %s %s is below the disk watermark %s", name, value, previousName)
+		}
+		previousName = name
+		previous = usage
+		settings[name] = value
+	}
+	return settings, nil
+}
+
+// parseDiskWatermark parses a disk watermark, either a percentage of used disk, e.g. 85%, or an amount of free disk,
+// e.g. 50gb. The returned usage grows with the disk usage the watermark stands for, so watermarks of the same format
+// can be compared: the used percentage, or the negated amount of free bytes.
+func parseDiskWatermark(value string) (float64, bool, error) {
+	if match := diskWatermarkPercentRegex.FindStringSubmatch(value); match != nil {
+		percentage, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, false, err
+		}
+		if percentage > 100 {
+			return 0, false, fmt.Errorf("percentage %s is above 100%%", value)
+		}
+		return percentage, true, nil
+	}
+	if match := diskWatermarkBytesRegex.FindStringSubmatch(value); match != nil {
+		amount, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return 0, false, err
+		}
+		return -amount * diskWatermarkByteUnits[match[2]], false, nil
+	}
+	return 0, false, fmt.Errorf("'%s' has to be a percentage, e.g. 85%%, or a byte value, e.g. 50gb", value)
+}
@@ -0,0 +1,123 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// TestConfigureDiskWatermarksDisabled Tests that the disk watermarks are not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureDiskWatermarks
+// THEN OpenSearch is not called and no error is returned
+func TestConfigureDiskWatermarksDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-o.ConfigureDiskWatermarks(&vmcontrollerv1.VerrazzanoMonitoringInstance{}))
+}
+
+// TestSyncDiskWatermarks Tests syncing the disk watermarks of a VMI
+// GIVEN disk watermarks of a VMI, and clusters with unchanged, changed, removed and unrelated settings
+// WHEN I call syncDiskWatermarks
+// THEN the changed and new watermarks are put as persistent settings, the removed watermarks are reset to the
+// OpenSearch defaults, and the cluster settings are not updated if no watermark changed
+func TestSyncDiskWatermarks(t *testing.T) {
+	var tests = []struct {
+		name               string
+		persistentSettings string
+		watermarks         *vmcontrollerv1.OpenSearchDiskWatermarks
+		expectedBodies     []string
+	}{
+		{
+			name: "changed percentages",
+			persistentSettings: `{
+  "cluster.routing.allocation.disk.watermark.low": "85%",
+  "cluster.routing.allocation.disk.watermark.high": "90%",
+  "cluster.routing.allocation.enable": "all"
+}`,
+			watermarks: &vmcontrollerv1.OpenSearchDiskWatermarks{Low: "85%", High: "92.5%", Flood: "97%"},
+			expectedBodies: []string{`{"persistent": {
+  "cluster.routing.allocation.disk.watermark.high": "92.5%",
+  "cluster.routing.allocation.disk.watermark.flood_stage": "97%"
+}}`},
+		},
+		{
+			name:               "byte values",
+			persistentSettings: `{}`,
+			watermarks:         &vmcontrollerv1.OpenSearchDiskWatermarks{Low: "100gb", High: "50gb", Flood: "10240mb"},
+			expectedBodies: []string{`{"persistent": {
+  "cluster.routing.allocation.disk.watermark.low": "100gb",
+  "cluster.routing.allocation.disk.watermark.high": "50gb",
+  "cluster.routing.allocation.disk.watermark.flood_stage": "10240mb"
+}}`},
+		},
+		{
+			name:               "removed",
+			persistentSettings: `{"cluster.routing.allocation.disk.watermark.low": "80%", "cluster.routing.allocation.disk.watermark.flood_stage": "98%"}`,
+			expectedBodies: []string{`{"persistent": {
+  "cluster.routing.allocation.disk.watermark.low": null,
+  "cluster.routing.allocation.disk.watermark.flood_stage": null
+}}`},
+		},
+		{
+			name:               "unchanged",
+			persistentSettings: `{"cluster.routing.allocation.disk.watermark.high": "95%"}`,
+			watermarks:         &vmcontrollerv1.OpenSearchDiskWatermarks{High: "95%"},
+		},
+		{
+			name:               "not configured",
+			persistentSettings: `{"cluster.routing.allocation.enable": "all"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updates []clusterSettingsUpdate
+			o := createClusterSettingsOSClient(t, tt.persistentSettings, &updates)
+			assert.NoError(t, o.syncDiskWatermarks("http://localhost:9200", tt.watermarks))
+			assert.Len(t, updates, len(tt.expectedBodies))
+			for i := range updates {
+				body, err := json.Marshal(updates[i])
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedBodies[i], string(body))
+			}
+		})
+	}
+}
+
+// TestSyncDiskWatermarksInvalid Tests syncing invalid disk watermarks of a VMI
+// GIVEN invalid disk watermarks of a VMI
+// WHEN I call syncDiskWatermarks
+// THEN an error is returned and OpenSearch is not called
+func TestSyncDiskWatermarksInvalid(t *testing.T) {
+	tests := []struct {
+		name       string
+		watermarks vmcontrollerv1.OpenSearchDiskWatermarks
+		err        string
+	}{
+		{"format", vmcontrollerv1.OpenSearchDiskWatermarks{Low: "85 percent"}, "has to be a percentage"},
+		{"ratio", vmcontrollerv1.OpenSearchDiskWatermarks{High: "0.9"}, "has to be a percentage"},
+		{"above 100%", vmcontrollerv1.OpenSearchDiskWatermarks{Flood: "101%"}, "above 100%"},
+		{"mixed", vmcontrollerv1.OpenSearchDiskWatermarks{Low: "85%", High: "50gb"}, "all be percentages or all be byte values"},
+		{"unordered percentages", vmcontrollerv1.OpenSearchDiskWatermarks{Low: "85%", Flood: "80%"}, "below the disk watermark " + diskWatermarkLowSetting},
+		{"unordered bytes", vmcontrollerv1.OpenSearchDiskWatermarks{High: "1gb", Flood: "2gb"}, "below the disk watermark " + diskWatermarkHighSetting},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := NewOSClient(statefulSetLister)
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+				return nil, nil
+			}
+			assert.ErrorContains(t, o.syncDiskWatermarks("http://localhost:9200", &tt.watermarks), tt.err)
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package opensearch
@@ -11,11 +11,14 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
 	nodetool "github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
 	"net/http"
+
+	"go.uber.org/zap"
 )
 
 type (
 	ClusterHealth struct {
-		Status string `json:"status"`
+		Status           string `json:"status"`
+		RelocatingShards int    `json:"relocating_shards"`
 	}
 
 	NodeSettings struct {
@@ -30,6 +33,7 @@ type (
 
 const (
 	HealthGreen           = "green"
+	HealthYellow          = "yellow"
 	MinDataNodesForResize = 2
 )
 
@@ -40,6 +44,10 @@ func (o *OSClient) opensearchHealth(vmo *vmcontrollerv1.VerrazzanoMonitoringInst
 	if !vmo.Spec.Opensearch.Enabled {
 		return nil
 	}
+	if o.skipHealthChecks {
+		zap.S().Warnf("Skipping the OpenSearch health checks of VMI %s/%s, this must never be done in production", vmo.Namespace, vmo.Name)
+		return nil
+	}
 	// Verify that the cluster is Green
 	clusterHealth, err := o.getOpenSearchClusterHealth(vmo)
 	if err != nil {
@@ -75,6 +83,26 @@ func (o *OSClient) opensearchHealth(vmo *vmcontrollerv1.VerrazzanoMonitoringInst
 	return nil
 }
 
+// opensearchAvailable returns an error if the OpenSearch cluster health is neither 'green' nor 'yellow', i.e. some
+// primary shards are not allocated
+func (o *OSClient) opensearchAvailable(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if !vmo.Spec.Opensearch.Enabled {
+		return nil
+	}
+	if o.skipHealthChecks {
+		zap.S().Warnf("Skipping the OpenSearch health checks of VMI %s/%s, this must never be done in production", vmo.Namespace, vmo.Name)
+		return nil
+	}
+	clusterHealth, err := o.getOpenSearchClusterHealth(vmo)
+	if err != nil {
+		return err
+	}
+	if clusterHealth.Status != HealthGreen && clusterHealth.Status != HealthYellow {
+		return fmt.Errorf("OpenSearch health is %s", clusterHealth.Status)
+	}
+	return nil
+}
+
 func (o *OSClient) getOpenSearchNodes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]Node, error) {
 	url := resources.GetOpenSearchHTTPEndpoint(vmo) + "/_nodes/settings"
 	req, err := http.NewRequest("GET", url, nil)
@@ -231,6 +231,32 @@ func TestIsOpenSearchResizable(t *testing.T) {
 	assert.Error(t, o.IsDataResizable(&notEnoughNodesVMO))
 }
 
+// TestSkipHealthChecks Tests that the health gates are bypassed when the health checks are skipped
+// GIVEN a client skipping the health checks, and an OpenSearch cluster which is red and fails the node requests
+// WHEN I call IsGreen, IsUpdated and IsDataResizable, also through a client bound to a context
+// THEN no error is returned and OpenSearch is not queried
+func TestSkipHealthChecks(t *testing.T) {
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.Opensearch.Nodes = []vmcontrollerv1.ElasticsearchNode{{Name: "data-2", Replicas: 1, Roles: []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole}}}
+	requests := 0
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		requests++
+		return mockHTTPGenerator(`{"status": "red"}`, "", 200, 500)(request)
+	}
+	assert.Error(t, o.IsGreen(vmo))
+	requests = 0
+
+	o.SkipHealthChecks()
+	for _, client := range []*OSClient{o, o.WithContext(context.TODO())} {
+		assert.NoError(t, client.IsGreen(vmo))
+		assert.NoError(t, client.IsUpdated(vmo))
+		assert.NoError(t, client.IsDataResizable(vmo))
+	}
+	assert.Equal(t, 0, requests)
+}
+
 // simple StatefulsetLister implementation
 type simpleStatefulSetLister struct {
 	kubeClient kubernetes.Interface
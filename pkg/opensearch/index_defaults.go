@@ -0,0 +1,236 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+)
+
+type (
+	// IndexTemplate is the document of an OpenSearch legacy index template
+	IndexTemplate struct {
+		IndexPatterns []string               `json:"index_patterns"`
+		Order         int                    `json:"order"`
+		Settings      map[string]interface{} `json:"settings"`
+	}
+)
+
+const (
+	// Name of the index template holding the index defaults of the VMI
+	indexDefaultsTemplateName = "vmi-index-defaults"
+
+	totalFieldsLimitSetting   = "index.mapping.total_fields.limit"
+	nestedFieldsLimitSetting  = "index.mapping.nested_fields.limit"
+	nestedObjectsLimitSetting = "index.mapping.nested_objects.limit"
+	numberOfShardsSetting     = "index.number_of_shards"
+	numberOfReplicasSetting   = "index.number_of_replicas"
+	refreshIntervalSetting    = "index.refresh_interval"
+	totalShardsPerNodeSetting = "index.routing.allocation.total_shards_per_node"
+	queriesCacheSetting       = "index.queries.cache.enabled"
+	requestsCacheSetting      = "index.requests.cache.enable"
+	defaultPipelineSetting    = "index.default_pipeline"
+	codecSetting              = "index.codec"
+	autoExpandReplicasSetting = "index.auto_expand_replicas"
+
+	// The replicas of the indices of a single node cluster, which are only allocated once the cluster grows
+	singleNodeAutoExpandReplicas = "0-1"
+)
+
+// ConfigureIndexDefaults configures the index defaults, the mapping limits, the query cache toggle and the default
+// pipeline of the VMI for all new indices, or removes them if the VMI has none so the OpenSearch defaults are used
+// again. The default pipeline must exist before it is set, see ensureDefaultPipeline. On a single node cluster, the
+// replicas of new indices auto expand, as SetAutoExpandIndices does for the system indices, so they do not stay
+// yellow. The settings are held by a component template composed into the composable index templates, e.g. the index
+// templates of data streams, and by a legacy index template with the lowest order for the indices matched by no
+// composable index template, as legacy index templates are ignored for the indices matched by a composable one.
+// The returned channel should be read for exactly one response, which tells whether the index defaults were configured.
+func (o *OSClient) ConfigureIndexDefaults(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		opensearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+		defaultPipeline := vmi.Spec.Opensearch.DefaultPipeline
+		template := toIndexDefaultsTemplate(vmi.Spec.Opensearch.IndexDefaults, vmi.Spec.Opensearch.MappingLimits, vmi.Spec.Opensearch.QueryCacheEnabled, defaultPipeline)
+		if nodes.IsSingleNodeCluster(vmi) {
+			if _, ok := template.Settings[numberOfReplicasSetting]; !ok {
+				template.Settings[autoExpandReplicasSetting] = singleNodeAutoExpandReplicas
+			}
+		}
+		if defaultPipeline != "" {
+			if err := o.ensureDefaultPipeline(opensearchEndpoint, vmi); err != nil {
+				ch <- err
+				return
+			}
+		}
+		ch <- o.syncIndexSettingsTemplates(opensearchEndpoint, indexDefaultsTemplateName, template)
+	}()
+
+	return ch
+}
+
+// syncIndexSettingsTemplates puts the component template and the legacy index template holding the settings of the
+// given template, or removes them if the template has no settings
+func (o *OSClient) syncIndexSettingsTemplates(opensearchEndpoint, name string, template *IndexTemplate) error {
+	if len(template.Settings) == 0 {
+		if err := o.syncIndexSettingsComponentTemplate(opensearchEndpoint, name, nil, nil); err != nil {
+			return err
+		}
+		return o.deleteIndexTemplate(opensearchEndpoint, name)
+	}
+	componentTemplate, err := newIndexSettingsComponentTemplate(template.Settings)
+	if err != nil {
+		return err
+	}
+	if err := o.syncIndexSettingsComponentTemplate(opensearchEndpoint, name, componentTemplate, template.IndexPatterns); err != nil {
+		return err
+	}
+	return o.putIndexTemplate(opensearchEndpoint, name, template)
+}
+
+func (o *OSClient) putIndexTemplate(opensearchEndpoint, name string, template *IndexTemplate) error {
+	body, err := json.Marshal(template)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/_template/%s", opensearchEndpoint, name)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when putting index template %s", resp.StatusCode, name)
+	}
+	return nil
+}
+
+// deleteIndexTemplate deletes the legacy index template if it exists, e.g. it does not exist if the VMI never had its
+// settings or once it was deleted
+func (o *OSClient) deleteIndexTemplate(opensearchEndpoint, name string) error {
+	url := fmt.Sprintf("%s/_template/%s", opensearchEndpoint, name)
+	req, err := http.NewRequest("HEAD", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when checking index template %s", resp.StatusCode, name)
+	}
+
+	req, err = http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err = o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting index template %s", resp.StatusCode, name)
+	}
+	return nil
+}
+
+// ensureDefaultPipeline returns an error unless the default pipeline of the VMI exists. As the ingest pipelines of the
+// VMI are configured concurrently with the index defaults, a default pipeline of the VMI which does not exist yet is
+// put first, so that new indices never reference a missing pipeline.
+func (o *OSClient) ensureDefaultPipeline(opensearchEndpoint string, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	name := vmi.Spec.Opensearch.DefaultPipeline
+	exists, err := o.ingestPipelineExists(opensearchEndpoint, name)
+	if err != nil || exists {
+		return err
+	}
+	for _, pipeline := range vmi.Spec.Opensearch.IngestPipelines {
+		if pipeline.Name != name {
+			continue
+		}
+		ingestPipeline, err := toIngestPipeline(pipeline)
+		if err != nil {
+			return err
+		}
+		return o.putIngestPipeline(opensearchEndpoint, name, ingestPipeline)
+	}
+	return fmt.Errorf("default pipeline %s is neither an ingest pipeline of the VMI nor an existing ingest pipeline", name)
+}
+
+// toIndexDefaultsTemplate creates the index template of the index defaults, of the mapping limits, of the query cache
+// toggle and of the default pipeline, only the configured settings are included
+func toIndexDefaultsTemplate(indexDefaults *vmcontrollerv1.IndexDefaults, mappingLimits *vmcontrollerv1.OpenSearchMappingLimits, queryCacheEnabled *bool, defaultPipeline string) *IndexTemplate {
+	settings := map[string]interface{}{}
+	if defaultPipeline != "" {
+		settings[defaultPipelineSetting] = defaultPipeline
+	}
+	if queryCacheEnabled != nil {
+		settings[queriesCacheSetting] = *queryCacheEnabled
+		settings[requestsCacheSetting] = *queryCacheEnabled
+	}
+	if indexDefaults == nil {
+		indexDefaults = &vmcontrollerv1.IndexDefaults{}
+	}
+	// the deprecated total fields limit of the index defaults is overridden by the total fields of the mapping limits
+	if indexDefaults.TotalFieldsLimit != nil {
+		settings[totalFieldsLimitSetting] = *indexDefaults.TotalFieldsLimit
+	}
+	if indexDefaults.NumberOfShards != nil {
+		settings[numberOfShardsSetting] = *indexDefaults.NumberOfShards
+	}
+	if indexDefaults.NumberOfReplicas != nil {
+		settings[numberOfReplicasSetting] = *indexDefaults.NumberOfReplicas
+	}
+	if indexDefaults.RefreshInterval != nil {
+		settings[refreshIntervalSetting] = *indexDefaults.RefreshInterval
+	}
+	if indexDefaults.TotalShardsPerNode != nil {
+		settings[totalShardsPerNodeSetting] = *indexDefaults.TotalShardsPerNode
+	}
+	if indexDefaults.Codec != nil {
+		settings[codecSetting] = *indexDefaults.Codec
+	}
+	if mappingLimits != nil {
+		if mappingLimits.TotalFields != nil {
+			settings[totalFieldsLimitSetting] = *mappingLimits.TotalFields
+		}
+		if mappingLimits.NestedFields != nil {
+			settings[nestedFieldsLimitSetting] = *mappingLimits.NestedFields
+		}
+		if mappingLimits.NestedObjects != nil {
+			settings[nestedObjectsLimitSetting] = *mappingLimits.NestedObjects
+		}
+	}
+	return &IndexTemplate{
+		IndexPatterns: []string{"*"},
+		Order:         0,
+		Settings:      settings,
+	}
+}
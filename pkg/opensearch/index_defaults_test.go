@@ -0,0 +1,345 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// lastRequest returns the method and the body of the last request to the given path, or empty strings if there is none
+func lastRequest(requests []*http.Request, bodies []string, path string) (string, string) {
+	for i := len(requests) - 1; i >= 0; i-- {
+		if requests[i].URL.Path == path {
+			return requests[i].Method, bodies[i]
+		}
+	}
+	return "", ""
+}
+
+// assertIndexSettingsTemplates asserts that the last requests put the component template and the legacy index template
+// with the given name, index patterns and settings
+func assertIndexSettingsTemplates(t *testing.T, requests []*http.Request, bodies []string, name string, indexPatterns []string, settings map[string]interface{}) {
+	method, body := lastRequest(requests, bodies, "/_component_template/"+name)
+	assert.Equal(t, "PUT", method)
+	var componentTemplate ComponentTemplate
+	assert.NoError(t, json.Unmarshal([]byte(body), &componentTemplate))
+	assert.Equal(t, map[string]interface{}{"settings": settings}, componentTemplate.Template)
+	assert.Equal(t, vmiIndexSettingsComponentTemplate, componentTemplate.Meta.ManagedBy)
+
+	method, body = lastRequest(requests, bodies, "/_template/"+name)
+	assert.Equal(t, "PUT", method)
+	var template map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(body), &template))
+	var patterns []interface{}
+	for _, pattern := range indexPatterns {
+		patterns = append(patterns, pattern)
+	}
+	assert.Equal(t, patterns, template["index_patterns"])
+	assert.Equal(t, float64(0), template["order"])
+	assert.Equal(t, settings, template["settings"])
+}
+
+// TestConfigureIndexDefaults Tests putting the index defaults templates
+// GIVEN a VMI with index defaults and mapping limits
+// WHEN I call ConfigureIndexDefaults
+// THEN a component template and a legacy index template matching all indices with the configured settings are put
+func TestConfigureIndexDefaults(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	o := createReadyOSClient(http.StatusOK, &requests, &bodies)
+	vmi := testvmo.DeepCopy()
+	totalFieldsLimit := int32(2000)
+	numberOfShards := int32(3)
+	numberOfReplicas := int32(0)
+	vmi.Spec.Opensearch.IndexDefaults = &vmcontrollerv1.IndexDefaults{
+		NumberOfShards:   &numberOfShards,
+		NumberOfReplicas: &numberOfReplicas,
+	}
+	vmi.Spec.Opensearch.MappingLimits = &vmcontrollerv1.OpenSearchMappingLimits{TotalFields: &totalFieldsLimit}
+
+	assert.NoError(t, <-o.ConfigureIndexDefaults(vmi))
+	assertIndexSettingsTemplates(t, requests, bodies, indexDefaultsTemplateName, []string{"*"}, map[string]interface{}{
+		totalFieldsLimitSetting: float64(2000),
+		numberOfShardsSetting:   float64(3),
+		numberOfReplicasSetting: float64(0),
+	})
+}
+
+// TestConfigureIndexDefaultsSettings Tests the settings of the index defaults templates
+// GIVEN a VMI with only some of the index defaults, the mapping limits or the query cache toggle set
+// WHEN I call ConfigureIndexDefaults
+// THEN the index defaults templates only contain the configured settings, the total fields mapping limit taking
+// precedence over the total fields limit of the index defaults
+func TestConfigureIndexDefaultsSettings(t *testing.T) {
+	totalFieldsLimit := int32(5000)
+	indexDefaultsLimit := int32(1000)
+	nestedFields := int32(100)
+	nestedObjects := int32(20000)
+	totalShardsPerNode := int32(2)
+	refreshInterval := "30s"
+	refreshDisabled := "-1"
+	codec := "best_compression"
+	queryCacheEnabled := true
+	queryCacheDisabled := false
+	var tests = []struct {
+		name              string
+		indexDefaults     *vmcontrollerv1.IndexDefaults
+		mappingLimits     *vmcontrollerv1.OpenSearchMappingLimits
+		queryCacheEnabled *bool
+		expectedSettings  map[string]interface{}
+	}{
+		{
+			"deprecated total fields limit",
+			&vmcontrollerv1.IndexDefaults{TotalFieldsLimit: &totalFieldsLimit},
+			nil,
+			nil,
+			map[string]interface{}{totalFieldsLimitSetting: float64(5000)},
+		},
+		{
+			"refresh interval",
+			&vmcontrollerv1.IndexDefaults{RefreshInterval: &refreshInterval},
+			nil,
+			nil,
+			map[string]interface{}{refreshIntervalSetting: "30s"},
+		},
+		{
+			"refreshes disabled",
+			&vmcontrollerv1.IndexDefaults{RefreshInterval: &refreshDisabled},
+			nil,
+			nil,
+			map[string]interface{}{refreshIntervalSetting: "-1"},
+		},
+		{
+			"total shards per node",
+			&vmcontrollerv1.IndexDefaults{TotalFieldsLimit: &totalFieldsLimit, TotalShardsPerNode: &totalShardsPerNode},
+			nil,
+			nil,
+			map[string]interface{}{totalFieldsLimitSetting: float64(5000), totalShardsPerNodeSetting: float64(2)},
+		},
+		{
+			"codec",
+			&vmcontrollerv1.IndexDefaults{Codec: &codec},
+			nil,
+			nil,
+			map[string]interface{}{codecSetting: "best_compression"},
+		},
+		{
+			"mapping limits",
+			&vmcontrollerv1.IndexDefaults{TotalFieldsLimit: &indexDefaultsLimit},
+			&vmcontrollerv1.OpenSearchMappingLimits{TotalFields: &totalFieldsLimit, NestedFields: &nestedFields, NestedObjects: &nestedObjects},
+			nil,
+			map[string]interface{}{
+				totalFieldsLimitSetting:   float64(5000),
+				nestedFieldsLimitSetting:  float64(100),
+				nestedObjectsLimitSetting: float64(20000),
+			},
+		},
+		{
+			"query cache enabled",
+			nil,
+			nil,
+			&queryCacheEnabled,
+			map[string]interface{}{queriesCacheSetting: true, requestsCacheSetting: true},
+		},
+		{
+			"query cache disabled",
+			nil,
+			nil,
+			&queryCacheDisabled,
+			map[string]interface{}{queriesCacheSetting: false, requestsCacheSetting: false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []*http.Request
+			var bodies []string
+			o := createReadyOSClient(http.StatusOK, &requests, &bodies)
+			vmi := testvmo.DeepCopy()
+			vmi.Spec.Opensearch.IndexDefaults = tt.indexDefaults
+			vmi.Spec.Opensearch.MappingLimits = tt.mappingLimits
+			vmi.Spec.Opensearch.QueryCacheEnabled = tt.queryCacheEnabled
+
+			assert.NoError(t, <-o.ConfigureIndexDefaults(vmi))
+			assertIndexSettingsTemplates(t, requests, bodies, indexDefaultsTemplateName, []string{"*"}, tt.expectedSettings)
+		})
+	}
+}
+
+// TestConfigureIndexDefaultsRemoved Tests deleting the index defaults templates
+// GIVEN a VMI without index defaults, with and without an existing legacy index defaults template
+// WHEN I call ConfigureIndexDefaults
+// THEN the legacy index template is only deleted if it exists, and no error is returned
+func TestConfigureIndexDefaultsRemoved(t *testing.T) {
+	var tests = []struct {
+		statusCode     int
+		expectedMethod string
+	}{
+		{http.StatusOK, "DELETE"},
+		{http.StatusNotFound, "HEAD"},
+	}
+	for _, tt := range tests {
+		var requests []*http.Request
+		var bodies []string
+		o := createReadyOSClient(tt.statusCode, &requests, &bodies)
+
+		assert.NoError(t, <-o.ConfigureIndexDefaults(testvmo.DeepCopy()))
+		method, _ := lastRequest(requests, bodies, "/_template/"+indexDefaultsTemplateName)
+		assert.Equal(t, tt.expectedMethod, method)
+		method, _ = lastRequest(requests, bodies, "/_component_template/"+indexDefaultsTemplateName)
+		assert.Empty(t, method)
+	}
+}
+
+// TestConfigureIndexDefaultsFailed Tests that an error is returned when OpenSearch rejects the index defaults template
+// GIVEN a VMI with index defaults and OpenSearch responding with an error
+// WHEN I call ConfigureIndexDefaults
+// THEN an error is returned
+func TestConfigureIndexDefaultsFailed(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	o := createReadyOSClient(http.StatusBadRequest, &requests, &bodies)
+	vmi := testvmo.DeepCopy()
+	numberOfShards := int32(1)
+	vmi.Spec.Opensearch.IndexDefaults = &vmcontrollerv1.IndexDefaults{NumberOfShards: &numberOfShards}
+
+	assert.Error(t, <-o.ConfigureIndexDefaults(vmi))
+}
+
+// TestConfigureIndexDefaultsSingleNode Tests the default replicas of a single node cluster
+// GIVEN a single node VMI without index defaults, with index defaults and with a number of replicas,
+// and the same VMI once the cluster grows
+// WHEN I call ConfigureIndexDefaults
+// THEN the replicas of new indices auto expand on the single node cluster unless configured otherwise, and the default
+// is removed from the index templates on the multi node cluster
+func TestConfigureIndexDefaultsSingleNode(t *testing.T) {
+	numberOfShards := int32(2)
+	numberOfReplicas := int32(1)
+	var tests = []struct {
+		name             string
+		indexDefaults    *vmcontrollerv1.IndexDefaults
+		dataReplicas     int32
+		expectedSettings map[string]interface{}
+	}{
+		{
+			"single node without index defaults",
+			nil,
+			0,
+			map[string]interface{}{autoExpandReplicasSetting: singleNodeAutoExpandReplicas},
+		},
+		{
+			"single node with index defaults",
+			&vmcontrollerv1.IndexDefaults{NumberOfShards: &numberOfShards},
+			0,
+			map[string]interface{}{numberOfShardsSetting: float64(2), autoExpandReplicasSetting: singleNodeAutoExpandReplicas},
+		},
+		{
+			"single node with a number of replicas",
+			&vmcontrollerv1.IndexDefaults{NumberOfReplicas: &numberOfReplicas},
+			0,
+			map[string]interface{}{numberOfReplicasSetting: float64(1)},
+		},
+		{
+			"multi node without index defaults",
+			nil,
+			2,
+			nil,
+		},
+		{
+			"multi node with index defaults",
+			&vmcontrollerv1.IndexDefaults{NumberOfShards: &numberOfShards},
+			2,
+			map[string]interface{}{numberOfShardsSetting: float64(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []*http.Request
+			var bodies []string
+			o := createReadyOSClient(http.StatusOK, &requests, &bodies)
+			vmi := testvmo.DeepCopy()
+			vmi.Spec.Opensearch.MasterNode.Roles = []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole, vmcontrollerv1.DataRole, vmcontrollerv1.IngestRole}
+			vmi.Spec.Opensearch.IngestNode.Replicas = 0
+			vmi.Spec.Opensearch.DataNode.Replicas = tt.dataReplicas
+			vmi.Spec.Opensearch.IndexDefaults = tt.indexDefaults
+
+			assert.NoError(t, <-o.ConfigureIndexDefaults(vmi))
+			if tt.expectedSettings != nil {
+				assertIndexSettingsTemplates(t, requests, bodies, indexDefaultsTemplateName, []string{"*"}, tt.expectedSettings)
+			} else {
+				method, _ := lastRequest(requests, bodies, "/_template/"+indexDefaultsTemplateName)
+				assert.Equal(t, "DELETE", method)
+			}
+			// the index defaults of the VMI are not modified
+			if tt.indexDefaults != nil && tt.indexDefaults.NumberOfShards != nil {
+				assert.Nil(t, vmi.Spec.Opensearch.IndexDefaults.NumberOfReplicas)
+			}
+		})
+	}
+}
+
+// TestConfigureIndexDefaultsDefaultPipeline Tests putting the index defaults template with a default pipeline
+// GIVEN a VMI whose default pipeline is one of its ingest pipelines which does not exist yet, and a VMI whose default
+// pipeline is an existing ingest pipeline not managed by the VMI
+// WHEN I call ConfigureIndexDefaults
+// THEN the missing ingest pipeline of the VMI is put before the index templates, and the index templates set the
+// default pipeline of new indices
+func TestConfigureIndexDefaultsDefaultPipeline(t *testing.T) {
+	var tests = []struct {
+		name             string
+		existing         map[string]bool
+		expectedRequests []string
+	}{
+		{
+			"managed pipeline is put first",
+			map[string]bool{},
+			[]string{"GET /_ingest/pipeline/enrich", "PUT /_ingest/pipeline/enrich", "GET /_component_template",
+				"PUT /_component_template/" + indexDefaultsTemplateName, "GET /_index_template", "PUT /_template/" + indexDefaultsTemplateName},
+		},
+		{
+			"existing pipeline",
+			map[string]bool{"enrich": true},
+			[]string{"GET /_ingest/pipeline/enrich", "GET /_component_template", "PUT /_component_template/" + indexDefaultsTemplateName,
+				"GET /_index_template", "PUT /_template/" + indexDefaultsTemplateName},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			var bodies []string
+			o := createPipelineOSClient(t, tt.existing, &requests, &bodies)
+			vmi := testvmo.DeepCopy()
+			vmi.Spec.Opensearch.IngestPipelines = []vmcontrollerv1.IngestPipeline{
+				{Name: "enrich", Processors: `[{"set": {"field": "cluster", "value": "local"}}]`},
+			}
+			vmi.Spec.Opensearch.DefaultPipeline = "enrich"
+
+			assert.NoError(t, <-o.ConfigureIndexDefaults(vmi))
+			assert.Equal(t, tt.expectedRequests, requests)
+			var template IndexTemplate
+			assert.NoError(t, json.Unmarshal([]byte(bodies[len(bodies)-1]), &template))
+			assert.Equal(t, map[string]interface{}{defaultPipelineSetting: "enrich"}, template.Settings)
+		})
+	}
+}
+
+// TestConfigureIndexDefaultsMissingDefaultPipeline Tests the index defaults template with a missing default pipeline
+// GIVEN a VMI whose default pipeline is neither one of its ingest pipelines nor an existing ingest pipeline
+// WHEN I call ConfigureIndexDefaults
+// THEN an error is returned and the index template is not put
+func TestConfigureIndexDefaultsMissingDefaultPipeline(t *testing.T) {
+	var requests []string
+	var bodies []string
+	o := createPipelineOSClient(t, map[string]bool{}, &requests, &bodies)
+	vmi := testvmo.DeepCopy()
+	vmi.Spec.Opensearch.DefaultPipeline = "missing"
+
+	assert.ErrorContains(t, <-o.ConfigureIndexDefaults(vmi), "default pipeline missing")
+	assert.Equal(t, []string{"GET /_ingest/pipeline/missing"}, requests)
+}
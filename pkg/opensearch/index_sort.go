@@ -0,0 +1,65 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+const (
+	// Name of the index template holding the default index sort of the VMI
+	indexSortTemplateName = "vmi-index-sort"
+
+	indexSortFieldSetting = "index.sort.field"
+	indexSortOrderSetting = "index.sort.order"
+	defaultIndexSortOrder = "asc"
+)
+
+// ConfigureIndexSort sorts the new indices matching the index patterns of the default index sort of the VMI, or removes
+// the sort if the VMI has no default index sort so new indices are not sorted. As for the index defaults, the sort is
+// held by a component template composed into the composable index templates matching the index patterns, and by a
+// legacy index template with the lowest order, merged with the other legacy index templates, e.g. the one mapping the
+// sort field.
+// The returned channel should be read for exactly one response, which tells whether the index sort was configured.
+func (o *OSClient) ConfigureIndexSort(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		opensearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmi)
+		template := &IndexTemplate{}
+		if indexSort := vmi.Spec.Opensearch.DefaultIndexSort; indexSort != nil {
+			template = toIndexSortTemplate(indexSort)
+		}
+		ch <- o.syncIndexSettingsTemplates(opensearchEndpoint, indexSortTemplateName, template)
+	}()
+
+	return ch
+}
+
+// toIndexSortTemplate creates the index template of the index sort, sorting in ascending order by default
+func toIndexSortTemplate(indexSort *vmcontrollerv1.IndexSort) *IndexTemplate {
+	order := indexSort.Order
+	if order == "" {
+		order = defaultIndexSortOrder
+	}
+	return &IndexTemplate{
+		IndexPatterns: indexSort.IndexPatterns,
+		Order:         0,
+		Settings: map[string]interface{}{
+			indexSortFieldSetting: indexSort.Field,
+			indexSortOrderSetting: order,
+		},
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// TestConfigureIndexSort Tests putting the index sort templates
+// GIVEN a VMI with a default index sort, without and with a sort order
+// WHEN I call ConfigureIndexSort
+// THEN a component template and a legacy index template matching the index patterns of the sort are put, sorting in
+// ascending order by default
+func TestConfigureIndexSort(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	o := createReadyOSClient(http.StatusOK, &requests, &bodies)
+	vmi := testvmo.DeepCopy()
+	vmi.Spec.Opensearch.DefaultIndexSort = &vmcontrollerv1.IndexSort{
+		Field:         "@timestamp",
+		IndexPatterns: []string{"verrazzano-data-*"},
+	}
+
+	for _, order := range []string{"", "desc"} {
+		vmi.Spec.Opensearch.DefaultIndexSort.Order = order
+		assert.NoError(t, <-o.ConfigureIndexSort(vmi))
+		expectedOrder := order
+		if expectedOrder == "" {
+			expectedOrder = "asc"
+		}
+		assertIndexSettingsTemplates(t, requests, bodies, indexSortTemplateName, []string{"verrazzano-data-*"}, map[string]interface{}{
+			indexSortFieldSetting: "@timestamp",
+			indexSortOrderSetting: expectedOrder,
+		})
+	}
+}
+
+// TestConfigureIndexSortRemoved Tests deleting the index sort templates
+// GIVEN a VMI without a default index sort, whose legacy index sort template exists or not
+// WHEN I call ConfigureIndexSort
+// THEN the legacy index sort template is only deleted if it exists, so new indices are not sorted
+func TestConfigureIndexSortRemoved(t *testing.T) {
+	var tests = []struct {
+		statusCode     int
+		expectedMethod string
+	}{
+		{http.StatusOK, "DELETE"},
+		{http.StatusNotFound, "HEAD"},
+	}
+	for _, tt := range tests {
+		var requests []*http.Request
+		var bodies []string
+		o := createReadyOSClient(tt.statusCode, &requests, &bodies)
+
+		assert.NoError(t, <-o.ConfigureIndexSort(testvmo.DeepCopy()))
+		method, _ := lastRequest(requests, bodies, "/_template/"+indexSortTemplateName)
+		assert.Equal(t, tt.expectedMethod, method)
+	}
+}
+
+// TestConfigureIndexSortFailed Tests failing to put the index sort template
+// GIVEN a VMI with a default index sort, and OpenSearch rejecting the index template
+// WHEN I call ConfigureIndexSort
+// THEN an error is returned
+func TestConfigureIndexSortFailed(t *testing.T) {
+	var requests []*http.Request
+	var bodies []string
+	o := createReadyOSClient(http.StatusBadRequest, &requests, &bodies)
+	vmi := testvmo.DeepCopy()
+	vmi.Spec.Opensearch.DefaultIndexSort = &vmcontrollerv1.IndexSort{Field: "@timestamp", IndexPatterns: []string{"*"}}
+
+	assert.Error(t, <-o.ConfigureIndexSort(vmi))
+}
@@ -9,7 +9,11 @@ import (
 	"fmt"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
 	"net/http"
+	"path"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/verrazzano/pkg/diff"
 
@@ -76,10 +80,36 @@ type (
 		MinIndexAge    string `json:"min_index_age,omitempty"`
 		MinRolloverAge string `json:"min_rollover_age,omitempty"`
 	}
+
+	// indexTemplates is the response of the _index_template API, with flat settings
+	indexTemplates struct {
+		IndexTemplates []struct {
+			Name          string `json:"name"`
+			IndexTemplate struct {
+				IndexPatterns []string  `json:"index_patterns"`
+				DataStream    *struct{} `json:"data_stream,omitempty"`
+				Template      struct {
+					Settings map[string]interface{} `json:"settings,omitempty"`
+				} `json:"template"`
+			} `json:"index_template"`
+		} `json:"index_templates"`
+	}
+
+	// rolloverBootstrapIndex is the body of the request creating the bootstrap index of a rollover alias
+	rolloverBootstrapIndex struct {
+		Aliases  map[string]rolloverWriteAlias `json:"aliases"`
+		Settings map[string]string             `json:"settings"`
+	}
+
+	rolloverWriteAlias struct {
+		IsWriteIndex bool `json:"is_write_index"`
+	}
 )
 
 const (
 	minIndexAgeKey = "min_index_age"
+	minSizeKey     = "min_size"
+	minDocCountKey = "min_doc_count"
 
 	// Default amount of time before a policy-managed index is deleted
 	defaultMinIndexAge = "7d"
@@ -88,6 +118,13 @@ const (
 	// Descriptor to identify policies as being managed by the VMI
 	vmiManagedPolicy = "__vmi-managed__"
 
+	// Suffix of the bootstrap index created for a rollover alias
+	rolloverBootstrapIndexSuffix = "-000001"
+	// Index setting which tells ISM the alias to roll over
+	rolloverAliasSetting = "plugins.index_state_management.rollover_alias"
+	// Rollover alias setting as returned in the flat settings of an index template
+	indexRolloverAliasSetting = "index." + rolloverAliasSetting
+
 	systemDefaultPolicyFileName = "vz-system-default-ISM-policy.json"
 	appDefaultPolicyFileName    = "vz-application-default-ISM-policy.json"
 	defaultPolicyPath           = "k8s/manifests/opensearch/"
@@ -97,11 +134,26 @@ const (
 
 var (
 	defaultISMPoliciesMap = map[string]string{systemDefaultPolicy: systemDefaultPolicyFileName, applicationDefaultPolicy: appDefaultPolicyFileName}
+
+	// ismAgeRegexp matches the ages of the ISM policies, as validated by the VMI CRD
+	ismAgeRegexp = regexp.MustCompile(`^([0-9]+)(d|h|m|s|ms|micros|nanos)$`)
+	ismAgeUnits  = map[string]time.Duration{
+		"d":      24 * time.Hour,
+		"h":      time.Hour,
+		"m":      time.Minute,
+		"s":      time.Second,
+		"ms":     time.Millisecond,
+		"micros": time.Microsecond,
+		"nanos":  time.Nanosecond,
+	}
 )
 
 // createISMPolicy creates an ISM policy if it does not exist, else the policy will be updated.
 // If the policy already exsts and its spec matches the VMO policy spec, no update will be issued
 func (o *OSClient) createISMPolicy(opensearchEndpoint string, policy vmcontrollerv1.IndexManagementPolicy) error {
+	if err := validateISMPolicy(&policy); err != nil {
+		return err
+	}
 	policyURL := fmt.Sprintf("%s/_plugins/_ism/policies/%s", opensearchEndpoint, policy.PolicyName)
 	existingPolicy, err := o.getPolicyByName(policyURL)
 	if err != nil {
@@ -114,6 +166,133 @@ func (o *OSClient) createISMPolicy(opensearchEndpoint string, policy vmcontrolle
 	return o.addPolicyToExistingIndices(opensearchEndpoint, &policy, updatedPolicy)
 }
 
+// ensureRolloverAlias creates the bootstrap index and write alias needed for the rollover action of the policy.
+// Rollover requires an existing write alias, without it the first rollover never triggers. The write alias is the
+// rollover alias configured by the index templates of the index pattern, so nothing is done if no rollover alias is
+// configured, or if the index pattern is the one of data streams, which roll over their backing indices without an
+// alias. If an index, alias or data stream with the alias name already exists, nothing is done either.
+func (o *OSClient) ensureRolloverAlias(opensearchEndpoint string, policy vmcontrollerv1.IndexManagementPolicy) error {
+	alias, isDataStream, err := o.templateRolloverAlias(opensearchEndpoint, policy.IndexPattern)
+	if err != nil || isDataStream || alias == "" {
+		return err
+	}
+	isDataStream, err = o.dataStreamsExist(opensearchEndpoint, policy.IndexPattern)
+	if err != nil || isDataStream {
+		return err
+	}
+	aliasURL := fmt.Sprintf("%s/%s", opensearchEndpoint, alias)
+	req, err := http.NewRequest("HEAD", aliasURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when checking rollover alias %s", resp.StatusCode, alias)
+	}
+
+	bootstrapIndex := alias + rolloverBootstrapIndexSuffix
+	body, err := json.Marshal(rolloverBootstrapIndex{
+		Aliases:  map[string]rolloverWriteAlias{alias: {IsWriteIndex: true}},
+		Settings: map[string]string{rolloverAliasSetting: alias},
+	})
+	if err != nil {
+		return err
+	}
+	req, err = http.NewRequest("PUT", fmt.Sprintf("%s/%s", opensearchEndpoint, bootstrapIndex), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err = o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when creating bootstrap index %s for rollover alias %s", resp.StatusCode, bootstrapIndex, alias)
+	}
+	return nil
+}
+
+// templateRolloverAlias returns the rollover alias configured by the index templates matching the index pattern, and
+// whether one of them is the index template of data streams. An index template matches if one of its index patterns
+// matches the index pattern, e.g. the template of verrazzano-application-* matches verrazzano-application-tenant-a*.
+func (o *OSClient) templateRolloverAlias(opensearchEndpoint, indexPattern string) (string, bool, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/_index_template?flat_settings=true", opensearchEndpoint), nil)
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", false, fmt.Errorf("got status code %d when fetching the index templates of %s", resp.StatusCode, indexPattern)
+	}
+	templates := &indexTemplates{}
+	if err := json.NewDecoder(resp.Body).Decode(templates); err != nil {
+		return "", false, err
+	}
+	var alias string
+	for _, template := range templates.IndexTemplates {
+		if !templateMatches(template.IndexTemplate.IndexPatterns, indexPattern) {
+			continue
+		}
+		if template.IndexTemplate.DataStream != nil {
+			return "", true, nil
+		}
+		if templateAlias, ok := template.IndexTemplate.Template.Settings[indexRolloverAliasSetting].(string); ok && templateAlias != "" {
+			alias = templateAlias
+		}
+	}
+	return alias, false, nil
+}
+
+// templateMatches returns true if one of the index patterns of an index template matches the index pattern
+func templateMatches(templatePatterns []string, indexPattern string) bool {
+	for _, templatePattern := range templatePatterns {
+		if matched, err := path.Match(templatePattern, indexPattern); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// dataStreamsExist returns true if there are data streams matching the index pattern
+func (o *OSClient) dataStreamsExist(opensearchEndpoint, indexPattern string) (bool, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/_data_stream/%s", opensearchEndpoint, indexPattern), nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("got status code %d when fetching data streams %s", resp.StatusCode, indexPattern)
+	}
+	dataStreams := &DataStreams{}
+	if err := json.NewDecoder(resp.Body).Decode(dataStreams); err != nil {
+		return false, err
+	}
+	return len(dataStreams.DataStreams) > 0, nil
+}
+
 func (o *OSClient) getPolicyByName(policyURL string) (*ISMPolicy, error) {
 	req, err := http.NewRequest("GET", policyURL, nil)
 	if err != nil {
@@ -477,22 +656,73 @@ func policyNeedsUpdate(policy *ISMPolicy, existingPolicy *ISMPolicy) bool {
 		diff.Diff(newPolicyDocument.ISMTemplate, oldPolicyDocument.ISMTemplate) != ""
 }
 
+// createRolloverAction translates the rollover policy into the conditions of an ISM rollover action.
+// Only the configured conditions are included. If none are configured, the default minimum index age is used
+// so that the index is not rolled over on every ISM run.
 func createRolloverAction(rollover *vmcontrollerv1.RolloverPolicy) map[string]interface{} {
 	rolloverAction := map[string]interface{}{}
 	if rollover.MinDocCount != nil {
-		rolloverAction["min_doc_count"] = *rollover.MinDocCount
+		rolloverAction[minDocCountKey] = *rollover.MinDocCount
 	}
 	if rollover.MinSize != nil {
-		rolloverAction["min_size"] = *rollover.MinSize
+		rolloverAction[minSizeKey] = *rollover.MinSize
 	}
-	var rolloverMinIndexAge = defaultRolloverIndexAge
 	if rollover.MinIndexAge != nil {
-		rolloverMinIndexAge = *rollover.MinIndexAge
+		rolloverAction[minIndexAgeKey] = *rollover.MinIndexAge
+	}
+	if len(rolloverAction) == 0 {
+		rolloverAction[minIndexAgeKey] = defaultRolloverIndexAge
 	}
-	rolloverAction[minIndexAgeKey] = rolloverMinIndexAge
 	return rolloverAction
 }
 
+// validateISMPolicy returns an error if the close age of the policy does not fit its lifecycle. Indices must not be
+// closed before they are old enough to be rolled over, which would close the write index, and must be closed before
+// they are old enough to be deleted.
+func validateISMPolicy(policy *vmcontrollerv1.IndexManagementPolicy) error {
+	if policy.MinCloseAge == nil {
+		return nil
+	}
+	closeAge, err := parseISMAge(*policy.MinCloseAge)
+	if err != nil {
+		return fmt.Errorf("invalid close age of policy %s: %v", policy.PolicyName, err)
+	}
+	if rolloverAge, ok := createRolloverAction(&policy.Rollover)[minIndexAgeKey].(string); ok {
+		minRolloverAge, err := parseISMAge(rolloverAge)
+		if err != nil {
+			return fmt.Errorf("invalid rollover age of policy %s: %v", policy.PolicyName, err)
+		}
+		if closeAge < minRolloverAge {
+			return fmt.Errorf("the close age %s of policy %s is smaller than its rollover age %s", *policy.MinCloseAge, policy.PolicyName, rolloverAge)
+		}
+	}
+	deleteAge := defaultMinIndexAge
+	if policy.MinIndexAge != nil {
+		deleteAge = *policy.MinIndexAge
+	}
+	minDeleteAge, err := parseISMAge(deleteAge)
+	if err != nil {
+		return fmt.Errorf("invalid minimum index age of policy %s: %v", policy.PolicyName, err)
+	}
+	if closeAge >= minDeleteAge {
+		return fmt.Errorf("the close age %s of policy %s must be smaller than its minimum index age %s", *policy.MinCloseAge, policy.PolicyName, deleteAge)
+	}
+	return nil
+}
+
+// parseISMAge returns the duration of an age of an ISM policy
+func parseISMAge(age string) (time.Duration, error) {
+	matches := ismAgeRegexp.FindStringSubmatch(age)
+	if matches == nil {
+		return 0, fmt.Errorf("age %s does not match %s", age, ismAgeRegexp.String())
+	}
+	number, err := strconv.ParseInt(matches[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(number) * ismAgeUnits[matches[2]], nil
+}
+
 func serializeIndexManagementPolicy(policy *ISMPolicy) ([]byte, error) {
 	return json.Marshal(policy)
 }
@@ -506,6 +736,57 @@ func toISMPolicy(policy *vmcontrollerv1.IndexManagementPolicy) *ISMPolicy {
 		minIndexAge = *policy.MinIndexAge
 	}
 
+	states := []PolicyState{
+		{
+			Name: "ingest",
+			Actions: []map[string]interface{}{
+				rolloverAction,
+			},
+			Transitions: []PolicyTransition{
+				{
+					StateName: "delete",
+					Conditions: &PolicyConditions{
+						MinIndexAge: minIndexAge,
+					},
+				},
+			},
+		},
+	}
+	// cold indices are closed before they are deleted, so they no longer use heap
+	if policy.MinCloseAge != nil {
+		states[0].Transitions[0] = PolicyTransition{
+			StateName: "close",
+			Conditions: &PolicyConditions{
+				MinIndexAge: *policy.MinCloseAge,
+			},
+		}
+		states = append(states, PolicyState{
+			Name: "close",
+			Actions: []map[string]interface{}{
+				{
+					"close": map[string]interface{}{},
+				},
+			},
+			Transitions: []PolicyTransition{
+				{
+					StateName: "delete",
+					Conditions: &PolicyConditions{
+						MinIndexAge: minIndexAge,
+					},
+				},
+			},
+		})
+	}
+	states = append(states, PolicyState{
+		Name: "delete",
+		Actions: []map[string]interface{}{
+			{
+				"delete": map[string]interface{}{},
+			},
+		},
+		Transitions: []PolicyTransition{},
+	})
+
 	return &ISMPolicy{
 		Policy: InlinePolicy{
 			DefaultState: "ingest",
@@ -518,31 +799,7 @@ func toISMPolicy(policy *vmcontrollerv1.IndexManagementPolicy) *ISMPolicy {
 					},
 				},
 			},
-			States: []PolicyState{
-				{
-					Name: "ingest",
-					Actions: []map[string]interface{}{
-						rolloverAction,
-					},
-					Transitions: []PolicyTransition{
-						{
-							StateName: "delete",
-							Conditions: &PolicyConditions{
-								MinIndexAge: minIndexAge,
-							},
-						},
-					},
-				},
-				{
-					Name: "delete",
-					Actions: []map[string]interface{}{
-						{
-							"delete": map[string]interface{}{},
-						},
-					},
-					Transitions: []PolicyTransition{},
-				},
-			},
+			States: states,
 		},
 	}
 }
@@ -204,6 +204,87 @@ func TestConfigureIndexManagementPluginHappyPath(t *testing.T) {
 	assert.NoError(t, <-ch)
 }
 
+// TestToISMPolicyCloseIndices Tests the ISM policy of a policy closing indices
+// GIVEN an ISM Policy with a close age and a delete age
+// WHEN I convert it to an OpenSearch ISM policy
+// THEN the indices are closed after the close age, then deleted after the delete age
+func TestToISMPolicyCloseIndices(t *testing.T) {
+	policy := createTestPolicy("30d", "1d", "*", "1gb", 1)
+	closeAge := "7d"
+	policy.MinCloseAge = &closeAge
+
+	states := toISMPolicy(policy).Policy.States
+	assert.Len(t, states, 3)
+	assert.Equal(t, "ingest", states[0].Name)
+	assert.Equal(t, "close", states[0].Transitions[0].StateName)
+	assert.Equal(t, "7d", states[0].Transitions[0].Conditions.MinIndexAge)
+	assert.Equal(t, "close", states[1].Name)
+	assert.Equal(t, []map[string]interface{}{{"close": map[string]interface{}{}}}, states[1].Actions)
+	assert.Equal(t, "delete", states[1].Transitions[0].StateName)
+	assert.Equal(t, "30d", states[1].Transitions[0].Conditions.MinIndexAge)
+	assert.Equal(t, "delete", states[2].Name)
+}
+
+// TestValidateISMPolicy Tests the validation of the close age of an ISM policy
+// GIVEN ISM policies with and without close ages
+// WHEN I call validateISMPolicy
+// THEN close ages smaller than the rollover age, or not smaller than the minimum index age, are rejected
+func TestValidateISMPolicy(t *testing.T) {
+	var tests = []struct {
+		name        string
+		age         string
+		rolloverAge string
+		closeAge    string
+		expectErr   bool
+	}{
+		{"no close age", "", "", "", false},
+		{"close between rollover and delete", "30d", "1d", "7d", false},
+		{"close at rollover", "30d", "1d", "24h", false},
+		{"close before rollover", "30d", "1d", "12h", true},
+		{"close before default rollover", "30d", "", "600s", true},
+		{"close at delete", "7d", "1d", "7d", true},
+		{"close after delete", "7d", "1d", "10d", true},
+		{"close after default delete", "", "1d", "8d", true},
+		{"close before default delete", "", "1d", "6d", false},
+		{"small units", "2000ms", "1000000micros", "1500000000nanos", false},
+		{"invalid close age", "30d", "1d", "7w", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// an empty age is not set
+			policy := &vmcontrollerv1.IndexManagementPolicy{PolicyName: "verrazzano-system"}
+			if tt.age != "" {
+				policy.MinIndexAge = &tt.age
+			}
+			if tt.rolloverAge != "" {
+				policy.Rollover.MinIndexAge = &tt.rolloverAge
+			}
+			if tt.closeAge != "" {
+				policy.MinCloseAge = &tt.closeAge
+			}
+			err := validateISMPolicy(policy)
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestToISMPolicyNoCloseIndices Tests the ISM policy of a policy not closing indices
+// GIVEN an ISM Policy without a close age
+// WHEN I convert it to an OpenSearch ISM policy
+// THEN the indices are deleted after the delete age without being closed
+func TestToISMPolicyNoCloseIndices(t *testing.T) {
+	states := toISMPolicy(createTestPolicy("30d", "1d", "*", "1gb", 1)).Policy.States
+	assert.Len(t, states, 2)
+	assert.Equal(t, "ingest", states[0].Name)
+	assert.Equal(t, "delete", states[0].Transitions[0].StateName)
+	assert.Equal(t, "30d", states[0].Transitions[0].Conditions.MinIndexAge)
+	assert.Equal(t, "delete", states[1].Name)
+}
+
 // TestGetPolicyByName Tests retrieving ISM policies by name
 // GIVEN an OpenSearch instance
 // WHEN I call getPolicyByName
@@ -769,3 +850,182 @@ func TestShouldAddOrRemoveDefaultPolicy(t *testing.T) {
 		assert.Equal(t, ok, tt.expectedResult)
 	}
 }
+
+// TestEnsureRolloverAlias Tests that the bootstrap index and write alias are created for rollover
+// GIVEN a policy with an index pattern and the index templates of OpenSearch
+// WHEN I call ensureRolloverAlias
+// THEN the bootstrap index with the write alias is created only if an index template configures the rollover alias,
+// the index pattern is not the one of data streams, and the alias does not already exist
+func TestEnsureRolloverAlias(t *testing.T) {
+	const rolloverTemplate = `{"index_templates": [{"name": "my-app", "index_template": {"index_patterns": ["my-app-*"],
+		"template": {"settings": {"index.plugins.index_state_management.rollover_alias": "my-app", "index.number_of_shards": "1"}}}}]}`
+	var tests = []struct {
+		name           string
+		indexPattern   string
+		templates      string
+		aliasStatus    int
+		dataStreams    bool
+		expectedCreate bool
+	}{
+		{
+			"bootstrap index is created when the alias does not exist",
+			"my-app-*",
+			rolloverTemplate,
+			http.StatusNotFound,
+			false,
+			true,
+		},
+		{
+			"bootstrap index is not created when the alias already exists",
+			"my-app-*",
+			rolloverTemplate,
+			http.StatusOK,
+			false,
+			false,
+		},
+		{
+			"bootstrap index is not created when no rollover alias is configured",
+			"my-app-*",
+			`{"index_templates": [{"name": "my-app", "index_template": {"index_patterns": ["my-app-*"],
+				"template": {"settings": {"index.number_of_shards": "1"}}}}]}`,
+			http.StatusNotFound,
+			false,
+			false,
+		},
+		{
+			"bootstrap index is not created when no index template matches",
+			"other-app-*",
+			rolloverTemplate,
+			http.StatusNotFound,
+			false,
+			false,
+		},
+		{
+			"bootstrap index is not created for the index pattern of data streams",
+			"verrazzano-application-tenant-a*",
+			`{"index_templates": [{"name": "verrazzano-data-stream", "index_template": {"index_patterns": ["verrazzano-application-*"],
+				"data_stream": {}, "template": {"settings": {"index.plugins.index_state_management.rollover_alias": "my-app"}}}}]}`,
+			http.StatusNotFound,
+			false,
+			false,
+		},
+		{
+			"bootstrap index is not created for existing data streams",
+			"my-app-*",
+			rolloverTemplate,
+			http.StatusNotFound,
+			true,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var createdIndex string
+			var createBody string
+			o := NewOSClient(statefulSetLister)
+			o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				switch {
+				case request.Method == "GET" && request.URL.Path == "/_index_template":
+					assert.Equal(t, "true", request.URL.Query().Get("flat_settings"))
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(tt.templates)),
+					}, nil
+				case request.Method == "GET":
+					if tt.dataStreams {
+						return &http.Response{
+							StatusCode: http.StatusOK,
+							Body:       io.NopCloser(strings.NewReader(dataStreamResponse)),
+						}, nil
+					}
+					return &http.Response{
+						StatusCode: http.StatusNotFound,
+						Body:       io.NopCloser(strings.NewReader("")),
+					}, nil
+				case request.Method == "HEAD":
+					assert.Equal(t, "/my-app", request.URL.Path)
+					return &http.Response{
+						StatusCode: tt.aliasStatus,
+						Body:       io.NopCloser(strings.NewReader("")),
+					}, nil
+				case request.Method == "PUT":
+					createdIndex = request.URL.Path
+					body, err := io.ReadAll(request.Body)
+					assert.NoError(t, err)
+					createBody = string(body)
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+					}, nil
+				}
+				return nil, errors.New("unexpected request")
+			}
+			policy := createTestPolicy("7d", "1d", tt.indexPattern, "1gb", 1)
+			assert.NoError(t, o.ensureRolloverAlias("http://localhost:9200", *policy))
+			if tt.expectedCreate {
+				assert.Equal(t, "/my-app-000001", createdIndex)
+				assert.JSONEq(t, `{"aliases": {"my-app": {"is_write_index": true}},
+					"settings": {"plugins.index_state_management.rollover_alias": "my-app"}}`, createBody)
+			} else {
+				assert.Empty(t, createdIndex)
+			}
+		})
+	}
+}
+
+// TestToISMPolicyRolloverConditions Tests the rollover conditions in the generated ISM policy JSON
+// GIVEN a rollover policy with a combination of configured conditions
+// WHEN I serialize the ISM policy generated from it
+// THEN the rollover action contains each configured condition and omits the unset ones
+func TestToISMPolicyRolloverConditions(t *testing.T) {
+	minIndexAge := "2d"
+	minSize := "5gb"
+	minDocCount := 1000
+	var tests = []struct {
+		name       string
+		rollover   vmcontrollerv1.RolloverPolicy
+		conditions map[string]interface{}
+	}{
+		{
+			"all conditions",
+			vmcontrollerv1.RolloverPolicy{MinIndexAge: &minIndexAge, MinSize: &minSize, MinDocCount: &minDocCount},
+			map[string]interface{}{minIndexAgeKey: "2d", minSizeKey: "5gb", minDocCountKey: float64(1000)},
+		},
+		{
+			"only min size",
+			vmcontrollerv1.RolloverPolicy{MinSize: &minSize},
+			map[string]interface{}{minSizeKey: "5gb"},
+		},
+		{
+			"only min doc count",
+			vmcontrollerv1.RolloverPolicy{MinDocCount: &minDocCount},
+			map[string]interface{}{minDocCountKey: float64(1000)},
+		},
+		{
+			"only min index age",
+			vmcontrollerv1.RolloverPolicy{MinIndexAge: &minIndexAge},
+			map[string]interface{}{minIndexAgeKey: "2d"},
+		},
+		{
+			"no conditions uses the default index age",
+			vmcontrollerv1.RolloverPolicy{},
+			map[string]interface{}{minIndexAgeKey: defaultRolloverIndexAge},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := &vmcontrollerv1.IndexManagementPolicy{
+				PolicyName:   "verrazzano-system",
+				IndexPattern: "verrazzano-system",
+				Rollover:     tt.rollover,
+			}
+			policyBytes, err := serializeIndexManagementPolicy(toISMPolicy(policy))
+			assert.NoError(t, err)
+			serialized := &ISMPolicy{}
+			assert.NoError(t, json.Unmarshal(policyBytes, serialized))
+			assert.Equal(t, tt.conditions, serialized.Policy.States[0].Actions[0]["rollover"])
+		})
+	}
+}
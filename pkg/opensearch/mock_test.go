@@ -0,0 +1,101 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	appslistersv1 "k8s.io/client-go/listers/apps/v1"
+)
+
+// mockOpenSearch responds to a request with a status code and a body, given the method, the path and the body of
+// the request
+type mockOpenSearch func(method, path string, body []byte) (int, string)
+
+// clusterSettingsUpdate is an update of the cluster settings, keyed by the persistent or the transient bucket
+type clusterSettingsUpdate map[string]map[string]interface{}
+
+// createReadyStatefulSetLister creates a StatefulSet lister with a ready OpenSearch StatefulSet of testvmo
+func createReadyStatefulSetLister() appslistersv1.StatefulSetLister {
+	return &simpleStatefulSetLister{kubeClient: fake.NewSimpleClientset(&appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: map[string]string{
+				constants.VMOLabel: testvmo.Name, constants.ComponentLabel: constants.ComponentOpenSearchValue,
+			},
+			Namespace: testvmo.Namespace,
+		},
+		Status: appsv1.StatefulSetStatus{
+			Replicas:      1,
+			ReadyReplicas: 1,
+		},
+	})}
+}
+
+// createMockOSClient creates an OSClient for which OpenSearch is ready, whose requests are answered by the given mock.
+// The method and the path of the requests it receives are recorded if requests is not nil
+func createMockOSClient(t *testing.T, requests *[]string, respond mockOpenSearch) *OSClient {
+	o := NewOSClient(createReadyStatefulSetLister())
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		if requests != nil {
+			*requests = append(*requests, request.Method+" "+request.URL.Path)
+		}
+		var body []byte
+		if request.Body != nil {
+			var err error
+			body, err = io.ReadAll(request.Body)
+			assert.NoError(t, err)
+		}
+		statusCode, responseBody := respond(request.Method, request.URL.Path, body)
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(strings.NewReader(responseBody)),
+		}, nil
+	}
+	return o
+}
+
+// createReadyOSClient creates an OSClient for which OpenSearch is ready, responding with the given status code to all
+// the requests, and recording the requests it receives with their bodies
+func createReadyOSClient(statusCode int, requests *[]*http.Request, bodies *[]string) *OSClient {
+	o := NewOSClient(createReadyStatefulSetLister())
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		*requests = append(*requests, request)
+		body := ""
+		if request.Body != nil {
+			b, _ := io.ReadAll(request.Body)
+			body = string(b)
+		}
+		*bodies = append(*bodies, body)
+		return &http.Response{
+			StatusCode: statusCode,
+			Body:       io.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+		}, nil
+	}
+	return o
+}
+
+// createClusterSettingsOSClient creates an OSClient for a cluster with the given persistent settings, recording the
+// cluster settings updates it receives
+func createClusterSettingsOSClient(t *testing.T, persistentSettings string, updates *[]clusterSettingsUpdate) *OSClient {
+	return createMockOSClient(t, nil, func(method, path string, body []byte) (int, string) {
+		assert.Equal(t, "/_cluster/settings", path)
+		if method == "GET" {
+			return http.StatusOK, `{"persistent": ` + persistentSettings + `, "transient": {}, "defaults": {}}`
+		}
+		assert.Equal(t, "PUT", method)
+		var update clusterSettingsUpdate
+		assert.NoError(t, json.Unmarshal(body, &update))
+		*updates = append(*updates, update)
+		return http.StatusOK, `{"acknowledged": true}`
+	})
+}
@@ -0,0 +1,309 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+// MonitorsResult is the result of configuring the alerting monitors of a VMI
+type MonitorsResult struct {
+	// Synced is true if the alerting monitors were synced, in which case Monitors are the monitors created by the VMO
+	Synced   bool
+	Monitors []vmcontrollerv1.AlertingMonitorStatus
+	Err      error
+}
+
+type createMonitorResponse struct {
+	ID string `json:"_id"`
+}
+
+type searchMonitorsResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID string `json:"_id"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// ConfigureMonitors creates or updates the alerting monitors of the VMI, and deletes the monitors created by the VMO
+// which were removed from the VMI. The monitors are identified by the IDs tracked in the VMI status.
+// The returned channel should be read for exactly one response, which has the monitors to track in the VMI status.
+func (o *OSClient) ConfigureMonitors(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan MonitorsResult {
+	ch := make(chan MonitorsResult)
+	// the VMI status is updated with the result, so the goroutine works on a copy
+	monitors := append([]vmcontrollerv1.AlertingMonitor{}, vmi.Spec.Opensearch.Monitors...)
+	trackedMonitors := append([]vmcontrollerv1.AlertingMonitorStatus{}, vmi.Status.Monitors...)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- MonitorsResult{}
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- MonitorsResult{}
+			return
+		}
+
+		statuses, err := o.syncMonitors(resources.GetOpenSearchHTTPEndpoint(vmi), monitors, trackedMonitors)
+		ch <- MonitorsResult{Synced: true, Monitors: statuses, Err: err}
+	}()
+
+	return ch
+}
+
+// syncMonitors creates the monitors which are not tracked yet, updates the tracked monitors which have changed,
+// and deletes the tracked monitors which are no longer expected. The monitors tracked once synced are returned,
+// also if an error occurred, so the monitors created before the error are not created again.
+func (o *OSClient) syncMonitors(opensearchEndpoint string, monitors []vmcontrollerv1.AlertingMonitor, trackedMonitors []vmcontrollerv1.AlertingMonitorStatus) ([]vmcontrollerv1.AlertingMonitorStatus, error) {
+	tracked := map[string]vmcontrollerv1.AlertingMonitorStatus{}
+	for _, status := range trackedMonitors {
+		tracked[status.Name] = status
+	}
+
+	expectedMonitorMap := map[string]bool{}
+	for _, monitor := range monitors {
+		expectedMonitorMap[monitor.Name] = true
+		status, isTracked := tracked[monitor.Name]
+		newStatus, err := o.syncMonitor(opensearchEndpoint, monitor, status, isTracked)
+		if err != nil {
+			return toMonitorStatuses(tracked), err
+		}
+		tracked[monitor.Name] = *newStatus
+	}
+
+	for name, status := range tracked {
+		if expectedMonitorMap[name] {
+			continue
+		}
+		if err := o.deleteMonitor(opensearchEndpoint, status.ID); err != nil {
+			return toMonitorStatuses(tracked), err
+		}
+		delete(tracked, name)
+	}
+	return toMonitorStatuses(tracked), nil
+}
+
+// syncMonitor creates a monitor if it is not tracked or no longer exists, and updates a tracked monitor if its
+// definition has changed. A monitor with the same name which is not tracked, because the VMI status could not be
+// updated after it was created, is updated and tracked instead of creating a duplicate.
+func (o *OSClient) syncMonitor(opensearchEndpoint string, monitor vmcontrollerv1.AlertingMonitor, status vmcontrollerv1.AlertingMonitorStatus, isTracked bool) (*vmcontrollerv1.AlertingMonitorStatus, error) {
+	body, checksum, err := toMonitor(monitor)
+	if err != nil {
+		return nil, err
+	}
+	if isTracked {
+		var exists bool
+		if status.Checksum == checksum {
+			exists, err = o.monitorExists(opensearchEndpoint, status.ID)
+		} else {
+			exists, err = o.updateMonitor(opensearchEndpoint, status.ID, body)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return &vmcontrollerv1.AlertingMonitorStatus{Name: monitor.Name, ID: status.ID, Checksum: checksum}, nil
+		}
+	}
+	id, err := o.findMonitor(opensearchEndpoint, monitor.Name)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" {
+		exists, err := o.updateMonitor(opensearchEndpoint, id, body)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return &vmcontrollerv1.AlertingMonitorStatus{Name: monitor.Name, ID: id, Checksum: checksum}, nil
+		}
+	}
+	id, err = o.createMonitor(opensearchEndpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	return &vmcontrollerv1.AlertingMonitorStatus{Name: monitor.Name, ID: id, Checksum: checksum}, nil
+}
+
+// findMonitor returns the ID of the monitor with the given name, or an empty string if there is none
+func (o *OSClient) findMonitor(opensearchEndpoint, name string) (string, error) {
+	query, err := json.Marshal(map[string]interface{}{
+		"size": 1,
+		"query": map[string]interface{}{
+			"term": map[string]interface{}{
+				"monitor.name.keyword": name,
+			},
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/_plugins/_alerting/monitors/_search", opensearchEndpoint)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(query))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	// the alerting config index does not exist until the first monitor is created
+	if resp.StatusCode == http.StatusNotFound {
+		return "", nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("got status code %d when searching alerting monitor %s", resp.StatusCode, name)
+	}
+	found := searchMonitorsResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&found); err != nil {
+		return "", err
+	}
+	if len(found.Hits.Hits) == 0 {
+		return "", nil
+	}
+	return found.Hits.Hits[0].ID, nil
+}
+
+// monitorExists returns true if the monitor with the given ID exists
+func (o *OSClient) monitorExists(opensearchEndpoint, id string) (bool, error) {
+	url := fmt.Sprintf("%s/_plugins/_alerting/monitors/%s", opensearchEndpoint, id)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("got status code %d when querying alerting monitor %s", resp.StatusCode, id)
+	}
+	return true, nil
+}
+
+// createMonitor creates a monitor, and returns its ID
+func (o *OSClient) createMonitor(opensearchEndpoint string, monitor map[string]interface{}) (string, error) {
+	body, err := json.Marshal(monitor)
+	if err != nil {
+		return "", err
+	}
+	url := fmt.Sprintf("%s/_plugins/_alerting/monitors", opensearchEndpoint)
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return "", fmt.Errorf("got status code %d when creating alerting monitor %v", resp.StatusCode, monitor["name"])
+	}
+	created := createMonitorResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return "", err
+	}
+	if created.ID == "" {
+		return "", fmt.Errorf("no ID returned when creating alerting monitor %v", monitor["name"])
+	}
+	return created.ID, nil
+}
+
+// updateMonitor updates the monitor with the given ID, and returns false if the monitor does not exist
+func (o *OSClient) updateMonitor(opensearchEndpoint, id string, monitor map[string]interface{}) (bool, error) {
+	body, err := json.Marshal(monitor)
+	if err != nil {
+		return false, err
+	}
+	url := fmt.Sprintf("%s/_plugins/_alerting/monitors/%s", opensearchEndpoint, id)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("got status code %d when updating alerting monitor %s", resp.StatusCode, id)
+	}
+	return true, nil
+}
+
+func (o *OSClient) deleteMonitor(opensearchEndpoint, id string) error {
+	url := fmt.Sprintf("%s/_plugins/_alerting/monitors/%s", opensearchEndpoint, id)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting alerting monitor %s", resp.StatusCode, id)
+	}
+	return nil
+}
+
+// toMonitor creates the monitor document of a VMI alerting monitor, named after the VMI monitor, and returns it
+// with the checksum of the monitor definition
+func toMonitor(monitor vmcontrollerv1.AlertingMonitor) (map[string]interface{}, string, error) {
+	if monitor.Name == "" {
+		return nil, "", fmt.Errorf("alerting monitor name must be specified")
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal([]byte(monitor.Monitor), &m); err != nil {
+		return nil, "", fmt.Errorf("definition of alerting monitor %s is not a JSON object: %v", monitor.Name, err)
+	}
+	if m == nil {
+		return nil, "", fmt.Errorf("definition of alerting monitor %s is not a JSON object", monitor.Name)
+	}
+	m["name"] = monitor.Name
+	if _, ok := m["type"]; !ok {
+		m["type"] = "monitor"
+	}
+	checksum := sha256.Sum256([]byte(monitor.Monitor))
+	return m, hex.EncodeToString(checksum[:]), nil
+}
+
+// toMonitorStatuses returns the tracked monitors sorted by name, or nil if no monitor is tracked
+func toMonitorStatuses(tracked map[string]vmcontrollerv1.AlertingMonitorStatus) []vmcontrollerv1.AlertingMonitorStatus {
+	if len(tracked) == 0 {
+		return nil
+	}
+	var statuses []vmcontrollerv1.AlertingMonitorStatus
+	for _, status := range tracked {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
@@ -0,0 +1,190 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+const testMonitor = `{"monitor_type": "query_level_monitor", "enabled": true, "schedule": {"period": {"interval": 1, "unit": "MINUTES"}}}`
+
+// createMonitorsOSClient creates an OSClient for a cluster with the given existing monitor IDs, and the IDs of the
+// monitors found by name, recording the requests it receives and the bodies of the monitors it receives
+func createMonitorsOSClient(t *testing.T, existingIDs map[string]bool, namedIDs map[string]string, requests *[]string, bodies *[]map[string]interface{}) *OSClient {
+	return createMockOSClient(t, requests, func(method, path string, body []byte) (int, string) {
+		id := strings.TrimPrefix(path, "/_plugins/_alerting/monitors/")
+		if id == "_search" {
+			var query struct {
+				Query struct {
+					Term map[string]string `json:"term"`
+				} `json:"query"`
+			}
+			assert.NoError(t, json.Unmarshal(body, &query))
+			hits := "[]"
+			if namedID, ok := namedIDs[query.Query.Term["monitor.name.keyword"]]; ok {
+				hits = `[{"_id": "` + namedID + `"}]`
+			}
+			return http.StatusOK, `{"hits": {"hits": ` + hits + `}}`
+		}
+		if body != nil {
+			var monitor map[string]interface{}
+			assert.NoError(t, json.Unmarshal(body, &monitor))
+			*bodies = append(*bodies, monitor)
+		}
+		switch {
+		case method == "POST":
+			return http.StatusCreated, `{"_id": "created-id", "_version": 1}`
+		case !existingIDs[id]:
+			return http.StatusNotFound, `{}`
+		}
+		return http.StatusOK, `{"_id": "` + id + `"}`
+	})
+}
+
+// testMonitorChecksum returns the checksum of a monitor definition
+func testMonitorChecksum(t *testing.T, definition string) string {
+	_, checksum, err := toMonitor(vmcontrollerv1.AlertingMonitor{Name: "monitor", Monitor: definition})
+	assert.NoError(t, err)
+	return checksum
+}
+
+// TestConfigureMonitorsDisabled Tests that alerting monitors are not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureMonitors
+// THEN OpenSearch is not called and the monitors are not synced
+func TestConfigureMonitorsDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	result := <-o.ConfigureMonitors(&vmcontrollerv1.VerrazzanoMonitoringInstance{})
+	assert.NoError(t, result.Err)
+	assert.False(t, result.Synced)
+}
+
+// TestSyncMonitors Tests syncing the alerting monitors of a VMI
+// GIVEN a new monitor, an unchanged and a changed tracked monitor, and a tracked monitor removed from the VMI
+// WHEN I call syncMonitors
+// THEN the new monitor is created, the unchanged monitor is only checked, the changed monitor is updated,
+// the removed monitor is deleted, and the monitors are tracked with their IDs
+func TestSyncMonitors(t *testing.T) {
+	var requests []string
+	var bodies []map[string]interface{}
+	o := createMonitorsOSClient(t, map[string]bool{"unchanged-id": true, "changed-id": true, "removed-id": true}, nil, &requests, &bodies)
+
+	changedMonitor := `{"monitor_type": "query_level_monitor", "enabled": false}`
+	statuses, err := o.syncMonitors("http://localhost:9200", []vmcontrollerv1.AlertingMonitor{
+		{Name: "new", Monitor: testMonitor},
+		{Name: "unchanged", Monitor: testMonitor},
+		{Name: "changed", Monitor: changedMonitor},
+	}, []vmcontrollerv1.AlertingMonitorStatus{
+		{Name: "unchanged", ID: "unchanged-id", Checksum: testMonitorChecksum(t, testMonitor)},
+		{Name: "changed", ID: "changed-id", Checksum: testMonitorChecksum(t, testMonitor)},
+		{Name: "removed", ID: "removed-id", Checksum: testMonitorChecksum(t, testMonitor)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"POST /_plugins/_alerting/monitors/_search",
+		"POST /_plugins/_alerting/monitors",
+		"GET /_plugins/_alerting/monitors/unchanged-id",
+		"PUT /_plugins/_alerting/monitors/changed-id",
+		"DELETE /_plugins/_alerting/monitors/removed-id",
+	}, requests)
+	assert.Len(t, bodies, 2)
+	assert.Equal(t, "new", bodies[0]["name"])
+	assert.Equal(t, "monitor", bodies[0]["type"])
+	assert.Equal(t, "query_level_monitor", bodies[0]["monitor_type"])
+	assert.Equal(t, "changed", bodies[1]["name"])
+	assert.Equal(t, false, bodies[1]["enabled"])
+	assert.Equal(t, []vmcontrollerv1.AlertingMonitorStatus{
+		{Name: "changed", ID: "changed-id", Checksum: testMonitorChecksum(t, changedMonitor)},
+		{Name: "new", ID: "created-id", Checksum: testMonitorChecksum(t, testMonitor)},
+		{Name: "unchanged", ID: "unchanged-id", Checksum: testMonitorChecksum(t, testMonitor)},
+	}, statuses)
+}
+
+// TestSyncMonitorsRecreated Tests syncing the tracked alerting monitors of a VMI which were deleted from OpenSearch
+// GIVEN an unchanged and a changed tracked monitor which no longer exist, and a VMI without monitors tracking none
+// WHEN I call syncMonitors
+// THEN both monitors are created again and tracked with their new IDs, and no monitor is tracked for the VMI without monitors
+func TestSyncMonitorsRecreated(t *testing.T) {
+	var requests []string
+	var bodies []map[string]interface{}
+	o := createMonitorsOSClient(t, map[string]bool{}, nil, &requests, &bodies)
+
+	statuses, err := o.syncMonitors("http://localhost:9200", []vmcontrollerv1.AlertingMonitor{
+		{Name: "unchanged", Monitor: testMonitor},
+		{Name: "changed", Monitor: `{"monitor_type": "bucket_level_monitor"}`},
+	}, []vmcontrollerv1.AlertingMonitorStatus{
+		{Name: "unchanged", ID: "unchanged-id", Checksum: testMonitorChecksum(t, testMonitor)},
+		{Name: "changed", ID: "changed-id", Checksum: testMonitorChecksum(t, testMonitor)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"GET /_plugins/_alerting/monitors/unchanged-id",
+		"POST /_plugins/_alerting/monitors/_search",
+		"POST /_plugins/_alerting/monitors",
+		"PUT /_plugins/_alerting/monitors/changed-id",
+		"POST /_plugins/_alerting/monitors/_search",
+		"POST /_plugins/_alerting/monitors",
+	}, requests)
+	assert.Len(t, statuses, 2)
+	for _, status := range statuses {
+		assert.Equal(t, "created-id", status.ID)
+	}
+
+	statuses, err = o.syncMonitors("http://localhost:9200", nil, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, statuses)
+}
+
+// TestSyncMonitorsUntrackedExisting Tests syncing alerting monitors which were created but not tracked
+// GIVEN a new monitor of the VMI which already exists in OpenSearch, because the VMI status update failed after it was created
+// WHEN I call syncMonitors
+// THEN the existing monitor is found by name, updated and tracked with its ID, and no monitor is created
+func TestSyncMonitorsUntrackedExisting(t *testing.T) {
+	var requests []string
+	var bodies []map[string]interface{}
+	o := createMonitorsOSClient(t, map[string]bool{"orphan-id": true}, map[string]string{"new": "orphan-id"}, &requests, &bodies)
+
+	statuses, err := o.syncMonitors("http://localhost:9200", []vmcontrollerv1.AlertingMonitor{
+		{Name: "new", Monitor: testMonitor},
+	}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"POST /_plugins/_alerting/monitors/_search",
+		"PUT /_plugins/_alerting/monitors/orphan-id",
+	}, requests)
+	assert.Len(t, bodies, 1)
+	assert.Equal(t, "new", bodies[0]["name"])
+	assert.Equal(t, []vmcontrollerv1.AlertingMonitorStatus{
+		{Name: "new", ID: "orphan-id", Checksum: testMonitorChecksum(t, testMonitor)},
+	}, statuses)
+}
+
+// TestSyncMonitorsInvalid Tests syncing an invalid alerting monitor
+// GIVEN a tracked monitor, and a new monitor whose definition is not a JSON object
+// WHEN I call syncMonitors
+// THEN an error is returned, and the tracked monitor is still tracked
+func TestSyncMonitorsInvalid(t *testing.T) {
+	var requests []string
+	var bodies []map[string]interface{}
+	o := createMonitorsOSClient(t, map[string]bool{"tracked-id": true}, nil, &requests, &bodies)
+
+	tracked := []vmcontrollerv1.AlertingMonitorStatus{{Name: "tracked", ID: "tracked-id", Checksum: testMonitorChecksum(t, testMonitor)}}
+	statuses, err := o.syncMonitors("http://localhost:9200", []vmcontrollerv1.AlertingMonitor{
+		{Name: "invalid", Monitor: `[{"monitor_type": "query_level_monitor"}]`},
+		{Name: "tracked", Monitor: testMonitor},
+	}, tracked)
+	assert.Error(t, err)
+	assert.Equal(t, tracked, statuses)
+	assert.Empty(t, requests)
+}
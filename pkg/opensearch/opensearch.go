@@ -5,6 +5,7 @@ package opensearch
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
@@ -25,6 +26,7 @@ type (
 		httpClient        *http.Client
 		DoHTTP            func(request *http.Request) (*http.Response, error)
 		statefulSetLister appslistersv1.StatefulSetLister
+		skipHealthChecks  bool
 	}
 )
 
@@ -45,6 +47,26 @@ func NewOSClient(statefulSetLister appslistersv1.StatefulSetLister) *OSClient {
 	return o
 }
 
+// SkipHealthChecks makes the health checks of the client succeed without querying OpenSearch, for development clusters
+// without a real OpenSearch. The health gates protecting the cluster from data loss are bypassed, so this must never be
+// used in production.
+func (o *OSClient) SkipHealthChecks() {
+	o.skipHealthChecks = true
+}
+
+// WithContext returns a copy of the client whose requests are bound to the context, so they are cancelled with it
+func (o *OSClient) WithContext(ctx context.Context) *OSClient {
+	doHTTP := o.DoHTTP
+	return &OSClient{
+		httpClient:        o.httpClient,
+		statefulSetLister: o.statefulSetLister,
+		skipHealthChecks:  o.skipHealthChecks,
+		DoHTTP: func(request *http.Request) (*http.Response, error) {
+			return doHTTP(request.WithContext(ctx))
+		},
+	}
+}
+
 // IsDataResizable returns an error unless these conditions of the OpenSearch cluster are met
 // - at least 2 data nodes
 // - 'green' health
@@ -92,6 +114,10 @@ func (o *OSClient) ConfigureISM(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance
 				ch <- err
 				return
 			}
+			if err := o.ensureRolloverAlias(opensearchEndpoint, policy); err != nil {
+				ch <- err
+				return
+			}
 		}
 
 		ch <- o.cleanupPolicies(opensearchEndpoint, vmi.Spec.Opensearch.Policies)
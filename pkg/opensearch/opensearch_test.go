@@ -0,0 +1,52 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestWithContextDeadlineExceeded Tests that the requests of a client bound to a context are cancelled with it
+// GIVEN an OpenSearch client bound to a context with a deadline, and an OpenSearch cluster which does not respond
+// WHEN the deadline is exceeded while a request is in progress
+// THEN the request is cancelled with a deadline exceeded error
+func TestWithContextDeadlineExceeded(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		<-request.Context().Done()
+		return nil, request.Context().Err()
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := o.WithContext(ctx).getClusterSettings("http://localhost:9200")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+// TestWithContext Tests that a client bound to a context sends the requests of the original client
+// GIVEN an OpenSearch client bound to a context
+// WHEN a request is made
+// THEN the request is sent with the original client, bound to the context
+func TestWithContext(t *testing.T) {
+	type contextKey struct{}
+	ctx := context.WithValue(context.Background(), contextKey{}, "reconcile")
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		assert.Equal(t, "reconcile", request.Context().Value(contextKey{}))
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"persistent": {}}`)),
+		}, nil
+	}
+
+	_, err := o.WithContext(ctx).getClusterSettings("http://localhost:9200")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,186 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+type (
+	// IngestPipeline is the document of an OpenSearch ingest pipeline
+	IngestPipeline struct {
+		Description string        `json:"description"`
+		Processors  []interface{} `json:"processors"`
+	}
+)
+
+// Descriptor to identify ingest pipelines as being managed by the VMI
+const vmiManagedPipeline = "__vmi-managed__"
+
+// ConfigureIngestPipelines creates or updates the ingest pipelines of the VMI, and deletes the VMI managed
+// ingest pipelines which were removed from the VMI.
+// The returned channel should be read for exactly one response, which tells whether the ingest pipelines were configured.
+func (o *OSClient) ConfigureIngestPipelines(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		ch <- o.syncIngestPipelines(resources.GetOpenSearchHTTPEndpoint(vmi), vmi.Spec.Opensearch.IngestPipelines)
+	}()
+
+	return ch
+}
+
+// syncIngestPipelines puts the pipelines which do not exist or have changed, and deletes the VMI managed pipelines
+// which are no longer expected
+func (o *OSClient) syncIngestPipelines(opensearchEndpoint string, pipelines []vmcontrollerv1.IngestPipeline) error {
+	existingPipelines, err := o.getAllIngestPipelines(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+
+	expectedPipelineMap := map[string]bool{}
+	for _, pipeline := range pipelines {
+		expectedPipelineMap[pipeline.Name] = true
+		ingestPipeline, err := toIngestPipeline(pipeline)
+		if err != nil {
+			return err
+		}
+		if existingPipeline, ok := existingPipelines[pipeline.Name]; ok && reflect.DeepEqual(existingPipeline, *ingestPipeline) {
+			continue
+		}
+		if err := o.putIngestPipeline(opensearchEndpoint, pipeline.Name, ingestPipeline); err != nil {
+			return err
+		}
+	}
+
+	// A pipeline is eligible for deletion if it is marked as VMI managed, but the VMI no longer
+	// has a pipeline entry for it
+	for name, pipeline := range existingPipelines {
+		if pipeline.Description == vmiManagedPipeline && !expectedPipelineMap[name] {
+			if err := o.deleteIngestPipeline(opensearchEndpoint, name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// getAllIngestPipelines returns the ingest pipelines of the cluster, keyed by name
+func (o *OSClient) getAllIngestPipelines(opensearchEndpoint string) (map[string]IngestPipeline, error) {
+	url := fmt.Sprintf("%s/_ingest/pipeline", opensearchEndpoint)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	pipelines := map[string]IngestPipeline{}
+	// OpenSearch responds with not found if there are no pipelines
+	if resp.StatusCode == http.StatusNotFound {
+		return pipelines, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got status code %d when querying ingest pipelines", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&pipelines); err != nil {
+		return nil, err
+	}
+	return pipelines, nil
+}
+
+// ingestPipelineExists returns true if the cluster has the ingest pipeline
+func (o *OSClient) ingestPipelineExists(opensearchEndpoint, name string) (bool, error) {
+	url := fmt.Sprintf("%s/_ingest/pipeline/%s", opensearchEndpoint, name)
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("got status code %d when querying ingest pipeline %s", resp.StatusCode, name)
+	}
+}
+
+func (o *OSClient) putIngestPipeline(opensearchEndpoint, name string, pipeline *IngestPipeline) error {
+	body, err := json.Marshal(pipeline)
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/_ingest/pipeline/%s", opensearchEndpoint, name)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when putting ingest pipeline %s", resp.StatusCode, name)
+	}
+	return nil
+}
+
+func (o *OSClient) deleteIngestPipeline(opensearchEndpoint, name string) error {
+	url := fmt.Sprintf("%s/_ingest/pipeline/%s", opensearchEndpoint, name)
+	req, err := http.NewRequest("DELETE", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("got status code %d when deleting ingest pipeline %s", resp.StatusCode, name)
+	}
+	return nil
+}
+
+// toIngestPipeline creates the pipeline document of a VMI ingest pipeline, marked as VMI managed
+func toIngestPipeline(pipeline vmcontrollerv1.IngestPipeline) (*IngestPipeline, error) {
+	if pipeline.Name == "" {
+		return nil, fmt.Errorf("ingest pipeline name must be specified")
+	}
+	var processors []interface{}
+	if err := json.Unmarshal([]byte(pipeline.Processors), &processors); err != nil {
+		return nil, fmt.Errorf("processors of ingest pipeline %s are not a JSON array: %v", pipeline.Name, err)
+	}
+	return &IngestPipeline{
+		Description: vmiManagedPipeline,
+		Processors:  processors,
+	}, nil
+}
@@ -0,0 +1,154 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+const (
+	testRenameProcessors  = `[{"rename": {"field": "kubernetes.pod_name", "target_field": "pod"}}]`
+	testExistingPipelines = `{
+  "rename-pod": {
+    "description": "__vmi-managed__",
+    "processors": [{"rename": {"field": "kubernetes.pod_name", "target_field": "pod"}}]
+  },
+  "removed": {
+    "description": "__vmi-managed__",
+    "processors": [{"remove": {"field": "stream"}}]
+  },
+  "user-pipeline": {
+    "description": "created by a user",
+    "processors": [{"remove": {"field": "tag"}}]
+  }
+}`
+)
+
+// createPipelineOSClient creates an OSClient for which OpenSearch is ready and has the given ingest pipelines, recording
+// the method and path of the requests it receives and their bodies
+func createPipelineOSClient(t *testing.T, existingPipelines map[string]bool, requests *[]string, bodies *[]string) *OSClient {
+	return createMockOSClient(t, requests, func(method, path string, body []byte) (int, string) {
+		*bodies = append(*bodies, string(body))
+		if method == "GET" && strings.HasPrefix(path, "/_ingest/pipeline/") && !existingPipelines[strings.TrimPrefix(path, "/_ingest/pipeline/")] {
+			return http.StatusNotFound, `{}`
+		}
+		return http.StatusOK, `{"acknowledged": true}`
+	})
+}
+
+// TestConfigureIngestPipelinesDisabled Tests that ingest pipelines are not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureIngestPipelines
+// THEN OpenSearch is not called and no error is returned
+func TestConfigureIngestPipelinesDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-o.ConfigureIngestPipelines(&vmcontrollerv1.VerrazzanoMonitoringInstance{}))
+}
+
+// TestSyncIngestPipelines Tests syncing the ingest pipelines of a VMI
+// GIVEN an unchanged, a new and a removed VMI managed pipeline, and a pipeline not managed by the VMI
+// WHEN I call syncIngestPipelines
+// THEN only the new pipeline is put, only the removed pipeline is deleted, and the other pipelines are left untouched
+func TestSyncIngestPipelines(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	var puts, deletes []string
+	var putPipeline IngestPipeline
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		switch request.Method {
+		case "GET":
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(testExistingPipelines)),
+			}, nil
+		case "PUT":
+			puts = append(puts, request.URL.Path)
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&putPipeline))
+		case "DELETE":
+			deletes = append(deletes, request.URL.Path)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+		}, nil
+	}
+
+	err := o.syncIngestPipelines("http://localhost:9200", []vmcontrollerv1.IngestPipeline{
+		{Name: "rename-pod", Processors: testRenameProcessors},
+		{Name: "geoip", Processors: `[{"geoip": {"field": "client_ip"}}]`},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/_ingest/pipeline/geoip"}, puts)
+	assert.Equal(t, vmiManagedPipeline, putPipeline.Description)
+	assert.Len(t, putPipeline.Processors, 1)
+	assert.Equal(t, []string{"/_ingest/pipeline/removed"}, deletes)
+}
+
+// TestSyncIngestPipelinesNoExistingPipelines Tests syncing the ingest pipelines of a VMI when the cluster has no pipelines
+// GIVEN a VMI pipeline and an OpenSearch cluster responding with not found as it has no pipelines
+// WHEN I call syncIngestPipelines
+// THEN the pipeline is put
+func TestSyncIngestPipelinesNoExistingPipelines(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	var puts int
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		if request.Method == "GET" {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+			}, nil
+		}
+		puts++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"acknowledged": true}`)),
+		}, nil
+	}
+
+	err := o.syncIngestPipelines("http://localhost:9200", []vmcontrollerv1.IngestPipeline{
+		{Name: "rename-pod", Processors: testRenameProcessors},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, puts)
+}
+
+// TestSyncIngestPipelinesInvalid Tests syncing an invalid ingest pipeline
+// GIVEN a VMI pipeline whose processors are not a JSON array, and a pipeline which is rejected by OpenSearch
+// WHEN I call syncIngestPipelines
+// THEN an error is returned
+func TestSyncIngestPipelinesInvalid(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		if request.Method == "GET" {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader("{}")),
+			}, nil
+		}
+		return &http.Response{
+			StatusCode: http.StatusBadRequest,
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+
+	err := o.syncIngestPipelines("http://localhost:9200", []vmcontrollerv1.IngestPipeline{
+		{Name: "invalid", Processors: `{"rename": {}}`},
+	})
+	assert.Error(t, err)
+
+	err = o.syncIngestPipelines("http://localhost:9200", []vmcontrollerv1.IngestPipeline{
+		{Name: "rejected", Processors: `[{"unknown": {}}]`},
+	})
+	assert.Error(t, err)
+}
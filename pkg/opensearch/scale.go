@@ -0,0 +1,233 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+type (
+	// ClusterSettings are the flat cluster settings, including the defaults
+	ClusterSettings struct {
+		Persistent map[string]interface{} `json:"persistent"`
+		Transient  map[string]interface{} `json:"transient"`
+		Defaults   map[string]interface{} `json:"defaults"`
+	}
+
+	// NodesFSStats are the file system stats of the nodes
+	NodesFSStats struct {
+		Nodes map[string]NodeFSStats `json:"nodes"`
+	}
+
+	NodeFSStats struct {
+		Name  string   `json:"name"`
+		Roles []string `json:"roles"`
+		FS    struct {
+			Total struct {
+				TotalInBytes     int64 `json:"total_in_bytes"`
+				AvailableInBytes int64 `json:"available_in_bytes"`
+			} `json:"total"`
+		} `json:"fs"`
+	}
+
+	// NodeAllocation is the shard allocation of a node, as returned by _cat/allocation
+	NodeAllocation struct {
+		Shards string `json:"shards"`
+		Node   string `json:"node"`
+	}
+)
+
+const (
+	diskThresholdEnabledSetting = "cluster.routing.allocation.disk.threshold_enabled"
+	lowDiskWatermarkSetting     = "cluster.routing.allocation.disk.watermark.low"
+	defaultLowDiskWatermark     = "85%"
+	dataRole                    = "data"
+	unassignedNode              = "UNASSIGNED"
+)
+
+var byteSizeRegex = regexp.MustCompile(`^([0-9.]+)\s*(b|kb|mb|gb|tb|pb)?$`)
+
+var byteSizeUnits = map[string]float64{
+	"":   1,
+	"b":  1,
+	"kb": 1 << 10,
+	"mb": 1 << 20,
+	"gb": 1 << 30,
+	"tb": 1 << 40,
+	"pb": 1 << 50,
+}
+
+// ValidateDataScaleUp returns an error unless the OpenSearch cluster can allocate shards to the new data nodes:
+// - 'green' or 'yellow' health: adding data nodes is the usual remedy for replica shards which cannot be allocated
+// - the share of the data of the existing data nodes each data node holds once the shards are rebalanced, fits on the
+// new data nodes below the low disk watermark, above which no shards are allocated to a node.
+// The disk usage is not checked if newNodeCapacityBytes is zero, i.e. the new data nodes have no PVC, or the disk thresholds are disabled.
+func (o *OSClient) ValidateDataScaleUp(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, newDataNodes int, newNodeCapacityBytes int64) error {
+	if err := o.opensearchAvailable(vmo); err != nil {
+		return err
+	}
+	if newDataNodes < 1 || newNodeCapacityBytes <= 0 {
+		return nil
+	}
+
+	opensearchEndpoint := resources.GetOpenSearchHTTPEndpoint(vmo)
+	settings, err := o.getClusterSettings(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	if thresholdEnabled := clusterSetting(settings, diskThresholdEnabledSetting, "true"); strings.EqualFold(thresholdEnabled, "false") {
+		return nil
+	}
+	watermark := clusterSetting(settings, lowDiskWatermarkSetting, defaultLowDiskWatermark)
+	allowedBytes, err := allowedBytesBelowWatermark(watermark, newNodeCapacityBytes)
+	if err != nil {
+		return err
+	}
+
+	stats, err := o.getNodesFSStats(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	var existingDataNodes int
+	var usedBytes int64
+	for _, node := range stats.Nodes {
+		if !resources.SliceContains(node.Roles, dataRole) {
+			continue
+		}
+		existingDataNodes++
+		usedBytes += node.FS.Total.TotalInBytes - node.FS.Total.AvailableInBytes
+	}
+	sharePerNode := usedBytes / int64(existingDataNodes+newDataNodes)
+	if sharePerNode > allowedBytes {
+		return fmt.Errorf("new data nodes with %d bytes of storage cannot hold their share of %d bytes of the data below the low disk watermark %s",
+			newNodeCapacityBytes, sharePerNode, watermark)
+	}
+	return nil
+}
+
+// VerifyDataShardsRelocated returns an error unless the shards have been relocated after adding data nodes:
+// no shards are relocating and every data node holds shards.
+func (o *OSClient) VerifyDataShardsRelocated(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	clusterHealth, err := o.getOpenSearchClusterHealth(vmo)
+	if err != nil {
+		return err
+	}
+	if clusterHealth.RelocatingShards > 0 {
+		return fmt.Errorf("%d shards are relocating", clusterHealth.RelocatingShards)
+	}
+
+	url := resources.GetOpenSearchHTTPEndpoint(vmo) + "/_cat/allocation?format=json"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get shard allocation: %s", resp.Status)
+	}
+	var allocations []NodeAllocation
+	if err := json.NewDecoder(resp.Body).Decode(&allocations); err != nil {
+		return err
+	}
+	for _, allocation := range allocations {
+		if allocation.Node == unassignedNode {
+			continue
+		}
+		if allocation.Shards == "0" {
+			return fmt.Errorf("no shards are allocated to data node %s", allocation.Node)
+		}
+	}
+	return nil
+}
+
+func (o *OSClient) getClusterSettings(opensearchEndpoint string) (*ClusterSettings, error) {
+	url := opensearchEndpoint + "/_cluster/settings?include_defaults=true&flat_settings=true"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get cluster settings: %s", resp.Status)
+	}
+	settings := &ClusterSettings{}
+	if err := json.NewDecoder(resp.Body).Decode(settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+func (o *OSClient) getNodesFSStats(opensearchEndpoint string) (*NodesFSStats, error) {
+	url := opensearchEndpoint + "/_nodes/stats/fs"
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get node stats: %s", resp.Status)
+	}
+	stats := &NodesFSStats{}
+	if err := json.NewDecoder(resp.Body).Decode(stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// clusterSetting returns the value of a flat cluster setting, the transient settings take precedence over the
+// persistent settings, which take precedence over the defaults
+func clusterSetting(settings *ClusterSettings, name, defaultValue string) string {
+	for _, values := range []map[string]interface{}{settings.Transient, settings.Persistent, settings.Defaults} {
+		if value, ok := values[name]; ok {
+			return fmt.Sprintf("%v", value)
+		}
+	}
+	return defaultValue
+}
+
+// allowedBytesBelowWatermark returns the number of bytes which can be used on a disk of the given capacity
+// without exceeding the disk watermark. The watermark is either a percentage or ratio of used disk space,
+// or the amount of free disk space as a byte size.
+func allowedBytesBelowWatermark(watermark string, capacityBytes int64) (int64, error) {
+	watermark = strings.ToLower(strings.TrimSpace(watermark))
+	if strings.HasSuffix(watermark, "%") {
+		percent, err := strconv.ParseFloat(strings.TrimSuffix(watermark, "%"), 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid disk watermark %s: %v", watermark, err)
+		}
+		return int64(float64(capacityBytes) * percent / 100), nil
+	}
+	if ratio, err := strconv.ParseFloat(watermark, 64); err == nil && ratio <= 1 {
+		return int64(float64(capacityBytes) * ratio), nil
+	}
+	matches := byteSizeRegex.FindStringSubmatch(watermark)
+	if matches == nil {
+		return 0, fmt.Errorf("invalid disk watermark %s", watermark)
+	}
+	size, err := strconv.ParseFloat(matches[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid disk watermark %s: %v", watermark, err)
+	}
+	return capacityBytes - int64(size*byteSizeUnits[matches[2]]), nil
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testGreenHealth = `{"status": "green", "relocating_shards": 0}`
+	// two data nodes using 40 and 60 bytes, and a master node using 50 bytes
+	testNodesFSStats = `{
+  "nodes": {
+    "a": {"name": "data-0", "roles": ["data", "ingest"], "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 60}}},
+    "b": {"name": "data-1", "roles": ["data", "ingest"], "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 40}}},
+    "c": {"name": "master-0", "roles": ["master"], "fs": {"total": {"total_in_bytes": 100, "available_in_bytes": 50}}}
+  }
+}`
+)
+
+// createScaleOSClient creates an OSClient responding to the requests made when validating a data node scale up
+func createScaleOSClient(t *testing.T, health, settings, allocation string) *OSClient {
+	return createMockOSClient(t, nil, func(method, path string, body []byte) (int, string) {
+		switch {
+		case strings.HasPrefix(path, "/_cluster/health"):
+			return http.StatusOK, health
+		case strings.HasPrefix(path, "/_cluster/settings"):
+			return http.StatusOK, settings
+		case strings.HasPrefix(path, "/_nodes/stats/fs"):
+			return http.StatusOK, testNodesFSStats
+		case strings.HasPrefix(path, "/_cat/allocation"):
+			return http.StatusOK, allocation
+		}
+		return http.StatusOK, "{}"
+	})
+}
+
+// TestValidateDataScaleUp Tests validating a data node scale up against the low disk watermark
+// GIVEN two data nodes using 100 bytes in total and different low disk watermarks
+// WHEN I call ValidateDataScaleUp to add a data node
+// THEN an error is returned only if the share of 33 bytes of the new data node exceeds the low disk watermark
+func TestValidateDataScaleUp(t *testing.T) {
+	var tests = []struct {
+		name     string
+		settings string
+		capacity int64
+		isError  bool
+	}{
+		{"default watermark fits", `{"defaults": {}}`, 100, false},
+		{"default watermark exceeded", `{"defaults": {}}`, 38, true},
+		{"percent watermark fits", `{"defaults": {"cluster.routing.allocation.disk.watermark.low": "85%"}}`, 40, false},
+		{"persistent watermark exceeded", `{"persistent": {"cluster.routing.allocation.disk.watermark.low": "50%"}, "defaults": {"cluster.routing.allocation.disk.watermark.low": "85%"}}`, 60, true},
+		{"transient watermark takes precedence", `{"transient": {"cluster.routing.allocation.disk.watermark.low": "0.9"}, "persistent": {"cluster.routing.allocation.disk.watermark.low": "50%"}}`, 37, false},
+		{"byte size watermark exceeded", `{"persistent": {"cluster.routing.allocation.disk.watermark.low": "20b"}}`, 50, true},
+		{"disk threshold disabled", `{"persistent": {"cluster.routing.allocation.disk.threshold_enabled": "false", "cluster.routing.allocation.disk.watermark.low": "1%"}}`, 10, false},
+		{"no PVC", `{"persistent": {"cluster.routing.allocation.disk.watermark.low": "1%"}}`, 0, false},
+		{"invalid watermark", `{"persistent": {"cluster.routing.allocation.disk.watermark.low": "high"}}`, 100, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := createScaleOSClient(t, testGreenHealth, tt.settings, "[]")
+			err := o.ValidateDataScaleUp(testvmo.DeepCopy(), 1, tt.capacity)
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestValidateDataScaleUpHealth Tests that a data node scale up is only allowed when all the primary shards are allocated
+// GIVEN a cluster with 'green', 'yellow' and 'red' health
+// WHEN I call ValidateDataScaleUp
+// THEN an error is returned only if the cluster health is 'red'
+func TestValidateDataScaleUpHealth(t *testing.T) {
+	var tests = []struct {
+		health  string
+		isError bool
+	}{
+		{"green", false},
+		{"yellow", false},
+		{"red", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.health, func(t *testing.T) {
+			o := createScaleOSClient(t, fmt.Sprintf(`{"status": "%s"}`, tt.health), `{"defaults": {}}`, "[]")
+			err := o.ValidateDataScaleUp(testvmo.DeepCopy(), 1, 1000)
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// TestAllowedBytesBelowWatermark Tests computing the bytes usable below a disk watermark
+// GIVEN percentage, ratio and byte size watermarks
+// WHEN I call allowedBytesBelowWatermark
+// THEN the bytes usable on the disk without exceeding the watermark are returned
+func TestAllowedBytesBelowWatermark(t *testing.T) {
+	var tests = []struct {
+		watermark string
+		capacity  int64
+		allowed   int64
+	}{
+		{"85%", 1000, 850},
+		{"0.5", 1000, 500},
+		{"100b", 1000, 900},
+		{"1kb", 4096, 3072},
+		{"1GB", 2 << 30, 1 << 30},
+	}
+	for _, tt := range tests {
+		allowed, err := allowedBytesBelowWatermark(tt.watermark, tt.capacity)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.allowed, allowed, tt.watermark)
+	}
+}
+
+// TestVerifyDataShardsRelocated Tests verifying that shards have relocated after a data node scale up
+// GIVEN relocating shards, a data node without shards, or shards allocated to all data nodes
+// WHEN I call VerifyDataShardsRelocated
+// THEN an error is returned until the shards are allocated to all data nodes and no shards are relocating
+func TestVerifyDataShardsRelocated(t *testing.T) {
+	var tests = []struct {
+		name       string
+		health     string
+		allocation string
+		isError    bool
+	}{
+		{"relocating", `{"status": "green", "relocating_shards": 2}`, `[{"shards": "5", "node": "data-0"}, {"shards": "5", "node": "data-1"}]`, true},
+		{"node without shards", testGreenHealth, `[{"shards": "10", "node": "data-0"}, {"shards": "0", "node": "data-1"}]`, true},
+		{"relocated", testGreenHealth, `[{"shards": "5", "node": "data-0"}, {"shards": "5", "node": "data-1"}, {"shards": "0", "node": "UNASSIGNED"}]`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := createScaleOSClient(t, tt.health, `{"defaults": {}}`, tt.allocation)
+			err := o.VerifyDataShardsRelocated(testvmo.DeepCopy())
+			if tt.isError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+const (
+	searchBackpressureModeSetting                  = "search_backpressure.mode"
+	searchBackpressureCPUThresholdSetting          = "search_backpressure.node_duress.cpu_threshold"
+	searchBackpressureHeapThresholdSetting         = "search_backpressure.node_duress.heap_threshold"
+	searchBackpressureNumSuccessiveBreachesSetting = "search_backpressure.node_duress.num_successive_breaches"
+)
+
+// searchBackpressureSettings are all the search backpressure settings managed by the VMI
+var searchBackpressureSettings = []string{
+	searchBackpressureModeSetting,
+	searchBackpressureCPUThresholdSetting,
+	searchBackpressureHeapThresholdSetting,
+	searchBackpressureNumSuccessiveBreachesSetting,
+}
+
+// ConfigureSearchBackpressure sets the persistent search backpressure cluster settings of the VMI, and resets the
+// settings which are not set in the VMI to the OpenSearch defaults. The settings are only updated if they differ
+// from the persistent cluster settings.
+// The returned channel should be read for exactly one response, which tells whether search backpressure was configured.
+func (o *OSClient) ConfigureSearchBackpressure(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) chan error {
+	ch := make(chan error)
+	// configuration is done asynchronously, as this does not need to be blocking
+	go func() {
+		if !vmi.Spec.Opensearch.Enabled {
+			ch <- nil
+			return
+		}
+
+		if !o.IsOpenSearchReady(vmi) {
+			ch <- nil
+			return
+		}
+
+		ch <- o.syncSearchBackpressure(resources.GetOpenSearchHTTPEndpoint(vmi), vmi.Spec.Opensearch.SearchBackpressure)
+	}()
+
+	return ch
+}
+
+// syncSearchBackpressure puts the search backpressure settings which differ from the persistent cluster settings,
+// a nil value resets a setting to its default
+func (o *OSClient) syncSearchBackpressure(opensearchEndpoint string, searchBackpressure *vmcontrollerv1.OpenSearchSearchBackpressure) error {
+	settings, err := o.getClusterSettings(opensearchEndpoint)
+	if err != nil {
+		return err
+	}
+	expected := toSearchBackpressureSettings(searchBackpressure)
+	changed := map[string]interface{}{}
+	for _, name := range searchBackpressureSettings {
+		current, isSet := settings.Persistent[name]
+		value, isExpected := expected[name]
+		if !isExpected {
+			if isSet {
+				changed[name] = nil
+			}
+			continue
+		}
+		if !isSet || fmt.Sprintf("%v", current) != value {
+			changed[name] = value
+		}
+	}
+	if len(changed) == 0 {
+		return nil
+	}
+	return o.putClusterSettings(opensearchEndpoint, changed, true)
+}
+
+// putClusterSettings puts cluster settings, in the persistent bucket if persistent, else in the transient bucket.
+// The settings applied by the reconcile must be persistent to survive a full cluster restart, while one-off settings
+// are transient and reset with the cluster. A nil value resets the setting to its default.
+func (o *OSClient) putClusterSettings(opensearchEndpoint string, settings map[string]interface{}, persistent bool) error {
+	body, err := json.Marshal(map[string]interface{}{clusterSettingsBucket(persistent): settings})
+	if err != nil {
+		return err
+	}
+	url := fmt.Sprintf("%s/_cluster/settings", opensearchEndpoint)
+	req, err := http.NewRequest("PUT", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Add(contentTypeHeader, applicationJSON)
+	resp, err := o.DoHTTP(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("got status code %d when updating cluster settings", resp.StatusCode)
+	}
+	return nil
+}
+
+// clusterSettingsBucket returns the bucket of the cluster settings, persistent or transient
+func clusterSettingsBucket(persistent bool) string {
+	if persistent {
+		return "persistent"
+	}
+	return "transient"
+}
+
+// toSearchBackpressureSettings returns the flat cluster settings of the search backpressure of the VMI,
+// only the configured settings are included
+func toSearchBackpressureSettings(searchBackpressure *vmcontrollerv1.OpenSearchSearchBackpressure) map[string]string {
+	settings := map[string]string{}
+	if searchBackpressure == nil {
+		return settings
+	}
+	if searchBackpressure.Mode != "" {
+		settings[searchBackpressureModeSetting] = searchBackpressure.Mode
+	}
+	if searchBackpressure.CPUThreshold != "" {
+		settings[searchBackpressureCPUThresholdSetting] = searchBackpressure.CPUThreshold
+	}
+	if searchBackpressure.HeapThreshold != "" {
+		settings[searchBackpressureHeapThresholdSetting] = searchBackpressure.HeapThreshold
+	}
+	if searchBackpressure.NumSuccessiveBreaches > 0 {
+		settings[searchBackpressureNumSuccessiveBreachesSetting] = strconv.Itoa(int(searchBackpressure.NumSuccessiveBreaches))
+	}
+	return settings
+}
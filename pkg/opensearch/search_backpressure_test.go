@@ -0,0 +1,131 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// TestConfigureSearchBackpressureDisabled Tests that search backpressure is not configured when OpenSearch is disabled
+// GIVEN a VMI with OpenSearch disabled
+// WHEN I call ConfigureSearchBackpressure
+// THEN OpenSearch is not called and no error is returned
+func TestConfigureSearchBackpressureDisabled(t *testing.T) {
+	o := NewOSClient(statefulSetLister)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	assert.NoError(t, <-o.ConfigureSearchBackpressure(&vmcontrollerv1.VerrazzanoMonitoringInstance{}))
+}
+
+// TestSyncSearchBackpressure Tests syncing the search backpressure settings of a VMI
+// GIVEN search backpressure settings of a VMI, and a cluster with an unchanged, a changed and a removed setting
+// WHEN I call syncSearchBackpressure
+// THEN only the changed and the new settings are put, and the removed setting is reset
+func TestSyncSearchBackpressure(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{
+  "search_backpressure.mode": "monitor_only",
+  "search_backpressure.node_duress.cpu_threshold": "0.9",
+  "search_backpressure.node_duress.num_successive_breaches": "3",
+  "cluster.routing.allocation.enable": "all"
+}`, &updates)
+
+	err := o.syncSearchBackpressure("http://localhost:9200", &vmcontrollerv1.OpenSearchSearchBackpressure{
+		Mode:          "enforced",
+		CPUThreshold:  "0.9",
+		HeapThreshold: "0.7",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, updates, 1)
+	assert.Equal(t, map[string]interface{}{
+		searchBackpressureModeSetting:                  "enforced",
+		searchBackpressureHeapThresholdSetting:         "0.7",
+		searchBackpressureNumSuccessiveBreachesSetting: nil,
+	}, updates[0]["persistent"])
+}
+
+// TestSyncSearchBackpressureUnchanged Tests syncing unchanged search backpressure settings of a VMI
+// GIVEN search backpressure settings of a VMI which are the persistent cluster settings, and a VMI without
+// search backpressure settings for a cluster without search backpressure settings
+// WHEN I call syncSearchBackpressure
+// THEN the cluster settings are not updated
+func TestSyncSearchBackpressureUnchanged(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{"search_backpressure.mode": "enforced", "search_backpressure.node_duress.num_successive_breaches": "5"}`, &updates)
+	err := o.syncSearchBackpressure("http://localhost:9200", &vmcontrollerv1.OpenSearchSearchBackpressure{
+		Mode:                  "enforced",
+		NumSuccessiveBreaches: 5,
+	})
+	assert.NoError(t, err)
+
+	o = createClusterSettingsOSClient(t, `{}`, &updates)
+	assert.NoError(t, o.syncSearchBackpressure("http://localhost:9200", nil))
+	assert.Empty(t, updates)
+}
+
+// TestSyncSearchBackpressureRemoved Tests syncing the search backpressure settings removed from a VMI
+// GIVEN a VMI without search backpressure settings, and a cluster with search backpressure settings
+// WHEN I call syncSearchBackpressure
+// THEN the search backpressure settings are reset to the OpenSearch defaults
+func TestSyncSearchBackpressureRemoved(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{"search_backpressure.mode": "enforced", "search_backpressure.node_duress.heap_threshold": "0.7"}`, &updates)
+
+	assert.NoError(t, o.syncSearchBackpressure("http://localhost:9200", nil))
+	assert.Len(t, updates, 1)
+	assert.Equal(t, map[string]interface{}{
+		searchBackpressureModeSetting:          nil,
+		searchBackpressureHeapThresholdSetting: nil,
+	}, updates[0]["persistent"])
+}
+
+// TestPutClusterSettings Tests putting persistent and transient cluster settings
+// GIVEN cluster settings
+// WHEN I call putClusterSettings with and without persistent
+// THEN the settings are put in the persistent and in the transient bucket respectively
+func TestPutClusterSettings(t *testing.T) {
+	tests := []struct {
+		name       string
+		persistent bool
+		bucket     string
+	}{
+		{"persistent", true, "persistent"},
+		{"transient", false, "transient"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var updates []clusterSettingsUpdate
+			o := createClusterSettingsOSClient(t, `{}`, &updates)
+			assert.NoError(t, o.putClusterSettings("http://localhost:9200", map[string]interface{}{autoCreateIndexSetting: "true"}, tt.persistent))
+			assert.Equal(t, []clusterSettingsUpdate{{tt.bucket: {autoCreateIndexSetting: "true"}}}, updates)
+		})
+	}
+}
+
+// TestReconcileClusterSettingsPersistent Tests that the cluster settings applied by the reconcile are persistent
+// GIVEN a cluster without settings
+// WHEN I sync the auto create index, search backpressure and allocation awareness settings
+// THEN all the settings are put in the persistent bucket, so they survive a full cluster restart
+func TestReconcileClusterSettingsPersistent(t *testing.T) {
+	var updates []clusterSettingsUpdate
+	o := createClusterSettingsOSClient(t, `{}`, &updates)
+	assert.NoError(t, o.syncAutoCreateIndex("http://localhost:9200", "+verrazzano-*,-*"))
+	assert.NoError(t, o.syncSearchBackpressure("http://localhost:9200", &vmcontrollerv1.OpenSearchSearchBackpressure{Mode: "enforced"}))
+	assert.NoError(t, o.syncAllocationAwareness("http://localhost:9200", &vmcontrollerv1.OpenSearchAllocationAwareness{
+		Attributes: []vmcontrollerv1.OpenSearchAwarenessAttribute{{Name: "zone"}},
+	}))
+	var buckets []string
+	for _, update := range updates {
+		for bucket := range update {
+			buckets = append(buckets, bucket)
+		}
+	}
+	assert.Equal(t, []string{"persistent", "persistent", "persistent"}, buckets)
+}
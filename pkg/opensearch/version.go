@@ -0,0 +1,49 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package opensearch
+
+import (
+	"regexp"
+	"strconv"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+)
+
+// majorVersionRegex matches the major version of an OpenSearch version, e.g. 2 of 2.3.0, or of an image tag, e.g. 2 of
+// v2.3.0-20230101
+var majorVersionRegex = regexp.MustCompile(`^v?([0-9]+)\.[0-9]+`)
+
+// RunningVersion returns the highest OpenSearch version the nodes of the cluster of the VMI are running, or an empty
+// string if the version is unknown, e.g. when the health checks are skipped or no node is running
+func (o *OSClient) RunningVersion(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (string, error) {
+	if o.skipHealthChecks {
+		return "", nil
+	}
+	nodes, err := o.getOpenSearchNodes(vmo)
+	if err != nil {
+		return "", err
+	}
+	runningVersion := ""
+	runningMajor := -1
+	for _, node := range nodes {
+		if major, ok := MajorVersion(node.Version); ok && major > runningMajor {
+			runningVersion = node.Version
+			runningMajor = major
+		}
+	}
+	return runningVersion, nil
+}
+
+// MajorVersion returns the major version of an OpenSearch version or of an image tag, and false if it has none
+func MajorVersion(version string) (int, bool) {
+	match := majorVersionRegex.FindStringSubmatch(version)
+	if match == nil {
+		return 0, false
+	}
+	major, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0, false
+	}
+	return major, true
+}
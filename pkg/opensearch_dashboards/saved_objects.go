@@ -0,0 +1,121 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+)
+
+// savedObjectsImportResponse is the response of the saved objects import API
+type savedObjectsImportResponse struct {
+	Success      bool                      `json:"success"`
+	SuccessCount int                       `json:"successCount"`
+	Errors       []savedObjectsImportError `json:"errors,omitempty"`
+}
+
+// savedObjectsImportError is the error of a saved object which could not be imported
+type savedObjectsImportError struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Error struct {
+		Type string `json:"type"`
+	} `json:"error"`
+}
+
+// SavedObjectsChecksum returns the checksum of the saved objects, keyed by ConfigMap key
+func SavedObjectsChecksum(savedObjects map[string]string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(toNDJSON(savedObjects))))
+}
+
+// ImportSavedObjects imports the NDJSON saved objects, keyed by ConfigMap key, into OpenSearch Dashboards, overwriting
+// the existing saved objects with the same IDs. If OpenSearch Dashboards is not ready yet, nothing is imported and
+// false is returned.
+func (od *OSDashboardsClient) ImportSavedObjects(log vzlog.VerrazzanoLogger, openSearchDashboardsEndpoint string, savedObjects map[string]string) (bool, error) {
+	if !od.isReady(openSearchDashboardsEndpoint) {
+		log.Progressf("OpenSearch Dashboards is not ready yet, the saved objects will be imported later")
+		return false, nil
+	}
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "saved-objects.ndjson")
+	if err != nil {
+		return false, err
+	}
+	if _, err := part.Write([]byte(toNDJSON(savedObjects))); err != nil {
+		return false, err
+	}
+	if err := writer.Close(); err != nil {
+		return false, err
+	}
+
+	importURL := fmt.Sprintf("%s/api/saved_objects/_import?overwrite=true", openSearchDashboardsEndpoint)
+	req, err := http.NewRequest("POST", importURL, body)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Add("Content-Type", writer.FormDataContentType())
+	req.Header.Add("osd-xsrf", "true")
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to import saved objects in OpenSearch Dashboards: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("got status code %d when importing saved objects", resp.StatusCode)
+	}
+	importResponse := &savedObjectsImportResponse{}
+	if err := json.NewDecoder(resp.Body).Decode(importResponse); err != nil {
+		return false, err
+	}
+	if !importResponse.Success {
+		var failed []string
+		for _, importError := range importResponse.Errors {
+			failed = append(failed, fmt.Sprintf("%s %s: %s", importError.Type, importError.ID, importError.Error.Type))
+		}
+		return false, fmt.Errorf("failed to import saved objects: %s", strings.Join(failed, ", "))
+	}
+	log.Oncef("Imported %d saved objects in OpenSearch Dashboards", importResponse.SuccessCount)
+	return true, nil
+}
+
+// isReady returns true if OpenSearch Dashboards is ready to serve requests
+func (od *OSDashboardsClient) isReady(openSearchDashboardsEndpoint string) bool {
+	req, err := http.NewRequest("GET", fmt.Sprintf("%s/api/status", openSearchDashboardsEndpoint), nil)
+	if err != nil {
+		return false
+	}
+	resp, err := od.DoHTTP(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// toNDJSON returns the saved objects as a single NDJSON document, ordered by ConfigMap key
+func toNDJSON(savedObjects map[string]string) string {
+	var keys []string
+	for key := range savedObjects {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var lines []string
+	for _, key := range keys {
+		for _, line := range strings.Split(savedObjects[key], "\n") {
+			if strings.TrimSpace(line) != "" {
+				lines = append(lines, strings.TrimSpace(line))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,100 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package dashboards
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+)
+
+const (
+	testIndexPatternObject  = `{"id": "logs", "type": "index-pattern", "attributes": {"title": "logs-*", "timeFieldName": "@timestamp"}}`
+	testVisualizationObject = `{"id": "errors", "type": "visualization", "attributes": {"title": "Errors"}}`
+)
+
+// createSavedObjectsOSDClient creates an OSDashboardsClient for an OpenSearch Dashboards with the given readiness and
+// import response, recording the NDJSON content of the imports it receives
+func createSavedObjectsOSDClient(t *testing.T, ready bool, importResponse string, imports *[]string) *OSDashboardsClient {
+	od := NewOSDashboardsClient()
+	od.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		switch request.URL.Path {
+		case "/api/status":
+			if !ready {
+				return &http.Response{StatusCode: http.StatusServiceUnavailable, Body: io.NopCloser(strings.NewReader(""))}, nil
+			}
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+		case "/api/saved_objects/_import":
+			assert.Equal(t, "POST", request.Method)
+			assert.Equal(t, "true", request.URL.Query().Get("overwrite"))
+			assert.Equal(t, "true", request.Header.Get("osd-xsrf"))
+			file, header, err := request.FormFile("file")
+			assert.NoError(t, err)
+			assert.True(t, strings.HasSuffix(header.Filename, ".ndjson"))
+			content, err := io.ReadAll(file)
+			assert.NoError(t, err)
+			*imports = append(*imports, string(content))
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(importResponse))}, nil
+		default:
+			t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+			return nil, nil
+		}
+	}
+	return od
+}
+
+// TestImportSavedObjects Tests importing saved objects into OpenSearch Dashboards
+// GIVEN saved objects in two ConfigMap keys, and a ready OpenSearch Dashboards
+// WHEN I call ImportSavedObjects
+// THEN the saved objects are imported as a single NDJSON file, ordered by key
+func TestImportSavedObjects(t *testing.T) {
+	var imports []string
+	od := createSavedObjectsOSDClient(t, true, `{"success": true, "successCount": 2}`, &imports)
+	imported, err := od.ImportSavedObjects(vzlog.DefaultLogger(), "http://localhost:5601", map[string]string{
+		"visualizations.ndjson": testVisualizationObject + "\n",
+		"index-patterns.ndjson": testIndexPatternObject,
+	})
+	assert.NoError(t, err)
+	assert.True(t, imported)
+	assert.Equal(t, []string{testIndexPatternObject + "\n" + testVisualizationObject}, imports)
+}
+
+// TestImportSavedObjectsNotReady Tests that saved objects are not imported while OpenSearch Dashboards is not ready
+// GIVEN saved objects, and an OpenSearch Dashboards which is not ready
+// WHEN I call ImportSavedObjects
+// THEN nothing is imported and no error is returned
+func TestImportSavedObjectsNotReady(t *testing.T) {
+	var imports []string
+	od := createSavedObjectsOSDClient(t, false, "", &imports)
+	imported, err := od.ImportSavedObjects(vzlog.DefaultLogger(), "http://localhost:5601", map[string]string{"objects.ndjson": testIndexPatternObject})
+	assert.NoError(t, err)
+	assert.False(t, imported)
+	assert.Empty(t, imports)
+}
+
+// TestImportSavedObjectsErrors Tests importing saved objects which are rejected by OpenSearch Dashboards
+// GIVEN saved objects, and an OpenSearch Dashboards which fails to import one of them
+// WHEN I call ImportSavedObjects
+// THEN an error naming the rejected saved object is returned
+func TestImportSavedObjectsErrors(t *testing.T) {
+	var imports []string
+	od := createSavedObjectsOSDClient(t, true, `{"success": false, "successCount": 1, "errors": [{"id": "errors", "type": "visualization", "error": {"type": "missing_references"}}]}`, &imports)
+	imported, err := od.ImportSavedObjects(vzlog.DefaultLogger(), "http://localhost:5601", map[string]string{"objects.ndjson": testIndexPatternObject + "\n" + testVisualizationObject})
+	assert.ErrorContains(t, err, "visualization errors: missing_references")
+	assert.False(t, imported)
+}
+
+// TestSavedObjectsChecksum Tests the checksum of saved objects
+// GIVEN saved objects
+// WHEN I compute their checksum
+// THEN the checksum ignores blank lines and changes with the saved objects
+func TestSavedObjectsChecksum(t *testing.T) {
+	checksum := SavedObjectsChecksum(map[string]string{"objects.ndjson": testIndexPatternObject})
+	assert.Equal(t, checksum, SavedObjectsChecksum(map[string]string{"objects.ndjson": "\n" + testIndexPatternObject + "\n\n"}))
+	assert.NotEqual(t, checksum, SavedObjectsChecksum(map[string]string{"objects.ndjson": testVisualizationObject}))
+}
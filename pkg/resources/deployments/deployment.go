@@ -12,6 +12,7 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
 
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -26,6 +27,9 @@ type Elasticsearch interface {
 	createElasticsearchIngestDeploymentElements(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []*appsv1.Deployment
 }
 
+// apiPrometheusHealthCheckQuery makes the health check of the API server also check that Prometheus is reachable
+const apiPrometheusHealthCheckQuery = "?prometheus=true"
+
 type ExpectedDeployments struct {
 	Deployments                 []*appsv1.Deployment
 	GrafanaDeployments          int
@@ -41,6 +45,21 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 	var err error
 
 	if vmo.Spec.Opensearch.Enabled {
+		if err := resources.ValidateOpenSearchLoggers(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateOpenSearchCircuitBreakers(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateOpenSearchPreStartScript(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateOpenSearchTransportCompression(vmo); err != nil {
+			return nil, err
+		}
+		if err := nodes.ValidateNodeRoles(vmo); err != nil {
+			return nil, err
+		}
 		basic := ElasticsearchBasic{}
 		ingestDeployments := basic.createElasticsearchIngestDeploymentElements(vmo)
 		dataDeployments := basic.createElasticsearchDataDeploymentElements(vmo, pvcToAdMap)
@@ -50,8 +69,24 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 		expected.OpenSearchDataDeployments += len(dataDeployments)
 	}
 
+	// The OpenSearch Dashboards deployment is created separately, only its settings are validated here
+	if vmo.Spec.OpensearchDashboards.Enabled {
+		if err := resources.ValidateOpenSearchDashboardsRequestSettings(vmo); err != nil {
+			return nil, err
+		}
+	}
+
 	// Grafana
 	if vmo.Spec.Grafana.Enabled {
+		if err := resources.ValidateGrafanaSecurityContext(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateGrafanaCookieSameSite(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateGrafanaPlugins(vmo); err != nil {
+			return nil, err
+		}
 		expected.GrafanaDeployments++
 		deployment := createDeploymentElement(vmo, &vmo.Spec.Grafana.Storage, &vmo.Spec.Grafana.Resources, config.Grafana, config.Grafana.Name)
 
@@ -61,14 +96,16 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 
 		deployment.Spec.Strategy.Type = "Recreate"
 
-		// Init grafana container security context.   472 is grafana UID and GID
+		// Init grafana container security context.   472 is grafana UID and GID, both may be overridden in the VMI.
+		// The container runs as the group owning the volumes, so an overridden FSGroup also overrides its group
+		grafanaUID, grafanaFSGroup := resources.GetGrafanaUserAndFSGroup(vmo)
 		deployment.Spec.Template.Spec.Containers[0].SecurityContext = &corev1.SecurityContext{
 			Capabilities: &corev1.Capabilities{
 				Drop: []corev1.Capability{"ALL"},
 			},
 			Privileged:               resources.NewBool(false),
-			RunAsUser:                resources.New64Val(472),
-			RunAsGroup:               resources.New64Val(472),
+			RunAsUser:                resources.New64Val(grafanaUID),
+			RunAsGroup:               resources.New64Val(grafanaFSGroup),
 			RunAsNonRoot:             resources.NewBool(true),
 			AllowPrivilegeEscalation: resources.NewBool(false),
 		}
@@ -111,6 +148,10 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 				{Name: "GF_AUTH_DISABLE_SIGNOUT_MENU", Value: "false"},
 			}...)
 		} else {
+			authProxyHeaderName := vmo.Spec.Grafana.AuthProxyHeaderName
+			if authProxyHeaderName == "" {
+				authProxyHeaderName = constants.GrafanaDefaultAuthProxyHeaderName
+			}
 			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, []corev1.EnvVar{
 				{
 					Name: "GF_SECURITY_ADMIN_USER",
@@ -142,7 +183,7 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 				{Name: "GF_AUTH_DISABLE_LOGIN_FORM", Value: "true"},
 				{Name: "GF_AUTH_DISABLE_SIGNOUT_MENU", Value: "true"},
 				{Name: "GF_AUTH_PROXY_ENABLED", Value: "true"},
-				{Name: "GF_AUTH_PROXY_HEADER_NAME", Value: "X-WEBAUTH-USER"},
+				{Name: "GF_AUTH_PROXY_HEADER_NAME", Value: authProxyHeaderName},
 				{Name: "GF_AUTH_PROXY_HEADER_PROPERTY", Value: "username"},
 				{Name: "GF_AUTH_PROXY_AUTO_SIGN_UP", Value: "true"},
 			}...)
@@ -255,7 +296,17 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 		if vmo.Spec.URI != "" {
 			externalDomainName := config.Grafana.Name + "." + vmo.Spec.URI
 			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "GF_SERVER_DOMAIN", Value: externalDomainName})
-			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "GF_SERVER_ROOT_URL", Value: "https://" + externalDomainName})
+			rootURLScheme := vmo.Spec.Grafana.RootURLScheme
+			if rootURLScheme == "" {
+				rootURLScheme = constants.GrafanaDefaultRootURLScheme
+			}
+			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "GF_SERVER_ROOT_URL", Value: rootURLScheme + "://" + externalDomainName})
+		}
+		deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env,
+			corev1.EnvVar{Name: "GF_SECURITY_COOKIE_SECURE", Value: strconv.FormatBool(resources.IsGrafanaCookieSecure(vmo))})
+		if vmo.Spec.Grafana.CookieSameSite != "" {
+			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env,
+				corev1.EnvVar{Name: "GF_SECURITY_COOKIE_SAMESITE", Value: vmo.Spec.Grafana.CookieSameSite})
 		}
 		// container will be restarted (per restart policy) if it fails the following liveness check:
 		deployment.Spec.Template.Spec.Containers[0].LivenessProbe.InitialDelaySeconds = 15
@@ -269,6 +320,12 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 
 		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe = deployment.Spec.Template.Spec.Containers[0].LivenessProbe
 
+		// the Grafana image installs the Grafana plugins before starting Grafana
+		if len(vmo.Spec.Grafana.Plugins) > 0 {
+			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env,
+				corev1.EnvVar{Name: "GF_INSTALL_PLUGINS", Value: resources.GetGrafanaInstallPlugins(vmo.Spec.Grafana.Plugins)})
+		}
+
 		// dashboard volume
 		volumes := []corev1.Volume{
 			{
@@ -338,6 +395,14 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 				{Name: "FOLDER", Value: "/etc/grafana/provisioning/dashboardjson"},
 				{Name: "NAMESPACE", Value: "ALL"},
 			}...)
+			if vmo.Spec.Grafana.DashboardFolders != nil {
+				// The dashboards of a ConfigMap are written to the subdirectory named by the folder annotation of the ConfigMap
+				folderAnnotation := vmo.Spec.Grafana.DashboardFolders.Annotation
+				if folderAnnotation == "" {
+					folderAnnotation = constants.GrafanaDefaultDashboardFolderAnnotation
+				}
+				deployment.Spec.Template.Spec.Containers[i+1].Env = append(deployment.Spec.Template.Spec.Containers[i+1].Env, corev1.EnvVar{Name: "FOLDER_ANNOTATION", Value: folderAnnotation})
+			}
 			deployment.Spec.Template.Spec.Containers[i+1].VolumeMounts = append(deployment.Spec.Template.Spec.Containers[i+1].VolumeMounts, corev1.VolumeMount{
 				Name:      "dashboards-volume",
 				MountPath: "/etc/grafana/provisioning/dashboardjson",
@@ -350,9 +415,9 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 		// "grafana" (GID 472), and a user "grafana" (UID 472) in that group.  When we provide FSGroup =
 		// 472 below, the volume is owned by root/grafana, with permissions "rwxrwsr-x".  This allows the Grafana
 		// image to run as UID 472, and have sufficient permissions to write to the mounted volume.
-		grafanaGid := int64(472)
+		// The FSGroup may be overridden in the VMI, for clusters enforcing the UID and GID ranges of the pods.
 		deployment.Spec.Template.Spec.SecurityContext = &corev1.PodSecurityContext{
-			FSGroup: &grafanaGid,
+			FSGroup: resources.New64Val(grafanaFSGroup),
 			SeccompProfile: &corev1.SeccompProfile{
 				Type: "RuntimeDefault",
 			},
@@ -371,14 +436,17 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 			{Name: "NAMESPACE", Value: vmo.Namespace},
 			{Name: "ENV_NAME", Value: operatorConfig.EnvName},
 		}
+		deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env, vmo.Spec.API.ExtraEnv...)
 		if len(vmo.Spec.NatGatewayIPs) > 0 {
 			deployment.Spec.Template.Spec.Containers[0].Args = []string{fmt.Sprintf("--natGatewayIPs=%s", strings.Join(vmo.Spec.NatGatewayIPs, ","))}
 		}
+		deployment.Spec.Template.Spec.Containers[0].Args = append(deployment.Spec.Template.Spec.Containers[0].Args, vmo.Spec.API.ExtraArgs...)
 
 		deployment.Spec.Template.Spec.Containers[0].LivenessProbe.InitialDelaySeconds = 15
 		deployment.Spec.Template.Spec.Containers[0].LivenessProbe.TimeoutSeconds = 3
 		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.InitialDelaySeconds = 5
 		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.TimeoutSeconds = 3
+		setAPIDependencyReadiness(vmo, deployment.Spec.Template.Spec.Containers[0].ReadinessProbe)
 
 		deployments = append(deployments, deployment)
 	}
@@ -387,6 +455,20 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kubeclientset kuberne
 	return expected, err
 }
 
+// setAPIDependencyReadiness makes the readiness probe of the API server get the health check which also checks that
+// Prometheus is reachable, if the VMI makes the API server readiness depend on Prometheus
+func setAPIDependencyReadiness(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, probe *corev1.Probe) {
+	if probe == nil || probe.HTTPGet == nil || !vmo.Spec.API.ReadinessDependsOnPrometheus || !vmo.Spec.Prometheus.Enabled {
+		return
+	}
+	probe.HTTPGet.Path = config.API.ReadinessHTTPPath + apiPrometheusHealthCheckQuery
+}
+
+// IsOpenSearchDashboardsDeployment returns true if the deployment is the OpenSearch Dashboards deployment
+func IsOpenSearchDashboardsDeployment(vmoName string, deployment *appsv1.Deployment) bool {
+	return deployment.Spec.Template.Labels[constants.ServiceAppLabel] == vmoName+"-"+config.OpenSearchDashboards.Name
+}
+
 func NewOpenSearchDashboardsDeployment(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) *appsv1.Deployment {
 	var deployment *appsv1.Deployment
 	if vmo.Spec.OpensearchDashboards.Enabled {
@@ -396,15 +478,26 @@ func NewOpenSearchDashboardsDeployment(vmo *vmcontrollerv1.VerrazzanoMonitoringI
 		deployment.Spec.Strategy = appsv1.DeploymentStrategy{
 			Type: appsv1.RecreateDeploymentStrategyType,
 		}
-		deployment.Spec.Replicas = resources.NewVal(vmo.Spec.OpensearchDashboards.Replicas)
+		deployment.Spec.Replicas = resources.NewVal(resources.GetOpenSearchDashboardsReplicas(vmo))
 		deployment.Spec.Template.Spec.Affinity = resources.CreateZoneAntiAffinityElement(vmo.Name, config.OpenSearchDashboards.Name)
 		deployment.Spec.Template.Spec.Containers[0].Env = []corev1.EnvVar{
 			{Name: "OPENSEARCH_HOSTS", Value: opensearchURL},
-			{
-				Name:  constants.DisableSecurityPluginOSD,
-				Value: "true",
-			},
 		}
+		// the security plugin is only kept for clusters with the OpenSearch security plugin enabled
+		if !vmo.Spec.OpensearchDashboards.SecurityEnabled {
+			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env,
+				corev1.EnvVar{Name: constants.DisableSecurityPluginOSD, Value: "true"})
+		}
+		if vmo.Spec.OpensearchDashboards.ServerName != "" {
+			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env,
+				corev1.EnvVar{Name: constants.OSDServerNameEnv, Value: vmo.Spec.OpensearchDashboards.ServerName})
+		}
+		if vmo.Spec.OpensearchDashboards.DefaultRoute != "" {
+			deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env,
+				corev1.EnvVar{Name: constants.OSDDefaultRouteEnv, Value: vmo.Spec.OpensearchDashboards.DefaultRoute})
+		}
+		deployment.Spec.Template.Spec.Containers[0].Env = append(deployment.Spec.Template.Spec.Containers[0].Env,
+			resources.GetOpenSearchDashboardsRequestEnvVars(vmo)...)
 
 		deployment.Spec.Template.Spec.Containers[0].LivenessProbe.InitialDelaySeconds = 120
 		deployment.Spec.Template.Spec.Containers[0].LivenessProbe.TimeoutSeconds = 3
@@ -415,6 +508,7 @@ func NewOpenSearchDashboardsDeployment(vmo *vmcontrollerv1.VerrazzanoMonitoringI
 		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.TimeoutSeconds = 3
 		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.PeriodSeconds = 20
 		deployment.Spec.Template.Spec.Containers[0].ReadinessProbe.FailureThreshold = 5
+		addOpenSearchDashboardsTLS(vmo, &deployment.Spec.Template.Spec)
 
 		// When the deployment does not have a pod security context with an FSGroup attribute, any mounted volumes are
 		// initially owned by root/root. The current OSD image creates a group
@@ -439,6 +533,44 @@ func NewOpenSearchDashboardsDeployment(vmo *vmcontrollerv1.VerrazzanoMonitoringI
 	return deployment
 }
 
+// addOpenSearchDashboardsTLS mounts the TLS Secret of OpenSearch Dashboards, if set, and configures OpenSearch
+// Dashboards and its probes to serve HTTPS with its certificate and key
+func addOpenSearchDashboardsTLS(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec *corev1.PodSpec) {
+	tls := vmo.Spec.OpensearchDashboards.TLS
+	if tls == nil {
+		return
+	}
+	certKey := tls.CertKey
+	if certKey == "" {
+		certKey = corev1.TLSCertKey
+	}
+	keyKey := tls.KeyKey
+	if keyKey == "" {
+		keyKey = corev1.TLSPrivateKeyKey
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: constants.OSDTLSVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: tls.SecretName,
+			},
+		},
+	})
+	container := &podSpec.Containers[0]
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      constants.OSDTLSVolumeName,
+		MountPath: constants.OSDTLSMountPath,
+		ReadOnly:  true,
+	})
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: constants.OSDServerSSLEnabledEnv, Value: "true"},
+		corev1.EnvVar{Name: constants.OSDServerSSLCertificateEnv, Value: fmt.Sprintf("%s/%s", constants.OSDTLSMountPath, certKey)},
+		corev1.EnvVar{Name: constants.OSDServerSSLKeyEnv, Value: fmt.Sprintf("%s/%s", constants.OSDTLSMountPath, keyKey)},
+	)
+	container.LivenessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+	container.ReadinessProbe.HTTPGet.Scheme = corev1.URISchemeHTTPS
+}
+
 func createVolumeElement(pvcName string) corev1.Volume {
 	return corev1.Volume{
 		Name: constants.StorageVolumeName,
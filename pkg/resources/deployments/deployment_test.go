@@ -417,3 +417,850 @@ func TestGrafanaSMTPConfig(t *testing.T) {
 		}
 	}
 }
+
+// TestOpenSearchDashboardsServerNameAndDefaultRoute Tests the OpenSearch Dashboards server name and default route env vars
+// GIVEN a VMI with OpenSearch Dashboards enabled
+// WHEN I call NewOpenSearchDashboardsDeployment
+// THEN the server name and default route env vars are set only when they are configured
+func TestOpenSearchDashboardsServerNameAndDefaultRoute(t *testing.T) {
+	var tests = []struct {
+		name         string
+		serverName   string
+		defaultRoute string
+	}{
+		{"not configured", "", ""},
+		{"server name configured", "osd-system", ""},
+		{"default route configured", "", "/app/discover"},
+		{"server name and default route configured", "osd-system", "/app/discover"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					OpensearchDashboards: vmcontrollerv1.OpensearchDashboards{
+						Enabled:      true,
+						ServerName:   tt.serverName,
+						DefaultRoute: tt.defaultRoute,
+					},
+				},
+			}
+			deployment := NewOpenSearchDashboardsDeployment(vmo)
+			envVars := map[string]string{}
+			for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+				envVars[env.Name] = env.Value
+			}
+			serverName, ok := envVars[constants.OSDServerNameEnv]
+			assert.Equal(t, tt.serverName != "", ok)
+			assert.Equal(t, tt.serverName, serverName)
+			defaultRoute, ok := envVars[constants.OSDDefaultRouteEnv]
+			assert.Equal(t, tt.defaultRoute != "", ok)
+			assert.Equal(t, tt.defaultRoute, defaultRoute)
+		})
+	}
+}
+
+// TestOpenSearchDashboardsSecurityPlugin Tests the env var disabling the OpenSearch Dashboards security plugin
+// GIVEN a VMI with OpenSearch Dashboards enabled, with and without security enabled
+// WHEN I call NewOpenSearchDashboardsDeployment
+// THEN the security plugin is disabled by default, and kept when security is enabled
+func TestOpenSearchDashboardsSecurityPlugin(t *testing.T) {
+	var tests = []struct {
+		name            string
+		securityEnabled bool
+		disabled        bool
+	}{
+		{"security plugin is disabled by default", false, true},
+		{"security plugin is kept when security is enabled", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					OpensearchDashboards: vmcontrollerv1.OpensearchDashboards{
+						Enabled:         true,
+						SecurityEnabled: tt.securityEnabled,
+					},
+				},
+			}
+			deployment := NewOpenSearchDashboardsDeployment(vmo)
+			envVar := resources.GetEnvVar(&deployment.Spec.Template.Spec.Containers[0], constants.DisableSecurityPluginOSD)
+			if tt.disabled {
+				assert.NotNil(t, envVar)
+				assert.Equal(t, "true", envVar.Value)
+			} else {
+				assert.Nil(t, envVar)
+			}
+			assert.NotNil(t, resources.GetEnvVar(&deployment.Spec.Template.Spec.Containers[0], "OPENSEARCH_HOSTS"))
+		})
+	}
+}
+
+// TestGrafanaPlugins Tests the Grafana plugins installation
+// GIVEN a VMI with Grafana enabled, without plugins, with plugins, and with an invalid plugin
+// WHEN I call New
+// THEN the GF_INSTALL_PLUGINS env var is only set when plugins are configured, the Grafana entrypoint is kept, and
+// invalid plugins are rejected
+func TestGrafanaPlugins(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Grafana: vmcontrollerv1.Grafana{
+				Enabled: true,
+			},
+		},
+	}
+	getGrafanaContainer := func() *corev1.Container {
+		expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+		assert.NoError(t, err)
+		for _, deployment := range expected.Deployments {
+			if deployment.Name == resources.GetMetaName(vmo.Name, config.Grafana.Name) {
+				return &deployment.Spec.Template.Spec.Containers[0]
+			}
+		}
+		t.Fatal("no Grafana deployment")
+		return nil
+	}
+
+	getInstallPlugins := func(container *corev1.Container) (string, bool) {
+		for _, env := range container.Env {
+			if env.Name == "GF_INSTALL_PLUGINS" {
+				return env.Value, true
+			}
+		}
+		return "", false
+	}
+
+	_, ok := getInstallPlugins(getGrafanaContainer())
+	assert.False(t, ok)
+
+	vmo.Spec.Grafana.Plugins = []string{"grafana-clock-panel", "grafana-piechart-panel 1.6.4"}
+	container := getGrafanaContainer()
+	assert.Empty(t, container.Command)
+	installPlugins, ok := getInstallPlugins(container)
+	assert.True(t, ok)
+	assert.Equal(t, "grafana-clock-panel,grafana-piechart-panel 1.6.4", installPlugins)
+
+	vmo.Spec.Grafana.Plugins = []string{"grafana-clock-panel && curl example.com"}
+	_, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.ErrorContains(t, err, "invalid Grafana plugin")
+}
+
+// TestOpenSearchDashboardsTLS Tests the OpenSearch Dashboards server TLS settings
+// GIVEN a VMI with OpenSearch Dashboards enabled, without TLS, with a TLS Secret, or with a TLS Secret with custom keys
+// WHEN I call NewOpenSearchDashboardsDeployment
+// THEN the TLS Secret is mounted, the SSL env vars point to its certificate and key and the probes use HTTPS only when
+// TLS is configured
+func TestOpenSearchDashboardsTLS(t *testing.T) {
+	var tests = []struct {
+		name        string
+		tls         *vmcontrollerv1.OpenSearchDashboardsTLS
+		certificate string
+		key         string
+	}{
+		{"no TLS", nil, "", ""},
+		{"default keys", &vmcontrollerv1.OpenSearchDashboardsTLS{SecretName: "osd-cert"}, constants.OSDTLSMountPath + "/tls.crt", constants.OSDTLSMountPath + "/tls.key"},
+		{"custom keys", &vmcontrollerv1.OpenSearchDashboardsTLS{SecretName: "osd-cert", CertKey: "cert.pem", KeyKey: "key.pem"}, constants.OSDTLSMountPath + "/cert.pem", constants.OSDTLSMountPath + "/key.pem"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					OpensearchDashboards: vmcontrollerv1.OpensearchDashboards{
+						Enabled: true,
+						TLS:     tt.tls,
+					},
+				},
+			}
+			deployment := NewOpenSearchDashboardsDeployment(vmo)
+			container := &deployment.Spec.Template.Spec.Containers[0]
+			if tt.tls == nil {
+				assert.Empty(t, deployment.Spec.Template.Spec.Volumes)
+				assert.Empty(t, container.VolumeMounts)
+				for _, name := range []string{constants.OSDServerSSLEnabledEnv, constants.OSDServerSSLCertificateEnv, constants.OSDServerSSLKeyEnv} {
+					assert.Nil(t, resources.GetEnvVar(container, name), name)
+				}
+				assert.Equal(t, corev1.URISchemeHTTP, container.LivenessProbe.HTTPGet.Scheme)
+				assert.Equal(t, corev1.URISchemeHTTP, container.ReadinessProbe.HTTPGet.Scheme)
+				return
+			}
+			assert.Contains(t, deployment.Spec.Template.Spec.Volumes, corev1.Volume{
+				Name:         constants.OSDTLSVolumeName,
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "osd-cert"}},
+			})
+			assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: constants.OSDTLSVolumeName, MountPath: constants.OSDTLSMountPath, ReadOnly: true})
+			assert.Equal(t, &corev1.EnvVar{Name: constants.OSDServerSSLEnabledEnv, Value: "true"}, resources.GetEnvVar(container, constants.OSDServerSSLEnabledEnv))
+			assert.Equal(t, &corev1.EnvVar{Name: constants.OSDServerSSLCertificateEnv, Value: tt.certificate}, resources.GetEnvVar(container, constants.OSDServerSSLCertificateEnv))
+			assert.Equal(t, &corev1.EnvVar{Name: constants.OSDServerSSLKeyEnv, Value: tt.key}, resources.GetEnvVar(container, constants.OSDServerSSLKeyEnv))
+			assert.Equal(t, corev1.URISchemeHTTPS, container.LivenessProbe.HTTPGet.Scheme)
+			assert.Equal(t, corev1.URISchemeHTTPS, container.ReadinessProbe.HTTPGet.Scheme)
+		})
+	}
+}
+
+// TestOpenSearchDashboardsRequestSettings Tests the OpenSearch Dashboards request timeout and max payload env vars
+// GIVEN a VMI with OpenSearch Dashboards enabled
+// WHEN I call New and NewOpenSearchDashboardsDeployment
+// THEN the request timeout, in milliseconds, and max payload env vars are set only when they are configured, and
+// invalid settings are rejected
+func TestOpenSearchDashboardsRequestSettings(t *testing.T) {
+	var tests = []struct {
+		name                    string
+		requestTimeout          string
+		maxPayloadBytes         *int64
+		expectedRequestTimeout  string
+		expectedMaxPayloadBytes string
+		expectErr               bool
+	}{
+		{"not configured", "", nil, "", "", false},
+		{"request timeout configured", "2m", nil, "120000", "", false},
+		{"max payload configured", "", resources.New64Val(10485760), "", "10485760", false},
+		{"invalid request timeout", "2 minutes", nil, "", "", true},
+		{"negative request timeout", "-30s", nil, "", "", true},
+		{"invalid max payload", "", resources.New64Val(0), "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					OpensearchDashboards: vmcontrollerv1.OpensearchDashboards{
+						Enabled:         true,
+						RequestTimeout:  tt.requestTimeout,
+						MaxPayloadBytes: tt.maxPayloadBytes,
+					},
+				},
+			}
+			_, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			deployment := NewOpenSearchDashboardsDeployment(vmo)
+			envVars := map[string]string{}
+			for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+				envVars[env.Name] = env.Value
+			}
+			requestTimeout, ok := envVars[constants.OSDRequestTimeoutEnv]
+			assert.Equal(t, tt.expectedRequestTimeout != "", ok)
+			assert.Equal(t, tt.expectedRequestTimeout, requestTimeout)
+			maxPayloadBytes, ok := envVars[constants.OSDMaxPayloadBytesEnv]
+			assert.Equal(t, tt.expectedMaxPayloadBytes != "", ok)
+			assert.Equal(t, tt.expectedMaxPayloadBytes, maxPayloadBytes)
+		})
+	}
+}
+
+// TestGrafanaRootURLScheme tests the scheme of the Grafana root URL
+// GIVEN a VMI with a URI and no root URL scheme, the http scheme and the https scheme
+// WHEN I call New
+// THEN the GF_SERVER_ROOT_URL env var of the Grafana deployment uses the given scheme, https by default
+func TestGrafanaRootURLScheme(t *testing.T) {
+	tests := []struct {
+		scheme      string
+		expectedURL string
+	}{
+		{"", "https://grafana.vmi.system.example.com"},
+		{"http", "http://grafana.vmi.system.example.com"},
+		{"https", "https://grafana.vmi.system.example.com"},
+	}
+	for _, tt := range tests {
+		vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "system",
+			},
+			Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+				URI: "vmi.system.example.com",
+				Grafana: vmcontrollerv1.Grafana{
+					Enabled:       true,
+					RootURLScheme: tt.scheme,
+				},
+			},
+		}
+		expected, err := New(vmi, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+		assert.NoError(t, err)
+		grafanaDeployment, err := getDeploymentByName(resources.GetMetaName(vmi.Name, config.Grafana.Name), expected.Deployments)
+		assert.NoError(t, err)
+		rootURL := ""
+		for _, env := range grafanaDeployment.Spec.Template.Spec.Containers[0].Env {
+			if env.Name == "GF_SERVER_ROOT_URL" {
+				rootURL = env.Value
+			}
+		}
+		assert.Equal(t, tt.expectedURL, rootURL, "unexpected root URL for scheme '%s'", tt.scheme)
+	}
+}
+
+// TestGrafanaCookieSettings tests the security settings of the Grafana cookies
+// GIVEN a VMI exposing Grafana over HTTPS, over plaintext HTTP, not exposing Grafana, and with explicit cookie settings
+// WHEN I call New
+// THEN the cookies are secure by default when Grafana is exposed over HTTPS, the SameSite env var is only set when
+// configured, and SameSite none is rejected for cookies which are not secure
+func TestGrafanaCookieSettings(t *testing.T) {
+	trueValue := true
+	falseValue := false
+	tests := []struct {
+		name             string
+		uri              string
+		scheme           string
+		cookieSecure     *bool
+		cookieSameSite   string
+		expectedSecure   string
+		expectedSameSite string
+		expectError      bool
+	}{
+		{"HTTPS", "vmi.system.example.com", "", nil, "", "true", "", false},
+		{"plaintext scheme", "vmi.system.example.com", "http", nil, "", "false", "", false},
+		{"no URI", "", "", nil, "", "false", "", false},
+		{"explicit insecure over HTTPS", "vmi.system.example.com", "https", &falseValue, "lax", "false", "lax", false},
+		{"explicit secure over plaintext", "vmi.system.example.com", "http", &trueValue, "none", "true", "none", false},
+		{"SameSite none over plaintext", "vmi.system.example.com", "http", nil, "none", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					URI: tt.uri,
+					Grafana: vmcontrollerv1.Grafana{
+						Enabled:        true,
+						RootURLScheme:  tt.scheme,
+						CookieSecure:   tt.cookieSecure,
+						CookieSameSite: tt.cookieSameSite,
+					},
+				},
+			}
+			expected, err := New(vmi, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			grafanaDeployment, err := getDeploymentByName(resources.GetMetaName(vmi.Name, config.Grafana.Name), expected.Deployments)
+			assert.NoError(t, err)
+			secure, sameSite := "", ""
+			for _, env := range grafanaDeployment.Spec.Template.Spec.Containers[0].Env {
+				switch env.Name {
+				case "GF_SECURITY_COOKIE_SECURE":
+					secure = env.Value
+				case "GF_SECURITY_COOKIE_SAMESITE":
+					sameSite = env.Value
+				}
+			}
+			assert.Equal(t, tt.expectedSecure, secure)
+			assert.Equal(t, tt.expectedSameSite, sameSite)
+		})
+	}
+}
+
+// TestGrafanaAuthProxyHeaderName tests the name of the Grafana auth proxy user header
+// GIVEN a VMI with no auth proxy header name and with an auth proxy header name
+// WHEN I call New
+// THEN the GF_AUTH_PROXY_HEADER_NAME env var of the Grafana deployment is the given header name, X-WEBAUTH-USER by default
+func TestGrafanaAuthProxyHeaderName(t *testing.T) {
+	tests := []struct {
+		headerName         string
+		expectedHeaderName string
+	}{
+		{"", constants.GrafanaDefaultAuthProxyHeaderName},
+		{"X-Forwarded-User", "X-Forwarded-User"},
+	}
+	for _, tt := range tests {
+		vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+			ObjectMeta: v1.ObjectMeta{
+				Name: "system",
+			},
+			Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+				Grafana: vmcontrollerv1.Grafana{
+					Enabled:             true,
+					AuthProxyHeaderName: tt.headerName,
+				},
+			},
+		}
+		expected, err := New(vmi, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+		assert.NoError(t, err)
+		grafanaDeployment, err := getDeploymentByName(resources.GetMetaName(vmi.Name, config.Grafana.Name), expected.Deployments)
+		assert.NoError(t, err)
+		headerName := ""
+		for _, env := range grafanaDeployment.Spec.Template.Spec.Containers[0].Env {
+			if env.Name == "GF_AUTH_PROXY_HEADER_NAME" {
+				headerName = env.Value
+			}
+		}
+		assert.Equal(t, tt.expectedHeaderName, headerName)
+	}
+}
+
+// TestGrafanaDashboardFolders tests grouping the Grafana dashboards provisioned from ConfigMaps into folders
+// GIVEN a VMI without dashboard folders, with dashboard folders, and with dashboard folders and a folder annotation
+// WHEN I call New
+// THEN the FOLDER_ANNOTATION env var of the dashboards sidecar is only set with dashboard folders, to the given
+// annotation or grafana_folder by default
+func TestGrafanaDashboardFolders(t *testing.T) {
+	tests := []struct {
+		name               string
+		dashboardFolders   *vmcontrollerv1.DashboardFolders
+		expectedAnnotation string
+	}{
+		{"no dashboard folders", nil, ""},
+		{"default annotation", &vmcontrollerv1.DashboardFolders{}, constants.GrafanaDefaultDashboardFolderAnnotation},
+		{"custom annotation", &vmcontrollerv1.DashboardFolders{Annotation: "example.com/dashboard-folder"}, "example.com/dashboard-folder"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					Grafana: vmcontrollerv1.Grafana{
+						Enabled:          true,
+						DashboardFolders: tt.dashboardFolders,
+					},
+				},
+			}
+			expected, err := New(vmi, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+			assert.NoError(t, err)
+			grafanaDeployment, err := getDeploymentByName(resources.GetMetaName(vmi.Name, config.Grafana.Name), expected.Deployments)
+			assert.NoError(t, err)
+			assert.Len(t, grafanaDeployment.Spec.Template.Spec.Containers, 2)
+			annotation := ""
+			for _, env := range grafanaDeployment.Spec.Template.Spec.Containers[1].Env {
+				if env.Name == "FOLDER_ANNOTATION" {
+					annotation = env.Value
+				}
+			}
+			assert.Equal(t, tt.expectedAnnotation, annotation)
+		})
+	}
+}
+
+// TestGrafanaSecurityContext tests the user and volume group of the Grafana deployment
+// GIVEN a VMI without a Grafana UID and FSGroup, with a Grafana UID and FSGroup, and with the root UID
+// WHEN I call New
+// THEN the Grafana container runs as the given UID and the given FSGroup, which owns the pod volumes, 472 by default,
+// and the root UID is rejected
+func TestGrafanaSecurityContext(t *testing.T) {
+	tests := []struct {
+		name            string
+		runAsUser       *int64
+		fsGroup         *int64
+		expectedUser    int64
+		expectedFSGroup int64
+		expectErr       bool
+	}{
+		{"defaults", nil, nil, constants.GrafanaDefaultUID, constants.GrafanaDefaultUID, false},
+		{"overrides", resources.New64Val(1000650000), resources.New64Val(1000650001), 1000650000, 1000650001, false},
+		{"UID override", resources.New64Val(1000650000), nil, 1000650000, constants.GrafanaDefaultUID, false},
+		{"root", resources.New64Val(0), nil, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: v1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					Grafana: vmcontrollerv1.Grafana{
+						Enabled:   true,
+						RunAsUser: tt.runAsUser,
+						FSGroup:   tt.fsGroup,
+					},
+				},
+			}
+			expected, err := New(vmi, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+			if tt.expectErr {
+				assert.ErrorContains(t, err, "cannot run as root")
+				return
+			}
+			assert.NoError(t, err)
+			grafanaDeployment, err := getDeploymentByName(resources.GetMetaName(vmi.Name, config.Grafana.Name), expected.Deployments)
+			assert.NoError(t, err)
+			containerSecurityContext := grafanaDeployment.Spec.Template.Spec.Containers[0].SecurityContext
+			assert.Equal(t, tt.expectedUser, *containerSecurityContext.RunAsUser)
+			assert.Equal(t, tt.expectedFSGroup, *containerSecurityContext.RunAsGroup)
+			assert.True(t, *containerSecurityContext.RunAsNonRoot)
+			assert.Equal(t, tt.expectedFSGroup, *grafanaDeployment.Spec.Template.Spec.SecurityContext.FSGroup)
+		})
+	}
+}
+
+// TestAPIWithExtraArgsAndEnv tests the additional command-line arguments and environment variables of the API server
+// GIVEN a VMI with NAT gateway IPs, extra API args and extra API env vars
+// WHEN I call New
+// THEN the extra args are appended to the NAT gateway IPs arg and the extra env vars are appended to the default env vars
+func TestAPIWithExtraArgsAndEnv(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "my-vmo",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			NatGatewayIPs: []string{"1.1.1.1"},
+			API: vmcontrollerv1.API{
+				ExtraArgs: []string{"--zap-log-level=debug", "--feature=true"},
+				ExtraEnv: []corev1.EnvVar{
+					{Name: "HTTP_PROXY", Value: "http://proxy:3128"},
+					{Name: "TOKEN", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{Key: "token"}}},
+				},
+			},
+		},
+	}
+	expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	apiDeployment, err := getDeploymentByName(constants.VMOServiceNamePrefix+"my-vmo-api", expected.Deployments)
+	assert.NoError(t, err)
+	container := apiDeployment.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, []string{"--natGatewayIPs=1.1.1.1", "--zap-log-level=debug", "--feature=true"}, container.Args)
+	assert.Len(t, container.Env, 5)
+	assert.Equal(t, "VMI_NAME", container.Env[0].Name)
+	assert.Equal(t, vmo.Spec.API.ExtraEnv, container.Env[3:])
+}
+
+// TestAPIReadinessDependsOnPrometheus tests the readiness probe of the API server depending on Prometheus
+// GIVEN a VMI with Prometheus enabled, with and without the API server readiness depending on Prometheus
+// WHEN I call New
+// THEN the readiness probe gets the API server health check which also checks Prometheus if the readiness depends on
+// Prometheus, and the API server health check path otherwise
+func TestAPIReadinessDependsOnPrometheus(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "my-vmo",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Prometheus: vmcontrollerv1.Prometheus{Enabled: true},
+		},
+	}
+	for _, dependsOnPrometheus := range []bool{false, true} {
+		vmo.Spec.API.ReadinessDependsOnPrometheus = dependsOnPrometheus
+		expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+		assert.NoError(t, err)
+		apiDeployment, err := getDeploymentByName(constants.VMOServiceNamePrefix+"my-vmo-api", expected.Deployments)
+		assert.NoError(t, err)
+		probe := apiDeployment.Spec.Template.Spec.Containers[0].ReadinessProbe
+		assert.Equal(t, int32(5), probe.InitialDelaySeconds)
+		if !dependsOnPrometheus {
+			assert.Nil(t, probe.Exec)
+			assert.Equal(t, config.API.ReadinessHTTPPath, probe.HTTPGet.Path)
+			continue
+		}
+		assert.Nil(t, probe.Exec)
+		assert.Equal(t, "/healthcheck?prometheus=true", probe.HTTPGet.Path)
+		assert.Equal(t, int32(9097), probe.HTTPGet.Port.IntVal)
+	}
+}
+
+// TestOpenSearchLivenessProbeType Tests the liveness probe of the OpenSearch data and ingest deployments
+// GIVEN a VMI without a liveness probe type, and a VMI with the exec liveness probe type
+// WHEN I call New
+// THEN the liveness probe of the OpenSearch containers gets the local cluster health by default, and runs curl with the exec type
+func TestOpenSearchLivenessProbeType(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:    true,
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				MasterNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				DataNode:   vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+			},
+		},
+	}
+	expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		probe := deployment.Spec.Template.Spec.Containers[0].LivenessProbe
+		assert.NotNil(t, probe.HTTPGet, deployment.Name)
+		assert.Equal(t, "/_cluster/health?local=true", probe.HTTPGet.Path, deployment.Name)
+		assert.Nil(t, probe.Exec, deployment.Name)
+	}
+
+	vmo.Spec.Opensearch.LivenessProbeType = vmcontrollerv1.ExecLivenessProbe
+	expected, err = New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		probe := deployment.Spec.Template.Spec.Containers[0].LivenessProbe
+		assert.Nil(t, probe.HTTPGet, deployment.Name)
+		assert.NotNil(t, probe.Exec, deployment.Name)
+		assert.Equal(t, []string{"sh", "-c", "curl -s -k --fail 'http://127.0.0.1:9200/_cluster/health?local=true'"}, probe.Exec.Command)
+		assert.Equal(t, int32(60), probe.InitialDelaySeconds)
+	}
+}
+
+// TestOpenSearchStartupProbe Tests the startup probe of the OpenSearch deployments
+// GIVEN a VMI with a startup probe configuration
+// WHEN I call New
+// THEN the OpenSearch containers have a startup probe checking the node like their liveness probe, with the
+// configured period and failure threshold
+func TestOpenSearchStartupProbe(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:      true,
+				IngestNode:   vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				MasterNode:   vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				DataNode:     vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				StartupProbe: &vmcontrollerv1.OpenSearchStartupProbe{FailureThreshold: 120},
+			},
+		},
+	}
+	expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		container := deployment.Spec.Template.Spec.Containers[0]
+		assert.NotNil(t, container.StartupProbe, deployment.Name)
+		assert.Equal(t, container.LivenessProbe.ProbeHandler, container.StartupProbe.ProbeHandler, deployment.Name)
+		assert.Equal(t, int32(120), container.StartupProbe.FailureThreshold, deployment.Name)
+		assert.Equal(t, int32(constants.OpenSearchStartupProbeDefaultPeriodSeconds), container.StartupProbe.PeriodSeconds, deployment.Name)
+	}
+}
+
+// TestOpenSearchSnapshotRepository Tests the shared filesystem snapshot repository of the OpenSearch deployments
+// GIVEN a VMI with a snapshot repository
+// WHEN I call New
+// THEN the shared filesystem is mounted on the OpenSearch containers and set as path.repo
+func TestOpenSearchSnapshotRepository(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:    true,
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				MasterNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				DataNode:   vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				SnapshotRepository: &vmcontrollerv1.OpenSearchSnapshotRepository{
+					Path:      "/mnt/snapshots",
+					ClaimName: "snapshots",
+				},
+			},
+		},
+	}
+	expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		podSpec := deployment.Spec.Template.Spec
+		assert.Contains(t, podSpec.Volumes, corev1.Volume{
+			Name: constants.OpenSearchSnapshotRepositoryVolumeName,
+			VolumeSource: corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "snapshots"},
+			},
+		}, deployment.Name)
+		assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+			Name:      constants.OpenSearchSnapshotRepositoryVolumeName,
+			MountPath: "/mnt/snapshots",
+		}, deployment.Name)
+		assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "path.repo", Value: "/mnt/snapshots"}, deployment.Name)
+	}
+}
+
+// TestOpenSearchExtraVolumes Tests the extra volumes of the OpenSearch deployments
+// GIVEN a VMI with an extra volume and volume mount
+// WHEN I call New
+// THEN the extra volume is added to the OpenSearch pods, and the extra volume mount to the OpenSearch containers
+func TestOpenSearchExtraVolumes(t *testing.T) {
+	volume := corev1.Volume{
+		Name: "synonyms",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "synonyms"}},
+		},
+	}
+	volumeMount := corev1.VolumeMount{Name: "synonyms", MountPath: "/usr/share/opensearch/config/analysis", ReadOnly: true}
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:           true,
+				IngestNode:        vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				MasterNode:        vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				DataNode:          vmcontrollerv1.ElasticsearchNode{Replicas: 1},
+				ExtraVolumes:      []corev1.Volume{volume},
+				ExtraVolumeMounts: []corev1.VolumeMount{volumeMount},
+			},
+		},
+	}
+	expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	var openSearchDeployments int
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		openSearchDeployments++
+		podSpec := deployment.Spec.Template.Spec
+		assert.Contains(t, podSpec.Volumes, volume, deployment.Name)
+		assert.Contains(t, podSpec.Containers[0].VolumeMounts, volumeMount, deployment.Name)
+	}
+	assert.NotZero(t, openSearchDeployments)
+}
+
+// TestOpenSearchKeystoreSecret Tests the pre-populated keystore of the OpenSearch data deployments
+// GIVEN a VMI without and with a keystore Secret
+// WHEN I call New
+// THEN the keystore is updated from the object store credentials environment variables without a keystore Secret,
+// and the keystore Secret is mounted and copied at startup instead with a keystore Secret
+func TestOpenSearchKeystoreSecret(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:    true,
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Name: "es-ingest", Replicas: 1},
+				MasterNode: vmcontrollerv1.ElasticsearchNode{Name: "es-master", Replicas: 1},
+				DataNode:   vmcontrollerv1.ElasticsearchNode{Name: "es-data", Replicas: 1},
+			},
+		},
+	}
+	keystoreMount := corev1.VolumeMount{
+		Name:      constants.OpenSearchKeystoreVolumeName,
+		MountPath: constants.OpenSearchKeystoreMountPath,
+		ReadOnly:  true,
+	}
+	for _, keystoreSecret := range []string{"", "opensearch-keystore"} {
+		vmo.Spec.Opensearch.KeystoreSecret = keystoreSecret
+		expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+		assert.NoError(t, err)
+		var dataDeployments int
+		for _, deployment := range expected.Deployments {
+			if !strings.Contains(deployment.Name, "es-data") {
+				continue
+			}
+			dataDeployments++
+			container := deployment.Spec.Template.Spec.Containers[0]
+			var objectStoreEnvVars int
+			for _, env := range container.Env {
+				if env.Name == constants.ObjectStoreAccessKeyVarName || env.Name == constants.ObjectStoreCustomerKeyVarName {
+					objectStoreEnvVars++
+				}
+			}
+			if keystoreSecret == "" {
+				assert.Equal(t, 2, objectStoreEnvVars, deployment.Name)
+				assert.NotContains(t, container.VolumeMounts, keystoreMount, deployment.Name)
+				assert.Contains(t, container.Command[2], "opensearch-keystore add", deployment.Name)
+			} else {
+				assert.Zero(t, objectStoreEnvVars, deployment.Name)
+				assert.Contains(t, container.VolumeMounts, keystoreMount, deployment.Name)
+				assert.NotContains(t, container.Command[2], "opensearch-keystore add", deployment.Name)
+			}
+		}
+		assert.NotZero(t, dataDeployments)
+	}
+}
+
+// TestOpenSearchAllocationAwareness Tests the allocation awareness node attributes of the OpenSearch deployments
+// GIVEN a VMI with allocation awareness attributes, including the availability_domain attribute of the data nodes
+// WHEN I call New
+// THEN the containers have a node.attr env var for each attribute with the value of their node, or None,
+// and the availability_domain attribute of the data nodes is not duplicated
+func TestOpenSearchAllocationAwareness(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:    true,
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Name: "es-ingest", Replicas: 1},
+				MasterNode: vmcontrollerv1.ElasticsearchNode{Name: "es-master", Replicas: 1},
+				DataNode:   vmcontrollerv1.ElasticsearchNode{Name: "es-data", Replicas: 2},
+				AllocationAwareness: &vmcontrollerv1.OpenSearchAllocationAwareness{
+					Attributes: []vmcontrollerv1.OpenSearchAwarenessAttribute{
+						{Name: "zone", NodeValues: map[string]string{"es-data": "zone1"}},
+						{Name: "availability_domain"},
+					},
+				},
+			},
+		},
+	}
+	expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	var openSearchDeployments int
+	for _, deployment := range expected.Deployments {
+		if !strings.Contains(deployment.Name, "es-data") && !strings.Contains(deployment.Name, "es-ingest") {
+			continue
+		}
+		openSearchDeployments++
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		var availabilityDomainEnvVars int
+		for _, envVar := range env {
+			if envVar.Name == "node.attr.availability_domain" {
+				availabilityDomainEnvVars++
+			}
+		}
+		assert.Equal(t, 1, availabilityDomainEnvVars, deployment.Name)
+		if strings.Contains(deployment.Name, "es-data") {
+			assert.Contains(t, env, corev1.EnvVar{Name: "node.attr.zone", Value: "zone1"}, deployment.Name)
+		} else {
+			assert.Contains(t, env, corev1.EnvVar{Name: "node.attr.zone", Value: constants.OpenSearchAwarenessAttributeDefaultValue}, deployment.Name)
+			assert.Contains(t, env, corev1.EnvVar{Name: "node.attr.availability_domain", Value: constants.OpenSearchAwarenessAttributeDefaultValue}, deployment.Name)
+		}
+	}
+	assert.Equal(t, 3, openSearchDeployments)
+}
+
+// TestOpenSearchGCLogging Tests the JVM garbage collection logs of the OpenSearch deployments
+// GIVEN a VMI without and with the garbage collection logs enabled
+// WHEN I call New
+// THEN the garbage collection logging options and the GC logs volume are only added to the ingest and data
+// deployments with the garbage collection logs enabled
+func TestOpenSearchGCLogging(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:    true,
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Name: "es-ingest", Replicas: 1},
+				MasterNode: vmcontrollerv1.ElasticsearchNode{Name: "es-master", Replicas: 1},
+				DataNode:   vmcontrollerv1.ElasticsearchNode{Name: "es-data", Replicas: 1},
+			},
+		},
+	}
+	volumeMount := corev1.VolumeMount{
+		Name:      constants.OpenSearchGCLogsVolumeName,
+		MountPath: constants.OpenSearchGCLogsMountPath,
+	}
+	for _, enabled := range []bool{false, true} {
+		vmo.Spec.Opensearch.EnableGCLogging = &enabled
+		expected, err := New(vmo, fake.NewSimpleClientset(), &config.OperatorConfig{}, map[string]string{})
+		assert.NoError(t, err)
+		var openSearchDeployments int
+		for _, deployment := range expected.Deployments {
+			if !strings.Contains(deployment.Name, "es-data") && !strings.Contains(deployment.Name, "es-ingest") {
+				continue
+			}
+			openSearchDeployments++
+			container := deployment.Spec.Template.Spec.Containers[0]
+			var javaOpts string
+			for _, env := range container.Env {
+				if env.Name == "OPENSEARCH_JAVA_OPTS" {
+					javaOpts = env.Value
+				}
+			}
+			if enabled {
+				assert.Contains(t, javaOpts, constants.OpenSearchGCLoggingJavaOpts, deployment.Name)
+				assert.Contains(t, container.VolumeMounts, volumeMount, deployment.Name)
+			} else {
+				assert.NotContains(t, javaOpts, "-Xlog:gc", deployment.Name)
+				assert.NotContains(t, container.VolumeMounts, volumeMount, deployment.Name)
+			}
+		}
+		assert.Equal(t, 2, openSearchDeployments)
+	}
+}
@@ -28,11 +28,20 @@ func IsOpenSearchDataDeployment(vmoName string, deployment *appsv1.Deployment) b
 	return deployment.Spec.Template.Labels[constants.ServiceAppLabel] == vmoName+"-"+config.ElasticsearchData.Name
 }
 
+// IsOpenSearchDeployment returns true if the deployment is an OpenSearch ingest or data node deployment
+func IsOpenSearchDeployment(vmoName string, deployment *appsv1.Deployment) bool {
+	return IsOpenSearchDataDeployment(vmoName, deployment) ||
+		deployment.Spec.Template.Labels[constants.ServiceAppLabel] == vmoName+"-"+config.ElasticsearchIngest.Name
+}
+
 // Returns a common base deployment structure for all Elasticsearch components
 func (es ElasticsearchBasic) createCommonDeployment(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, node vmcontrollerv1.ElasticsearchNode, componentDetails config.ComponentDetails, index int) *appsv1.Deployment {
 
 	deploymentElement := createDeploymentElementByPvcIndex(vmo, node.Storage, &node.Resources, componentDetails, index, node.Name)
 	esContainer := &deploymentElement.Spec.Template.Spec.Containers[0]
+	if node.Image != "" {
+		esContainer.Image = node.Image
+	}
 	esContainer.Env = append(esContainer.Env,
 		corev1.EnvVar{
 			Name: "NAMESPACE",
@@ -51,8 +60,14 @@ func (es ElasticsearchBasic) createCommonDeployment(vmo *vmcontrollerv1.Verrazza
 			},
 		},
 		corev1.EnvVar{Name: "cluster.name", Value: vmo.Name},
-		corev1.EnvVar{Name: "logger.org.opensearch", Value: "info"},
 	)
+	esContainer.Env = append(esContainer.Env, resources.GetOpenSearchLoggerEnvVars(vmo)...)
+	esContainer.Env = append(esContainer.Env, resources.GetOpenSearchCircuitBreakerEnvVars(vmo)...)
+	esContainer.Env = append(esContainer.Env, resources.GetOpenSearchTransportCompressionEnvVars(vmo)...)
+	esContainer.Env = append(esContainer.Env, resources.GetOpenSearchMaxClauseCountEnvVars(vmo)...)
+	resources.AddOpenSearchSnapshotRepository(vmo, &deploymentElement.Spec.Template.Spec, esContainer)
+	resources.AddOpenSearchExtraVolumes(vmo, &deploymentElement.Spec.Template.Spec, esContainer)
+	resources.AddOpenSearchGCLogsVolume(vmo, &deploymentElement.Spec.Template.Spec, esContainer)
 
 	esContainer.Ports = []corev1.ContainerPort{
 		{Name: "http", ContainerPort: int32(constants.OSHTTPPort)},
@@ -66,6 +81,8 @@ func (es ElasticsearchBasic) createCommonDeployment(vmo *vmcontrollerv1.Verrazza
 		esContainer.LivenessProbe.PeriodSeconds = 20
 		esContainer.LivenessProbe.FailureThreshold = 5
 	}
+	resources.SetOpenSearchLivenessProbe(vmo, esContainer.LivenessProbe)
+	resources.SetOpenSearchStartupProbe(vmo, esContainer)
 	if esContainer.ReadinessProbe != nil {
 		esContainer.ReadinessProbe.InitialDelaySeconds = 60
 		esContainer.ReadinessProbe.TimeoutSeconds = 3
@@ -90,6 +107,7 @@ func (es ElasticsearchBasic) createCommonDeployment(vmo *vmcontrollerv1.Verrazza
 	podSecurityContext := &corev1.PodSecurityContext{
 		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
 	}
+	resources.SetOpenSearchSecurityContext(vmo, podSecurityContext, esContainer.SecurityContext)
 	deploymentElement.Spec.Template.Spec.SecurityContext = podSecurityContext
 
 	return deploymentElement
@@ -112,6 +130,7 @@ func (es ElasticsearchBasic) createElasticsearchIngestDeploymentElements(vmo *vm
 		if node.JavaOpts != "" {
 			javaOpts = node.JavaOpts
 		}
+		javaOpts = resources.GetOpenSearchJavaOpts(vmo, javaOpts)
 
 		ingestDeployment := es.createCommonDeployment(vmo, node, config.ElasticsearchIngest, -1)
 		ingestDeployment.Spec.Replicas = resources.NewVal(node.Replicas)
@@ -128,6 +147,8 @@ func (es ElasticsearchBasic) createElasticsearchIngestDeploymentElements(vmo *vm
 				Value: "true",
 			},
 		)
+		ingestDeployment.Spec.Template.Spec.Containers[0].Env = append(ingestDeployment.Spec.Template.Spec.Containers[0].Env,
+			resources.GetOpenSearchAwarenessEnvVars(vmo, node.Name, ingestDeployment.Spec.Template.Spec.Containers[0].Env)...)
 		// add the required istio annotations to allow inter-es component communication
 		if ingestDeployment.Spec.Template.Annotations == nil {
 			ingestDeployment.Spec.Template.Annotations = make(map[string]string)
@@ -136,7 +157,7 @@ func (es ElasticsearchBasic) createElasticsearchIngestDeploymentElements(vmo *vm
 		ingestDeployment.Spec.Template.Spec.Containers[0].Command = []string{
 			"sh",
 			"-c",
-			fmt.Sprintf(resources.OpenSearchIngestCmdTmpl, resources.GetOSPluginsInstallTmpl(resources.GetOpenSearchPluginList(vmo), resources.OSPluginsInstallCmd, resources.OSIngestPluginsInstallTmpl)),
+			fmt.Sprintf(resources.OpenSearchIngestCmdTmpl, resources.GetOSPluginsInstallTmpl(resources.GetOpenSearchPluginList(vmo), resources.OSPluginsInstallCmd, resources.OSIngestPluginsInstallTmpl), resources.OpenSearchPreStartCmd(vmo.Spec.Opensearch.PreStartScript)),
 		}
 		ingestDeployment.Spec.Template.Annotations["traffic.sidecar.istio.io/excludeInboundPorts"] = fmt.Sprintf("%d", constants.OSTransportPort)
 		ingestDeployment.Spec.Template.Annotations["traffic.sidecar.istio.io/excludeOutboundPorts"] = fmt.Sprintf("%d", constants.OSTransportPort)
@@ -163,6 +184,7 @@ func (es ElasticsearchBasic) createElasticsearchDataDeploymentElements(vmo *vmco
 		if node.JavaOpts != "" {
 			javaOpts = node.JavaOpts
 		}
+		javaOpts = resources.GetOpenSearchJavaOpts(vmo, javaOpts)
 		for i := 0; i < int(node.Replicas); i++ {
 			dataDeployment := es.createCommonDeployment(vmo, node, config.ElasticsearchData, i)
 
@@ -201,6 +223,7 @@ func (es ElasticsearchBasic) createElasticsearchDataDeploymentElements(vmo *vmco
 				FSGroup:        &elasticsearchGid,
 				SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
 			}
+			resources.SetOpenSearchSecurityContext(vmo, dataDeployment.Spec.Template.Spec.SecurityContext, dataDeployment.Spec.Template.Spec.Containers[0].SecurityContext)
 
 			dataDeployment.Spec.Strategy.Type = appsv1.RecreateDeploymentStrategyType
 			dataDeployment.Spec.Strategy.RollingUpdate = nil
@@ -209,44 +232,25 @@ func (es ElasticsearchBasic) createElasticsearchDataDeploymentElements(vmo *vmco
 				corev1.EnvVar{Name: "node.attr.availability_domain", Value: availabilityDomain},
 				corev1.EnvVar{Name: "node.roles", Value: nodes.GetRolesString(&nodeList[idx])},
 				corev1.EnvVar{Name: "OPENSEARCH_JAVA_OPTS", Value: javaOpts},
-				corev1.EnvVar{Name: constants.ObjectStoreAccessKeyVarName,
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: constants.VerrazzanoBackupScrtName,
-							},
-							Key: constants.ObjectStoreAccessKey,
-							Optional: func(opt bool) *bool {
-								return &opt
-							}(true),
-						},
-					},
-				},
-				corev1.EnvVar{Name: constants.ObjectStoreCustomerKeyVarName,
-					ValueFrom: &corev1.EnvVarSource{
-						SecretKeyRef: &corev1.SecretKeySelector{
-							LocalObjectReference: corev1.LocalObjectReference{
-								Name: constants.VerrazzanoBackupScrtName,
-							},
-							Key: constants.ObjectStoreCustomerKey,
-							Optional: func(opt bool) *bool {
-								return &opt
-							}(true),
-						},
-					},
-				},
+			)
+			dataDeployment.Spec.Template.Spec.Containers[0].Env = append(dataDeployment.Spec.Template.Spec.Containers[0].Env,
+				resources.GetOpenSearchObjectStoreEnvVars(vmo)...)
+			dataDeployment.Spec.Template.Spec.Containers[0].Env = append(dataDeployment.Spec.Template.Spec.Containers[0].Env,
 				corev1.EnvVar{
 					Name:  constants.DisableSecurityPluginOS,
 					Value: "true",
 				},
 			)
+			dataDeployment.Spec.Template.Spec.Containers[0].Env = append(dataDeployment.Spec.Template.Spec.Containers[0].Env,
+				resources.GetOpenSearchAwarenessEnvVars(vmo, node.Name, dataDeployment.Spec.Template.Spec.Containers[0].Env)...)
 
 			// Adding command for add keystore values and OS plugins installation at pod bootup
 			dataDeployment.Spec.Template.Spec.Containers[0].Command = []string{
 				"sh",
 				"-c",
-				resources.CreateOpenSearchContainerCMD(javaOpts, resources.GetOpenSearchPluginList(vmo), resources.OSDataPluginsInstallTmpl),
+				resources.CreateOpenSearchContainerCMD(javaOpts, resources.GetOpenSearchPluginList(vmo), resources.OSDataPluginsInstallTmpl, resources.IsOpenSearchKeystoreMounted(vmo), vmo.Spec.Opensearch.PreStartScript),
 			}
+			resources.AddOpenSearchKeystore(vmo, &dataDeployment.Spec.Template.Spec, &dataDeployment.Spec.Template.Spec.Containers[0])
 
 			// add the required istio annotations to allow inter-es component communication
 			if dataDeployment.Spec.Template.Annotations == nil {
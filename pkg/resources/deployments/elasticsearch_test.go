@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package deployments
@@ -68,6 +68,25 @@ func TestIsOpenSearchDeployment(t *testing.T) {
 	}
 }
 
+func TestIsOpenSearchIngestOrDataDeployment(t *testing.T) {
+	createDeploy := func(app string) *appsv1.Deployment {
+		return &appsv1.Deployment{
+			Spec: appsv1.DeploymentSpec{
+				Template: corev1.PodTemplateSpec{
+					ObjectMeta: v1.ObjectMeta{
+						Labels: map[string]string{"app": app},
+					},
+				},
+			},
+		}
+	}
+
+	assert.True(t, IsOpenSearchDeployment("system", createDeploy("system-es-data")))
+	assert.True(t, IsOpenSearchDeployment("system", createDeploy("system-es-ingest")))
+	assert.False(t, IsOpenSearchDeployment("system", createDeploy("system-es-master")))
+	assert.False(t, IsOpenSearchDeployment("system", createDeploy("system-grafana")))
+}
+
 func TestElasticsearchDefaultDeployments1(t *testing.T) {
 	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
 		ObjectMeta: v1.ObjectMeta{
@@ -144,3 +163,255 @@ func getEnvVarValue(envVarName string, envVarList []corev1.EnvVar) string {
 	}
 	return ""
 }
+
+// TestElasticsearchDeploymentsLoggers tests the OpenSearch logger env vars of the OpenSearch deployments
+// GIVEN a VMI with OpenSearch loggers
+// WHEN I call New
+// THEN a logger env var is set in the ingest and data deployments for each logger, merged with the default org.opensearch logger
+// AND an error is returned if a logger level is invalid
+func TestElasticsearchDeploymentsLoggers(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "myVMO",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1, Name: config.ElasticsearchIngest.Name},
+				DataNode: vmcontrollerv1.ElasticsearchNode{
+					Replicas: 1,
+					Name:     config.ElasticsearchData.Name,
+					Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+				},
+				Enabled: true,
+				Loggers: map[string]string{
+					"org.opensearch.discovery":   "debug",
+					"org.opensearch.index.shard": "warn",
+				},
+			},
+		},
+	}
+	expected, err := New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expected.OpenSearchIngestDeployments)
+	assert.Equal(t, 1, expected.OpenSearchDataDeployments)
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, "info", getEnvVarValue("logger.org.opensearch", env))
+		assert.Equal(t, "debug", getEnvVarValue("logger.org.opensearch.discovery", env))
+		assert.Equal(t, "warn", getEnvVarValue("logger.org.opensearch.index.shard", env))
+	}
+
+	vmo.Spec.Opensearch.Loggers["org.opensearch.discovery"] = "verbose"
+	_, err = New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.Error(t, err)
+}
+
+// TestElasticsearchDeploymentsPodSecurityContext tests the security contexts of the OpenSearch deployments
+// GIVEN a VMI with an OpenSearch pod security context overriding only the user and the FSGroup
+// WHEN I call New
+// THEN the ingest and data deployments run as the overridden user and FSGroup
+// AND the init containers are unchanged
+func TestElasticsearchDeploymentsPodSecurityContext(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "myVMO",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1, Name: config.ElasticsearchIngest.Name},
+				DataNode: vmcontrollerv1.ElasticsearchNode{
+					Replicas: 1,
+					Name:     config.ElasticsearchData.Name,
+					Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+				},
+				Enabled: true,
+				PodSecurityContext: &vmcontrollerv1.OpenSearchPodSecurityContext{
+					RunAsUser: resources.New64Val(2000),
+					FSGroup:   resources.New64Val(3000),
+				},
+			},
+		},
+	}
+	expected, err := New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	var openSearchDeployments int
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		openSearchDeployments++
+		podSecurityContext := deployment.Spec.Template.Spec.SecurityContext
+		assert.Equal(t, int64(2000), *deployment.Spec.Template.Spec.Containers[0].SecurityContext.RunAsUser)
+		assert.Equal(t, int64(2000), *podSecurityContext.RunAsUser)
+		assert.Equal(t, int64(3000), *podSecurityContext.FSGroup)
+		assert.Nil(t, podSecurityContext.RunAsGroup)
+		assert.Equal(t, int64(0), *deployment.Spec.Template.Spec.InitContainers[0].SecurityContext.RunAsUser)
+	}
+	assert.Equal(t, 2, openSearchDeployments)
+}
+
+// TestElasticsearchDeploymentsCircuitBreakers tests the circuit breaker env vars of the OpenSearch deployments
+// GIVEN a VMI with OpenSearch circuit breaker limits
+// WHEN I call New
+// THEN an env var is set in the ingest and data deployments for each circuit breaker limit
+// AND an error is returned if a limit is invalid
+func TestElasticsearchDeploymentsCircuitBreakers(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "myVMO",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1, Name: config.ElasticsearchIngest.Name},
+				DataNode: vmcontrollerv1.ElasticsearchNode{
+					Replicas: 1,
+					Name:     config.ElasticsearchData.Name,
+					Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+				},
+				Enabled: true,
+				CircuitBreakers: &vmcontrollerv1.OpenSearchCircuitBreakers{
+					Total:     "75%",
+					Request:   "512mb",
+					Fielddata: "40.5%",
+				},
+			},
+		},
+	}
+	expected, err := New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, expected.OpenSearchDataDeployments)
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, "75%", getEnvVarValue("indices.breaker.total.limit", env))
+		assert.Equal(t, "512mb", getEnvVarValue("indices.breaker.request.limit", env))
+		assert.Equal(t, "40.5%", getEnvVarValue("indices.breaker.fielddata.limit", env))
+	}
+
+	vmo.Spec.Opensearch.CircuitBreakers.Total = "75"
+	_, err = New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.Error(t, err)
+}
+
+// TestElasticsearchDeploymentsTransportCompression tests the transport compression env vars of the OpenSearch deployments
+// GIVEN a VMI with an OpenSearch transport compression of the indexing data
+// WHEN I call New
+// THEN the transport compression env vars are set in the ingest and data deployments
+// AND an error is returned if the compression is invalid
+func TestElasticsearchDeploymentsTransportCompression(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "myVMO",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1, Name: config.ElasticsearchIngest.Name},
+				DataNode: vmcontrollerv1.ElasticsearchNode{
+					Replicas: 1,
+					Name:     config.ElasticsearchData.Name,
+					Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+				},
+				Enabled:              true,
+				TransportCompression: "indexing_data",
+			},
+		},
+	}
+	expected, err := New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	openSearchDeployments := 0
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		openSearchDeployments++
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		assert.Equal(t, "indexing_data", getEnvVarValue("transport.compress", env))
+		assert.Equal(t, "deflate", getEnvVarValue("transport.compression_scheme", env))
+	}
+	assert.Equal(t, 2, openSearchDeployments)
+
+	vmo.Spec.Opensearch.TransportCompression = "false"
+	_, err = New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.Error(t, err)
+}
+
+// TestElasticsearchDeploymentsMaxClauseCount tests the maximum number of clauses of a bool query of the OpenSearch
+// deployments
+// GIVEN a VMI with a maximum clause count
+// WHEN I call New
+// THEN the indices.query.bool.max_clause_count env var is set in the ingest and data deployments
+func TestElasticsearchDeploymentsMaxClauseCount(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "myVMO",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1, Name: config.ElasticsearchIngest.Name},
+				DataNode: vmcontrollerv1.ElasticsearchNode{
+					Replicas: 1,
+					Name:     config.ElasticsearchData.Name,
+					Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+				},
+				Enabled:        true,
+				MaxClauseCount: resources.NewVal(2048),
+			},
+		},
+	}
+	expected, err := New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	openSearchDeployments := 0
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		openSearchDeployments++
+		assert.Equal(t, "2048", getEnvVarValue("indices.query.bool.max_clause_count", deployment.Spec.Template.Spec.Containers[0].Env))
+	}
+	assert.Equal(t, 2, openSearchDeployments)
+}
+
+// TestElasticsearchDeploymentsNodeImage tests the images of the OpenSearch deployments
+// GIVEN a VMI whose data nodes have an image override and whose ingest nodes do not
+// WHEN I call New
+// THEN the data deployment uses the image of its node, and the ingest deployment uses the image of the operator config
+func TestElasticsearchDeploymentsNodeImage(t *testing.T) {
+	const dataImage = "example.com/opensearch-analyzers:2.3.0"
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "myVMO",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				IngestNode: vmcontrollerv1.ElasticsearchNode{Replicas: 1, Name: config.ElasticsearchIngest.Name},
+				DataNode: vmcontrollerv1.ElasticsearchNode{
+					Replicas: 1,
+					Name:     config.ElasticsearchData.Name,
+					Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+					Image:    dataImage,
+				},
+				Enabled: true,
+			},
+		},
+	}
+	expected, err := New(vmo, nil, &config.OperatorConfig{}, map[string]string{})
+	assert.NoError(t, err)
+	openSearchDeployments := 0
+	for _, deployment := range expected.Deployments {
+		if !IsOpenSearchDeployment(vmo.Name, deployment) {
+			continue
+		}
+		openSearchDeployments++
+		if IsOpenSearchDataDeployment(vmo.Name, deployment) {
+			assert.Equal(t, dataImage, deployment.Spec.Template.Spec.Containers[0].Image)
+		} else {
+			assert.Equal(t, config.ElasticsearchIngest.Image, deployment.Spec.Template.Spec.Containers[0].Image)
+		}
+	}
+	assert.Equal(t, 2, openSearchDeployments)
+}
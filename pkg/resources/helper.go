@@ -9,8 +9,10 @@ import (
 	"math/big"
 	"os"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
@@ -22,26 +24,39 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 var (
 	runes = []rune("abcdefghijklmnopqrstuvwxyz0123456789")
+	// clusterDomain is the DNS domain of the cluster, used to build the fully qualified names of the services
+	clusterDomain = constants.DefaultClusterDomain
 )
 
 const (
-	serviceClusterLocal     = ".svc.cluster.local"
 	masterHTTPEndpoint      = "VMO_MASTER_HTTP_ENDPOINT"
 	dashboardsHTTPEndpoint  = "VMO_DASHBOARDS_HTTP_ENDPOINT"
+	grafanaHTTPEndpoint     = "VMO_GRAFANA_HTTP_ENDPOINT"
 	OpenSearchIngestCmdTmpl = `#!/usr/bin/env bash -e
 	set -euo pipefail
     %s
+	%s
 	/usr/local/bin/docker-entrypoint.sh
     `
 	OpenSearchDashboardCmdTmpl = `#!/usr/bin/env bash -e
     %s
 	./opensearch-dashboards-docker-entrypoint.sh`
 	containerCmdTmpl = `#!/usr/bin/env bash -e
-	# Updating opensearch keystore with keys
+	%s
+	
+	%s
+
+    %s 
+	
+	%s
+	/usr/local/bin/docker-entrypoint.sh`
+
+	keystoreFromEnvCmd = `# Updating opensearch keystore with keys
 	# required for the repository-s3 plugin
 	if [ "${OBJECT_STORE_ACCESS_KEY_ID:-}" ]; then
 		echo "Updating object store access key..."
@@ -50,13 +65,10 @@ const (
 	if [ "${OBJECT_STORE_SECRET_KEY_ID:-}" ]; then
 		echo "Updating object store secret key..."
 		echo $OBJECT_STORE_SECRET_KEY_ID | /usr/share/opensearch/bin/opensearch-keystore add --stdin --force s3.client.default.secret_key;
-	fi
-	
-	%s
-
-    %s 
-	
-	/usr/local/bin/docker-entrypoint.sh`
+	fi`
+	keystoreFromSecretCmd = `# Copying the pre-populated opensearch keystore mounted from a secret
+	echo "Copying mounted opensearch keystore..."
+	cp ` + constants.OpenSearchKeystoreMountPath + "/" + constants.OpenSearchKeystoreKey + ` /usr/share/opensearch/config/opensearch.keystore`
 
 	jvmOptsDisableCmd = `
 	# Disable the jvm heap settings in jvm.options
@@ -87,7 +99,6 @@ const (
      fi
     `
 	DashboardsPluginInstalledCheck = `
-     exit_status=$?
      if [ $exit_status -ne 0 ]; then
           if grep -iq "plugin .* already exists" /tmp/error.log; then
                echo "Plugin already installed. Skipping installation"
@@ -114,17 +125,95 @@ const (
      %s
     ` + PluginInstalledCheck
 	OSDashboardPluginsInstallTmpl = `
-     # Install OS plugins that are not bundled with OS
-     %s
+     # Install OS plugins that are not bundled with OS, retrying transient download failures
+     for attempt in $(seq 1 ` + osdPluginInstallAttempts + `); do
+          %s
+          exit_status=$?
+          if [ $exit_status -eq 0 ] || grep -iq "plugin .* already exists" /tmp/error.log; then
+               break
+          fi
+          if [ $attempt -lt ` + osdPluginInstallAttempts + ` ]; then
+               echo "Plugin installation attempt $attempt failed, retrying in ` + osdPluginInstallRetryDelaySeconds + ` seconds..."
+               rm -rf /usr/share/opensearch-dashboards/plugins/.plugin.installing
+               sleep ` + osdPluginInstallRetryDelaySeconds + `
+          fi
+     done
     ` + DashboardsPluginInstalledCheck
+	// Number of times an OpenSearch Dashboards plugin installation is attempted, and the delay between attempts
+	osdPluginInstallAttempts          = "5"
+	osdPluginInstallRetryDelaySeconds = "10"
+
 	OSPluginsInstallCmd = `
     /usr/share/opensearch/bin/opensearch-plugin install -b %s 2>/tmp/error.log
 	`
 	OSDashboardPluginsInstallCmd = `
     /usr/share/opensearch-dashboards/bin/opensearch-dashboards-plugin install %s 2>/tmp/error.log
 	`
+	// OSPluginChecksumVerifyCmd downloads a plugin and verifies its checksum. It is chained with && to the plugin
+	// installation cmd, so the plugin is only installed from the downloaded file when the checksum matches.
+	OSPluginChecksumVerifyCmd = `
+    curl -sSfL -o %[1]s '%[2]s' 2>/tmp/error.log && echo "%[3]s  %[1]s" | %[4]s -c - >/tmp/error.log 2>&1 &&`
+	// OSPluginInvalidChecksumCmd fails the plugin installation when the checksum of a plugin entry is malformed
+	OSPluginInvalidChecksumCmd = `
+    echo "Invalid checksum for plugin %s" >/tmp/error.log; false
+	`
+	// openSearchClusterHealthPath is the path of the OpenSearch cluster health, as seen by the elected master
+	openSearchClusterHealthPath = "/_cluster/health"
+	// openSearchLocalClusterHealthPath is the path of the OpenSearch cluster health, as seen by the requested node
+	openSearchLocalClusterHealthPath = openSearchClusterHealthPath + "?local=true"
+	// pluginChecksumSeparator separates a plugin URL from its checksum in a plugin install list entry,
+	// e.g. https://example.com/plugin.zip#sha256=<checksum>
+	pluginChecksumSeparator = "#"
+)
+
+// pluginChecksumTools maps the supported plugin checksum algorithms to the tool verifying them, and the length of their hex encoded checksum
+var pluginChecksumTools = map[string]struct {
+	tool   string
+	length int
+}{
+	"sha256": {"sha256sum", 64},
+	"sha512": {"sha512sum", 128},
+}
+
+var hexRegex = regexp.MustCompile(`^[0-9a-fA-F]+$`)
+
+const (
+	openSearchLoggerPrefix       = "logger."
+	defaultOpenSearchLogger      = "org.opensearch"
+	defaultOpenSearchLoggerLevel = "info"
 )
 
+// preStartScriptForbiddenRegex matches the OpenSearch entrypoint, which would start OpenSearch from the pre-start script
+var preStartScriptForbiddenRegex = regexp.MustCompile(`docker-entrypoint`)
+
+// circuitBreakerLimitRegex matches the limits of the OpenSearch circuit breakers: a percentage of the JVM heap or a byte size
+var circuitBreakerLimitRegex = regexp.MustCompile(`^(?i)([0-9]+(\.[0-9]+)?%|[0-9]+(\.[0-9]+)?(b|kb|mb|gb|tb|pb))$`)
+
+// openSearchTransportCompressions are the transport compression settings of the VMI, mapped to the value of transport.compress
+var openSearchTransportCompressions = map[string]string{
+	"none":          "false",
+	"indexing_data": "indexing_data",
+	"true":          "true",
+}
+
+// grafanaPluginRegex matches the Grafana plugins of the VMI: a plugin ID, optionally followed by a space and a version
+var grafanaPluginRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*( [a-zA-Z0-9][a-zA-Z0-9.+-]*)?$`)
+
+// grafanaCookieSameSites are the SameSite attributes of the Grafana cookies known by Grafana
+var grafanaCookieSameSites = map[string]bool{"lax": true, "strict": true, "none": true, "disabled": true}
+
+// openSearchLoggerLevels are the log levels known by OpenSearch
+var openSearchLoggerLevels = map[string]bool{
+	"off":   true,
+	"fatal": true,
+	"error": true,
+	"warn":  true,
+	"info":  true,
+	"debug": true,
+	"trace": true,
+	"all":   true,
+}
+
 // CopyImmutableEnvVars copies the initial master node environment variable from an existing container to an expected container
 // cluster.initial_master_nodes shouldn't be changed after it's set.
 func CopyImmutableEnvVars(expected, existing []corev1.Container, containerName string) {
@@ -177,6 +266,20 @@ func SetEnvVar(container *corev1.Container, envVar *corev1.EnvVar) {
 	container.Env = append(container.Env, *envVar)
 }
 
+// SetClusterDomain sets the DNS domain of the cluster, for clusters not using the default "cluster.local" domain
+func SetClusterDomain(domain string) error {
+	if errs := validation.IsDNS1123Subdomain(domain); len(errs) > 0 {
+		return fmt.Errorf("invalid cluster domain %s: %s", domain, strings.Join(errs, ", "))
+	}
+	clusterDomain = domain
+	return nil
+}
+
+// ServiceDomain returns the DNS suffix of the fully qualified names of the services, such as ".svc.cluster.local"
+func ServiceDomain() string {
+	return ".svc." + clusterDomain
+}
+
 func GetOpenSearchHTTPEndpoint(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) string {
 	// The master HTTP port may be overridden if necessary.
 	// This can be useful in situations where the VMO does not have direct access to the cluster service,
@@ -188,21 +291,92 @@ func GetOpenSearchHTTPEndpoint(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance)
 	return fmt.Sprintf("http://%s-http.%s%s:%d",
 		GetMetaName(vmo.Name, config.ElasticsearchMaster.Name),
 		vmo.Namespace,
-		serviceClusterLocal,
+		ServiceDomain(),
 		constants.OSHTTPPort)
 }
 
+// IsOpenSearchPaused returns true if reconciling of the OpenSearch cluster is paused
+func IsOpenSearchPaused(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return vmo.Spec.Opensearch.Paused != nil && *vmo.Spec.Opensearch.Paused
+}
+
+// IsOpenSearchDashboardsManaged returns true if the OpenSearch Dashboards deployment is managed by the VMO
+func IsOpenSearchDashboardsManaged(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return vmo.Spec.OpensearchDashboards.Managed == nil || *vmo.Spec.OpensearchDashboards.Managed
+}
+
+// SetOpenSearchSecurityContext overrides the user and groups of the OpenSearch pod and container security contexts
+// with the ones set in the VMI
+func SetOpenSearchSecurityContext(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSecurityContext *corev1.PodSecurityContext, containerSecurityContext *corev1.SecurityContext) {
+	override := vmo.Spec.Opensearch.PodSecurityContext
+	if override == nil {
+		return
+	}
+	if override.RunAsUser != nil {
+		// the container security context takes precedence over the pod security context
+		containerSecurityContext.RunAsUser = New64Val(*override.RunAsUser)
+		podSecurityContext.RunAsUser = New64Val(*override.RunAsUser)
+	}
+	if override.RunAsGroup != nil {
+		podSecurityContext.RunAsGroup = New64Val(*override.RunAsGroup)
+	}
+	if override.FSGroup != nil {
+		podSecurityContext.FSGroup = New64Val(*override.FSGroup)
+	}
+}
+
+// GetOpenSearchDataOwner returns the UID and GID owning the data directory of the OpenSearch nodes, which are the
+// OpenSearch user and group unless overridden in the VMI. The data directory is owned by the FSGroup if set, which
+// owns the volumes of the pods, otherwise by the group the containers run as.
+func GetOpenSearchDataOwner(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (int64, int64) {
+	uid, gid := int64(constants.PodUser), int64(constants.PodUser)
+	override := vmo.Spec.Opensearch.PodSecurityContext
+	if override == nil {
+		return uid, gid
+	}
+	if override.RunAsUser != nil {
+		uid = *override.RunAsUser
+	}
+	if override.FSGroup != nil {
+		gid = *override.FSGroup
+	} else if override.RunAsGroup != nil {
+		gid = *override.RunAsGroup
+	}
+	return uid, gid
+}
+
 func GetOpenSearchDashboardsHTTPEndpoint(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) string {
 	dashboardsServiceEndpoint := os.Getenv(dashboardsHTTPEndpoint)
 	if len(dashboardsServiceEndpoint) > 0 {
 		return dashboardsServiceEndpoint
 	}
-	return fmt.Sprintf("http://%s.%s%s:%d", GetMetaName(vmo.Name, config.OpenSearchDashboards.Name),
+	return fmt.Sprintf("%s://%s.%s%s:%d", GetOpenSearchDashboardsScheme(vmo), GetMetaName(vmo.Name, config.OpenSearchDashboards.Name),
 		vmo.Namespace,
-		serviceClusterLocal,
+		ServiceDomain(),
 		constants.OSDashboardsHTTPPort)
 }
 
+// GetOpenSearchDashboardsScheme returns https if OpenSearch Dashboards serves HTTPS with the certificate of its TLS
+// Secret, http otherwise
+func GetOpenSearchDashboardsScheme(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) string {
+	if vmo.Spec.OpensearchDashboards.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+// GetGrafanaHTTPEndpoint returns the HTTP endpoint of the Grafana service of the VMI
+func GetGrafanaHTTPEndpoint(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) string {
+	grafanaServiceEndpoint := os.Getenv(grafanaHTTPEndpoint)
+	if len(grafanaServiceEndpoint) > 0 {
+		return grafanaServiceEndpoint
+	}
+	return fmt.Sprintf("http://%s.%s%s:%d", GetMetaName(vmo.Name, config.Grafana.Name),
+		vmo.Namespace,
+		ServiceDomain(),
+		config.Grafana.Port)
+}
+
 func GetOwnerLabels(owner string) map[string]string {
 	return map[string]string{
 		"owner": owner,
@@ -310,6 +484,15 @@ func GetReplicasForComponent(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, c
 	return 0
 }
 
+// GetOpenSearchDashboardsReplicas returns the number of OpenSearch Dashboards replicas, zero when OpenSearch Dashboards
+// is scaled to zero whatever its replicas
+func GetOpenSearchDashboardsReplicas(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) int32 {
+	if vmo.Spec.OpensearchDashboards.ScaleToZero {
+		return 0
+	}
+	return vmo.Spec.OpensearchDashboards.Replicas
+}
+
 // GetNextStringInSequence returns the next string in the incremental sequence given a string
 func GetNextStringInSequence(name string) string {
 	tokens := strings.Split(name, "-")
@@ -393,6 +576,205 @@ func CreateContainerElement(vmoStorage *vmcontrollerv1.Storage,
 	}
 }
 
+// SetOpenSearchLivenessProbe replaces the handler of the liveness probe of an OpenSearch container with the one of
+// the liveness probe type of the VMI. The handler is left unchanged if the VMI does not set a liveness probe type,
+// except that an HTTP GET of the cluster health only gets the local cluster health.
+func SetOpenSearchLivenessProbe(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, probe *corev1.Probe) {
+	if probe == nil {
+		return
+	}
+	// the local cluster health does not depend on an elected master, so the nodes are not restarted with the master
+	switch vmo.Spec.Opensearch.LivenessProbeType {
+	case "":
+		if probe.HTTPGet != nil && probe.HTTPGet.Path == openSearchClusterHealthPath {
+			probe.HTTPGet.Path = openSearchLocalClusterHealthPath
+		}
+	case vmcontrollerv1.TCPLivenessProbe:
+		probe.ProbeHandler = corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(constants.OSTransportPort),
+			},
+		}
+	case vmcontrollerv1.HTTPLivenessProbe:
+		probe.ProbeHandler = corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path:   openSearchLocalClusterHealthPath,
+				Port:   intstr.FromInt(constants.OSHTTPPort),
+				Scheme: "HTTP",
+			},
+		}
+	case vmcontrollerv1.ExecLivenessProbe:
+		probe.ProbeHandler = corev1.ProbeHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{
+					"sh",
+					"-c",
+					fmt.Sprintf("curl -s -k --fail 'http://127.0.0.1:%d%s'", constants.OSHTTPPort, openSearchLocalClusterHealthPath),
+				},
+			},
+		}
+	}
+}
+
+// SetOpenSearchStartupProbe sets the startup probe of an OpenSearch container, which checks the node like its liveness
+// probe, so the liveness probe only begins once the node has started
+func SetOpenSearchStartupProbe(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, container *corev1.Container) {
+	if container.LivenessProbe == nil {
+		return
+	}
+	periodSeconds := int32(constants.OpenSearchStartupProbeDefaultPeriodSeconds)
+	failureThreshold := int32(constants.OpenSearchStartupProbeDefaultFailureThreshold)
+	if startupProbe := vmo.Spec.Opensearch.StartupProbe; startupProbe != nil {
+		if startupProbe.PeriodSeconds > 0 {
+			periodSeconds = startupProbe.PeriodSeconds
+		}
+		if startupProbe.FailureThreshold > 0 {
+			failureThreshold = startupProbe.FailureThreshold
+		}
+	}
+	container.StartupProbe = &corev1.Probe{
+		ProbeHandler:     *container.LivenessProbe.ProbeHandler.DeepCopy(),
+		TimeoutSeconds:   container.LivenessProbe.TimeoutSeconds,
+		PeriodSeconds:    periodSeconds,
+		FailureThreshold: failureThreshold,
+	}
+}
+
+// AddOpenSearchSnapshotRepository mounts the shared filesystem snapshot repository of the VMI on an OpenSearch container,
+// and sets it as the path.repo of the node so an fs snapshot repository can be registered
+func AddOpenSearchSnapshotRepository(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec *corev1.PodSpec, container *corev1.Container) {
+	repository := vmo.Spec.Opensearch.SnapshotRepository
+	if repository == nil || repository.Path == "" || repository.ClaimName == "" {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: constants.OpenSearchSnapshotRepositoryVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: repository.ClaimName,
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      constants.OpenSearchSnapshotRepositoryVolumeName,
+		MountPath: repository.Path,
+	})
+	container.Env = append(container.Env, corev1.EnvVar{Name: "path.repo", Value: repository.Path})
+}
+
+// AddOpenSearchExtraVolumes adds the extra volumes of the VMI to an OpenSearch pod, and the extra volume mounts to its
+// OpenSearch container
+func AddOpenSearchExtraVolumes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec *corev1.PodSpec, container *corev1.Container) {
+	for _, volume := range vmo.Spec.Opensearch.ExtraVolumes {
+		podSpec.Volumes = append(podSpec.Volumes, *volume.DeepCopy())
+	}
+	for _, volumeMount := range vmo.Spec.Opensearch.ExtraVolumeMounts {
+		container.VolumeMounts = append(container.VolumeMounts, *volumeMount.DeepCopy())
+	}
+}
+
+// IsOpenSearchGCLoggingEnabled returns true if the JVM garbage collection logs of the OpenSearch nodes are enabled
+func IsOpenSearchGCLoggingEnabled(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return vmo.Spec.Opensearch.EnableGCLogging != nil && *vmo.Spec.Opensearch.EnableGCLogging
+}
+
+// GetOpenSearchJavaOpts returns the JVM options of an OpenSearch node, with the garbage collection logging options
+// appended if the VMI enables the garbage collection logs
+func GetOpenSearchJavaOpts(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, javaOpts string) string {
+	if !IsOpenSearchGCLoggingEnabled(vmo) {
+		return javaOpts
+	}
+	return strings.TrimSpace(javaOpts + " " + constants.OpenSearchGCLoggingJavaOpts)
+}
+
+// AddOpenSearchGCLogsVolume adds the volume the JVM garbage collection logs are written to to an OpenSearch pod, and
+// mounts it on its OpenSearch container, if the VMI enables the garbage collection logs
+func AddOpenSearchGCLogsVolume(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec *corev1.PodSpec, container *corev1.Container) {
+	if !IsOpenSearchGCLoggingEnabled(vmo) {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: constants.OpenSearchGCLogsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      constants.OpenSearchGCLogsVolumeName,
+		MountPath: constants.OpenSearchGCLogsMountPath,
+	})
+}
+
+// IsOpenSearchKeystoreMounted returns true if a pre-populated keystore is mounted on the OpenSearch nodes
+func IsOpenSearchKeystoreMounted(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return vmo.Spec.Opensearch.KeystoreSecret != ""
+}
+
+// GetOpenSearchObjectStoreEnvVars returns the environment variables of the object store credentials the keystore
+// of an OpenSearch node is updated with, none if a pre-populated keystore is mounted
+func GetOpenSearchObjectStoreEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []corev1.EnvVar {
+	if IsOpenSearchKeystoreMounted(vmo) {
+		return nil
+	}
+	return []corev1.EnvVar{
+		{Name: constants.ObjectStoreAccessKeyVarName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: constants.VerrazzanoBackupScrtName,
+					},
+					Key: constants.ObjectStoreAccessKey,
+					Optional: func(opt bool) *bool {
+						return &opt
+					}(true),
+				},
+			},
+		},
+		{Name: constants.ObjectStoreCustomerKeyVarName,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: constants.VerrazzanoBackupScrtName,
+					},
+					Key: constants.ObjectStoreCustomerKey,
+					Optional: func(opt bool) *bool {
+						return &opt
+					}(true),
+				},
+			},
+		},
+	}
+}
+
+// AddOpenSearchKeystore mounts the pre-populated keystore Secret of the VMI on an OpenSearch container, which copies it
+// to its config directory at startup
+func AddOpenSearchKeystore(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, podSpec *corev1.PodSpec, container *corev1.Container) {
+	if !IsOpenSearchKeystoreMounted(vmo) {
+		return
+	}
+	podSpec.Volumes = append(podSpec.Volumes, corev1.Volume{
+		Name: constants.OpenSearchKeystoreVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			Projected: &corev1.ProjectedVolumeSource{
+				Sources: []corev1.VolumeProjection{{
+					Secret: &corev1.SecretProjection{
+						LocalObjectReference: corev1.LocalObjectReference{Name: vmo.Spec.Opensearch.KeystoreSecret},
+						Items: []corev1.KeyToPath{{
+							Key:  constants.OpenSearchKeystoreKey,
+							Path: constants.OpenSearchKeystoreKey,
+						}},
+					},
+				}},
+			},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      constants.OpenSearchKeystoreVolumeName,
+		MountPath: constants.OpenSearchKeystoreMountPath,
+		ReadOnly:  true,
+	})
+}
+
 // CreateSidecarContainer creates the container for a deployment sidecar given the Sidecar information
 func CreateSidecarContainer(sidecar config.ComponentSidecar) corev1.Container {
 	return corev1.Container{
@@ -422,11 +804,13 @@ func CreateZoneAntiAffinityElement(vmoName string, component string) *corev1.Aff
 }
 
 // GetElasticsearchMasterInitContainer return an Elasticsearch Init container for the master.  This changes ownership of
-// the ES directory permissions needed to access PV volume data.  Also set the max map count.
-func GetElasticsearchMasterInitContainer() *corev1.Container {
+// the ES data directory permissions needed to access PV volume data to the user and group of the OpenSearch nodes.
+// Also set the max map count.
+func GetElasticsearchMasterInitContainer(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, dataDir string) *corev1.Container {
 	elasticsearchInitContainer := CreateContainerElement(nil, nil, config.ElasticsearchInit)
+	uid, gid := GetOpenSearchDataOwner(vmo)
 	elasticsearchInitContainer.Command =
-		[]string{"sh", "-c", "chown -R 1000:1000 /usr/share/opensearch/data; sysctl -w vm.max_map_count=262144"}
+		[]string{"sh", "-c", fmt.Sprintf("chown -R %d:%d %s; sysctl -w vm.max_map_count=262144", uid, gid, dataDir)}
 	elasticsearchInitContainer.Ports = nil
 	elasticsearchInitContainer.SecurityContext = getInitContainerSecurityContext()
 	return &elasticsearchInitContainer
@@ -555,13 +939,31 @@ func OidcProxyService(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, componen
 			OwnerReferences: GetOwnerReferences(vmo),
 		},
 		Spec: corev1.ServiceSpec{
-			Type:     vmo.Spec.ServiceType,
-			Selector: GetSpecID(vmo.Name, component.Name),
-			Ports:    []corev1.ServicePort{{Name: "oidc", Port: int32(constants.OidcProxyPort)}},
+			Type:            vmo.Spec.ServiceType,
+			Selector:        GetSpecID(vmo.Name, component.Name),
+			Ports:           []corev1.ServicePort{{Name: "oidc", Port: int32(constants.OidcProxyPort)}},
+			SessionAffinity: GetSessionAffinity(vmo, component.Name),
 		},
 	}
 }
 
+// GetSessionAffinity returns the session affinity of the service of the given component of the VMI, defaulting to None
+func GetSessionAffinity(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, componentName string) corev1.ServiceAffinity {
+	var sessionAffinity corev1.ServiceAffinity
+	switch componentName {
+	case config.Grafana.Name:
+		sessionAffinity = vmo.Spec.Grafana.SessionAffinity
+	case config.OpenSearchDashboards.Name:
+		sessionAffinity = vmo.Spec.OpensearchDashboards.SessionAffinity
+	case config.ElasticsearchMaster.Name, config.ElasticsearchData.Name, config.OpensearchIngest.Name:
+		sessionAffinity = vmo.Spec.Opensearch.SessionAffinity
+	}
+	if sessionAffinity == "" {
+		return corev1.ServiceAffinityNone
+	}
+	return sessionAffinity
+}
+
 // convertToRegexp converts index pattern to a regular expression pattern.
 func ConvertToRegexp(pattern string) string {
 	var result strings.Builder
@@ -585,11 +987,18 @@ func ConvertToRegexp(pattern string) string {
 
 // CreateOpenSearchContainerCMD creates the CMD for OpenSearch containers.
 // The resulting CMD contains
+// command to copy the mounted keystore if mountedKeystore is true, or else to update the keystore from the object store credentials
 // command to comment java heap settings in config/jvm/options if input javaOpts is non-empty
 // OS plugins installation commands if OpenSearch plugins are provided
+// the pre-start script if one is provided
 // and contains java min/max heap settings
-func CreateOpenSearchContainerCMD(javaOpts string, plugins []string, OSPluginsInstallTmpl string) string {
+func CreateOpenSearchContainerCMD(javaOpts string, plugins []string, OSPluginsInstallTmpl string, mountedKeystore bool, preStartScript string) string {
 	pluginsInstallTmpl := GetOSPluginsInstallTmpl(plugins, OSPluginsInstallCmd, OSPluginsInstallTmpl)
+	preStartCmd := OpenSearchPreStartCmd(preStartScript)
+	keystoreCmd := keystoreFromEnvCmd
+	if mountedKeystore {
+		keystoreCmd = keystoreFromSecretCmd
+	}
 	if javaOpts != "" {
 		jvmOptsPair := strings.Split(javaOpts, " ")
 		minHeapMemory := ""
@@ -605,11 +1014,42 @@ func CreateOpenSearchContainerCMD(javaOpts string, plugins []string, OSPluginsIn
 		}
 
 		if minHeapMemory != "" && maxHeapMemory != "" {
-			return fmt.Sprintf(containerCmdTmpl, jvmOptsDisableCmd, pluginsInstallTmpl)
+			return fmt.Sprintf(containerCmdTmpl, keystoreCmd, jvmOptsDisableCmd, pluginsInstallTmpl, preStartCmd)
 		}
 	}
 
-	return fmt.Sprintf(containerCmdTmpl, "", pluginsInstallTmpl)
+	return fmt.Sprintf(containerCmdTmpl, keystoreCmd, "", pluginsInstallTmpl, preStartCmd)
+}
+
+// CreateOpenSearchNodeNamePrefixCMD returns the CMD of an OpenSearch master container which starts OpenSearch with the
+// node name made of the node name prefix env var followed by the ordinal of the pod, taken from its hostname
+func CreateOpenSearchNodeNamePrefixCMD(cmd string) string {
+	return strings.Replace(cmd, "/usr/local/bin/docker-entrypoint.sh",
+		`env "node.name=${`+constants.OpenSearchNodeNamePrefixEnv+`}${HOSTNAME##*-}" /usr/local/bin/docker-entrypoint.sh`, 1)
+}
+
+// OpenSearchPreStartCmd returns the command running the pre-start script before OpenSearch is started, or an empty
+// command if there is no pre-start script. The script runs in a subshell whose failure is ignored, so that a failing
+// command of the script does not stop the container commands running with set -e.
+func OpenSearchPreStartCmd(preStartScript string) string {
+	if strings.TrimSpace(preStartScript) == "" {
+		return ""
+	}
+	return fmt.Sprintf(`# Running the pre-start script, its failures do not prevent OpenSearch from starting
+	echo "Running pre-start script..."
+	(
+	%s
+	) || true
+	`, preStartScript)
+}
+
+// ValidateOpenSearchPreStartScript returns an error if the pre-start script in the VMI calls the OpenSearch entrypoint
+// itself. The script runs in a subshell, so replacing or exiting its shell does not prevent OpenSearch from starting.
+func ValidateOpenSearchPreStartScript(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if match := preStartScriptForbiddenRegex.FindString(vmo.Spec.Opensearch.PreStartScript); match != "" {
+		return fmt.Errorf("invalid OpenSearch pre-start script, the script cannot call %s", match)
+	}
+	return nil
 }
 
 // GetOpenSearchPluginList retrieves the list of plugins provided in the VMI CRD for OpenSearch.
@@ -624,6 +1064,230 @@ func GetOpenSearchPluginList(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) [
 	return []string{}
 }
 
+// ValidateOpenSearchLoggers returns an error if a logger in the VMI is set to a level which is not known by OpenSearch
+func ValidateOpenSearchLoggers(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	for name, level := range vmo.Spec.Opensearch.Loggers {
+		if !openSearchLoggerLevels[strings.ToLower(level)] {
+			return fmt.Errorf("invalid level %s for OpenSearch logger %s, the level has to be one of off, fatal, error, warn, info, debug, trace or all", level, name)
+		}
+	}
+	return nil
+}
+
+// GetOpenSearchLoggerEnvVars returns the env vars setting the OpenSearch logger levels: the default org.opensearch logger,
+// merged with the loggers in the VMI. The default logger comes first, followed by the other loggers sorted by name.
+func GetOpenSearchLoggerEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []corev1.EnvVar {
+	levels := map[string]string{defaultOpenSearchLogger: defaultOpenSearchLoggerLevel}
+	for name, level := range vmo.Spec.Opensearch.Loggers {
+		levels[strings.TrimPrefix(name, openSearchLoggerPrefix)] = strings.ToLower(level)
+	}
+	var names []string
+	for name := range levels {
+		if name != defaultOpenSearchLogger {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	envVars := []corev1.EnvVar{{Name: openSearchLoggerPrefix + defaultOpenSearchLogger, Value: levels[defaultOpenSearchLogger]}}
+	for _, name := range names {
+		envVars = append(envVars, corev1.EnvVar{Name: openSearchLoggerPrefix + name, Value: levels[name]})
+	}
+	return envVars
+}
+
+// getOpenSearchCircuitBreakerLimits returns the limits of the circuit breakers set in the VMI, keyed by OpenSearch setting
+func getOpenSearchCircuitBreakerLimits(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) [][2]string {
+	circuitBreakers := vmo.Spec.Opensearch.CircuitBreakers
+	if circuitBreakers == nil {
+		return nil
+	}
+	var limits [][2]string
+	for _, limit := range [][2]string{
+		{"indices.breaker.total.limit", circuitBreakers.Total},
+		{"indices.breaker.request.limit", circuitBreakers.Request},
+		{"indices.breaker.fielddata.limit", circuitBreakers.Fielddata},
+	} {
+		if limit[1] != "" {
+			limits = append(limits, limit)
+		}
+	}
+	return limits
+}
+
+// ValidateOpenSearchCircuitBreakers returns an error if a circuit breaker limit in the VMI is neither a percentage nor a byte size
+func ValidateOpenSearchCircuitBreakers(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	for _, limit := range getOpenSearchCircuitBreakerLimits(vmo) {
+		if !circuitBreakerLimitRegex.MatchString(limit[1]) {
+			return fmt.Errorf("invalid limit %s for OpenSearch circuit breaker %s, the limit has to be a percentage, e.g. 70%%, or a byte size, e.g. 2gb", limit[1], limit[0])
+		}
+	}
+	return nil
+}
+
+// GetOpenSearchCircuitBreakerEnvVars returns the env vars setting the limits of the OpenSearch circuit breakers set in the VMI
+func GetOpenSearchCircuitBreakerEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	for _, limit := range getOpenSearchCircuitBreakerLimits(vmo) {
+		envVars = append(envVars, corev1.EnvVar{Name: limit[0], Value: strings.ToLower(limit[1])})
+	}
+	return envVars
+}
+
+// ValidateOpenSearchTransportCompression returns an error if the transport compression in the VMI is not known
+func ValidateOpenSearchTransportCompression(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	compression := vmo.Spec.Opensearch.TransportCompression
+	if _, ok := openSearchTransportCompressions[compression]; compression != "" && !ok {
+		return fmt.Errorf("invalid OpenSearch transport compression %s, the compression has to be one of none, indexing_data or true", compression)
+	}
+	return nil
+}
+
+// ValidateOpenSearchDashboardsRequestSettings returns an error if the OpenSearch Dashboards request timeout in the VMI
+// is not a positive duration, or its max payload is not positive
+func ValidateOpenSearchDashboardsRequestSettings(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if requestTimeout := vmo.Spec.OpensearchDashboards.RequestTimeout; requestTimeout != "" {
+		timeout, err := time.ParseDuration(requestTimeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("invalid OpenSearch Dashboards request timeout %s, the timeout has to be a positive duration, e.g. 90s", requestTimeout)
+		}
+	}
+	if maxPayloadBytes := vmo.Spec.OpensearchDashboards.MaxPayloadBytes; maxPayloadBytes != nil && *maxPayloadBytes <= 0 {
+		return fmt.Errorf("invalid OpenSearch Dashboards max payload of %d bytes, the max payload has to be positive", *maxPayloadBytes)
+	}
+	return nil
+}
+
+// GetOpenSearchDashboardsRequestEnvVars returns the env vars setting the request timeout, in milliseconds, and the max
+// payload of OpenSearch Dashboards, if set in the VMI
+func GetOpenSearchDashboardsRequestEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []corev1.EnvVar {
+	var envVars []corev1.EnvVar
+	if timeout, err := time.ParseDuration(vmo.Spec.OpensearchDashboards.RequestTimeout); err == nil && timeout > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.OSDRequestTimeoutEnv, Value: strconv.FormatInt(timeout.Milliseconds(), 10)})
+	}
+	if maxPayloadBytes := vmo.Spec.OpensearchDashboards.MaxPayloadBytes; maxPayloadBytes != nil {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.OSDMaxPayloadBytesEnv, Value: strconv.FormatInt(*maxPayloadBytes, 10)})
+	}
+	return envVars
+}
+
+// ValidateGrafanaSecurityContext returns an error if the VMI overrides the Grafana UID with root
+func ValidateGrafanaSecurityContext(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if runAsUser := vmo.Spec.Grafana.RunAsUser; runAsUser != nil && *runAsUser == 0 {
+		return fmt.Errorf("invalid Grafana UID 0, Grafana cannot run as root")
+	}
+	return nil
+}
+
+// IsGrafanaCookieSecure returns true if the Grafana cookies are only sent over HTTPS, which is the default when
+// Grafana is exposed over HTTPS
+func IsGrafanaCookieSecure(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	if vmo.Spec.Grafana.CookieSecure != nil {
+		return *vmo.Spec.Grafana.CookieSecure
+	}
+	rootURLScheme := vmo.Spec.Grafana.RootURLScheme
+	if rootURLScheme == "" {
+		rootURLScheme = constants.GrafanaDefaultRootURLScheme
+	}
+	return vmo.Spec.URI != "" && rootURLScheme == "https"
+}
+
+// ValidateGrafanaCookieSameSite returns an error if the SameSite attribute of the Grafana cookies in the VMI is not
+// known, or is none while the cookies are not secure, as browsers reject such cookies
+func ValidateGrafanaCookieSameSite(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	sameSite := vmo.Spec.Grafana.CookieSameSite
+	if _, ok := grafanaCookieSameSites[sameSite]; sameSite != "" && !ok {
+		return fmt.Errorf("invalid Grafana cookie SameSite %s, the SameSite has to be one of lax, strict, none or disabled", sameSite)
+	}
+	if sameSite == "none" && !IsGrafanaCookieSecure(vmo) {
+		return fmt.Errorf("invalid Grafana cookie SameSite none, the cookies have to be secure")
+	}
+	return nil
+}
+
+// ValidateGrafanaPlugins returns an error if a Grafana plugin in the VMI is not a plugin ID, optionally followed by a
+// space and a version
+func ValidateGrafanaPlugins(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	for _, plugin := range vmo.Spec.Grafana.Plugins {
+		if !grafanaPluginRegex.MatchString(plugin) {
+			return fmt.Errorf("invalid Grafana plugin %q, the plugin has to be a plugin ID, optionally followed by a space and a version, e.g. grafana-piechart-panel 1.6.4", plugin)
+		}
+	}
+	return nil
+}
+
+// GetGrafanaInstallPlugins returns the value of the GF_INSTALL_PLUGINS env var, from which the Grafana image installs
+// the given Grafana plugins when Grafana starts
+func GetGrafanaInstallPlugins(plugins []string) string {
+	return strings.Join(plugins, ",")
+}
+
+// GetGrafanaUserAndFSGroup returns the UID the Grafana container runs as and the GID owning the volumes of the
+// Grafana pod, which are the Grafana user and group unless overridden in the VMI
+func GetGrafanaUserAndFSGroup(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (int64, int64) {
+	runAsUser, fsGroup := int64(constants.GrafanaDefaultUID), int64(constants.GrafanaDefaultUID)
+	if vmo.Spec.Grafana.RunAsUser != nil {
+		runAsUser = *vmo.Spec.Grafana.RunAsUser
+	}
+	if vmo.Spec.Grafana.FSGroup != nil {
+		fsGroup = *vmo.Spec.Grafana.FSGroup
+	}
+	return runAsUser, fsGroup
+}
+
+// GetOpenSearchTransportCompressionEnvVars returns the env vars setting the transport compression of the VMI, the
+// compressed traffic uses the deflate compression scheme
+func GetOpenSearchTransportCompressionEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []corev1.EnvVar {
+	compress, ok := openSearchTransportCompressions[vmo.Spec.Opensearch.TransportCompression]
+	if !ok {
+		return nil
+	}
+	envVars := []corev1.EnvVar{{Name: "transport.compress", Value: compress}}
+	if compress != "false" {
+		envVars = append(envVars, corev1.EnvVar{Name: "transport.compression_scheme", Value: "deflate"})
+	}
+	return envVars
+}
+
+// GetOpenSearchMaxClauseCountEnvVars returns the env var setting the maximum number of clauses of a bool query of the
+// VMI, if set
+func GetOpenSearchMaxClauseCountEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []corev1.EnvVar {
+	if vmo.Spec.Opensearch.MaxClauseCount == nil {
+		return nil
+	}
+	return []corev1.EnvVar{{Name: "indices.query.bool.max_clause_count", Value: strconv.Itoa(int(*vmo.Spec.Opensearch.MaxClauseCount))}}
+}
+
+// GetOpenSearchAwarenessEnvVars returns the env vars setting the allocation awareness attributes of the VMI as
+// node.attr.<name> attributes of the node, the attributes already set in the given env vars are skipped
+func GetOpenSearchAwarenessEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, nodeName string, existing []corev1.EnvVar) []corev1.EnvVar {
+	awareness := vmo.Spec.Opensearch.AllocationAwareness
+	if awareness == nil {
+		return nil
+	}
+	var envVars []corev1.EnvVar
+	for _, attribute := range awareness.Attributes {
+		name := "node.attr." + attribute.Name
+		if containsEnvVar(existing, name) || containsEnvVar(envVars, name) {
+			continue
+		}
+		value, ok := attribute.NodeValues[nodeName]
+		if !ok || value == "" {
+			value = constants.OpenSearchAwarenessAttributeDefaultValue
+		}
+		envVars = append(envVars, corev1.EnvVar{Name: name, Value: value})
+	}
+	return envVars
+}
+
+func containsEnvVar(envVars []corev1.EnvVar, name string) bool {
+	for _, envVar := range envVars {
+		if envVar.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // GetOSDashboardPluginList retrieves the list of plugins provided in the VMI CRD for OpenSearch dashboard.
 // GIVEN VMI CRD
 // RETURN the list of provided OSD plugins. If there is no plugin in VMI CRD, an empty list is returned.
@@ -641,11 +1305,34 @@ func GetOSPluginsInstallTmpl(plugins []string, osPluginInstallCmd string, OSPlug
 	var pluginsInstallTmpl string
 
 	for _, plugin := range plugins {
-		pluginsInstallTmpl += fmt.Sprintf(OSPluginsInstallTmpl, fmt.Sprintf(osPluginInstallCmd, plugin))
+		pluginsInstallTmpl += fmt.Sprintf(OSPluginsInstallTmpl, getPluginInstallCmd(plugin, osPluginInstallCmd))
 	}
 	return pluginsInstallTmpl
 }
 
+// getPluginInstallCmd returns the installation cmd for a plugin install list entry.
+// An entry may carry a checksum after the plugin URL, e.g. https://example.com/plugin.zip#sha256=<checksum>, in which
+// case the plugin is downloaded and verified against the checksum before it is installed from the downloaded file.
+func getPluginInstallCmd(plugin string, osPluginInstallCmd string) string {
+	separatorIndex := strings.LastIndex(plugin, pluginChecksumSeparator)
+	if separatorIndex < 0 {
+		return fmt.Sprintf(osPluginInstallCmd, plugin)
+	}
+	pluginURL := plugin[:separatorIndex]
+	algorithm, checksum, found := strings.Cut(plugin[separatorIndex+1:], "=")
+	checksumTool, supported := pluginChecksumTools[strings.ToLower(algorithm)]
+	if !found || !supported {
+		// Not a checksum, keep the entry as is
+		return fmt.Sprintf(osPluginInstallCmd, plugin)
+	}
+	if len(checksum) != checksumTool.length || !hexRegex.MatchString(checksum) {
+		return fmt.Sprintf(OSPluginInvalidChecksumCmd, pluginURL)
+	}
+	downloadPath := fmt.Sprintf("/tmp/%s.zip", strings.ToLower(checksum))
+	return fmt.Sprintf(OSPluginChecksumVerifyCmd, downloadPath, pluginURL, strings.ToLower(checksum), checksumTool.tool) +
+		fmt.Sprintf(osPluginInstallCmd, "file://"+downloadPath)
+}
+
 // getInitContainerSecurityContext returns the security context for os init containers
 func getInitContainerSecurityContext() *corev1.SecurityContext {
 	return &corev1.SecurityContext{
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package resources
@@ -6,6 +6,7 @@ package resources
 import (
 	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -13,6 +14,7 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	vmov1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 )
 
 func createTestVMI() *vmov1.VerrazzanoMonitoringInstance {
@@ -29,11 +31,44 @@ func TestGetOpenSearchDashboardsHTTPEndpoint(t *testing.T) {
 	assert.Equal(t, "http://vmi-system-osd.test.svc.cluster.local:5601", osdEndpoint)
 }
 
+// TestGetOpenSearchDashboardsHTTPEndpointTLS Tests the endpoint of OpenSearch Dashboards serving HTTPS
+// GIVEN a VMI with an OpenSearch Dashboards TLS Secret
+// WHEN I get the OpenSearch Dashboards endpoint
+// THEN the endpoint uses the https scheme
+func TestGetOpenSearchDashboardsHTTPEndpointTLS(t *testing.T) {
+	vmi := createTestVMI()
+	vmi.Spec.OpensearchDashboards.TLS = &vmov1.OpenSearchDashboardsTLS{SecretName: "osd-tls"}
+	assert.Equal(t, "https://vmi-system-osd.test.svc.cluster.local:5601", GetOpenSearchDashboardsHTTPEndpoint(vmi))
+}
+
 func TestGetOpenSearchHTTPEndpoint(t *testing.T) {
 	osEndpoint := GetOpenSearchHTTPEndpoint(createTestVMI())
 	assert.Equal(t, "http://vmi-system-es-master-http.test.svc.cluster.local:9200", osEndpoint)
 }
 
+// TestClusterDomain Tests the endpoints in a cluster with a custom DNS domain
+// GIVEN a cluster domain set to "cluster.internal"
+// WHEN I get the OpenSearch and OpenSearch Dashboards endpoints
+// THEN the endpoints use the configured cluster domain
+func TestClusterDomain(t *testing.T) {
+	assert.NoError(t, SetClusterDomain("cluster.internal"))
+	defer func() { _ = SetClusterDomain(constants.DefaultClusterDomain) }()
+
+	assert.Equal(t, ".svc.cluster.internal", ServiceDomain())
+	assert.Equal(t, "http://vmi-system-es-master-http.test.svc.cluster.internal:9200", GetOpenSearchHTTPEndpoint(createTestVMI()))
+	assert.Equal(t, "http://vmi-system-osd.test.svc.cluster.internal:5601", GetOpenSearchDashboardsHTTPEndpoint(createTestVMI()))
+}
+
+// TestSetClusterDomainInvalid Tests setting an invalid cluster domain
+// GIVEN a cluster domain which is not a valid DNS name
+// WHEN I set the cluster domain
+// THEN an error is returned and the cluster domain is unchanged
+func TestSetClusterDomainInvalid(t *testing.T) {
+	assert.Error(t, SetClusterDomain("Cluster_Local"))
+	assert.Error(t, SetClusterDomain(""))
+	assert.Equal(t, ".svc.cluster.local", ServiceDomain())
+}
+
 func TestConvertToRegexp(t *testing.T) {
 	var tests = []struct {
 		pattern string
@@ -113,44 +148,120 @@ func TestDeepCopyMap(t *testing.T) {
 
 // GIVEN a string representing java options settings for an OpenSerach container
 // WHEN  CreateOpenSearchContainerCMD is invoked to get the command for the OpenSearch container
-// THEN the command contains a subcommand to disable the jvm heap settings, if input contains java heap settings
+// THEN the command contains a subcommand to disable the jvm heap settings, if input contains java heap settings,
+// and copies the mounted keystore instead of updating the keystore from the object store credentials if it is mounted
 func TestCreateOpenSearchContainerCMD(t *testing.T) {
-	containerCmdWithoutJavaOpts := fmt.Sprintf(containerCmdTmpl, "", "")
-	containerCmdWithJavaOpts := fmt.Sprintf(containerCmdTmpl, jvmOptsDisableCmd, "")
+	containerCmdWithoutJavaOpts := fmt.Sprintf(containerCmdTmpl, keystoreFromEnvCmd, "", "", "")
+	containerCmdWithJavaOpts := fmt.Sprintf(containerCmdTmpl, keystoreFromEnvCmd, jvmOptsDisableCmd, "", "")
+	containerCmdWithMountedKeystore := fmt.Sprintf(containerCmdTmpl, keystoreFromSecretCmd, jvmOptsDisableCmd, "", "")
 	var tests = []struct {
 		description          string
 		javaOpts             string
 		expectedResult       string
 		OSPluginsInstallTmpl string
+		mountedKeystore      bool
 	}{
 		{
 			"testCreateOpenSearchContainerCMD with empty jvmOpts",
 			"",
 			containerCmdWithoutJavaOpts,
 			OSMasterPluginsInstallTmpl,
+			false,
 		},
 		{
 			"testCreateOpenSearchContainerCMD with jvmOpts not containing jvm memory settings",
 			"-Xsomething",
 			containerCmdWithoutJavaOpts,
 			OpenSearchIngestCmdTmpl,
+			false,
 		},
 		{
 			"testCreateOpenSearchContainerCMD with jvmOpts containing jvm memory settings",
 			"-Xms1g -Xmx2g",
 			containerCmdWithJavaOpts,
 			OpenSearchIngestCmdTmpl,
+			false,
+		},
+		{
+			"testCreateOpenSearchContainerCMD with a mounted keystore",
+			"-Xms1g -Xmx2g",
+			containerCmdWithMountedKeystore,
+			OpenSearchIngestCmdTmpl,
+			true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
-			r := CreateOpenSearchContainerCMD(tt.javaOpts, []string{}, tt.OSPluginsInstallTmpl)
+			r := CreateOpenSearchContainerCMD(tt.javaOpts, []string{}, tt.OSPluginsInstallTmpl, tt.mountedKeystore, "")
 			assert.Equal(t, tt.expectedResult, r)
 		})
 	}
 }
 
+// TestCreateOpenSearchContainerCMDPreStartScript Tests that the pre-start script is run before OpenSearch is started
+// GIVEN a pre-start script
+// WHEN  CreateOpenSearchContainerCMD is invoked to get the command for the OpenSearch container
+// THEN the command runs the pre-start script before the OpenSearch entrypoint
+func TestCreateOpenSearchContainerCMDPreStartScript(t *testing.T) {
+	preStartScript := "ulimit -a; env | sort"
+	r := CreateOpenSearchContainerCMD("", []string{}, OSMasterPluginsInstallTmpl, false, preStartScript)
+	assert.Contains(t, r, preStartScript)
+	assert.Less(t, strings.Index(r, preStartScript), strings.Index(r, "/usr/local/bin/docker-entrypoint.sh"))
+
+	assert.Empty(t, OpenSearchPreStartCmd(""))
+	assert.Empty(t, OpenSearchPreStartCmd("  \n"))
+}
+
+// TestOpenSearchPreStartCmd Tests the rendered command of the pre-start script
+// GIVEN a pre-start script with a failing command
+// WHEN  the ingest node command is rendered with the pre-start script
+// THEN the pre-start script runs in a subshell whose failure is ignored, after set -euo pipefail and before the
+// OpenSearch entrypoint
+func TestOpenSearchPreStartCmd(t *testing.T) {
+	preStartScript := "ls /missing; env | sort"
+	assert.Equal(t, `# Running the pre-start script, its failures do not prevent OpenSearch from starting
+	echo "Running pre-start script..."
+	(
+	ls /missing; env | sort
+	) || true
+	`, OpenSearchPreStartCmd(preStartScript))
+
+	r := fmt.Sprintf(OpenSearchIngestCmdTmpl, "", OpenSearchPreStartCmd(preStartScript))
+	assert.Less(t, strings.Index(r, "set -euo pipefail"), strings.Index(r, "(\n\t"+preStartScript+"\n\t) || true"))
+	assert.Less(t, strings.Index(r, ") || true"), strings.Index(r, "/usr/local/bin/docker-entrypoint.sh"))
+}
+
+// TestValidateOpenSearchPreStartScript Tests the validation of the OpenSearch pre-start script
+// GIVEN pre-start scripts
+// WHEN  ValidateOpenSearchPreStartScript is called
+// THEN an error is only returned for the scripts calling the OpenSearch entrypoint
+func TestValidateOpenSearchPreStartScript(t *testing.T) {
+	var tests = []struct {
+		preStartScript string
+		isValid        bool
+	}{
+		{"", true},
+		{"ulimit -a; env | sort", true},
+		{"cat /usr/share/opensearch/config/opensearch.yml; executor=1", true},
+		{"exec ls /tmp", true},
+		{"ls /tmp || exit 1", true},
+		{"/usr/local/bin/docker-entrypoint.sh", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.preStartScript, func(t *testing.T) {
+			vmo := createTestVMI()
+			vmo.Spec.Opensearch.PreStartScript = tt.preStartScript
+			err := ValidateOpenSearchPreStartScript(vmo)
+			if tt.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
 // TestGetOpenSearchPluginList tests the GetOpenSearchPluginList
 // GIVEN VMI CRD
 // WHEN GetOpenSearchPluginList is called
@@ -281,3 +392,119 @@ func TestGetOSPluginsInstallTmpl(t *testing.T) {
 		})
 	}
 }
+
+// TestGetGrafanaInstallPlugins tests the Grafana plugins installed by the Grafana image
+// GIVEN Grafana plugins with and without a version
+// WHEN GetGrafanaInstallPlugins is called
+// THEN the plugins are separated by commas, with their version
+func TestGetGrafanaInstallPlugins(t *testing.T) {
+	assert.Empty(t, GetGrafanaInstallPlugins(nil))
+	assert.Equal(t, "grafana-clock-panel,grafana-piechart-panel 1.6.4", GetGrafanaInstallPlugins([]string{"grafana-clock-panel", "grafana-piechart-panel 1.6.4"}))
+}
+
+// TestValidateGrafanaPlugins tests the validation of the Grafana plugins
+// GIVEN Grafana plugins IDs, with or without a version, and invalid plugins
+// WHEN ValidateGrafanaPlugins is called
+// THEN an error is only returned for the invalid plugins
+func TestValidateGrafanaPlugins(t *testing.T) {
+	tests := []struct {
+		plugin string
+		valid  bool
+	}{
+		{"grafana-clock-panel", true},
+		{"grafana-piechart-panel 1.6.4", true},
+		{"", false},
+		{"grafana-clock-panel; rm -rf /", false},
+		{"grafana-clock-panel $(id)", false},
+		{"grafana-clock-panel 1.0 extra", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.plugin, func(t *testing.T) {
+			vmo := &vmov1.VerrazzanoMonitoringInstance{}
+			vmo.Spec.Grafana.Plugins = []string{tt.plugin}
+			err := ValidateGrafanaPlugins(vmo)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, "invalid Grafana plugin")
+			}
+		})
+	}
+}
+
+// TestGetOSDashboardPluginsInstallTmplRetry Tests the OpenSearch Dashboards plugin install command
+// GIVEN a plugin to install in OpenSearch Dashboards
+// WHEN GetOSPluginsInstallTmpl is called with the OpenSearch Dashboards templates
+// THEN the install command is wrapped in a bounded retry loop which stops retrying once the plugin is already installed
+func TestGetOSDashboardPluginsInstallTmplRetry(t *testing.T) {
+	plugin := "testPluginsURL"
+	cmd := GetOSPluginsInstallTmpl([]string{plugin}, OSDashboardPluginsInstallCmd, OSDashboardPluginsInstallTmpl)
+
+	assert.Contains(t, cmd, "for attempt in $(seq 1 "+osdPluginInstallAttempts+"); do")
+	assert.Contains(t, cmd, "sleep "+osdPluginInstallRetryDelaySeconds)
+	assert.Contains(t, cmd, fmt.Sprintf(OSDashboardPluginsInstallCmd, plugin))
+	assert.Contains(t, cmd, `if [ $exit_status -eq 0 ] || grep -iq "plugin .* already exists" /tmp/error.log; then`)
+	assert.Contains(t, cmd, "Plugin already installed. Skipping installation")
+	// The install command runs inside the retry loop, before the loop ends
+	assert.Less(t, strings.Index(cmd, "for attempt in"), strings.Index(cmd, "opensearch-dashboards-plugin install"))
+	assert.Less(t, strings.Index(cmd, "opensearch-dashboards-plugin install"), strings.Index(cmd, "done"))
+}
+
+// TestGetOSPluginsInstallTmplWithChecksum Tests the plugin install command generated for install list entries with a checksum
+// GIVEN plugin install list entries with and without a checksum
+// WHEN GetOSPluginsInstallTmpl is called
+// THEN plugins with a valid checksum are downloaded and verified before being installed from the downloaded file,
+// plugins with an invalid checksum are not installed and plugins without a checksum are installed as before
+func TestGetOSPluginsInstallTmplWithChecksum(t *testing.T) {
+	sha256Checksum := strings.Repeat("ab", 32)
+	sha512Checksum := strings.Repeat("CD", 64)
+	tests := []struct {
+		name   string
+		plugin string
+		want   string
+	}{
+		{
+			"plugin URL without checksum",
+			"https://example.com/plugin.zip",
+			fmt.Sprintf(OSPluginsInstallCmd, "https://example.com/plugin.zip"),
+		},
+		{
+			"plugin name",
+			"analysis-icu",
+			fmt.Sprintf(OSPluginsInstallCmd, "analysis-icu"),
+		},
+		{
+			"plugin URL with a fragment which is not a checksum",
+			"https://example.com/plugin.zip#latest",
+			fmt.Sprintf(OSPluginsInstallCmd, "https://example.com/plugin.zip#latest"),
+		},
+		{
+			"plugin URL with sha256 checksum",
+			"https://example.com/plugin.zip#sha256=" + sha256Checksum,
+			fmt.Sprintf(OSPluginChecksumVerifyCmd, "/tmp/"+sha256Checksum+".zip", "https://example.com/plugin.zip", sha256Checksum, "sha256sum") +
+				fmt.Sprintf(OSPluginsInstallCmd, "file:///tmp/"+sha256Checksum+".zip"),
+		},
+		{
+			"plugin URL with sha512 checksum",
+			"https://example.com/plugin.zip#SHA512=" + sha512Checksum,
+			fmt.Sprintf(OSPluginChecksumVerifyCmd, "/tmp/"+strings.ToLower(sha512Checksum)+".zip", "https://example.com/plugin.zip", strings.ToLower(sha512Checksum), "sha512sum") +
+				fmt.Sprintf(OSPluginsInstallCmd, "file:///tmp/"+strings.ToLower(sha512Checksum)+".zip"),
+		},
+		{
+			"plugin URL with checksum of the wrong length",
+			"https://example.com/plugin.zip#sha256=abcd",
+			fmt.Sprintf(OSPluginInvalidChecksumCmd, "https://example.com/plugin.zip"),
+		},
+		{
+			"plugin URL with non hex checksum",
+			"https://example.com/plugin.zip#sha256=" + strings.Repeat("zz", 32),
+			fmt.Sprintf(OSPluginInvalidChecksumCmd, "https://example.com/plugin.zip"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GetOSPluginsInstallTmpl([]string{tt.plugin}, OSPluginsInstallCmd, OSMasterPluginsInstallTmpl)
+			assert.Equal(t, fmt.Sprintf(OSMasterPluginsInstallTmpl, tt.want), got)
+		})
+	}
+}
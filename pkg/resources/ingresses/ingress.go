@@ -6,6 +6,7 @@ package ingresses
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
@@ -138,7 +139,9 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingIngresses map
 
 	if vmo.Spec.Grafana.Enabled {
 		if config.Grafana.OidcProxy != nil {
-			ingresses = append(ingresses, newOidcProxyIngress(vmo, &config.Grafana))
+			ingress := newOidcProxyIngress(vmo, &config.Grafana)
+			addIngressHosts(vmo, ingress, config.Grafana, vmo.Spec.Grafana.IngressHosts)
+			ingresses = append(ingresses, ingress)
 		} else {
 			// Create Ingress Rule for Grafana Endpoint
 			ingRule := createIngressRuleElement(vmo, config.Grafana)
@@ -147,12 +150,14 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingIngresses map
 			if err != nil {
 				return ingresses, err
 			}
+			addIngressHosts(vmo, ingress, config.Grafana, vmo.Spec.Grafana.IngressHosts)
 			ingresses = append(ingresses, ingress)
 		}
 	}
 	if vmo.Spec.OpensearchDashboards.Enabled {
 		if config.OpenSearchDashboards.OidcProxy != nil {
 			ingress := newOidcProxyIngress(vmo, &config.OpenSearchDashboards)
+			addIngressHosts(vmo, ingress, config.OpenSearchDashboards, vmo.Spec.OpensearchDashboards.IngressHosts)
 			ingresses = append(ingresses, ingress)
 			redirectIngress := createRedirectIngressIfNecessary(vmo, existingIngresses, &config.Kibana, &config.OpenSearchDashboardsRedirect)
 			if redirectIngress != nil {
@@ -169,6 +174,10 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingIngresses map
 			if err != nil {
 				return ingresses, err
 			}
+			if vmo.Spec.OpensearchDashboards.TLS != nil {
+				ingress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"] = "HTTPS"
+			}
+			addIngressHosts(vmo, ingress, config.OpenSearchDashboards, vmo.Spec.OpensearchDashboards.IngressHosts)
 			ingresses = append(ingresses, ingress)
 			redirectIngress := createRedirectIngressIfNecessary(vmo, existingIngresses, &config.Kibana, &config.OpenSearchDashboardsRedirect)
 			if redirectIngress != nil {
@@ -181,6 +190,7 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingIngresses map
 	if vmo.Spec.Opensearch.Enabled {
 		if config.OpensearchIngest.OidcProxy != nil {
 			ingress := newOidcProxyIngress(vmo, &config.OpensearchIngest)
+			addIngressHosts(vmo, ingress, config.OpensearchIngest, vmo.Spec.Opensearch.IngressHosts)
 			ingress.Annotations["nginx.ingress.kubernetes.io/proxy-body-size"] = "65M"
 			ingresses = append(ingresses, ingress)
 			redirectIngress := createRedirectIngressIfNecessary(vmo, existingIngresses, &config.ElasticsearchIngest, &config.OpensearchIngestRedirect)
@@ -198,6 +208,7 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingIngresses map
 			if err != nil {
 				return ingresses, err
 			}
+			addIngressHosts(vmo, ingress, config.OpensearchIngest, vmo.Spec.Opensearch.IngressHosts)
 			ingress.Annotations["nginx.ingress.kubernetes.io/proxy-read-timeout"] = constants.NginxProxyReadTimeoutForKibana
 			ingresses = append(ingresses, ingress)
 			redirectIngress := createRedirectIngressIfNecessary(vmo, existingIngresses, &config.ElasticsearchIngest, &config.OpensearchIngestRedirect)
@@ -212,20 +223,49 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingIngresses map
 	return ingresses, nil
 }
 
+// addIngressHosts adds a rule and a TLS entry to the ingress of a component for each additional host. The rule of an
+// additional host routes to the same backend as the rule of the default host, and each host has its own TLS secret.
+func addIngressHosts(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, ingress *netv1.Ingress, componentDetails config.ComponentDetails, ingressHosts []vmcontrollerv1.IngressHost) {
+	if len(ingress.Spec.Rules) == 0 {
+		return
+	}
+	for _, ingressHost := range ingressHosts {
+		rule := ingress.Spec.Rules[0].DeepCopy()
+		rule.Host = ingressHost.Host
+		ingress.Spec.Rules = append(ingress.Spec.Rules, *rule)
+		ingress.Spec.TLS = append(ingress.Spec.TLS, netv1.IngressTLS{
+			Hosts:      []string{ingressHost.Host},
+			SecretName: ingressHostSecretName(vmo, componentDetails, ingressHost),
+		})
+	}
+}
+
+// ingressHostSecretName returns the name of the TLS secret of an additional ingress host
+func ingressHostSecretName(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, componentDetails config.ComponentDetails, ingressHost vmcontrollerv1.IngressHost) string {
+	if ingressHost.SecretName != "" {
+		return ingressHost.SecretName
+	}
+	return fmt.Sprintf("%s-tls-%s-%s", vmo.Name, componentDetails.Name, strings.ReplaceAll(ingressHost.Host, ".", "-"))
+}
+
 // setNginxRoutingAnnotations adds the nginx annotations required for routing via istio envoy
 func setNginxRoutingAnnotations(ingress *netv1.Ingress) {
 	ingress.Annotations["nginx.ingress.kubernetes.io/service-upstream"] = "true"
-	ingress.Annotations["nginx.ingress.kubernetes.io/upstream-vhost"] = "${service_name}.${namespace}.svc.cluster.local"
+	ingress.Annotations["nginx.ingress.kubernetes.io/upstream-vhost"] = "${service_name}.${namespace}" + resources.ServiceDomain()
 }
 
 // noAuthOnHealthCheckSnippet returns an NGINX configuration snippet with Basic Authentication disabled for the the
 // specified component's health check path.
 func noAuthOnHealthCheckSnippet(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, disambiguationRoot string, componentDetails config.ComponentDetails) string {
+	scheme := "http"
+	if componentDetails.Name == config.OpenSearchDashboards.Name {
+		scheme = resources.GetOpenSearchDashboardsScheme(vmo)
+	}
 	// Added = check so nginx matches only this path i.e. strict check
 	return `location = ` + disambiguationRoot + componentDetails.LivenessHTTPPath + ` {
    auth_basic off;
    auth_request off;
-   proxy_pass  ` + fmt.Sprintf("http://%s.%s.svc.cluster.local:%d%s", constants.VMOServiceNamePrefix+vmo.Name+"-"+componentDetails.Name, vmo.Namespace, componentDetails.Port, componentDetails.LivenessHTTPPath) + `;
+   proxy_pass  ` + fmt.Sprintf("%s://%s.%s%s:%d%s", scheme, constants.VMOServiceNamePrefix+vmo.Name+"-"+componentDetails.Name, vmo.Namespace, resources.ServiceDomain(), componentDetails.Port, componentDetails.LivenessHTTPPath) + `;
 }
 `
 }
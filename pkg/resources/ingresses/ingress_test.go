@@ -240,3 +240,83 @@ func checkClusterIssuerAnnotation(t *testing.T, ingresses []*netv1.Ingress) {
 		assert.Equal(t, verrazzanoClusterIssuerName, ing.Annotations["cert-manager.io/cluster-issuer"], issuerCheckFailedFormatString, ing.Name)
 	}
 }
+
+// TestVMOWithIngressHosts Tests the ingress of the OpenSearch ingest component with additional hosts
+// GIVEN a VMI with a read and a write host for OpenSearch, one of them with its own TLS secret name
+// WHEN I call New
+// THEN the OpenSearch ingest ingress has a rule and a TLS entry per host, each routed to the ingest service
+func TestVMOWithIngressHosts(t *testing.T) {
+	const vmiName = "test-vmi"
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			SecretName: "secret",
+			URI:        "example.com",
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				IngressHosts: []vmcontrollerv1.IngressHost{
+					{Host: "opensearch-read.example.com"},
+					{Host: "opensearch-write.example.com", SecretName: "write-tls"},
+				},
+			},
+		},
+	}
+	vmo.Name = vmiName
+	ingresses, err := New(vmo, map[string]*netv1.Ingress{})
+	assert.NoError(t, err)
+	assert.Len(t, ingresses, 2)
+	ingress := ingresses[1]
+
+	var hosts []string
+	for _, rule := range ingress.Spec.Rules {
+		hosts = append(hosts, rule.Host)
+		assert.Equal(t, ingress.Spec.Rules[0].HTTP, rule.HTTP)
+	}
+	assert.Equal(t, []string{"opensearch.example.com", "opensearch-read.example.com", "opensearch-write.example.com"}, hosts)
+	assert.Equal(t, []netv1.IngressTLS{
+		{Hosts: []string{"opensearch.example.com"}, SecretName: vmiName + "-tls-os-ingest"},
+		{Hosts: []string{"opensearch-read.example.com"}, SecretName: vmiName + "-tls-os-ingest-opensearch-read-example-com"},
+		{Hosts: []string{"opensearch-write.example.com"}, SecretName: "write-tls"},
+	}, ingress.Spec.TLS)
+}
+
+// TestVMOWithOpenSearchDashboardsTLS Tests the ingress of OpenSearch Dashboards serving HTTPS
+// GIVEN a VMI with OpenSearch Dashboards with and without a TLS Secret, and OpenSearch Dashboards without OIDC proxy
+// WHEN I call New
+// THEN the OpenSearch Dashboards ingress proxies to the HTTPS backend and health check only if the TLS Secret is set
+func TestVMOWithOpenSearchDashboardsTLS(t *testing.T) {
+	var tests = []struct {
+		name            string
+		tls             *vmcontrollerv1.OpenSearchDashboardsTLS
+		backendProtocol string
+		scheme          string
+	}{
+		{"without TLS", nil, "", "http"},
+		{"with TLS", &vmcontrollerv1.OpenSearchDashboardsTLS{SecretName: "osd-tls"}, "HTTPS", "https"},
+	}
+	oidcProxy := config.OpenSearchDashboards.OidcProxy
+	config.OpenSearchDashboards.OidcProxy = nil
+	defer func() { config.OpenSearchDashboards.OidcProxy = oidcProxy }()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: "system", Namespace: constants.VerrazzanoSystemNamespace},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					SecretName: "secret",
+					URI:        "example.com",
+					OpensearchDashboards: vmcontrollerv1.OpensearchDashboards{
+						Enabled: true,
+						TLS:     tt.tls,
+					},
+				},
+			}
+			ingresses, err := New(vmo, map[string]*netv1.Ingress{})
+			assert.NoError(t, err)
+			assert.Len(t, ingresses, 2)
+			ingress := ingresses[1]
+			assert.Equal(t, "osd.example.com", ingress.Spec.Rules[0].Host)
+			assert.Equal(t, tt.backendProtocol, ingress.Annotations["nginx.ingress.kubernetes.io/backend-protocol"])
+			assert.Contains(t, ingress.Annotations["nginx.ingress.kubernetes.io/server-snippet"],
+				fmt.Sprintf("proxy_pass  %s://vmi-system-osd.verrazzano-system.svc.cluster.local:5601%s;", tt.scheme, config.OpenSearchDashboards.LivenessHTTPPath))
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package networkpolicies
+
+import (
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// namespaceNameLabel is set by Kubernetes on every namespace to the name of the namespace
+	namespaceNameLabel = "kubernetes.io/metadata.name"
+	// operatorAppLabelValue is the k8s-app label value of the VMO pods
+	operatorAppLabelValue = "verrazzano-monitoring-operator"
+	// authProxyAppLabelValue is the app label value of the auth proxy pods, which proxy the OpenSearch requests of users
+	authProxyAppLabelValue = "verrazzano-authproxy"
+	// fluentdAppLabelValue is the app label value of the Fluentd pods, which ship the logs to OpenSearch
+	fluentdAppLabelValue = "fluentd"
+)
+
+// New creates the NetworkPolicies for a VMO resource, the VMO running in the given namespace. A NetworkPolicy
+// restricting ingress to the OpenSearch HTTP and transport ports is only created if OpenSearch is enabled and a network
+// policy is configured.
+func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, operatorNamespace string) []*netv1.NetworkPolicy {
+	var networkPolicies []*netv1.NetworkPolicy
+	if vmo.Spec.Opensearch.Enabled && vmo.Spec.Opensearch.NetworkPolicy != nil {
+		networkPolicies = append(networkPolicies, createOpenSearchNetworkPolicy(vmo, operatorNamespace))
+	}
+	return networkPolicies
+}
+
+// createOpenSearchNetworkPolicy creates a NetworkPolicy selecting the OpenSearch pods of the VMI, which only allows ingress
+// to the OpenSearch ports from the VMI components, the VMO, the auth proxy, Fluentd and the configured namespaces and pods
+func createOpenSearchNetworkPolicy(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, operatorNamespace string) *netv1.NetworkPolicy {
+	resourceLabel := resources.GetMetaLabels(vmo)
+	resourceLabel[constants.ComponentLabel] = constants.ComponentOpenSearchValue
+	tcp := corev1.ProtocolTCP
+	httpPort := intstr.FromInt(constants.OSHTTPPort)
+	transportPort := intstr.FromInt(constants.OSTransportPort)
+
+	return &netv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels:          resourceLabel,
+			Name:            resources.GetMetaName(vmo.Name, constants.ComponentOpenSearchValue),
+			Namespace:       vmo.Namespace,
+			OwnerReferences: resources.GetOwnerReferences(vmo),
+		},
+		Spec: netv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					constants.ComponentLabel: constants.ComponentOpenSearchValue,
+				},
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      constants.ServiceAppLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   openSearchAppLabelValues(vmo.Name),
+					},
+				},
+			},
+			PolicyTypes: []netv1.PolicyType{netv1.PolicyTypeIngress},
+			Ingress: []netv1.NetworkPolicyIngressRule{
+				{
+					From: openSearchPeers(vmo, operatorNamespace),
+					Ports: []netv1.NetworkPolicyPort{
+						{Protocol: &tcp, Port: &httpPort},
+						{Protocol: &tcp, Port: &transportPort},
+					},
+				},
+			},
+		},
+	}
+}
+
+// openSearchPeers returns the peers allowed to access OpenSearch: the VMI components in the VMI namespace, the VMO,
+// the auth proxy and Fluentd in the operator namespace, and the namespaces and pods configured in the VMI
+func openSearchPeers(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, operatorNamespace string) []netv1.NetworkPolicyPeer {
+	peers := []netv1.NetworkPolicyPeer{
+		{
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      constants.ComponentLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values: []string{
+							constants.ComponentOpenSearchValue,
+							resources.GetCompLabel(config.Kibana.Name),
+							resources.GetCompLabel(config.OpenSearchDashboards.Name),
+							resources.GetCompLabel(config.Grafana.Name),
+							resources.GetCompLabel(config.API.Name),
+						},
+					},
+				},
+			},
+		},
+		{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{namespaceNameLabel: operatorNamespace},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{constants.K8SAppLabel: operatorAppLabelValue},
+			},
+		},
+		{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{namespaceNameLabel: operatorNamespace},
+			},
+			PodSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{
+						Key:      constants.ServiceAppLabel,
+						Operator: metav1.LabelSelectorOpIn,
+						Values:   []string{authProxyAppLabelValue, fluentdAppLabelValue},
+					},
+				},
+			},
+		},
+	}
+
+	networkPolicy := vmo.Spec.Opensearch.NetworkPolicy
+	for _, namespace := range networkPolicy.AllowedNamespaces {
+		peers = append(peers, netv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{namespaceNameLabel: namespace},
+			},
+		})
+	}
+	for i := range networkPolicy.AllowedPodSelectors {
+		peers = append(peers, netv1.NetworkPolicyPeer{
+			PodSelector: networkPolicy.AllowedPodSelectors[i].DeepCopy(),
+		})
+	}
+	return peers
+}
+
+// openSearchAppLabelValues returns the app label values of the OpenSearch pods of the VMI
+func openSearchAppLabelValues(vmoName string) []string {
+	return []string{
+		vmoName + "-" + config.ElasticsearchMaster.Name,
+		vmoName + "-" + config.ElasticsearchData.Name,
+		vmoName + "-" + config.ElasticsearchIngest.Name,
+		vmoName + "-" + config.OpensearchIngest.Name,
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package networkpolicies
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func createTestVMI(networkPolicy *vmcontrollerv1.OpenSearchNetworkPolicy) *vmcontrollerv1.VerrazzanoMonitoringInstance {
+	return &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "system",
+			Namespace: constants.VerrazzanoSystemNamespace,
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled:       true,
+				NetworkPolicy: networkPolicy,
+			},
+		},
+	}
+}
+
+// TestNoNetworkPolicy tests that no NetworkPolicy is created unless one is configured
+// GIVEN a VMI with OpenSearch enabled and no network policy, and a VMI with OpenSearch disabled and a network policy
+// WHEN I call New
+// THEN no NetworkPolicy is returned
+func TestNoNetworkPolicy(t *testing.T) {
+	assert.Empty(t, New(createTestVMI(nil), constants.VerrazzanoSystemNamespace))
+
+	vmo := createTestVMI(&vmcontrollerv1.OpenSearchNetworkPolicy{})
+	vmo.Spec.Opensearch.Enabled = false
+	assert.Empty(t, New(vmo, constants.VerrazzanoSystemNamespace))
+}
+
+// TestOpenSearchNetworkPolicy tests building the OpenSearch NetworkPolicy from the VMI spec
+// GIVEN a VMI with OpenSearch enabled and a network policy allowing a namespace and a pod selector, and a VMO running
+// in another namespace than the VMI
+// WHEN I call New
+// THEN a NetworkPolicy selecting the OpenSearch pods is returned, which only allows ingress to the OpenSearch ports
+// from the VMI components, the VMO, the auth proxy and Fluentd in the VMO namespace, and the configured namespace and pods
+func TestOpenSearchNetworkPolicy(t *testing.T) {
+	vmo := createTestVMI(&vmcontrollerv1.OpenSearchNetworkPolicy{
+		AllowedNamespaces: []string{"verrazzano-monitoring"},
+		AllowedPodSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{"app": "fluentd"}},
+		},
+	})
+	networkPolicies := New(vmo, "vmo-system")
+	assert.Len(t, networkPolicies, 1)
+	networkPolicy := networkPolicies[0]
+
+	assert.Equal(t, "vmi-system-opensearch", networkPolicy.Name)
+	assert.Equal(t, vmo.Namespace, networkPolicy.Namespace)
+	assert.Equal(t, vmo.Name, networkPolicy.Labels[constants.VMOLabel])
+	assert.Equal(t, constants.ComponentOpenSearchValue, networkPolicy.Spec.PodSelector.MatchLabels[constants.ComponentLabel])
+	assert.Len(t, networkPolicy.Spec.PodSelector.MatchExpressions, 1)
+	assert.Contains(t, networkPolicy.Spec.PodSelector.MatchExpressions[0].Values, "system-es-master")
+	assert.Contains(t, networkPolicy.Spec.PodSelector.MatchExpressions[0].Values, "system-es-data")
+	assert.Equal(t, []netv1.PolicyType{netv1.PolicyTypeIngress}, networkPolicy.Spec.PolicyTypes)
+
+	assert.Len(t, networkPolicy.Spec.Ingress, 1)
+	ingress := networkPolicy.Spec.Ingress[0]
+	assert.Len(t, ingress.Ports, 2)
+	for _, port := range ingress.Ports {
+		assert.Equal(t, corev1.ProtocolTCP, *port.Protocol)
+	}
+	assert.Equal(t, constants.OSHTTPPort, ingress.Ports[0].Port.IntValue())
+	assert.Equal(t, constants.OSTransportPort, ingress.Ports[1].Port.IntValue())
+
+	assert.Len(t, ingress.From, 5)
+	// VMI components
+	assert.Nil(t, ingress.From[0].NamespaceSelector)
+	assert.Equal(t, constants.ComponentLabel, ingress.From[0].PodSelector.MatchExpressions[0].Key)
+	assert.Contains(t, ingress.From[0].PodSelector.MatchExpressions[0].Values, constants.ComponentOpenSearchValue)
+	assert.Contains(t, ingress.From[0].PodSelector.MatchExpressions[0].Values, "osd")
+	assert.Contains(t, ingress.From[0].PodSelector.MatchExpressions[0].Values, "grafana")
+	assert.Contains(t, ingress.From[0].PodSelector.MatchExpressions[0].Values, "api")
+	// VMO
+	assert.Equal(t, &metav1.LabelSelector{
+		MatchLabels: map[string]string{namespaceNameLabel: "vmo-system"},
+	}, ingress.From[1].NamespaceSelector)
+	assert.Equal(t, operatorAppLabelValue, ingress.From[1].PodSelector.MatchLabels[constants.K8SAppLabel])
+	// auth proxy and Fluentd
+	assert.Equal(t, ingress.From[1].NamespaceSelector, ingress.From[2].NamespaceSelector)
+	assert.Equal(t, []metav1.LabelSelectorRequirement{{
+		Key:      constants.ServiceAppLabel,
+		Operator: metav1.LabelSelectorOpIn,
+		Values:   []string{authProxyAppLabelValue, fluentdAppLabelValue},
+	}}, ingress.From[2].PodSelector.MatchExpressions)
+	// configured namespace and pods
+	assert.Equal(t, "verrazzano-monitoring", ingress.From[3].NamespaceSelector.MatchLabels[namespaceNameLabel])
+	assert.Nil(t, ingress.From[3].PodSelector)
+	assert.Nil(t, ingress.From[4].NamespaceSelector)
+	assert.Equal(t, "fluentd", ingress.From[4].PodSelector.MatchLabels["app"])
+}
@@ -1,4 +1,4 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package nodes
@@ -9,6 +9,7 @@ import (
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"regexp"
 	"strings"
 )
 
@@ -29,6 +30,18 @@ var (
 	RoleData     = GetRoleLabel(vmcontrollerv1.DataRole)
 	RoleIngest   = GetRoleLabel(vmcontrollerv1.IngestRole)
 	RoleAssigned = "true"
+
+	knownRoles = map[vmcontrollerv1.NodeRole]bool{
+		vmcontrollerv1.MasterRole:              true,
+		vmcontrollerv1.DataRole:                true,
+		vmcontrollerv1.IngestRole:              true,
+		vmcontrollerv1.RemoteClusterClientRole: true,
+		vmcontrollerv1.TransformRole:           true,
+		vmcontrollerv1.SearchRole:              true,
+	}
+
+	// nodeNamePrefixRegex matches the valid node name prefixes of the master nodes
+	nodeNamePrefixRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
 )
 
 // MasterNodes returns the list of master role containing nodes in the VMI spec. These nodes will be created as statefulsets.
@@ -89,7 +102,7 @@ func GetNodeCount(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) *NodeCount {
 				nodeCount.IngestNodes += node.Replicas
 			case vmcontrollerv1.DataRole:
 				nodeCount.DataNodes += node.Replicas
-			default:
+			case vmcontrollerv1.MasterRole:
 				nodeCount.MasterNodes += node.Replicas
 			}
 		}
@@ -97,18 +110,93 @@ func GetNodeCount(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) *NodeCount {
 	return nodeCount
 }
 
+// ValidateNodeRoles returns an error if a node of the VMI has an unknown or duplicate role, or does not have
+// a master, data or ingest role, which decides whether the node is created as a statefulset or a deployment.
+// The other roles, e.g. transform or remote_cluster_client, are only given to nodes in addition to these roles.
+// The master and data nodes of the VMI must also have the master and data role respectively.
+func ValidateNodeRoles(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if err := validateRequiredRole(vmo.Spec.Opensearch.MasterNode, vmcontrollerv1.MasterRole); err != nil {
+		return err
+	}
+	if err := validateRequiredRole(vmo.Spec.Opensearch.DataNode, vmcontrollerv1.DataRole); err != nil {
+		return err
+	}
+	for _, node := range AllNodes(vmo) {
+		if len(node.Roles) == 0 {
+			continue
+		}
+		roles := map[vmcontrollerv1.NodeRole]bool{}
+		for _, role := range node.Roles {
+			if !knownRoles[role] {
+				return fmt.Errorf("node %s has unknown role %s", node.Name, role)
+			}
+			if roles[role] {
+				return fmt.Errorf("node %s has duplicate role %s", node.Name, role)
+			}
+			roles[role] = true
+		}
+		if !roles[vmcontrollerv1.MasterRole] && !roles[vmcontrollerv1.DataRole] && !roles[vmcontrollerv1.IngestRole] {
+			return fmt.Errorf("node %s must have at least one of the roles %s, %s or %s", node.Name,
+				vmcontrollerv1.MasterRole, vmcontrollerv1.DataRole, vmcontrollerv1.IngestRole)
+		}
+	}
+	return nil
+}
+
+// validateRequiredRole returns an error if the node has roles, but not the required role
+func validateRequiredRole(node vmcontrollerv1.ElasticsearchNode, required vmcontrollerv1.NodeRole) error {
+	if len(node.Roles) == 0 {
+		return nil
+	}
+	for _, role := range node.Roles {
+		if role == required {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s node %s must have the role %s, its roles are %v", required, node.Name, required, node.Roles)
+}
+
 // InitialMasterNodes returns a comma separated list of master nodes for cluster bootstrapping
 func InitialMasterNodes(vmoName string, masterNodes []vmcontrollerv1.ElasticsearchNode) string {
 	var j int32
 	var initialMasterNodes []string
 	for _, node := range masterNodes {
 		for j = 0; j < node.Replicas; j++ {
-			initialMasterNodes = append(initialMasterNodes, resources.GetMetaName(vmoName, node.Name)+"-"+fmt.Sprintf("%d", j))
+			initialMasterNodes = append(initialMasterNodes, MasterNodeName(vmoName, node, j))
 		}
 	}
 	return strings.Join(initialMasterNodes, ",")
 }
 
+// MasterNodeName returns the OpenSearch node name of the master pod with the given ordinal, which is the node name
+// prefix followed by the ordinal if the node has a node name prefix, or the name of the pod otherwise
+func MasterNodeName(vmoName string, node vmcontrollerv1.ElasticsearchNode, ordinal int32) string {
+	if node.NodeNamePrefix != "" {
+		return fmt.Sprintf("%s%d", node.NodeNamePrefix, ordinal)
+	}
+	return fmt.Sprintf("%s-%d", resources.GetMetaName(vmoName, node.Name), ordinal)
+}
+
+// ValidateNodeNamePrefixes returns an error if a node name prefix of the master nodes of the VMI is not a valid node
+// name, or is the prefix of another master node, as the node names must be unique
+func ValidateNodeNamePrefixes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	prefixes := map[string]string{}
+	for _, node := range MasterNodes(vmo) {
+		prefix := node.NodeNamePrefix
+		if prefix == "" {
+			continue
+		}
+		if !nodeNamePrefixRegex.MatchString(prefix) {
+			return fmt.Errorf("invalid node name prefix %s of master node %s, the prefix must start with a letter or digit, followed by letters, digits, '.', '_' or '-'", prefix, node.Name)
+		}
+		if other, ok := prefixes[prefix]; ok {
+			return fmt.Errorf("master nodes %s and %s have the same node name prefix %s", other, node.Name, prefix)
+		}
+		prefixes[prefix] = node.Name
+	}
+	return nil
+}
+
 // AllNodes returns a list of all nodes that need to be created
 func AllNodes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []vmcontrollerv1.ElasticsearchNode {
 	return append(vmo.Spec.Opensearch.Nodes, vmo.Spec.Opensearch.MasterNode, vmo.Spec.Opensearch.DataNode, vmo.Spec.Opensearch.IngestNode)
@@ -1,4 +1,4 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package nodes
@@ -119,6 +119,16 @@ func TestGetRolesString(t *testing.T) {
 			},
 			"data,ingest",
 		},
+		{
+			vmcontrollerv1.ElasticsearchNode{
+				Roles: []vmcontrollerv1.NodeRole{
+					vmcontrollerv1.DataRole,
+					vmcontrollerv1.TransformRole,
+					vmcontrollerv1.RemoteClusterClientRole,
+				},
+			},
+			"data,transform,remote_cluster_client",
+		},
 	}
 
 	for _, tt := range tests {
@@ -204,3 +214,143 @@ func TestGetNodeRoleCount(t *testing.T) {
 	assert.EqualValues(t, 6, nodeRoles.IngestNodes)
 	assert.EqualValues(t, 8, nodeRoles.Replicas)
 }
+
+// TestGetNodeRoleCountAdditionalRoles tests counting the nodes of a VMI with additional roles
+// GIVEN a VMI with a data node which also has the transform and remote_cluster_client roles
+// WHEN I call GetNodeCount
+// THEN the additional roles are not counted as master nodes
+func TestGetNodeRoleCountAdditionalRoles(t *testing.T) {
+	vmi := testMultiNodeVMI.DeepCopy()
+	vmi.Spec.Opensearch.DataNode.Roles = append(vmi.Spec.Opensearch.DataNode.Roles, vmcontrollerv1.TransformRole, vmcontrollerv1.RemoteClusterClientRole)
+	nodeRoles := GetNodeCount(vmi)
+	assert.EqualValues(t, 5, nodeRoles.DataNodes)
+	assert.EqualValues(t, 3, nodeRoles.MasterNodes)
+	assert.EqualValues(t, 6, nodeRoles.IngestNodes)
+	assert.EqualValues(t, 8, nodeRoles.Replicas)
+}
+
+// TestValidateNodeRoles tests validating the roles of the nodes of a VMI
+// GIVEN VMIs with valid and invalid combinations of node roles
+// WHEN I call ValidateNodeRoles
+// THEN an error is returned for unknown or duplicate roles, and for nodes without a master, data or ingest role
+func TestValidateNodeRoles(t *testing.T) {
+	var tests = []struct {
+		name    string
+		roles   []vmcontrollerv1.NodeRole
+		isValid bool
+	}{
+		{"no roles", nil, true},
+		{"data and transform", []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole, vmcontrollerv1.TransformRole}, true},
+		{"master and remote cluster client", []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole, vmcontrollerv1.RemoteClusterClientRole}, true},
+		{"ingest and search", []vmcontrollerv1.NodeRole{vmcontrollerv1.IngestRole, vmcontrollerv1.SearchRole}, true},
+		{"only transform", []vmcontrollerv1.NodeRole{vmcontrollerv1.TransformRole}, false},
+		{"unknown", []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole, "ml"}, false},
+		{"duplicate", []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole, vmcontrollerv1.DataRole}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := testMultiNodeVMI.DeepCopy()
+			vmi.Spec.Opensearch.Nodes = append(vmi.Spec.Opensearch.Nodes, vmcontrollerv1.ElasticsearchNode{
+				Name:     "node",
+				Replicas: 1,
+				Roles:    tt.roles,
+			})
+			err := ValidateNodeRoles(vmi)
+			if tt.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestValidateNodeRolesRequiredRoles tests validating the roles of the master and data nodes of a VMI
+// GIVEN VMIs whose master and data nodes have valid and invalid combinations of roles
+// WHEN I call ValidateNodeRoles
+// THEN an error is returned if the master node does not have the master role, or the data node does not have the data role
+func TestValidateNodeRolesRequiredRoles(t *testing.T) {
+	var tests = []struct {
+		name        string
+		masterRoles []vmcontrollerv1.NodeRole
+		dataRoles   []vmcontrollerv1.NodeRole
+		isValid     bool
+	}{
+		{"default roles", nil, nil, true},
+		{"master and data", []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole}, []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole}, true},
+		{"data and ingest", nil, []vmcontrollerv1.NodeRole{vmcontrollerv1.IngestRole, vmcontrollerv1.DataRole}, true},
+		{"master and data on master node", []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole, vmcontrollerv1.DataRole}, nil, true},
+		{"only ingest on data node", nil, []vmcontrollerv1.NodeRole{vmcontrollerv1.IngestRole}, false},
+		{"only master on data node", nil, []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole}, false},
+		{"only data on master node", []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole}, nil, false},
+		{"only ingest on master node", []vmcontrollerv1.NodeRole{vmcontrollerv1.IngestRole}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := testMultiNodeVMI.DeepCopy()
+			vmi.Spec.Opensearch.MasterNode.Roles = tt.masterRoles
+			vmi.Spec.Opensearch.DataNode.Roles = tt.dataRoles
+			err := ValidateNodeRoles(vmi)
+			if tt.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+// TestInitialMasterNodesWithNodeNamePrefix tests the initial master nodes of master nodes with a node name prefix
+// GIVEN master nodes, one of them with a node name prefix
+// WHEN I call InitialMasterNodes
+// THEN the node names of the master node with a node name prefix are the prefix followed by the pod ordinal, and the
+// node names of the other master nodes are the pod names
+func TestInitialMasterNodesWithNodeNamePrefix(t *testing.T) {
+	masterNodes := []vmcontrollerv1.ElasticsearchNode{
+		{Name: "es-master", Replicas: 2, NodeNamePrefix: "logs-master-"},
+		{Name: "a", Replicas: 1},
+	}
+	assert.Equal(t, "logs-master-0,logs-master-1,vmi-system-a-0", InitialMasterNodes("system", masterNodes))
+	assert.Equal(t, "logs-master-1", MasterNodeName("system", masterNodes[0], 1))
+	assert.Equal(t, "vmi-system-a-0", MasterNodeName("system", masterNodes[1], 0))
+}
+
+// TestValidateNodeNamePrefixes tests validating the node name prefixes of the master nodes of a VMI
+// GIVEN VMIs whose master nodes have valid, invalid and duplicate node name prefixes
+// WHEN I call ValidateNodeNamePrefixes
+// THEN an error is returned if a prefix is not a valid node name, or is the prefix of another master node
+func TestValidateNodeNamePrefixes(t *testing.T) {
+	var tests = []struct {
+		name         string
+		masterPrefix string
+		otherPrefix  string
+		isValid      bool
+	}{
+		{"no prefixes", "", "", true},
+		{"distinct prefixes", "master-", "other.master_", true},
+		{"invalid prefix", "-master", "", false},
+		{"prefix with a space", "", "master ", false},
+		{"duplicate prefixes", "master-", "master-", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := testMultiNodeVMI.DeepCopy()
+			vmi.Spec.Opensearch.MasterNode.NodeNamePrefix = tt.masterPrefix
+			vmi.Spec.Opensearch.Nodes = append(vmi.Spec.Opensearch.Nodes, vmcontrollerv1.ElasticsearchNode{
+				Name:           "other-master",
+				Replicas:       1,
+				Roles:          []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole},
+				NodeNamePrefix: tt.otherPrefix,
+			})
+			err := ValidateNodeNamePrefixes(vmi)
+			if tt.isValid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package pvcs
@@ -21,7 +21,7 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, storageClassName stri
 	if vmo.Spec.Opensearch.Enabled {
 		for _, dataNode := range nodes.DataNodes(vmo) {
 			if dataNode.Storage != nil && dataNode.Storage.Size != "" {
-				pvcs, err := createPvcElements(vmo, dataNode.Storage, config.ElasticsearchData, storageClassName)
+				pvcs, err := createPvcElements(vmo, dataNode.Storage, config.ElasticsearchData, GetNodeStorageClassName(dataNode, storageClassName))
 				if err != nil {
 					return pvcList, err
 				}
@@ -39,6 +39,14 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, storageClassName stri
 	return pvcList, nil
 }
 
+// GetNodeStorageClassName returns the name of the storage class of the node if set, otherwise the given default
+func GetNodeStorageClassName(node vmcontrollerv1.ElasticsearchNode, defaultStorageClassName string) string {
+	if node.StorageClass != nil && *node.StorageClass != "" {
+		return *node.StorageClass
+	}
+	return defaultStorageClassName
+}
+
 // Returns slice of pvc elements
 func createPvcElements(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, vmoStorage *vmcontrollerv1.Storage, componentDetails config.ComponentDetails, storageClassName string) ([]*corev1.PersistentVolumeClaim, error) {
 	storageQuantity, err := resource.ParseQuantity(vmoStorage.Size)
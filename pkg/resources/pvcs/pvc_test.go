@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package pvcs
@@ -198,3 +198,56 @@ func TestVMOWithCascadingDelete(t *testing.T) {
 		assert.Equal(t, 0, len(pvc.ObjectMeta.OwnerReferences), "OwnerReferences is set even with CascadingDelete false")
 	}
 }
+
+// TestVMOWithNodeStorageClasses tests the storage classes of the PVCs of data nodes overriding the storage class
+// GIVEN a VMI with a data node overriding the storage class, a data node without storage class and Grafana storage
+// WHEN I call New
+// THEN the PVCs of the overriding data node use its storage class, the other PVCs use the storage class of the VMI
+func TestVMOWithNodeStorageClasses(t *testing.T) {
+	fastStorageClass := "nvme"
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Grafana: vmcontrollerv1.Grafana{
+				Enabled: true,
+				Storage: vmcontrollerv1.Storage{
+					Size:     "50Gi",
+					PvcNames: []string{"grafana-pvc"},
+				},
+			},
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				Nodes: []vmcontrollerv1.ElasticsearchNode{
+					{
+						Name:         "hot",
+						Roles:        []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+						StorageClass: &fastStorageClass,
+						Storage: &vmcontrollerv1.Storage{
+							Size:     "100Gi",
+							PvcNames: []string{"hot-0", "hot-1"},
+						},
+					},
+					{
+						Name:  "warm",
+						Roles: []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+						Storage: &vmcontrollerv1.Storage{
+							Size:     "500Gi",
+							PvcNames: []string{"warm-0"},
+						},
+					},
+				},
+			},
+		},
+	}
+	pvcs, err := New(vmo, "standard")
+	assert.NoError(t, err)
+	assert.Equal(t, 4, len(pvcs), "Length of generated PVCs")
+	expectedStorageClasses := map[string]string{
+		"hot-0":       fastStorageClass,
+		"hot-1":       fastStorageClass,
+		"warm-0":      "standard",
+		"grafana-pvc": "standard",
+	}
+	for _, pvc := range pvcs {
+		assert.Equal(t, expectedStorageClasses[pvc.Name], *pvc.Spec.StorageClassName, pvc.Name)
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package services
@@ -32,6 +32,8 @@ func createOpenSearchMasterServiceElements(vmo *vmcontrollerv1.VerrazzanoMonitor
 		// MasterNodes service is headless
 		openSearchMasterService.Spec.Type = corev1.ServiceTypeClusterIP
 		openSearchMasterService.Spec.ClusterIP = corev1.ClusterIPNone
+		// Session affinity does not apply to headless services
+		openSearchMasterService.Spec.SessionAffinity = corev1.ServiceAffinityNone
 	}
 	return openSearchMasterService
 }
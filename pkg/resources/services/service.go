@@ -1,9 +1,11 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package services
 
 import (
+	"fmt"
+
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
@@ -17,6 +19,9 @@ import (
 // the VMO resource that 'owns' it.
 func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, useNodeRoleSelectors bool) ([]*corev1.Service, error) {
 	var services []*corev1.Service
+	if err := validateSessionAffinity(vmo); err != nil {
+		return nil, err
+	}
 
 	if vmo.Spec.Grafana.Enabled {
 		service := createServiceElement(vmo, config.Grafana)
@@ -35,6 +40,24 @@ func New(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, useNodeRoleSelectors
 
 	return services, nil
 }
+
+// validateSessionAffinity returns an error if the session affinity of a service of the VMI is neither None nor ClientIP
+func validateSessionAffinity(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	for component, sessionAffinity := range map[string]corev1.ServiceAffinity{
+		config.Grafana.Name:              vmo.Spec.Grafana.SessionAffinity,
+		config.OpenSearchDashboards.Name: vmo.Spec.OpensearchDashboards.SessionAffinity,
+		config.OpensearchIngest.Name:     vmo.Spec.Opensearch.SessionAffinity,
+	} {
+		switch sessionAffinity {
+		case "", corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP:
+		default:
+			return fmt.Errorf("invalid session affinity %s for the %s service, must be %s or %s",
+				sessionAffinity, component, corev1.ServiceAffinityNone, corev1.ServiceAffinityClientIP)
+		}
+	}
+	return nil
+}
+
 func createServiceElement(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, componentDetails config.ComponentDetails) *corev1.Service {
 	resourceLabel := resources.GetMetaLabels(vmo)
 	resourceLabel[constants.ComponentLabel] = resources.GetCompLabel(componentDetails.Name)
@@ -46,9 +69,10 @@ func createServiceElement(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, comp
 			OwnerReferences: resources.GetOwnerReferences(vmo),
 		},
 		Spec: corev1.ServiceSpec{
-			Type:     vmo.Spec.ServiceType,
-			Selector: resources.GetSpecID(vmo.Name, componentDetails.Name),
-			Ports:    []corev1.ServicePort{resources.GetServicePort(componentDetails)},
+			Type:            vmo.Spec.ServiceType,
+			Selector:        resources.GetSpecID(vmo.Name, componentDetails.Name),
+			Ports:           []corev1.ServicePort{resources.GetServicePort(componentDetails)},
+			SessionAffinity: resources.GetSessionAffinity(vmo, componentDetails.Name),
 		},
 	}
 }
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package services
@@ -7,6 +7,10 @@ import (
 	"testing"
 
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -47,3 +51,91 @@ func TestVMOWithCascadingDelete(t *testing.T) {
 		assert.Equal(t, 0, len(service.ObjectMeta.OwnerReferences), "OwnerReferences is set even with CascadingDelete false")
 	}
 }
+
+// TestServiceSessionAffinity Tests the session affinity of the services of a VMI
+// GIVEN a VMI with the ClientIP session affinity configured for one component
+// WHEN I call New
+// THEN the services of that component have the ClientIP session affinity, except the headless OpenSearch master
+// service, and the other services have none
+func TestServiceSessionAffinity(t *testing.T) {
+	const vmiName = "system"
+	var tests = []struct {
+		name             string
+		grafana          corev1.ServiceAffinity
+		osd              corev1.ServiceAffinity
+		opensearch       corev1.ServiceAffinity
+		expectedClientIP []string
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:             "OpenSearch Dashboards",
+			osd:              corev1.ServiceAffinityClientIP,
+			expectedClientIP: []string{resources.GetMetaName(vmiName, config.OpenSearchDashboards.Name)},
+		},
+		{
+			name:             "Grafana",
+			grafana:          corev1.ServiceAffinityClientIP,
+			expectedClientIP: []string{resources.GetMetaName(vmiName, config.Grafana.Name)},
+		},
+		{
+			name:       "OpenSearch",
+			opensearch: corev1.ServiceAffinityClientIP,
+			expectedClientIP: []string{
+				resources.GetMetaName(vmiName, config.ElasticsearchMaster.Name) + "-http",
+				resources.GetMetaName(vmiName, config.ElasticsearchData.Name),
+				resources.GetMetaName(vmiName, config.OpensearchIngest.Name),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: metav1.ObjectMeta{Name: vmiName},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					Grafana: vmcontrollerv1.Grafana{
+						Enabled:         true,
+						SessionAffinity: tt.grafana,
+					},
+					OpensearchDashboards: vmcontrollerv1.OpensearchDashboards{
+						Enabled:         true,
+						SessionAffinity: tt.osd,
+					},
+					Opensearch: vmcontrollerv1.Opensearch{
+						Enabled:         true,
+						SessionAffinity: tt.opensearch,
+					},
+				},
+			}
+			services, err := New(vmo, false)
+			assert.NoError(t, err)
+			var clientIPServices []string
+			for _, service := range services {
+				if service.Spec.SessionAffinity == corev1.ServiceAffinityClientIP {
+					clientIPServices = append(clientIPServices, service.Name)
+				} else {
+					assert.Equal(t, corev1.ServiceAffinityNone, service.Spec.SessionAffinity, service.Name)
+				}
+			}
+			assert.ElementsMatch(t, tt.expectedClientIP, clientIPServices)
+		})
+	}
+}
+
+// TestServiceSessionAffinityInvalid Tests that an invalid session affinity is rejected
+// GIVEN a VMI with an invalid session affinity configured for Grafana
+// WHEN I call New
+// THEN an error is returned
+func TestServiceSessionAffinityInvalid(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Grafana: vmcontrollerv1.Grafana{
+				Enabled:         true,
+				SessionAffinity: "Sticky",
+			},
+		},
+	}
+	_, err := New(vmo, false)
+	assert.Error(t, err)
+}
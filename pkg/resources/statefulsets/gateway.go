@@ -0,0 +1,81 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package statefulsets
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	gatewayRecoverAfterNodesEnv = "gateway.recover_after_nodes"
+	gatewayExpectedNodesEnv     = "gateway.expected_nodes"
+	gatewayRecoverAfterTimeEnv  = "gateway.recover_after_time"
+)
+
+// gatewayRecoverAfterTimeRegex matches the OpenSearch time values of the recover after time, e.g. 5m
+var gatewayRecoverAfterTimeRegex = regexp.MustCompile(`^[0-9]+(ms|s|m|h|d)$`)
+
+// validateGatewaySettings returns an error if the recover after time of the gateway settings of the VMI is not an
+// OpenSearch time value, if more nodes must join before the recovery starts than are expected in the cluster, or if
+// more nodes are expected than there are nodes in the cluster
+func validateGatewaySettings(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	gateway := vmo.Spec.Opensearch.GatewaySettings
+	if gateway == nil {
+		return nil
+	}
+	if gateway.RecoverAfterTime != "" && !gatewayRecoverAfterTimeRegex.MatchString(gateway.RecoverAfterTime) {
+		return fmt.Errorf("invalid OpenSearch gateway recover after time %s, the time has to be a number followed by one of ms, s, m, h or d, e.g. 5m", gateway.RecoverAfterTime)
+	}
+	recoverAfterNodes, expectedNodes := gatewayNodes(vmo)
+	if totalNodes := nodes.GetNodeCount(vmo).Replicas; expectedNodes > totalNodes {
+		return fmt.Errorf("invalid OpenSearch gateway settings, the expected nodes %d exceed the %d nodes of the cluster", expectedNodes, totalNodes)
+	}
+	if recoverAfterNodes > expectedNodes {
+		return fmt.Errorf("invalid OpenSearch gateway settings, the recover after nodes %d exceed the expected nodes %d", recoverAfterNodes, expectedNodes)
+	}
+	return nil
+}
+
+// gatewayNodes returns the number of nodes which must join before the recovery starts and the number of expected
+// nodes of the gateway settings of the VMI. Unless set, they default to a majority and to all the master nodes, which
+// only depend on the master replicas, so scaling the other nodes does not restart the master nodes.
+func gatewayNodes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (int32, int32) {
+	var masterReplicas int32
+	for _, node := range nodes.MasterNodes(vmo) {
+		masterReplicas += node.Replicas
+	}
+	recoverAfterNodes := masterReplicas/2 + 1
+	expectedNodes := masterReplicas
+	gateway := vmo.Spec.Opensearch.GatewaySettings
+	if gateway.RecoverAfterNodes != nil {
+		recoverAfterNodes = *gateway.RecoverAfterNodes
+	}
+	if gateway.ExpectedNodes != nil {
+		expectedNodes = *gateway.ExpectedNodes
+	}
+	return recoverAfterNodes, expectedNodes
+}
+
+// getGatewayEnvVars returns the env vars setting the gateway settings of the VMI on the master nodes, if set
+func getGatewayEnvVars(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []corev1.EnvVar {
+	gateway := vmo.Spec.Opensearch.GatewaySettings
+	if gateway == nil {
+		return nil
+	}
+	recoverAfterNodes, expectedNodes := gatewayNodes(vmo)
+	envVars := []corev1.EnvVar{
+		{Name: gatewayRecoverAfterNodesEnv, Value: strconv.Itoa(int(recoverAfterNodes))},
+		{Name: gatewayExpectedNodesEnv, Value: strconv.Itoa(int(expectedNodes))},
+	}
+	if gateway.RecoverAfterTime != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: gatewayRecoverAfterTimeEnv, Value: gateway.RecoverAfterTime})
+	}
+	return envVars
+}
@@ -0,0 +1,156 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package statefulsets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createGatewayTestVMI creates a VMI with 7 nodes, 3 master nodes split across two node groups and 4 data nodes, and
+// the given gateway settings
+func createGatewayTestVMI(gateway *vmcontrollerv1.OpenSearchGatewaySettings) *vmcontrollerv1.VerrazzanoMonitoringInstance {
+	return &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 2,
+				},
+				Nodes: []vmcontrollerv1.ElasticsearchNode{
+					{
+						Name:     "es-master-zone2",
+						Replicas: 1,
+						Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole},
+					},
+					{
+						Name:     "es-data",
+						Replicas: 4,
+						Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole},
+					},
+				},
+				GatewaySettings: gateway,
+			},
+		},
+	}
+}
+
+// TestGatewaySettings tests the gateway env vars of the OpenSearch master StatefulSets
+// GIVEN a VMI with 7 nodes and with default, configured or no gateway settings
+// WHEN I call New
+// THEN the gateway env vars of every master StatefulSet are the configured values, or are derived from the master
+// replicas, and are not set without gateway settings
+func TestGatewaySettings(t *testing.T) {
+	tests := []struct {
+		name    string
+		gateway *vmcontrollerv1.OpenSearchGatewaySettings
+		envVars map[string]string
+	}{
+		{
+			name:    "no gateway settings",
+			envVars: map[string]string{},
+		},
+		{
+			name:    "derived",
+			gateway: &vmcontrollerv1.OpenSearchGatewaySettings{},
+			envVars: map[string]string{
+				gatewayRecoverAfterNodesEnv: "2",
+				gatewayExpectedNodesEnv:     "3",
+			},
+		},
+		{
+			name: "recover after nodes and expected nodes exceeding the master nodes",
+			gateway: &vmcontrollerv1.OpenSearchGatewaySettings{
+				RecoverAfterNodes: resources.NewVal(4),
+				ExpectedNodes:     resources.NewVal(7),
+			},
+			envVars: map[string]string{
+				gatewayRecoverAfterNodesEnv: "4",
+				gatewayExpectedNodesEnv:     "7",
+			},
+		},
+		{
+			name: "configured",
+			gateway: &vmcontrollerv1.OpenSearchGatewaySettings{
+				RecoverAfterNodes: resources.NewVal(5),
+				ExpectedNodes:     resources.NewVal(7),
+				RecoverAfterTime:  "10m",
+			},
+			envVars: map[string]string{
+				gatewayRecoverAfterNodesEnv: "5",
+				gatewayExpectedNodesEnv:     "7",
+				gatewayRecoverAfterTimeEnv:  "10m",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := New(vzlog.DefaultLogger(), createGatewayTestVMI(tt.gateway), &storageClass, "vmi-system-es-master-0")
+			assert.NoError(t, err)
+			assert.Len(t, result, 2)
+			for _, statefulSet := range result {
+				container := &statefulSet.Spec.Template.Spec.Containers[0]
+				for _, name := range []string{gatewayRecoverAfterNodesEnv, gatewayExpectedNodesEnv, gatewayRecoverAfterTimeEnv} {
+					value, isExpected := tt.envVars[name]
+					envVar := resources.GetEnvVar(container, name)
+					if !isExpected {
+						assert.Nil(t, envVar, name)
+						continue
+					}
+					assert.Equal(t, &corev1.EnvVar{Name: name, Value: value}, envVar)
+				}
+			}
+		})
+	}
+}
+
+// TestGatewaySettingsInvalid tests invalid gateway settings of the OpenSearch master StatefulSets
+// GIVEN a VMI with 7 nodes and an invalid recover after time, more recover after nodes than expected or derived
+// expected nodes, or more expected nodes than nodes
+// WHEN I call New
+// THEN an error is returned
+func TestGatewaySettingsInvalid(t *testing.T) {
+	tests := []struct {
+		name          string
+		gateway       *vmcontrollerv1.OpenSearchGatewaySettings
+		expectedError string
+	}{
+		{
+			name:          "invalid recover after time",
+			gateway:       &vmcontrollerv1.OpenSearchGatewaySettings{RecoverAfterTime: "5 minutes"},
+			expectedError: "recover after time",
+		},
+		{
+			name:          "recover after nodes exceeding the expected nodes",
+			gateway:       &vmcontrollerv1.OpenSearchGatewaySettings{RecoverAfterNodes: resources.NewVal(5), ExpectedNodes: resources.NewVal(4)},
+			expectedError: "the recover after nodes 5 exceed the expected nodes 4",
+		},
+		{
+			name:          "recover after nodes exceeding the derived expected nodes",
+			gateway:       &vmcontrollerv1.OpenSearchGatewaySettings{RecoverAfterNodes: resources.NewVal(4)},
+			expectedError: "the recover after nodes 4 exceed the expected nodes 3",
+		},
+		{
+			name:          "expected nodes exceeding the nodes",
+			gateway:       &vmcontrollerv1.OpenSearchGatewaySettings{ExpectedNodes: resources.NewVal(8)},
+			expectedError: "the expected nodes 8 exceed the 7 nodes of the cluster",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(vzlog.DefaultLogger(), createGatewayTestVMI(tt.gateway), &storageClass, "vmi-system-es-master-0")
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package statefulsets
+
+import (
+	"github.com/verrazzano/pkg/diff"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// IsStagedRollout returns true if the updates of the StatefulSet are rolled out one pod at a time by the controller,
+// through the partition of its rolling update strategy
+func IsStagedRollout(sts *appsv1.StatefulSet) bool {
+	return sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil
+}
+
+// StageRollout sets the partition of an expected StatefulSet with a staged rollout. When its pod template changed, the
+// partition only lets the pod with the highest ordinal be updated, otherwise the partition of the existing StatefulSet
+// is kept so that a rollout in progress is not reset.
+func StageRollout(expected, existing *appsv1.StatefulSet) {
+	if !IsStagedRollout(expected) {
+		return
+	}
+	if diff.Diff(existing.Spec.Template, expected.Spec.Template) != "" {
+		expected.Spec.UpdateStrategy.RollingUpdate.Partition = resources.NewVal(*expected.Spec.Replicas - 1)
+		return
+	}
+	if IsStagedRollout(existing) {
+		expected.Spec.UpdateStrategy.RollingUpdate.Partition = resources.NewVal(*existing.Spec.UpdateStrategy.RollingUpdate.Partition)
+	}
+}
+
+// IsRolloutSettled returns true if the StatefulSet controller observed the latest spec of the StatefulSet, every pod is
+// available, i.e. ready for at least the minimum ready seconds of the StatefulSet, and every pod at or above the
+// partition is updated
+func IsRolloutSettled(sts *appsv1.StatefulSet) bool {
+	replicas := *sts.Spec.Replicas
+	if sts.Status.ObservedGeneration < sts.Generation || sts.Status.AvailableReplicas < replicas {
+		return false
+	}
+	var partition int32
+	if IsStagedRollout(sts) {
+		partition = *sts.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+	return sts.Status.UpdatedReplicas >= replicas-partition
+}
+
+// NextRolloutPartition returns the partition letting the next pod of a staged rollout be updated, and false if the
+// StatefulSet has no rollout in progress or the pods updated so far are not settled
+func NextRolloutPartition(sts *appsv1.StatefulSet) (int32, bool) {
+	if !IsStagedRollout(sts) || *sts.Spec.UpdateStrategy.RollingUpdate.Partition < 1 || !IsRolloutSettled(sts) {
+		return 0, false
+	}
+	return *sts.Spec.UpdateStrategy.RollingUpdate.Partition - 1, true
+}
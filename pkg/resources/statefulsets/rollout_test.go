@@ -0,0 +1,132 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package statefulsets
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// createStagedTestSTS creates a StatefulSet with a staged rollout at the given partition, running the given image
+func createStagedTestSTS(name string, replicas, partition int32, image string) *appsv1.StatefulSet {
+	sts := createTestSTS(name, replicas)
+	sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: resources.NewVal(partition)},
+	}
+	sts.Spec.Template.Spec.Containers = []corev1.Container{{Name: "es-master", Image: image}}
+	sts.Status.AvailableReplicas = replicas
+	sts.Status.UpdatedReplicas = replicas - partition
+	return sts
+}
+
+// TestStageRollout Tests staging the rollout of master StatefulSets
+// GIVEN existing StatefulSets, and expected StatefulSets with and without a staged rollout
+// WHEN I call CreatePlan
+// THEN a changed pod template only lets the pod with the highest ordinal be updated, and the partition of a rollout
+// in progress is kept when the pod template did not change
+func TestStageRollout(t *testing.T) {
+	var tests = []struct {
+		name              string
+		existing          *appsv1.StatefulSet
+		expected          *appsv1.StatefulSet
+		expectedUpdate    bool
+		expectedPartition *int32
+	}{
+		{
+			"changed pod template is staged from the highest ordinal",
+			createStagedTestSTS("foo", 3, 0, "opensearch:1"),
+			createStagedTestSTS("foo", 3, 0, "opensearch:2"),
+			true,
+			resources.NewVal(2),
+		},
+		{
+			"rollout in progress is kept",
+			createStagedTestSTS("foo", 3, 1, "opensearch:2"),
+			createStagedTestSTS("foo", 3, 0, "opensearch:2"),
+			false,
+			resources.NewVal(1),
+		},
+		{
+			"changed pod template during a rollout restarts the rollout",
+			createStagedTestSTS("foo", 3, 1, "opensearch:2"),
+			createStagedTestSTS("foo", 3, 0, "opensearch:3"),
+			true,
+			resources.NewVal(2),
+		},
+		{
+			"rollout is not staged when disabled",
+			createStagedTestSTS("foo", 3, 0, "opensearch:1"),
+			func() *appsv1.StatefulSet {
+				sts := createStagedTestSTS("foo", 3, 0, "opensearch:2")
+				sts.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{}
+				return sts
+			}(),
+			true,
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// expected StatefulSets have no status
+			tt.expected.Status = appsv1.StatefulSetStatus{}
+			plan := CreatePlan(vzlog.DefaultLogger(), []*appsv1.StatefulSet{tt.existing}, []*appsv1.StatefulSet{tt.expected})
+			assert.NoError(t, plan.Conflict)
+			if tt.expectedUpdate {
+				assert.Equal(t, []*appsv1.StatefulSet{tt.expected}, plan.Update)
+			} else {
+				assert.Empty(t, plan.Update)
+			}
+			if tt.expectedPartition == nil {
+				assert.False(t, IsStagedRollout(tt.expected))
+			} else {
+				assert.Equal(t, tt.expectedPartition, tt.expected.Spec.UpdateStrategy.RollingUpdate.Partition)
+			}
+		})
+	}
+}
+
+// TestNextRolloutPartition Tests advancing the partition of a staged rollout
+// GIVEN StatefulSets with staged rollouts in various states
+// WHEN I call NextRolloutPartition
+// THEN the partition is lowered by one only when a rollout is in progress and the updated pods are settled
+func TestNextRolloutPartition(t *testing.T) {
+	notReady := createStagedTestSTS("foo", 3, 2, "opensearch:2")
+	notReady.Status.ReadyReplicas = 2
+	notReady.Status.AvailableReplicas = 2
+	notAvailable := createStagedTestSTS("foo", 3, 2, "opensearch:2")
+	notAvailable.Status.AvailableReplicas = 2
+	notUpdated := createStagedTestSTS("foo", 3, 2, "opensearch:2")
+	notUpdated.Status.UpdatedReplicas = 0
+	notObserved := createStagedTestSTS("foo", 3, 2, "opensearch:2")
+	notObserved.Generation = 2
+	notObserved.Status.ObservedGeneration = 1
+	var tests = []struct {
+		name              string
+		sts               *appsv1.StatefulSet
+		expectedPartition int32
+		expectedAdvance   bool
+	}{
+		{"settled pods advance the rollout", createStagedTestSTS("foo", 3, 2, "opensearch:2"), 1, true},
+		{"last pod of the rollout", createStagedTestSTS("foo", 3, 1, "opensearch:2"), 0, true},
+		{"completed rollout", createStagedTestSTS("foo", 3, 0, "opensearch:2"), 0, false},
+		{"rollout not staged", createTestSTS("foo", 3), 0, false},
+		{"updated pod not ready", notReady, 0, false},
+		{"updated pod ready for less than the minimum ready seconds", notAvailable, 0, false},
+		{"pod not updated yet", notUpdated, 0, false},
+		{"spec not observed yet", notObserved, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			partition, ok := NextRolloutPartition(tt.sts)
+			assert.Equal(t, tt.expectedAdvance, ok)
+			assert.Equal(t, tt.expectedPartition, partition)
+		})
+	}
+}
@@ -28,6 +28,27 @@ func New(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1.VerrazzanoMonitoringIns
 
 	// OpenSearch MasterNodes
 	if vmo.Spec.Opensearch.Enabled {
+		if err := resources.ValidateOpenSearchLoggers(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateOpenSearchCircuitBreakers(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateOpenSearchPreStartScript(vmo); err != nil {
+			return nil, err
+		}
+		if err := resources.ValidateOpenSearchTransportCompression(vmo); err != nil {
+			return nil, err
+		}
+		if err := validateGatewaySettings(vmo); err != nil {
+			return nil, err
+		}
+		if err := nodes.ValidateNodeRoles(vmo); err != nil {
+			return nil, err
+		}
+		if err := nodes.ValidateNodeNamePrefixes(vmo); err != nil {
+			return nil, err
+		}
 		statefulSets = append(statefulSets, createOpenSearchStatefulSets(log, vmo, storageClass, initialMasterNodes)...)
 	}
 	return statefulSets, nil
@@ -57,6 +78,15 @@ func createOpenSearchStatefulSet(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1
 	statefulSet.Spec.Template.Labels[constants.NodeGroupLabel] = node.Name
 
 	statefulSet.Spec.Replicas = resources.NewVal(node.Replicas)
+	// wait before a ready pod is available, so that a restarted master has rejoined the cluster before the rollout proceeds
+	statefulSet.Spec.MinReadySeconds = vmo.Spec.Opensearch.MinReadySeconds
+	if vmo.Spec.Opensearch.StagedMasterRollout {
+		// the controller lowers the partition one pod at a time, see StageRollout
+		statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+			Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+			RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: resources.NewVal(0)},
+		}
+	}
 	statefulSet.Spec.Template.Spec.Affinity = resources.CreateZoneAntiAffinityElement(vmo.Name, config.ElasticsearchMaster.Name)
 
 	podSecurityContext := &corev1.PodSecurityContext{
@@ -66,9 +96,13 @@ func createOpenSearchStatefulSet(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1
 
 	var elasticsearchUID int64 = 1000
 	esMasterContainer := &statefulSet.Spec.Template.Spec.Containers[0]
+	if node.Image != "" {
+		esMasterContainer.Image = node.Image
+	}
 	esMasterContainer.SecurityContext.RunAsUser = &elasticsearchUID
 	esMasterContainer.SecurityContext.AllowPrivilegeEscalation = resources.NewBool(false)
 	esMasterContainer.SecurityContext.Capabilities = &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}}
+	resources.SetOpenSearchSecurityContext(vmo, podSecurityContext, esMasterContainer.SecurityContext)
 	esMasterContainer.Ports[0].Name = "transport"
 	esMasterContainer.Ports = append(esMasterContainer.Ports, corev1.ContainerPort{Name: "http", ContainerPort: int32(constants.OSHTTPPort), Protocol: "TCP"})
 
@@ -81,11 +115,12 @@ func createOpenSearchStatefulSet(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1
 	if node.JavaOpts != "" {
 		javaOpts = node.JavaOpts
 	}
+	javaOpts = resources.GetOpenSearchJavaOpts(vmo, javaOpts)
 	// Adding command for add keystore values at pod bootup
 	esMasterContainer.Command = []string{
 		"sh",
 		"-c",
-		resources.CreateOpenSearchContainerCMD(javaOpts, resources.GetOpenSearchPluginList(vmo), resources.OSMasterPluginsInstallTmpl),
+		resources.CreateOpenSearchContainerCMD(javaOpts, resources.GetOpenSearchPluginList(vmo), resources.OSMasterPluginsInstallTmpl, resources.IsOpenSearchKeystoreMounted(vmo), vmo.Spec.Opensearch.PreStartScript),
 	}
 	var envVars = []corev1.EnvVar{
 		{
@@ -99,48 +134,39 @@ func createOpenSearchStatefulSet(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1
 		{Name: "cluster.name", Value: vmo.Name},
 		// HTTP is enabled on the master here solely for our readiness check below (on _cluster/health)
 		{Name: "HTTP_ENABLE", Value: "true"},
-		{Name: "logger.org.opensearch", Value: "info"},
-		{Name: constants.ObjectStoreAccessKeyVarName,
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: constants.VerrazzanoBackupScrtName,
-					},
-					Key: constants.ObjectStoreAccessKey,
-					Optional: func(opt bool) *bool {
-						return &opt
-					}(true),
-				},
-			},
-		},
-		{Name: constants.ObjectStoreCustomerKeyVarName,
-			ValueFrom: &corev1.EnvVarSource{
-				SecretKeyRef: &corev1.SecretKeySelector{
-					LocalObjectReference: corev1.LocalObjectReference{
-						Name: constants.VerrazzanoBackupScrtName,
-					},
-					Key: constants.ObjectStoreCustomerKey,
-					Optional: func(opt bool) *bool {
-						return &opt
-					}(true),
-				},
-			},
-		},
-		{
-			Name:  constants.DisableSecurityPluginOS,
-			Value: "true",
-		},
 	}
+	if node.NodeNamePrefix != "" {
+		// the node name is set when starting OpenSearch, from the node name prefix and the ordinal of the pod
+		envVars[0] = corev1.EnvVar{Name: constants.OpenSearchNodeNamePrefixEnv, Value: node.NodeNamePrefix}
+		esMasterContainer.Command[2] = resources.CreateOpenSearchNodeNamePrefixCMD(esMasterContainer.Command[2])
+	}
+	envVars = append(envVars, resources.GetOpenSearchLoggerEnvVars(vmo)...)
+	envVars = append(envVars, resources.GetOpenSearchCircuitBreakerEnvVars(vmo)...)
+	envVars = append(envVars, resources.GetOpenSearchTransportCompressionEnvVars(vmo)...)
+	envVars = append(envVars, resources.GetOpenSearchMaxClauseCountEnvVars(vmo)...)
+	envVars = append(envVars, getGatewayEnvVars(vmo)...)
+	envVars = append(envVars, resources.GetOpenSearchObjectStoreEnvVars(vmo)...)
+	envVars = append(envVars, corev1.EnvVar{
+		Name:  constants.DisableSecurityPluginOS,
+		Value: "true",
+	})
 	var readinessProbeCondition string
 	envVars = append(envVars,
 		corev1.EnvVar{Name: "OPENSEARCH_JAVA_OPTS", Value: javaOpts},
 	)
 	if nodes.IsSingleNodeCluster(vmo) {
-		node.Roles = []vmcontrollerv1.NodeRole{
+		roles := []vmcontrollerv1.NodeRole{
 			vmcontrollerv1.MasterRole,
 			vmcontrollerv1.DataRole,
 			vmcontrollerv1.IngestRole,
 		}
+		// keep the additional roles of the node, e.g. transform
+		for _, role := range node.Roles {
+			if role != vmcontrollerv1.MasterRole && role != vmcontrollerv1.DataRole && role != vmcontrollerv1.IngestRole {
+				roles = append(roles, role)
+			}
+		}
+		node.Roles = roles
 		log.Oncef("ES topology for %s indicates a single-node cluster (single master node only)", vmo.Name)
 		envVars = append(envVars,
 			corev1.EnvVar{Name: "node.roles", Value: nodes.GetRolesString(&node)},
@@ -158,6 +184,7 @@ func createOpenSearchStatefulSet(log vzlog.VerrazzanoLogger, vmo *vmcontrollerv1
 			envVars = append(envVars, corev1.EnvVar{Name: constants.ClusterInitialMasterNodes, Value: initialMasterNodes})
 		}
 	}
+	envVars = append(envVars, resources.GetOpenSearchAwarenessEnvVars(vmo, node.Name, envVars)...)
 	esMasterContainer.Env = envVars
 
 	basicAuthParams := ""
@@ -216,9 +243,11 @@ fi`,
 			TimeoutSeconds:      5,
 			FailureThreshold:    5,
 		}
+	resources.SetOpenSearchLivenessProbe(vmo, esMasterContainer.LivenessProbe)
+	resources.SetOpenSearchStartupProbe(vmo, esMasterContainer)
 
 	const esMasterVolName = "elasticsearch-master"
-	const esMasterData = "/usr/share/opensearch/data"
+	esMasterData := config.ElasticsearchMaster.DataDir
 
 	// Add the pv volume mount to the main container
 	esMasterContainer.VolumeMounts =
@@ -229,7 +258,7 @@ fi`,
 
 	// Add init container
 	statefulSet.Spec.Template.Spec.InitContainers = append(statefulSet.Spec.Template.Spec.InitContainers,
-		*resources.GetElasticsearchMasterInitContainer())
+		*resources.GetElasticsearchMasterInitContainer(vmo, esMasterData))
 
 	// Add the pv volume mount to the init container
 	statefulSet.Spec.Template.Spec.InitContainers[0].VolumeMounts =
@@ -257,7 +286,10 @@ fi`,
 		// Only set the storage class name if one was explicitly specified by the user.
 		// This is to facilitate upgrades where storage class name is empty,
 		// since you cannot update this field of a statefulset
-		if storageClass != nil {
+		if node.StorageClass != nil && *node.StorageClass != "" {
+			storageClassName := *node.StorageClass
+			statefulSet.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = &storageClassName
+		} else if storageClass != nil {
 			statefulSet.Spec.VolumeClaimTemplates[0].Spec.StorageClassName = &storageClass.Name
 		}
 	} else {
@@ -271,6 +303,12 @@ fi`,
 		}
 	}
 
+	// Mount the shared filesystem snapshot repository, the extra volumes, the GC logs volume and the keystore, if any
+	resources.AddOpenSearchSnapshotRepository(vmo, &statefulSet.Spec.Template.Spec, esMasterContainer)
+	resources.AddOpenSearchExtraVolumes(vmo, &statefulSet.Spec.Template.Spec, esMasterContainer)
+	resources.AddOpenSearchGCLogsVolume(vmo, &statefulSet.Spec.Template.Spec, esMasterContainer)
+	resources.AddOpenSearchKeystore(vmo, &statefulSet.Spec.Template.Spec, esMasterContainer)
+
 	// add istio annotations required for inter component communication
 	if statefulSet.Spec.Template.Annotations == nil {
 		statefulSet.Spec.Template.Annotations = make(map[string]string)
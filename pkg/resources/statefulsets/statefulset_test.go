@@ -4,6 +4,7 @@
 package statefulsets
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
@@ -403,3 +404,810 @@ func TestCreateMultiMasterWithStorage(t *testing.T) {
 	assert.Equal(t, nodes.RoleAssigned, sts.Spec.Template.Labels[nodes.RoleIngest])
 	assert.Equal(t, constants.ComponentOpenSearchValue, sts.Spec.Template.Labels[constants.ComponentLabel])
 }
+
+// TestCustomDataDir tests the creation of the OpenSearch master StatefulSet with a custom data directory
+// GIVEN a custom OpenSearch data directory
+//
+//	WHEN I call New
+//	THEN the data volume is mounted at the custom data directory in the OpenSearch and init containers
+//	 AND the init container changes the ownership of the custom data directory
+func TestCustomDataDir(t *testing.T) {
+	const customDataDir = "/opt/opensearch/data"
+	defaultDataDir := config.ElasticsearchMaster.DataDir
+	config.ElasticsearchMaster.DataDir = customDataDir
+	defer func() { config.ElasticsearchMaster.DataDir = defaultDataDir }()
+
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+					Storage: &vmcontrollerv1.Storage{
+						Size: "1Gi",
+					},
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result))
+	sts := result[0]
+	assert.Equal(t, customDataDir, sts.Spec.Template.Spec.Containers[0].VolumeMounts[0].MountPath)
+	assert.Equal(t, customDataDir, sts.Spec.Template.Spec.InitContainers[0].VolumeMounts[0].MountPath)
+	assert.Contains(t, sts.Spec.Template.Spec.InitContainers[0].Command[2], "chown -R 1000:1000 "+customDataDir+";")
+	assert.Equal(t, sts.Spec.VolumeClaimTemplates[0].Name, sts.Spec.Template.Spec.Containers[0].VolumeMounts[0].Name)
+}
+
+// TestOpenSearchLoggers tests the creation of the OpenSearch master StatefulSet with OpenSearch loggers
+// GIVEN a VMI with OpenSearch loggers
+//
+//	WHEN I call New
+//	THEN a logger env var is set for each logger, merged with the default org.opensearch logger
+//	 AND an error is returned if a logger level is invalid
+func TestOpenSearchLoggers(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+				},
+				Loggers: map[string]string{
+					"org.opensearch.discovery":      "debug",
+					"logger.org.opensearch.cluster": "TRACE",
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(result))
+	env := result[0].Spec.Template.Spec.Containers[0].Env
+	assert.Equal(t, corev1.EnvVar{Name: "logger.org.opensearch", Value: "info"}, env[3])
+	assert.Equal(t, corev1.EnvVar{Name: "logger.org.opensearch.cluster", Value: "trace"}, env[4])
+	assert.Equal(t, corev1.EnvVar{Name: "logger.org.opensearch.discovery", Value: "debug"}, env[5])
+
+	// The default logger can be overridden
+	vmi.Spec.Opensearch.Loggers["org.opensearch"] = "warn"
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	env = result[0].Spec.Template.Spec.Containers[0].Env
+	assert.Equal(t, corev1.EnvVar{Name: "logger.org.opensearch", Value: "warn"}, env[3])
+	loggerCount := 0
+	for _, envVar := range env {
+		if envVar.Name == "logger.org.opensearch" {
+			loggerCount++
+		}
+	}
+	assert.Equal(t, 1, loggerCount)
+
+	vmi.Spec.Opensearch.Loggers["org.opensearch.discovery"] = "verbose"
+	_, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.Error(t, err)
+}
+
+// TestOpenSearchPodSecurityContext tests the creation of the OpenSearch master StatefulSet with a pod security context override
+// GIVEN a VMI with and without an OpenSearch pod security context
+//
+//	WHEN I call New
+//	THEN the container runs as the OpenSearch user by default
+//	 AND the user and groups are overridden by the pod security context of the VMI
+func TestOpenSearchPodSecurityContext(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), *result[0].Spec.Template.Spec.Containers[0].SecurityContext.RunAsUser)
+	assert.Nil(t, result[0].Spec.Template.Spec.SecurityContext.FSGroup)
+
+	vmi.Spec.Opensearch.PodSecurityContext = &vmcontrollerv1.OpenSearchPodSecurityContext{
+		RunAsUser:  resources.New64Val(1000650000),
+		RunAsGroup: resources.New64Val(1000650001),
+		FSGroup:    resources.New64Val(1000650002),
+	}
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	podSecurityContext := result[0].Spec.Template.Spec.SecurityContext
+	assert.Equal(t, int64(1000650000), *result[0].Spec.Template.Spec.Containers[0].SecurityContext.RunAsUser)
+	assert.Equal(t, int64(1000650000), *podSecurityContext.RunAsUser)
+	assert.Equal(t, int64(1000650001), *podSecurityContext.RunAsGroup)
+	assert.Equal(t, int64(1000650002), *podSecurityContext.FSGroup)
+	assert.Equal(t, corev1.SeccompProfileTypeRuntimeDefault, podSecurityContext.SeccompProfile.Type)
+}
+
+// TestOpenSearchInitContainerDataOwner tests the owner of the data directory set by the init container of the OpenSearch
+// master StatefulSet
+// GIVEN a VMI with and without an OpenSearch pod security context
+//
+//	WHEN I call New
+//	THEN the data directory is owned by the OpenSearch user and group by default
+//	 AND the data directory is owned by the UID and FSGroup, or the GID if no FSGroup is set, of the pod security context
+func TestOpenSearchInitContainerDataOwner(t *testing.T) {
+	var tests = []struct {
+		name               string
+		podSecurityContext *vmcontrollerv1.OpenSearchPodSecurityContext
+		expectedOwner      string
+	}{
+		{
+			"default owner",
+			nil,
+			"1000:1000",
+		},
+		{
+			"UID and FSGroup",
+			&vmcontrollerv1.OpenSearchPodSecurityContext{
+				RunAsUser:  resources.New64Val(1000650000),
+				RunAsGroup: resources.New64Val(1000650001),
+				FSGroup:    resources.New64Val(1000650002),
+			},
+			"1000650000:1000650002",
+		},
+		{
+			"UID and GID without FSGroup",
+			&vmcontrollerv1.OpenSearchPodSecurityContext{
+				RunAsUser:  resources.New64Val(1000650000),
+				RunAsGroup: resources.New64Val(1000650001),
+			},
+			"1000650000:1000650001",
+		},
+		{
+			"UID only",
+			&vmcontrollerv1.OpenSearchPodSecurityContext{
+				RunAsUser: resources.New64Val(1000650000),
+			},
+			"1000650000:1000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "system",
+				},
+				Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+					Opensearch: vmcontrollerv1.Opensearch{
+						Enabled: true,
+						MasterNode: vmcontrollerv1.ElasticsearchNode{
+							Name:     "es-master",
+							Replicas: 1,
+						},
+						PodSecurityContext: tt.podSecurityContext,
+					},
+				},
+			}
+			result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+			assert.NoError(t, err)
+			assert.Equal(t, "chown -R "+tt.expectedOwner+" "+config.ElasticsearchMaster.DataDir+"; sysctl -w vm.max_map_count=262144",
+				result[0].Spec.Template.Spec.InitContainers[0].Command[2])
+		})
+	}
+}
+
+// TestOpenSearchNodeStorageClass tests the storage class of the volume claim template of the OpenSearch master StatefulSet
+// GIVEN a VMI with master node storage, with and without a master node storage class
+//
+//	WHEN I call New
+//	THEN the volume claim template uses the storage class of the node if set, otherwise the storage class of the VMI
+func TestOpenSearchNodeStorageClass(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+					Storage:  &vmcontrollerv1.Storage{Size: "50Gi"},
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultStorageClass, *result[0].Spec.VolumeClaimTemplates[0].Spec.StorageClassName)
+
+	masterStorageClass := "standard"
+	vmi.Spec.Opensearch.MasterNode.StorageClass = &masterStorageClass
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, masterStorageClass, *result[0].Spec.VolumeClaimTemplates[0].Spec.StorageClassName)
+
+	result, err = New(vzlog.DefaultLogger(), vmi, nil, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, masterStorageClass, *result[0].Spec.VolumeClaimTemplates[0].Spec.StorageClassName)
+}
+
+// TestOpenSearchNodeImage tests the image of the OpenSearch master StatefulSet
+// GIVEN a VMI with and without an image override on its master nodes
+// WHEN I call New
+// THEN the OpenSearch container uses the image of the master nodes if set, else the image of the operator config
+func TestOpenSearchNodeImage(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, config.ElasticsearchMaster.Image, result[0].Spec.Template.Spec.Containers[0].Image)
+
+	vmi.Spec.Opensearch.MasterNode.Image = "example.com/opensearch-analyzers:2.3.0"
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, "example.com/opensearch-analyzers:2.3.0", result[0].Spec.Template.Spec.Containers[0].Image)
+}
+
+// TestOpenSearchCircuitBreakers tests the creation of the OpenSearch master StatefulSet with circuit breaker limits
+// GIVEN a VMI with OpenSearch circuit breaker limits
+//
+//	WHEN I call New
+//	THEN an env var is set for each circuit breaker limit
+//	 AND an error is returned if a limit is invalid
+func TestOpenSearchCircuitBreakers(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+				},
+				CircuitBreakers: &vmcontrollerv1.OpenSearchCircuitBreakers{
+					Total:     "70%",
+					Fielddata: "2GB",
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	env := result[0].Spec.Template.Spec.Containers[0].Env
+	assert.Contains(t, env, corev1.EnvVar{Name: "indices.breaker.total.limit", Value: "70%"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "indices.breaker.fielddata.limit", Value: "2gb"})
+	for _, envVar := range env {
+		assert.NotEqual(t, "indices.breaker.request.limit", envVar.Name)
+	}
+
+	vmi.Spec.Opensearch.CircuitBreakers.Request = "lots"
+	_, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.Error(t, err)
+}
+
+// TestOpenSearchTransportCompression tests the creation of the OpenSearch master StatefulSet with a transport compression
+// GIVEN a VMI with each OpenSearch transport compression
+//
+//	WHEN I call New
+//	THEN the transport compression env vars are set
+//	 AND an error is returned if the compression is invalid
+func TestOpenSearchTransportCompression(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+				},
+			},
+		},
+	}
+	var tests = []struct {
+		compression string
+		expected    []corev1.EnvVar
+	}{
+		{"", nil},
+		{"none", []corev1.EnvVar{{Name: "transport.compress", Value: "false"}}},
+		{"indexing_data", []corev1.EnvVar{
+			{Name: "transport.compress", Value: "indexing_data"},
+			{Name: "transport.compression_scheme", Value: "deflate"},
+		}},
+		{"true", []corev1.EnvVar{
+			{Name: "transport.compress", Value: "true"},
+			{Name: "transport.compression_scheme", Value: "deflate"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.compression, func(t *testing.T) {
+			vmi.Spec.Opensearch.TransportCompression = tt.compression
+			result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+			assert.NoError(t, err)
+			var actual []corev1.EnvVar
+			for _, envVar := range result[0].Spec.Template.Spec.Containers[0].Env {
+				if strings.HasPrefix(envVar.Name, "transport.") {
+					actual = append(actual, envVar)
+				}
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+
+	vmi.Spec.Opensearch.TransportCompression = "gzip"
+	_, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.Error(t, err)
+}
+
+// TestOpenSearchMaxClauseCount tests the maximum number of clauses of a bool query of the OpenSearch master StatefulSet
+// GIVEN a VMI with and without a maximum clause count
+// WHEN I call New
+// THEN the indices.query.bool.max_clause_count env var is only set when the VMI has a maximum clause count, so that
+// removing it restores the OpenSearch default
+func TestOpenSearchMaxClauseCount(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+				},
+				MaxClauseCount: resources.NewVal(4096),
+			},
+		},
+	}
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	maxClauseCount := resources.GetEnvVar(&result[0].Spec.Template.Spec.Containers[0], "indices.query.bool.max_clause_count")
+	assert.NotNil(t, maxClauseCount)
+	assert.Equal(t, "4096", maxClauseCount.Value)
+
+	vmi.Spec.Opensearch.MaxClauseCount = nil
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Nil(t, resources.GetEnvVar(&result[0].Spec.Template.Spec.Containers[0], "indices.query.bool.max_clause_count"))
+}
+
+// TestOpenSearchAdditionalNodeRoles tests the node roles of a master node with additional roles
+// GIVEN a VMI with a master node which also has the transform and remote_cluster_client roles, and a node with only
+// the transform role
+// WHEN I call New
+// THEN the node.roles env var of the single node cluster statefulset has the additional roles of the master node,
+// and an error is returned for the node with only the transform role
+func TestOpenSearchAdditionalNodeRoles(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 1,
+					Roles: []vmcontrollerv1.NodeRole{
+						vmcontrollerv1.MasterRole,
+						vmcontrollerv1.TransformRole,
+						vmcontrollerv1.RemoteClusterClientRole,
+					},
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Contains(t, result[0].Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: "node.roles", Value: "master,data,ingest,transform,remote_cluster_client"})
+
+	vmi.Spec.Opensearch.Nodes = []vmcontrollerv1.ElasticsearchNode{
+		{
+			Name:     "transform",
+			Replicas: 1,
+			Roles:    []vmcontrollerv1.NodeRole{vmcontrollerv1.TransformRole},
+		},
+	}
+	_, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.Error(t, err)
+}
+
+// TestOpenSearchMinReadySeconds tests the minimum ready seconds of the OpenSearch master StatefulSet
+// GIVEN a VMI with and without OpenSearch minimum ready seconds
+// WHEN I call New
+// THEN the minReadySeconds of the StatefulSet is set from the VMI, zero by default
+func TestOpenSearchMinReadySeconds(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), result[0].Spec.MinReadySeconds)
+
+	vmi.Spec.Opensearch.MinReadySeconds = 30
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(30), result[0].Spec.MinReadySeconds)
+}
+
+// TestOpenSearchNodeNamePrefix Tests the node names of the OpenSearch master nodes
+// GIVEN a VMI without a master node name prefix, and with a master node name prefix
+// WHEN I call New
+// THEN the node name is the pod name by default, and is derived from the prefix and the pod ordinal otherwise
+func TestOpenSearchNodeNamePrefix(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	container := result[0].Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "node.name", container.Env[0].Name)
+	assert.Equal(t, "metadata.name", container.Env[0].ValueFrom.FieldRef.FieldPath)
+	assert.NotContains(t, container.Command[2], "node.name")
+
+	vmi.Spec.Opensearch.MasterNode.NodeNamePrefix = "logs-master-"
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "logs-master-0")
+	assert.NoError(t, err)
+	container = result[0].Spec.Template.Spec.Containers[0]
+	for _, env := range container.Env {
+		assert.NotEqual(t, "node.name", env.Name)
+	}
+	assert.Equal(t, corev1.EnvVar{Name: constants.OpenSearchNodeNamePrefixEnv, Value: "logs-master-"}, container.Env[0])
+	assert.Contains(t, container.Command[2], `env "node.name=${NODE_NAME_PREFIX}${HOSTNAME##*-}" /usr/local/bin/docker-entrypoint.sh`)
+
+	vmi.Spec.Opensearch.MasterNode.NodeNamePrefix = "-master"
+	_, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "")
+	assert.Error(t, err)
+}
+
+// TestOpenSearchLivenessProbeType Tests the liveness probe of the OpenSearch master statefulset
+// GIVEN a VMI without a liveness probe type, and VMIs with each liveness probe type
+// WHEN I call New
+// THEN the liveness probe of the master container checks the transport port by default, and matches the liveness probe type otherwise
+func TestOpenSearchLivenessProbeType(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	for _, probeType := range []vmcontrollerv1.LivenessProbeType{"", vmcontrollerv1.TCPLivenessProbe, vmcontrollerv1.HTTPLivenessProbe, vmcontrollerv1.ExecLivenessProbe} {
+		vmi.Spec.Opensearch.LivenessProbeType = probeType
+		result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+		assert.NoError(t, err)
+		probe := result[0].Spec.Template.Spec.Containers[0].LivenessProbe
+		assert.NotNil(t, probe)
+		assert.Equal(t, int32(5), probe.FailureThreshold)
+		switch probeType {
+		case "", vmcontrollerv1.TCPLivenessProbe:
+			assert.NotNil(t, probe.TCPSocket)
+			assert.Equal(t, int32(constants.OSTransportPort), probe.TCPSocket.Port.IntVal)
+		case vmcontrollerv1.HTTPLivenessProbe:
+			assert.Nil(t, probe.TCPSocket)
+			assert.NotNil(t, probe.HTTPGet)
+			assert.Equal(t, "/_cluster/health?local=true", probe.HTTPGet.Path)
+			assert.Equal(t, int32(constants.OSHTTPPort), probe.HTTPGet.Port.IntVal)
+		case vmcontrollerv1.ExecLivenessProbe:
+			assert.Nil(t, probe.TCPSocket)
+			assert.NotNil(t, probe.Exec)
+			assert.Contains(t, probe.Exec.Command[2], "/_cluster/health?local=true")
+		}
+	}
+}
+
+// TestOpenSearchStartupProbe Tests the startup probe of the OpenSearch master nodes
+// GIVEN a VMI without and with a startup probe configuration
+// WHEN I call New
+// THEN the master container has a startup probe checking the node like its liveness probe, with the default
+// or the configured period and failure threshold
+func TestOpenSearchStartupProbe(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	container := result[0].Spec.Template.Spec.Containers[0]
+	assert.NotNil(t, container.StartupProbe)
+	assert.Equal(t, container.LivenessProbe.ProbeHandler, container.StartupProbe.ProbeHandler)
+	assert.Equal(t, int32(constants.OpenSearchStartupProbeDefaultPeriodSeconds), container.StartupProbe.PeriodSeconds)
+	assert.Equal(t, int32(constants.OpenSearchStartupProbeDefaultFailureThreshold), container.StartupProbe.FailureThreshold)
+
+	vmi.Spec.Opensearch.LivenessProbeType = vmcontrollerv1.HTTPLivenessProbe
+	vmi.Spec.Opensearch.StartupProbe = &vmcontrollerv1.OpenSearchStartupProbe{FailureThreshold: 180, PeriodSeconds: 20}
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	container = result[0].Spec.Template.Spec.Containers[0]
+	assert.NotNil(t, container.StartupProbe.HTTPGet)
+	assert.Equal(t, int32(20), container.StartupProbe.PeriodSeconds)
+	assert.Equal(t, int32(180), container.StartupProbe.FailureThreshold)
+}
+
+// TestOpenSearchSnapshotRepository Tests the shared filesystem snapshot repository of the OpenSearch master nodes
+// GIVEN a VMI without and with a snapshot repository
+// WHEN I call New
+// THEN the shared filesystem is only mounted and set as path.repo when the snapshot repository is set
+func TestOpenSearchSnapshotRepository(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	for _, env := range result[0].Spec.Template.Spec.Containers[0].Env {
+		assert.NotEqual(t, "path.repo", env.Name)
+	}
+
+	vmi.Spec.Opensearch.SnapshotRepository = &vmcontrollerv1.OpenSearchSnapshotRepository{
+		Path:      "/mnt/snapshots",
+		ClaimName: "snapshots",
+	}
+	result, err = New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	podSpec := result[0].Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, corev1.Volume{
+		Name: constants.OpenSearchSnapshotRepositoryVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "snapshots"},
+		},
+	})
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      constants.OpenSearchSnapshotRepositoryVolumeName,
+		MountPath: "/mnt/snapshots",
+	})
+	assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "path.repo", Value: "/mnt/snapshots"})
+}
+
+// TestOpenSearchExtraVolumes Tests the extra volumes of the OpenSearch master nodes
+// GIVEN a VMI with an extra volume and volume mount
+// WHEN I call New
+// THEN the extra volume is added to the master pod, and the extra volume mount to the master container
+func TestOpenSearchExtraVolumes(t *testing.T) {
+	volume := corev1.Volume{
+		Name: "synonyms",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: "synonyms"}},
+		},
+	}
+	volumeMount := corev1.VolumeMount{Name: "synonyms", MountPath: "/usr/share/opensearch/config/analysis", ReadOnly: true}
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+				ExtraVolumes:      []corev1.Volume{volume},
+				ExtraVolumeMounts: []corev1.VolumeMount{volumeMount},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	podSpec := result[0].Spec.Template.Spec
+	assert.Contains(t, podSpec.Volumes, volume)
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, volumeMount)
+	assert.NotContains(t, podSpec.InitContainers[0].VolumeMounts, volumeMount)
+}
+
+// TestOpenSearchKeystoreSecret Tests the pre-populated keystore of the OpenSearch master nodes
+// GIVEN a VMI with a keystore Secret
+// WHEN I call New
+// THEN the keystore Secret is mounted on the master container, which copies it at startup instead of updating the
+// keystore from the object store credentials environment variables
+func TestOpenSearchKeystoreSecret(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+				KeystoreSecret: "opensearch-keystore",
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	podSpec := result[0].Spec.Template.Spec
+	var keystoreVolume *corev1.Volume
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == constants.OpenSearchKeystoreVolumeName {
+			keystoreVolume = &podSpec.Volumes[i]
+		}
+	}
+	assert.NotNil(t, keystoreVolume)
+	assert.Equal(t, "opensearch-keystore", keystoreVolume.Projected.Sources[0].Secret.Name)
+	assert.Contains(t, podSpec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      constants.OpenSearchKeystoreVolumeName,
+		MountPath: constants.OpenSearchKeystoreMountPath,
+		ReadOnly:  true,
+	})
+	for _, env := range podSpec.Containers[0].Env {
+		assert.NotEqual(t, constants.ObjectStoreAccessKeyVarName, env.Name)
+		assert.NotEqual(t, constants.ObjectStoreCustomerKeyVarName, env.Name)
+	}
+	assert.Contains(t, podSpec.Containers[0].Command[2], constants.OpenSearchKeystoreMountPath)
+	assert.NotContains(t, podSpec.Containers[0].Command[2], "opensearch-keystore add")
+}
+
+// TestOpenSearchAllocationAwareness Tests the allocation awareness node attributes of the OpenSearch master nodes
+// GIVEN a VMI with allocation awareness attributes with and without a value for the master node
+// WHEN I call New
+// THEN the master container has a node.attr env var for each attribute, with the value None for the attribute
+// without a value for the master node
+func TestOpenSearchAllocationAwareness(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+				},
+				AllocationAwareness: &vmcontrollerv1.OpenSearchAllocationAwareness{
+					Attributes: []vmcontrollerv1.OpenSearchAwarenessAttribute{
+						{Name: "zone", NodeValues: map[string]string{"es-master": "zone1"}},
+						{Name: "rack", NodeValues: map[string]string{"es-data": "rack1"}},
+					},
+				},
+			},
+		},
+	}
+
+	result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+	assert.NoError(t, err)
+	env := result[0].Spec.Template.Spec.Containers[0].Env
+	assert.Contains(t, env, corev1.EnvVar{Name: "node.attr.zone", Value: "zone1"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "node.attr.rack", Value: constants.OpenSearchAwarenessAttributeDefaultValue})
+}
+
+// TestOpenSearchGCLogging Tests the JVM garbage collection logs of the OpenSearch master nodes
+// GIVEN a VMI without and with the garbage collection logs enabled
+// WHEN I call New
+// THEN the garbage collection logging options and the GC logs volume are only added with the garbage collection logs enabled
+func TestOpenSearchGCLogging(t *testing.T) {
+	vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "system",
+		},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Enabled: true,
+				MasterNode: vmcontrollerv1.ElasticsearchNode{
+					Name:     "es-master",
+					Replicas: 3,
+					JavaOpts: "-Xms1g -Xmx1g",
+				},
+			},
+		},
+	}
+	volumeMount := corev1.VolumeMount{
+		Name:      constants.OpenSearchGCLogsVolumeName,
+		MountPath: constants.OpenSearchGCLogsMountPath,
+	}
+	for _, enabled := range []*bool{nil, resources.NewBool(false), resources.NewBool(true)} {
+		vmi.Spec.Opensearch.EnableGCLogging = enabled
+		result, err := New(vzlog.DefaultLogger(), vmi, &storageClass, "vmi-system-es-master-0")
+		assert.NoError(t, err)
+		podSpec := result[0].Spec.Template.Spec
+		var gcLogsVolumes int
+		for _, volume := range podSpec.Volumes {
+			if volume.Name == constants.OpenSearchGCLogsVolumeName {
+				gcLogsVolumes++
+			}
+		}
+		expectedJavaOpts := "-Xms1g -Xmx1g"
+		if enabled != nil && *enabled {
+			expectedJavaOpts = "-Xms1g -Xmx1g " + constants.OpenSearchGCLoggingJavaOpts
+			assert.Equal(t, 1, gcLogsVolumes)
+			assert.Contains(t, podSpec.Containers[0].VolumeMounts, volumeMount)
+		} else {
+			assert.Zero(t, gcLogsVolumes)
+			assert.NotContains(t, podSpec.Containers[0].VolumeMounts, volumeMount)
+		}
+		assert.Contains(t, podSpec.Containers[0].Env, corev1.EnvVar{Name: "OPENSEARCH_JAVA_OPTS", Value: expectedJavaOpts})
+	}
+}
@@ -1,10 +1,11 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package upgrade
 
 import (
 	"fmt"
+	"sync"
 
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
@@ -14,9 +15,12 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
 )
 
+// Monitor tracks the background migrations of the old indices of the VMIs to data streams, one migration per VMI.
+// The zero value is ready to use, and a Monitor may be shared by the workers reconciling different VMIs concurrently.
 type Monitor struct {
-	running bool
-	ch      chan error
+	mutex sync.Mutex
+	// migrations holds the result channel of the running migration of each VMI, keyed by VMI namespace and name
+	migrations map[string]chan error
 }
 
 func (m *Monitor) MigrateOldIndices(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance,
@@ -25,52 +29,37 @@ func (m *Monitor) MigrateOldIndices(log vzlog.VerrazzanoLogger, vmi *vmcontrolle
 		return nil
 	}
 
+	key := vmi.Namespace + "/" + vmi.Name
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	ch, running := m.migrations[key]
 	// if not already migrating, start migrating indices
-	if !m.running {
-		m.run(log, vmi, o, od)
+	if !running {
+		if m.migrations == nil {
+			m.migrations = map[string]chan error{}
+		}
+		// the migration outlives the reconcile, which keeps changing the VMI
+		m.migrations[key] = m.run(log, vmi.DeepCopy(), o, od)
 		return nil
 	}
 
-	complete, err := m.isUpgradeComplete()
-	// an error occurred during reindex
-	if err != nil {
-		// reset the monitor so we can retry the upgrade
-		m.reset()
+	select {
+	case err := <-ch:
+		// the migration is done, an error resets the monitor so we can retry the upgrade
+		delete(m.migrations, key)
 		return err
-	}
-	// reindex is still in progress
-	if !complete {
+	default:
+		// reindex is still in progress
 		log.Info("Data stream reindex is in progress.")
 		return nil
 	}
-	// reindex was successful
-	m.reset()
-	return nil
-}
-
-func (m *Monitor) isUpgradeComplete() (bool, error) {
-	if m.ch == nil {
-		return false, nil
-	}
-
-	select {
-	case e := <-m.ch:
-		return true, e
-	default:
-		return false, nil
-	}
-}
-
-func (m *Monitor) reset() {
-	m.running = false
-	close(m.ch)
 }
 
+// run starts migrating the old indices of the VMI, and returns the channel receiving the result of the migration. The
+// channel is buffered, so the migration ends even if its result is never read.
 func (m *Monitor) run(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.VerrazzanoMonitoringInstance,
-	o *opensearch.OSClient, od *dashboards.OSDashboardsClient) {
-	ch := make(chan error)
-	m.running = true
-	m.ch = ch
+	o *opensearch.OSClient, od *dashboards.OSDashboardsClient) chan error {
+	ch := make(chan error, 1)
 	// configuration is done asynchronously, as this does not need to be blocking
 	go func() {
 		if !vmi.Spec.Opensearch.Enabled {
@@ -104,4 +93,5 @@ func (m *Monitor) run(log vzlog.VerrazzanoLogger, vmi *vmcontrollerv1.Verrazzano
 		}
 		ch <- nil
 	}()
+	return ch
 }
@@ -1,16 +1,24 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package upgrade
 
 import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	kubeinformers "k8s.io/client-go/informers"
 	fake "k8s.io/client-go/kubernetes/fake"
 )
@@ -27,3 +35,58 @@ func TestMigrateOldIndicesNoErrorWhenOSNotReady(t *testing.T) {
 	err := monitor.MigrateOldIndices(vzlog.DefaultLogger(), &vmcontrollerv1.VerrazzanoMonitoringInstance{}, o, nil)
 	assert.NoError(t, err)
 }
+
+// TestMigrateOldIndicesConcurrentVMIs tests that the migrations of different VMIs are tracked separately
+// GIVEN a monitor shared by two OpenSearch-enabled VMIs whose OpenSearch is ready, and without migration data stream
+// WHEN I call MigrateOldIndices concurrently for both VMIs until their migrations are done
+// THEN a migration is started for each VMI, and completing the migration of a VMI does not reset the other one,
+// without data race when run with -race
+func TestMigrateOldIndicesConcurrentVMIs(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	statefulSetInformer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().StatefulSets()
+	var vmis []*vmcontrollerv1.VerrazzanoMonitoringInstance
+	for _, name := range []string{"vmi-1", "vmi-2"} {
+		vmi := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: constants.VerrazzanoSystemNamespace},
+			Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+				Opensearch: vmcontrollerv1.Opensearch{Enabled: true},
+			},
+		}
+		vmis = append(vmis, vmi)
+		assert.NoError(t, statefulSetInformer.Informer().GetIndexer().Add(&appsv1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name + "-es-master",
+				Namespace: vmi.Namespace,
+				Labels:    map[string]string{constants.VMOLabel: name, constants.ComponentLabel: constants.ComponentOpenSearchValue},
+			},
+			Status: appsv1.StatefulSetStatus{Replicas: 1, ReadyReplicas: 1},
+		}))
+	}
+	o := opensearch.NewOSClient(statefulSetInformer.Lister())
+	var requests int32
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&requests, 1)
+		return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+	}
+	monitor := &Monitor{}
+
+	var wg sync.WaitGroup
+	for _, vmi := range vmis {
+		wg.Add(1)
+		go func(vmi *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+			defer wg.Done()
+			// the first call starts the migration, the following calls complete it
+			assert.NoError(t, monitor.MigrateOldIndices(vzlog.DefaultLogger(), vmi, o, nil))
+			assert.Eventually(t, func() bool {
+				assert.NoError(t, monitor.MigrateOldIndices(vzlog.DefaultLogger(), vmi, o, nil))
+				monitor.mutex.Lock()
+				defer monitor.mutex.Unlock()
+				_, running := monitor.migrations[vmi.Namespace+"/"+vmi.Name]
+				return !running
+			}, 5*time.Second, 10*time.Millisecond)
+		}(vmi)
+	}
+	wg.Wait()
+	// each VMI checked for the migration data stream once
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
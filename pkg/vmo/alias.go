@@ -0,0 +1,35 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// trackPendingAliases persists the pending aliases in the VMI status before the aliases are synced, so that the
+// aliases added in OpenSearch are tracked even if the VMI update at the end of the reconcile fails. Only the status
+// of the VMI read at the start of the reconcile is updated, so the spec defaults set by the reconcile are not
+// persisted early, and both VMIs get the new resource version so that the final VMI update does not conflict.
+func trackPendingAliases(ctx context.Context, controller *Controller, vmo, originalVMO *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	pending := opensearch.PendingAliases(vmo)
+	// the pending aliases include the tracked aliases, so there is nothing to persist if no alias was added
+	if len(pending) == len(vmo.Status.Aliases) {
+		return nil
+	}
+	updated := originalVMO.DeepCopy()
+	updated.Status.Aliases = pending
+	result, err := controller.vmoclientset.VerrazzanoV1().VerrazzanoMonitoringInstances(vmo.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+	for _, vmi := range []*vmcontrollerv1.VerrazzanoMonitoringInstance{vmo, originalVMO} {
+		vmi.ResourceVersion = result.ResourceVersion
+		vmi.Status.Aliases = pending
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	vmofake "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/clientset/versioned/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestTrackPendingAliases Tests persisting the pending aliases before the aliases are synced
+// GIVEN a VMI with a tracked alias and a new alias, whose spec was changed by the reconcile
+// WHEN I call trackPendingAliases
+// THEN the VMI status is persisted with both aliases without the spec changes, and both VMIs track both aliases
+func TestTrackPendingAliases(t *testing.T) {
+	originalVMO := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "system", Namespace: "verrazzano-system"},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Aliases: []vmcontrollerv1.IndexAlias{
+					{Name: "new", Index: "new-*"},
+					{Name: "tracked", Index: "tracked-*"},
+				},
+			},
+		},
+		Status: vmcontrollerv1.VerrazzanoMonitoringInstanceStatus{
+			Aliases: []vmcontrollerv1.IndexAliasStatus{{Name: "tracked", Index: "tracked-*"}},
+		},
+	}
+	controller := &Controller{vmoclientset: vmofake.NewSimpleClientset(originalVMO.DeepCopy())}
+	vmo := originalVMO.DeepCopy()
+	vmo.Spec.URI = "changed"
+
+	assert.NoError(t, trackPendingAliases(context.TODO(), controller, vmo, originalVMO))
+	expected := []vmcontrollerv1.IndexAliasStatus{
+		{Name: "new", Index: "new-*"},
+		{Name: "tracked", Index: "tracked-*"},
+	}
+	assert.Equal(t, expected, vmo.Status.Aliases)
+	assert.Equal(t, expected, originalVMO.Status.Aliases)
+	persisted, err := controller.vmoclientset.VerrazzanoV1().VerrazzanoMonitoringInstances(vmo.Namespace).Get(context.TODO(), vmo.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected, persisted.Status.Aliases)
+	assert.Empty(t, persisted.Spec.URI)
+}
+
+// TestTrackPendingAliasesNoChange Tests that the VMI is not updated when no alias was added
+// GIVEN a VMI whose aliases are all tracked
+// WHEN I call trackPendingAliases
+// THEN the VMI is not updated
+func TestTrackPendingAliasesNoChange(t *testing.T) {
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{
+		ObjectMeta: metav1.ObjectMeta{Name: "system", Namespace: "verrazzano-system"},
+		Spec: vmcontrollerv1.VerrazzanoMonitoringInstanceSpec{
+			Opensearch: vmcontrollerv1.Opensearch{
+				Aliases: []vmcontrollerv1.IndexAlias{{Name: "tracked", Index: "tracked-*"}},
+			},
+		},
+		Status: vmcontrollerv1.VerrazzanoMonitoringInstanceStatus{
+			Aliases: []vmcontrollerv1.IndexAliasStatus{{Name: "tracked", Index: "tracked-*"}},
+		},
+	}
+	// the VMI does not exist, so an update would fail
+	controller := &Controller{vmoclientset: vmofake.NewSimpleClientset()}
+	assert.NoError(t, trackPendingAliases(context.TODO(), controller, vmo, vmo.DeepCopy()))
+}
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"context"
 	"html/template"
+	"reflect"
 	"strings"
 
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
@@ -26,10 +27,18 @@ import (
 const (
 	prometheusOperatorPrometheusHost = "prometheus-operator-kube-p-prometheus.verrazzano-monitoring"
 	datasourceYAMLKey                = "datasource.yaml"
+
+	// endpointsConfigMapComponent is the component of the name of the configmap publishing the endpoints of the VMI
+	endpointsConfigMapComponent = "endpoints"
+	// Keys of the endpoints configmap
+	openSearchURLKey           = "opensearch.url"
+	openSearchDashboardsURLKey = "opensearch-dashboards.url"
+	openSearchClusterNameKey   = "opensearch.cluster.name"
 )
 
 // CreateConfigmaps to create all required configmaps for VMI
-func CreateConfigmaps(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func CreateConfigmaps(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 	metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesConfigMap)
 	if metricErr != nil {
 		return metricErr
@@ -41,9 +50,9 @@ func CreateConfigmaps(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMoni
 	// Configmap for Grafana dashboard
 	dashboardTemplateMap := map[string]string{"vmo-dashboard-provider.yml": constants.DashboardProviderTmpl}
 	// Only create the CM if it doesnt exist. This will allow us to override the provider file e.g. Verrazzano
-	err := createConfigMapIfDoesntExist(controller, vmo, vmo.Spec.Grafana.DashboardsConfigMap, dashboardTemplateMap)
+	err := createConfigMapIfDoesntExist(ctx, controller, vmo, vmo.Spec.Grafana.DashboardsConfigMap, dashboardTemplateMap)
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed to create dashboard configmap %s: %v", vmo.Spec.Grafana.DashboardsConfigMap, err)
+		return log.ErrorfNewErr("Failed to create dashboard configmap %s: %v", vmo.Spec.Grafana.DashboardsConfigMap, err)
 	}
 	configMaps = append(configMaps, vmo.Spec.Grafana.DashboardsConfigMap)
 
@@ -52,41 +61,74 @@ func CreateConfigmaps(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMoni
 		constants.GrafanaTmplAlertManagerURI: resources.GetMetaName(vmo.Name, config.AlertManager.Name)}
 	dataSourceTemplate, err := asDashboardTemplate(constants.DataSourcesTmpl, replaceMap)
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed to create dashboard datasource template for VMI %s: %v", vmo.Name, err)
+		return log.ErrorfNewErr("Failed to create dashboard datasource template for VMI %s: %v", vmo.Name, err)
 	}
-	err = createUpdateDatasourcesConfigMap(controller, vmo, vmo.Spec.Grafana.DatasourcesConfigMap, map[string]string{datasourceYAMLKey: dataSourceTemplate})
+	err = createUpdateDatasourcesConfigMap(ctx, controller, vmo, vmo.Spec.Grafana.DatasourcesConfigMap, map[string]string{datasourceYAMLKey: dataSourceTemplate})
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed to create datasource configmap %s: %v", vmo.Spec.Grafana.DatasourcesConfigMap, err)
+		return log.ErrorfNewErr("Failed to create datasource configmap %s: %v", vmo.Spec.Grafana.DatasourcesConfigMap, err)
 	}
 	configMaps = append(configMaps, vmo.Spec.Grafana.DatasourcesConfigMap)
 
-	// Delete configmaps that shouldn't exist
-	controller.log.Debugf("Deleting unwanted ConfigMaps for VMI %s/%s", vmo.Namespace, vmo.Name)
+	// Configmap publishing the endpoints of the VMI, for the workloads which need to discover them
+	endpointsConfigMap := resources.GetMetaName(vmo.Name, endpointsConfigMapComponent)
+	if err := createUpdateConfigMap(ctx, controller, vmo, endpointsConfigMap, getEndpointsConfigMapData(vmo)); err != nil {
+		return log.ErrorfNewErr("Failed to create endpoints configmap %s: %v", endpointsConfigMap, err)
+	}
+	configMaps = append(configMaps, endpointsConfigMap)
+
+	// Delete configmaps that shouldn't exist, e.g. the configmaps of removed components
+	if err := deleteOrphanedConfigMaps(ctx, controller, vmo, configMaps); err != nil {
+		return err
+	}
+	timeMetric, timeErr := metricsexporter.GetTimestampMetrics(metricsexporter.NamesConfigMap)
+	if timeErr != nil {
+		return timeErr
+	}
+	timeMetric.SetLastTime()
+	return nil
+}
+
+// deleteOrphanedConfigMaps deletes the configmaps of the VMI which are not in the expected configmaps
+func deleteOrphanedConfigMaps(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, expectedConfigMaps []string) error {
+	log := reconcileLog(ctx)
+	log.Debugf("Deleting unwanted ConfigMaps for VMI %s/%s", vmo.Namespace, vmo.Name)
 	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
 	configMapList, err := controller.configMapLister.ConfigMaps(vmo.Namespace).List(selector)
 	if err != nil {
 		return err
 	}
 	for _, configMap := range configMapList {
-		if !contains(configMaps, configMap.Name) {
-			controller.log.Debugf("Deleting config map %s", configMap.Name)
-			err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Delete(context.TODO(), configMap.Name, metav1.DeleteOptions{})
-			if err != nil {
-				return controller.log.ErrorfNewErr("Failed to delete configmap %s%s: %v", vmo.Namespace, configMap.Name, err)
-			}
+		if contains(expectedConfigMaps, configMap.Name) {
+			continue
+		}
+		if err := deleteConfigMap(ctx, controller, vmo, configMap); err != nil {
+			return log.ErrorfNewErr("Failed to delete configmap %s/%s: %v", vmo.Namespace, configMap.Name, err)
 		}
 	}
-	timeMetric, timeErr := metricsexporter.GetTimestampMetrics(metricsexporter.NamesConfigMap)
-	if timeErr != nil {
-		return timeErr
+	return nil
+}
+
+func deleteConfigMap(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configMap *corev1.ConfigMap) error {
+	log := reconcileLog(ctx)
+	log.Oncef("Deleting configmap %s/%s", vmo.Namespace, configMap.Name)
+	err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Delete(ctx, configMap.Name, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesConfigMapDeleted)
+	if metricErr != nil {
+		// log it, the configmap is deleted
+		log.Errorf("Failed to get counter metric %s: %v", metricsexporter.NamesConfigMapDeleted, metricErr)
+	} else {
+		metric.Inc()
 	}
-	timeMetric.SetLastTime()
 	return nil
 }
 
 // createUpdateDatasourcesConfigMap creates or updates the Grafana datasource configmap. If the configmap exists and the Prometheus URL still points
 // to the legacy VMO-managed Prometheus, then replace the Prometheus URL with the new Prometheus Operator-managed Prometheus URL.
-func createUpdateDatasourcesConfigMap(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmapName string, data map[string]string) error {
+func createUpdateDatasourcesConfigMap(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmapName string, data map[string]string) error {
+	log := reconcileLog(ctx)
 
 	existingConfig, err := getConfigMap(controller, vmo.Namespace, configmapName)
 	if err != nil {
@@ -94,7 +136,7 @@ func createUpdateDatasourcesConfigMap(controller *Controller, vmo *vmcontrollerv
 	}
 	if existingConfig == nil {
 		configMap := configmaps.NewConfig(vmo, configmapName, data)
-		_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+		_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
 		if err != nil {
 			return err
 		}
@@ -104,11 +146,13 @@ func createUpdateDatasourcesConfigMap(controller *Controller, vmo *vmcontrollerv
 	// if the datasource still points to the legacy Prometheus instance, update it to point to the new Prometheus Operator-managed Prometheus
 	if ds, found := existingConfig.Data[datasourceYAMLKey]; found {
 		updatedDatasourceStr := strings.Replace(ds, resources.GetMetaName(vmo.Name, config.Prometheus.Name), prometheusOperatorPrometheusHost, 1)
+		// the user-supplied datasources may also point to another Prometheus service
+		updatedDatasourceStr = checkPrometheusDatasources(ctx, controller, vmo, configmapName, updatedDatasourceStr)
 		if updatedDatasourceStr != ds {
-			controller.log.Infof("Replacing Prometheus URL in existing datasource configmap %s/%s", vmo.Namespace, configmapName)
+			log.Infof("Replacing Prometheus URL in existing datasource configmap %s/%s", vmo.Namespace, configmapName)
 
 			existingConfig.Data[datasourceYAMLKey] = updatedDatasourceStr
-			_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Update(context.TODO(), existingConfig, metav1.UpdateOptions{})
+			_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Update(ctx, existingConfig, metav1.UpdateOptions{})
 			if err != nil {
 				return err
 			}
@@ -117,10 +161,45 @@ func createUpdateDatasourcesConfigMap(controller *Controller, vmo *vmcontrollerv
 	return nil
 }
 
+// getEndpointsConfigMapData returns the endpoints of the enabled components of the VMI, and the OpenSearch cluster name
+func getEndpointsConfigMapData(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) map[string]string {
+	data := map[string]string{}
+	if vmo.Spec.Opensearch.Enabled {
+		data[openSearchURLKey] = resources.GetOpenSearchHTTPEndpoint(vmo)
+		data[openSearchClusterNameKey] = vmo.Name
+	}
+	if vmo.Spec.OpensearchDashboards.Enabled {
+		data[openSearchDashboardsURLKey] = resources.GetOpenSearchDashboardsHTTPEndpoint(vmo)
+	}
+	return data
+}
+
+// createUpdateConfigMap creates the configmap, or updates its data if it changed
+func createUpdateConfigMap(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmapName string, data map[string]string) error {
+	log := reconcileLog(ctx)
+	existingConfig, err := getConfigMap(controller, vmo.Namespace, configmapName)
+	if err != nil {
+		return err
+	}
+	if existingConfig == nil {
+		configMap := configmaps.NewConfig(vmo, configmapName, data)
+		_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
+		return err
+	}
+	if reflect.DeepEqual(existingConfig.Data, data) || (len(existingConfig.Data) == 0 && len(data) == 0) {
+		return nil
+	}
+	log.Oncef("Updating configmap %s/%s", vmo.Namespace, configmapName)
+	updatedConfig := existingConfig.DeepCopy()
+	updatedConfig.Data = data
+	_, err = controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Update(ctx, updatedConfig, metav1.UpdateOptions{})
+	return err
+}
+
 // This function is being called for configmaps which don't modify with spec changes
-func createConfigMapIfDoesntExist(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmap string, data map[string]string) error {
+func createConfigMapIfDoesntExist(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmap string, data map[string]string) error {
 	configMap := configmaps.NewConfig(vmo, configmap, data)
-	_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+	_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(ctx, configMap, metav1.CreateOptions{})
 	if err != nil && !apierrors.IsAlreadyExists(err) {
 		return err
 	}
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -46,11 +46,11 @@ func TestCreateConfigmaps(t *testing.T) {
 
 	previousCount := testutil.ToFloat64(metricsexporter.TestDelegate.GetCounterMetric(metricsexporter.NamesConfigMap))
 
-	err := CreateConfigmaps(controller, vmo)
+	err := CreateConfigmaps(context.TODO(), controller, vmo)
 	t.Logf("Error is %v", err)
 	assert.Nil(t, err)
 	all, _ := client.CoreV1().ConfigMaps(vmo.Namespace).List(context.TODO(), metav1.ListOptions{})
-	assert.Equal(t, 2, len(all.Items))
+	assert.Equal(t, 3, len(all.Items))
 
 	newCount := testutil.ToFloat64(metricsexporter.TestDelegate.GetCounterMetric(metricsexporter.NamesConfigMap))
 	newTimeStamp := testutil.ToFloat64(metricsexporter.TestDelegate.GetTimestampMetric(metricsexporter.NamesConfigMap).WithLabelValues(fmt.Sprintf("%v", newCount)))
@@ -58,6 +58,82 @@ func TestCreateConfigmaps(t *testing.T) {
 	assert.LessOrEqual(t, int64(newTimeStamp*10)/10, time.Now().Unix())
 }
 
+// TestCreateConfigmapsDeletesOrphanedConfigMaps tests the deletion of the configmaps of the VMI which are no longer expected
+// GIVEN a stale configmap of the VMI, and a configmap with the VMO label of another VMI
+// WHEN I call CreateConfigmaps
+// THEN the stale configmap is deleted, the expected configmaps and the configmap of the other VMI are kept
+func TestCreateConfigmapsDeletesOrphanedConfigMaps(t *testing.T) {
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	vmo.Name = constants.VMODefaultName
+	vmo.Namespace = constants.VerrazzanoSystemNamespace
+	vmo.Spec.Grafana.DashboardsConfigMap = "myDashboardsConfigMap"
+	vmo.Spec.Grafana.DatasourcesConfigMap = "myDatasourcesConfigMap"
+	otherVMO := &vmctl.VerrazzanoMonitoringInstance{}
+	otherVMO.Name = "other"
+	otherVMO.Namespace = constants.VerrazzanoSystemNamespace
+
+	client := fake.NewSimpleClientset(
+		configmaps.NewConfig(vmo, "stale-oidc-config", map[string]string{"conf": "stale"}),
+		configmaps.NewConfig(otherVMO, "other-config", map[string]string{"conf": "other"}),
+	)
+	controller := &Controller{
+		kubeclientset:   client,
+		configMapLister: &simpleConfigMapLister{kubeClient: client},
+		secretLister:    &simpleSecretLister{kubeClient: client},
+		log:             vzlog.DefaultLogger(),
+	}
+	previousCount := testutil.ToFloat64(metricsexporter.TestDelegate.GetCounterMetric(metricsexporter.NamesConfigMapDeleted))
+
+	assert.NoError(t, CreateConfigmaps(context.TODO(), controller, vmo))
+	all, err := client.CoreV1().ConfigMaps(vmo.Namespace).List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	var names []string
+	for _, configMap := range all.Items {
+		names = append(names, configMap.Name)
+	}
+	assert.ElementsMatch(t, []string{"myDashboardsConfigMap", "myDatasourcesConfigMap", "vmi-system-endpoints", "other-config"}, names)
+	assert.Equal(t, previousCount+1, testutil.ToFloat64(metricsexporter.TestDelegate.GetCounterMetric(metricsexporter.NamesConfigMapDeleted)))
+}
+
+// TestCreateConfigmapsEndpoints tests publishing the endpoints of the VMI in a configmap
+// GIVEN a VMI with OpenSearch and OpenSearch Dashboards enabled
+// WHEN I call CreateConfigmaps, then disable OpenSearch Dashboards and call CreateConfigmaps again
+// THEN the endpoints configmap contains the OpenSearch and OpenSearch Dashboards endpoints and the cluster name,
+// and the OpenSearch Dashboards endpoint is removed once it is disabled
+func TestCreateConfigmapsEndpoints(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	controller := &Controller{
+		kubeclientset:   client,
+		configMapLister: &simpleConfigMapLister{kubeClient: client},
+		secretLister:    &simpleSecretLister{kubeClient: client},
+		log:             vzlog.DefaultLogger(),
+	}
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	vmo.Name = constants.VMODefaultName
+	vmo.Namespace = constants.VerrazzanoSystemNamespace
+	vmo.Spec.Grafana.DashboardsConfigMap = "myDashboardsConfigMap"
+	vmo.Spec.Grafana.DatasourcesConfigMap = "myDatasourcesConfigMap"
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.OpensearchDashboards.Enabled = true
+
+	assert.NoError(t, CreateConfigmaps(context.TODO(), controller, vmo))
+	endpoints, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), "vmi-system-endpoints", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		openSearchURLKey:           resources.GetOpenSearchHTTPEndpoint(vmo),
+		openSearchDashboardsURLKey: resources.GetOpenSearchDashboardsHTTPEndpoint(vmo),
+		openSearchClusterNameKey:   constants.VMODefaultName,
+	}, endpoints.Data)
+	assert.Equal(t, "http://vmi-system-es-master-http.verrazzano-system.svc.cluster.local:9200", endpoints.Data[openSearchURLKey])
+
+	vmo.Spec.OpensearchDashboards.Enabled = false
+	assert.NoError(t, CreateConfigmaps(context.TODO(), controller, vmo))
+	endpoints, err = client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), "vmi-system-endpoints", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.NotContains(t, endpoints.Data, openSearchDashboardsURLKey)
+	assert.Contains(t, endpoints.Data, openSearchURLKey)
+}
+
 // simple ConfigMapLister implementation
 type simpleConfigMapLister struct {
 	kubeClient kubernetes.Interface
@@ -203,7 +279,7 @@ func TestCreateUpdateDatasourcesConfigMap(t *testing.T) {
 		log:             vzlog.DefaultLogger(),
 	}
 
-	err = createUpdateDatasourcesConfigMap(controller, vmo, configMapName, map[string]string{})
+	err = createUpdateDatasourcesConfigMap(context.TODO(), controller, vmo, configMapName, map[string]string{})
 	assert.NoError(t, err)
 
 	// fetch the configmap, the Prometheus URL should now be the new Prometheus URL
@@ -40,6 +40,7 @@ import (
 	listers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/listers/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/grafana"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/metricsexporter"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
 	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
@@ -60,30 +61,32 @@ type Controller struct {
 	kubeextclientset apiextensionsclient.Interface
 
 	// listers and syncs
-	clusterRoleLister    rbacv1listers1.ClusterRoleLister
-	clusterRolesSynced   cache.InformerSynced
-	configMapLister      corelistersv1.ConfigMapLister
-	configMapsSynced     cache.InformerSynced
-	deploymentLister     appslistersv1.DeploymentLister
-	deploymentsSynced    cache.InformerSynced
-	ingressLister        netlistersv1.IngressLister
-	ingressesSynced      cache.InformerSynced
-	nodeLister           corelistersv1.NodeLister
-	nodesSynced          cache.InformerSynced
-	pvcLister            corelistersv1.PersistentVolumeClaimLister
-	pvcsSynced           cache.InformerSynced
-	roleBindingLister    rbacv1listers1.RoleBindingLister
-	roleBindingsSynced   cache.InformerSynced
-	secretLister         corelistersv1.SecretLister
-	secretsSynced        cache.InformerSynced
-	serviceLister        corelistersv1.ServiceLister
-	servicesSynced       cache.InformerSynced
-	statefulSetLister    appslistersv1.StatefulSetLister
-	statefulSetsSynced   cache.InformerSynced
-	vmoLister            listers.VerrazzanoMonitoringInstanceLister
-	vmosSynced           cache.InformerSynced
-	storageClassLister   storagelisters1.StorageClassLister
-	storageClassesSynced cache.InformerSynced
+	clusterRoleLister     rbacv1listers1.ClusterRoleLister
+	clusterRolesSynced    cache.InformerSynced
+	configMapLister       corelistersv1.ConfigMapLister
+	configMapsSynced      cache.InformerSynced
+	deploymentLister      appslistersv1.DeploymentLister
+	deploymentsSynced     cache.InformerSynced
+	ingressLister         netlistersv1.IngressLister
+	ingressesSynced       cache.InformerSynced
+	networkPolicyLister   netlistersv1.NetworkPolicyLister
+	networkPoliciesSynced cache.InformerSynced
+	nodeLister            corelistersv1.NodeLister
+	nodesSynced           cache.InformerSynced
+	pvcLister             corelistersv1.PersistentVolumeClaimLister
+	pvcsSynced            cache.InformerSynced
+	roleBindingLister     rbacv1listers1.RoleBindingLister
+	roleBindingsSynced    cache.InformerSynced
+	secretLister          corelistersv1.SecretLister
+	secretsSynced         cache.InformerSynced
+	serviceLister         corelistersv1.ServiceLister
+	servicesSynced        cache.InformerSynced
+	statefulSetLister     appslistersv1.StatefulSetLister
+	statefulSetsSynced    cache.InformerSynced
+	vmoLister             listers.VerrazzanoMonitoringInstanceLister
+	vmosSynced            cache.InformerSynced
+	storageClassLister    storagelisters1.StorageClassLister
+	storageClassesSynced  cache.InformerSynced
 
 	// misc
 	namespace      string
@@ -111,23 +114,63 @@ type Controller struct {
 	// recorder is an event recorder for recording Event resources to the
 	// Kubernetes API.
 	recorder record.EventRecorder
-
-	// VerrazzanoLogger is used to log
+	// deploymentUpdateFailures tracks consecutive failures of the same deployment update
+	deploymentUpdateFailures deploymentFailureTracker
+	// deploymentVersions tracks the resource versions of the deployments written by the controller
+	deploymentVersions deploymentVersionTracker
+	// dataScaleUps tracks the VMIs whose OpenSearch data nodes were scaled up, until the shards are relocated
+	dataScaleUps dataScaleUpTracker
+	// quotaBackoffs tracks the VMIs whose resources were rejected by a resource quota
+	quotaBackoffs quotaBackoffTracker
+	// dashboardsScaleDowns tracks the OpenSearch Dashboards deployments requested to scale to zero replicas
+	dashboardsScaleDowns dashboardsScaleDownTracker
+	// reconcileOperatorRBAC tells whether the ClusterRole and the ClusterRoleBinding of the VMO itself are reconciled
+	reconcileOperatorRBAC bool
+	// strictMasterQuorum tells whether the OpenSearch cluster of a VMI whose master nodes are at risk of losing their
+	// quorum is not created
+	strictMasterQuorum bool
+	// masterQuorumWarnings tracks the VMIs whose master nodes are at risk of losing their quorum
+	masterQuorumWarnings warningTracker
+	// openSearchDowngradeChecks tracks the last OpenSearch downgrade check of the OpenSearch images of the VMIs
+	openSearchDowngradeChecks keyedTracker[openSearchDowngradeCheck]
+	// correctGrafanaDatasources tells whether the Prometheus datasources of the Grafana datasources configmaps are
+	// corrected when they do not point to the expected Prometheus service
+	correctGrafanaDatasources bool
+	// prometheusDatasourceWarnings tracks the Grafana datasources configmaps whose Prometheus datasource does not
+	// point to the expected Prometheus service
+	prometheusDatasourceWarnings warningTracker
+	// reconcileOnNodeTopology tells whether the VMIs with OpenSearch enabled are reconciled when the topology labels of
+	// a node change, nodeTopologyDebounce is the delay coalescing the changes of many nodes
+	reconcileOnNodeTopology bool
+	nodeTopologyDebounce    time.Duration
+	// leaderElection tells whether the workqueue is only processed once the leader election Lease is acquired, so that
+	// several replicas of the VMO do not reconcile the same VMIs
+	leaderElection       bool
+	leaderElectionConfig leaderElectionConfig
+
+	// VerrazzanoLogger is used to log outside of a VMI reconcile, a reconcile logs with the loggers of its context
 	log vzlog.VerrazzanoLogger
 
-	// lowFrequencyLog is used to log messages at a lower frequency
-	// to reduce error noise in the logs
-	lowFrequencyLog vzlog.VerrazzanoLogger
-
 	// OpenSearch Client
 	osClient *opensearch.OSClient
 
 	// OpenSearchDashboards Client
 	osDashboardsClient *dashboards.OSDashboardsClient
 
+	// Grafana Client
+	grafanaClient *grafana.Client
+
 	indexUpgradeMonitor *upgrade.Monitor
 }
 
+// leaderElectionConfig is the identity of the replica and the timing of the leader election
+type leaderElectionConfig struct {
+	identity      string
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+}
+
 // ClusterInfo has info like ContainerRuntime and managed cluster name
 type ClusterInfo struct {
 	clusterName      string
@@ -193,6 +236,7 @@ func NewController(namespace string, configmapName string, buildVersion string,
 	configmapInformer := kubeInformerFactory.Core().V1().ConfigMaps()
 	deploymentInformer := kubeInformerFactory.Apps().V1().Deployments()
 	ingressInformer := kubeInformerFactory.Networking().V1().Ingresses()
+	networkPolicyInformer := kubeInformerFactory.Networking().V1().NetworkPolicies()
 	nodeInformer := kubeInformerFactory.Core().V1().Nodes()
 	pvcInformer := kubeInformerFactory.Core().V1().PersistentVolumeClaims()
 	roleBindingInformer := kubeInformerFactory.Rbac().V1().RoleBindings()
@@ -236,6 +280,8 @@ func NewController(namespace string, configmapName string, buildVersion string,
 		deploymentsSynced:     deploymentInformer.Informer().HasSynced,
 		ingressLister:         ingressInformer.Lister(),
 		ingressesSynced:       ingressInformer.Informer().HasSynced,
+		networkPolicyLister:   networkPolicyInformer.Lister(),
+		networkPoliciesSynced: networkPolicyInformer.Informer().HasSynced,
 		nodeLister:            nodeInformer.Lister(),
 		nodesSynced:           nodeInformer.Informer().HasSynced,
 		pvcLister:             pvcInformer.Lister(),
@@ -260,9 +306,9 @@ func NewController(namespace string, configmapName string, buildVersion string,
 		latestConfigMap:       operatorConfigMap,
 		clusterInfo:           ClusterInfo{},
 		log:                   vzlog.DefaultLogger(),
-		lowFrequencyLog:       vzlog.DefaultLogger(),
 		osClient:              osClient,
 		osDashboardsClient:    osDashboardsClient,
+		grafanaClient:         grafana.NewClient(),
 		indexUpgradeMonitor:   &upgrade.Monitor{},
 	}
 
@@ -276,6 +322,16 @@ func NewController(namespace string, configmapName string, buildVersion string,
 		},
 	})
 
+	// Set up an event handler for when managed deployments are changed externally
+	deploymentInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: controller.handleDeploymentUpdate,
+	})
+
+	// Set up an event handler for when the topology labels of the nodes change
+	nodeInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: controller.handleNodeUpdate,
+	})
+
 	// Create watchers on the operator ConfigMap, which may signify a need to reload our config
 	configMapInformer := kubeInformerFactory.Core().V1().ConfigMaps()
 	configMapInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -306,11 +362,20 @@ func NewController(namespace string, configmapName string, buildVersion string,
 	return controller, nil
 }
 
+// SkipOpenSearchHealthChecks makes the OpenSearch health gates of the controller always pass, for development clusters
+// without a real OpenSearch
+func (c *Controller) SkipOpenSearchHealthChecks() {
+	c.osClient.SkipHealthChecks()
+}
+
 // Run will set up the event handlers for types we are interested in, as well
 // as syncing informer caches and starting workers. It will block until stopCh
 // is closed, at which point it will shutdown the workqueue and wait for
 // workers to finish processing their current work items.
 func (c *Controller) Run(threadiness int) error {
+	if threadiness < 1 {
+		return fmt.Errorf("the number of workers must be at least 1, got %d", threadiness)
+	}
 	defer runtime.HandleCrash()
 	defer c.workqueue.ShutDown()
 
@@ -319,25 +384,88 @@ func (c *Controller) Run(threadiness int) error {
 
 	// Wait for the caches to be synced before starting workers
 	zap.S().Infow("Waiting for informer caches to sync")
-	if ok := cache.WaitForCacheSync(c.stopCh, c.clusterRolesSynced, c.configMapsSynced,
-		c.deploymentsSynced, c.ingressesSynced, c.nodesSynced, c.pvcsSynced, c.roleBindingsSynced, c.secretsSynced,
-		c.servicesSynced, c.statefulSetsSynced, c.vmosSynced, c.storageClassesSynced); !ok {
+	if ok := c.waitForCacheSync(); !ok {
 		return errors.New("failed to wait for caches to sync")
 	}
-
-	zap.S().Infow("Starting workers")
-	// Launch two workers to process VMO resources
-	for i := 0; i < threadiness; i++ {
-		go wait.Until(c.runWorker, time.Second, c.stopCh)
+	// Periodically re-check the informer sync status so that a stalled informer is surfaced in the metrics
+	go wait.Until(c.updateInformerSyncedMetrics, constants.InformerSyncCheckPeriod, c.stopCh)
+
+	if c.leaderElection {
+		// Only the leader processes the workqueue, the workers stop with the process once the leadership is lost
+		return c.runAsLeader(func(ctx context.Context) {
+			c.createOperatorRBAC(ctx)
+			c.startWorkers(threadiness)
+			<-ctx.Done()
+		})
 	}
 
-	zap.S().Infow("Started workers")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	c.createOperatorRBAC(ctx)
+	c.startWorkers(threadiness)
 	<-c.stopCh
 	zap.S().Infow("Shutting down workers")
 
 	return nil
 }
 
+// startWorkers launches the workers processing the workqueue until stopCh is closed
+func (c *Controller) startWorkers(threadiness int) {
+	zap.S().Infow("Starting workers", "workers", threadiness)
+	// Launch the workers to process VMO resources, the workqueue never hands the same VMO to two workers
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, c.stopCh)
+	}
+	zap.S().Infow("Started workers")
+}
+
+// informerSyncs returns the InformerSynced function of each informer used by the controller, keyed by informer name
+func (c *Controller) informerSyncs() map[string]cache.InformerSynced {
+	return map[string]cache.InformerSynced{
+		"clusterroles":    c.clusterRolesSynced,
+		"configmaps":      c.configMapsSynced,
+		"deployments":     c.deploymentsSynced,
+		"ingresses":       c.ingressesSynced,
+		"networkpolicies": c.networkPoliciesSynced,
+		"nodes":           c.nodesSynced,
+		"pvcs":            c.pvcsSynced,
+		"rolebindings":    c.roleBindingsSynced,
+		"secrets":         c.secretsSynced,
+		"services":        c.servicesSynced,
+		"statefulsets":    c.statefulSetsSynced,
+		"vmos":            c.vmosSynced,
+		"storageclasses":  c.storageClassesSynced,
+	}
+}
+
+// waitForCacheSync waits for all informer caches to sync and records the sync status of each informer
+func (c *Controller) waitForCacheSync() bool {
+	var syncs []cache.InformerSynced
+	for _, synced := range c.informerSyncs() {
+		syncs = append(syncs, synced)
+	}
+	ok := cache.WaitForCacheSync(c.stopCh, syncs...)
+	c.updateInformerSyncedMetrics()
+	return ok
+}
+
+// updateInformerSyncedMetrics sets the informer synced gauge to 1 for each synced informer, and 0 otherwise
+func (c *Controller) updateInformerSyncedMetrics() {
+	metric, err := metricsexporter.GetLabeledGaugeMetrics(metricsexporter.NamesInformerSynced)
+	if err != nil {
+		zap.S().Errorf("Failed to get labeled gauge metric %s: %v", metricsexporter.NamesInformerSynced, err)
+		return
+	}
+	for name, synced := range c.informerSyncs() {
+		if synced != nil && synced() {
+			metric.SetWithLabel(name, 1)
+		} else {
+			zap.S().Warnf("Informer cache for %s is not synced", name)
+			metric.SetWithLabel(name, 0)
+		}
+	}
+}
+
 // runWorker is a long-running function that will continually call the
 // processNextWorkItem function in order to read and process a message on the
 // workqueue.
@@ -382,7 +510,7 @@ func (c *Controller) processNextWorkItem() bool {
 		// Run the syncHandler, passing it the namespace/name string of the
 		// VMO resource to be synced.
 		if err := c.syncHandler(key); err != nil {
-			return fmt.Errorf("error syncing '%s': %s", key, err.Error())
+			return c.handleSyncError(key, err)
 		}
 		// Finally, if no error occurs we Forget this item so it does not
 		// get queued again until another change happens.
@@ -398,6 +526,22 @@ func (c *Controller) processNextWorkItem() bool {
 	return true
 }
 
+// handleSyncError requeues the VMI of a failed reconcile if it should be retried, and returns the error to report.
+// A reconcile waiting for a temporary condition is retried after a back-off without being reported as an error, the
+// item is not forgotten so the back-off grows while the condition lasts.
+func (c *Controller) handleSyncError(key string, err error) error {
+	if isWaitingError(err) {
+		c.workqueue.AddRateLimited(key)
+		return nil
+	}
+	// A reconcile cancelled because it exceeded the maximum reconcile duration, or which could not update the status
+	// of the VMI, is retried after a back-off
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, errStatusUpdate) {
+		c.workqueue.AddRateLimited(key)
+	}
+	return fmt.Errorf("error syncing '%s': %s", key, err.Error())
+}
+
 // Process an update to a VMO
 func (c *Controller) syncHandler(key string) error {
 	// Convert the namespace/name string into a distinct namespace and name
@@ -417,12 +561,19 @@ func (c *Controller) syncHandler(key string) error {
 		return err
 	}
 
-	c.log = getLogger(vmo)
-	c.lowFrequencyLog = getLogger(vmo)
-	c.lowFrequencyLog.SetFrequency(120)
-
-	c.log.Progressf("Reconciling vmi resource %v, generation %v", types.NamespacedName{Namespace: vmo.Namespace, Name: vmo.Name}, vmo.Generation)
-	return c.syncHandlerStandardMode(vmo)
+	// The loggers are built for each reconcile and passed down with its context, as several workers reconcile VMIs
+	// concurrently
+	log := getLogger(vmo)
+	log.Progressf("Reconciling vmi resource %v, generation %v", types.NamespacedName{Namespace: vmo.Namespace, Name: vmo.Name}, vmo.Generation)
+	// Bound the reconcile, so a slow OpenSearch or API server call cannot block the worker
+	ctx, cancel := context.WithTimeout(withReconcileLoggers(context.Background(), log), *c.operatorConfig.MaxReconcileDuration)
+	defer cancel()
+	err = c.syncHandlerStandardMode(ctx, vmo)
+	if ctx.Err() != nil {
+		log.Errorf("Reconcile of VMI %s/%s exceeded the maximum duration of %v and was cancelled", vmo.Namespace, vmo.Name, *c.operatorConfig.MaxReconcileDuration)
+		return fmt.Errorf("reconcile cancelled: %w", ctx.Err())
+	}
+	return err
 }
 
 // getLogger returns the resource logger needed to log message using 'progress' and 'once' methods
@@ -440,19 +591,59 @@ func getLogger(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) vzlog.Verrazzan
 	return log
 }
 
+// reconcileLoggers are the loggers of a VMI reconcile
+type reconcileLoggers struct {
+	log vzlog.VerrazzanoLogger
+	// lowFrequencyLog is used to log messages at a lower frequency to reduce error noise in the logs
+	lowFrequencyLog vzlog.VerrazzanoLogger
+}
+
+type reconcileLoggersKey struct{}
+
+// withReconcileLoggers returns a context carrying the given logger of a VMI reconcile, and a logger of the same VMI
+// logging messages at a lower frequency
+func withReconcileLoggers(ctx context.Context, log vzlog.VerrazzanoLogger) context.Context {
+	lowFrequencyLog := log.GetContext().EnsureLogger("low-frequency", log.GetZapLogger(), log.GetZapLogger()).SetFrequency(120)
+	return context.WithValue(ctx, reconcileLoggersKey{}, &reconcileLoggers{log: log, lowFrequencyLog: lowFrequencyLog})
+}
+
+// reconcileLog returns the logger of the VMI reconcile of the context, the default logger outside of a reconcile
+func reconcileLog(ctx context.Context) vzlog.VerrazzanoLogger {
+	if loggers, ok := ctx.Value(reconcileLoggersKey{}).(*reconcileLoggers); ok {
+		return loggers.log
+	}
+	return vzlog.DefaultLogger()
+}
+
+// reconcileLowFrequencyLog returns the low frequency logger of the VMI reconcile of the context, the default logger
+// outside of a reconcile
+func reconcileLowFrequencyLog(ctx context.Context) vzlog.VerrazzanoLogger {
+	if loggers, ok := ctx.Value(reconcileLoggersKey{}).(*reconcileLoggers); ok {
+		return loggers.lowFrequencyLog
+	}
+	return vzlog.DefaultLogger()
+}
+
 // In Standard Mode, we compare the actual state with the desired, and attempt to
 // converge the two.  We then update the Status block of the VMO resource
 // with the current status.
-func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func (c *Controller) syncHandlerStandardMode(ctx context.Context, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
+	lowFrequencyLog := reconcileLowFrequencyLog(ctx)
 	var errorObserved bool
+	// waitErr is set when a reconcile step is waiting for a temporary condition
+	var waitErr error
+	// statusErr is set when the VMI could not be updated with its status
+	var statusErr error
 	functionMetric, functionError := metricsexporter.GetFunctionMetrics(metricsexporter.NamesReconcile)
 	if functionError == nil {
-		functionMetric.LogStart()
-		defer func() { functionMetric.LogEnd(errorObserved) }()
+		timer := functionMetric.LogStart()
+		defer func() { functionMetric.LogEnd(timer, errorObserved) }()
 	} else {
 		return functionError
 	}
 
+	syncStart := time.Now()
 	originalVMO := vmo.DeepCopy()
 
 	// populate clusterInfo
@@ -465,94 +656,190 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 
 	// If lock, controller will not sync/process the VMO env
 	if vmo.Spec.Lock {
-		c.log.Progressf("[%s/%s] Lock is set to true, this VMO env will not be synced/processed.", vmo.Name, vmo.Namespace)
+		log.Progressf("[%s/%s] Lock is set to true, this VMO env will not be synced/processed.", vmo.Name, vmo.Namespace)
 		return nil
 	}
 
 	/*********************
 	 * Initialize VMO Spec
 	 **********************/
-	InitializeVMOSpec(c, vmo)
+	InitializeVMOSpec(ctx, c, vmo)
 
 	errorObserved = false
 
-	/***************************************
-	 * Configure Index AutoExpand settings
-	 ****************************************/
-	autoExpandIndexChannel := c.osClient.SetAutoExpandIndices(vmo)
-
-	/*********************
-	 * Configure ISM
-	 **********************/
-	ismChannel := c.osClient.ConfigureISM(vmo)
+	// If OpenSearch is paused, the OpenSearch cluster is left untouched while the other components are synced
+	openSearchPaused := resources.IsOpenSearchPaused(vmo)
+	if openSearchPaused {
+		log.Progressf("[%s/%s] OpenSearch is paused, the OpenSearch cluster will not be synced/processed.", vmo.Name, vmo.Namespace)
+	}
 
-	/*********************
-	 * Synchronise Default ISM Policies
-	 **********************/
-	defaultISMChannel := c.osClient.SyncDefaultISMPolicy(c.log, vmo)
+	osClient := c.osClient.WithContext(ctx)
+	autoExpandIndexChannel := skippedChannel()
+	ismChannel := skippedChannel()
+	defaultISMChannel := skippedChannel()
+	ingestPipelinesChannel := skippedChannel()
+	indexDefaultsChannel := skippedChannel()
+	indexSortChannel := skippedChannel()
+	componentTemplatesChannel := skippedChannel()
+	searchBackpressureChannel := skippedChannel()
+	autoCreateIndexChannel := skippedChannel()
+	allocationAwarenessChannel := skippedChannel()
+	diskWatermarksChannel := skippedChannel()
+	monitorsChannel := skippedMonitorsChannel()
+	aliasesChannel := skippedAliasesChannel()
+	if !openSearchPaused {
+		/***************************************
+		 * Configure Index AutoExpand settings
+		 ****************************************/
+		autoExpandIndexChannel = osClient.SetAutoExpandIndices(vmo)
+
+		/*********************
+		 * Configure ISM
+		 **********************/
+		ismChannel = osClient.ConfigureISM(vmo)
+
+		/*********************
+		 * Synchronise Default ISM Policies
+		 **********************/
+		defaultISMChannel = osClient.SyncDefaultISMPolicy(log, vmo)
+
+		/*********************
+		 * Configure Ingest Pipelines
+		 **********************/
+		ingestPipelinesChannel = osClient.ConfigureIngestPipelines(vmo)
+
+		/*********************
+		 * Configure Index Defaults
+		 **********************/
+		indexDefaultsChannel = osClient.ConfigureIndexDefaults(vmo)
+
+		/*********************
+		 * Configure Default Index Sort
+		 **********************/
+		indexSortChannel = osClient.ConfigureIndexSort(vmo)
+
+		/*********************
+		 * Configure Component Templates
+		 **********************/
+		componentTemplatesChannel = osClient.ConfigureComponentTemplates(vmo)
+
+		/*********************
+		 * Configure Search Backpressure
+		 **********************/
+		searchBackpressureChannel = osClient.ConfigureSearchBackpressure(vmo)
+
+		/*********************
+		 * Configure Index Auto Creation
+		 **********************/
+		autoCreateIndexChannel = osClient.ConfigureAutoCreateIndex(vmo)
+
+		/*********************
+		 * Configure Shard Allocation Awareness
+		 **********************/
+		allocationAwarenessChannel = osClient.ConfigureAllocationAwareness(vmo)
+
+		/*********************
+		 * Configure Disk Watermarks
+		 **********************/
+		diskWatermarksChannel = osClient.ConfigureDiskWatermarks(vmo)
+
+		/*********************
+		 * Configure Alerting Monitors
+		 **********************/
+		monitorsChannel = osClient.ConfigureMonitors(vmo)
+
+		/*********************
+		 * Configure Index Aliases
+		 **********************/
+		if err := trackPendingAliases(ctx, c, vmo, originalVMO); err != nil {
+			lowFrequencyLog.ErrorfThrottled("Failed to track the pending index aliases, the index aliases will be configured later: %v", err)
+			errorObserved = true
+		} else {
+			aliasesChannel = osClient.ConfigureAliases(vmo)
+		}
 
-	/********************************************
-	 * Migrate old indices if any to data streams
-	*********************************************/
-	err = c.indexUpgradeMonitor.MigrateOldIndices(c.log, vmo, c.osClient, c.osDashboardsClient)
-	if err != nil {
-		c.lowFrequencyLog.ErrorfThrottled("Failed to migrate old indices to data stream: %v", err)
-		errorObserved = true
+		/********************************************
+		 * Migrate old indices if any to data streams
+		*********************************************/
+		// The migration runs in the background across reconciles, so it must not use the client and logger bound to
+		// this reconcile, which are cancelled once the reconcile returns
+		err = c.indexUpgradeMonitor.MigrateOldIndices(getLogger(vmo), vmo, c.osClient, c.osDashboardsClient)
+		if err != nil {
+			lowFrequencyLog.ErrorfThrottled("Failed to migrate old indices to data stream: %v", err)
+			errorObserved = true
+		}
 	}
 
 	/*********************
 	 * Create RoleBindings
 	 **********************/
-	err = CreateRoleBindings(c, vmo)
+	err = CreateRoleBindings(ctx, c, vmo)
 	if err != nil {
-		c.log.ErrorfThrottled("Failed to create Role Bindings for VMI %s: %v", vmo.Name, err)
+		log.ErrorfThrottled("Failed to create Role Bindings for VMI %s: %v", vmo.Name, err)
 		errorObserved = true
 	}
 
 	/*********************
 	* Create configmaps
 	**********************/
-	err = CreateConfigmaps(c, vmo)
+	err = CreateConfigmaps(ctx, c, vmo)
 	if err != nil {
-		c.log.ErrorfThrottled("Failed to create configmaps for VMI %s: %v", vmo.Name, err)
+		log.ErrorfThrottled("Failed to create configmaps for VMI %s: %v", vmo.Name, err)
 		errorObserved = true
 	}
 
 	/*********************
 	 * Create Services
 	 **********************/
-	err = CreateServices(c, vmo)
+	err = CreateServices(ctx, c, vmo)
 	if err != nil {
-		c.log.ErrorfThrottled("Failed to create Services for VMI %s: %v", vmo.Name, err)
+		log.ErrorfThrottled("Failed to create Services for VMI %s: %v", vmo.Name, err)
 		errorObserved = true
 	}
 
 	/*********************
-	 * Create Persistent Volume Claims
+	 * Create NetworkPolicies
 	 **********************/
-	pvcToAdMap, err := CreatePersistentVolumeClaims(c, vmo)
+	err = CreateNetworkPolicies(ctx, c, vmo)
 	if err != nil {
-		c.log.ErrorfThrottled("Failed to create/update PVCs for VMI %s: %v", vmo.Name, err)
+		log.ErrorfThrottled("Failed to create NetworkPolicies for VMI %s: %v", vmo.Name, err)
 		errorObserved = true
 	}
 
 	/*********************
-	 * Create StatefulSets
+	 * Create Persistent Volume Claims
 	 **********************/
-	existingCluster, err := CreateStatefulSets(c, vmo)
+	pvcToAdMap, err := CreatePersistentVolumeClaims(ctx, c, vmo)
 	if err != nil {
-		c.log.ErrorfThrottled("Failed to create/update statefulsets for VMI %s: %v", vmo.Name, err)
+		log.ErrorfThrottled("Failed to create/update PVCs for VMI %s: %v", vmo.Name, err)
 		errorObserved = true
 	}
 
+	/*********************
+	 * Create StatefulSets
+	 **********************/
+	var existingCluster bool
+	if !openSearchPaused {
+		existingCluster, err = CreateStatefulSets(ctx, c, vmo)
+		if err != nil {
+			log.ErrorfThrottled("Failed to create/update statefulsets for VMI %s: %v", vmo.Name, err)
+			errorObserved = true
+		}
+	}
+
 	/*********************
 	 * Create Deployments
 	 **********************/
 	var deploymentsDirty bool
 	if !errorObserved {
-		deploymentsDirty, err = CreateDeployments(c, vmo, pvcToAdMap, existingCluster)
-		if err != nil {
-			c.log.ErrorfThrottled("Failed to create/update deployments for VMI %s: %v", vmo.Name, err)
+		deploymentsDirty, err = CreateDeployments(ctx, c, vmo, pvcToAdMap, existingCluster)
+		if isWaitingError(err) {
+			// The deployments are not done yet, the VMI is requeued with a back-off once the reconcile is complete
+			log.Progressf("Waiting for the deployments of VMI %s: %v", vmo.Name, err)
+			deploymentsDirty = true
+			waitErr = err
+		} else if err != nil {
+			log.ErrorfThrottled("Failed to create/update deployments for VMI %s: %v", vmo.Name, err)
 			functionMetric.IncError()
 			errorObserved = true
 		}
@@ -560,62 +847,165 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 	/*********************
 	 * Create Ingresses
 	 **********************/
-	err = CreateIngresses(c, vmo)
+	err = CreateIngresses(ctx, c, vmo)
 	if err != nil {
-		c.log.ErrorfThrottled("Failed to create Ingresses for VMI %s: %v", vmo.Name, err)
+		log.ErrorfThrottled("Failed to create Ingresses for VMI %s: %v", vmo.Name, err)
 		errorObserved = true
 	}
 
+	/*********************
+	 * Track the alerting monitors, the VMI status must be updated with the monitors created in OpenSearch
+	 **********************/
+	monitorsResult := <-monitorsChannel
+	if monitorsResult.Err != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure alerting monitors: %v", monitorsResult.Err)
+		errorObserved = true
+	}
+	if monitorsResult.Synced {
+		vmo.Status.Monitors = monitorsResult.Monitors
+	}
+
+	/*********************
+	 * Track the index aliases, the VMI status must be updated with the aliases added in OpenSearch
+	 **********************/
+	aliasesResult := <-aliasesChannel
+	if aliasesResult.Err != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure index aliases: %v", aliasesResult.Err)
+		errorObserved = true
+	}
+	if aliasesResult.Synced {
+		vmo.Status.Aliases = aliasesResult.Aliases
+	}
+
+	/*********************
+	 * Import the saved objects into OpenSearch Dashboards, the VMI status must be updated with the imported content
+	 **********************/
+	err = importSavedObjects(ctx, c, vmo)
+	if err != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to import saved objects into OpenSearch Dashboards: %v", err)
+		errorObserved = true
+	}
+
+	/*********************
+	 * Provision the token of the Grafana service account
+	 **********************/
+	err = createGrafanaServiceAccountToken(ctx, c, vmo)
+	if err != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to provision the Grafana service account token: %v", err)
+		errorObserved = true
+	}
+
+	/*********************
+	 * Set the Degraded condition, if a resource quota rejected the resources of the VMI or their pods
+	 **********************/
+	err = checkPodQuotaExceeded(ctx, c, vmo)
+	if err != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to check the pods rejected by a resource quota: %v", err)
+		errorObserved = true
+	}
+	setDegradedCondition(c, vmo, syncStart)
+
 	/*********************
 	* Update VMO itself (if necessary, if anything has changed)
 	**********************/
 	specDiffs := diff.Diff(originalVMO, vmo)
 	if specDiffs != "" {
-		deleteISMChannel := c.osClient.DeleteDefaultISMPolicy(c.log, vmo)
-		c.log.Debugf("Acquired lock in namespace: %s", vmo.Namespace)
-		c.log.Debugf("VMO %s : Spec differences %s", vmo.Name, specDiffs)
-		c.log.Oncef("Updating VMO")
+		deleteISMChannel := skippedChannel()
+		if !openSearchPaused {
+			deleteISMChannel = osClient.DeleteDefaultISMPolicy(log, vmo)
+		}
+		log.Debugf("Acquired lock in namespace: %s", vmo.Namespace)
+		log.Debugf("VMO %s : Spec differences %s", vmo.Name, specDiffs)
+		log.Oncef("Updating VMO")
 		metric, err := metricsexporter.GetCounterMetrics(metricsexporter.NamesVMOUpdate)
 		if err != nil {
 			return err
 		}
 		metric.Inc()
-		_, err = c.vmoclientset.VerrazzanoV1().VerrazzanoMonitoringInstances(vmo.Namespace).Update(context.TODO(), vmo, metav1.UpdateOptions{})
+		_, err = c.vmoclientset.VerrazzanoV1().VerrazzanoMonitoringInstances(vmo.Namespace).Update(ctx, vmo, metav1.UpdateOptions{})
 		if err != nil {
-			c.log.Errorf("Failed to update status for VMI %s: %v", vmo.Name, err)
+			log.Errorf("Failed to update status for VMI %s: %v", vmo.Name, err)
 			errorObserved = true
+			statusErr = fmt.Errorf("%w %s: %v", errStatusUpdate, vmo.Name, err)
 		}
 		deleteISMPolicyError := <-deleteISMChannel
 		if deleteISMPolicyError != nil {
-			c.log.ErrorfThrottled("Failed to delete the default ISM policies: %v", deleteISMPolicyError)
+			log.ErrorfThrottled("Failed to delete the default ISM policies: %v", deleteISMPolicyError)
 			errorObserved = true
 		}
 	}
 
 	autoExpandIndexErr := <-autoExpandIndexChannel
 	if autoExpandIndexErr != nil {
-		c.lowFrequencyLog.ErrorfThrottled("Failed to update auto expand settings for indices: %v", autoExpandIndexErr)
+		lowFrequencyLog.ErrorfThrottled("Failed to update auto expand settings for indices: %v", autoExpandIndexErr)
 		errorObserved = true
 	}
 
 	ismErr := <-ismChannel
 	if ismErr != nil {
-		c.lowFrequencyLog.ErrorfThrottled("Failed to configure ISM Policies: %v", ismErr)
+		lowFrequencyLog.ErrorfThrottled("Failed to configure ISM Policies: %v", ismErr)
 		errorObserved = true
 	}
 
 	defaultISMErr := <-defaultISMChannel
 	if defaultISMErr != nil {
-		c.lowFrequencyLog.ErrorfThrottled("Failed to create or update default ISM Policies: %v", defaultISMErr)
+		lowFrequencyLog.ErrorfThrottled("Failed to create or update default ISM Policies: %v", defaultISMErr)
+		errorObserved = true
+	}
+
+	ingestPipelinesErr := <-ingestPipelinesChannel
+	if ingestPipelinesErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure ingest pipelines: %v", ingestPipelinesErr)
+		errorObserved = true
+	}
+
+	indexDefaultsErr := <-indexDefaultsChannel
+	if indexDefaultsErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure index defaults: %v", indexDefaultsErr)
+		errorObserved = true
+	}
+
+	indexSortErr := <-indexSortChannel
+	if indexSortErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure the default index sort: %v", indexSortErr)
+		errorObserved = true
+	}
+
+	componentTemplatesErr := <-componentTemplatesChannel
+	if componentTemplatesErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure component templates: %v", componentTemplatesErr)
+		errorObserved = true
+	}
+
+	searchBackpressureErr := <-searchBackpressureChannel
+	if searchBackpressureErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure search backpressure: %v", searchBackpressureErr)
+		errorObserved = true
+	}
+
+	autoCreateIndexErr := <-autoCreateIndexChannel
+	if autoCreateIndexErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure index auto creation: %v", autoCreateIndexErr)
+		errorObserved = true
+	}
+
+	allocationAwarenessErr := <-allocationAwarenessChannel
+	if allocationAwarenessErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure shard allocation awareness: %v", allocationAwarenessErr)
+		errorObserved = true
+	}
+	diskWatermarksErr := <-diskWatermarksChannel
+	if diskWatermarksErr != nil {
+		lowFrequencyLog.ErrorfThrottled("Failed to configure disk watermarks: %v", diskWatermarksErr)
 		errorObserved = true
 	}
 	/*********************
 	* Add default index patterns
 	**********************/
 	if vmo.Spec.OpensearchDashboards.Enabled && vmo.Spec.Opensearch.Enabled {
-		err = c.osDashboardsClient.CreateDefaultIndexPatterns(c.log, resources.GetOpenSearchDashboardsHTTPEndpoint(vmo))
+		err = c.osDashboardsClient.CreateDefaultIndexPatterns(log, resources.GetOpenSearchDashboardsHTTPEndpoint(vmo))
 		if err != nil {
-			c.log.ErrorfThrottled("Failed to add default index patterns : %v", err)
+			log.ErrorfThrottled("Failed to add default index patterns : %v", err)
 			errorObserved = true
 		}
 	}
@@ -626,11 +1016,11 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 		// into production) to know when a given vmo has been (mostly) updated, and thus when it's relatively safe to
 		// start checking various aspects of the vmo for health.
 		vmo.Spec.Versioning.CurrentVersion = c.buildVersion
-		_, err = c.vmoclientset.VerrazzanoV1().VerrazzanoMonitoringInstances(vmo.Namespace).Update(context.TODO(), vmo, metav1.UpdateOptions{})
+		_, err = c.vmoclientset.VerrazzanoV1().VerrazzanoMonitoringInstances(vmo.Namespace).Update(ctx, vmo, metav1.UpdateOptions{})
 		if err != nil {
-			c.log.Errorf("Failed to update currentVersion for VMI %s: %v", vmo.Name, err)
+			log.Errorf("Failed to update currentVersion for VMI %s: %v", vmo.Name, err)
 		} else {
-			c.log.Oncef("Updated VMI currentVersion to %s", c.buildVersion)
+			log.Oncef("Updated VMI currentVersion to %s", c.buildVersion)
 			timeMetric, timeErr := metricsexporter.GetTimestampMetrics(metricsexporter.NamesVMOUpdate)
 			if timeErr != nil {
 				return timeErr
@@ -642,13 +1032,19 @@ func (c *Controller) syncHandlerStandardMode(vmo *vmcontrollerv1.VerrazzanoMonit
 	// Create a Hash on vmo/Status object to identify changes to vmo spec
 	hash, err := vmo.Hash()
 	if err != nil {
-		c.log.Errorf("Error getting VMO hash: %v", err)
+		log.Errorf("Error getting VMO hash: %v", err)
 	}
 	if vmo.Status.Hash != hash {
 		vmo.Status.Hash = hash
 	}
 
-	c.log.Oncef("Successfully synced VMI'%s/%s'", vmo.Namespace, vmo.Name)
+	if statusErr != nil {
+		return statusErr
+	}
+	if waitErr != nil {
+		return waitErr
+	}
+	log.Oncef("Successfully synced VMI'%s/%s'", vmo.Namespace, vmo.Name)
 	return nil
 }
 
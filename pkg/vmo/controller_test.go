@@ -0,0 +1,231 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	vmofake "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/informers/externalversions"
+	listers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/listers/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// blockingVMOLister is a VMI lister whose Get blocks until released, recording the number of concurrent calls
+type blockingVMOLister struct {
+	mutex         sync.Mutex
+	concurrent    int
+	maxConcurrent int
+	release       chan struct{}
+}
+
+func (l *blockingVMOLister) List(_ labels.Selector) ([]*vmcontrollerv1.VerrazzanoMonitoringInstance, error) {
+	return nil, nil
+}
+
+func (l *blockingVMOLister) VerrazzanoMonitoringInstances(_ string) listers.VerrazzanoMonitoringInstanceNamespaceLister {
+	return l
+}
+
+func (l *blockingVMOLister) Get(name string) (*vmcontrollerv1.VerrazzanoMonitoringInstance, error) {
+	l.mutex.Lock()
+	l.concurrent++
+	if l.concurrent > l.maxConcurrent {
+		l.maxConcurrent = l.concurrent
+	}
+	l.mutex.Unlock()
+	<-l.release
+	l.mutex.Lock()
+	l.concurrent--
+	l.mutex.Unlock()
+	return nil, k8serrors.NewNotFound(vmcontrollerv1.Resource("verrazzanomonitoringinstance"), name)
+}
+
+// counts returns the current and the maximum number of concurrent calls
+func (l *blockingVMOLister) counts() (int, int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.concurrent, l.maxConcurrent
+}
+
+// createRunTestController creates a controller whose informers are synced, and whose VMI lister blocks
+func createRunTestController() (*Controller, *blockingVMOLister, chan struct{}) {
+	controller, _ := createControllerForTesting()
+	synced := func() bool { return true }
+	controller.clusterRolesSynced = synced
+	controller.configMapsSynced = synced
+	controller.deploymentsSynced = synced
+	controller.ingressesSynced = synced
+	controller.networkPoliciesSynced = synced
+	controller.nodesSynced = synced
+	controller.pvcsSynced = synced
+	controller.roleBindingsSynced = synced
+	controller.secretsSynced = synced
+	controller.servicesSynced = synced
+	controller.statefulSetsSynced = synced
+	controller.vmosSynced = synced
+	controller.storageClassesSynced = synced
+	stopCh := make(chan struct{})
+	controller.stopCh = stopCh
+	lister := &blockingVMOLister{release: make(chan struct{})}
+	controller.vmoLister = lister
+	return controller, lister, stopCh
+}
+
+// TestRunWorkers Tests that the controller runs the given number of workers
+// GIVEN a controller and more queued VMIs than workers
+// WHEN I run the controller with 3 workers
+// THEN 3 VMIs are reconciled concurrently, and never more
+func TestRunWorkers(t *testing.T) {
+	controller, lister, stopCh := createRunTestController()
+	for i := 0; i < 5; i++ {
+		controller.workqueue.Add(fmt.Sprintf("%s/vmi-%d", constants.VerrazzanoSystemNamespace, i))
+	}
+
+	done := make(chan error)
+	go func() { done <- controller.Run(3) }()
+	assert.Eventually(t, func() bool {
+		concurrent, _ := lister.counts()
+		return concurrent == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	_, maxConcurrent := lister.counts()
+	assert.Equal(t, 3, maxConcurrent)
+
+	close(lister.release)
+	close(stopCh)
+	assert.NoError(t, <-done)
+}
+
+// TestRunInvalidWorkers Tests that the controller does not run without workers
+// GIVEN a controller
+// WHEN I run the controller with less than 1 worker
+// THEN an error is returned
+func TestRunInvalidWorkers(t *testing.T) {
+	controller, _, stopCh := createRunTestController()
+	defer close(stopCh)
+	assert.Error(t, controller.Run(0))
+	assert.Error(t, controller.Run(-1))
+}
+
+// TestSyncHandlerMaxReconcileDurationExceeded Tests that a reconcile exceeding the maximum reconcile duration is cancelled
+// GIVEN a VMI and a maximum reconcile duration shorter than the reconcile
+// WHEN the VMI is processed by a worker
+// THEN the reconcile is cancelled with a deadline exceeded error, and the VMI is requeued
+func TestSyncHandlerMaxReconcileDurationExceeded(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	informer := informers.NewSharedInformerFactory(vmofake.NewSimpleClientset(), constants.ResyncPeriod).Verrazzano().V1().VerrazzanoMonitoringInstances()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(vmo))
+	controller.vmoLister = informer.Lister()
+	maxReconcileDuration := time.Nanosecond
+	controller.operatorConfig.MaxReconcileDuration = &maxReconcileDuration
+	key := vmo.Namespace + "/" + vmo.Name
+
+	err := controller.syncHandler(key)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	controller.workqueue.Add(key)
+	assert.True(t, controller.processNextWorkItem())
+	assert.Eventually(t, func() bool {
+		return controller.workqueue.Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// TestSyncHandlerWithinMaxReconcileDuration Tests that a reconcile within the maximum reconcile duration is not cancelled
+// GIVEN a VMI and a maximum reconcile duration longer than the reconcile
+// WHEN the VMI is synced
+// THEN the reconcile completes without error
+func TestSyncHandlerWithinMaxReconcileDuration(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	informer := informers.NewSharedInformerFactory(vmofake.NewSimpleClientset(), constants.ResyncPeriod).Verrazzano().V1().VerrazzanoMonitoringInstances()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(vmo))
+	controller.vmoLister = informer.Lister()
+	// the VMI must exist to be updated with its status
+	controller.vmoclientset = vmofake.NewSimpleClientset(vmo.DeepCopy())
+	maxReconcileDuration := time.Minute
+	controller.operatorConfig.MaxReconcileDuration = &maxReconcileDuration
+
+	assert.NoError(t, controller.syncHandler(vmo.Namespace+"/"+vmo.Name))
+}
+
+// TestSyncHandlerConcurrentWorkers Tests that the workers reconciling different VMIs do not share their loggers
+// GIVEN two queued VMIs
+// WHEN the VMIs are processed by 2 workers
+// THEN both VMIs are reconciled concurrently, without data race when run with -race
+func TestSyncHandlerConcurrentWorkers(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	informer := informers.NewSharedInformerFactory(vmofake.NewSimpleClientset(), constants.ResyncPeriod).Verrazzano().V1().VerrazzanoMonitoringInstances()
+	for i := 0; i < 2; i++ {
+		vmi := vmo.DeepCopy()
+		vmi.Name = fmt.Sprintf("vmi-%d", i)
+		vmi.UID = types.UID(vmi.Name)
+		assert.NoError(t, informer.Informer().GetIndexer().Add(vmi))
+		controller.workqueue.Add(vmi.Namespace + "/" + vmi.Name)
+	}
+	controller.vmoLister = informer.Lister()
+	maxReconcileDuration := time.Minute
+	controller.operatorConfig.MaxReconcileDuration = &maxReconcileDuration
+
+	// the workers stop once the queued VMIs are processed
+	controller.workqueue.ShutDown()
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			controller.runWorker()
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 0, controller.workqueue.Len())
+}
+
+// TestHandleSyncErrorWaiting Tests that a reconcile waiting for a temporary condition is requeued without an error
+// GIVEN a reconcile which failed with a waiting error, wrapped or not
+// WHEN the error is handled
+// THEN the VMI is requeued with a growing back-off and no error is reported
+func TestHandleSyncErrorWaiting(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	key := vmo.Namespace + "/" + vmo.Name
+	waitErr := newWaitingError("waiting to bring OS Dashboards replica up to full count")
+
+	assert.NoError(t, controller.handleSyncError(key, waitErr))
+	assert.NoError(t, controller.handleSyncError(key, fmt.Errorf("failed to create deployments: %w", waitErr)))
+	assert.Equal(t, 2, controller.workqueue.NumRequeues(key))
+}
+
+// TestHandleSyncErrorFailed Tests that a failed reconcile is reported as an error
+// GIVEN a reconcile which failed with an error other than a waiting error
+// WHEN the error is handled
+// THEN the error is reported and the VMI is not requeued
+func TestHandleSyncErrorFailed(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	key := vmo.Namespace + "/" + vmo.Name
+
+	assert.ErrorContains(t, controller.handleSyncError(key, fmt.Errorf("failed")), "error syncing")
+	assert.Equal(t, 0, controller.workqueue.NumRequeues(key))
+}
+
+// TestHandleSyncErrorStatusUpdate Tests that a reconcile which could not update the VMI status is requeued
+// GIVEN a VMI which does not exist in the API server, so its status update fails
+// WHEN the VMI is reconciled and the error is handled
+// THEN the status update error is returned, reported and the VMI is requeued with a back-off
+func TestHandleSyncErrorStatusUpdate(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	key := vmo.Namespace + "/" + vmo.Name
+
+	err := controller.syncHandlerStandardMode(context.TODO(), vmo)
+	assert.ErrorIs(t, err, errStatusUpdate)
+	assert.ErrorContains(t, controller.handleSyncError(key, err), "error syncing")
+	assert.Equal(t, 1, controller.workqueue.NumRequeues(key))
+}
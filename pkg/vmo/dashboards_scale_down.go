@@ -0,0 +1,23 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import "time"
+
+// dashboardsScaleDownTracker tracks when the OpenSearch Dashboards deployments were requested to scale to zero replicas,
+// so that their last replica is only removed after the scale down grace period. The zero value is ready to use.
+type dashboardsScaleDownTracker struct {
+	keyedTracker[time.Time]
+}
+
+// requestedAt returns the time the deployment was first requested to scale to zero replicas, recording the given time
+// if this is the first request
+func (t *dashboardsScaleDownTracker) requestedAt(key string, now time.Time) time.Time {
+	return t.modify(key, func(requestedAt time.Time, ok bool) (time.Time, bool) {
+		if !ok {
+			requestedAt = now
+		}
+		return requestedAt, true
+	})
+}
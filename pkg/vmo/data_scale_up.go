@@ -0,0 +1,114 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// dataScaleUpTracker tracks the VMIs whose OpenSearch data nodes were scaled up, until the shards are relocated.
+// The zero value is ready to use.
+type dataScaleUpTracker struct {
+	keyedTracker[struct{}]
+}
+
+// start records that data nodes were added to the VMI
+func (t *dataScaleUpTracker) start(key string) {
+	t.set(key, struct{}{})
+}
+
+// isPending returns true if data nodes were added to the VMI and the shards are not relocated yet
+func (t *dataScaleUpTracker) isPending(key string) bool {
+	_, ok := t.get(key)
+	return ok
+}
+
+// done records that the shards of the VMI are relocated
+func (t *dataScaleUpTracker) done(key string) {
+	t.reset(key)
+}
+
+// validateDataScaleUp validates that the OpenSearch cluster can allocate shards to the given new data node deployments,
+// unless the validation is disabled. Returns true if the new data nodes scale up the cluster: creating the data nodes
+// during the initial bring-up of a cluster is not a scale up and is not validated, as the cluster cannot be healthy
+// before all its nodes are created.
+func validateDataScaleUp(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, newDataDeployments []*appsv1.Deployment, existingCluster bool) (bool, error) {
+	if len(newDataDeployments) == 0 || !existingCluster {
+		return false, nil
+	}
+	existingDeployments, err := controller.deploymentLister.Deployments(vmo.Namespace).List(labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name}))
+	if err != nil {
+		return false, err
+	}
+	existingDataNodes := false
+	for _, deployment := range existingDeployments {
+		if deployments.IsOpenSearchDataDeployment(vmo.Name, deployment) {
+			existingDataNodes = true
+		}
+	}
+	if !existingDataNodes {
+		return false, nil
+	}
+	if controller.operatorConfig.DisableDataScaleUpValidation {
+		return true, nil
+	}
+	var minCapacity int64
+	for _, deployment := range newDataDeployments {
+		capacity, err := getDataDeploymentCapacity(controller, vmo, deployment)
+		if err != nil {
+			return true, err
+		}
+		if minCapacity == 0 || (capacity > 0 && capacity < minCapacity) {
+			minCapacity = capacity
+		}
+	}
+	if err := controller.osClient.WithContext(ctx).ValidateDataScaleUp(vmo, len(newDataDeployments), minCapacity); err != nil {
+		return true, fmt.Errorf("scale up of OpenSearch data nodes not allowed: %v", err)
+	}
+	return true, nil
+}
+
+// getDataDeploymentCapacity returns the storage requested by the PVC of a data node deployment, zero if it has no PVC.
+// Returns a waiting error if the PVC is not known yet, the disk usage of the new data node cannot be validated without it.
+func getDataDeploymentCapacity(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) (int64, error) {
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvc, err := controller.pvcLister.PersistentVolumeClaims(vmo.Namespace).Get(volume.PersistentVolumeClaim.ClaimName)
+		if k8serrors.IsNotFound(err) {
+			return 0, newWaitingError(fmt.Sprintf("PVC %s of OpenSearch data deployment %s not found", volume.PersistentVolumeClaim.ClaimName, deployment.Name))
+		}
+		if err != nil {
+			return 0, err
+		}
+		storage := pvc.Spec.Resources.Requests[corev1.ResourceStorage]
+		return storage.Value(), nil
+	}
+	return 0, nil
+}
+
+// verifyDataShardsRelocated logs whether the shards were relocated to the data nodes added to the VMI
+func verifyDataShardsRelocated(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	log := reconcileLog(ctx)
+	key := vmo.Namespace + "/" + vmo.Name
+	if !controller.dataScaleUps.isPending(key) {
+		return
+	}
+	if err := controller.osClient.WithContext(ctx).VerifyDataShardsRelocated(vmo); err != nil {
+		log.Progressf("Waiting for shards to relocate to the new OpenSearch data nodes of VMI %s: %v", vmo.Name, err)
+		return
+	}
+	log.Oncef("Shards have relocated to the new OpenSearch data nodes of VMI %s", vmo.Name)
+	controller.dataScaleUps.done(key)
+}
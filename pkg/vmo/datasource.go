@@ -0,0 +1,120 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// prometheusDatasourceMismatchReason is the reason of the Warning event recorded when the Prometheus datasource of the
+// Grafana datasources configmap does not point to the expected Prometheus service
+const prometheusDatasourceMismatchReason = "PrometheusDatasourceMismatch"
+
+// vmoPrometheusDatasourceName is the name of the Prometheus datasource provisioned by the VMO in the Grafana datasources
+// configmap. The other datasources are the ones of the users, which may point to any Prometheus service
+const vmoPrometheusDatasourceName = "Prometheus"
+
+// EnableGrafanaDatasourceCorrection makes the controller rewrite the Prometheus datasource of the Grafana datasources
+// configmaps which does not point to the expected Prometheus service, instead of only warning
+func (c *Controller) EnableGrafanaDatasourceCorrection() {
+	c.correctGrafanaDatasources = true
+}
+
+// expectedPrometheusURL returns the URL of the Prometheus service the Grafana Prometheus datasource must point to
+func expectedPrometheusURL() string {
+	return fmt.Sprintf("http://%s:%d", prometheusOperatorPrometheusHost, config.Prometheus.Port)
+}
+
+// checkPrometheusDatasources records a Warning event if the Prometheus datasource provisioned by the VMO in the given
+// Grafana datasources provisioning file does not point to the expected Prometheus service. If the datasource
+// correction is enabled, the returned datasources have the VMO datasource point to the expected Prometheus service,
+// otherwise they are returned as is. The other datasources are never changed. The event is only recorded when the
+// mismatch of the configmap changes.
+func checkPrometheusDatasources(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, configmapName, datasources string) string {
+	log := reconcileLog(ctx)
+	var document yaml.Node
+	if err := yaml.Unmarshal([]byte(datasources), &document); err != nil {
+		log.Infof("Failed to parse the datasources of configmap %s/%s: %v", vmo.Namespace, configmapName, err)
+		return datasources
+	}
+	key := vmo.Namespace + "/" + vmo.Name + "/" + configmapName
+	datasource := findVMOPrometheusDatasource(&document)
+	if datasource == nil {
+		controller.prometheusDatasourceWarnings.update(key, "")
+		return datasources
+	}
+	var url string
+	urlNode := yamlMappingValue(datasource, "url")
+	if urlNode != nil {
+		url = urlNode.Value
+	}
+	expectedURL := expectedPrometheusURL()
+	if url == expectedURL {
+		controller.prometheusDatasourceWarnings.update(key, "")
+		return datasources
+	}
+	message := fmt.Sprintf("Grafana datasource %s of configmap %s/%s points to %s instead of %s", vmoPrometheusDatasourceName, vmo.Namespace, configmapName, url, expectedURL)
+	if controller.prometheusDatasourceWarnings.update(key, message) && controller.recorder != nil {
+		controller.recorder.Event(vmo, corev1.EventTypeWarning, prometheusDatasourceMismatchReason, message)
+	}
+	if !controller.correctGrafanaDatasources || url == "" {
+		log.Oncef("%s", message)
+		return datasources
+	}
+	log.Infof("%s, correcting it", message)
+	urlNode.Value = expectedURL
+	var corrected strings.Builder
+	encoder := yaml.NewEncoder(&corrected)
+	encoder.SetIndent(2)
+	err := encoder.Encode(&document)
+	if err == nil {
+		err = encoder.Close()
+	}
+	if err != nil {
+		log.Infof("Failed to correct the datasources of configmap %s/%s: %v", vmo.Namespace, configmapName, err)
+		return datasources
+	}
+	return corrected.String()
+}
+
+// findVMOPrometheusDatasource returns the Prometheus datasource provisioned by the VMO in the parsed datasources
+// provisioning file, or nil if there is none
+func findVMOPrometheusDatasource(document *yaml.Node) *yaml.Node {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+	datasources := yamlMappingValue(document.Content[0], "datasources")
+	if datasources == nil || datasources.Kind != yaml.SequenceNode {
+		return nil
+	}
+	for _, datasource := range datasources.Content {
+		name := yamlMappingValue(datasource, "name")
+		datasourceType := yamlMappingValue(datasource, "type")
+		if name != nil && name.Value == vmoPrometheusDatasourceName && datasourceType != nil && datasourceType.Value == "prometheus" {
+			return datasource
+		}
+	}
+	return nil
+}
+
+// yamlMappingValue returns the value of the key of a parsed YAML mapping, or nil if the node is not a mapping or does
+// not have the key
+func yamlMappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmctl "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/configmaps"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+// createDatasourceTestController creates a controller whose client holds a Grafana datasources configmap with a
+// Prometheus datasource pointing to the given Prometheus host
+func createDatasourceTestController(t *testing.T, configMapName, prometheusHost string) (*Controller, *vmctl.VerrazzanoMonitoringInstance, *fake.Clientset, *record.FakeRecorder) {
+	vmo := &vmctl.VerrazzanoMonitoringInstance{}
+	vmo.Name = constants.VMODefaultName
+	vmo.Namespace = constants.VerrazzanoSystemNamespace
+	dataSourceTemplate, err := asDashboardTemplate(constants.DataSourcesTmpl, map[string]string{
+		constants.GrafanaTmplPrometheusURI:   prometheusHost,
+		constants.GrafanaTmplAlertManagerURI: "",
+	})
+	assert.NoError(t, err)
+	client := fake.NewSimpleClientset(configmaps.NewConfig(vmo, configMapName, map[string]string{datasourceYAMLKey: dataSourceTemplate}))
+	recorder := record.NewFakeRecorder(10)
+	controller := &Controller{
+		kubeclientset:   client,
+		configMapLister: &simpleConfigMapLister{kubeClient: client},
+		log:             vzlog.DefaultLogger(),
+		recorder:        recorder,
+	}
+	return controller, vmo, client, recorder
+}
+
+// TestPrometheusDatasourceMismatch Tests detecting a Grafana Prometheus datasource pointing to another Prometheus service
+// GIVEN a Grafana datasources configmap whose Prometheus datasource points to another Prometheus service
+// WHEN I call createUpdateDatasourcesConfigMap twice
+// THEN a single Warning event naming the expected Prometheus URL is recorded, and the configmap is left as is
+func TestPrometheusDatasourceMismatch(t *testing.T) {
+	const configMapName = "myDatasourcesConfigMap"
+	controller, vmo, client, recorder := createDatasourceTestController(t, configMapName, "prometheus.monitoring")
+
+	assert.NoError(t, createUpdateDatasourcesConfigMap(context.TODO(), controller, vmo, configMapName, map[string]string{}))
+	assert.NoError(t, createUpdateDatasourcesConfigMap(context.TODO(), controller, vmo, configMapName, map[string]string{}))
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "Warning "+prometheusDatasourceMismatchReason)
+	assert.Contains(t, event, "points to http://prometheus.monitoring:9090 instead of "+expectedPrometheusURL())
+	cm, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, cm.Data[datasourceYAMLKey], "url: http://prometheus.monitoring:9090")
+}
+
+// TestPrometheusDatasourceCorrection Tests correcting a Grafana Prometheus datasource pointing to another Prometheus service
+// GIVEN a Grafana datasources configmap whose Prometheus datasource points to another Prometheus service, and the
+// datasource correction enabled
+// WHEN I call createUpdateDatasourcesConfigMap
+// THEN a Warning event is recorded, and the Prometheus datasource of the configmap points to the expected Prometheus URL
+func TestPrometheusDatasourceCorrection(t *testing.T) {
+	const configMapName = "myDatasourcesConfigMap"
+	controller, vmo, client, recorder := createDatasourceTestController(t, configMapName, "prometheus.monitoring")
+	controller.EnableGrafanaDatasourceCorrection()
+
+	assert.NoError(t, createUpdateDatasourcesConfigMap(context.TODO(), controller, vmo, configMapName, map[string]string{}))
+	assert.Len(t, recorder.Events, 1)
+	cm, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, cm.Data[datasourceYAMLKey], "url: "+expectedPrometheusURL())
+	assert.NotContains(t, cm.Data[datasourceYAMLKey], "prometheus.monitoring")
+}
+
+// TestPrometheusDatasourceMatch Tests that no warning is recorded for a Grafana Prometheus datasource pointing to the
+// expected Prometheus service
+// GIVEN a Grafana datasources configmap whose Prometheus datasource points to the expected Prometheus service
+// WHEN I call createUpdateDatasourcesConfigMap
+// THEN no event is recorded
+func TestPrometheusDatasourceMatch(t *testing.T) {
+	const configMapName = "myDatasourcesConfigMap"
+	controller, vmo, _, recorder := createDatasourceTestController(t, configMapName, prometheusOperatorPrometheusHost)
+
+	assert.NoError(t, createUpdateDatasourcesConfigMap(context.TODO(), controller, vmo, configMapName, map[string]string{}))
+	assert.Len(t, recorder.Events, 0)
+}
+
+// TestPrometheusDatasourceCorrectionUserDatasources Tests that only the Prometheus datasource provisioned by the VMO
+// is checked and corrected
+// GIVEN a Grafana datasources configmap whose VMO Prometheus datasource and a user Prometheus datasource point to
+// another Prometheus service, and the datasource correction enabled
+// WHEN I call createUpdateDatasourcesConfigMap
+// THEN a single Warning event is recorded for the VMO datasource, which is corrected, and the user datasource is left as is
+func TestPrometheusDatasourceCorrectionUserDatasources(t *testing.T) {
+	const configMapName = "myDatasourcesConfigMap"
+	controller, vmo, client, recorder := createDatasourceTestController(t, configMapName, "prometheus.monitoring")
+	controller.EnableGrafanaDatasourceCorrection()
+	cm, err := client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	cm.Data[datasourceYAMLKey] += `
+- name: Thanos
+  type: prometheus
+  url: http://prometheus.monitoring:9090`
+	_, err = client.CoreV1().ConfigMaps(vmo.Namespace).Update(context.TODO(), cm, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	assert.NoError(t, createUpdateDatasourcesConfigMap(context.TODO(), controller, vmo, configMapName, map[string]string{}))
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "Grafana datasource Prometheus of configmap")
+	cm, err = client.CoreV1().ConfigMaps(vmo.Namespace).Get(context.TODO(), configMapName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Contains(t, cm.Data[datasourceYAMLKey], `- name: Prometheus
+    type: prometheus
+    orgId: 1
+    access: proxy
+    url: `+expectedPrometheusURL())
+	assert.Contains(t, cm.Data[datasourceYAMLKey], `- name: Thanos
+    type: prometheus
+    url: http://prometheus.monitoring:9090`)
+}
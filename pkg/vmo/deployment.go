@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/verrazzano/pkg/diff"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
@@ -16,74 +17,106 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
 	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 )
 
-func updateOpenSearchDashboardsDeployment(osd *appsv1.Deployment, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func updateOpenSearchDashboardsDeployment(ctx context.Context, osd *appsv1.Deployment, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 	if osd == nil {
 		return nil
 	}
 	var err error
 
 	// Wait for OS to be green before deploying OS Dashboards
-	if err = controller.osClient.IsGreen(vmo); err != nil {
+	if err = controller.osClient.WithContext(ctx).IsGreen(vmo); err != nil {
 		return err
 	}
 
+	replicas := resources.GetOpenSearchDashboardsReplicas(vmo)
 	existingDeployment, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(osd.Name)
 	if err != nil {
 		if k8serrors.IsNotFound(err) {
-			controller.log.Oncef("Creating deployment %s/%s", osd.Namespace, osd.Name)
-			// Initialize the replica count to one, and scale up one at a time during update.
+			log.Oncef("Creating deployment %s/%s", osd.Namespace, osd.Name)
+			// Initialize the replica count to one, unless scaled to zero, and scale up one at a time during update.
 			// The OS Dashboard pods are being rolled out one at a time to avoid getting failures
 			// due to indices needing to be migrated.  We considered using StatefulSets with a
 			// pod management policy of "ordered ready".  However, StatefulSets do not support a
 			// deployment strategy of "recreate", which is also needed to avoid the migrating indices error.
-			osd.Spec.Replicas = resources.NewVal(int32(1))
-			_, err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Create(context.TODO(), osd, metav1.CreateOptions{})
+			if replicas > 0 {
+				osd.Spec.Replicas = resources.NewVal(int32(1))
+			}
+			err = createDeployment(ctx, controller, vmo, osd)
 		} else {
 			return err
 		}
 	} else {
-		if err = controller.osClient.IsUpdated(vmo); err != nil {
+		if err = controller.osClient.WithContext(ctx).IsUpdated(vmo); err != nil {
 			return err
 		}
-		if existingDeployment.Status.AvailableReplicas == *existingDeployment.Spec.Replicas &&
-			*resources.NewVal(vmo.Spec.OpensearchDashboards.Replicas) > *existingDeployment.Spec.Replicas {
-			// Ok to scale up
-			*osd.Spec.Replicas = *existingDeployment.Spec.Replicas + 1
-			controller.log.Oncef("Incrementing replica count of deployment %s/%s to %d", osd.Namespace, osd.Name, *osd.Spec.Replicas)
-		}
-		if err = updateDeployment(controller, vmo, existingDeployment, osd); err == nil {
-			// Return a temporary error if not finished scaling up to the desired replica count
-			if *resources.NewVal(vmo.Spec.OpensearchDashboards.Replicas) != *existingDeployment.Spec.Replicas {
-				return fmt.Errorf("waiting to bring OS Dashboards replica up to full count")
+		if replicas == 0 && *existingDeployment.Spec.Replicas > 0 {
+			var draining bool
+			if draining, err = scaleOpenSearchDashboardsToZero(ctx, controller, vmo, existingDeployment, osd); err == nil && draining {
+				return newWaitingError("waiting for OS Dashboards requests to drain before scaling to zero replicas")
+			}
+		} else {
+			controller.dashboardsScaleDowns.reset(osd.Namespace + "/" + osd.Name)
+			if existingDeployment.Status.AvailableReplicas == *existingDeployment.Spec.Replicas &&
+				replicas > *existingDeployment.Spec.Replicas {
+				// Ok to scale up
+				*osd.Spec.Replicas = *existingDeployment.Spec.Replicas + 1
+				log.Oncef("Incrementing replica count of deployment %s/%s to %d", osd.Namespace, osd.Name, *osd.Spec.Replicas)
+			}
+			if err = updateDeployment(ctx, controller, vmo, existingDeployment, osd); err == nil {
+				// Wait for the next replica if not finished scaling up to the desired replica count
+				if replicas != *existingDeployment.Spec.Replicas {
+					return newWaitingError("waiting to bring OS Dashboards replica up to full count")
+				}
 			}
 		}
 	}
 	if err != nil {
 		if metric, metricErr := metricsexporter.GetErrorMetrics(metricsexporter.NamesDeploymentUpdateError); metricErr != nil {
-			controller.log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
+			log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
 		} else {
 			metric.Inc()
 		}
-		controller.log.Errorf("Failed to update deployment %s/%s: %v", osd.Namespace, osd.Name, err)
+		log.Errorf("Failed to update deployment %s/%s: %v", osd.Namespace, osd.Name, err)
 		return err
 	}
 
 	return nil
 }
 
+// scaleOpenSearchDashboardsToZero scales the OpenSearch Dashboards deployment to zero replicas, which disables
+// OpenSearch Dashboards. The last replica is kept until the scale down grace period has elapsed, so that in-flight
+// requests, e.g. a migration of the OpenSearch Dashboards index, are drained. Returns true while the last replica is kept.
+func scaleOpenSearchDashboardsToZero(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingDeployment, osd *appsv1.Deployment) (bool, error) {
+	log := reconcileLog(ctx)
+	requestedAt := controller.dashboardsScaleDowns.requestedAt(osd.Namespace+"/"+osd.Name, time.Now())
+	if time.Since(requestedAt) < *controller.operatorConfig.DashboardsScaleDownGracePeriod {
+		*osd.Spec.Replicas = 1
+		return true, updateDeployment(ctx, controller, vmo, existingDeployment, osd)
+	}
+	// The deployment is updated directly, as zero replicas are not a spec difference
+	log.Oncef("Scaling deployment %s/%s to zero replicas, OpenSearch Dashboards is disabled", osd.Namespace, osd.Name)
+	*osd.Spec.Replicas = 0
+	osd.Spec.Selector = existingDeployment.Spec.Selector
+	_, err := controller.kubeclientset.AppsV1().Deployments(osd.Namespace).Update(ctx, osd, metav1.UpdateOptions{})
+	return false, err
+}
+
 // CreateDeployments create/update VMO deployment k8s resources
-func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, pvcToAdMap map[string]string, existingCluster bool) (dirty bool, err error) {
+func CreateDeployments(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, pvcToAdMap map[string]string, existingCluster bool) (dirty bool, err error) {
+	log := reconcileLog(ctx)
 	// The error count is incremented by the function which calls createDeployment
 	functionMetric, functionError := metricsexporter.GetFunctionMetrics(metricsexporter.NamesDeployment)
 	if functionError == nil {
-		functionMetric.LogStart()
-		defer functionMetric.LogEnd(false)
+		timer := functionMetric.LogStart()
+		defer functionMetric.LogEnd(timer, false)
 	} else {
 		return false, functionError
 	}
@@ -94,14 +127,37 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 
 	expected, err := deployments.New(vmo, controller.kubeclientset, controller.operatorConfig, pvcToAdMap)
 	if err != nil {
-		controller.log.Errorf("Failed to create Deployment specs for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to create Deployment specs for VMI %s: %v", vmo.Name, err)
 		return false, err
 	}
 	deployList := expected.Deployments
 
+	// Report the data deployments whose PVC drifted from the PVCs of the VMI, they are realigned by the rolling update
+	if !resources.IsOpenSearchPaused(vmo) {
+		if err := checkDataDeploymentPVCs(ctx, controller, vmo, deployList); err != nil {
+			log.Errorf("Failed to check the PVCs of the OpenSearch data deployments of VMI %s: %v", vmo.Name, err)
+			return false, err
+		}
+	}
+
+	// Validate that the cluster can allocate shards to the data nodes being added
+	var newDataDeployments []*appsv1.Deployment
+	for _, curDeployment := range deployList {
+		if !deployments.IsOpenSearchDataDeployment(vmo.Name, curDeployment) || resources.IsOpenSearchPaused(vmo) {
+			continue
+		}
+		if _, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(curDeployment.Name); k8serrors.IsNotFound(err) {
+			newDataDeployments = append(newDataDeployments, curDeployment)
+		}
+	}
+	isScaleUp, scaleUpErr := validateDataScaleUp(ctx, controller, vmo, newDataDeployments, existingCluster)
+	if scaleUpErr != nil {
+		log.Oncef("Not creating the new OpenSearch data deployments of VMI %s: %v", vmo.Name, scaleUpErr)
+	}
+
 	var openSearchDeployments []*appsv1.Deployment
 	var deploymentNames []string
-	controller.log.Oncef("Creating/updating ExpectedDeployments for VMI %s", vmo.Name)
+	log.Oncef("Creating/updating ExpectedDeployments for VMI %s", vmo.Name)
 	for _, curDeployment := range deployList {
 		deploymentName := curDeployment.Name
 		deploymentNames = append(deploymentNames, deploymentName)
@@ -112,12 +168,19 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 			runtime.HandleError(errors.New("deployment name must be specified"))
 			return true, nil
 		}
-		controller.log.Debugf("Applying Deployment '%s' in namespace '%s' for VMI '%s'\n", deploymentName, vmo.Namespace, vmo.Name)
+		if resources.IsOpenSearchPaused(vmo) && deployments.IsOpenSearchDeployment(vmo.Name, curDeployment) {
+			log.Debugf("Skipping Deployment '%s' in namespace '%s' for VMI '%s', OpenSearch is paused\n", deploymentName, vmo.Namespace, vmo.Name)
+			continue
+		}
+		log.Debugf("Applying Deployment '%s' in namespace '%s' for VMI '%s'\n", deploymentName, vmo.Namespace, vmo.Name)
 		existingDeployment, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(deploymentName)
 
 		if err != nil {
 			if k8serrors.IsNotFound(err) {
-				_, err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Create(context.TODO(), curDeployment, metav1.CreateOptions{})
+				if scaleUpErr != nil && deployments.IsOpenSearchDataDeployment(vmo.Name, curDeployment) {
+					continue
+				}
+				err = createDeployment(ctx, controller, vmo, curDeployment)
 			} else {
 				return false, err
 			}
@@ -125,37 +188,47 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 			if existingDeployment.Spec.Template.Labels[constants.ServiceAppLabel] == fmt.Sprintf("%s-%s", vmo.Name, config.ElasticsearchData.Name) {
 				openSearchDeployments = append(openSearchDeployments, curDeployment)
 			} else {
-				err = updateDeployment(controller, vmo, existingDeployment, curDeployment)
+				err = updateDeployment(ctx, controller, vmo, existingDeployment, curDeployment)
 			}
 		}
 		if err != nil {
 			if metric, metricErr := metricsexporter.GetErrorMetrics(metricsexporter.NamesDeploymentUpdateError); metricErr != nil {
-				controller.log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
+				log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
 			} else {
 				metric.Inc()
 			}
-			controller.log.Errorf("Failed to update deployment %s/%s: %v", curDeployment.Namespace, curDeployment.Name, err)
+			log.Errorf("Failed to update deployment %s/%s: %v", curDeployment.Namespace, curDeployment.Name, err)
 			return false, err
 		}
 	}
 
-	openSearchDirty, err := updateOpenSearchDeployments(controller, vmo, openSearchDeployments, existingCluster)
+	// Once the new data nodes are created, verify that the shards relocate to them
+	if isScaleUp && scaleUpErr == nil {
+		controller.dataScaleUps.start(vmo.Namespace + "/" + vmo.Name)
+	} else if len(newDataDeployments) == 0 {
+		verifyDataShardsRelocated(ctx, controller, vmo)
+	}
+
+	openSearchDirty, err := updateOpenSearchDeployments(ctx, controller, vmo, openSearchDeployments, existingCluster)
 	if err != nil {
 		return false, err
 	}
 
-	// Create the OSD deployment
-	osd := deployments.NewOpenSearchDashboardsDeployment(vmo)
-	if osd != nil {
-		deploymentNames = append(deploymentNames, osd.Name)
-		err = updateOpenSearchDashboardsDeployment(osd, controller, vmo)
-		if err != nil {
-			return false, err
+	// Create the OSD deployment, unless it is managed outside of the VMO
+	osdManaged := resources.IsOpenSearchDashboardsManaged(vmo)
+	if osdManaged {
+		osd := deployments.NewOpenSearchDashboardsDeployment(vmo)
+		if osd != nil {
+			deploymentNames = append(deploymentNames, osd.Name)
+			err = updateOpenSearchDashboardsDeployment(ctx, osd, controller, vmo)
+			if err != nil {
+				return false, err
+			}
 		}
 	}
 
 	// Delete deployments that shouldn't exist
-	controller.log.Oncef("Deleting deployments that should not exist for VMI %s", vmo.Name)
+	log.Oncef("Deleting deployments that should not exist for VMI %s", vmo.Name)
 	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
 	existingDeploymentsList, err := controller.deploymentLister.Deployments(vmo.Namespace).List(selector)
 	if err != nil {
@@ -163,37 +236,52 @@ func CreateDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 	}
 	for _, deployment := range existingDeploymentsList {
 		if !contains(deploymentNames, deployment.Name) {
+			if resources.IsOpenSearchPaused(vmo) && deployments.IsOpenSearchDeployment(vmo.Name, deployment) {
+				continue
+			}
+			if !osdManaged && deployments.IsOpenSearchDashboardsDeployment(vmo.Name, deployment) {
+				continue
+			}
 			// if processing an OpenSearch data node, and the data node is expected and running
 			// An OpenSearch health check should be made to prevent unexpected shard allocation
 			if deployments.IsOpenSearchDataDeployment(vmo.Name, deployment) && (expected.OpenSearchDataDeployments > 0 || deployment.Status.ReadyReplicas > 0) {
-				if err := controller.osClient.IsGreen(vmo); err != nil {
-					controller.log.Oncef("Scale down of deployment %s not allowed: cluster health is not green", deployment.Name)
+				if err := controller.osClient.WithContext(ctx).IsGreen(vmo); err != nil {
+					log.Oncef("Scale down of deployment %s not allowed: cluster health is not green", deployment.Name)
 					continue
 				}
 			}
-			if err := deleteDeployment(controller, vmo, deployment); err != nil {
+			if err := deleteDeployment(ctx, controller, vmo, deployment); err != nil {
 				return false, err
 			}
+			if deployments.IsOpenSearchDataDeployment(vmo.Name, deployment) {
+				if err := handleOrphanedPVCs(ctx, controller, vmo, deployment); err != nil {
+					return false, err
+				}
+			}
 		}
 	}
 
+	if scaleUpErr != nil {
+		return false, scaleUpErr
+	}
 	return openSearchDirty, nil
 }
 
-func deleteDeployment(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) error {
-	controller.log.Oncef("Deleting deployment %s/%s", deployment.Namespace, deployment.Name)
+func deleteDeployment(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) error {
+	log := reconcileLog(ctx)
+	log.Oncef("Deleting deployment %s/%s", deployment.Namespace, deployment.Name)
 	metric, err := metricsexporter.GetCounterMetrics(metricsexporter.NamesDeploymentDeleteCounter)
 	if err != nil {
 		// log it but continue on with deleting the deployment
-		controller.log.Errorf("Failed to get counter metric %s: %v", metricsexporter.NamesDeploymentDeleteCounter, err)
+		log.Errorf("Failed to get counter metric %s: %v", metricsexporter.NamesDeploymentDeleteCounter, err)
 	} else {
 		metric.Inc()
 	}
-	err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Delete(context.TODO(), deployment.Name, metav1.DeleteOptions{})
+	err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Delete(ctx, deployment.Name, metav1.DeleteOptions{})
 	if err != nil {
-		controller.log.Errorf("Failed to delete deployment %s: %v", deployment.Name, err)
+		log.Errorf("Failed to delete deployment %s: %v", deployment.Name, err)
 		if metric, metricErr := metricsexporter.GetErrorMetrics(metricsexporter.NamesDeploymentDeleteError); metricErr != nil {
-			controller.log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentDeleteError, metricErr)
+			log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentDeleteError, metricErr)
 		} else {
 			metric.Inc()
 		}
@@ -202,9 +290,23 @@ func deleteDeployment(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMoni
 	return nil
 }
 
-func updateDeployment(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingDeployment, curDeployment *appsv1.Deployment) error {
+// createDeployment creates a deployment of the VMI, unless the creation is backing off as a resource quota was exceeded
+func createDeployment(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) error {
+	if err := checkQuotaBackoff(controller, vmo, "Deployment", deployment.Name); err != nil {
+		return err
+	}
+	created, err := controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Create(ctx, deployment, metav1.CreateOptions{})
+	if err != nil {
+		return handleCreateError(controller, vmo, "Deployment", deployment.Name, err)
+	}
+	controller.deploymentVersions.record(created)
+	return nil
+}
+
+func updateDeployment(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingDeployment, curDeployment *appsv1.Deployment) error {
+	log := reconcileLog(ctx)
 	if metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesDeploymentUpdateCounter); metricErr != nil {
-		controller.log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateCounter, metricErr)
+		log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateCounter, metricErr)
 	} else {
 		metric.Inc()
 	}
@@ -212,9 +314,30 @@ func updateDeployment(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMoni
 	curDeployment.Spec.Selector = existingDeployment.Spec.Selector
 	specDiffs := diff.Diff(existingDeployment, curDeployment)
 	if specDiffs != "" {
-		controller.log.Oncef("Deployment %s/%s has spec differences %s", curDeployment.Namespace, curDeployment.Name, specDiffs)
-		controller.log.Oncef("Updating deployment %s/%s", curDeployment.Namespace, curDeployment.Name)
-		_, err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(context.TODO(), curDeployment, metav1.UpdateOptions{})
+		key := curDeployment.Namespace + "/" + curDeployment.Name
+		var desiredHash string
+		if desiredHash, err = desiredDeploymentHash(vmo, curDeployment); err != nil {
+			return err
+		}
+		// Stop retrying an update which keeps failing, until the VMI spec or the desired pod template changes
+		if controller.deploymentUpdateFailures.isBlocked(key, desiredHash) {
+			log.Oncef("Skipping update of deployment %s, it failed %d consecutive times with the same desired spec", key, constants.DeploymentUpdateMaxFailures)
+			return nil
+		}
+		log.Oncef("Deployment %s/%s has spec differences %s", curDeployment.Namespace, curDeployment.Name, specDiffs)
+		log.Oncef("Updating deployment %s/%s", curDeployment.Namespace, curDeployment.Name)
+		var updated *appsv1.Deployment
+		updated, err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(ctx, curDeployment, metav1.UpdateOptions{})
+		if err != nil {
+			if controller.deploymentUpdateFailures.recordFailure(key, desiredHash) && controller.recorder != nil {
+				controller.recorder.Eventf(vmo, corev1.EventTypeWarning, deploymentUpdateFailedReason,
+					"Stopped updating deployment %s after %d consecutive failures, the update may change an immutable field. Change the VMI spec to retry: %v",
+					key, constants.DeploymentUpdateMaxFailures, err)
+			}
+		} else {
+			controller.deploymentUpdateFailures.reset(key)
+			controller.deploymentVersions.record(updated)
+		}
 	}
 
 	return err
@@ -223,7 +346,8 @@ func updateDeployment(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMoni
 // Updates the *next* candidate deployment of the given deployments list.  A deployment is a candidate only if
 // its predecessors in the list have already been updated and are fully up and running.
 // return false if 1) no errors occurred, and 2) no work was done
-func rollingUpdate(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployments []*appsv1.Deployment) (dirty bool, err error) {
+func rollingUpdate(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployments []*appsv1.Deployment) (dirty bool, err error) {
+	log := reconcileLog(ctx)
 	for index, current := range deployments {
 		existing, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(current.Name)
 		if err != nil {
@@ -231,7 +355,7 @@ func rollingUpdate(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitor
 		}
 
 		// check if the current node is ready to be updated. If it can't, skip it for the next reconcile
-		if !isUpdateAllowed(controller, vmo, current) {
+		if !isUpdateAllowed(ctx, controller, vmo, existing) {
 			continue
 		}
 		metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesDeploymentUpdateCounter)
@@ -244,17 +368,18 @@ func rollingUpdate(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitor
 		// Deployment spec differences, so call Update() and return
 		specDiffs := diff.Diff(existing, current)
 		if specDiffs != "" {
-			controller.log.Debugf("Deployment %s : Spec differences %s", current.Name, specDiffs)
-			controller.log.Oncef("Updating deployment %s in namespace %s", current.Name, current.Namespace)
-			_, err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(context.TODO(), current, metav1.UpdateOptions{})
+			log.Debugf("Deployment %s : Spec differences %s", current.Name, specDiffs)
+			log.Oncef("Updating deployment %s in namespace %s", current.Name, current.Namespace)
+			updated, err := controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(ctx, current, metav1.UpdateOptions{})
 			if err != nil {
 				if metric, metricErr := metricsexporter.GetErrorMetrics(metricsexporter.NamesDeploymentUpdateError); err != nil {
-					controller.log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
+					log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
 				} else {
 					metric.Inc()
 				}
 				return false, err
 			}
+			controller.deploymentVersions.record(updated)
 			//okay to return dirty=false after updating the *last* deployment
 			return index < len(deployments)-1, nil
 		}
@@ -266,17 +391,21 @@ func rollingUpdate(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitor
 	return false, nil
 }
 
-func updateOpenSearchDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployments []*appsv1.Deployment, existingCluster bool) (dirty bool, err error) {
+// updateOpenSearchDeployments updates the OpenSearch data deployments. During the initial bring-up of the cluster the
+// deployments are updated without waiting for the cluster to be healthy, since the cluster cannot form before all
+// its nodes are up. Once the cluster is formed, the deployments are updated one at a time while the cluster is green.
+func updateOpenSearchDeployments(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployments []*appsv1.Deployment, existingCluster bool) (dirty bool, err error) {
 	// if the cluster isn't up, patch all deployments sequentially
 	if !existingCluster {
-		return updateAllDeployments(controller, vmo, deployments)
+		return updateAllDeployments(ctx, controller, vmo, deployments)
 	}
 	// if the cluster is running, do a rolling update of each deployment
-	return rollingUpdate(controller, vmo, deployments)
+	return rollingUpdate(ctx, controller, vmo, deployments)
 }
 
 // Update all deployments in the list concurrently
-func updateAllDeployments(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployments []*appsv1.Deployment) (dirty bool, err error) {
+func updateAllDeployments(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployments []*appsv1.Deployment) (dirty bool, err error) {
+	log := reconcileLog(ctx)
 	for _, curDeployment := range deployments {
 		_, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(curDeployment.Name)
 		if err != nil {
@@ -287,33 +416,35 @@ func updateAllDeployments(controller *Controller, vmo *vmcontrollerv1.Verrazzano
 			return false, metricErr
 		}
 		metric.Inc()
-		controller.log.Oncef("Updating deployment %s in namespace %s", curDeployment.Name, curDeployment.Namespace)
-		_, err = controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(context.TODO(), curDeployment, metav1.UpdateOptions{})
+		log.Oncef("Updating deployment %s in namespace %s", curDeployment.Name, curDeployment.Namespace)
+		updated, err := controller.kubeclientset.AppsV1().Deployments(vmo.Namespace).Update(ctx, curDeployment, metav1.UpdateOptions{})
 		if err != nil {
 			if metric, metricErr := metricsexporter.GetErrorMetrics(metricsexporter.NamesDeploymentUpdateError); metricErr != nil {
-				controller.log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
+				log.Errorf("Failed to get error metric %s: %v", metricsexporter.NamesDeploymentUpdateError, metricErr)
 			} else {
 				metric.Inc()
 			}
 			return false, err
 		}
+		controller.deploymentVersions.record(updated)
 	}
 	return false, nil
 }
 
 // isUpdateAllowed checks if OpenSearch nodes are allowed to update. If a data node is removed when the cluster is yellow,
 // data loss may occur.
-func isUpdateAllowed(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, current *appsv1.Deployment) bool {
-	// if current is an OpenSearch data node
-	if deployments.IsOpenSearchDataDeployment(vmo.Namespace, current) {
+func isUpdateAllowed(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existing *appsv1.Deployment) bool {
+	log := reconcileLog(ctx)
+	// if existing is an OpenSearch data node
+	if deployments.IsOpenSearchDataDeployment(vmo.Name, existing) {
 		// if the node is down, we should try to fix it
-		if current.Status.ReadyReplicas == 0 {
+		if existing.Status.ReadyReplicas == 0 {
 			return true
 		}
 
 		// if the node is running, we shouldn't take it down unless the cluster is green (to avoid data loss)
-		if err := controller.osClient.IsGreen(vmo); err != nil {
-			controller.log.Oncef("OpenSearch node %s was not upgraded, since the cluster is not ready", current.Name)
+		if err := controller.osClient.WithContext(ctx).IsGreen(vmo); err != nil {
+			log.Oncef("OpenSearch node %s was not upgraded, since the cluster is not ready", existing.Name)
 			return false
 		}
 	}
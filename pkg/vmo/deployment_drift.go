@@ -0,0 +1,68 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/runtime"
+)
+
+// deploymentVersionTracker tracks the resource versions of the deployments written by the controller, so the
+// controller's own writes are not mistaken for external edits. The zero value is ready to use.
+type deploymentVersionTracker struct {
+	keyedTracker[string]
+}
+
+// record records the resource version of a deployment written by the controller
+func (t *deploymentVersionTracker) record(deployment *appsv1.Deployment) {
+	if deployment == nil {
+		return
+	}
+	t.set(deployment.Namespace+"/"+deployment.Name, deployment.ResourceVersion)
+}
+
+// isOwnWrite returns true if the deployment has the resource version last written by the controller
+func (t *deploymentVersionTracker) isOwnWrite(deployment *appsv1.Deployment) bool {
+	version, ok := t.get(deployment.Namespace + "/" + deployment.Name)
+	return ok && version == deployment.ResourceVersion
+}
+
+// handleDeploymentUpdate enqueues the VMI owning a deployment whose spec was changed by someone other than the
+// controller, so the drift from the VMI is corrected without waiting for the resync period. Resyncs, status
+// updates and the controller's own updates are ignored.
+func (c *Controller) handleDeploymentUpdate(old, new interface{}) {
+	oldDeployment, ok := old.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+	newDeployment, ok := new.(*appsv1.Deployment)
+	if !ok {
+		return
+	}
+	if newDeployment.ResourceVersion == oldDeployment.ResourceVersion || newDeployment.Generation == oldDeployment.Generation {
+		return
+	}
+	if c.deploymentVersions.isOwnWrite(newDeployment) {
+		return
+	}
+	owner := metav1.GetControllerOf(newDeployment)
+	if owner == nil || owner.Kind != constants.VMOKind {
+		return
+	}
+	vmo, err := c.vmoLister.VerrazzanoMonitoringInstances(newDeployment.Namespace).Get(owner.Name)
+	if err != nil {
+		if !k8serrors.IsNotFound(err) {
+			runtime.HandleError(err)
+		}
+		return
+	}
+	if vmo.UID != owner.UID {
+		return
+	}
+	c.log.Infof("Deployment %s/%s of VMI %s was changed externally, reconciling the VMI", newDeployment.Namespace, newDeployment.Name, vmo.Name)
+	c.enqueueVMO(vmo)
+}
@@ -0,0 +1,131 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vmofake "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/informers/externalversions"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// createDriftTestController creates a controller with the given VMI in its VMI lister, and a managed deployment of the VMI
+func createDriftTestController(t *testing.T) (*Controller, *appsv1.Deployment) {
+	controller, vmo := createControllerForTesting()
+	vmo.UID = "vmi-uid"
+	vmo.Spec.CascadingDelete = true
+	informer := informers.NewSharedInformerFactory(vmofake.NewSimpleClientset(), constants.ResyncPeriod).Verrazzano().V1().VerrazzanoMonitoringInstances()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(vmo))
+	controller.vmoLister = informer.Lister()
+	controller.workqueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "VMOs")
+
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            resources.GetMetaName(vmo.Name, "grafana"),
+			Namespace:       vmo.Namespace,
+			OwnerReferences: resources.GetOwnerReferences(vmo),
+			ResourceVersion: "1",
+			Generation:      1,
+		},
+	}
+	return controller, deployment
+}
+
+// assertEnqueued asserts that the VMI of the controller is enqueued, once the rate limited delay has passed
+func assertEnqueued(t *testing.T, controller *Controller) {
+	assert.Eventually(t, func() bool {
+		return controller.workqueue.Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+// editDeployment returns a copy of the deployment with a changed spec, as written by the API server
+func editDeployment(deployment *appsv1.Deployment, resourceVersion string) *appsv1.Deployment {
+	edited := deployment.DeepCopy()
+	edited.ResourceVersion = resourceVersion
+	edited.Generation++
+	edited.Spec.Replicas = resources.NewVal(3)
+	return edited
+}
+
+// TestHandleDeploymentUpdateExternalEdit Tests that the VMI is enqueued when its deployment is edited externally
+// GIVEN a deployment owned by a VMI
+// WHEN the deployment spec is changed by someone other than the controller
+// THEN the VMI owning the deployment is enqueued
+func TestHandleDeploymentUpdateExternalEdit(t *testing.T) {
+	controller, deployment := createDriftTestController(t)
+
+	controller.handleDeploymentUpdate(deployment, editDeployment(deployment, "2"))
+	assertEnqueued(t, controller)
+	key, _ := controller.workqueue.Get()
+	assert.Equal(t, constants.VerrazzanoSystemNamespace+"/"+constants.VMODefaultName, key)
+}
+
+// TestHandleDeploymentUpdateIgnored Tests that the VMI is not enqueued for deployment updates which are not drift
+// GIVEN a deployment owned by a VMI, and a deployment without an owner
+// WHEN the deployment is resynced, its status is updated, it is updated by the controller, or the deployment without
+// an owner is edited
+// THEN the VMI is not enqueued
+func TestHandleDeploymentUpdateIgnored(t *testing.T) {
+	controller, deployment := createDriftTestController(t)
+
+	// resync
+	controller.handleDeploymentUpdate(deployment, deployment.DeepCopy())
+
+	// status update
+	statusUpdate := deployment.DeepCopy()
+	statusUpdate.ResourceVersion = "2"
+	statusUpdate.Status.ReadyReplicas = 1
+	controller.handleDeploymentUpdate(deployment, statusUpdate)
+
+	// update by the controller
+	ownUpdate := editDeployment(deployment, "3")
+	controller.deploymentVersions.record(ownUpdate)
+	controller.handleDeploymentUpdate(deployment, ownUpdate)
+
+	// edit of a deployment without an owner
+	unowned := deployment.DeepCopy()
+	unowned.OwnerReferences = nil
+	controller.handleDeploymentUpdate(unowned, editDeployment(unowned, "4"))
+
+	// edit of a deployment owned by a recreated VMI
+	otherOwner := deployment.DeepCopy()
+	otherOwner.OwnerReferences[0].UID = "other-uid"
+	controller.handleDeploymentUpdate(otherOwner, editDeployment(otherOwner, "5"))
+
+	// the VMI is enqueued with a rate limited delay, so give it time to be added
+	time.Sleep(100 * time.Millisecond)
+	assert.Zero(t, controller.workqueue.Len())
+
+	// a later external edit of the deployment updated by the controller is drift again
+	controller.handleDeploymentUpdate(ownUpdate, editDeployment(ownUpdate, "6"))
+	assertEnqueued(t, controller)
+}
+
+// TestDeploymentVersionsRecorded Tests that the deployments written by the controller are tracked
+// GIVEN a deployment of a VMI
+// WHEN the controller creates the deployment
+// THEN the resource version of the created deployment is tracked as written by the controller
+func TestDeploymentVersionsRecorded(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.GetMetaName(vmo.Name, "grafana"),
+			Namespace: vmo.Namespace,
+		},
+	}
+	assert.NoError(t, createDeployment(context.TODO(), controller, vmo, deployment))
+	assert.True(t, controller.deploymentVersions.isOwnWrite(deployment))
+
+	edited := deployment.DeepCopy()
+	edited.ResourceVersion = "2"
+	assert.False(t, controller.deploymentVersions.isOwnWrite(edited))
+}
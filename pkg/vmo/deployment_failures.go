@@ -0,0 +1,63 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+// deploymentUpdateFailedReason is the reason of the Warning event recorded when a deployment update is no longer retried
+const deploymentUpdateFailedReason = "DeploymentUpdateFailed"
+
+// deploymentFailureTracker counts the consecutive failures of updating a deployment to the same desired spec, identified
+// by desiredDeploymentHash. The zero value is ready to use.
+type deploymentFailureTracker struct {
+	keyedTracker[deploymentFailure]
+}
+
+type deploymentFailure struct {
+	desiredHash string
+	count       int
+}
+
+// isBlocked returns true if the update of the deployment to the given desired spec
+// has failed too many consecutive times and should not be retried
+func (t *deploymentFailureTracker) isBlocked(key, desiredHash string) bool {
+	failure, ok := t.get(key)
+	return ok && failure.desiredHash == desiredHash && failure.count >= constants.DeploymentUpdateMaxFailures
+}
+
+// recordFailure records a failed update of the deployment. A failure with a different desired spec restarts the count.
+// Returns true if the update has just reached the maximum number of consecutive failures.
+func (t *deploymentFailureTracker) recordFailure(key, desiredHash string) bool {
+	failure := t.modify(key, func(failure deploymentFailure, _ bool) (deploymentFailure, bool) {
+		if failure.desiredHash != desiredHash {
+			failure = deploymentFailure{desiredHash: desiredHash}
+		}
+		failure.count++
+		return failure, true
+	})
+	return failure.count == constants.DeploymentUpdateMaxFailures
+}
+
+// desiredDeploymentHash returns a hash identifying the desired spec of a deployment update, made of the generation of
+// the VMI and a hash of the desired pod template. Unlike the differences with the existing deployment, it does not
+// change with the status or the fields defaulted by the API server, but changes whenever the VMI spec changes.
+func desiredDeploymentHash(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) (string, error) {
+	b, err := json.Marshal(deployment.Spec.Template)
+	if err != nil {
+		return "", err
+	}
+	h := fnv.New32a()
+	if _, err := h.Write(b); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%x", vmo.Generation, h.Sum32()), nil
+}
@@ -0,0 +1,431 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+// TestCreateDeploymentsOpenSearchPaused Tests that OpenSearch deployments are not reconciled while OpenSearch is paused
+// GIVEN a VMI with OpenSearch paused, an outdated OpenSearch ingest deployment and a surplus OpenSearch data deployment
+// WHEN I call CreateDeployments
+// THEN the OpenSearch deployments are left untouched and the Grafana deployment is still created
+func TestCreateDeploymentsOpenSearchPaused(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	paused := true
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.Opensearch.Paused = &paused
+	vmo.Spec.Opensearch.IngestNode.Replicas = 1
+	vmo.Spec.Grafana.Enabled = true
+	vmo.Spec.Grafana.Replicas = 1
+
+	expected, err := deployments.New(vmo, controller.kubeclientset, controller.operatorConfig, map[string]string{})
+	assert.NoError(t, err)
+	var ingestDeployment *appsv1.Deployment
+	for _, deployment := range expected.Deployments {
+		if deployments.IsOpenSearchDeployment(vmo.Name, deployment) {
+			ingestDeployment = deployment.DeepCopy()
+		}
+	}
+	assert.NotNil(t, ingestDeployment)
+	ingestDeployment.Spec.Replicas = resources.NewVal(3)
+	dataDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.GetMetaName(vmo.Name, config.ElasticsearchData.Name) + "-5",
+			Namespace: vmo.Namespace,
+			Labels:    map[string]string{constants.VMOLabel: vmo.Name},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: resources.GetSpecID(vmo.Name, config.ElasticsearchData.Name),
+				},
+			},
+		},
+	}
+
+	client := fake.NewSimpleClientset(ingestDeployment, dataDeployment)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(ingestDeployment))
+	assert.NoError(t, informer.Informer().GetIndexer().Add(dataDeployment))
+	controller.kubeclientset = client
+	controller.deploymentLister = informer.Lister()
+
+	_, err = CreateDeployments(context.TODO(), controller, vmo, map[string]string{}, true)
+	assert.NoError(t, err)
+
+	existingIngest, err := client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), ingestDeployment.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), *existingIngest.Spec.Replicas)
+	_, err = client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), dataDeployment.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	_, err = client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), resources.GetMetaName(vmo.Name, config.Grafana.Name), metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+// TestCreateDeploymentsOpenSearchDashboardsUnmanaged Tests that the OpenSearch Dashboards deployment is left alone
+// when it is not managed by the VMO
+// GIVEN a VMI with OpenSearch Dashboards enabled but not managed, and an OpenSearch Dashboards deployment managed by
+// another operator
+// WHEN I call CreateDeployments
+// THEN the OpenSearch Dashboards deployment is neither updated nor deleted, and the Grafana deployment is still created
+func TestCreateDeploymentsOpenSearchDashboardsUnmanaged(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	managed := false
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	vmo.Spec.OpensearchDashboards.Replicas = 1
+	vmo.Spec.OpensearchDashboards.Managed = &managed
+	vmo.Spec.Grafana.Enabled = true
+	vmo.Spec.Grafana.Replicas = 1
+
+	osdDeployment := deployments.NewOpenSearchDashboardsDeployment(vmo)
+	osdDeployment.Spec.Replicas = resources.NewVal(3)
+	client := fake.NewSimpleClientset(osdDeployment)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(osdDeployment))
+	controller.kubeclientset = client
+	controller.deploymentLister = informer.Lister()
+
+	_, err := CreateDeployments(context.TODO(), controller, vmo, map[string]string{}, true)
+	assert.NoError(t, err)
+
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "update" || action.GetVerb() == "delete" {
+			t.Errorf("unexpected action %s on deployments", action.GetVerb())
+		}
+	}
+	existingOSD, err := client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), osdDeployment.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), *existingOSD.Spec.Replicas)
+	_, err = client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), resources.GetMetaName(vmo.Name, config.Grafana.Name), metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	// OpenSearch Dashboards is not created either when it is not managed
+	client = fake.NewSimpleClientset()
+	controller.kubeclientset = client
+	controller.deploymentLister = kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments().Lister()
+	_, err = CreateDeployments(context.TODO(), controller, vmo, map[string]string{}, true)
+	assert.NoError(t, err)
+	_, err = client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), osdDeployment.Name, metav1.GetOptions{})
+	assert.Error(t, err)
+}
+
+// TestUpdateDeploymentCircuitBreaker Tests that a deployment update which keeps failing is no longer retried
+// GIVEN a deployment update which always fails with the same desired spec
+// WHEN I call updateDeployment repeatedly
+// THEN the update is only attempted until the maximum number of failures is reached, a Warning event is recorded,
+// the update is not attempted again when only the differences with the existing deployment change, and is attempted
+// again once the desired pod template or the generation of the VMI change
+func TestUpdateDeploymentCircuitBreaker(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	existing := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "deploy",
+			Namespace: vmo.Namespace,
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: resources.NewVal(1),
+		},
+	}
+	client := fake.NewSimpleClientset(existing)
+	updateAttempts := 0
+	client.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		updateAttempts++
+		return true, nil, errors.New("field is immutable")
+	})
+	recorder := record.NewFakeRecorder(10)
+	controller.kubeclientset = client
+	controller.recorder = recorder
+
+	for i := 0; i < constants.DeploymentUpdateMaxFailures+2; i++ {
+		updated := existing.DeepCopy()
+		updated.Spec.Replicas = resources.NewVal(2)
+		_ = updateDeployment(context.TODO(), controller, vmo, existing, updated)
+	}
+	assert.Equal(t, constants.DeploymentUpdateMaxFailures, updateAttempts)
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, corev1.EventTypeWarning)
+	assert.Contains(t, event, deploymentUpdateFailedReason)
+
+	// A change of the existing deployment, e.g. of its status, does not resume the updates
+	changedExisting := existing.DeepCopy()
+	changedExisting.Status.Replicas = 1
+	updated := existing.DeepCopy()
+	updated.Spec.Replicas = resources.NewVal(2)
+	assert.NoError(t, updateDeployment(context.TODO(), controller, vmo, changedExisting, updated))
+	assert.Equal(t, constants.DeploymentUpdateMaxFailures, updateAttempts)
+
+	// A change of the desired pod template resumes the updates
+	updated = existing.DeepCopy()
+	updated.Spec.Template.Labels = map[string]string{"app": "deploy"}
+	assert.Error(t, updateDeployment(context.TODO(), controller, vmo, existing, updated))
+	assert.Equal(t, constants.DeploymentUpdateMaxFailures+1, updateAttempts)
+
+	// A change of the VMI spec resumes the updates
+	for i := 0; i < constants.DeploymentUpdateMaxFailures; i++ {
+		_ = updateDeployment(context.TODO(), controller, vmo, existing, updated.DeepCopy())
+	}
+	attempts := updateAttempts
+	vmo.Generation++
+	assert.Error(t, updateDeployment(context.TODO(), controller, vmo, existing, updated.DeepCopy()))
+	assert.Equal(t, attempts+1, updateAttempts)
+}
+
+// createHealthOSClient creates an OSClient for an OpenSearch cluster with the given health, counting the requests it receives
+func createHealthOSClient(health string, requests *int) *opensearch.OSClient {
+	o := opensearch.NewOSClient(nil)
+	o.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		*requests++
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(fmt.Sprintf(`{"status": "%s"}`, health))),
+		}, nil
+	}
+	return o
+}
+
+// createRunningDataDeployment creates a running OpenSearch data deployment of the VMI
+func createRunningDataDeployment(vmoName, namespace, name string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{constants.VMOLabel: vmoName},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: resources.NewVal(1),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: resources.GetSpecID(vmoName, config.ElasticsearchData.Name),
+				},
+			},
+		},
+		Status: appsv1.DeploymentStatus{
+			Replicas:          1,
+			ReadyReplicas:     1,
+			AvailableReplicas: 1,
+		},
+	}
+}
+
+// TestUpdateOpenSearchDeploymentsHealthGate Tests that OpenSearch data deployments are only gated on the cluster health
+// once the cluster is formed
+// GIVEN a running OpenSearch data deployment with spec changes, and a cluster with 'yellow' health
+// WHEN I call updateOpenSearchDeployments during the initial bring-up of the cluster, and once the cluster is formed
+// THEN the deployment is updated without checking the cluster health during the initial bring-up,
+// and is not updated once the cluster is formed
+func TestUpdateOpenSearchDeploymentsHealthGate(t *testing.T) {
+	var tests = []struct {
+		name            string
+		existingCluster bool
+		updated         bool
+	}{
+		{"initial bring-up", false, true},
+		{"cluster formed", true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, vmo := createControllerForTesting()
+			vmo.Spec.Opensearch.Enabled = true
+			existing := createRunningDataDeployment(vmo.Name, vmo.Namespace, resources.GetMetaName(vmo.Name, config.ElasticsearchData.Name)+"-0")
+			client := fake.NewSimpleClientset(existing)
+			informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments()
+			assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+			var requests int
+			controller.kubeclientset = client
+			controller.deploymentLister = informer.Lister()
+			controller.osClient = createHealthOSClient("yellow", &requests)
+
+			expected := existing.DeepCopy()
+			expected.Status = appsv1.DeploymentStatus{}
+			expected.Spec.Template.Labels["updated"] = "true"
+			_, err := updateOpenSearchDeployments(context.TODO(), controller, vmo, []*appsv1.Deployment{expected}, tt.existingCluster)
+			assert.NoError(t, err)
+
+			actual, err := client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.updated, actual.Spec.Template.Labels["updated"] == "true")
+			assert.Equal(t, tt.existingCluster, requests > 0)
+		})
+	}
+}
+
+// TestValidateDataScaleUpHealthGate Tests that adding OpenSearch data deployments is only gated on the cluster health
+// once the cluster is formed
+// GIVEN an existing OpenSearch data deployment, a new OpenSearch data deployment, and a cluster with 'red' health
+// WHEN I call validateDataScaleUp during the initial bring-up of the cluster, and once the cluster is formed
+// THEN the new deployment is allowed without checking the cluster health during the initial bring-up,
+// and is not allowed once the cluster is formed
+func TestValidateDataScaleUpHealthGate(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Opensearch.Enabled = true
+	existing := createRunningDataDeployment(vmo.Name, vmo.Namespace, resources.GetMetaName(vmo.Name, config.ElasticsearchData.Name)+"-0")
+	newDeployment := createRunningDataDeployment(vmo.Name, vmo.Namespace, resources.GetMetaName(vmo.Name, config.ElasticsearchData.Name)+"-1")
+	informer := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), constants.ResyncPeriod).Apps().V1().Deployments()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	var requests int
+	controller.deploymentLister = informer.Lister()
+	controller.osClient = createHealthOSClient("red", &requests)
+
+	isScaleUp, err := validateDataScaleUp(context.TODO(), controller, vmo, []*appsv1.Deployment{newDeployment}, false)
+	assert.NoError(t, err)
+	assert.False(t, isScaleUp)
+	assert.Equal(t, 0, requests)
+
+	isScaleUp, err = validateDataScaleUp(context.TODO(), controller, vmo, []*appsv1.Deployment{newDeployment}, true)
+	assert.Error(t, err)
+	assert.True(t, isScaleUp)
+	assert.Greater(t, requests, 0)
+}
+
+// TestValidateDataScaleUpUnknownPVC Tests that adding an OpenSearch data deployment waits for its PVC to be known
+// GIVEN an existing OpenSearch data deployment, and a new OpenSearch data deployment whose PVC is not in the lister
+// WHEN I call validateDataScaleUp once the cluster is formed
+// THEN a waiting error is returned without validating the scale up against the cluster
+func TestValidateDataScaleUpUnknownPVC(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Opensearch.Enabled = true
+	existing := createRunningDataDeployment(vmo.Name, vmo.Namespace, resources.GetMetaName(vmo.Name, config.ElasticsearchData.Name)+"-0")
+	newDeployment := createRunningDataDeployment(vmo.Name, vmo.Namespace, resources.GetMetaName(vmo.Name, config.ElasticsearchData.Name)+"-1")
+	newDeployment.Spec.Template.Spec.Volumes = []corev1.Volume{{
+		Name: "elasticsearch-data",
+		VolumeSource: corev1.VolumeSource{
+			PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: "pvc-1"},
+		},
+	}}
+	informerFactory := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), constants.ResyncPeriod)
+	informer := informerFactory.Apps().V1().Deployments()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	var requests int
+	controller.deploymentLister = informer.Lister()
+	controller.pvcLister = informerFactory.Core().V1().PersistentVolumeClaims().Lister()
+	controller.osClient = createHealthOSClient("green", &requests)
+
+	isScaleUp, err := validateDataScaleUp(context.TODO(), controller, vmo, []*appsv1.Deployment{newDeployment}, true)
+	assert.True(t, isWaitingError(err))
+	assert.True(t, isScaleUp)
+	assert.Equal(t, 0, requests)
+}
+
+// TestUpdateOpenSearchDashboardsDeploymentScaleToZero Tests that OpenSearch Dashboards is only scaled to zero replicas
+// once its in-flight requests are drained
+// GIVEN a VMI whose OpenSearch Dashboards is scaled to zero, and an OpenSearch Dashboards deployment with 2 replicas
+// WHEN I call updateOpenSearchDashboardsDeployment before and after the scale down grace period has elapsed
+// THEN the deployment is scaled to its last replica and a temporary error is returned during the grace period,
+// and the deployment is scaled to zero replicas once the grace period has elapsed
+func TestUpdateOpenSearchDashboardsDeploymentScaleToZero(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	vmo.Spec.OpensearchDashboards.Replicas = 2
+	existing := deployments.NewOpenSearchDashboardsDeployment(vmo)
+	existing.Status.AvailableReplicas = 2
+	client := fake.NewSimpleClientset(existing)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	controller.kubeclientset = client
+	controller.deploymentLister = informer.Lister()
+	gracePeriod := time.Hour
+	controller.operatorConfig.DashboardsScaleDownGracePeriod = &gracePeriod
+	vmo.Spec.OpensearchDashboards.ScaleToZero = true
+	InitializeVMOSpec(context.TODO(), controller, vmo)
+
+	// the last replica is kept during the grace period
+	err := updateOpenSearchDashboardsDeployment(context.TODO(), deployments.NewOpenSearchDashboardsDeployment(vmo), controller, vmo)
+	assert.ErrorContains(t, err, "waiting")
+	actual, err := client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), *actual.Spec.Replicas)
+
+	// the last replica is removed once the grace period has elapsed
+	gracePeriod = 0
+	err = updateOpenSearchDashboardsDeployment(context.TODO(), deployments.NewOpenSearchDashboardsDeployment(vmo), controller, vmo)
+	assert.NoError(t, err)
+	actual, err = client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(0), *actual.Spec.Replicas)
+}
+
+// TestUpdateOpenSearchDashboardsDeploymentScaleToZeroCancelled Tests that a scale down of OpenSearch Dashboards to zero
+// replicas is cancelled when OpenSearch Dashboards is scaled up again during the grace period
+// GIVEN a VMI whose OpenSearch Dashboards is scaled to zero, and an OpenSearch Dashboards deployment with 1 replica
+// WHEN I call updateOpenSearchDashboardsDeployment during the grace period, then after scaling OpenSearch Dashboards up again
+// THEN the deployment keeps its replica, and the scale down request is cleared once OpenSearch Dashboards is scaled up
+func TestUpdateOpenSearchDashboardsDeploymentScaleToZeroCancelled(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	vmo.Spec.OpensearchDashboards.Replicas = 1
+	existing := deployments.NewOpenSearchDashboardsDeployment(vmo)
+	existing.Status.AvailableReplicas = 1
+	client := fake.NewSimpleClientset(existing)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	controller.kubeclientset = client
+	controller.deploymentLister = informer.Lister()
+	gracePeriod := time.Hour
+	controller.operatorConfig.DashboardsScaleDownGracePeriod = &gracePeriod
+	key := existing.Namespace + "/" + existing.Name
+
+	vmo.Spec.OpensearchDashboards.ScaleToZero = true
+	InitializeVMOSpec(context.TODO(), controller, vmo)
+	assert.Error(t, updateOpenSearchDashboardsDeployment(context.TODO(), deployments.NewOpenSearchDashboardsDeployment(vmo), controller, vmo))
+	assert.Contains(t, controller.dashboardsScaleDowns.values, key)
+
+	vmo.Spec.OpensearchDashboards.ScaleToZero = false
+	vmo.Spec.OpensearchDashboards.Replicas = 1
+	InitializeVMOSpec(context.TODO(), controller, vmo)
+	assert.NoError(t, updateOpenSearchDashboardsDeployment(context.TODO(), deployments.NewOpenSearchDashboardsDeployment(vmo), controller, vmo))
+	assert.NotContains(t, controller.dashboardsScaleDowns.values, key)
+	actual, err := client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(1), *actual.Spec.Replicas)
+}
+
+// TestUpdateOpenSearchDashboardsDeploymentScaleUpWaiting Tests that scaling up OpenSearch Dashboards one replica at a
+// time is a wait, not a failure
+// GIVEN a VMI with 3 OpenSearch Dashboards replicas, and an OpenSearch Dashboards deployment with 1 available replica
+// WHEN I call updateOpenSearchDashboardsDeployment
+// THEN the deployment is scaled up by one replica and a waiting error is returned
+func TestUpdateOpenSearchDashboardsDeploymentScaleUpWaiting(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	vmo.Spec.OpensearchDashboards.Replicas = 1
+	existing := deployments.NewOpenSearchDashboardsDeployment(vmo)
+	existing.Status.AvailableReplicas = 1
+	client := fake.NewSimpleClientset(existing)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	controller.kubeclientset = client
+	controller.deploymentLister = informer.Lister()
+
+	vmo.Spec.OpensearchDashboards.Replicas = 3
+	err := updateOpenSearchDashboardsDeployment(context.TODO(), deployments.NewOpenSearchDashboardsDeployment(vmo), controller, vmo)
+	assert.ErrorContains(t, err, "waiting to bring OS Dashboards replica up to full count")
+	assert.True(t, isWaitingError(err))
+	actual, err := client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), *actual.Spec.Replicas)
+}
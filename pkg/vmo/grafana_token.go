@@ -0,0 +1,126 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/grafana"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createGrafanaServiceAccountToken ensures that the Grafana service account of the VMI exists with its role, and that
+// its Secret holds a valid token of the service account. A new token is only created when the token of the Secret is
+// missing or rejected by Grafana.
+func createGrafanaServiceAccountToken(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
+	tokenSpec := vmo.Spec.Grafana.ServiceAccountToken
+	if !vmo.Spec.Grafana.Enabled || tokenSpec == nil {
+		return nil
+	}
+	role := tokenSpec.Role
+	if role == "" {
+		role = constants.GrafanaDefaultServiceAccountRole
+	}
+	grafanaEndpoint := resources.GetGrafanaHTTPEndpoint(vmo)
+	if !controller.grafanaClient.IsReady(ctx, grafanaEndpoint) {
+		log.Progressf("Grafana is not ready yet, the token of the service account %s will be created later", tokenSpec.ServiceAccountName)
+		return nil
+	}
+
+	secret, err := controller.secretLister.Secrets(vmo.Namespace).Get(tokenSpec.SecretName)
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	var token string
+	if secret != nil {
+		token = string(secret.Data[constants.GrafanaServiceAccountTokenKey])
+	}
+	tokenValid := false
+	if token != "" {
+		if tokenValid, err = controller.grafanaClient.IsTokenValid(ctx, grafanaEndpoint, token); err != nil {
+			return err
+		}
+	}
+	if tokenValid && secret.Annotations[constants.GrafanaServiceAccountRoleAnnotation] == role {
+		return nil
+	}
+
+	credentials, err := getGrafanaAdminCredentials(controller, vmo)
+	if err != nil {
+		return err
+	}
+	serviceAccount, err := controller.grafanaClient.EnsureServiceAccount(ctx, grafanaEndpoint, credentials, tokenSpec.ServiceAccountName, role)
+	if err != nil {
+		return err
+	}
+	if !tokenValid {
+		log.Oncef("Creating a token of the Grafana service account %s in Secret %s/%s", tokenSpec.ServiceAccountName, vmo.Namespace, tokenSpec.SecretName)
+		// token names are unique per service account
+		tokenName := fmt.Sprintf("%s-%d", tokenSpec.SecretName, time.Now().Unix())
+		if token, err = controller.grafanaClient.CreateServiceAccountToken(ctx, grafanaEndpoint, credentials, serviceAccount.ID, tokenName); err != nil {
+			return err
+		}
+	}
+	return createOrUpdateGrafanaTokenSecret(ctx, controller, vmo, secret, token, role)
+}
+
+// getGrafanaAdminCredentials returns the credentials of the Grafana admin user, from the Grafana admin Secret
+func getGrafanaAdminCredentials(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (*grafana.AdminCredentials, error) {
+	secret, err := controller.secretLister.Secrets(vmo.Namespace).Get(constants.GrafanaAdminSecret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the Grafana admin Secret %s/%s: %v", vmo.Namespace, constants.GrafanaAdminSecret, err)
+	}
+	credentials := &grafana.AdminCredentials{
+		Username: string(secret.Data[constants.VMOSecretUsernameField]),
+		Password: string(secret.Data[constants.VMOSecretPasswordField]),
+	}
+	if config.Grafana.OidcProxy != nil {
+		// the basic auth is disabled when Grafana is behind the auth proxy
+		credentials.AuthProxyHeaderName = vmo.Spec.Grafana.AuthProxyHeaderName
+		if credentials.AuthProxyHeaderName == "" {
+			credentials.AuthProxyHeaderName = constants.GrafanaDefaultAuthProxyHeaderName
+		}
+	}
+	return credentials, nil
+}
+
+// createOrUpdateGrafanaTokenSecret writes the token of the Grafana service account and its role to the Secret, which is
+// created if it does not exist
+func createOrUpdateGrafanaTokenSecret(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existing *corev1.Secret, token, role string) error {
+	if existing == nil {
+		secret := &corev1.Secret{
+			Type: corev1.SecretTypeOpaque,
+			ObjectMeta: metav1.ObjectMeta{
+				Labels:          resources.GetMetaLabels(vmo),
+				Annotations:     map[string]string{constants.GrafanaServiceAccountRoleAnnotation: role},
+				Name:            vmo.Spec.Grafana.ServiceAccountToken.SecretName,
+				Namespace:       vmo.Namespace,
+				OwnerReferences: resources.GetOwnerReferences(vmo),
+			},
+			Data: map[string][]byte{constants.GrafanaServiceAccountTokenKey: []byte(token)},
+		}
+		_, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Create(ctx, secret, metav1.CreateOptions{})
+		return err
+	}
+	secret := existing.DeepCopy()
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[constants.GrafanaServiceAccountRoleAnnotation] = role
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[constants.GrafanaServiceAccountTokenKey] = []byte(token)
+	_, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
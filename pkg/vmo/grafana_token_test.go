@@ -0,0 +1,140 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/grafana"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// createGrafanaTokenTestClient creates a Grafana client for a mock Grafana with a single service account slot, which
+// counts the created tokens and the role updates
+func createGrafanaTokenTestClient(t *testing.T, validTokens map[string]bool, role *string, tokensCreated, roleUpdates *int) *grafana.Client {
+	g := grafana.NewClient()
+	g.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		respond := func(code int, body string) (*http.Response, error) {
+			return &http.Response{StatusCode: code, Body: io.NopCloser(strings.NewReader(body))}, nil
+		}
+		switch {
+		case request.URL.Path == "/api/health":
+			return respond(http.StatusOK, `{"database": "ok"}`)
+		case request.URL.Path == "/api/org":
+			if validTokens[strings.TrimPrefix(request.Header.Get("Authorization"), "Bearer ")] {
+				return respond(http.StatusOK, `{"id": 1}`)
+			}
+			return respond(http.StatusUnauthorized, `{"message": "invalid API key"}`)
+		case request.URL.Path == "/api/serviceaccounts/search":
+			if *role == "" {
+				return respond(http.StatusOK, `{"totalCount": 0, "serviceAccounts": []}`)
+			}
+			return respond(http.StatusOK, `{"totalCount": 1, "serviceAccounts": [{"id": 7, "name": "automation", "role": "`+*role+`"}]}`)
+		case request.Method == "POST" && request.URL.Path == "/api/serviceaccounts":
+			body := map[string]interface{}{}
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+			*role = body["role"].(string)
+			return respond(http.StatusCreated, `{"id": 7, "name": "automation", "role": "`+*role+`"}`)
+		case request.Method == http.MethodPatch && request.URL.Path == "/api/serviceaccounts/7":
+			body := map[string]string{}
+			assert.NoError(t, json.NewDecoder(request.Body).Decode(&body))
+			*role = body["role"]
+			*roleUpdates++
+			return respond(http.StatusOK, `{}`)
+		case request.Method == "POST" && request.URL.Path == "/api/serviceaccounts/7/tokens":
+			*tokensCreated++
+			key := fmt.Sprintf("glsa_%d", *tokensCreated)
+			validTokens[key] = true
+			return respond(http.StatusOK, `{"id": 1, "name": "automation-token", "key": "`+key+`"}`)
+		}
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	return g
+}
+
+// TestCreateGrafanaServiceAccountToken Tests provisioning the token of a Grafana service account in a Secret
+// GIVEN a VMI with a Grafana service account token, and a mock Grafana
+// WHEN the token is provisioned, again with a valid token, after its token is revoked, and after its role changed
+// THEN the service account and its token Secret are created, the token is only created again when it is no longer
+// valid, and the role of the service account is updated without creating a token
+func TestCreateGrafanaServiceAccountToken(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Grafana.Enabled = true
+	vmo.Spec.Grafana.ServiceAccountToken = &vmcontrollerv1.GrafanaServiceAccountToken{
+		ServiceAccountName: "automation",
+		SecretName:         "grafana-automation-token",
+	}
+	adminSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.GrafanaAdminSecret, Namespace: vmo.Namespace},
+		Data:       map[string][]byte{constants.VMOSecretUsernameField: []byte("admin"), constants.VMOSecretPasswordField: []byte("secret")},
+	}
+	_, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Create(context.TODO(), adminSecret, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	validTokens := map[string]bool{}
+	role := ""
+	tokensCreated, roleUpdates := 0, 0
+	controller.grafanaClient = createGrafanaTokenTestClient(t, validTokens, &role, &tokensCreated, &roleUpdates)
+	getSecret := func() *corev1.Secret {
+		secret, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Get(context.TODO(), "grafana-automation-token", metav1.GetOptions{})
+		assert.NoError(t, err)
+		return secret
+	}
+
+	assert.NoError(t, createGrafanaServiceAccountToken(context.TODO(), controller, vmo))
+	assert.Equal(t, "Viewer", role)
+	assert.Equal(t, 1, tokensCreated)
+	secret := getSecret()
+	token := string(secret.Data[constants.GrafanaServiceAccountTokenKey])
+	assert.True(t, validTokens[token])
+	assert.Equal(t, "Viewer", secret.Annotations[constants.GrafanaServiceAccountRoleAnnotation])
+
+	// a valid token is not created again
+	assert.NoError(t, createGrafanaServiceAccountToken(context.TODO(), controller, vmo))
+	assert.Equal(t, 1, tokensCreated)
+	assert.Equal(t, token, string(getSecret().Data[constants.GrafanaServiceAccountTokenKey]))
+
+	// a revoked token is created again
+	delete(validTokens, token)
+	assert.NoError(t, createGrafanaServiceAccountToken(context.TODO(), controller, vmo))
+	assert.Equal(t, 2, tokensCreated)
+	token = string(getSecret().Data[constants.GrafanaServiceAccountTokenKey])
+	assert.True(t, validTokens[token])
+
+	// a changed role is updated, keeping the token
+	vmo.Spec.Grafana.ServiceAccountToken.Role = "Editor"
+	assert.NoError(t, createGrafanaServiceAccountToken(context.TODO(), controller, vmo))
+	assert.Equal(t, "Editor", role)
+	assert.Equal(t, 1, roleUpdates)
+	assert.Equal(t, 2, tokensCreated)
+	secret = getSecret()
+	assert.Equal(t, token, string(secret.Data[constants.GrafanaServiceAccountTokenKey]))
+	assert.Equal(t, "Editor", secret.Annotations[constants.GrafanaServiceAccountRoleAnnotation])
+}
+
+// TestCreateGrafanaServiceAccountTokenDisabled Tests that no token is provisioned unless requested
+// GIVEN a VMI with Grafana enabled and no Grafana service account token
+// WHEN the token is provisioned
+// THEN Grafana is not called
+func TestCreateGrafanaServiceAccountTokenDisabled(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Grafana.Enabled = true
+	g := grafana.NewClient()
+	g.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	controller.grafanaClient = g
+	assert.NoError(t, createGrafanaServiceAccountToken(context.TODO(), controller, vmo))
+}
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -19,11 +19,12 @@ import (
 )
 
 // CreateIngresses create/update VMO ingress k8s resources
-func CreateIngresses(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func CreateIngresses(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 	functionMetric, functionError := metricsexporter.GetFunctionMetrics(metricsexporter.NamesIngress)
 	if functionError == nil {
-		functionMetric.LogStart()
-		defer functionMetric.LogEnd(false)
+		timer := functionMetric.LogStart()
+		defer functionMetric.LogEnd(timer, false)
 	} else {
 		return functionError
 	}
@@ -37,16 +38,16 @@ func CreateIngresses(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonit
 
 	ingList, err := ingresses.New(vmo, getExistingIngresses(existingIngressList, vmo))
 	if err != nil {
-		controller.log.Errorf("Failed to create Ingress specs for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to create Ingress specs for VMI %s: %v", vmo.Name, err)
 		functionMetric.IncError()
 		return err
 	}
 	if vmo.Spec.IngressTargetDNSName == "" {
-		controller.log.Debugf("No Ingress target specified, using default Ingress target: '%s'", controller.operatorConfig.DefaultIngressTargetDNSName)
+		log.Debugf("No Ingress target specified, using default Ingress target: '%s'", controller.operatorConfig.DefaultIngressTargetDNSName)
 		vmo.Spec.IngressTargetDNSName = controller.operatorConfig.DefaultIngressTargetDNSName
 	}
 	var ingressNames []string
-	controller.log.Oncef("Creating/updating Ingresses for VMI %s", vmo.Name)
+	log.Oncef("Creating/updating Ingresses for VMI %s", vmo.Name)
 	for _, curIngress := range ingList {
 		ingName := curIngress.Name
 		ingressNames = append(ingressNames, ingName)
@@ -58,36 +59,36 @@ func CreateIngresses(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonit
 			functionMetric.IncError()
 			return nil
 		}
-		controller.log.Debugf("Applying Ingress '%s' in namespace '%s' for VMI '%s'\n", ingName, vmo.Namespace, vmo.Name)
+		log.Debugf("Applying Ingress '%s' in namespace '%s' for VMI '%s'\n", ingName, vmo.Namespace, vmo.Name)
 		existingIngress, err := controller.ingressLister.Ingresses(vmo.Namespace).Get(ingName)
 		if existingIngress != nil {
 			specDiffs := diff.Diff(existingIngress, curIngress)
 			if specDiffs != "" {
-				controller.log.Debugf("Ingress %s : Spec differences %s", curIngress.Name, specDiffs)
-				_, err = controller.kubeclientset.NetworkingV1().Ingresses(vmo.Namespace).Update(context.TODO(), curIngress, metav1.UpdateOptions{})
+				log.Debugf("Ingress %s : Spec differences %s", curIngress.Name, specDiffs)
+				_, err = controller.kubeclientset.NetworkingV1().Ingresses(vmo.Namespace).Update(ctx, curIngress, metav1.UpdateOptions{})
 			}
 		} else if k8serrors.IsNotFound(err) {
-			_, err = controller.kubeclientset.NetworkingV1().Ingresses(vmo.Namespace).Create(context.TODO(), curIngress, metav1.CreateOptions{})
+			_, err = controller.kubeclientset.NetworkingV1().Ingresses(vmo.Namespace).Create(ctx, curIngress, metav1.CreateOptions{})
 		} else {
-			controller.log.Errorf("Failed getting existing Ingress %s/%s: %v", vmo.Namespace, ingName, err)
+			log.Errorf("Failed getting existing Ingress %s/%s: %v", vmo.Namespace, ingName, err)
 			functionMetric.IncError()
 			return err
 		}
 
 		if err != nil {
-			controller.log.Errorf("Failed to create/update Ingress %s/%s: %v", vmo.Namespace, ingName, err)
+			log.Errorf("Failed to create/update Ingress %s/%s: %v", vmo.Namespace, ingName, err)
 			functionMetric.IncError()
 			return err
 		}
 	}
 	// Delete ingresses that shouldn't exist
-	controller.log.Oncef("Deleting unwanted Ingresses for VMI %s", vmo.Name)
+	log.Oncef("Deleting unwanted Ingresses for VMI %s", vmo.Name)
 	for _, ingress := range existingIngressList {
 		if !contains(ingressNames, ingress.Name) {
-			controller.log.Oncef("Deleting ingress %s", ingress.Name)
-			err := controller.kubeclientset.NetworkingV1().Ingresses(vmo.Namespace).Delete(context.TODO(), ingress.Name, metav1.DeleteOptions{})
+			log.Oncef("Deleting ingress %s", ingress.Name)
+			err := controller.kubeclientset.NetworkingV1().Ingresses(vmo.Namespace).Delete(ctx, ingress.Name, metav1.DeleteOptions{})
 			if err != nil {
-				controller.log.Errorf("Failed to delete Ingress %s/%s: %v", vmo.Namespace, ingress.Name, err)
+				log.Errorf("Failed to delete Ingress %s/%s: %v", vmo.Namespace, ingress.Name, err)
 				return err
 			}
 			metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesIngressDeleted)
@@ -0,0 +1,97 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+// EnableLeaderElection makes the controller acquire the leader election Lease in the namespace of the operator before
+// it processes the workqueue, so that only one of several replicas of the operator reconciles the VMIs. The other
+// replicas keep their informer caches synced, to take over quickly when the leader goes away.
+func (c *Controller) EnableLeaderElection() {
+	c.leaderElection = true
+	c.leaderElectionConfig = leaderElectionConfig{
+		identity:      leaderElectionIdentity(),
+		leaseDuration: constants.LeaderElectionLeaseDuration,
+		renewDeadline: constants.LeaderElectionRenewDeadline,
+		retryPeriod:   constants.LeaderElectionRetryPeriod,
+	}
+}
+
+// runAsLeader blocks until the Lease is acquired, then calls run with a context cancelled when the leadership is lost.
+// It returns once stopCh is closed, or with an error if the leadership is lost, so that the replica stops processing
+// the workqueue as soon as another replica may have taken over.
+func (c *Controller) runAsLeader(run func(ctx context.Context)) error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Name:      constants.LeaderElectionLeaseName,
+				Namespace: c.namespace,
+			},
+			Client:     c.kubeclientset.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{Identity: c.leaderElectionConfig.identity},
+		},
+		LeaseDuration:   c.leaderElectionConfig.leaseDuration,
+		RenewDeadline:   c.leaderElectionConfig.renewDeadline,
+		RetryPeriod:     c.leaderElectionConfig.retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            constants.LeaderElectionLeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				zap.S().Infow("Acquired the leader election lease", "identity", c.leaderElectionConfig.identity)
+				run(ctx)
+			},
+			OnStoppedLeading: func() {
+				zap.S().Infow("Released the leader election lease", "identity", c.leaderElectionConfig.identity)
+			},
+			OnNewLeader: func(identity string) {
+				if identity != c.leaderElectionConfig.identity {
+					zap.S().Infow("Waiting for the leader election lease", "leader", identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	zap.S().Infow("Acquiring the leader election lease", "namespace", c.namespace, "name", constants.LeaderElectionLeaseName)
+	elector.Run(ctx)
+
+	select {
+	case <-c.stopCh:
+		return nil
+	default:
+		return errors.New("lost the leader election lease")
+	}
+}
+
+// leaderElectionIdentity returns the identity of the replica in the leader election, the pod name with a unique suffix
+// so that a restarted replica does not take over the Lease of its previous incarnation before it expires
+func leaderElectionIdentity() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "verrazzano-monitoring-operator"
+	}
+	return hostname + "_" + string(uuid.NewUUID())
+}
@@ -0,0 +1,113 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// createLeaderElectionTestController creates a controller with leader election enabled and fast leader election
+// timings, whose Kubernetes client has the given leases
+func createLeaderElectionTestController(stopCh <-chan struct{}, leases ...*coordinationv1.Lease) *Controller {
+	controller, _ := createControllerForTesting()
+	client := fake.NewSimpleClientset()
+	for _, lease := range leases {
+		_, _ = client.CoordinationV1().Leases(lease.Namespace).Create(context.TODO(), lease, metav1.CreateOptions{})
+	}
+	controller.kubeclientset = client
+	controller.namespace = constants.VerrazzanoSystemNamespace
+	controller.stopCh = stopCh
+	controller.EnableLeaderElection()
+	controller.leaderElectionConfig.leaseDuration = time.Second
+	controller.leaderElectionConfig.renewDeadline = 500 * time.Millisecond
+	controller.leaderElectionConfig.retryPeriod = 100 * time.Millisecond
+	return controller
+}
+
+// TestRunAsLeaderGating Tests that the controller only runs once it holds the leader election Lease
+// GIVEN leader election enabled and a Lease held by another replica, which stops renewing it
+// WHEN I call runAsLeader
+// THEN run is not called while the other replica holds the Lease, and is called once the Lease expired and was
+// acquired by the controller
+func TestRunAsLeaderGating(t *testing.T) {
+	stopCh := make(chan struct{})
+	leaseDurationSeconds := int32(1)
+	renewTime := metav1.NewMicroTime(time.Now())
+	otherIdentity := "other-replica"
+	controller := createLeaderElectionTestController(stopCh, &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.LeaderElectionLeaseName, Namespace: constants.VerrazzanoSystemNamespace},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       &otherIdentity,
+			LeaseDurationSeconds: &leaseDurationSeconds,
+			AcquireTime:          &renewTime,
+			RenewTime:            &renewTime,
+		},
+	})
+
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- controller.runAsLeader(func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+		})
+	}()
+
+	select {
+	case <-started:
+		t.Fatal("run was called while another replica held the lease")
+	case <-time.After(500 * time.Millisecond):
+	}
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run was not called once the lease expired")
+	}
+	lease, err := controller.kubeclientset.CoordinationV1().Leases(constants.VerrazzanoSystemNamespace).Get(context.TODO(), constants.LeaderElectionLeaseName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, controller.leaderElectionConfig.identity, *lease.Spec.HolderIdentity)
+
+	close(stopCh)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("runAsLeader did not return once stopped")
+	}
+}
+
+// TestRunAsLeaderNoLease Tests that the controller runs at once when no replica holds the leader election Lease
+// GIVEN leader election enabled and no Lease
+// WHEN I call runAsLeader
+// THEN the Lease is created and run is called
+func TestRunAsLeaderNoLease(t *testing.T) {
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	controller := createLeaderElectionTestController(stopCh)
+
+	started := make(chan struct{})
+	go func() {
+		_ = controller.runAsLeader(func(ctx context.Context) {
+			close(started)
+			<-ctx.Done()
+		})
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run was not called")
+	}
+	lease, err := controller.kubeclientset.CoordinationV1().Leases(constants.VerrazzanoSystemNamespace).Get(context.TODO(), constants.LeaderElectionLeaseName, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, controller.leaderElectionConfig.identity, *lease.Spec.HolderIdentity)
+}
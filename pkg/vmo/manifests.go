@@ -0,0 +1,108 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"fmt"
+	"io"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/ingresses"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/services"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/statefulsets"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+)
+
+// PrintManifests reads a VMI from the given reader and writes the Deployments, StatefulSets, Services and Ingresses
+// generated for it to the given writer as YAML documents. The cluster is never contacted, so the manifests are
+// the ones which would be created for a new VMI: existing resources, secrets and storage classes are not considered.
+func PrintManifests(reader io.Reader, writer io.Writer, operatorConfig *config.OperatorConfig) error {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return fmt.Errorf("Failed to read VMI: %v", err)
+	}
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	if err := yaml.Unmarshal(data, vmo); err != nil {
+		return fmt.Errorf("Failed to unmarshal VMI: %v", err)
+	}
+	objects, err := generateManifests(vmo, operatorConfig)
+	if err != nil {
+		return err
+	}
+	for _, object := range objects {
+		out, err := yaml.Marshal(object)
+		if err != nil {
+			return fmt.Errorf("Failed to marshal %s: %v", object.GetObjectKind().GroupVersionKind().Kind, err)
+		}
+		if _, err := fmt.Fprintf(writer, "---\n%s", out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateManifests runs the resource builders for the VMI the same way the controller does for a new VMI
+func generateManifests(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, operatorConfig *config.OperatorConfig) ([]runtime.Object, error) {
+	vmo.Spec.SecretName = vmo.Name + "-basicauth"
+	setVMOSpecDefaults(vmo, operatorConfig)
+	setPerNodeStorage(vmo)
+	vmo.Spec.NatGatewayIPs = operatorConfig.NatGatewayIPs
+	if vmo.Spec.IngressTargetDNSName == "" {
+		vmo.Spec.IngressTargetDNSName = operatorConfig.DefaultIngressTargetDNSName
+	}
+
+	var objects []runtime.Object
+	expected, err := deployments.New(vmo, nil, operatorConfig, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Deployment specs for VMI %s: %v", vmo.Name, err)
+	}
+	deploymentList := expected.Deployments
+	if resources.IsOpenSearchDashboardsManaged(vmo) {
+		if osd := deployments.NewOpenSearchDashboardsDeployment(vmo); osd != nil {
+			deploymentList = append(deploymentList, osd)
+		}
+	}
+	for _, deployment := range deploymentList {
+		deployment.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("Deployment"))
+		objects = append(objects, deployment)
+	}
+
+	initialMasterNodes := nodes.InitialMasterNodes(vmo.Name, nodes.MasterNodes(vmo))
+	statefulSetList, err := statefulsets.New(vzlog.DefaultLogger(), vmo, nil, initialMasterNodes)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create StatefulSet specs for VMI %s: %v", vmo.Name, err)
+	}
+	for _, sts := range statefulSetList {
+		sts.SetGroupVersionKind(appsv1.SchemeGroupVersion.WithKind("StatefulSet"))
+		objects = append(objects, sts)
+	}
+
+	serviceList, err := services.New(vmo, true)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Service specs for VMI %s: %v", vmo.Name, err)
+	}
+	for _, service := range serviceList {
+		service.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("Service"))
+		objects = append(objects, service)
+	}
+
+	ingressList, err := ingresses.New(vmo, map[string]*netv1.Ingress{})
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Ingress specs for VMI %s: %v", vmo.Name, err)
+	}
+	for _, ingress := range ingressList {
+		ingress.SetGroupVersionKind(netv1.SchemeGroupVersion.WithKind("Ingress"))
+		objects = append(objects, ingress)
+	}
+	return objects, nil
+}
@@ -0,0 +1,70 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+)
+
+const testManifestsVMI = `apiVersion: verrazzano.io/v1
+kind: VerrazzanoMonitoringInstance
+metadata:
+  name: system
+  namespace: verrazzano-system
+spec:
+  uri: vmi.system.example.com
+  grafana:
+    enabled: true
+    replicas: 1
+  opensearch:
+    enabled: true
+    masterNode:
+      replicas: 3
+      storage:
+        size: 50Gi
+    ingestNode:
+      replicas: 1
+    dataNode:
+      replicas: 2
+      storage:
+        size: 50Gi
+`
+
+// TestPrintManifests Tests printing the manifests generated for a VMI
+// GIVEN a VMI with Grafana and OpenSearch enabled
+// WHEN I call PrintManifests
+// THEN the Deployments, StatefulSets, Services and Ingresses generated for the VMI are printed as YAML documents
+func TestPrintManifests(t *testing.T) {
+	var out bytes.Buffer
+	err := PrintManifests(strings.NewReader(testManifestsVMI), &out, config.NewDefaultConfig())
+	assert.NoError(t, err)
+
+	manifests := out.String()
+	assert.True(t, strings.HasPrefix(manifests, "---\n"))
+	assert.Contains(t, manifests, "kind: Deployment")
+	assert.Contains(t, manifests, "kind: StatefulSet")
+	assert.Contains(t, manifests, "kind: Service")
+	assert.Contains(t, manifests, "kind: Ingress")
+	assert.Contains(t, manifests, "name: vmi-system-grafana\n")
+	assert.Contains(t, manifests, "name: vmi-system-es-master\n")
+	assert.Contains(t, manifests, "name: vmi-system-es-ingest\n")
+	assert.Contains(t, manifests, "name: vmi-system-es-data-0\n")
+	assert.Contains(t, manifests, "name: vmi-system-es-data-1\n")
+}
+
+// TestPrintManifestsInvalidVMI Tests printing the manifests for input which is not a VMI
+// GIVEN input which cannot be unmarshalled into a VMI
+// WHEN I call PrintManifests
+// THEN an error is returned and nothing is printed
+func TestPrintManifestsInvalidVMI(t *testing.T) {
+	var out bytes.Buffer
+	err := PrintManifests(strings.NewReader("spec: [invalid"), &out, config.NewDefaultConfig())
+	assert.Error(t, err)
+	assert.Empty(t, out.String())
+}
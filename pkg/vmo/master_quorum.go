@@ -0,0 +1,85 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// masterQuorumReason is the reason of the Warning event recorded when the master nodes of the VMI cannot keep a
+	// quorum through the loss of a master node
+	masterQuorumReason = "MasterQuorumAtRisk"
+	// masterQuorumRestoredReason is the reason of the Normal event recorded when the master nodes of the VMI can keep
+	// a quorum again
+	masterQuorumRestoredReason = "MasterQuorumRestored"
+)
+
+// warningTracker tracks the last warning of the VMIs, such as their master quorum warning, so that an event is only
+// recorded when the warning of a VMI changes. The zero value is ready to use.
+type warningTracker struct {
+	keyedTracker[string]
+}
+
+// update records the warning of the given key, an empty warning if there is none, and returns whether the warning
+// changed
+func (t *warningTracker) update(key, warning string) bool {
+	changed := false
+	t.modify(key, func(last string, _ bool) (string, bool) {
+		changed = last != warning
+		return warning, warning != ""
+	})
+	return changed
+}
+
+// EnableStrictMasterQuorum makes the controller refuse to create or update the OpenSearch cluster of a VMI whose
+// master nodes are at risk of losing their quorum, instead of only warning
+func (c *Controller) EnableStrictMasterQuorum() {
+	c.strictMasterQuorum = true
+}
+
+// checkMasterQuorum records a Warning event if the master nodes of a multi-node OpenSearch cluster are fewer than 3,
+// or are even, as the cluster then cannot elect a master after losing a master node. An error is returned instead of
+// proceeding if the strict master quorum is enabled. A single-node cluster is not checked. The events are only
+// recorded when the warning of the VMI changes, and a Normal event is recorded once the warning is resolved.
+func checkMasterQuorum(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
+	message := masterQuorumWarning(nodes.GetNodeCount(vmo))
+	changed := controller.masterQuorumWarnings.update(vmo.Namespace+"/"+vmo.Name, message)
+	if message == "" {
+		if changed && controller.recorder != nil {
+			controller.recorder.Event(vmo, corev1.EventTypeNormal, masterQuorumRestoredReason,
+				"the OpenSearch master nodes are no longer at risk of losing their quorum")
+		}
+		return nil
+	}
+	if changed && controller.recorder != nil {
+		controller.recorder.Event(vmo, corev1.EventTypeWarning, masterQuorumReason, message)
+	}
+	if controller.strictMasterQuorum {
+		return fmt.Errorf("not creating the OpenSearch cluster of VMI %s/%s: %s", vmo.Namespace, vmo.Name, message)
+	}
+	log.Oncef("VMI %s/%s: %s", vmo.Namespace, vmo.Name, message)
+	return nil
+}
+
+// masterQuorumWarning returns why the master nodes of the cluster are at risk of losing their quorum, or an empty
+// string if they are not
+func masterQuorumWarning(nodeCount *nodes.NodeCount) string {
+	if nodeCount.MasterNodes == 0 || nodeCount.Replicas <= 1 {
+		return ""
+	}
+	if nodeCount.MasterNodes%2 == 0 {
+		return fmt.Sprintf("the OpenSearch cluster has an even number of %d master nodes, which risks a split brain, use an odd number of master nodes", nodeCount.MasterNodes)
+	}
+	if nodeCount.MasterNodes < 3 {
+		return fmt.Sprintf("the OpenSearch cluster has %d master node, which is a single point of failure, use at least 3 master nodes", nodeCount.MasterNodes)
+	}
+	return ""
+}
@@ -0,0 +1,124 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// setMasterQuorumTestNodes sets the OpenSearch nodes of the VMI to the given master and data replicas
+func setMasterQuorumTestNodes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, masters, data int32) {
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.Opensearch.MasterNode = vmcontrollerv1.ElasticsearchNode{Name: "es-master", Replicas: masters, Roles: []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole}}
+	vmo.Spec.Opensearch.DataNode = vmcontrollerv1.ElasticsearchNode{Name: "es-data", Replicas: data, Roles: []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole}}
+}
+
+// TestCheckMasterQuorumEven Tests the master quorum warning for an even number of master nodes
+// GIVEN a VMI with 4 master nodes
+// WHEN I call checkMasterQuorum
+// THEN a Warning event about the even number of master nodes is recorded, and no error is returned
+func TestCheckMasterQuorumEven(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = recorder
+	setMasterQuorumTestNodes(vmo, 4, 3)
+
+	assert.NoError(t, checkMasterQuorum(context.TODO(), controller, vmo))
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "Warning "+masterQuorumReason)
+	assert.Contains(t, event, "even number of 4 master nodes")
+}
+
+// TestCheckMasterQuorumLow Tests the master quorum warning for fewer than 3 master nodes
+// GIVEN a VMI with a single master node and 2 data nodes
+// WHEN I call checkMasterQuorum
+// THEN a Warning event about the single point of failure is recorded, and no error is returned
+func TestCheckMasterQuorumLow(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = recorder
+	setMasterQuorumTestNodes(vmo, 1, 2)
+
+	assert.NoError(t, checkMasterQuorum(context.TODO(), controller, vmo))
+	assert.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "has 1 master node, which is a single point of failure")
+}
+
+// TestCheckMasterQuorumStrict Tests that the strict master quorum blocks a cluster at risk of losing its quorum
+// GIVEN a VMI with 2 master nodes, and the strict master quorum enabled
+// WHEN I call checkMasterQuorum
+// THEN a Warning event is recorded and an error is returned
+func TestCheckMasterQuorumStrict(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = recorder
+	controller.EnableStrictMasterQuorum()
+	setMasterQuorumTestNodes(vmo, 2, 0)
+
+	assert.ErrorContains(t, checkMasterQuorum(context.TODO(), controller, vmo), "even number of 2 master nodes")
+	assert.Len(t, recorder.Events, 1)
+}
+
+// TestCheckMasterQuorumHealthy Tests that no warning is recorded for master nodes which can keep their quorum
+// GIVEN VMIs with 3 or 5 master nodes, or a single-node cluster
+// WHEN I call checkMasterQuorum, with the strict master quorum enabled
+// THEN no event is recorded and no error is returned
+func TestCheckMasterQuorumHealthy(t *testing.T) {
+	tests := []struct {
+		name    string
+		masters int32
+		data    int32
+	}{
+		{"three masters", 3, 2},
+		{"five masters", 5, 0},
+		{"single node", 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, vmo := createControllerForTesting()
+			recorder := record.NewFakeRecorder(10)
+			controller.recorder = recorder
+			controller.EnableStrictMasterQuorum()
+			setMasterQuorumTestNodes(vmo, tt.masters, tt.data)
+
+			assert.NoError(t, checkMasterQuorum(context.TODO(), controller, vmo))
+			assert.Len(t, recorder.Events, 0)
+		})
+	}
+}
+
+// TestCheckMasterQuorumTransitions Tests that the master quorum events are only recorded when the warning changes
+// GIVEN a VMI with 2 master nodes, then 4 master nodes, then 3 master nodes
+// WHEN I call checkMasterQuorum twice for each master node count
+// THEN a Warning event is recorded for the first check of 2 and 4 master nodes, and a Normal event for the first
+// check of 3 master nodes
+func TestCheckMasterQuorumTransitions(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = recorder
+
+	var tests = []struct {
+		masters       int32
+		expectedEvent string
+	}{
+		{2, "Warning " + masterQuorumReason + " the OpenSearch cluster has an even number of 2 master nodes"},
+		{4, "Warning " + masterQuorumReason + " the OpenSearch cluster has an even number of 4 master nodes"},
+		{3, "Normal " + masterQuorumRestoredReason + " the OpenSearch master nodes are no longer at risk"},
+	}
+	for _, tt := range tests {
+		setMasterQuorumTestNodes(vmo, tt.masters, 2)
+		for i := 0; i < 2; i++ {
+			assert.NoError(t, checkMasterQuorum(context.TODO(), controller, vmo))
+		}
+		assert.Len(t, recorder.Events, 1)
+		assert.True(t, strings.HasPrefix(<-recorder.Events, tt.expectedEvent), tt.expectedEvent)
+	}
+}
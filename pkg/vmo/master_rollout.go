@@ -0,0 +1,52 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"sort"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/statefulsets"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// advanceMasterRollouts lets the next master pod of a staged rollout be updated, by lowering the partition of its
+// StatefulSet. The partition is only lowered when the pods of every master StatefulSet are settled and the cluster is
+// green, and only for one StatefulSet at a time so that a single master is restarted at once.
+func advanceMasterRollouts(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingList []*appsv1.StatefulSet) error {
+	log := reconcileLog(ctx)
+	if !vmo.Spec.Opensearch.StagedMasterRollout {
+		return nil
+	}
+	sorted := make([]*appsv1.StatefulSet, len(existingList))
+	copy(sorted, existingList)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	var next *appsv1.StatefulSet
+	var partition int32
+	for _, sts := range sorted {
+		if !statefulsets.IsRolloutSettled(sts) {
+			return nil
+		}
+		if p, ok := statefulsets.NextRolloutPartition(sts); ok && next == nil {
+			next, partition = sts, p
+		}
+	}
+	if next == nil {
+		return nil
+	}
+	if err := controller.osClient.WithContext(ctx).IsGreen(vmo); err != nil {
+		log.Progressf("Waiting for OpenSearch to be green before updating the next pod of StatefulSet %s/%s: %v", next.Namespace, next.Name, err)
+		return nil
+	}
+	log.Infof("Updating pod %s-%d of StatefulSet %s/%s", next.Name, partition, next.Namespace, next.Name)
+	updated := next.DeepCopy()
+	updated.Spec.UpdateStrategy.RollingUpdate.Partition = resources.NewVal(partition)
+	_, err := controller.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// createRolloutTestSTS creates a master StatefulSet whose staged rollout is at the given partition, with settled pods
+func createRolloutTestSTS(name string, replicas, partition int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "verrazzano-system"},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: resources.NewVal(replicas),
+			UpdateStrategy: appsv1.StatefulSetUpdateStrategy{
+				Type:          appsv1.RollingUpdateStatefulSetStrategyType,
+				RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{Partition: resources.NewVal(partition)},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: replicas, AvailableReplicas: replicas, UpdatedReplicas: replicas - partition},
+	}
+}
+
+// TestAdvanceMasterRollouts Tests advancing the staged rollouts of the master StatefulSets
+// GIVEN a VMI with staged master rollouts, and master StatefulSets with rollouts in progress
+// WHEN I call advanceMasterRollouts
+// THEN the partition of a single StatefulSet is lowered by one, only when its pods are settled and the cluster is green
+func TestAdvanceMasterRollouts(t *testing.T) {
+	notReady := createRolloutTestSTS("es-master-b", 3, 2)
+	notReady.Status.ReadyReplicas = 2
+	notReady.Status.AvailableReplicas = 2
+	var tests = []struct {
+		name               string
+		staged             bool
+		health             string
+		existing           []*appsv1.StatefulSet
+		expectedPartitions map[string]int32
+	}{
+		{
+			"first StatefulSet with a rollout in progress is advanced when green",
+			true,
+			"green",
+			[]*appsv1.StatefulSet{createRolloutTestSTS("es-master-b", 3, 2), createRolloutTestSTS("es-master-a", 3, 1)},
+			map[string]int32{"es-master-a": 0, "es-master-b": 2},
+		},
+		{
+			"rollout is not advanced when the cluster is not green",
+			true,
+			"yellow",
+			[]*appsv1.StatefulSet{createRolloutTestSTS("es-master-a", 3, 2)},
+			map[string]int32{"es-master-a": 2},
+		},
+		{
+			"rollout is not advanced while a master pod is not ready",
+			true,
+			"green",
+			[]*appsv1.StatefulSet{createRolloutTestSTS("es-master-a", 3, 2), notReady},
+			map[string]int32{"es-master-a": 2, "es-master-b": 2},
+		},
+		{
+			"rollout is not advanced when staged rollouts are disabled",
+			false,
+			"green",
+			[]*appsv1.StatefulSet{createRolloutTestSTS("es-master-a", 3, 2)},
+			map[string]int32{"es-master-a": 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, vmo := createControllerForTesting()
+			vmo.Spec.Opensearch.Enabled = true
+			vmo.Spec.Opensearch.StagedMasterRollout = tt.staged
+			client := fake.NewSimpleClientset()
+			for _, sts := range tt.existing {
+				_, err := client.AppsV1().StatefulSets(sts.Namespace).Create(context.TODO(), sts, metav1.CreateOptions{})
+				assert.NoError(t, err)
+			}
+			controller.kubeclientset = client
+			osClient := opensearch.NewOSClient(controller.statefulSetLister)
+			osClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+				body := `{"nodes": {}}`
+				if strings.Contains(request.URL.Path, "_cluster/health") {
+					body = `{"status": "` + tt.health + `"}`
+				}
+				return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+			}
+			controller.osClient = osClient
+
+			assert.NoError(t, advanceMasterRollouts(context.TODO(), controller, vmo, tt.existing))
+			for name, partition := range tt.expectedPartitions {
+				sts, err := client.AppsV1().StatefulSets("verrazzano-system").Get(context.TODO(), name, metav1.GetOptions{})
+				assert.NoError(t, err)
+				assert.Equal(t, partition, *sts.Spec.UpdateStrategy.RollingUpdate.Partition, name)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package vmo
 
 import (
+	"context"
 	"strconv"
 	"testing"
 	"time"
@@ -51,7 +52,7 @@ func TestInitializeAllMetricsArray(t *testing.T) {
 	assert := assert.New(t)
 	metricsexporter.TestDelegate.InitializeAllMetricsArray()
 	//This number should correspond to the number of total metrics, including metrics inside of metric maps
-	assert.Equal(30, len(*allMetrics), "There may be new metrics in the map, or some metrics may not be added to the allmetrics array from the metrics maps")
+	assert.Equal(33, len(*allMetrics), "There may be new metrics in the map, or some metrics may not be added to the allmetrics array from the metrics maps")
 }
 
 // TestNoMetrics, TestValid & TestInvalid tests that metrics in the allmetrics array are registered and failedMetrics are retried
@@ -185,12 +186,13 @@ func createControllerForTesting() (*Controller, *vmctl.VerrazzanoMonitoringInsta
 		pvcLister:           kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), constants.ResyncPeriod).Core().V1().PersistentVolumeClaims().Lister(),
 		statefulSetLister:   statefulSetLister,
 		ingressLister:       kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), constants.ResyncPeriod).Networking().V1().Ingresses().Lister(),
+		networkPolicyLister: kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), constants.ResyncPeriod).Networking().V1().NetworkPolicies().Lister(),
 		vmoclientset:        vmofake.NewSimpleClientset(),
 		workqueue:           workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "VMOs"),
 		osClient:            opensearch.NewOSClient(statefulSetLister),
 		osDashboardsClient:  dashboards.NewOSDashboardsClient(),
 	}
-	_ = createUpdateDatasourcesConfigMap(controller, vmo, configMapName, map[string]string{})
+	_ = createUpdateDatasourcesConfigMap(context.TODO(), controller, vmo, configMapName, map[string]string{})
 
 	return controller, vmo
 }
@@ -206,9 +208,10 @@ func TestReconcileAndUpdateMetrics(t *testing.T) {
 
 	metricsexporter.DefaultLabelFunction = func(idx int64) string { return "1" }
 	previousCount := testutil.ToFloat64(delegate.GetFunctionCounterMetric(metricsexporter.NamesReconcile))
+	previousErrorCount := testutil.ToFloat64(delegate.GetFunctionErrorMetric(metricsexporter.NamesReconcile).WithLabelValues("1"))
 	previousUpdateCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesVMOUpdate))
 
-	controller.syncHandlerStandardMode(vmo)
+	controller.syncHandlerStandardMode(context.TODO(), vmo)
 
 	newTimeStamp := testutil.ToFloat64(delegate.GetFunctionTimestampMetric(metricsexporter.NamesReconcile).WithLabelValues("1"))
 	newErrorCount := testutil.ToFloat64(delegate.GetFunctionErrorMetric(metricsexporter.NamesReconcile).WithLabelValues("1"))
@@ -216,7 +219,7 @@ func TestReconcileAndUpdateMetrics(t *testing.T) {
 	newUpdateCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesVMOUpdate))
 
 	assert.Equal(t, previousCount, float64(newCount-1))
-	assert.Equal(t, newErrorCount, float64(1))
+	assert.Equal(t, previousErrorCount, float64(newErrorCount-1))
 	assert.Equal(t, previousUpdateCount, float64(newUpdateCount-1))
 	assert.LessOrEqual(t, int64(newTimeStamp*10)/10, time.Now().Unix())
 }
@@ -233,7 +236,7 @@ func TestDeploymentMetrics(t *testing.T) {
 	metricsexporter.DefaultLabelFunction = func(idx int64) string { return "1" }
 	previousCount := testutil.ToFloat64(delegate.GetFunctionCounterMetric(metricsexporter.NamesDeployment))
 
-	CreateDeployments(controller, vmo, map[string]string{}, true)
+	CreateDeployments(context.TODO(), controller, vmo, map[string]string{}, true)
 
 	newTimeStamp := testutil.ToFloat64(delegate.GetFunctionTimestampMetric(metricsexporter.NamesDeployment).WithLabelValues("1"))
 	newCount := testutil.ToFloat64(delegate.GetFunctionCounterMetric(metricsexporter.NamesDeployment))
@@ -247,7 +250,7 @@ func TestIngressMetrics(t *testing.T) {
 	controller, vmo := createControllerForTesting()
 	metricsexporter.DefaultLabelFunction = func(idx int64) string { return "1" }
 	previousCount := testutil.ToFloat64(delegate.GetFunctionCounterMetric(metricsexporter.NamesIngress))
-	CreateIngresses(controller, vmo)
+	CreateIngresses(context.TODO(), controller, vmo)
 	newTimeStamp := testutil.ToFloat64(delegate.GetFunctionTimestampMetric(metricsexporter.NamesIngress).WithLabelValues("1"))
 	newCount := testutil.ToFloat64(delegate.GetFunctionCounterMetric(metricsexporter.NamesIngress))
 	assert.Equal(t, previousCount, float64(newCount-1))
@@ -257,7 +260,7 @@ func TestIngressMetrics(t *testing.T) {
 func TestRoleBindingMetrics(t *testing.T) {
 	controller, vmo := createControllerForTesting()
 	previousCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesRoleBindings))
-	CreateRoleBindings(controller, vmo)
+	CreateRoleBindings(context.TODO(), controller, vmo)
 	newCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesRoleBindings))
 	assert.Equal(t, previousCount, float64(newCount-1))
 }
@@ -274,7 +277,7 @@ func TestThreadingMetrics(t *testing.T) {
 func TestConfigMapMetrics(t *testing.T) {
 	controller, vmo := createControllerForTesting()
 	previousCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesConfigMap))
-	CreateConfigmaps(controller, vmo)
+	CreateConfigmaps(context.TODO(), controller, vmo)
 	newCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesConfigMap))
 	newTimeStamp := testutil.ToFloat64(delegate.GetFunctionTimestampMetric(metricsexporter.NamesIngress).WithLabelValues(strconv.FormatInt(int64(previousCount)+1, 10)))
 	assert.Equal(t, previousCount, float64(newCount-1))
@@ -284,7 +287,7 @@ func TestServiceMetrics(t *testing.T) {
 	clearMetrics()
 	controller, vmo := createControllerForTesting()
 	previousCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesServices))
-	CreateServices(controller, vmo)
+	CreateServices(context.TODO(), controller, vmo)
 	newCount := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesServices))
 	newServicesCreated := testutil.ToFloat64(delegate.GetCounterMetric(metricsexporter.NamesServicesCreated))
 	newTimeStamp := testutil.ToFloat64(delegate.GetTimestampMetric(metricsexporter.NamesServices).WithLabelValues(strconv.FormatInt(int64(previousCount)+1, 10)))
@@ -293,6 +296,41 @@ func TestServiceMetrics(t *testing.T) {
 	assert.LessOrEqual(t, int64(newTimeStamp*10)/10, time.Now().Unix())
 }
 
+// TestInformerSyncedMetrics tests that the informer synced gauges are set when the caches are synced
+// GIVEN a controller whose informers have all synced
+//
+//	WHEN I call waitForCacheSync
+//	THEN the informer synced gauge is set to 1 for each informer
+func TestInformerSyncedMetrics(t *testing.T) {
+	controller, _ := createControllerForTesting()
+	synced := func() bool { return true }
+	controller.clusterRolesSynced = synced
+	controller.configMapsSynced = synced
+	controller.deploymentsSynced = synced
+	controller.ingressesSynced = synced
+	controller.networkPoliciesSynced = synced
+	controller.nodesSynced = synced
+	controller.pvcsSynced = synced
+	controller.roleBindingsSynced = synced
+	controller.secretsSynced = synced
+	controller.servicesSynced = synced
+	controller.statefulSetsSynced = synced
+	controller.vmosSynced = synced
+	controller.storageClassesSynced = synced
+	controller.stopCh = make(chan struct{})
+
+	assert.True(t, controller.waitForCacheSync())
+	gauge := delegate.GetLabeledGaugeMetric(metricsexporter.NamesInformerSynced)
+	for name := range controller.informerSyncs() {
+		assert.Equal(t, float64(1), testutil.ToFloat64(gauge.WithLabelValues(name)), "informer %s should be synced", name)
+	}
+
+	// an informer which is no longer synced is reported as 0
+	controller.vmosSynced = func() bool { return false }
+	controller.updateInformerSyncedMetrics()
+	assert.Equal(t, float64(0), testutil.ToFloat64(gauge.WithLabelValues("vmos")))
+}
+
 // helper function to ensure consistency between tests
 func clearMetrics() {
 	*allMetrics = []prometheus.Collector{}
@@ -0,0 +1,68 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+
+	"github.com/verrazzano/pkg/diff"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/metricsexporter"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/networkpolicies"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// CreateNetworkPolicies creates/updates/deletes VMO NetworkPolicy k8s resources
+func CreateNetworkPolicies(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
+	metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesNetworkPolicies)
+	if metricErr != nil {
+		return metricErr
+	}
+	metric.Inc()
+
+	var networkPolicyNames []string
+	log.Oncef("Creating/updating NetworkPolicies for VMI %s", vmo.Name)
+	for _, curNetworkPolicy := range networkpolicies.New(vmo, controller.namespace) {
+		networkPolicyNames = append(networkPolicyNames, curNetworkPolicy.Name)
+		existingNetworkPolicy, err := controller.networkPolicyLister.NetworkPolicies(vmo.Namespace).Get(curNetworkPolicy.Name)
+		if existingNetworkPolicy != nil {
+			specDiffs := diff.Diff(existingNetworkPolicy, curNetworkPolicy)
+			if specDiffs != "" {
+				log.Debugf("NetworkPolicy %s : Spec differences %s", curNetworkPolicy.Name, specDiffs)
+				_, err = controller.kubeclientset.NetworkingV1().NetworkPolicies(vmo.Namespace).Update(ctx, curNetworkPolicy, metav1.UpdateOptions{})
+			}
+		} else if k8serrors.IsNotFound(err) {
+			_, err = controller.kubeclientset.NetworkingV1().NetworkPolicies(vmo.Namespace).Create(ctx, curNetworkPolicy, metav1.CreateOptions{})
+		} else {
+			log.Errorf("Failed getting existing NetworkPolicy %s/%s: %v", vmo.Namespace, curNetworkPolicy.Name, err)
+			return err
+		}
+		if err != nil {
+			log.Errorf("Failed to create/update NetworkPolicy %s/%s: %v", vmo.Namespace, curNetworkPolicy.Name, err)
+			return err
+		}
+	}
+
+	// Delete NetworkPolicies that shouldn't exist
+	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
+	existingNetworkPolicies, err := controller.networkPolicyLister.NetworkPolicies(vmo.Namespace).List(selector)
+	if err != nil {
+		return err
+	}
+	for _, networkPolicy := range existingNetworkPolicies {
+		if !contains(networkPolicyNames, networkPolicy.Name) {
+			log.Oncef("Deleting NetworkPolicy %s", networkPolicy.Name)
+			err := controller.kubeclientset.NetworkingV1().NetworkPolicies(vmo.Namespace).Delete(ctx, networkPolicy.Name, metav1.DeleteOptions{})
+			if err != nil {
+				log.Errorf("Failed to delete NetworkPolicy %s/%s: %v", vmo.Namespace, networkPolicy.Name, err)
+				return err
+			}
+		}
+	}
+	return nil
+}
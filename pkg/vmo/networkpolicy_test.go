@@ -0,0 +1,74 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/networkpolicies"
+	netv1 "k8s.io/api/networking/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestCreateNetworkPolicies tests that the OpenSearch NetworkPolicy is created and updated
+// GIVEN a VMI with OpenSearch enabled and a network policy
+// WHEN I call CreateNetworkPolicies without and then with an outdated existing NetworkPolicy
+// THEN the NetworkPolicy is created and then updated to match the VMI spec
+func TestCreateNetworkPolicies(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.Opensearch.NetworkPolicy = &vmcontrollerv1.OpenSearchNetworkPolicy{AllowedNamespaces: []string{"verrazzano-monitoring"}}
+
+	client := fake.NewSimpleClientset()
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Networking().V1().NetworkPolicies()
+	controller.kubeclientset = client
+	controller.networkPolicyLister = informer.Lister()
+
+	assert.NoError(t, CreateNetworkPolicies(context.TODO(), controller, vmo))
+	expected := networkpolicies.New(vmo, controller.namespace)[0]
+	created, err := client.NetworkingV1().NetworkPolicies(vmo.Namespace).Get(context.TODO(), expected.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Spec, created.Spec)
+
+	// an outdated NetworkPolicy is updated
+	outdated := created.DeepCopy()
+	outdated.Spec.Ingress = []netv1.NetworkPolicyIngressRule{}
+	_, err = client.NetworkingV1().NetworkPolicies(vmo.Namespace).Update(context.TODO(), outdated, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, informer.Informer().GetIndexer().Add(outdated))
+
+	assert.NoError(t, CreateNetworkPolicies(context.TODO(), controller, vmo))
+	updated, err := client.NetworkingV1().NetworkPolicies(vmo.Namespace).Get(context.TODO(), expected.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Spec, updated.Spec)
+}
+
+// TestDeleteNetworkPolicies tests that the OpenSearch NetworkPolicy is deleted once the network policy is removed from the VMI
+// GIVEN an existing OpenSearch NetworkPolicy and a VMI without a network policy
+// WHEN I call CreateNetworkPolicies
+// THEN the NetworkPolicy is deleted
+func TestDeleteNetworkPolicies(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.Opensearch.NetworkPolicy = &vmcontrollerv1.OpenSearchNetworkPolicy{}
+	existing := networkpolicies.New(vmo, controller.namespace)[0]
+	vmo.Spec.Opensearch.NetworkPolicy = nil
+
+	client := fake.NewSimpleClientset(existing)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Networking().V1().NetworkPolicies()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	controller.kubeclientset = client
+	controller.networkPolicyLister = informer.Lister()
+
+	assert.NoError(t, CreateNetworkPolicies(context.TODO(), controller, vmo))
+	_, err := client.NetworkingV1().NetworkPolicies(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+	assert.True(t, k8serrors.IsNotFound(err))
+}
@@ -0,0 +1,76 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"time"
+
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/tools/cache"
+)
+
+// EnableNodeTopologyReconcile makes the controller reconcile the VMIs with OpenSearch enabled when the topology labels
+// of a node change, so the placement of the data nodes across the availability domains is re-evaluated. The VMIs are
+// enqueued once the debounce period has passed since the first change, so that the changes of many nodes, e.g. while
+// the cluster scales, are reconciled together.
+func (c *Controller) EnableNodeTopologyReconcile(debounce time.Duration) {
+	c.reconcileOnNodeTopology = true
+	c.nodeTopologyDebounce = debounce
+}
+
+// handleNodeUpdate enqueues the VMIs with OpenSearch enabled when the topology labels of a node change. Resyncs and
+// the changes of the other labels of the node are ignored.
+func (c *Controller) handleNodeUpdate(old, new interface{}) {
+	if !c.reconcileOnNodeTopology {
+		return
+	}
+	oldNode, ok := old.(*corev1.Node)
+	if !ok {
+		return
+	}
+	newNode, ok := new.(*corev1.Node)
+	if !ok {
+		return
+	}
+	if newNode.ResourceVersion == oldNode.ResourceVersion || !c.topologyLabelsChanged(oldNode, newNode) {
+		return
+	}
+	vmos, err := c.vmoLister.List(labels.Everything())
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+	for _, vmo := range vmos {
+		if !vmo.Spec.Opensearch.Enabled {
+			continue
+		}
+		key, err := cache.MetaNamespaceKeyFunc(vmo)
+		if err != nil {
+			runtime.HandleError(err)
+			continue
+		}
+		c.log.Debugf("Topology labels of node %s changed, reconciling VMI %s", newNode.Name, key)
+		// a VMI already waiting in the queue is only enqueued once
+		c.workqueue.AddAfter(key, c.nodeTopologyDebounce)
+	}
+}
+
+// topologyLabelsChanged returns true if a topology label used to place the data nodes differs between the nodes
+func (c *Controller) topologyLabelsChanged(oldNode, newNode *corev1.Node) bool {
+	topologyLabels := []string{constants.K8sZoneLabel, corev1.LabelTopologyZone, constants.OciAvailabilityDomainLabel}
+	if c.operatorConfig != nil && c.operatorConfig.Pvcs.ZoneMatchLabel != "" {
+		topologyLabels = append(topologyLabels, c.operatorConfig.Pvcs.ZoneMatchLabel)
+	}
+	for _, label := range topologyLabels {
+		oldValue, oldOk := oldNode.Labels[label]
+		newValue, newOk := newNode.Labels[label]
+		if oldOk != newOk || oldValue != newValue {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	vmofake "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/clientset/versioned/fake"
+	informers "github.com/verrazzano/verrazzano-monitoring-operator/pkg/client/informers/externalversions"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// createNodeTopologyTestController creates a controller with a VMI with OpenSearch enabled and a VMI with OpenSearch
+// disabled in its VMI lister
+func createNodeTopologyTestController(t *testing.T) *Controller {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Opensearch.Enabled = true
+	disabled := vmo.DeepCopy()
+	disabled.Name = "disabled"
+	disabled.Spec.Opensearch.Enabled = false
+	informer := informers.NewSharedInformerFactory(vmofake.NewSimpleClientset(), constants.ResyncPeriod).Verrazzano().V1().VerrazzanoMonitoringInstances()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(vmo))
+	assert.NoError(t, informer.Informer().GetIndexer().Add(disabled))
+	controller.vmoLister = informer.Lister()
+	controller.workqueue = workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "VMOs")
+	return controller
+}
+
+// createTopologyNode creates a node with the given resource version and zone label
+func createTopologyNode(resourceVersion, zone string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "node-1",
+			ResourceVersion: resourceVersion,
+			Labels:          map[string]string{corev1.LabelTopologyZone: zone, "team": "a"},
+		},
+	}
+}
+
+// TestHandleNodeUpdateTopologyChange Tests that the VMIs with OpenSearch enabled are enqueued when the zone of a node changes
+// GIVEN the node topology reconcile enabled, a VMI with OpenSearch enabled and a VMI with OpenSearch disabled
+// WHEN the zone label of a node changes several times within the debounce period
+// THEN only the VMI with OpenSearch enabled is enqueued, once, after the debounce period
+func TestHandleNodeUpdateTopologyChange(t *testing.T) {
+	controller := createNodeTopologyTestController(t)
+	controller.EnableNodeTopologyReconcile(100 * time.Millisecond)
+
+	controller.handleNodeUpdate(createTopologyNode("1", "zone-a"), createTopologyNode("2", "zone-b"))
+	controller.handleNodeUpdate(createTopologyNode("2", "zone-b"), createTopologyNode("3", "zone-c"))
+	assert.Equal(t, 0, controller.workqueue.Len())
+	assert.Eventually(t, func() bool {
+		return controller.workqueue.Len() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	key, _ := controller.workqueue.Get()
+	assert.Equal(t, constants.VerrazzanoSystemNamespace+"/"+constants.VMODefaultName, key)
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, 0, controller.workqueue.Len())
+}
+
+// TestHandleNodeUpdateIgnored Tests that no VMI is enqueued for the node updates unrelated to the topology
+// GIVEN a VMI with OpenSearch enabled
+// WHEN a node is resynced, a label other than a topology label changes, or the node topology reconcile is disabled
+// THEN the VMI is not enqueued
+func TestHandleNodeUpdateIgnored(t *testing.T) {
+	controller := createNodeTopologyTestController(t)
+	node := createTopologyNode("1", "zone-a")
+	controller.handleNodeUpdate(node, createTopologyNode("2", "zone-b"))
+
+	controller.EnableNodeTopologyReconcile(10 * time.Millisecond)
+	controller.handleNodeUpdate(node, node)
+	relabeled := createTopologyNode("2", "zone-a")
+	relabeled.Labels["team"] = "b"
+	controller.handleNodeUpdate(node, relabeled)
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, 0, controller.workqueue.Len())
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// openSearchDowngradeReason is the reason of the Warning event recorded when the OpenSearch images of the VMI are an
+// older major version than the running OpenSearch cluster
+const openSearchDowngradeReason = "OpenSearchDowngradeBlocked"
+
+// openSearchDowngradeCheck is the result of the OpenSearch downgrade check of the images of a VMI, the message
+// being empty if the images are not blocked. The last check of the VMIs is tracked, so that the running version is
+// only queried, and the event only recorded, when the OpenSearch images of a VMI change.
+type openSearchDowngradeCheck struct {
+	images  string
+	message string
+}
+
+// checkOpenSearchDowngrade returns an error, and records a Warning event, if an OpenSearch image of the VMI is an older
+// major version than the running OpenSearch cluster, as the older nodes cannot read the indices written by the newer
+// version. The check is skipped if the VMI has the allow OpenSearch downgrade annotation, and when the running version
+// is unknown, e.g. while the cluster is not ready, so that an unreachable cluster can still be repaired. The images
+// are only checked again once they change, so the event is recorded once for the blocked images.
+func checkOpenSearchDowngrade(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
+	key := vmo.Namespace + "/" + vmo.Name
+	if !vmo.Spec.Opensearch.Enabled || vmo.Annotations[constants.AllowOpenSearchDowngradeAnnotation] == "true" {
+		controller.openSearchDowngradeChecks.reset(key)
+		return nil
+	}
+	images := getOpenSearchImages(vmo)
+	checkedImages := strings.Join(images, ",")
+	if check, ok := controller.openSearchDowngradeChecks.get(key); ok && check.images == checkedImages {
+		if check.message == "" {
+			return nil
+		}
+		return fmt.Errorf("not updating the OpenSearch cluster of VMI %s/%s: %s", vmo.Namespace, vmo.Name, check.message)
+	}
+	osClient := controller.osClient.WithContext(ctx)
+	if !osClient.IsOpenSearchReady(vmo) {
+		return nil
+	}
+	runningVersion, err := osClient.RunningVersion(vmo)
+	if err != nil {
+		log.Oncef("Skipping the OpenSearch downgrade check of VMI %s/%s, failed to get the running version: %v", vmo.Namespace, vmo.Name, err)
+		return nil
+	}
+	runningMajor, ok := opensearch.MajorVersion(runningVersion)
+	if !ok {
+		return nil
+	}
+	for _, image := range images {
+		imageMajor, ok := opensearch.MajorVersion(imageTag(image))
+		if !ok || imageMajor >= runningMajor {
+			continue
+		}
+		message := fmt.Sprintf("the OpenSearch image %s is an older major version than the running OpenSearch version %s, which cannot read its indices. Annotate the VMI with %s=true to downgrade intentionally, e.g. after reindexing", image, runningVersion, constants.AllowOpenSearchDowngradeAnnotation)
+		controller.openSearchDowngradeChecks.set(key, openSearchDowngradeCheck{images: checkedImages, message: message})
+		if controller.recorder != nil {
+			controller.recorder.Event(vmo, corev1.EventTypeWarning, openSearchDowngradeReason, message)
+		}
+		return fmt.Errorf("not updating the OpenSearch cluster of VMI %s/%s: %s", vmo.Namespace, vmo.Name, message)
+	}
+	controller.openSearchDowngradeChecks.set(key, openSearchDowngradeCheck{images: checkedImages})
+	return nil
+}
+
+// getOpenSearchImages returns the OpenSearch images of the nodes of the VMI, the image of a node group defaulting to
+// the image of the operator config for its tier
+func getOpenSearchImages(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) []string {
+	var images []string
+	addImages := func(nodeList []vmcontrollerv1.ElasticsearchNode, defaultImage string) {
+		for _, node := range nodeList {
+			if node.Replicas < 1 {
+				continue
+			}
+			if node.Image != "" {
+				images = append(images, node.Image)
+			} else {
+				images = append(images, defaultImage)
+			}
+		}
+	}
+	addImages(nodes.MasterNodes(vmo), config.ElasticsearchMaster.Image)
+	addImages(nodes.DataNodes(vmo), config.ElasticsearchData.Image)
+	addImages(nodes.IngestNodes(vmo), config.ElasticsearchIngest.Image)
+	return images
+}
+
+// imageTag returns the tag of an image, without its digest, or an empty string if the image has no tag
+func imageTag(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	name := image[strings.LastIndex(image, "/")+1:]
+	_, tag, _ := strings.Cut(name, ":")
+	return tag
+}
@@ -0,0 +1,135 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/tools/record"
+)
+
+// createDowngradeTestController creates a controller whose OpenSearch cluster is ready and runs the given versions,
+// recording the OpenSearch requests it receives
+func createDowngradeTestController(t *testing.T, requests *[]string, versions ...string) (*Controller, *vmcontrollerv1.VerrazzanoMonitoringInstance, *record.FakeRecorder) {
+	controller, vmo := createControllerForTesting()
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = recorder
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.Opensearch.MasterNode = vmcontrollerv1.ElasticsearchNode{Name: "es-master", Replicas: 1, Roles: []vmcontrollerv1.NodeRole{vmcontrollerv1.MasterRole}}
+	vmo.Spec.Opensearch.DataNode = vmcontrollerv1.ElasticsearchNode{Name: "es-data", Replicas: 2, Roles: []vmcontrollerv1.NodeRole{vmcontrollerv1.DataRole}}
+
+	statefulSetInformer := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), constants.ResyncPeriod).Apps().V1().StatefulSets()
+	assert.NoError(t, statefulSetInformer.Informer().GetIndexer().Add(&appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmi-system-es-master",
+			Namespace: vmo.Namespace,
+			Labels:    map[string]string{constants.VMOLabel: vmo.Name, constants.ComponentLabel: constants.ComponentOpenSearchValue},
+		},
+		Status: appsv1.StatefulSetStatus{Replicas: 1, ReadyReplicas: 1},
+	}))
+	osClient := opensearch.NewOSClient(statefulSetInformer.Lister())
+	osClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		*requests = append(*requests, request.URL.Path)
+		var nodes []string
+		for i, version := range versions {
+			nodes = append(nodes, fmt.Sprintf(`"node-%d": {"version": "%s"}`, i, version))
+		}
+		body := `{"nodes": {` + strings.Join(nodes, ",") + `}}`
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	controller.osClient = osClient
+	return controller, vmo, recorder
+}
+
+// TestCheckOpenSearchDowngradeBlocked Tests that a downgrade to an older OpenSearch major version is blocked
+// GIVEN a cluster running OpenSearch 2.3.0, partially upgraded from 1.3.6, and a VMI whose data nodes use an OpenSearch 1 image
+// WHEN I call checkOpenSearchDowngrade twice, then with a VMI whose data nodes use another OpenSearch 1 image
+// THEN an error is returned each time, and a Warning event naming the image and the running version is only recorded,
+// and the running version only queried, when the images change
+func TestCheckOpenSearchDowngradeBlocked(t *testing.T) {
+	var requests []string
+	controller, vmo, recorder := createDowngradeTestController(t, &requests, "1.3.6", "2.3.0")
+	vmo.Spec.Opensearch.MasterNode.Image = "ghcr.io/verrazzano/opensearch:2.3.0-20230101"
+	vmo.Spec.Opensearch.DataNode.Image = "ghcr.io/verrazzano/opensearch:1.3.6-20220601@sha256:0123"
+
+	err := checkOpenSearchDowngrade(context.TODO(), controller, vmo)
+	assert.ErrorContains(t, err, "older major version than the running OpenSearch version 2.3.0")
+	assert.Equal(t, []string{"/_nodes/settings"}, requests)
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, "Warning "+openSearchDowngradeReason)
+	assert.Contains(t, event, "ghcr.io/verrazzano/opensearch:1.3.6-20220601")
+	assert.Contains(t, event, constants.AllowOpenSearchDowngradeAnnotation)
+
+	err = checkOpenSearchDowngrade(context.TODO(), controller, vmo)
+	assert.ErrorContains(t, err, "older major version than the running OpenSearch version 2.3.0")
+	assert.Len(t, requests, 1)
+	assert.Empty(t, recorder.Events)
+
+	vmo.Spec.Opensearch.DataNode.Image = "ghcr.io/verrazzano/opensearch:1.3.7-20220701"
+	err = checkOpenSearchDowngrade(context.TODO(), controller, vmo)
+	assert.ErrorContains(t, err, "ghcr.io/verrazzano/opensearch:1.3.7-20220701")
+	assert.Len(t, requests, 2)
+	assert.Len(t, recorder.Events, 1)
+}
+
+// TestCheckOpenSearchDowngradeAllowed Tests the OpenSearch images which are not blocked
+// GIVEN a cluster running OpenSearch 2.3.0
+// WHEN I call checkOpenSearchDowngrade with a VMI with the same major version, a newer major version, an image
+// without a version tag, or an older major version with the allow OpenSearch downgrade annotation
+// THEN no error is returned and no event is recorded
+func TestCheckOpenSearchDowngradeAllowed(t *testing.T) {
+	tests := []struct {
+		name        string
+		image       string
+		annotations map[string]string
+	}{
+		{"same major version", "ghcr.io/verrazzano/opensearch:2.1.0", nil},
+		{"newer major version", "ghcr.io/verrazzano/opensearch:v3.0.0", nil},
+		{"no version tag", "registry:5000/opensearch:latest", nil},
+		{"downgrade annotation", "ghcr.io/verrazzano/opensearch:1.3.6", map[string]string{constants.AllowOpenSearchDowngradeAnnotation: "true"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			controller, vmo, recorder := createDowngradeTestController(t, &requests, "2.3.0")
+			vmo.Annotations = tt.annotations
+			vmo.Spec.Opensearch.MasterNode.Image = tt.image
+			vmo.Spec.Opensearch.DataNode.Image = tt.image
+
+			assert.NoError(t, checkOpenSearchDowngrade(context.TODO(), controller, vmo))
+			assert.Empty(t, recorder.Events)
+		})
+	}
+}
+
+// TestCheckOpenSearchDowngradeNotReady Tests that the downgrade check is skipped while OpenSearch is not ready
+// GIVEN a VMI with an OpenSearch 1 image and no ready OpenSearch StatefulSet
+// WHEN I call checkOpenSearchDowngrade
+// THEN OpenSearch is not called and no error is returned
+func TestCheckOpenSearchDowngradeNotReady(t *testing.T) {
+	var requests []string
+	controller, vmo, _ := createDowngradeTestController(t, &requests, "2.3.0")
+	controller.osClient = opensearch.NewOSClient(controller.statefulSetLister)
+	controller.osClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		t.Fatalf("unexpected request %s %s", request.Method, request.URL.Path)
+		return nil, nil
+	}
+	vmo.Spec.Opensearch.MasterNode.Image = "ghcr.io/verrazzano/opensearch:1.3.6"
+
+	assert.NoError(t, checkOpenSearchDowngrade(context.TODO(), controller, vmo))
+}
@@ -0,0 +1,183 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/verrazzano/verrazzano-monitoring-operator"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"go.uber.org/zap"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// operatorRBACLabels are the labels of the RBAC resources of the VMO itself
+var operatorRBACLabels = map[string]string{"k8s-app": constants.ServiceAccountName}
+
+// operatorRules returns the rules of the ClusterRole of the VMO itself, read from the embedded VMO manifest so that the
+// manifest is their only source
+func operatorRules() ([]rbacv1.PolicyRule, error) {
+	for _, document := range strings.Split(string(verrazzanomonitoringoperator.GetEmbeddedOperatorManifest()), "\n---") {
+		var clusterRole rbacv1.ClusterRole
+		if err := yaml.Unmarshal([]byte(document), &clusterRole); err != nil {
+			return nil, fmt.Errorf("failed to parse the VMO manifest: %v", err)
+		}
+		if clusterRole.Kind == "ClusterRole" && strings.HasPrefix(clusterRole.Name, constants.ClusterRoleForVMO+"-") {
+			return clusterRole.Rules, nil
+		}
+	}
+	return nil, fmt.Errorf("the VMO manifest has no ClusterRole %s", constants.ClusterRoleForVMO)
+}
+
+// EnableOperatorRBACReconcile makes the controller reconcile the ClusterRole and the ClusterRoleBinding of the VMO itself
+// when it starts and then periodically, so that they are recreated if deleted and corrected if edited. The VMO must be
+// allowed to manage its ClusterRole and ClusterRoleBinding, as granted by the RBAC reconcile overlay of the manifest.
+func (c *Controller) EnableOperatorRBACReconcile() {
+	c.reconcileOperatorRBAC = true
+}
+
+// createOperatorRBAC reconciles the RBAC of the VMO before the workers start, then every
+// OperatorRBACReconcilePeriod until the context is done, so that drift is corrected while the VMO runs. A failure is
+// only logged, as the existing RBAC of the VMO may still allow it to reconcile the VMIs.
+func (c *Controller) createOperatorRBAC(ctx context.Context) {
+	if !c.reconcileOperatorRBAC {
+		return
+	}
+	reconcile := func() {
+		if err := CreateOperatorRBAC(ctx, c); err != nil {
+			zap.S().Errorf("Failed to reconcile the ClusterRole and ClusterRoleBinding of the VMO: %v", err)
+		}
+	}
+	reconcile()
+	go func() {
+		ticker := time.NewTicker(constants.OperatorRBACReconcilePeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				reconcile()
+			}
+		}
+	}()
+}
+
+// CreateOperatorRBAC creates the ClusterRole and the ClusterRoleBinding of the VMO itself, or corrects them if they
+// drifted from the expected ones. Nothing is done unless the operator RBAC reconcile is enabled, as the RBAC of the VMO
+// may be managed externally.
+func CreateOperatorRBAC(ctx context.Context, controller *Controller) error {
+	if !controller.reconcileOperatorRBAC {
+		return nil
+	}
+	clusterRole, err := newOperatorClusterRole(controller.namespace)
+	if err != nil {
+		return err
+	}
+	if err := createOperatorClusterRole(ctx, controller, clusterRole); err != nil {
+		return err
+	}
+	return createOperatorClusterRoleBinding(ctx, controller, newOperatorClusterRoleBinding(controller.namespace))
+}
+
+// createOperatorClusterRole creates the ClusterRole of the VMO, or updates it if its rules drifted
+func createOperatorClusterRole(ctx context.Context, controller *Controller, clusterRole *rbacv1.ClusterRole) error {
+	existing, err := controller.clusterRoleLister.Get(clusterRole.Name)
+	if k8serrors.IsNotFound(err) {
+		controller.log.Oncef("Creating ClusterRole %s", clusterRole.Name)
+		_, err = controller.kubeclientset.RbacV1().ClusterRoles().Create(ctx, clusterRole, metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Rules, clusterRole.Rules) {
+		return nil
+	}
+	controller.log.Infof("ClusterRole %s drifted from its expected rules, restoring them", clusterRole.Name)
+	updated := existing.DeepCopy()
+	updated.Rules = clusterRole.Rules
+	_, err = controller.kubeclientset.RbacV1().ClusterRoles().Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// createOperatorClusterRoleBinding creates the ClusterRoleBinding of the VMO, or corrects it if its subjects or role
+// drifted. The subjects are updated in place, while the binding is recreated if its role drifted, as the role of a
+// ClusterRoleBinding cannot be updated. The ClusterRoleBindings are not watched, the binding is read from the API server.
+func createOperatorClusterRoleBinding(ctx context.Context, controller *Controller, binding *rbacv1.ClusterRoleBinding) error {
+	existing, err := controller.kubeclientset.RbacV1().ClusterRoleBindings().Get(ctx, binding.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		controller.log.Oncef("Creating ClusterRoleBinding %s", binding.Name)
+		_, err = controller.kubeclientset.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if existing.RoleRef != binding.RoleRef {
+		controller.log.Infof("ClusterRoleBinding %s drifted from its expected role, recreating it", binding.Name)
+		err = controller.kubeclientset.RbacV1().ClusterRoleBindings().Delete(ctx, binding.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		_, err = controller.kubeclientset.RbacV1().ClusterRoleBindings().Create(ctx, binding, metav1.CreateOptions{})
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Subjects, binding.Subjects) {
+		return nil
+	}
+	controller.log.Infof("ClusterRoleBinding %s drifted from its expected subjects, restoring them", binding.Name)
+	updated := existing.DeepCopy()
+	updated.Subjects = binding.Subjects
+	_, err = controller.kubeclientset.RbacV1().ClusterRoleBindings().Update(ctx, updated, metav1.UpdateOptions{})
+	return err
+}
+
+// newOperatorClusterRole constructs the expected ClusterRole of the VMO running in the given namespace
+func newOperatorClusterRole(namespace string) (*rbacv1.ClusterRole, error) {
+	rules, err := operatorRules()
+	if err != nil {
+		return nil, err
+	}
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   constants.ClusterRoleForVMO + "-" + namespace,
+			Labels: operatorRBACLabels,
+		},
+		Rules: rules,
+	}, nil
+}
+
+// newOperatorClusterRoleBinding constructs the expected ClusterRoleBinding of the VMO running in the given namespace,
+// which binds the ClusterRole of the VMO to its service account, as in the VMO manifest
+func newOperatorClusterRoleBinding(namespace string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   constants.ClusterRoleBindingForVMO + "-" + namespace,
+			Labels: operatorRBACLabels,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      constants.ServiceAccountName,
+				Namespace: namespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: "rbac.authorization.k8s.io",
+			Kind:     "ClusterRole",
+			Name:     constants.ClusterRoleForVMO + "-" + namespace,
+		},
+	}
+}
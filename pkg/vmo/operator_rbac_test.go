@@ -0,0 +1,150 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testOperatorNamespace = "verrazzano-system"
+
+// createOperatorRBACTestController creates a controller whose client and ClusterRole lister hold the given objects
+func createOperatorRBACTestController(t *testing.T, objects ...runtime.Object) (*Controller, *fake.Clientset) {
+	controller, _ := createControllerForTesting()
+	controller.namespace = testOperatorNamespace
+	client := fake.NewSimpleClientset(objects...)
+	factory := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod)
+	clusterRoleInformer := factory.Rbac().V1().ClusterRoles()
+	for _, object := range objects {
+		if _, ok := object.(*rbacv1.ClusterRole); ok {
+			assert.NoError(t, clusterRoleInformer.Informer().GetIndexer().Add(object))
+		}
+	}
+	controller.kubeclientset = client
+	controller.clusterRoleLister = clusterRoleInformer.Lister()
+	return controller, client
+}
+
+// expectedOperatorClusterRole returns the expected ClusterRole of the VMO running in the test namespace
+func expectedOperatorClusterRole(t *testing.T) *rbacv1.ClusterRole {
+	clusterRole, err := newOperatorClusterRole(testOperatorNamespace)
+	assert.NoError(t, err)
+	return clusterRole
+}
+
+// TestOperatorRules Tests reading the rules of the ClusterRole of the VMO from the VMO manifest
+// GIVEN the embedded VMO manifest
+// WHEN I call operatorRules
+// THEN the rules of the ClusterRole of the VMO are returned, without the rules needed to reconcile the RBAC of the VMO,
+// which are only granted by the RBAC reconcile overlay
+func TestOperatorRules(t *testing.T) {
+	rules, err := operatorRules()
+	assert.NoError(t, err)
+	assert.Contains(t, rules, rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"events"}, Verbs: []string{"get", "list", "watch", "create", "patch"}})
+	assert.Contains(t, rules, rbacv1.PolicyRule{NonResourceURLs: []string{"/metrics"}, Verbs: []string{"get"}})
+	for _, rule := range rules {
+		assert.NotContains(t, rule.Resources, "clusterrolebindings")
+		assert.NotContains(t, rule.Verbs, "escalate")
+		assert.NotContains(t, rule.Verbs, "bind")
+	}
+}
+
+// TestCreateOperatorRBACDisabled Tests that the RBAC of the VMO is not reconciled unless enabled
+// GIVEN a controller without the operator RBAC reconcile enabled
+// WHEN I call CreateOperatorRBAC
+// THEN neither the ClusterRole nor the ClusterRoleBinding of the VMO are created
+func TestCreateOperatorRBACDisabled(t *testing.T) {
+	controller, client := createOperatorRBACTestController(t)
+
+	assert.NoError(t, CreateOperatorRBAC(context.TODO(), controller))
+	clusterRoles, err := client.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, clusterRoles.Items)
+	bindings, err := client.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, bindings.Items)
+}
+
+// TestCreateOperatorRBACMissing Tests creating the RBAC of the VMO
+// GIVEN a controller with the operator RBAC reconcile enabled, and no ClusterRole nor ClusterRoleBinding of the VMO
+// WHEN I call CreateOperatorRBAC
+// THEN the ClusterRole and the ClusterRoleBinding of the VMO are created, named as in the VMO manifest
+func TestCreateOperatorRBACMissing(t *testing.T) {
+	controller, client := createOperatorRBACTestController(t)
+	controller.EnableOperatorRBACReconcile()
+
+	assert.NoError(t, CreateOperatorRBAC(context.TODO(), controller))
+	clusterRole, err := client.RbacV1().ClusterRoles().Get(context.TODO(), constants.ClusterRoleForVMO+"-"+testOperatorNamespace, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedOperatorClusterRole(t).Rules, clusterRole.Rules)
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), "verrazzano-monitoring-operator-cluster-role-binding-"+testOperatorNamespace, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "verrazzano-monitoring-operator-cluster-role-"+testOperatorNamespace, binding.RoleRef.Name)
+	assert.Equal(t, clusterRole.Name, binding.RoleRef.Name)
+	assert.Equal(t, constants.ServiceAccountName, binding.Subjects[0].Name)
+}
+
+// TestCreateOperatorRBACDrift Tests correcting the drift of the RBAC of the VMO
+// GIVEN a controller with the operator RBAC reconcile enabled, a ClusterRole of the VMO with a removed rule,
+// and a ClusterRoleBinding of the VMO bound to another service account
+// WHEN I call CreateOperatorRBAC
+// THEN the rules of the ClusterRole are restored and the subjects of the ClusterRoleBinding are updated in place
+func TestCreateOperatorRBACDrift(t *testing.T) {
+	driftedClusterRole := expectedOperatorClusterRole(t)
+	driftedClusterRole.Rules = driftedClusterRole.Rules[1:]
+	driftedBinding := newOperatorClusterRoleBinding(testOperatorNamespace)
+	driftedBinding.Subjects = []rbacv1.Subject{{Kind: "ServiceAccount", Name: "intruder", Namespace: testOperatorNamespace}}
+	controller, client := createOperatorRBACTestController(t, driftedClusterRole, driftedBinding)
+	controller.EnableOperatorRBACReconcile()
+
+	assert.NoError(t, CreateOperatorRBAC(context.TODO(), controller))
+	clusterRole, err := client.RbacV1().ClusterRoles().Get(context.TODO(), driftedClusterRole.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, expectedOperatorClusterRole(t).Rules, clusterRole.Rules)
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), driftedBinding.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, newOperatorClusterRoleBinding(testOperatorNamespace).Subjects, binding.Subjects)
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "delete", action.GetVerb())
+	}
+}
+
+// TestCreateOperatorRBACRoleDrift Tests correcting the role of the ClusterRoleBinding of the VMO
+// GIVEN a controller with the operator RBAC reconcile enabled, and a ClusterRoleBinding of the VMO bound to another role
+// WHEN I call CreateOperatorRBAC
+// THEN the ClusterRoleBinding is recreated with the ClusterRole of the VMO
+func TestCreateOperatorRBACRoleDrift(t *testing.T) {
+	driftedBinding := newOperatorClusterRoleBinding(testOperatorNamespace)
+	driftedBinding.RoleRef.Name = "cluster-admin"
+	controller, client := createOperatorRBACTestController(t, expectedOperatorClusterRole(t), driftedBinding)
+	controller.EnableOperatorRBACReconcile()
+
+	assert.NoError(t, CreateOperatorRBAC(context.TODO(), controller))
+	binding, err := client.RbacV1().ClusterRoleBindings().Get(context.TODO(), driftedBinding.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, newOperatorClusterRoleBinding(testOperatorNamespace).RoleRef, binding.RoleRef)
+}
+
+// TestCreateOperatorRBACUnchanged Tests that the RBAC of the VMO is left as is when it did not drift
+// GIVEN a controller with the operator RBAC reconcile enabled, and the expected ClusterRole and ClusterRoleBinding of the VMO
+// WHEN I call CreateOperatorRBAC
+// THEN neither the ClusterRole nor the ClusterRoleBinding are written
+func TestCreateOperatorRBACUnchanged(t *testing.T) {
+	controller, client := createOperatorRBACTestController(t, expectedOperatorClusterRole(t), newOperatorClusterRoleBinding(testOperatorNamespace))
+	controller.EnableOperatorRBACReconcile()
+
+	assert.NoError(t, CreateOperatorRBAC(context.TODO(), controller))
+	for _, action := range client.Actions() {
+		assert.Equal(t, "get", action.GetVerb(), "unexpected action %s %s", action.GetVerb(), action.GetResource().Resource)
+	}
+}
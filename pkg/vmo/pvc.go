@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -12,6 +12,7 @@ import (
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/pvcs"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
@@ -23,7 +24,8 @@ import (
 // CreatePersistentVolumeClaims Creates PVCs for the given VMO instance.  Returns a pvc->AD map, which is populated *only if* AD information
 // can be specified for new PVCs or determined from existing PVCs.  A pvc-AD map with empty AD values instructs the
 // subsequent deployment processing logic to do the job of choosing ADs.
-func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string]string, error) {
+func CreatePersistentVolumeClaims(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (map[string]string, error) {
+	log := reconcileLog(ctx)
 	// Update storage with the new API
 	setPerNodeStorage(vmo)
 	// Inspect the Storage Class to use
@@ -31,16 +33,19 @@ func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.Ve
 	if err != nil {
 		return nil, err
 	}
-	storageClassInfo := parseStorageClassInfo(storageClass, controller.operatorConfig)
+	// Data nodes may override the storage class, the storage class info is looked up by the storage class of each PVC
+	storageClassInfos := map[string]StorageClassInfo{
+		storageClass.Name: parseStorageClassInfo(storageClass, controller.operatorConfig),
+	}
 
 	expectedPVCs, err := pvcs.New(vmo, storageClass.Name)
 	if err != nil {
-		controller.log.Errorf("Failed to create PVC specs for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to create PVC specs for VMI %s: %v", vmo.Name, err)
 		return nil, err
 	}
 	pvcToAdMap := map[string]string{}
 
-	controller.log.Oncef("Creating/updating PVCs for VMI %s", vmo.Name)
+	log.Oncef("Creating/updating PVCs for VMI %s", vmo.Name)
 
 	// Get total list of all possible schedulable ADs
 	schedulableADs, err := getSchedulableADs(controller)
@@ -50,10 +55,14 @@ func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.Ve
 
 	opensearchAdCounter := NewAdPvcCounter(schedulableADs)
 
-	if len(expectedPVCs) > 0 && storageClassInfo.Name == "" {
-		return nil, fmt.Errorf("cannot create PVCs when the cluster has no storage class")
-	}
 	for _, expectedPVC := range expectedPVCs {
+		storageClassInfo, err := getPVCStorageClassInfo(controller, expectedPVC, storageClassInfos)
+		if err != nil {
+			return nil, err
+		}
+		if storageClassInfo.Name == "" {
+			return nil, fmt.Errorf("cannot create PVCs when the cluster has no storage class")
+		}
 		pvcName := expectedPVC.Name
 		if pvcName == "" {
 			// We choose to absorb the error here as the worker would requeue the
@@ -63,7 +72,11 @@ func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.Ve
 			return pvcToAdMap, nil
 		}
 
-		controller.log.Debugf("Applying PVC '%s' in namespace '%s' for VMI '%s'\n", pvcName, vmo.Namespace, vmo.Name)
+		if resources.IsOpenSearchPaused(vmo) && isOpenSearchPVC(expectedPVC) {
+			log.Debugf("Skipping PVC '%s' in namespace '%s' for VMI '%s', OpenSearch is paused\n", pvcName, vmo.Namespace, vmo.Name)
+			continue
+		}
+		log.Debugf("Applying PVC '%s' in namespace '%s' for VMI '%s'\n", pvcName, vmo.Namespace, vmo.Name)
 		existingPvc, err := controller.pvcLister.PersistentVolumeClaims(vmo.Namespace).Get(pvcName)
 
 		// If the PVC already exists, we check if it needs resizing
@@ -74,7 +87,7 @@ func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.Ve
 				if err != nil {
 					return nil, err
 				}
-				if newPVCName, err := resizePVC(controller, vmo, existingPvc, expectedPVC, existingStorageClass); err != nil {
+				if newPVCName, err := resizePVC(ctx, controller, vmo, existingPvc, expectedPVC, existingStorageClass); err != nil {
 					return nil, err
 				} else if newPVCName != nil {
 					// we need to wait until the PVC is bound
@@ -103,12 +116,15 @@ func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.Ve
 				}
 				expectedPVC.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{storageClassInfo.PvcZoneMatchLabel: newAd}}
 			}
-			controller.log.Oncef("Creating PVC %s in AD %s", expectedPVC.Name, newAd)
+			if err = checkQuotaBackoff(controller, vmo, "PersistentVolumeClaim", expectedPVC.Name); err != nil {
+				return pvcToAdMap, err
+			}
+			log.Oncef("Creating PVC %s in AD %s", expectedPVC.Name, newAd)
 
-			_, err = controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Create(context.TODO(), expectedPVC, metav1.CreateOptions{})
+			_, err = controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Create(ctx, expectedPVC, metav1.CreateOptions{})
 
 			if err != nil {
-				return pvcToAdMap, err
+				return pvcToAdMap, handleCreateError(controller, vmo, "PersistentVolumeClaim", expectedPVC.Name, err)
 			}
 
 			pvcToAdMap[pvcName] = newAd
@@ -117,10 +133,10 @@ func CreatePersistentVolumeClaims(controller *Controller, vmo *vmcontrollerv1.Ve
 		if err != nil {
 			return pvcToAdMap, err
 		}
-		controller.log.Debugf("Successfully applied PVC '%s'\n", pvcName)
+		log.Debugf("Successfully applied PVC '%s'\n", pvcName)
 	}
 
-	return pvcToAdMap, cleanupUnusedPVCs(controller, vmo)
+	return pvcToAdMap, cleanupUnusedPVCs(ctx, controller, vmo)
 }
 
 // AdPvcCounter type for AD PVC counts
@@ -201,6 +217,25 @@ func getStorageClassByName(controller *Controller, className string) (*storagev1
 	return storageClass, err
 }
 
+// getPVCStorageClassInfo returns the info of the storage class of the given PVC, the storage classes already looked up
+// are cached in storageClassInfos
+func getPVCStorageClassInfo(controller *Controller, pvc *corev1.PersistentVolumeClaim, storageClassInfos map[string]StorageClassInfo) (StorageClassInfo, error) {
+	var className string
+	if pvc.Spec.StorageClassName != nil {
+		className = *pvc.Spec.StorageClassName
+	}
+	if storageClassInfo, ok := storageClassInfos[className]; ok {
+		return storageClassInfo, nil
+	}
+	storageClass, err := getStorageClassByName(controller, className)
+	if err != nil {
+		return StorageClassInfo{}, err
+	}
+	storageClassInfo := parseStorageClassInfo(storageClass, controller.operatorConfig)
+	storageClassInfos[className] = storageClassInfo
+	return storageClassInfo, nil
+}
+
 // Parses the given storage class into a StorageClassInfo objects
 func parseStorageClassInfo(storageClass *storagev1.StorageClass, operatorConfig *config.OperatorConfig) StorageClassInfo {
 	pvcAcceptsZone := false
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -13,6 +13,8 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestNoPvcs(t *testing.T) {
@@ -130,6 +132,37 @@ func TestParseStorageClassInfoWithoutMatchLabel(t *testing.T) {
 	assert.Equal(t, expectedStorageClassInfo, parseStorageClassInfo(&storageClass, &config.OperatorConfig{}), "No match label specified in operator config")
 }
 
+// TestGetPVCStorageClassInfo tests looking up the storage class info of PVCs with different storage classes
+// GIVEN the storage class info of the VMI storage class, and PVCs using the VMI storage class, a node storage class
+// and an unknown storage class
+// WHEN I call getPVCStorageClassInfo
+// THEN the info of the storage class of each PVC is returned and cached, and an error is returned for the unknown storage class
+func TestGetPVCStorageClassInfo(t *testing.T) {
+	informer := kubeinformers.NewSharedInformerFactory(fake.NewSimpleClientset(), constants.ResyncPeriod).Storage().V1().StorageClasses()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(&storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "nvme"},
+		Provisioner: constants.OciFlexVolumeProvisioner,
+	}))
+	controller := &Controller{storageClassLister: informer.Lister(), operatorConfig: &config.OperatorConfig{}}
+	storageClassInfos := map[string]StorageClassInfo{"standard": {Name: "standard"}}
+	pvcWithStorageClass := func(name string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{Spec: corev1.PersistentVolumeClaimSpec{StorageClassName: &name}}
+	}
+
+	info, err := getPVCStorageClassInfo(controller, pvcWithStorageClass("standard"), storageClassInfos)
+	assert.NoError(t, err)
+	assert.Equal(t, StorageClassInfo{Name: "standard"}, info)
+
+	info, err = getPVCStorageClassInfo(controller, pvcWithStorageClass("nvme"), storageClassInfos)
+	assert.NoError(t, err)
+	expectedInfo := StorageClassInfo{Name: "nvme", PvcAcceptsZone: true, PvcZoneMatchLabel: constants.OciAvailabilityDomainLabel}
+	assert.Equal(t, expectedInfo, info)
+	assert.Equal(t, expectedInfo, storageClassInfos["nvme"])
+
+	_, err = getPVCStorageClassInfo(controller, pvcWithStorageClass("unknown"), storageClassInfos)
+	assert.Error(t, err)
+}
+
 func TestAdFromExistingPVC1(t *testing.T) {
 	// Storage class accepts an AD, and the PVC is labels as expected
 	storageClassInfo := StorageClassInfo{Name: "storageclass1", PvcAcceptsZone: true, PvcZoneMatchLabel: "somematchlabel"}
@@ -1,4 +1,4 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -8,30 +8,34 @@ import (
 	"strings"
 
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/statefulsets"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
 
 // resizePVC resizes a PVC to a new size
 // if the underlying storage class does not support expansion, a new PVC will be created.
-func resizePVC(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingPVC, expectedPVC *corev1.PersistentVolumeClaim, storageClass *storagev1.StorageClass) (*string, error) {
+func resizePVC(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, existingPVC, expectedPVC *corev1.PersistentVolumeClaim, storageClass *storagev1.StorageClass) (*string, error) {
 	if storageClass.AllowVolumeExpansion != nil && *storageClass.AllowVolumeExpansion {
 		// Volume expansion means dynamic resize is possible - we can do an Update of the PVC in place
 		updatedPVC := existingPVC.DeepCopy()
 		updatedPVC.Spec.Resources = expectedPVC.Spec.Resources
-		_, err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Update(context.TODO(), updatedPVC, metav1.UpdateOptions{})
+		_, err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Update(ctx, updatedPVC, metav1.UpdateOptions{})
 		return nil, err
 	}
 
 	// If we are updating an OpenSearch PVC, we need to make sure the OpenSearch cluster is ready
 	// before doing the resize
 	if isOpenSearchPVC(expectedPVC) {
-		if err := controller.osClient.IsDataResizable(vmo); err != nil {
+		if err := controller.osClient.WithContext(ctx).IsDataResizable(vmo); err != nil {
 			return nil, err
 		}
 	}
@@ -45,7 +49,7 @@ func resizePVC(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringI
 		return nil, err
 	}
 	expectedPVC.Name = newName
-	_, err = controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Create(context.TODO(), expectedPVC, metav1.CreateOptions{})
+	_, err = controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Create(ctx, expectedPVC, metav1.CreateOptions{})
 	if err != nil {
 		return nil, err
 	}
@@ -55,29 +59,168 @@ func resizePVC(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringI
 	return &expectedPVC.Name, nil
 }
 
-// cleanupUnusedPVCs finds any unused PVCs and deletes them
-func cleanupUnusedPVCs(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
-	selector := labels.SelectorFromSet(resources.GetMetaLabels(vmo))
-	deployments, err := controller.deploymentLister.Deployments(vmo.Namespace).List(selector)
+// cleanupUnusedPVCs deletes the PVCs of the VMI which are no longer used by any of its deployments or StatefulSets,
+// nor are PVCs of its data nodes. The PVCs retained when their data node was removed are only deleted once the VMI no
+// longer retains orphaned PVCs. The unused OpenSearch PVCs are only deleted once the cluster is green, so that they no
+// longer hold the only copy of a shard.
+func cleanupUnusedPVCs(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
+	unboundPVCs, err := getUnusedPVCs(controller, vmo)
 	if err != nil {
 		return err
 	}
-	inUsePVCNames := getInUsePVCNames(deployments, vmo)
+
+	var healthErr error
+	checkedHealth := false
+	for _, unboundPVC := range unboundPVCs {
+		if _, ok := unboundPVC.Annotations[constants.RetainedPVCAnnotation]; ok && retainOrphanedPVCs(vmo) {
+			continue
+		}
+		if isOpenSearchPVC(unboundPVC) {
+			if resources.IsOpenSearchPaused(vmo) {
+				continue
+			}
+			if !checkedHealth {
+				healthErr = controller.osClient.WithContext(ctx).IsGreen(vmo)
+				checkedHealth = true
+			}
+			if healthErr != nil {
+				log.Oncef("Deletion of unused PVC %s/%s not allowed: cluster health is not green", unboundPVC.Namespace, unboundPVC.Name)
+				continue
+			}
+		}
+		log.Oncef("Deleting unused PVC %s/%s", unboundPVC.Namespace, unboundPVC.Name)
+		err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(unboundPVC.Namespace).Delete(ctx, unboundPVC.Name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// getUnusedPVCs returns the PVCs of the VMI which are not used by any of its deployments or StatefulSets, nor are PVCs
+// of its data nodes
+func getUnusedPVCs(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) ([]*corev1.PersistentVolumeClaim, error) {
+	selector := labels.SelectorFromSet(resources.GetMetaLabels(vmo))
+	existingDeployments, err := controller.deploymentLister.Deployments(vmo.Namespace).List(selector)
+	if err != nil {
+		return nil, err
+	}
+	inUsePVCNames := getInUsePVCNames(existingDeployments, vmo)
+	existingStatefulSets, err := controller.statefulSetLister.StatefulSets(vmo.Namespace).List(labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name}))
+	if err != nil {
+		return nil, err
+	}
+	for _, statefulSet := range existingStatefulSets {
+		for _, pvcName := range statefulsets.GetPVCNames(statefulSet) {
+			inUsePVCNames[pvcName] = true
+		}
+	}
 	allPVCs, err := controller.pvcLister.PersistentVolumeClaims(vmo.Namespace).List(selector)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	unboundPVCs := getUnboundPVCs(allPVCs, inUsePVCNames)
+	return getUnboundPVCs(allPVCs, inUsePVCNames), nil
+}
 
-	for _, unboundPVC := range unboundPVCs {
-		err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(unboundPVC.Namespace).Delete(context.TODO(), unboundPVC.Name, metav1.DeleteOptions{})
+// handleOrphanedPVCs retains the PVCs of a removed OpenSearch data deployment while the VMI retains orphaned PVCs.
+// Retained PVCs are annotated so they are skipped by the unused PVC cleanup while the VMI retains orphaned PVCs, and
+// must be deleted manually. Otherwise, the PVCs are left to the unused PVC cleanup, which only deletes them once the
+// cluster is green.
+func handleOrphanedPVCs(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, deployment *appsv1.Deployment) error {
+	if !retainOrphanedPVCs(vmo) {
+		return nil
+	}
+	log := reconcileLog(ctx)
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.PersistentVolumeClaim == nil {
+			continue
+		}
+		pvcName := volume.PersistentVolumeClaim.ClaimName
+		pvc, err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Get(ctx, pvcName, metav1.GetOptions{})
 		if err != nil {
+			if k8serrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if _, ok := pvc.Annotations[constants.RetainedPVCAnnotation]; ok {
+			continue
+		}
+		retainedPVC := pvc.DeepCopy()
+		if retainedPVC.Annotations == nil {
+			retainedPVC.Annotations = map[string]string{}
+		}
+		retainedPVC.Annotations[constants.RetainedPVCAnnotation] = deployment.Name
+		if _, err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(ctx, retainedPVC, metav1.UpdateOptions{}); err != nil {
+			return err
+		}
+		log.Infof("Retaining PVC %s/%s of removed deployment %s, it must be deleted manually", pvc.Namespace, pvc.Name, deployment.Name)
+	}
+	return nil
+}
+
+// checkDataDeploymentPVCs reports the OpenSearch data deployments whose PVC has drifted from the PVCs of the VMI, e.g.
+// after a partial failure. Nothing is repaired here: a missing PVC of the VMI is recreated by
+// CreatePersistentVolumeClaims in its availability domain, and a deployment referencing a deleted PVC which is no longer
+// a PVC of the VMI is realigned with the PVC of its index by the rolling update of the data deployments, one node at a
+// time while the cluster is green. PVCs which are not bound are only logged.
+func checkDataDeploymentPVCs(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, expectedDeployments []*appsv1.Deployment) error {
+	log := reconcileLog(ctx)
+	for _, expected := range expectedDeployments {
+		if !deployments.IsOpenSearchDataDeployment(vmo.Name, expected) {
+			continue
+		}
+		expectedPVCName := getDeploymentPVCName(expected)
+		if expectedPVCName == "" {
+			continue
+		}
+		existing, err := controller.deploymentLister.Deployments(vmo.Namespace).Get(expected.Name)
+		if k8serrors.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		existingPVCName := getDeploymentPVCName(existing)
+		if existingPVCName == "" {
+			continue
+		}
+
+		pvc, err := controller.pvcLister.PersistentVolumeClaims(vmo.Namespace).Get(existingPVCName)
+		if err == nil {
+			if pvc.Status.Phase != corev1.ClaimBound {
+				log.Oncef("PVC %s/%s of deployment %s is not bound, its phase is %s", vmo.Namespace, existingPVCName, existing.Name, pvc.Status.Phase)
+			}
+			continue
+		}
+		if !k8serrors.IsNotFound(err) {
 			return err
 		}
+		if existingPVCName == expectedPVCName {
+			log.Oncef("PVC %s/%s of deployment %s is missing, it is recreated with the PVCs of VMI %s", vmo.Namespace, existingPVCName, existing.Name, vmo.Name)
+		} else {
+			log.Oncef("Deployment %s references the deleted PVC %s/%s, it is realigned with PVC %s by the rolling update", existing.Name, vmo.Namespace, existingPVCName, expectedPVCName)
+		}
 	}
 	return nil
 }
 
+// getDeploymentPVCName returns the name of the PVC used by the deployment, or an empty string if it uses none
+func getDeploymentPVCName(deployment *appsv1.Deployment) string {
+	for _, volume := range deployment.Spec.Template.Spec.Volumes {
+		if volume.PersistentVolumeClaim != nil {
+			return volume.PersistentVolumeClaim.ClaimName
+		}
+	}
+	return ""
+}
+
+// retainOrphanedPVCs returns true unless the VMI disables retaining the PVCs of removed data nodes
+func retainOrphanedPVCs(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) bool {
+	return vmo.Spec.Opensearch.RetainOrphanedPVCs == nil || *vmo.Spec.Opensearch.RetainOrphanedPVCs
+}
+
 // getInUsePVCNames gets the names of PVCs that are currently used by VMO deployments
 func getInUsePVCNames(deployments []*appsv1.Deployment, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) map[string]bool {
 	inUsePVCNames := map[string]bool{}
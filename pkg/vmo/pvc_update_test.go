@@ -1,17 +1,29 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
 
 import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
 	"github.com/stretchr/testify/assert"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/util/logs/vzlog"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	storagev1 "k8s.io/api/storage/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubeinformers "k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes/fake"
 	"testing"
 )
@@ -82,6 +94,51 @@ func makeDeploymentWithPVC(pvc *corev1.PersistentVolumeClaim) *appsv1.Deployment
 	return d
 }
 
+// createUnusedPVCsTestController creates a controller whose client and listers hold the given objects, for an
+// OpenSearch cluster with the given health
+func createUnusedPVCsTestController(t *testing.T, health string, objects ...runtime.Object) *Controller {
+	controller, _ := createControllerForTesting()
+	client := fake.NewSimpleClientset(objects...)
+	factory := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod)
+	pvcInformer := factory.Core().V1().PersistentVolumeClaims()
+	deploymentInformer := factory.Apps().V1().Deployments()
+	statefulSetInformer := factory.Apps().V1().StatefulSets()
+	for _, object := range objects {
+		switch object.(type) {
+		case *corev1.PersistentVolumeClaim:
+			assert.NoError(t, pvcInformer.Informer().GetIndexer().Add(object))
+		case *appsv1.Deployment:
+			assert.NoError(t, deploymentInformer.Informer().GetIndexer().Add(object))
+		case *appsv1.StatefulSet:
+			assert.NoError(t, statefulSetInformer.Informer().GetIndexer().Add(object))
+		}
+	}
+	controller.kubeclientset = client
+	controller.pvcLister = pvcInformer.Lister()
+	controller.deploymentLister = deploymentInformer.Lister()
+	controller.statefulSetLister = statefulSetInformer.Lister()
+	osClient := opensearch.NewOSClient(controller.statefulSetLister)
+	osClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		body := `{"nodes": {}}`
+		if strings.Contains(request.URL.Path, "_cluster/health") {
+			body = `{"status": "` + health + `"}`
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+	controller.osClient = osClient
+	return controller
+}
+
+// makeVMIPVC creates a PVC labeled for the VMI, optionally annotated as retained
+func makeVMIPVC(name string, retained bool) *corev1.PersistentVolumeClaim {
+	pvc := makePVC(name, "1Gi")
+	pvc.Labels = resources.GetMetaLabels(&testvmo)
+	if retained {
+		pvc.Annotations = map[string]string{constants.RetainedPVCAnnotation: "deploy"}
+	}
+	return pvc
+}
+
 func TestGetUnboundPVCs(t *testing.T) {
 	pvcs := []*corev1.PersistentVolumeClaim{
 		makePVC("pvc1", "1Gi"),
@@ -275,7 +332,7 @@ func TestResizePVC(t *testing.T) {
 			c := &Controller{
 				kubeclientset: fake.NewSimpleClientset(existingPVC),
 			}
-			newName, err := resizePVC(c, &testvmo, existingPVC, expectedPVC, tt.storageClass)
+			newName, err := resizePVC(context.TODO(), c, &testvmo, existingPVC, expectedPVC, tt.storageClass)
 			assert.NoError(t, err)
 			if tt.createdPVC {
 				assert.NotNil(t, newName)
@@ -284,3 +341,225 @@ func TestResizePVC(t *testing.T) {
 		})
 	}
 }
+
+// TestHandleOrphanedPVCs Tests handling the PVCs of a removed OpenSearch data deployment
+// GIVEN a removed data deployment with a PVC
+// WHEN I call handleOrphanedPVCs
+// THEN the PVC is annotated and kept if retaining orphaned PVCs, or otherwise kept without annotation for the unused PVC
+// cleanup, which only deletes it once the cluster is green
+func TestHandleOrphanedPVCs(t *testing.T) {
+	retain := true
+	noRetain := false
+	var tests = []struct {
+		name       string
+		retainPVCs *bool
+		retained   bool
+	}{
+		{
+			"PVC is retained by default",
+			nil,
+			true,
+		},
+		{
+			"PVC is retained when enabled",
+			&retain,
+			true,
+		},
+		{
+			"PVC is left to the unused PVC cleanup when disabled",
+			&noRetain,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pvc := makePVC("vmi-system-es-data-1", "1Gi")
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Opensearch.RetainOrphanedPVCs = tt.retainPVCs
+			c := &Controller{
+				kubeclientset: fake.NewSimpleClientset(pvc),
+				osClient:      opensearch.NewOSClient(nil),
+				log:           vzlog.DefaultLogger(),
+			}
+			err := handleOrphanedPVCs(context.TODO(), c, vmo, makeDeploymentWithPVC(pvc))
+			assert.NoError(t, err)
+			existingPVC, err := c.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Get(context.TODO(), pvc.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			_, annotated := existingPVC.Annotations[constants.RetainedPVCAnnotation]
+			assert.Equal(t, tt.retained, annotated)
+		})
+	}
+}
+
+// TestCleanupUnusedPVCs Tests cleaning up the unused PVCs of a VMI
+// GIVEN a VMI with a data deployment and a master StatefulSet using their PVCs, an unused OpenSearch PVC, an unused
+// Grafana PVC and a PVC retained when its data node was removed
+// WHEN I call cleanupUnusedPVCs
+// THEN the unused OpenSearch PVC is only deleted when the cluster is green, the retained PVC is only deleted when the VMI
+// does not retain orphaned PVCs and the cluster is green, the Grafana PVC is deleted whatever the cluster health, and
+// the PVCs in use are never deleted
+func TestCleanupUnusedPVCs(t *testing.T) {
+	retain := true
+	noRetain := false
+	var tests = []struct {
+		name            string
+		retainPVCs      *bool
+		health          string
+		unusedDeleted   bool
+		retainedDeleted bool
+	}{
+		{"retained PVCs are kept by default", nil, "green", true, false},
+		{"retained PVCs are kept when enabled", &retain, "green", true, false},
+		{"OpenSearch PVCs are not deleted when the cluster is not green", &noRetain, "yellow", false, false},
+		{"retained PVCs are deleted when disabled and the cluster is green", &noRetain, "green", true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Opensearch.Enabled = true
+			vmo.Spec.Opensearch.RetainOrphanedPVCs = tt.retainPVCs
+			inUsePVC := makeVMIPVC("vmi-system-es-data-0", false)
+			deployment := makeDeploymentWithPVC(inUsePVC)
+			deployment.Namespace = vmo.Namespace
+			deployment.Labels = resources.GetMetaLabels(vmo)
+			masterPVC := makeVMIPVC("elasticsearch-master-vmi-system-es-master-0", false)
+			statefulSet := &appsv1.StatefulSet{
+				ObjectMeta: metav1.ObjectMeta{Name: "vmi-system-es-master", Namespace: vmo.Namespace, Labels: resources.GetMetaLabels(vmo)},
+				Spec: appsv1.StatefulSetSpec{
+					Replicas:             resources.NewVal(1),
+					VolumeClaimTemplates: []corev1.PersistentVolumeClaim{{ObjectMeta: metav1.ObjectMeta{Name: "elasticsearch-master"}}},
+				},
+			}
+			unusedPVC := makeVMIPVC("vmi-system-es-data-1", false)
+			retainedPVC := makeVMIPVC("vmi-system-es-data-2", true)
+			grafanaPVC := makeVMIPVC("vmi-system-grafana", false)
+			controller := createUnusedPVCsTestController(t, tt.health, inUsePVC, deployment, masterPVC, statefulSet, unusedPVC, retainedPVC, grafanaPVC)
+
+			assert.NoError(t, cleanupUnusedPVCs(context.TODO(), controller, vmo))
+			assertPVCDeleted := func(pvc *corev1.PersistentVolumeClaim, deleted bool) {
+				_, err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Get(context.TODO(), pvc.Name, metav1.GetOptions{})
+				if deleted {
+					assert.True(t, k8serrors.IsNotFound(err), pvc.Name)
+				} else {
+					assert.NoError(t, err, pvc.Name)
+				}
+			}
+			assertPVCDeleted(inUsePVC, false)
+			assertPVCDeleted(masterPVC, false)
+			assertPVCDeleted(unusedPVC, tt.unusedDeleted)
+			assertPVCDeleted(retainedPVC, tt.retainedDeleted)
+			assertPVCDeleted(grafanaPVC, true)
+		})
+	}
+}
+
+// TestCleanupUnusedPVCsPaused Tests that the unused OpenSearch PVCs are kept while OpenSearch is paused
+// GIVEN a VMI which does not retain orphaned PVCs, with OpenSearch paused, and an unused OpenSearch PVC
+// WHEN I call cleanupUnusedPVCs
+// THEN the unused PVC is kept
+func TestCleanupUnusedPVCsPaused(t *testing.T) {
+	noRetain := false
+	vmo := testvmo.DeepCopy()
+	vmo.Spec.Opensearch.Enabled = true
+	vmo.Spec.Opensearch.RetainOrphanedPVCs = &noRetain
+	vmo.Spec.Opensearch.Paused = resources.NewBool(true)
+	unusedPVC := makeVMIPVC("vmi-system-es-data-1", false)
+	controller := createUnusedPVCsTestController(t, "green", unusedPVC)
+
+	assert.NoError(t, cleanupUnusedPVCs(context.TODO(), controller, vmo))
+	_, err := controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Get(context.TODO(), unusedPVC.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+}
+
+// TestRealignDataDeploymentPVCs Tests repairing the OpenSearch data deployments whose PVC drifted from the PVCs of the VMI
+// GIVEN a data deployment which is down and references a deleted PVC which is not a PVC of the VMI, a missing PVC of
+// the VMI, or a bound PVC of the VMI
+// WHEN I call checkDataDeploymentPVCs and then the rolling update of the data deployments
+// THEN nothing is created nor updated by the check, and the deployment referencing the deleted PVC is realigned with the
+// PVC of the VMI by the rolling update
+func TestRealignDataDeploymentPVCs(t *testing.T) {
+	const expectedPVCName = "vmi-system-es-data"
+	var tests = []struct {
+		name            string
+		existingPVCName string
+		existingPVCs    []*corev1.PersistentVolumeClaim
+		realigned       bool
+	}{
+		{
+			"deployment referencing a deleted PVC is realigned",
+			"vmi-system-es-data-abcde",
+			[]*corev1.PersistentVolumeClaim{makePVC(expectedPVCName, "1Gi")},
+			true,
+		},
+		{
+			"missing PVC is recreated",
+			expectedPVCName,
+			nil,
+			false,
+		},
+		{
+			"bound PVC is left alone",
+			expectedPVCName,
+			[]*corev1.PersistentVolumeClaim{makePVC(expectedPVCName, "1Gi")},
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storageClassName := "standard"
+			vmo := testvmo.DeepCopy()
+			vmo.Spec.Opensearch.Enabled = true
+			vmo.Spec.Opensearch.DataNode.Storage = &vmcontrollerv1.Storage{Size: "1Gi", PvcNames: []string{expectedPVCName}}
+			vmo.Spec.StorageClass = &storageClassName
+
+			makeDataDeployment := func(pvcName string) *appsv1.Deployment {
+				deployment := makeDeploymentWithPVC(makePVC(pvcName, "1Gi"))
+				deployment.Name = resources.GetMetaName(vmo.Name, config.ElasticsearchData.Name+"-0")
+				deployment.Namespace = vmo.Namespace
+				deployment.Spec.Template.Labels = resources.GetSpecID(vmo.Name, config.ElasticsearchData.Name)
+				return deployment
+			}
+			existing := makeDataDeployment(tt.existingPVCName)
+			objects := []runtime.Object{existing}
+			for _, pvc := range tt.existingPVCs {
+				pvc.Status.Phase = corev1.ClaimBound
+				objects = append(objects, pvc)
+			}
+			client := fake.NewSimpleClientset(objects...)
+			deploymentInformer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments()
+			assert.NoError(t, deploymentInformer.Informer().GetIndexer().Add(existing))
+			pvcInformer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Core().V1().PersistentVolumeClaims()
+			for _, pvc := range tt.existingPVCs {
+				assert.NoError(t, pvcInformer.Informer().GetIndexer().Add(pvc))
+			}
+			storageClassInformer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Storage().V1().StorageClasses()
+			assert.NoError(t, storageClassInformer.Informer().GetIndexer().Add(&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: storageClassName}}))
+			c := &Controller{
+				kubeclientset:      client,
+				deploymentLister:   deploymentInformer.Lister(),
+				pvcLister:          pvcInformer.Lister(),
+				storageClassLister: storageClassInformer.Lister(),
+				operatorConfig:     &config.OperatorConfig{},
+				log:                vzlog.DefaultLogger(),
+			}
+
+			err := checkDataDeploymentPVCs(context.TODO(), c, vmo, []*appsv1.Deployment{makeDataDeployment(expectedPVCName)})
+			assert.NoError(t, err)
+			for _, action := range client.Actions() {
+				assert.Equal(t, "get", action.GetVerb())
+			}
+
+			_, err = rollingUpdate(context.TODO(), c, vmo, []*appsv1.Deployment{makeDataDeployment(expectedPVCName)})
+			assert.NoError(t, err)
+			deployment, err := client.AppsV1().Deployments(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+			assert.NoError(t, err)
+			if tt.realigned {
+				assert.Equal(t, expectedPVCName, getDeploymentPVCName(deployment))
+			} else {
+				assert.Equal(t, tt.existingPVCName, getDeploymentPVCName(deployment))
+			}
+		})
+	}
+}
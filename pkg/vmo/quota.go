@@ -0,0 +1,197 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	// quotaExceededReason is the reason of the Warning event and the Degraded condition when a resource quota
+	// rejects a resource of the VMI
+	quotaExceededReason = "QuotaExceeded"
+	// degradedCondition is the type of the VMI condition telling whether the resources of the VMI cannot be created
+	degradedCondition = "Degraded"
+	// reconciledReason is the reason of the Degraded condition once the resources of the VMI can be created again
+	reconciledReason = "Reconciled"
+	// failedCreateReason is the reason of the ReplicaFailure condition of a deployment, and of the Warning event of a
+	// StatefulSet, when its pods cannot be created
+	failedCreateReason = "FailedCreate"
+)
+
+// quotaBackoffTracker tracks the VMIs whose resources were rejected by a resource quota, and backs off creating
+// their resources with an exponential delay. The zero value is ready to use.
+type quotaBackoffTracker struct {
+	keyedTracker[quotaBackoff]
+}
+
+type quotaBackoff struct {
+	message string
+	delay   time.Duration
+	// since is the time of the first of the consecutive rejections, kept until the rejections are reset
+	since      time.Time
+	exceededAt time.Time
+}
+
+// isBackingOff returns true if the creation of the resources of the VMI should not be retried yet
+func (t *quotaBackoffTracker) isBackingOff(key string, now time.Time) bool {
+	backoff, ok := t.get(key)
+	return ok && now.Before(backoff.exceededAt.Add(backoff.delay))
+}
+
+// recordExceeded records that a resource quota rejected a resource of the VMI, and returns the time
+// before the creation is retried, which doubles on each consecutive rejection
+func (t *quotaBackoffTracker) recordExceeded(key, message string, now time.Time) time.Duration {
+	backoff := t.modify(key, func(backoff quotaBackoff, _ bool) (quotaBackoff, bool) {
+		backoff.delay *= 2
+		if backoff.delay == 0 {
+			backoff.delay = constants.QuotaExceededInitialBackoff
+		}
+		if backoff.delay > constants.QuotaExceededMaxBackoff {
+			backoff.delay = constants.QuotaExceededMaxBackoff
+		}
+		if backoff.since.IsZero() {
+			backoff.since = now
+		}
+		backoff.message = message
+		backoff.exceededAt = now
+		return backoff, true
+	})
+	return backoff.delay
+}
+
+// exceeded returns the message of the last rejection of a resource of the VMI and the time of the first of the
+// consecutive rejections, if the resources of the VMI were rejected since the given time or are still backing off
+func (t *quotaBackoffTracker) exceeded(key string, since, now time.Time) (string, time.Time, bool) {
+	backoff, ok := t.get(key)
+	if !ok {
+		return "", time.Time{}, false
+	}
+	if !backoff.exceededAt.Before(since) || now.Before(backoff.exceededAt.Add(backoff.delay)) {
+		return backoff.message, backoff.since, true
+	}
+	return "", time.Time{}, false
+}
+
+// isQuotaExceeded returns true if the error is the rejection of a resource by a resource quota
+func isQuotaExceeded(err error) bool {
+	return k8serrors.IsForbidden(err) && strings.Contains(err.Error(), "exceeded quota")
+}
+
+// checkQuotaBackoff returns an error if the creation of the resources of the VMI is backing off,
+// because a resource quota rejected one of them
+func checkQuotaBackoff(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kind, name string) error {
+	if controller.quotaBackoffs.isBackingOff(vmo.Namespace+"/"+vmo.Name, time.Now()) {
+		return fmt.Errorf("not creating %s %s/%s, backing off as a resource quota was exceeded", kind, vmo.Namespace, name)
+	}
+	return nil
+}
+
+// recordQuotaExceeded records that a resource quota rejected a resource of the VMI with a Warning event, and backs
+// off creating the resources of the VMI. Returns the time before the creation is retried.
+func recordQuotaExceeded(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, message string) time.Duration {
+	delay := controller.quotaBackoffs.recordExceeded(vmo.Namespace+"/"+vmo.Name, message, time.Now())
+	if controller.recorder != nil {
+		controller.recorder.Eventf(vmo, corev1.EventTypeWarning, quotaExceededReason, "%s, retrying in %s", message, delay)
+	}
+	return delay
+}
+
+// handleCreateError returns the error of creating a resource of the VMI. If a resource quota rejected the resource,
+// a Warning event is recorded with the quota detail and the creation of the resources of the VMI backs off.
+func handleCreateError(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, kind, name string, err error) error {
+	if !isQuotaExceeded(err) {
+		return err
+	}
+	message := fmt.Sprintf("Failed to create %s %s/%s: %v", kind, vmo.Namespace, name, err)
+	delay := recordQuotaExceeded(controller, vmo, message)
+	return fmt.Errorf("%s, retrying in %s", message, delay)
+}
+
+// checkPodQuotaExceeded records the rejection of the pods of the workloads of the VMI by a resource quota. Creating a
+// workload succeeds even if its pods exceed a quota, the rejection of the pods is only reported by the ReplicaFailure
+// condition of a deployment, and by the FailedCreate Warning events of a StatefulSet. Nothing is recorded while the
+// creation of the resources of the VMI is backing off.
+func checkPodQuotaExceeded(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	if controller.quotaBackoffs.isBackingOff(vmo.Namespace+"/"+vmo.Name, time.Now()) {
+		return nil
+	}
+	deployments, err := controller.deploymentLister.Deployments(vmo.Namespace).List(labels.SelectorFromSet(resources.GetMetaLabels(vmo)))
+	if err != nil {
+		return err
+	}
+	for _, deployment := range deployments {
+		for _, condition := range deployment.Status.Conditions {
+			if condition.Type == appsv1.DeploymentReplicaFailure && condition.Status == corev1.ConditionTrue &&
+				condition.Reason == failedCreateReason && strings.Contains(condition.Message, "exceeded quota") {
+				recordQuotaExceeded(controller, vmo, fmt.Sprintf("Failed to create the pods of Deployment %s/%s: %s", deployment.Namespace, deployment.Name, condition.Message))
+				return nil
+			}
+		}
+	}
+
+	statefulSets, err := controller.statefulSetLister.StatefulSets(vmo.Namespace).List(labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name}))
+	if err != nil {
+		return err
+	}
+	for _, statefulSet := range statefulSets {
+		// a StatefulSet whose pods were all created has no pod rejected
+		if statefulSet.Spec.Replicas == nil || statefulSet.Status.Replicas >= *statefulSet.Spec.Replicas {
+			continue
+		}
+		events, err := controller.kubeclientset.CoreV1().Events(statefulSet.Namespace).List(ctx, metav1.ListOptions{
+			FieldSelector: fmt.Sprintf("involvedObject.kind=StatefulSet,involvedObject.name=%s,reason=%s", statefulSet.Name, failedCreateReason),
+		})
+		if err != nil {
+			return err
+		}
+		for _, event := range events.Items {
+			if event.InvolvedObject.UID == statefulSet.UID && event.Reason == failedCreateReason && strings.Contains(event.Message, "exceeded quota") {
+				recordQuotaExceeded(controller, vmo, fmt.Sprintf("Failed to create the pods of StatefulSet %s/%s: %s", statefulSet.Namespace, statefulSet.Name, event.Message))
+				return nil
+			}
+		}
+	}
+	return nil
+}
+
+// setDegradedCondition sets the Degraded condition of the VMI, which is true if a resource quota rejected a resource
+// of the VMI since the given start of the reconcile or the creation is still backing off. The condition transitions
+// at the first of the consecutive rejections, whatever the reconciles since. The condition is only added once the VMI
+// has been degraded.
+func setDegradedCondition(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, since time.Time) {
+	key := vmo.Namespace + "/" + vmo.Name
+	condition := metav1.Condition{
+		Type:               degradedCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             reconciledReason,
+		Message:            "The resources of the VMI were created",
+		ObservedGeneration: vmo.Generation,
+	}
+	if message, exceededSince, exceeded := controller.quotaBackoffs.exceeded(key, since, time.Now()); exceeded {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = quotaExceededReason
+		condition.Message = message
+		condition.LastTransitionTime = metav1.NewTime(exceededSince)
+	} else {
+		controller.quotaBackoffs.reset(key)
+		if meta.FindStatusCondition(vmo.Status.Conditions, degradedCondition) == nil {
+			return
+		}
+	}
+	meta.SetStatusCondition(&vmo.Status.Conditions, condition)
+}
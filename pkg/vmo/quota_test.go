@@ -0,0 +1,266 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+)
+
+// newQuotaExceededError returns the error of the API server when a resource quota rejects a resource
+func newQuotaExceededError(resource, name string) error {
+	return k8serrors.NewForbidden(schema.GroupResource{Resource: resource}, name,
+		fmt.Errorf("exceeded quota: compute-resources, requested: pods=1, used: pods=10, limited: pods=10"))
+}
+
+// TestCreateDeploymentsQuotaExceeded Tests creating the deployments of a VMI when a resource quota rejects them
+// GIVEN a VMI with Grafana and a resource quota rejecting the creation of deployments
+// WHEN I call CreateDeployments twice and then set the Degraded condition
+// THEN an error is returned, a Warning event with the quota detail is recorded, the second call backs off
+// without creating the deployment, and the VMI is Degraded
+func TestCreateDeploymentsQuotaExceeded(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Grafana.Enabled = true
+	vmo.Spec.Grafana.Replicas = 1
+
+	client := fake.NewSimpleClientset()
+	createAttempts := 0
+	client.PrependReactor("create", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		createAttempts++
+		return true, nil, newQuotaExceededError("deployments", "grafana")
+	})
+	recorder := record.NewFakeRecorder(10)
+	controller.kubeclientset = client
+	controller.deploymentLister = kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Apps().V1().Deployments().Lister()
+	controller.recorder = recorder
+
+	syncStart := time.Now()
+	_, err := CreateDeployments(context.TODO(), controller, vmo, map[string]string{}, true)
+	assert.Error(t, err)
+	assert.Equal(t, 1, createAttempts)
+	assert.Len(t, recorder.Events, 1)
+	event := <-recorder.Events
+	assert.Contains(t, event, corev1.EventTypeWarning)
+	assert.Contains(t, event, quotaExceededReason)
+	assert.Contains(t, event, "exceeded quota: compute-resources")
+
+	_, err = CreateDeployments(context.TODO(), controller, vmo, map[string]string{}, true)
+	assert.Error(t, err)
+	assert.Equal(t, 1, createAttempts)
+
+	setDegradedCondition(controller, vmo, syncStart)
+	condition := meta.FindStatusCondition(vmo.Status.Conditions, degradedCondition)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, quotaExceededReason, condition.Reason)
+	assert.Contains(t, condition.Message, "exceeded quota: compute-resources")
+}
+
+// TestHandleCreateErrorNotQuota Tests handling an error of creating a resource which is not a quota rejection
+// GIVEN a create error which is not a quota rejection
+// WHEN I call handleCreateError
+// THEN the error is returned as is, no event is recorded and the creation does not back off
+func TestHandleCreateErrorNotQuota(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	recorder := record.NewFakeRecorder(10)
+	controller.recorder = recorder
+
+	createErr := errors.New("connection refused")
+	assert.Equal(t, createErr, handleCreateError(controller, vmo, "Deployment", "grafana", createErr))
+	forbiddenErr := k8serrors.NewForbidden(schema.GroupResource{Resource: "deployments"}, "grafana", errors.New("not allowed"))
+	assert.Equal(t, forbiddenErr, handleCreateError(controller, vmo, "Deployment", "grafana", forbiddenErr))
+	assert.Len(t, recorder.Events, 0)
+	assert.NoError(t, checkQuotaBackoff(controller, vmo, "Deployment", "grafana"))
+}
+
+// TestQuotaBackoffTracker Tests the back off of creating the resources of a VMI rejected by a resource quota
+// GIVEN consecutive quota rejections of the resources of a VMI
+// WHEN I record them
+// THEN the back off doubles up to the maximum back off, and creation is retried once the back off has elapsed
+func TestQuotaBackoffTracker(t *testing.T) {
+	tracker := quotaBackoffTracker{}
+	now := time.Now()
+	assert.False(t, tracker.isBackingOff("ns/vmi", now))
+
+	assert.Equal(t, constants.QuotaExceededInitialBackoff, tracker.recordExceeded("ns/vmi", "exceeded", now))
+	assert.True(t, tracker.isBackingOff("ns/vmi", now))
+	assert.False(t, tracker.isBackingOff("ns/other", now))
+	assert.False(t, tracker.isBackingOff("ns/vmi", now.Add(constants.QuotaExceededInitialBackoff)))
+	assert.Equal(t, 2*constants.QuotaExceededInitialBackoff, tracker.recordExceeded("ns/vmi", "exceeded", now))
+
+	for i := 0; i < 10; i++ {
+		tracker.recordExceeded("ns/vmi", "exceeded", now)
+	}
+	assert.Equal(t, constants.QuotaExceededMaxBackoff, tracker.recordExceeded("ns/vmi", "exceeded", now))
+
+	tracker.reset("ns/vmi")
+	assert.False(t, tracker.isBackingOff("ns/vmi", now))
+}
+
+// TestSetDegradedConditionRecovered Tests the Degraded condition of a VMI whose resources can be created again
+// GIVEN a VMI which has never been degraded, and a Degraded VMI whose quota back off has elapsed without new rejections
+// WHEN I call setDegradedCondition
+// THEN no condition is added to the VMI which has never been degraded, and the Degraded VMI is no longer Degraded
+func TestSetDegradedConditionRecovered(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	setDegradedCondition(controller, vmo, time.Now())
+	assert.Empty(t, vmo.Status.Conditions)
+
+	key := vmo.Namespace + "/" + vmo.Name
+	controller.quotaBackoffs.recordExceeded(key, "exceeded", time.Now().Add(-2*constants.QuotaExceededInitialBackoff))
+	meta.SetStatusCondition(&vmo.Status.Conditions, metav1.Condition{
+		Type:    degradedCondition,
+		Status:  metav1.ConditionTrue,
+		Reason:  quotaExceededReason,
+		Message: "exceeded",
+	})
+	setDegradedCondition(controller, vmo, time.Now())
+	condition := meta.FindStatusCondition(vmo.Status.Conditions, degradedCondition)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, reconciledReason, condition.Reason)
+	assert.False(t, controller.quotaBackoffs.isBackingOff(key, time.Now()))
+}
+
+// TestCheckPodQuotaExceeded Tests detecting the pods of the workloads of a VMI rejected by a resource quota
+// GIVEN a deployment whose ReplicaFailure condition reports a quota rejection, or a StatefulSet missing pods with a
+// FailedCreate Warning event reporting a quota rejection, or workloads whose pods were created
+// WHEN I call checkPodQuotaExceeded and then set the Degraded condition
+// THEN the VMI is Degraded with the quota detail and a Warning event is recorded only for the rejected pods
+func TestCheckPodQuotaExceeded(t *testing.T) {
+	quotaMessage := `pods "vmi-system-grafana-1" is forbidden: exceeded quota: compute-resources, requested: pods=1, used: pods=10, limited: pods=10`
+	var tests = []struct {
+		name     string
+		objects  func(labels map[string]string) []runtime.Object
+		degraded bool
+	}{
+		{
+			"deployment with pods rejected by a quota",
+			func(labels map[string]string) []runtime.Object {
+				return []runtime.Object{&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{Name: "vmi-system-grafana", Namespace: constants.VerrazzanoSystemNamespace, Labels: labels},
+					Status: appsv1.DeploymentStatus{Conditions: []appsv1.DeploymentCondition{
+						{Type: appsv1.DeploymentReplicaFailure, Status: corev1.ConditionTrue, Reason: failedCreateReason, Message: quotaMessage},
+					}},
+				}}
+			},
+			true,
+		},
+		{
+			"StatefulSet with pods rejected by a quota",
+			func(labels map[string]string) []runtime.Object {
+				return []runtime.Object{
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Name: "vmi-system-es-master", Namespace: constants.VerrazzanoSystemNamespace, UID: "sts", Labels: labels},
+						Spec:       appsv1.StatefulSetSpec{Replicas: resources.NewVal(3)},
+						Status:     appsv1.StatefulSetStatus{Replicas: 1},
+					},
+					&corev1.Event{
+						ObjectMeta:     metav1.ObjectMeta{Name: "vmi-system-es-master.1", Namespace: constants.VerrazzanoSystemNamespace},
+						InvolvedObject: corev1.ObjectReference{Kind: "StatefulSet", Name: "vmi-system-es-master", UID: "sts"},
+						Reason:         failedCreateReason,
+						Type:           corev1.EventTypeWarning,
+						Message:        "create Pod vmi-system-es-master-1 in StatefulSet vmi-system-es-master failed error: " + quotaMessage,
+					},
+				}
+			},
+			true,
+		},
+		{
+			"workloads whose pods were created",
+			func(labels map[string]string) []runtime.Object {
+				return []runtime.Object{
+					&appsv1.Deployment{
+						ObjectMeta: metav1.ObjectMeta{Name: "vmi-system-grafana", Namespace: constants.VerrazzanoSystemNamespace, Labels: labels},
+					},
+					&appsv1.StatefulSet{
+						ObjectMeta: metav1.ObjectMeta{Name: "vmi-system-es-master", Namespace: constants.VerrazzanoSystemNamespace, UID: "sts", Labels: labels},
+						Spec:       appsv1.StatefulSetSpec{Replicas: resources.NewVal(3)},
+						Status:     appsv1.StatefulSetStatus{Replicas: 3},
+					},
+				}
+			},
+			false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller, vmo := createControllerForTesting()
+			labels := resources.GetMetaLabels(vmo)
+			labels[constants.VMOLabel] = vmo.Name
+			objects := tt.objects(labels)
+			client := fake.NewSimpleClientset(objects...)
+			factory := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod)
+			deploymentInformer := factory.Apps().V1().Deployments()
+			statefulSetInformer := factory.Apps().V1().StatefulSets()
+			for _, object := range objects {
+				switch object.(type) {
+				case *appsv1.Deployment:
+					assert.NoError(t, deploymentInformer.Informer().GetIndexer().Add(object))
+				case *appsv1.StatefulSet:
+					assert.NoError(t, statefulSetInformer.Informer().GetIndexer().Add(object))
+				}
+			}
+			recorder := record.NewFakeRecorder(10)
+			controller.kubeclientset = client
+			controller.deploymentLister = deploymentInformer.Lister()
+			controller.statefulSetLister = statefulSetInformer.Lister()
+			controller.recorder = recorder
+
+			syncStart := time.Now()
+			assert.NoError(t, checkPodQuotaExceeded(context.TODO(), controller, vmo))
+			// the rejection is not recorded again while backing off
+			assert.NoError(t, checkPodQuotaExceeded(context.TODO(), controller, vmo))
+			setDegradedCondition(controller, vmo, syncStart)
+			condition := meta.FindStatusCondition(vmo.Status.Conditions, degradedCondition)
+			if tt.degraded {
+				assert.Len(t, recorder.Events, 1)
+				assert.NotNil(t, condition)
+				assert.Equal(t, metav1.ConditionTrue, condition.Status)
+				assert.Contains(t, condition.Message, "exceeded quota: compute-resources")
+			} else {
+				assert.Len(t, recorder.Events, 0)
+				assert.Nil(t, condition)
+			}
+		})
+	}
+}
+
+// TestSetDegradedConditionSince Tests the transition time of the Degraded condition of a VMI rejected repeatedly
+// GIVEN consecutive quota rejections of the resources of a VMI over several reconciles
+// WHEN I call setDegradedCondition after each rejection
+// THEN the Degraded condition transitioned at the first rejection
+func TestSetDegradedConditionSince(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	key := vmo.Namespace + "/" + vmo.Name
+	first := time.Now().Add(-time.Hour).Truncate(time.Second)
+	controller.quotaBackoffs.recordExceeded(key, "exceeded", first)
+	setDegradedCondition(controller, vmo, first)
+	controller.quotaBackoffs.recordExceeded(key, "exceeded again", time.Now())
+	setDegradedCondition(controller, vmo, time.Now().Add(-time.Second))
+
+	condition := meta.FindStatusCondition(vmo.Status.Conditions, degradedCondition)
+	assert.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "exceeded again", condition.Message)
+	assert.True(t, first.Equal(condition.LastTransitionTime.Time))
+}
@@ -0,0 +1,34 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"errors"
+)
+
+// errStatusUpdate is wrapped by the error returned when the VMI could not be updated with its status. The VMI is
+// requeued with a back-off, so that the status tracking the resources configured in OpenSearch is not lost.
+var errStatusUpdate = errors.New("failed to update the status of the VMI")
+
+// waitingError is returned by a reconcile step which is waiting for a temporary condition, e.g. a deployment being
+// scaled up one replica at a time. It is not a failure of the reconcile: the VMI is requeued with a back-off, and the
+// condition is neither logged nor counted as an error.
+type waitingError struct {
+	message string
+}
+
+func (e *waitingError) Error() string {
+	return e.message
+}
+
+// newWaitingError returns a waitingError with the given message
+func newWaitingError(message string) error {
+	return &waitingError{message: message}
+}
+
+// isWaitingError returns true if the error, or an error it wraps, is a waitingError
+func isWaitingError(err error) bool {
+	var waitingErr *waitingError
+	return errors.As(err, &waitingErr)
+}
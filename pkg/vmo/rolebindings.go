@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -18,13 +18,14 @@ import (
 )
 
 // CreateRoleBindings creates/updates VMO RoleBindings k8s resources
-func CreateRoleBindings(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func CreateRoleBindings(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 	metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesRoleBindings)
 	if metricErr != nil {
 		return metricErr
 	}
 	metric.Inc()
-	controller.log.Oncef("Creating/updating RoleBindings for VMI %s", vmo.Name)
+	log.Oncef("Creating/updating RoleBindings for VMI %s", vmo.Name)
 
 	newRoleBindings, err := NewRoleBindings(vmo, controller)
 	if err != nil {
@@ -41,15 +42,15 @@ func CreateRoleBindings(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 		if existingRoleBinding != nil {
 			specDiffs := diff.Diff(existingRoleBinding, newRoleBinding)
 			if specDiffs != "" {
-				controller.log.Debugf("RoleBinding %s : Spec differences %s", newRoleBinding.Name, specDiffs)
-				err = controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Delete(context.TODO(), newRoleBinding.Name, metav1.DeleteOptions{})
+				log.Debugf("RoleBinding %s : Spec differences %s", newRoleBinding.Name, specDiffs)
+				err = controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Delete(ctx, newRoleBinding.Name, metav1.DeleteOptions{})
 				if err != nil {
-					controller.log.Errorf("Failed deleting role binding %s: %v", newRoleBinding.Name, err)
+					log.Errorf("Failed deleting role binding %s: %v", newRoleBinding.Name, err)
 				}
-				_, err = controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Create(context.TODO(), newRoleBinding, metav1.CreateOptions{})
+				_, err = controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Create(ctx, newRoleBinding, metav1.CreateOptions{})
 			}
 		} else {
-			_, err = controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Create(context.TODO(), newRoleBinding, metav1.CreateOptions{})
+			_, err = controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Create(ctx, newRoleBinding, metav1.CreateOptions{})
 		}
 		if err != nil {
 			return err
@@ -57,7 +58,7 @@ func CreateRoleBindings(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 	}
 
 	// Delete RoleBindings that shouldn't exist
-	controller.log.Debugf("Deleting unwanted RoleBindings for VMI '%s' in namespace '%s'", vmo.Name, vmo.Namespace)
+	log.Debugf("Deleting unwanted RoleBindings for VMI '%s' in namespace '%s'", vmo.Name, vmo.Namespace)
 	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
 	existingRoleBindings, err := controller.roleBindingLister.RoleBindings(vmo.Namespace).List(selector)
 	if err != nil {
@@ -72,10 +73,10 @@ func CreateRoleBindings(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 	}
 	for _, roleBinding := range existingRoleBindings {
 		if !contains(roleBindingNames, roleBinding.Name) {
-			controller.log.Oncef("Deleting RoleBinding %s", roleBinding.Name)
-			err := controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Delete(context.TODO(), roleBinding.Name, metav1.DeleteOptions{})
+			log.Oncef("Deleting RoleBinding %s", roleBinding.Name)
+			err := controller.kubeclientset.RbacV1().RoleBindings(vmo.Namespace).Delete(ctx, roleBinding.Name, metav1.DeleteOptions{})
 			if err != nil {
-				controller.log.Errorf("Failed to delete RoleBinding %s: %v", roleBinding.Name, err)
+				log.Errorf("Failed to delete RoleBinding %s: %v", roleBinding.Name, err)
 				return err
 			}
 		}
@@ -0,0 +1,41 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+
+	"fmt"
+
+	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+)
+
+// importSavedObjects imports the saved objects of the saved objects ConfigMap of the VMI into OpenSearch Dashboards.
+// The checksum of the imported content is recorded in the VMI status, so the saved objects are only imported again
+// when the content of the ConfigMap changes.
+func importSavedObjects(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
+	configMapName := vmo.Spec.OpensearchDashboards.SavedObjectsConfigMap
+	if !vmo.Spec.OpensearchDashboards.Enabled || configMapName == "" {
+		return nil
+	}
+	configMap, err := controller.configMapLister.ConfigMaps(vmo.Namespace).Get(configMapName)
+	if err != nil {
+		return fmt.Errorf("failed to get the saved objects ConfigMap %s/%s: %v", vmo.Namespace, configMapName, err)
+	}
+	checksum := dashboards.SavedObjectsChecksum(configMap.Data)
+	if checksum == vmo.Status.SavedObjectsChecksum {
+		return nil
+	}
+	imported, err := controller.osDashboardsClient.ImportSavedObjects(log, resources.GetOpenSearchDashboardsHTTPEndpoint(vmo), configMap.Data)
+	if err != nil {
+		return err
+	}
+	if imported {
+		vmo.Status.SavedObjectsChecksum = checksum
+	}
+	return nil
+}
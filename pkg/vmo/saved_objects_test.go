@@ -0,0 +1,73 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	dashboards "github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch_dashboards"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestImportSavedObjectsOncePerContent Tests that the saved objects are imported once per content of their ConfigMap
+// GIVEN a VMI with a saved objects ConfigMap, and a ready OpenSearch Dashboards
+// WHEN the saved objects are imported, then imported again with the same and with changed content
+// THEN the saved objects are only imported for new content, and the checksum of the content is recorded in the VMI status
+func TestImportSavedObjectsOncePerContent(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	vmo.Spec.OpensearchDashboards.SavedObjectsConfigMap = "saved-objects"
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "saved-objects", Namespace: vmo.Namespace},
+		Data:       map[string]string{"objects.ndjson": `{"id": "logs", "type": "index-pattern", "attributes": {"title": "logs-*"}}`},
+	}
+	_, err := controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Create(context.TODO(), configMap, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	imports := 0
+	osdClient := dashboards.NewOSDashboardsClient()
+	osdClient.DoHTTP = func(request *http.Request) (*http.Response, error) {
+		if request.URL.Path == "/api/saved_objects/_import" {
+			imports++
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"success": true, "successCount": 1}`))}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{}`))}, nil
+	}
+	controller.osDashboardsClient = osdClient
+
+	assert.NoError(t, importSavedObjects(context.TODO(), controller, vmo))
+	assert.Equal(t, 1, imports)
+	assert.Equal(t, dashboards.SavedObjectsChecksum(configMap.Data), vmo.Status.SavedObjectsChecksum)
+
+	// unchanged content is not imported again
+	assert.NoError(t, importSavedObjects(context.TODO(), controller, vmo))
+	assert.Equal(t, 1, imports)
+
+	// changed content is imported
+	configMap.Data["objects.ndjson"] = `{"id": "logs", "type": "index-pattern", "attributes": {"title": "verrazzano-*"}}`
+	_, err = controller.kubeclientset.CoreV1().ConfigMaps(vmo.Namespace).Update(context.TODO(), configMap, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	assert.NoError(t, importSavedObjects(context.TODO(), controller, vmo))
+	assert.Equal(t, 2, imports)
+	assert.Equal(t, dashboards.SavedObjectsChecksum(configMap.Data), vmo.Status.SavedObjectsChecksum)
+}
+
+// TestImportSavedObjectsMissingConfigMap Tests importing the saved objects of a missing ConfigMap
+// GIVEN a VMI with a saved objects ConfigMap which does not exist
+// WHEN the saved objects are imported
+// THEN an error is returned and no checksum is recorded
+func TestImportSavedObjectsMissingConfigMap(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	vmo.Spec.OpensearchDashboards.SavedObjectsConfigMap = "missing"
+
+	assert.Error(t, importSavedObjects(context.TODO(), controller, vmo))
+	assert.Empty(t, vmo.Status.SavedObjectsChecksum)
+}
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -92,7 +92,8 @@ func GetClusterNameFromSecret(controller *Controller, namespace string) (string,
 }
 
 // CreateOrUpdateAuthSecrets create/updates auth secrets
-func CreateOrUpdateAuthSecrets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, credsMap map[string]string) error {
+func CreateOrUpdateAuthSecrets(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, credsMap map[string]string) error {
+	log := reconcileLog(ctx)
 
 	passwords := HashedPasswords(map[string]string{})
 	for k, v := range credsMap {
@@ -110,9 +111,9 @@ func CreateOrUpdateAuthSecrets(controller *Controller, vmo *vmcontrollerv1.Verra
 		isEqual := reflect.DeepEqual(secretData, secret.Data)
 		if !isEqual {
 			secret.Data = secretData
-			_, err = controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+			_, err = controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
 			if err != nil {
-				return controller.log.ErrorfNewErr("Failed to update a basic auth secret %s:%s: %v", vmo.Namespace, vmo.Spec.SecretName, err)
+				return log.ErrorfNewErr("Failed to update a basic auth secret %s:%s: %v", vmo.Namespace, vmo.Spec.SecretName, err)
 			}
 		}
 		return nil
@@ -121,13 +122,13 @@ func CreateOrUpdateAuthSecrets(controller *Controller, vmo *vmcontrollerv1.Verra
 	// create the secret based on the Username/Password passed in the spec
 	secret, err = secrets.New(vmo, vmo.Spec.SecretName, []byte(auth))
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed creating a password hash, err: %v", err)
+		return log.ErrorfNewErr("Failed creating a password hash, err: %v", err)
 	}
-	secretOut, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+	secretOut, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Create(ctx, secret, metav1.CreateOptions{})
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed creating secret %s/%s: %v", vmo.Namespace, vmo.Spec.SecretName, err)
+		return log.ErrorfNewErr("Failed creating secret %s/%s: %v", vmo.Namespace, vmo.Spec.SecretName, err)
 	}
-	controller.log.Debugf("Created secret: %s", secretOut.Name)
+	log.Debugf("Created secret: %s", secretOut.Name)
 
 	// Delete Auth secrets if it is not supposed to exists
 
@@ -135,14 +136,14 @@ func CreateOrUpdateAuthSecrets(controller *Controller, vmo *vmcontrollerv1.Verra
 	selector := labels.SelectorFromSet(map[string]string{constants.VMOLabel: vmo.Name})
 	secretList, err := controller.secretLister.Secrets(vmo.Namespace).List(selector)
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed listing secrets in namespace %s: %v", vmo.Namespace, err)
+		return log.ErrorfNewErr("Failed listing secrets in namespace %s: %v", vmo.Namespace, err)
 	}
 	for _, existedSecret := range secretList {
 		if !contains(secretsNames, existedSecret.Name) {
-			controller.log.Debugf("Deleting secret %s", existedSecret.Name)
-			err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Delete(context.TODO(), existedSecret.Name, metav1.DeleteOptions{})
+			log.Debugf("Deleting secret %s", existedSecret.Name)
+			err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Delete(ctx, existedSecret.Name, metav1.DeleteOptions{})
 			if err != nil {
-				return controller.log.ErrorfNewErr("Failed to delete secret %s/%s: %v", vmo.Namespace, existedSecret.Name, err)
+				return log.ErrorfNewErr("Failed to delete secret %s/%s: %v", vmo.Namespace, existedSecret.Name, err)
 			}
 		}
 	}
@@ -151,10 +152,11 @@ func CreateOrUpdateAuthSecrets(controller *Controller, vmo *vmcontrollerv1.Verra
 }
 
 // CreateOrUpdateTLSSecrets create/updates TLS secrets
-func CreateOrUpdateTLSSecrets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func CreateOrUpdateTLSSecrets(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 
 	if vmo.Spec.AutoSecret {
-		controller.log.Debug("Not explicitly creating TLS secret, we expect it to be auto-generated")
+		log.Debug("Not explicitly creating TLS secret, we expect it to be auto-generated")
 		// by setting the AutoSecret to true we ask that a certificate be made for us
 		// currently the mechanism relies on ingressShim part of cert-manager to notice the
 		// annotation we set on the ingress rule which is set off by AutoSecret being true
@@ -164,14 +166,14 @@ func CreateOrUpdateTLSSecrets(controller *Controller, vmo *vmcontrollerv1.Verraz
 	tlsCrt, err := controller.loadSecretData(vmo.Namespace,
 		vmo.Spec.SecretsName, constants.TLSCRTName)
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed getting tls.crt data using name %s, from secret %s/%s: %v",
+		return log.ErrorfNewErr("Failed getting tls.crt data using name %s, from secret %s/%s: %v",
 			constants.TLSCRTName, vmo.Namespace, vmo.Spec.SecretsName, err)
 	}
 	//get tlsKey from vmoSecrets
 	tlsKey, err := controller.loadSecretData(vmo.Namespace,
 		vmo.Spec.SecretsName, constants.TLSKeyName)
 	if err != nil {
-		return controller.log.ErrorfNewErr("Failed getting tls.key data using name %s, from secret %s/%s: %v",
+		return log.ErrorfNewErr("Failed getting tls.key data using name %s, from secret %s/%s: %v",
 			constants.TLSKeyName, vmo.Namespace, vmo.Spec.SecretsName, err)
 	}
 
@@ -185,22 +187,22 @@ func CreateOrUpdateTLSSecrets(controller *Controller, vmo *vmcontrollerv1.Verraz
 			isSecretDataEqual := reflect.DeepEqual(secretData, secret.Data)
 			if !isSecretDataEqual {
 				secret.Data = secretData
-				_, err = controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Update(context.TODO(), secret, metav1.UpdateOptions{})
+				_, err = controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Update(ctx, secret, metav1.UpdateOptions{})
 				if err != nil {
-					return controller.log.ErrorfNewErr("Failed to updated basic auth secret %s/%s: err: %v", vmo.Namespace, vmo.Name+"-tls", err)
+					return log.ErrorfNewErr("Failed to updated basic auth secret %s/%s: err: %v", vmo.Namespace, vmo.Name+"-tls", err)
 				}
 			}
 			return nil
 		}
 		secret, err = secrets.NewTLS(vmo, vmo.Name+"-tls", secretData)
 		if err != nil {
-			return controller.log.ErrorfNewErr("Failed trying to create a password hash: %v", err)
+			return log.ErrorfNewErr("Failed trying to create a password hash: %v", err)
 		}
-		secretOut, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Create(context.TODO(), secret, metav1.CreateOptions{})
+		secretOut, err := controller.kubeclientset.CoreV1().Secrets(vmo.Namespace).Create(ctx, secret, metav1.CreateOptions{})
 		if err != nil {
-			return controller.log.ErrorfNewErr("Failed to create secret %s/%s: %v", vmo.Namespace, vmo.Name+"-tls", err)
+			return log.ErrorfNewErr("Failed to create secret %s/%s: %v", vmo.Namespace, vmo.Name+"-tls", err)
 		}
-		controller.log.Debugf("Create TLS secret: %s", secretOut.Name)
+		log.Debugf("Create TLS secret: %s", secretOut.Name)
 	}
 	return nil
 }
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -12,32 +12,34 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/metricsexporter"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/services"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/runtime"
 )
 
 // CreateServices creates/updates/deletes VMO service k8s resources
-func CreateServices(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func CreateServices(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 	counter, counterErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesServices)
 	if counterErr != nil {
 		return counterErr
 	}
 	counter.Inc()
 
-	useNodeRoleSelectors, err := clusterHasNodeRoleSelectors(controller, vmo)
+	useNodeRoleSelectors, err := clusterHasNodeRoleSelectors(ctx, controller, vmo)
 	if err != nil {
-		controller.log.Errorf("Failed to check node role selectors when creating services for VMI %s: %s", vmo.Name, err)
+		log.Errorf("Failed to check node role selectors when creating services for VMI %s: %s", vmo.Name, err)
 		return err
 	}
 
 	svcList, err := services.New(vmo, useNodeRoleSelectors)
 	if err != nil {
-		controller.log.Errorf("Failed to create Services for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to create Services for VMI %s: %v", vmo.Name, err)
 		return err
 	}
 	var serviceNames []string
-	controller.log.Oncef("Creating/updating Services for VMI %s", vmo.Name)
+	log.Oncef("Creating/updating Services for VMI %s", vmo.Name)
 	for _, curService := range svcList {
 		serviceName := curService.Name
 		serviceNames = append(serviceNames, serviceName)
@@ -49,27 +51,32 @@ func CreateServices(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonito
 			return nil
 		}
 
-		controller.log.Debugf("Applying Service '%s' in namespace '%s' for VMI '%s'\n", serviceName, vmo.Namespace, vmo.Name)
+		log.Debugf("Applying Service '%s' in namespace '%s' for VMI '%s'\n", serviceName, vmo.Namespace, vmo.Name)
 		existingService, err := controller.serviceLister.Services(vmo.Namespace).Get(serviceName)
 		if existingService != nil {
 			specDiffs := diff.Diff(existingService, curService)
 			if specDiffs != "" {
-				controller.log.Debugf("Service %s : Spec differences %s", curService.Name, specDiffs)
-				err = controller.kubeclientset.CoreV1().Services(vmo.Namespace).Delete(context.TODO(), serviceName, metav1.DeleteOptions{})
-				if err != nil {
-					controller.log.Errorf("Failed to delete service %s: %v", serviceName, err)
+				log.Debugf("Service %s : Spec differences %s", curService.Name, specDiffs)
+				if serviceNeedsRecreate(existingService, curService) {
+					// The type and cluster IP of a service cannot be changed in place
+					err = controller.kubeclientset.CoreV1().Services(vmo.Namespace).Delete(ctx, serviceName, metav1.DeleteOptions{})
+					if err != nil {
+						log.Errorf("Failed to delete service %s: %v", serviceName, err)
+					}
+					_, err = controller.kubeclientset.CoreV1().Services(vmo.Namespace).Create(ctx, curService, metav1.CreateOptions{})
+				} else {
+					_, err = controller.kubeclientset.CoreV1().Services(vmo.Namespace).Update(ctx, updatedService(existingService, curService), metav1.UpdateOptions{})
 				}
-				_, err = controller.kubeclientset.CoreV1().Services(vmo.Namespace).Create(context.TODO(), curService, metav1.CreateOptions{})
 			}
 		} else {
-			_, err = controller.kubeclientset.CoreV1().Services(vmo.Namespace).Create(context.TODO(), curService, metav1.CreateOptions{})
+			_, err = controller.kubeclientset.CoreV1().Services(vmo.Namespace).Create(ctx, curService, metav1.CreateOptions{})
 		}
 
 		if err != nil {
-			controller.log.Errorf("Failed to apply Service for VMI %s: %v", vmo.Name, err)
+			log.Errorf("Failed to apply Service for VMI %s: %v", vmo.Name, err)
 			return err
 		}
-		controller.log.Debugf("Successfully applied Service '%s'\n", serviceName)
+		log.Debugf("Successfully applied Service '%s'\n", serviceName)
 		metric, metricErr := metricsexporter.GetCounterMetrics(metricsexporter.NamesServicesCreated)
 		if metricErr != nil {
 			return metricErr
@@ -85,10 +92,10 @@ func CreateServices(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonito
 	}
 	for _, service := range existingServicesList {
 		if !contains(serviceNames, service.Name) {
-			controller.log.Debugf("Deleting service %s", service.Name)
-			err := controller.kubeclientset.CoreV1().Services(vmo.Namespace).Delete(context.TODO(), service.Name, metav1.DeleteOptions{})
+			log.Debugf("Deleting service %s", service.Name)
+			err := controller.kubeclientset.CoreV1().Services(vmo.Namespace).Delete(ctx, service.Name, metav1.DeleteOptions{})
 			if err != nil {
-				controller.log.Errorf("Failed to delete service %s: %v", service.Name, err)
+				log.Errorf("Failed to delete service %s: %v", service.Name, err)
 				return err
 			}
 		}
@@ -101,9 +108,39 @@ func CreateServices(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonito
 	return nil
 }
 
-func clusterHasNodeRoleSelectors(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (bool, error) {
+// serviceNeedsRecreate returns true if the existing service must be recreated to get the given service, because the
+// type or the cluster IP of the service changed
+func serviceNeedsRecreate(existingService, curService *corev1.Service) bool {
+	if curService.Spec.Type != "" && curService.Spec.Type != existingService.Spec.Type {
+		return true
+	}
+	return curService.Spec.ClusterIP != "" && curService.Spec.ClusterIP != existingService.Spec.ClusterIP
+}
+
+// updatedService returns the given service with the values assigned by Kubernetes to the existing service, so that
+// the existing service can be updated in place
+func updatedService(existingService, curService *corev1.Service) *corev1.Service {
+	service := curService.DeepCopy()
+	service.ResourceVersion = existingService.ResourceVersion
+	service.Spec.ClusterIP = existingService.Spec.ClusterIP
+	service.Spec.ClusterIPs = existingService.Spec.ClusterIPs
+	service.Spec.IPFamilies = existingService.Spec.IPFamilies
+	service.Spec.IPFamilyPolicy = existingService.Spec.IPFamilyPolicy
+	service.Spec.HealthCheckNodePort = existingService.Spec.HealthCheckNodePort
+	// keep the node ports allocated to the existing ports
+	for i, port := range service.Spec.Ports {
+		for _, existingPort := range existingService.Spec.Ports {
+			if port.NodePort == 0 && port.Name == existingPort.Name && port.Port == existingPort.Port {
+				service.Spec.Ports[i].NodePort = existingPort.NodePort
+			}
+		}
+	}
+	return service
+}
+
+func clusterHasNodeRoleSelectors(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (bool, error) {
 	selector := services.OpenSearchPodSelector(vmo.Name)
-	pods, err := controller.kubeclientset.CoreV1().Pods(vmo.Namespace).List(context.TODO(), metav1.ListOptions{LabelSelector: selector})
+	pods, err := controller.kubeclientset.CoreV1().Pods(vmo.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
 	if err != nil {
 		return false, err
 	}
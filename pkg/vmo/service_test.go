@@ -0,0 +1,93 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// TestCreateServicesUpdateInPlace Tests that a changed service is updated in place
+// GIVEN an existing Grafana service with an assigned cluster IP and a VMI with a new Grafana session affinity
+// WHEN I call CreateServices
+// THEN the Grafana service is updated without being deleted, and keeps its cluster IP
+func TestCreateServicesUpdateInPlace(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Grafana.Enabled = true
+	vmo.Spec.ServiceType = corev1.ServiceTypeClusterIP
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.GetMetaName(vmo.Name, config.Grafana.Name),
+			Namespace: vmo.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type:            corev1.ServiceTypeClusterIP,
+			ClusterIP:       "10.96.0.10",
+			SessionAffinity: corev1.ServiceAffinityNone,
+		},
+	}
+	vmo.Spec.Grafana.SessionAffinity = corev1.ServiceAffinityClientIP
+
+	client := fake.NewSimpleClientset(existing)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Core().V1().Services()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	controller.kubeclientset = client
+	controller.serviceLister = informer.Lister()
+
+	assert.NoError(t, CreateServices(context.TODO(), controller, vmo))
+	for _, action := range client.Actions() {
+		assert.NotEqual(t, "delete", action.GetVerb())
+	}
+	updated, err := client.CoreV1().Services(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ServiceAffinityClientIP, updated.Spec.SessionAffinity)
+	assert.Equal(t, "10.96.0.10", updated.Spec.ClusterIP)
+	assert.Equal(t, resources.GetSpecID(vmo.Name, config.Grafana.Name), updated.Spec.Selector)
+}
+
+// TestCreateServicesRecreate Tests that a service whose type changed is recreated
+// GIVEN an existing NodePort Grafana service and a VMI with the ClusterIP service type
+// WHEN I call CreateServices
+// THEN the Grafana service is deleted and created again with the ClusterIP type
+func TestCreateServicesRecreate(t *testing.T) {
+	controller, vmo := createControllerForTesting()
+	vmo.Spec.Grafana.Enabled = true
+	vmo.Spec.ServiceType = corev1.ServiceTypeClusterIP
+	existing := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      resources.GetMetaName(vmo.Name, config.Grafana.Name),
+			Namespace: vmo.Namespace,
+		},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeNodePort,
+		},
+	}
+
+	client := fake.NewSimpleClientset(existing)
+	informer := kubeinformers.NewSharedInformerFactory(client, constants.ResyncPeriod).Core().V1().Services()
+	assert.NoError(t, informer.Informer().GetIndexer().Add(existing))
+	controller.kubeclientset = client
+	controller.serviceLister = informer.Lister()
+
+	assert.NoError(t, CreateServices(context.TODO(), controller, vmo))
+	deletes := 0
+	for _, action := range client.Actions() {
+		if action.GetVerb() == "delete" {
+			deletes++
+		}
+	}
+	assert.Equal(t, 1, deletes)
+	recreated, err := client.CoreV1().Services(vmo.Namespace).Get(context.TODO(), existing.Name, metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, recreated.Spec.Type)
+}
@@ -17,10 +17,17 @@ import (
 )
 
 // CreateStatefulSets creates/updates/deletes VMO statefulset k8s resources
-func CreateStatefulSets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (bool, error) {
+func CreateStatefulSets(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) (bool, error) {
+	log := reconcileLog(ctx)
+	if err := checkMasterQuorum(ctx, controller, vmo); err != nil {
+		return false, err
+	}
+	if err := checkOpenSearchDowngrade(ctx, controller, vmo); err != nil {
+		return false, err
+	}
 	storageClass, err := getStorageClassOverride(controller, vmo.Spec.StorageClass)
 	if err != nil {
-		controller.log.Errorf("Failed to determine storage class for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to determine storage class for VMI %s: %v", vmo.Name, err)
 		return false, err
 	}
 
@@ -30,19 +37,22 @@ func CreateStatefulSets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 		return false, err
 	}
 	initialMasterNodes := getInitialMasterNodes(vmo, existingList)
-	expectedList, err := statefulsets.New(controller.log, vmo, storageClass, initialMasterNodes)
+	expectedList, err := statefulsets.New(log, vmo, storageClass, initialMasterNodes)
 	if err != nil {
-		controller.log.Errorf("Failed to create StatefulSet specs for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to create StatefulSet specs for VMI %s: %v", vmo.Name, err)
 		return false, err
 	}
 
 	// Loop through the existing stateful sets and create/update as needed
-	controller.log.Oncef("Creating/updating Statefulsets for VMI %s", vmo.Name)
-	plan := statefulsets.CreatePlan(controller.log, existingList, expectedList)
+	log.Oncef("Creating/updating Statefulsets for VMI %s", vmo.Name)
+	plan := statefulsets.CreatePlan(log, existingList, expectedList)
 
 	for _, sts := range plan.Create {
-		if _, err := controller.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Create(context.TODO(), sts, metav1.CreateOptions{}); err != nil {
-			return plan.ExistingCluster, logReturnError(controller.log, sts, err)
+		if err := checkQuotaBackoff(controller, vmo, "StatefulSet", sts.Name); err != nil {
+			return plan.ExistingCluster, err
+		}
+		if _, err := controller.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Create(ctx, sts, metav1.CreateOptions{}); err != nil {
+			return plan.ExistingCluster, logReturnError(log, sts, handleCreateError(controller, vmo, "StatefulSet", sts.Name, err))
 		}
 	}
 
@@ -52,19 +62,19 @@ func CreateStatefulSets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 		return plan.ExistingCluster, err
 	}
 	for _, sts := range latestList {
-		if err := updateOwnerForPVCs(controller, sts, vmo); err != nil {
+		if err := updateOwnerForPVCs(ctx, controller, sts, vmo); err != nil {
 			return plan.ExistingCluster, err
 		}
 	}
 
 	for _, sts := range plan.Update {
-		if err := updateStatefulSet(controller, sts, vmo, plan); err != nil {
-			return plan.ExistingCluster, logReturnError(controller.log, sts, err)
+		if err := updateStatefulSet(ctx, controller, sts, vmo, plan); err != nil {
+			return plan.ExistingCluster, logReturnError(log, sts, err)
 		}
 	}
 
 	for _, sts := range plan.Delete {
-		if err := scaleDownStatefulSet(controller, expectedList, sts, vmo); err != nil {
+		if err := scaleDownStatefulSet(ctx, controller, expectedList, sts, vmo); err != nil {
 			return plan.ExistingCluster, err
 		}
 		// We only scale down one statefulset at a time. This gives the statefulset data
@@ -72,10 +82,17 @@ func CreateStatefulSets(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMo
 		break
 	}
 
+	// staged rollouts only advance once the StatefulSets are neither updated nor scaled down
+	if len(plan.Update) == 0 && len(plan.Delete) == 0 {
+		if err := advanceMasterRollouts(ctx, controller, vmo, latestList); err != nil {
+			return plan.ExistingCluster, err
+		}
+	}
+
 	if plan.Conflict == nil {
-		controller.log.Oncef("Successfully applied StatefulSets for VMI %s", vmo.Name)
+		log.Oncef("Successfully applied StatefulSets for VMI %s", vmo.Name)
 	} else {
-		controller.log.Errorf("StatefulSet update plan conflict: %v", plan.Conflict)
+		log.Errorf("StatefulSet update plan conflict: %v", plan.Conflict)
 	}
 	return plan.ExistingCluster, plan.Conflict
 }
@@ -92,31 +109,32 @@ func getInitialMasterNodes(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, exi
 	return nodes.InitialMasterNodes(vmo.Name, nodes.MasterNodes(vmo))
 }
 
-func updateStatefulSet(c *Controller, sts *appsv1.StatefulSet, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, plan *statefulsets.StatefulSetPlan) error {
+func updateStatefulSet(ctx context.Context, c *Controller, sts *appsv1.StatefulSet, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, plan *statefulsets.StatefulSetPlan) error {
 	// if the cluster is alive, but unhealthy we shouldn't do an update - may cause data loss/corruption
 	if !plan.BounceNodes && plan.ExistingCluster {
 		// We should only update an existing cluster if it is healthy
-		if err := c.osClient.IsGreen(vmo); err != nil {
+		if err := c.osClient.WithContext(ctx).IsGreen(vmo); err != nil {
 			return err
 		}
 	}
 
-	if _, err := c.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Update(context.TODO(), sts, metav1.UpdateOptions{}); err != nil {
+	if _, err := c.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Update(ctx, sts, metav1.UpdateOptions{}); err != nil {
 		return err
 	}
 	// if it was a single node cluster, delete the pod to ensure it picks up the updated settings.
 	if plan.BounceNodes {
-		return c.kubeclientset.CoreV1().Pods(vmo.Namespace).Delete(context.TODO(), sts.Name+"-0", metav1.DeleteOptions{})
+		return c.kubeclientset.CoreV1().Pods(vmo.Namespace).Delete(ctx, sts.Name+"-0", metav1.DeleteOptions{})
 	}
 	return nil
 }
 
 // scaleDownStatefulSet scales down a statefulset, and deletes the statefulset if it is already at 1 or fewer replicas.
-func scaleDownStatefulSet(c *Controller, expectedList []*appsv1.StatefulSet, statefulSet *appsv1.StatefulSet, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func scaleDownStatefulSet(ctx context.Context, c *Controller, expectedList []*appsv1.StatefulSet, statefulSet *appsv1.StatefulSet, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 	deleteSTS := func() error {
-		err := c.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Delete(context.TODO(), statefulSet.Name, metav1.DeleteOptions{})
+		err := c.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Delete(ctx, statefulSet.Name, metav1.DeleteOptions{})
 		if err != nil {
-			c.log.Errorf("Failed to delete StatefulSet %s: %v", statefulSet.Name, err)
+			log.Errorf("Failed to delete StatefulSet %s: %v", statefulSet.Name, err)
 			return err
 		}
 		return nil
@@ -128,7 +146,7 @@ func scaleDownStatefulSet(c *Controller, expectedList []*appsv1.StatefulSet, sta
 	}
 
 	// The cluster should be in steady state before any nodes are removed
-	if err := c.osClient.IsUpdated(vmo); err != nil {
+	if err := c.osClient.WithContext(ctx).IsUpdated(vmo); err != nil {
 		return err
 	}
 
@@ -136,7 +154,7 @@ func scaleDownStatefulSet(c *Controller, expectedList []*appsv1.StatefulSet, sta
 	// If the statefulset already has one replica, then it can be deleted.
 	if *statefulSet.Spec.Replicas > 1 {
 		*statefulSet.Spec.Replicas--
-		if _, err := c.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Update(context.TODO(), statefulSet, metav1.UpdateOptions{}); err != nil {
+		if _, err := c.kubeclientset.AppsV1().StatefulSets(vmo.Namespace).Update(ctx, statefulSet, metav1.UpdateOptions{}); err != nil {
 			return err
 		}
 
@@ -153,7 +171,8 @@ func scaleDownStatefulSet(c *Controller, expectedList []*appsv1.StatefulSet, sta
 // to the STS resource, the PVC will automatically get deleted when the STS is deleted.
 // Because PVC is dynamic, when it is deleted, the bound PV will also get deleted.
 // NOTE: This cannot be done automatically using the STS VolumeClaimTemplate.
-func updateOwnerForPVCs(controller *Controller, statefulSet *appsv1.StatefulSet, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+func updateOwnerForPVCs(ctx context.Context, controller *Controller, statefulSet *appsv1.StatefulSet, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) error {
+	log := reconcileLog(ctx)
 	pvcNames := statefulsets.GetPVCNames(statefulSet)
 	for _, pvcName := range pvcNames {
 		pvc, err := controller.pvcLister.PersistentVolumeClaims(vmo.Namespace).Get(pvcName)
@@ -166,10 +185,10 @@ func updateOwnerForPVCs(controller *Controller, statefulSet *appsv1.StatefulSet,
 			Name:       vmo.Name,
 			UID:        vmo.UID,
 		}}
-		controller.log.Debugf("Setting VMI owner reference for PVC %s", pvc.Name)
-		_, err = controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Update(context.TODO(), pvc, metav1.UpdateOptions{})
+		log.Debugf("Setting VMI owner reference for PVC %s", pvc.Name)
+		_, err = controller.kubeclientset.CoreV1().PersistentVolumeClaims(vmo.Namespace).Update(ctx, pvc, metav1.UpdateOptions{})
 		if err != nil {
-			controller.log.Errorf("Failed to update the owner reference in PVC %s: %v", pvc.Name, err)
+			log.Errorf("Failed to update the owner reference in PVC %s: %v", pvc.Name, err)
 			return err
 		}
 	}
@@ -0,0 +1,54 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import "sync"
+
+// keyedTracker tracks a value per key, e.g. per VMI or per deployment, across the reconciles of the controller
+// workers. The zero value is ready to use.
+type keyedTracker[V any] struct {
+	mutex  sync.Mutex
+	values map[string]V
+}
+
+// get returns the value of the key, and whether there is one
+func (t *keyedTracker[V]) get(key string) (V, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	value, ok := t.values[key]
+	return value, ok
+}
+
+// set sets the value of the key
+func (t *keyedTracker[V]) set(key string, value V) {
+	t.modify(key, func(V, bool) (V, bool) {
+		return value, true
+	})
+}
+
+// modify replaces the value of the key with the value returned by the given function, which is called with the
+// current value of the key and whether there is one. The key is deleted if the function returns false.
+// Returns the new value.
+func (t *keyedTracker[V]) modify(key string, fn func(value V, ok bool) (V, bool)) V {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	value, ok := t.values[key]
+	value, keep := fn(value, ok)
+	if !keep {
+		delete(t.values, key)
+		return value
+	}
+	if t.values == nil {
+		t.values = map[string]V{}
+	}
+	t.values[key] = value
+	return value
+}
+
+// reset forgets the value of the key
+func (t *keyedTracker[V]) reset(key string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.values, key)
+}
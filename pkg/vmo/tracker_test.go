@@ -0,0 +1,64 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package vmo
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestKeyedTracker Tests tracking values per key
+// GIVEN a zero value keyed tracker
+// WHEN values are set, modified and reset
+// THEN the tracker returns the value of each key, and forgets the keys which are reset or not kept
+func TestKeyedTracker(t *testing.T) {
+	var tracker keyedTracker[int]
+	_, ok := tracker.get("a")
+	assert.False(t, ok)
+
+	tracker.set("a", 1)
+	assert.Equal(t, 2, tracker.modify("a", func(value int, ok bool) (int, bool) {
+		assert.True(t, ok)
+		return value + 1, true
+	}))
+	assert.Equal(t, 5, tracker.modify("b", func(value int, ok bool) (int, bool) {
+		assert.False(t, ok)
+		return 5, true
+	}))
+	value, ok := tracker.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, value)
+
+	tracker.modify("a", func(value int, _ bool) (int, bool) {
+		return value, false
+	})
+	_, ok = tracker.get("a")
+	assert.False(t, ok)
+	tracker.reset("b")
+	_, ok = tracker.get("b")
+	assert.False(t, ok)
+}
+
+// TestKeyedTrackerConcurrent Tests modifying a value of a keyed tracker from concurrent workers
+// GIVEN a keyed tracker
+// WHEN 10 workers increment the value of the same key concurrently
+// THEN no increment is lost, without data race when run with -race
+func TestKeyedTrackerConcurrent(t *testing.T) {
+	var tracker keyedTracker[int]
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tracker.modify("key", func(value int, _ bool) (int, bool) {
+				return value + 1, true
+			})
+		}()
+	}
+	wg.Wait()
+	value, _ := tracker.get("key")
+	assert.Equal(t, 10, value)
+}
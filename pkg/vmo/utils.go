@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2021, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -8,6 +8,7 @@ import (
 	"math/big"
 
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/opensearch"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -81,3 +82,26 @@ func chooseRandomElementFromSlice(slice []string) string {
 	}
 	return ""
 }
+
+// skippedChannel returns a channel with a nil error, to stand in for an asynchronous step that is skipped
+func skippedChannel() chan error {
+	ch := make(chan error, 1)
+	ch <- nil
+	return ch
+}
+
+// skippedMonitorsChannel returns a channel with an unsynced result, which leaves the
+// tracked monitors unchanged, to stand in for the alerting monitors step when it is skipped
+func skippedMonitorsChannel() chan opensearch.MonitorsResult {
+	ch := make(chan opensearch.MonitorsResult, 1)
+	ch <- opensearch.MonitorsResult{}
+	return ch
+}
+
+// skippedAliasesChannel returns a channel with an unsynced result, which leaves the
+// tracked index aliases unchanged, to stand in for the index aliases step when it is skipped
+func skippedAliasesChannel() chan opensearch.AliasesResult {
+	ch := make(chan opensearch.AliasesResult, 1)
+	ch <- opensearch.AliasesResult{}
+	return ch
+}
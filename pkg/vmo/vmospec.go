@@ -1,9 +1,12 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
 
 import (
+	"context"
+	"reflect"
+
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/constants"
@@ -11,36 +14,44 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/nodes"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"reflect"
 )
 
 // InitializeVMOSpec initializes any uninitialized elements of the VMO spec.
-func InitializeVMOSpec(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+func InitializeVMOSpec(ctx context.Context, controller *Controller, vmo *vmcontrollerv1.VerrazzanoMonitoringInstance) {
+	log := reconcileLog(ctx)
 	// The secretName we use for basic authentication in the Nginx ingress controller
 	vmo.Spec.SecretName = vmo.Name + "-basicauth"
 
 	/*********************
 	 * Create Secrets
 	 **********************/
-	controller.log.Oncef("Loading auth secret data")
+	log.Oncef("Loading auth secret data")
 	credsMap, err := controller.loadAllAuthSecretData(vmo.Namespace, vmo.Spec.SecretsName)
 	if err != nil {
-		controller.log.Errorf("Failed to extract VMO Secrets for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to extract VMO Secrets for VMI %s: %v", vmo.Name, err)
 	}
 
-	controller.log.Oncef("Reconciling auth secrets")
-	err = CreateOrUpdateAuthSecrets(controller, vmo, credsMap)
+	log.Oncef("Reconciling auth secrets")
+	err = CreateOrUpdateAuthSecrets(ctx, controller, vmo, credsMap)
 	if err != nil {
-		controller.log.Errorf("Failed to create VMO Secrets for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to create VMO Secrets for VMI %s: %v", vmo.Name, err)
 	}
 
 	// Create TLS secrets or get certs
-	controller.log.Oncef("Reconciling TLS secrets")
-	err = CreateOrUpdateTLSSecrets(controller, vmo)
+	log.Oncef("Reconciling TLS secrets")
+	err = CreateOrUpdateTLSSecrets(ctx, controller, vmo)
 	if err != nil {
-		controller.log.Errorf("Failed to create TLS Secrets for VMI %s: %v", vmo.Name, err)
+		log.Errorf("Failed to create TLS Secrets for VMI %s: %v", vmo.Name, err)
 	}
 
+	setVMOSpecDefaults(vmo, controller.operatorConfig)
+
+	// set label for managed-cluster-name
+	vmo.Labels[constants.ClusterNameData] = controller.clusterInfo.clusterName
+}
+
+// setVMOSpecDefaults initializes the uninitialized elements of the VMO spec which do not depend on cluster state.
+func setVMOSpecDefaults(vmo *vmcontrollerv1.VerrazzanoMonitoringInstance, operatorConfig *config.OperatorConfig) {
 	// Set creation time
 	if vmo.Status.CreationTime == nil {
 		now := metav1.Now()
@@ -49,7 +60,7 @@ func InitializeVMOSpec(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 
 	// Set environment
 	if vmo.Status.EnvName == "" {
-		vmo.Status.EnvName = controller.operatorConfig.EnvName
+		vmo.Status.EnvName = operatorConfig.EnvName
 	}
 
 	// Service type
@@ -68,9 +79,10 @@ func InitializeVMOSpec(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 	// Kibana to OpenSearch Dashboards CR conversion
 	handleOpensearchDashboardsConversion(&vmo.Spec)
 
-	// Number of replicas for each component
+	// Number of replicas for each component. The replicas of OpenSearch Dashboards are kept when it is scaled to zero
+	// replicas, so that it is scaled up again to the same replicas
 	if vmo.Spec.OpensearchDashboards.Replicas == 0 {
-		vmo.Spec.OpensearchDashboards.Replicas = int32(*controller.operatorConfig.DefaultSimpleComponentReplicas)
+		vmo.Spec.OpensearchDashboards.Replicas = int32(*operatorConfig.DefaultSimpleComponentReplicas)
 	}
 
 	// Elasticsearch to OpenSearch CR conversion
@@ -98,9 +110,6 @@ func InitializeVMOSpec(controller *Controller, vmo *vmcontrollerv1.VerrazzanoMon
 	if vmo.Status.State == "" {
 		vmo.Status.State = string(constants.Running)
 	}
-
-	// set label for managed-cluster-name
-	vmo.Labels[constants.ClusterNameData] = controller.clusterInfo.clusterName
 }
 
 func initNode(node *vmcontrollerv1.ElasticsearchNode, role vmcontrollerv1.NodeRole) {
@@ -1,4 +1,4 @@
-// Copyright (C) 2022, Oracle and/or its affiliates.
+// Copyright (C) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package vmo
@@ -6,6 +6,9 @@ package vmo
 import (
 	"github.com/stretchr/testify/assert"
 	vmcontrollerv1 "github.com/verrazzano/verrazzano-monitoring-operator/pkg/apis/vmcontroller/v1"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/config"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources"
+	"github.com/verrazzano/verrazzano-monitoring-operator/pkg/resources/deployments"
 	"testing"
 )
 
@@ -163,6 +166,38 @@ func TestInitializeVMOSpecOpensearchDashboards(t *testing.T) {
 	}
 }
 
+// TestSetVMOSpecDefaultsOpensearchDashboardsReplicas tests the defaulting of the OpenSearch Dashboards replicas
+// GIVEN a VMI without OpenSearch Dashboards replicas, then a VMI whose OpenSearch Dashboards is scaled to zero
+// WHEN setVMOSpecDefaults is called
+// THEN the replicas default to the operator config replicas, and the replicas of OpenSearch Dashboards scaled to zero
+// are kept in the spec, while zero replicas are deployed
+func TestSetVMOSpecDefaultsOpensearchDashboardsReplicas(t *testing.T) {
+	defaultReplicas := 1
+	operatorConfig := &config.OperatorConfig{DefaultSimpleComponentReplicas: &defaultReplicas}
+
+	vmo := &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	setVMOSpecDefaults(vmo, operatorConfig)
+	assert.Equal(t, int32(1), vmo.Spec.OpensearchDashboards.Replicas)
+	assert.Equal(t, int32(1), resources.GetOpenSearchDashboardsReplicas(vmo))
+
+	vmo = &vmcontrollerv1.VerrazzanoMonitoringInstance{}
+	vmo.Spec.OpensearchDashboards.Enabled = true
+	vmo.Spec.OpensearchDashboards.Replicas = 3
+	vmo.Spec.OpensearchDashboards.ScaleToZero = true
+	setVMOSpecDefaults(vmo, operatorConfig)
+	assert.Equal(t, int32(3), vmo.Spec.OpensearchDashboards.Replicas)
+	assert.Equal(t, int32(0), resources.GetOpenSearchDashboardsReplicas(vmo))
+	assert.Equal(t, int32(0), *deployments.NewOpenSearchDashboardsDeployment(vmo).Spec.Replicas)
+
+	// scaled up again to the same replicas
+	vmo.Spec.OpensearchDashboards.ScaleToZero = false
+	setVMOSpecDefaults(vmo, operatorConfig)
+	assert.Equal(t, int32(3), vmo.Spec.OpensearchDashboards.Replicas)
+	assert.Equal(t, int32(3), resources.GetOpenSearchDashboardsReplicas(vmo))
+	assert.Equal(t, int32(3), *deployments.NewOpenSearchDashboardsDeployment(vmo).Spec.Replicas)
+}
+
 func TestInitStorageElement(t *testing.T) {
 	var tests = []struct {
 		name     string
@@ -1,4 +1,4 @@
-// Copyright (C) 2020, 2022, Oracle and/or its affiliates.
+// Copyright (C) 2020, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package integ
@@ -738,10 +738,15 @@ func verifyGrafana(t *testing.T, vmo *vmcontrollerv1.VerrazzanoMonitoringInstanc
 		fmt.Printf("'domain' obtained from Grafana config is = %+v\n", domain)
 	}
 	if externalDomainName != "localhost" {
+		rootURLScheme := vmo.Spec.Grafana.RootURLScheme
+		if rootURLScheme == "" {
+			rootURLScheme = constants.GrafanaDefaultRootURLScheme
+		}
+		expectedRootURL := rootURLScheme + "://" + externalDomainName
 		if rootURL, ok := grafanaServerConfig["root_url"]; !ok {
 			t.Fatalf("Expected 'root_url' element in result but found none\n")
-		} else if rootURL != "https://"+externalDomainName {
-			t.Fatalf("Actual root_url value '%s' doesn't match expected value '%s'\n", rootURL, "https://"+externalDomainName)
+		} else if rootURL != expectedRootURL {
+			t.Fatalf("Actual root_url value '%s' doesn't match expected value '%s'\n", rootURL, expectedRootURL)
 		} else {
 			fmt.Printf("'root_url' obtained from Grafana config is = %+v\n", rootURL)
 		}
@@ -3,6 +3,8 @@
 
 package constants
 
+import "time"
+
 // General constants
 const (
 	// VerrazzanoSystemNamespace is the Namespace where Opensearch components are installed
@@ -47,6 +49,9 @@ const (
 	// OpenSearchHealthCheckTimeoutDefaultValue Env value for key OpenSearchHealthCheckTimeoutKey for Opensearch health check
 	OpenSearchHealthCheckTimeoutDefaultValue = "10m"
 
+	// OSDDrainTimeoutDefaultValue Default time to wait for the OpenSearch Dashboards pods to terminate before restoring
+	OSDDrainTimeoutDefaultValue = "5m"
+
 	// DisableSecurityPluginOS Env key to disable Security Plugin
 	DisableSecurityPluginOS = "DISABLE_SECURITY_PLUGIN"
 )
@@ -78,6 +83,18 @@ const (
 	// OpenSearchSnapShotRepoName Opensearch snapshot name in remote repository
 	OpenSearchSnapShotRepoName = "verrazzano-backup"
 
+	// SnapshotNameAnnotation annotation of the Velero backup recording the name of the snapshot taken by the backup
+	SnapshotNameAnnotation = "verrazzano.io/opensearch-snapshot-name"
+
+	// S3SnapshotRepoType type of the snapshot repository storing snapshots in an object store
+	S3SnapshotRepoType = "s3"
+
+	// FSSnapshotRepoType type of the snapshot repository storing snapshots in a shared filesystem
+	FSSnapshotRepoType = "fs"
+
+	// OpenSearchKeystoreVolumeName name of the volume of a pre-populated keystore mounted on the OpenSearch pods
+	OpenSearchKeystoreVolumeName = "opensearch-keystore"
+
 	// IngestDeploymentName Opensearch ingest deployment name
 	IngestDeploymentName = "vmi-system-es-ingest"
 
@@ -98,6 +115,46 @@ const (
 	// VMOLabelSelector Label selector for Verrazzano Monitoring Operator
 	VMOLabelSelector = "k8s-app=verrazzano-monitoring-operator"
 
+	// NotificationStatusSuccess status of the completion notification of a successful operation
+	NotificationStatusSuccess = "success"
+
+	// NotificationStatusFailure status of the completion notification of a failed operation
+	NotificationStatusFailure = "failure"
+
+	// NotificationTimeout time to wait for the webhook to accept the completion notification
+	NotificationTimeout = 30 * time.Second
+
+	// TestRestoreIndexPrefix prefix of the indices and data streams restored in test mode
+	TestRestoreIndexPrefix = "vz-restore-test-"
+
+	// RecoveryMaxBytesPerSecSetting cluster setting limiting the shard recovery rate per node
+	RecoveryMaxBytesPerSecSetting = "indices.recovery.max_bytes_per_sec"
+
+	// NodeConcurrentRecoveriesSetting cluster setting limiting the concurrent shard recoveries per node
+	NodeConcurrentRecoveriesSetting = "cluster.routing.allocation.node_concurrent_recoveries"
+
+	// NodeInitialPrimariesRecoveriesSetting cluster setting limiting the concurrent primary shard recoveries per node,
+	// shards restored from a snapshot are recovered as primaries
+	NodeInitialPrimariesRecoveriesSetting = "cluster.routing.allocation.node_initial_primaries_recoveries"
+
+	// IndexRefreshIntervalSetting index setting of the interval between the refreshes of an index
+	IndexRefreshIntervalSetting = "index.refresh_interval"
+
+	// IndexNumberOfReplicasSetting index setting of the number of replicas of each primary shard of an index
+	IndexNumberOfReplicasSetting = "index.number_of_replicas"
+
+	// RestoreIndexSettingsBatchSize maximum number of restored indices whose settings are read or updated by one request
+	RestoreIndexSettingsBatchSize = 100
+
+	// OpenSearchSecurityIndex the security index, which is never restored
+	OpenSearchSecurityIndex = ".opendistro_security"
+
+	// OpenSearchSecurityIndexExclusion excludes the security index from a restore
+	OpenSearchSecurityIndexExclusion = "-" + OpenSearchSecurityIndex
+
+	// DataStreamBackingIndexPrefix prefix of the names of the backing indices of a data stream
+	DataStreamBackingIndexPrefix = ".ds-"
+
 	// OpenSearchSnapShotSuccess Success status message expected value
 	OpenSearchSnapShotSuccess = "SUCCESS"
 
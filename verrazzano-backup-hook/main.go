@@ -23,6 +23,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	kzap "sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"strings"
+	"time"
 )
 
 var (
@@ -31,6 +32,38 @@ var (
 	Operation        string
 	Profile          string
 	VeleroNamespace  string
+	SnapshotName     string
+
+	ChunkSize              string
+	MaxSnapshotBytesPerSec string
+	MaxConcurrentSnapshots int
+	MaxRestoreBytesPerSec  string
+	TestMode               bool
+
+	RecoveryMaxBytesPerSec   string
+	NodeConcurrentRecoveries int
+	MaxConcurrentRecoveries  int
+
+	RestoreIndices string
+	IncludeAliases bool
+
+	RestoreRefreshInterval string
+	RestoreReplicas        int
+
+	OSDDrainTimeout string
+
+	HealthPollInterval string
+	HealthTimeout      string
+
+	RetryWaitMin string
+	RetryWaitMax string
+
+	RepoType       string
+	RepoPath       string
+	SkipRepoVerify bool
+
+	NotifyURL   string
+	ClusterName string
 )
 
 func main() {
@@ -39,6 +72,29 @@ func main() {
 	flag.StringVar(&Operation, "operation", "", "Operation must be one of 'backup' or 'restore'.")
 	flag.StringVar(&Profile, "profile", "default", "Object store credentials profile.")
 	flag.StringVar(&VeleroNamespace, "namespace", "verrazzano-backup", "Namespace where Velero component is deployed.")
+	flag.StringVar(&SnapshotName, "snapshot-name", "", "Optionally, the name of the snapshot. For 'backup', a Go template, e.g. daily-{{.Date}}, which can use {{.Date}}, {{.Time}} and {{.BackupName}}, the Velero backup name by default. For 'restore', the literal name of a snapshot, e.g. daily-2023-03-21, by default the snapshot name recorded on the Velero backup by the backup, or the Velero backup name.")
+	flag.StringVar(&ChunkSize, "chunk-size", "", "Optionally, the chunk size used to break up large files in the snapshot repository, e.g. 1gb.")
+	flag.StringVar(&MaxSnapshotBytesPerSec, "max-snapshot-bytes-per-sec", "", "Optionally, the maximum snapshot rate per node of the snapshot repository, e.g. 40mb.")
+	flag.IntVar(&MaxConcurrentSnapshots, "max-concurrent-snapshots", 0, "Optionally, the maximum number of snapshots in progress in the OpenSearch cluster, a backup waits for the snapshots in progress to complete before taking its snapshot. The limit is approximate: backups starting at the same time may exceed it, as they do not coordinate with each other. Not limited by default.")
+	flag.StringVar(&MaxRestoreBytesPerSec, "max-restore-bytes-per-sec", "", "Optionally, the maximum restore rate per node of the snapshot repository, e.g. 40mb.")
+	flag.StringVar(&RecoveryMaxBytesPerSec, "recovery-max-bytes-per-sec", "", "Optionally, the maximum shard recovery rate per node while restoring, e.g. 40mb.")
+	flag.IntVar(&NodeConcurrentRecoveries, "node-concurrent-recoveries", 0, "Optionally, the maximum number of concurrent shard recoveries per node while restoring.")
+	flag.IntVar(&MaxConcurrentRecoveries, "max-concurrent-recoveries", 0, "Optionally, the maximum number of shards restored from the snapshot concurrently per node while restoring.")
+	flag.StringVar(&RestoreIndices, "restore-indices", "", "Optionally, the comma separated list of indices and data streams to restore, all of them by default. Only the data streams and indices to restore are deleted before the restore.")
+	flag.BoolVar(&IncludeAliases, "include-aliases", true, "Whether to restore the aliases of the restored indices (Default = true).")
+	flag.StringVar(&RestoreRefreshInterval, "restore-refresh-interval", "-1", "The refresh interval of the restored indices while restoring, -1 disables the refreshes to speed up the restore. The snapshot value is kept if empty, and is put back once the restore is done (Default = -1).")
+	flag.IntVar(&RestoreReplicas, "restore-replicas", 0, "The number of replicas of the restored indices while restoring. The snapshot value is kept if negative, and is put back once the restore is done (Default = 0).")
+	flag.StringVar(&OSDDrainTimeout, "osd-drain-timeout", constants.OSDDrainTimeoutDefaultValue, "The time to wait for the OpenSearch Dashboards pods to terminate before restoring, e.g. 5m.")
+	flag.StringVar(&HealthPollInterval, "health-poll-interval", "", "Optionally, the interval between the OpenSearch reachability and health checks, e.g. 5s. A random interval by default.")
+	flag.StringVar(&HealthTimeout, "health-timeout", "", "Optionally, the overall time to wait for OpenSearch to be reachable and healthy, e.g. 30m. The HEALTH_CHECK environment variable or 10m by default.")
+	flag.StringVar(&RetryWaitMin, "retry-wait-min", "10s", "The shortest random wait between two retries, e.g. 10s (Default = 10s).")
+	flag.StringVar(&RetryWaitMax, "retry-wait-max", "25s", "The longest random wait between two retries, e.g. 25s (Default = 25s).")
+	flag.StringVar(&RepoType, "repo-type", constants.S3SnapshotRepoType, "The type of the snapshot repository, one of 's3' or 'fs' (Default = s3).")
+	flag.StringVar(&RepoPath, "repo-path", "", "The path of the shared filesystem snapshot repository, required for the 'fs' repository type, e.g. /mnt/snapshots.")
+	flag.StringVar(&NotifyURL, "notify-url", "", "Optionally, the URL of a webhook, e.g. a Slack or Teams incoming webhook, the JSON completion notification of the operation is posted to, whether it succeeds or fails. The summary of the notification is in its text field.")
+	flag.StringVar(&ClusterName, "cluster-name", "", "Optionally, the name of the cluster reported in the completion notification.")
+	flag.BoolVar(&SkipRepoVerify, "skip-repo-verify", false, "Skip the verification of the snapshot repository when it is registered, which fails the operation early if the repository is unreachable. Only set if the verification is known to fail, e.g. for an object store rejecting the verification objects.")
+	flag.BoolVar(&TestMode, "test-mode", false, "Restore the snapshot into renamed indices without scaling down the operator or deleting services and data. Only valid for 'restore'.")
 
 	// Add the zap logger flag set to the CLI.
 	opts := kzap.Options{}
@@ -60,6 +116,91 @@ func main() {
 		fmt.Printf("VeleroBackupName must refer to an existing Velero backup.\n")
 		os.Exit(1)
 	}
+	if TestMode && Operation != constants.RestoreOperation {
+		fmt.Printf("Test mode is only supported for the 'restore' operation\n")
+		os.Exit(1)
+	}
+	if NodeConcurrentRecoveries < 0 {
+		fmt.Printf("Node concurrent recoveries cannot be negative\n")
+		os.Exit(1)
+	}
+	if MaxConcurrentRecoveries < 0 {
+		fmt.Printf("Max concurrent recoveries cannot be negative\n")
+		os.Exit(1)
+	}
+	if MaxConcurrentSnapshots < 0 {
+		fmt.Printf("Max concurrent snapshots cannot be negative\n")
+		os.Exit(1)
+	}
+	if RepoType != constants.S3SnapshotRepoType && RepoType != constants.FSSnapshotRepoType {
+		fmt.Printf("Repository type has to be 's3/fs'\n")
+		os.Exit(1)
+	}
+	if RepoType == constants.FSSnapshotRepoType && RepoPath == "" {
+		fmt.Printf("Repository path is required for the 'fs' repository type\n")
+		os.Exit(1)
+	}
+	if err := futil.ValidateRefreshInterval(RestoreRefreshInterval); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	if timeout, err := time.ParseDuration(OSDDrainTimeout); err != nil || timeout <= 0 {
+		fmt.Printf("OSD drain timeout has to be a positive duration, e.g. 5m\n")
+		os.Exit(1)
+	}
+	if err := futil.ValidateNotifyURL(NotifyURL); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	// a template is only rendered by the backup, the restore needs the name of the snapshot the backup took, which is
+	// recorded on the Velero backup unless the name is set
+	var snapshotName string
+	var err error
+	if Operation == constants.BackupOperation {
+		snapshotName, err = futil.RenderSnapshotName(SnapshotName, VeleroBackupName, time.Now())
+	} else {
+		snapshotName, err = futil.RestoreSnapshotName(SnapshotName, VeleroBackupName)
+	}
+	if err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	var healthCheckSettings model.HealthCheckSettings
+	if HealthPollInterval != "" {
+		interval, err := time.ParseDuration(HealthPollInterval)
+		if err != nil || interval <= 0 {
+			fmt.Printf("Health poll interval has to be a positive duration, e.g. 5s\n")
+			os.Exit(1)
+		}
+		healthCheckSettings.PollInterval = interval
+	}
+	if HealthTimeout != "" {
+		timeout, err := time.ParseDuration(HealthTimeout)
+		if err != nil || timeout <= 0 {
+			fmt.Printf("Health timeout has to be a positive duration, e.g. 30m\n")
+			os.Exit(1)
+		}
+		healthCheckSettings.Timeout = timeout
+	}
+	var waitSettings model.WaitSettings
+	if waitSettings.Min, err = time.ParseDuration(RetryWaitMin); err != nil {
+		fmt.Printf("Minimum retry wait has to be a duration, e.g. 10s\n")
+		os.Exit(1)
+	}
+	if waitSettings.Max, err = time.ParseDuration(RetryWaitMax); err != nil {
+		fmt.Printf("Maximum retry wait has to be a duration, e.g. 25s\n")
+		os.Exit(1)
+	}
+	if err := futil.ValidateWaitSettings(waitSettings); err != nil {
+		fmt.Printf("%v\n", err)
+		os.Exit(1)
+	}
+	for _, byteSize := range []string{ChunkSize, MaxSnapshotBytesPerSec, MaxRestoreBytesPerSec, RecoveryMaxBytesPerSec} {
+		if err := futil.ValidateByteSize(byteSize); err != nil {
+			fmt.Printf("%v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	// Initialize the zap log
 	file, err := os.CreateTemp(os.TempDir(), fmt.Sprintf("verrazzano-%s-hook-*.log", strings.ToLower(Operation)))
@@ -75,6 +216,22 @@ func main() {
 	}
 	log.Info("Verrazzano backup and restore helper invoked.")
 
+	if err := run(log, snapshotName, healthCheckSettings, waitSettings); err != nil {
+		os.Exit(1)
+	}
+}
+
+// run runs the operation. Every failure of the operation is returned, so that the completion of the operation is
+// notified whichever step it fails at.
+func run(log *zap.SugaredLogger, snapshotName string, healthCheckSettings model.HealthCheckSettings, waitSettings model.WaitSettings) (err error) {
+	start := time.Now()
+	defer func() {
+		if err != nil {
+			log.Errorf("Operation '%s' unsuccessfull due to %v", Operation, err)
+		}
+		notify(log, strings.ToLower(Operation), snapshotName, start, err)
+	}()
+
 	// Gathering k8s clients
 	done := false
 	retryCount := 0
@@ -114,9 +271,9 @@ func main() {
 		if !k8sContextReady {
 			if retryCount <= constants.RetryCount {
 				message := "Unable to get context"
-				_, err := futil.WaitRandom(message, globalTimeout, log)
+				_, err := futil.WaitRandom(message, globalTimeout, waitSettings, log)
 				if err != nil {
-					log.Panic(err)
+					return err
 				}
 				retryCount = retryCount + 1
 				// setting k8sContextReady flag to true os that subsequent checks dont re-use old value
@@ -132,13 +289,13 @@ func main() {
 
 	// Initialize K8s object
 	k8s := kutil.New(dynamicKubeClientInterface, kubeClient, kubeClientInterface, config, Profile, log)
+	k8s.WaitSettings = waitSettings
 
 	httpClient := http.DefaultClient
 	isLegacyOS, err := k8s.IsLegacyOS()
 
 	if err != nil {
-		log.Errorf("Failed to determine if Security Plugin is enabled: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("Failed to determine if Security Plugin is enabled: %v", err)
 	}
 
 	// Log which OS we are backing up or restoring
@@ -155,8 +312,7 @@ func main() {
 		if !isLegacyOS {
 			err = k8s.ScaleDeployment(opensearchVar.OperatorDeploymentLabelSelector, opensearchVar.Namespace, opensearchVar.OperatorDeploymentName, int32(0))
 			if err != nil {
-				log.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, zap.Error(err))
-				os.Exit(1)
+				return fmt.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, err)
 			}
 			// Reset cluster status
 			err = k8s.ResetClusterInitialization()
@@ -169,38 +325,33 @@ func main() {
 			}
 			err = k8s.ScaleDeployment(opensearchVar.OperatorDeploymentLabelSelector, opensearchVar.Namespace, opensearchVar.OperatorDeploymentName, int32(1))
 			if err != nil {
-				log.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, zap.Error(err))
-				os.Exit(1)
+				return fmt.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, err)
 			}
 
 			err = k8s.CheckBootstrapResources()
 			if err != nil {
-				log.Errorf("Failed to find bootstrap pod or securityconfig job pod: %v", err)
-				os.Exit(1)
+				return fmt.Errorf("Failed to find bootstrap pod or securityconfig job pod: %v", err)
 			}
 
 			err = k8s.ScaleDeployment(opensearchVar.OperatorDeploymentLabelSelector, opensearchVar.Namespace, opensearchVar.OperatorDeploymentName, int32(0))
 			if err != nil {
-				log.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, zap.Error(err))
-				os.Exit(1)
+				return fmt.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, err)
 			}
 		}
 
 		log.Infof("Pre-restore operation successfully completed")
-		os.Exit(0)
+		return nil
 	}
 
 	basicAuth := opensearch.NewBasicAuth(false, "", "")
 	if !isLegacyOS {
 		username, err := os.ReadFile("/mnt/admin-credentials/username")
 		if err != nil {
-			log.Errorf("Failed to get username for basic auth")
-			os.Exit(1)
+			return fmt.Errorf("Failed to get username for basic auth: %v", err)
 		}
 		password, err := os.ReadFile("/mnt/admin-credentials/password")
 		if err != nil {
-			log.Errorf("Failed to get password for basic auth")
-			os.Exit(1)
+			return fmt.Errorf("Failed to get password for basic auth: %v", err)
 		}
 
 		basicAuth = opensearch.NewBasicAuth(true, string(username), string(password))
@@ -212,33 +363,81 @@ func main() {
 
 	// Initialize Opensearch object
 	search := opensearch.New(opensearchVar.OpenSearchURL, globalTimeout, httpClient, &checkConData, log, basicAuth)
+	search.HealthCheckSettings = healthCheckSettings
+	search.WaitSettings = waitSettings
+	search.MaxConcurrentSnapshots = MaxConcurrentSnapshots
 	// Check OpenSearch health before proceeding with backup or restore
 	err = search.EnsureOpenSearchIsHealthy()
 	if err != nil {
-		log.Errorf("Operation cannot be performed as OpenSearch is not healthy")
-		os.Exit(1)
+		return fmt.Errorf("Operation cannot be performed as OpenSearch is not healthy: %v", err)
 	}
 
 	// Get S3 access details from Velero Backup Storage location associated with Backup given as input
-	// Ensure the Backup Storage Location is NOT default
-	openSearchConData, err := k8s.PopulateConnData(VeleroNamespace, VeleroBackupName)
-	if err != nil {
-		log.Errorf("Unable to fetch secret: %v", err)
-		os.Exit(1)
+	// Ensure the Backup Storage Location is NOT default. A fs repository does not use the Velero object store.
+	var openSearchConData *model.ConnectionData
+	if RepoType == constants.S3SnapshotRepoType {
+		openSearchConData, err = k8s.PopulateConnData(VeleroNamespace, VeleroBackupName)
+		if err != nil {
+			return fmt.Errorf("Unable to fetch secret: %v", err)
+		}
+	} else {
+		openSearchConData, err = k8s.PopulateFSConnData(VeleroNamespace, VeleroBackupName)
+		if err != nil {
+			return fmt.Errorf("Unable to fetch backup: %v", err)
+		}
 	}
 
-	// Update OpenSearch keystore
-	_, err = k8s.UpdateKeystore(openSearchConData, globalTimeout, opensearchVar)
-	if err != nil {
-		log.Errorf("Unable to update keystore")
-		os.Exit(1)
+	// Update OpenSearch keystore with the object store credentials, a fs repository does not need them
+	if RepoType == constants.S3SnapshotRepoType {
+		_, err = k8s.UpdateKeystore(openSearchConData, globalTimeout, opensearchVar)
+		if err != nil {
+			return fmt.Errorf("Unable to update keystore: %v", err)
+		}
+	}
+
+	if strings.ToLower(Operation) == constants.RestoreOperation && SnapshotName == "" {
+		var backup *model.VeleroBackup
+		backup, err = k8s.GetBackup(VeleroNamespace, VeleroBackupName)
+		if err != nil {
+			return fmt.Errorf("Unable to fetch backup: %v", err)
+		}
+		if recordedName := backup.Metadata.Annotations.VerrazzanoIoOpenSearchSnapshotName; recordedName != "" {
+			log.Infof("Restoring snapshot '%s' recorded on backup '%s'", recordedName, VeleroBackupName)
+			snapshotName = recordedName
+		}
 	}
 
 	openSearch := opensearch.New(opensearchVar.OpenSearchURL, globalTimeout, httpClient, openSearchConData, log, basicAuth)
-	err = search.ReloadOpensearchSecureSettings()
-	if err != nil {
-		log.Errorf("Unable to reload security settings")
-		os.Exit(1)
+	openSearch.SnapshotName = snapshotName
+	openSearch.WaitSettings = waitSettings
+	openSearch.RepositorySettings = model.SnapshotRepositorySettings{
+		Type:                   RepoType,
+		Location:               RepoPath,
+		ChunkSize:              ChunkSize,
+		MaxSnapshotBytesPerSec: MaxSnapshotBytesPerSec,
+		MaxRestoreBytesPerSec:  MaxRestoreBytesPerSec,
+		SkipVerify:             SkipRepoVerify,
+	}
+	openSearch.RecoverySettings = model.RestoreRecoverySettings{
+		MaxBytesPerSec:           RecoveryMaxBytesPerSec,
+		NodeConcurrentRecoveries: NodeConcurrentRecoveries,
+		MaxConcurrentRecoveries:  MaxConcurrentRecoveries,
+	}
+	openSearch.RestoreOptions = model.RestoreOptions{
+		Indices:        RestoreIndices,
+		IncludeAliases: &IncludeAliases,
+	}
+	openSearch.RestoreIndexSettings = model.RestoreIndexSettings{
+		RefreshInterval: RestoreRefreshInterval,
+	}
+	if RestoreReplicas >= 0 {
+		openSearch.RestoreIndexSettings.NumberOfReplicas = &RestoreReplicas
+	}
+	if RepoType == constants.S3SnapshotRepoType {
+		err = search.ReloadOpensearchSecureSettings()
+		if err != nil {
+			return fmt.Errorf("Unable to reload security settings: %v", err)
+		}
 	}
 
 	switch strings.ToLower(Operation) {
@@ -247,55 +446,71 @@ func main() {
 		log.Info("Commencing opensearch backup ..")
 		err = openSearch.Backup()
 		if err != nil {
-			log.Errorf("Operation '%s' unsuccessfull due to %v", Operation, zap.Error(err))
-			os.Exit(1)
+			return err
+		}
+		// record the snapshot name, which the restore cannot render again from a template
+		err = k8s.AnnotateBackup(VeleroNamespace, VeleroBackupName, constants.SnapshotNameAnnotation, snapshotName)
+		if err != nil {
+			return fmt.Errorf("Unable to record snapshot name '%s' on backup: %v", snapshotName, err)
 		}
 		log.Infof("%s backup was successfull", strings.ToTitle(Component))
 
 	case constants.RestoreOperation:
+		if TestMode {
+			// OpenSearch test restore handling, live state is left untouched
+			log.Infof("Commencing OpenSearch test restore with index prefix '%s' ..", constants.TestRestoreIndexPrefix)
+			openSearch.RestoreRenamePrefix = constants.TestRestoreIndexPrefix
+			err = openSearch.TestRestore()
+			if err != nil {
+				return fmt.Errorf("Test restore unsuccessfull due to %v", err)
+			}
+			log.Infof("%s test restore was successfull. Live state was untouched: the operator was not scaled down, no services or data were deleted", strings.ToTitle(Component))
+			break
+		}
+
 		// OpenSearch restore handling
 		log.Infof("Commencing OpenSearch restore ..")
 
 		err = k8s.ScaleDeployment(opensearchVar.OperatorDeploymentLabelSelector, opensearchVar.Namespace, opensearchVar.OperatorDeploymentName, int32(0))
 		if err != nil {
-			log.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, zap.Error(err))
-			os.Exit(1)
+			return fmt.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, err)
+		}
+
+		// Drain OpenSearch Dashboards, so it does not write to its indices while they are restored
+		ok, err := k8s.CheckDeployment(opensearchVar.OSDDeploymentLabelSelector, opensearchVar.Namespace)
+		if err != nil {
+			return fmt.Errorf("Unable to detect OSD deployment '%s' due to %v", opensearchVar.OSDLabelSelector, err)
+		}
+		if ok {
+			err = k8s.ScaleDeployment(opensearchVar.OSDLabelSelector, opensearchVar.Namespace, opensearchVar.OSDDeploymentName, int32(0))
+			if err != nil {
+				return fmt.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OSDDeploymentName, err)
+			}
+			err = k8s.WaitForPodsTerminated(opensearchVar.OSDLabelSelector, opensearchVar.Namespace, OSDDrainTimeout)
+			if err != nil {
+				return fmt.Errorf("OSD deployment '%s' was not drained due to %v", opensearchVar.OSDDeploymentName, err)
+			}
 		}
 
 		if isLegacyOS {
 			err = k8s.ScaleDeployment(opensearchVar.IngestLabelSelector, opensearchVar.Namespace, opensearchVar.IngestResourceName, int32(0))
 			if err != nil {
-				log.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.IngestResourceName, zap.Error(err))
-				os.Exit(1)
+				return fmt.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.IngestResourceName, err)
 			}
 		}
 
 		if !isLegacyOS {
 			err = k8s.DeleteOpenSearchService()
 			if err != nil {
-				log.Errorf("Failed to delete opensearch service")
-				os.Exit(1)
+				return fmt.Errorf("Failed to delete opensearch service: %v", err)
 			}
 		}
 
 		err = openSearch.Restore()
 		if err != nil {
-			log.Errorf("Operation '%s' unsuccessfull due to %v", Operation, zap.Error(err))
-			os.Exit(1)
+			return err
 		}
 
-		ok, err := k8s.CheckDeployment(opensearchVar.OSDDeploymentLabelSelector, opensearchVar.Namespace)
-		if err != nil {
-			log.Errorf("Unable to detect OSD deployment '%s' due to %v", opensearchVar.OSDLabelSelector, zap.Error(err))
-			os.Exit(1)
-		}
-		// If kibana is deployed then scale it down
-		if ok {
-			err = k8s.ScaleDeployment(opensearchVar.OSDLabelSelector, opensearchVar.Namespace, opensearchVar.OSDDeploymentName, int32(0))
-			if err != nil {
-				log.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OSDDeploymentName, zap.Error(err))
-			}
-		}
 		err = k8s.ScaleDeployment(opensearchVar.OperatorDeploymentLabelSelector, opensearchVar.Namespace, opensearchVar.OperatorDeploymentName, int32(1))
 		if err != nil {
 			log.Errorf("Unable to scale deployment '%s' due to %v", opensearchVar.OperatorDeploymentName, zap.Error(err))
@@ -304,5 +519,29 @@ func main() {
 		log.Infof("%s restore was successfull", strings.ToTitle(Component))
 
 	}
+	return nil
+}
 
+// notify posts the completion notification of the operation to the notification webhook, if any.
+// Failures to notify are logged, they do not fail the operation.
+func notify(log *zap.SugaredLogger, operation, snapshotName string, start time.Time, opErr error) {
+	if NotifyURL == "" {
+		return
+	}
+	payload := model.NotificationPayload{
+		Operation:    operation,
+		Status:       constants.NotificationStatusSuccess,
+		SnapshotName: snapshotName,
+		Duration:     time.Since(start).Round(time.Second).String(),
+		Cluster:      ClusterName,
+	}
+	if opErr != nil {
+		payload.Status = constants.NotificationStatusFailure
+		payload.Error = opErr.Error()
+	}
+	if err := futil.NotifyWebhook(&http.Client{}, NotifyURL, payload); err != nil {
+		log.Warnf("Unable to notify the completion of the '%s' operation: %v", operation, err)
+		return
+	}
+	log.Infof("Completion of the '%s' operation notified", operation)
 }
@@ -30,6 +30,12 @@ type Opensearch interface {
 	// RegisterSnapshotRepository creates a new S3 based repository
 	RegisterSnapshotRepository() error
 
+	// VerifySnapshotRepository checks that the nodes can access the snapshot repository
+	VerifySnapshotRepository() error
+
+	// WaitForSnapshotSlot waits until fewer than the maximum number of snapshots are in progress in the cluster
+	WaitForSnapshotSlot() error
+
 	// TriggerSnapshot starts the snapshot(backup) of the Opensearch data streams
 	TriggerSnapshot() error
 
@@ -39,6 +45,18 @@ type Opensearch interface {
 	// DeleteData deletes all data streams and indices
 	DeleteData() error
 
+	// ApplyRecoverySettings sets the cluster recovery settings used during restore
+	ApplyRecoverySettings() error
+
+	// ResetRecoverySettings resets the cluster recovery settings to their defaults
+	ResetRecoverySettings() error
+
+	// ApplyRestoreIndexSettings records the snapshot settings of the restored indices and overrides them while restoring
+	ApplyRestoreIndexSettings() error
+
+	// ResetRestoreIndexSettings puts back the snapshot settings of the restored indices once the restore is done
+	ResetRestoreIndexSettings() error
+
 	// TriggerRestore starts the snapshot restore of the Opensearch data streams
 	TriggerRestore() error
 
@@ -51,6 +69,9 @@ type Opensearch interface {
 	// Restore Toplevel method to start the restore operation
 	Restore() error
 
+	// TestRestore Toplevel method to restore the snapshot into renamed indices without touching live data
+	TestRestore() error
+
 	// BasicAuthRequired tells whether to use basic auth while performing http requests or not
 	BasicAuthRequired() bool
 
@@ -66,6 +87,27 @@ type OpensearchImpl struct {
 	SecretData *types.ConnectionData
 	Log        *zap.SugaredLogger
 	BasicAuth  *BasicAuth
+	// RepositorySettings optional settings used when registering the snapshot repository
+	RepositorySettings types.SnapshotRepositorySettings
+	// SnapshotName if set, the name of the snapshot to create or restore instead of the Velero backup name
+	SnapshotName string
+	// RestoreRenamePrefix if set, restored indices and data streams are renamed with this prefix
+	RestoreRenamePrefix string
+	// RecoverySettings optional cluster recovery settings applied while restoring
+	RecoverySettings types.RestoreRecoverySettings
+	// RestoreOptions optional options of the snapshot restore request
+	RestoreOptions types.RestoreOptions
+	// RestoreIndexSettings optional settings of the restored indices applied while restoring
+	RestoreIndexSettings types.RestoreIndexSettings
+	// HealthCheckSettings optional poll interval and timeout of the reachability and health checks
+	HealthCheckSettings types.HealthCheckSettings
+	// MaxConcurrentSnapshots if positive, the backup waits until fewer snapshots are in progress in the cluster.
+	// The limit is approximate, backups starting at the same time may exceed it
+	MaxConcurrentSnapshots int
+	// WaitSettings optional bounds of the random waits between retries
+	WaitSettings types.WaitSettings
+	// restoredIndexSettings the snapshot settings of the restored indices overridden while restoring, keyed by index
+	restoredIndexSettings map[string]map[string]interface{}
 }
 
 // BasicAuth for BasicAuth interface
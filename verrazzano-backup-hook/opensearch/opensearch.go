@@ -16,10 +16,13 @@ import (
 	"io/ioutil"
 	"net/http"
 	"os"
+	"path"
+	"sort"
+	"strings"
 	"time"
 )
 
-// HTTPHelper supports net/http calls of type GET/POST/DELETE
+// HTTPHelper supports net/http calls of type GET/POST/PUT/DELETE
 func (o *OpensearchImpl) HTTPHelper(ctx context.Context, method, requestURL string, body io.Reader, data interface{}) error {
 	o.Log.Debugf("Invoking HTTP '%s' request with url '%s'", method, requestURL)
 	var response *http.Response
@@ -39,6 +42,8 @@ func (o *OpensearchImpl) HTTPHelper(ctx context.Context, method, requestURL stri
 		request, err = http.NewRequestWithContext(ctx, http.MethodGet, requestURL, body)
 	case "POST":
 		request, err = http.NewRequestWithContext(ctx, http.MethodPost, requestURL, body)
+	case "PUT":
+		request, err = http.NewRequestWithContext(ctx, http.MethodPut, requestURL, body)
 	case "DELETE":
 		request, err = http.NewRequestWithContext(ctx, http.MethodDelete, requestURL, body)
 	}
@@ -79,37 +84,59 @@ func (o *OpensearchImpl) HTTPHelper(ctx context.Context, method, requestURL stri
 	return nil
 }
 
+// healthCheckTimeout returns the overall time to wait for a successful reachability or health check
+func (o *OpensearchImpl) healthCheckTimeout() (time.Duration, error) {
+	if o.HealthCheckSettings.Timeout > 0 {
+		return o.HealthCheckSettings.Timeout, nil
+	}
+	timeParse, err := time.ParseDuration(o.SecretData.VeleroTimeout)
+	if err != nil {
+		o.Log.Errorf("Unable to parse time duration ", zap.Error(err))
+		return 0, err
+	}
+	return timeParse, nil
+}
+
+// waitHealthCheck waits for the poll interval before the next reachability or health check and returns the time waited.
+// Without a poll interval, a random time bounded by the timeout is waited.
+func (o *OpensearchImpl) waitHealthCheck(message string, timeout time.Duration) (time.Duration, error) {
+	if o.HealthCheckSettings.PollInterval > 0 {
+		o.Log.Infof("%v . Wait for '%v' ...", message, o.HealthCheckSettings.PollInterval)
+		time.Sleep(o.HealthCheckSettings.PollInterval)
+		return o.HealthCheckSettings.PollInterval, nil
+	}
+	return utilities.WaitRandom(message, timeout.String(), o.WaitSettings, o.Log)
+}
+
 // EnsureOpenSearchIsReachable is used determine whether OpenSearch cluster is reachable
 func (o *OpensearchImpl) EnsureOpenSearchIsReachable() error {
 	o.Log.Infof("Checking if cluster is reachable")
 	var osinfo types.OpenSearchClusterInfo
 	done := false
-	var timeSeconds float64
+	var elapsed time.Duration
 
 	if utilities.GetEnvWithDefault(constants.DevKey, constants.FalseString) == constants.TrueString {
 		// if UT flag is set, skip to avoid retry logic
 		return nil
 	}
 
-	timeParse, err := time.ParseDuration(o.SecretData.VeleroTimeout)
+	timeout, err := o.healthCheckTimeout()
 	if err != nil {
-		o.Log.Errorf("Unable to parse time duration ", zap.Error(err))
 		return err
 	}
-	totalSeconds := timeParse.Seconds()
 
 	for !done {
 		err := o.HTTPHelper(context.Background(), "GET", o.BaseURL, nil, &osinfo)
 		if err != nil {
-			if timeSeconds < totalSeconds {
+			if elapsed < timeout {
 				message := "Cluster is not reachable"
-				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.Log)
+				duration, err := o.waitHealthCheck(message, timeout)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				elapsed = elapsed + duration
 			} else {
-				o.Log.Errorf("VeleroTimeout '%s' exceeded. Cluster not reachable", o.SecretData.VeleroTimeout)
+				o.Log.Errorf("Timeout '%v' exceeded. Cluster not reachable", timeout)
 				return err
 			}
 		} else {
@@ -133,14 +160,12 @@ func (o *OpensearchImpl) EnsureOpenSearchIsHealthy() error {
 
 	healthURL := fmt.Sprintf("%s/_cluster/health", o.BaseURL)
 	healthReachable := false
-	var timeSeconds float64
+	var elapsed time.Duration
 
-	timeParse, err := time.ParseDuration(o.SecretData.VeleroTimeout)
+	timeout, err := o.healthCheckTimeout()
 	if err != nil {
-		o.Log.Errorf("Unable to parse time duration ", zap.Error(err))
 		return err
 	}
-	totalSeconds := timeParse.Seconds()
 
 	if utilities.GetEnvWithDefault(constants.DevKey, constants.FalseString) == constants.TrueString {
 		// if UT flag is set, skip to avoid retry logic
@@ -150,15 +175,15 @@ func (o *OpensearchImpl) EnsureOpenSearchIsHealthy() error {
 	for !healthReachable {
 		err = o.HTTPHelper(context.Background(), "GET", healthURL, nil, &clusterHealth)
 		if err != nil {
-			if timeSeconds < totalSeconds {
+			if elapsed < timeout {
 				message := "Cluster health endpoint is not reachable"
-				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.Log)
+				duration, err := o.waitHealthCheck(message, timeout)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				elapsed = elapsed + duration
 			} else {
-				o.Log.Errorf("VeleroTimeout '%s' exceeded. Cluster health endpoint is not reachable", o.SecretData.VeleroTimeout)
+				o.Log.Errorf("Timeout '%v' exceeded. Cluster health endpoint is not reachable", timeout)
 				return err
 			}
 		} else {
@@ -172,29 +197,29 @@ func (o *OpensearchImpl) EnsureOpenSearchIsHealthy() error {
 	for !healthGreen {
 		err = o.HTTPHelper(context.Background(), "GET", healthURL, nil, &clusterHealth)
 		if err != nil {
-			if timeSeconds < totalSeconds {
+			if elapsed < timeout {
 				message := "Json unmarshalling error"
-				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.Log)
+				duration, err := o.waitHealthCheck(message, timeout)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				elapsed = elapsed + duration
 				continue
 			} else {
-				return fmt.Errorf("VeleroTimeout '%s' exceeded. Json unmarshalling error while checking cluster health %v", o.SecretData.VeleroTimeout, zap.Error(err))
+				return fmt.Errorf("Timeout '%v' exceeded. Json unmarshalling error while checking cluster health %v", timeout, zap.Error(err))
 			}
 		}
 
 		if clusterHealth.Status != "green" {
-			if timeSeconds < totalSeconds {
+			if elapsed < timeout {
 				message := fmt.Sprintf("Cluster health is '%s'", clusterHealth.Status)
-				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.Log)
+				duration, err := o.waitHealthCheck(message, timeout)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				elapsed = elapsed + duration
 			} else {
-				return fmt.Errorf("VeleroTimeout '%s' exceeded. Cluster health expected 'green' , current state '%s'", o.SecretData.VeleroTimeout, clusterHealth.Status)
+				return fmt.Errorf("Timeout '%v' exceeded. Cluster health expected 'green' , current state '%s'", timeout, clusterHealth.Status)
 			}
 		} else {
 			healthGreen = true
@@ -230,17 +255,36 @@ func (o *OpensearchImpl) ReloadOpensearchSecureSettings() error {
 	return fmt.Errorf("Not all nodes were updated successfully. Total = '%v', Failed = '%v' , Successful = '%v'", secureSettings.ClusterNodes.Total, secureSettings.ClusterNodes.Failed, secureSettings.ClusterNodes.Successful)
 }
 
-// RegisterSnapshotRepository registers an object store with OpenSearch using the s3-plugin
+// RegisterSnapshotRepository registers an object store with OpenSearch using the s3-plugin, or a shared filesystem
+// location if the repository type is fs. The repository is then verified, unless the verification is skipped, so that a
+// misconfigured repository fails the operation before a snapshot is taken or restored.
 func (o *OpensearchImpl) RegisterSnapshotRepository() error {
-	o.Log.Infof("Registering s3 backend repository '%s'", constants.OpenSearchSnapShotRepoName)
+	repoType := o.RepositorySettings.Type
+	if repoType == "" {
+		repoType = constants.S3SnapshotRepoType
+	}
+	o.Log.Infof("Registering %s backend repository '%s'", repoType, constants.OpenSearchSnapShotRepoName)
 	var snapshotPayload types.OpenSearchSnapshotRequestPayload
 	var registerResponse types.OpenSearchOperationResponse
-	snapshotPayload.Type = "s3"
-	snapshotPayload.Settings.Bucket = o.SecretData.BucketName
-	snapshotPayload.Settings.Region = o.SecretData.RegionName
-	snapshotPayload.Settings.Client = "default"
-	snapshotPayload.Settings.Endpoint = o.SecretData.Endpoint
-	snapshotPayload.Settings.PathStyleAccess = true
+	snapshotPayload.Type = repoType
+	switch repoType {
+	case constants.S3SnapshotRepoType:
+		snapshotPayload.Settings.Bucket = o.SecretData.BucketName
+		snapshotPayload.Settings.Region = o.SecretData.RegionName
+		snapshotPayload.Settings.Client = "default"
+		snapshotPayload.Settings.Endpoint = o.SecretData.Endpoint
+		snapshotPayload.Settings.PathStyleAccess = true
+	case constants.FSSnapshotRepoType:
+		if o.RepositorySettings.Location == "" {
+			return fmt.Errorf("A location is required to register a %s snapshot repository", repoType)
+		}
+		snapshotPayload.Settings.Location = o.RepositorySettings.Location
+	default:
+		return fmt.Errorf("Unsupported snapshot repository type '%s'", repoType)
+	}
+	snapshotPayload.Settings.ChunkSize = o.RepositorySettings.ChunkSize
+	snapshotPayload.Settings.MaxSnapshotBytesPerSec = o.RepositorySettings.MaxSnapshotBytesPerSec
+	snapshotPayload.Settings.MaxRestoreBytesPerSec = o.RepositorySettings.MaxRestoreBytesPerSec
 
 	postBody, err := json.Marshal(snapshotPayload)
 	if err != nil {
@@ -248,24 +292,58 @@ func (o *OpensearchImpl) RegisterSnapshotRepository() error {
 	}
 
 	url := fmt.Sprintf("%s/_snapshot/%s", o.BaseURL, constants.OpenSearchSnapShotRepoName)
+	if o.RepositorySettings.SkipVerify {
+		// OpenSearch verifies the repository on registration, unless told otherwise
+		url += "?verify=false"
+	}
 
 	err = o.HTTPHelper(context.Background(), "POST", url, bytes.NewBuffer(postBody), &registerResponse)
 	if err != nil {
 		return err
 	}
 
-	if registerResponse.Acknowledged {
-		o.Log.Infof("Snapshot registered successfully !")
+	if !registerResponse.Acknowledged {
+		return fmt.Errorf("Snapshot registration unsuccessful. Response = %v", registerResponse)
+	}
+	o.Log.Infof("Snapshot registered successfully !")
+
+	if o.RepositorySettings.SkipVerify {
+		o.Log.Infof("Skipping the verification of the snapshot repository '%s'", constants.OpenSearchSnapShotRepoName)
 		return nil
 	}
-	return fmt.Errorf("Snapshot registration unsuccessful. Response = %v", registerResponse)
+	return o.VerifySnapshotRepository()
+}
+
+// VerifySnapshotRepository checks that the nodes of the cluster can access the registered snapshot repository.
+// OpenSearch responds with the nodes which verified the repository, or with an error if a node could not access it.
+func (o *OpensearchImpl) VerifySnapshotRepository() error {
+	o.Log.Infof("Verifying snapshot repository '%s'", constants.OpenSearchSnapShotRepoName)
+	url := fmt.Sprintf("%s/_snapshot/%s/_verify", o.BaseURL, constants.OpenSearchSnapShotRepoName)
+	var verifyResponse types.OpenSearchVerifyRepositoryResponse
+	err := o.HTTPHelper(context.Background(), "POST", url, nil, &verifyResponse)
+	if err != nil {
+		return fmt.Errorf("Snapshot repository '%s' verification failed: %v", constants.OpenSearchSnapShotRepoName, err)
+	}
+	if len(verifyResponse.Nodes) == 0 {
+		return fmt.Errorf("Snapshot repository '%s' verification failed, the repository is unreachable or misconfigured. Check the bucket, endpoint and credentials of the backup storage location, or the location of a fs repository", constants.OpenSearchSnapShotRepoName)
+	}
+	o.Log.Infof("Snapshot repository verified by %d nodes", len(verifyResponse.Nodes))
+	return nil
+}
+
+// snapshotName returns the name of the snapshot to create or restore, the Velero backup name by default
+func (o *OpensearchImpl) snapshotName() string {
+	if o.SnapshotName != "" {
+		return o.SnapshotName
+	}
+	return o.SecretData.BackupName
 }
 
 // TriggerSnapshot this triggers a snapshot/backup of all the data streams/indices
 func (o *OpensearchImpl) TriggerSnapshot() error {
-	o.Log.Infof("Triggering snapshot with name '%s'", o.SecretData.BackupName)
+	o.Log.Infof("Triggering snapshot with name '%s'", o.snapshotName())
 	var snapshotResponse types.OpenSearchSnapshotResponse
-	snapShotURL := fmt.Sprintf("%s/_snapshot/%s/%s", o.BaseURL, constants.OpenSearchSnapShotRepoName, o.SecretData.BackupName)
+	snapShotURL := fmt.Sprintf("%s/_snapshot/%s/%s", o.BaseURL, constants.OpenSearchSnapShotRepoName, o.snapshotName())
 
 	err := o.HTTPHelper(context.Background(), "POST", snapShotURL, nil, &snapshotResponse)
 	if err != nil {
@@ -281,8 +359,8 @@ func (o *OpensearchImpl) TriggerSnapshot() error {
 
 // CheckSnapshotProgress checks the data backup progress.
 func (o *OpensearchImpl) CheckSnapshotProgress() error {
-	o.Log.Infof("Checking snapshot progress with name '%s'", o.SecretData.BackupName)
-	snapShotURL := fmt.Sprintf("%s/_snapshot/%s/%s", o.BaseURL, constants.OpenSearchSnapShotRepoName, o.SecretData.BackupName)
+	o.Log.Infof("Checking snapshot progress with name '%s'", o.snapshotName())
+	snapShotURL := fmt.Sprintf("%s/_snapshot/%s/%s", o.BaseURL, constants.OpenSearchSnapShotRepoName, o.snapshotName())
 	var snapshotInfo types.OpenSearchSnapshotStatus
 
 	if utilities.GetEnvWithDefault(constants.DevKey, constants.FalseString) == constants.TrueString {
@@ -307,20 +385,20 @@ func (o *OpensearchImpl) CheckSnapshotProgress() error {
 		switch snapshotInfo.Snapshots[0].State {
 		case constants.OpenSearchSnapShotInProgress:
 			if timeSeconds < totalSeconds {
-				message := fmt.Sprintf("Snapshot '%s' is in progress", o.SecretData.BackupName)
-				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.Log)
+				message := fmt.Sprintf("Snapshot '%s' is in progress", o.snapshotName())
+				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.WaitSettings, o.Log)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				timeSeconds = timeSeconds + duration.Seconds()
 			} else {
-				return fmt.Errorf("VeleroTimeout '%s' exceeded. Snapshot '%s' state is still IN_PROGRESS", o.SecretData.VeleroTimeout, o.SecretData.BackupName)
+				return fmt.Errorf("VeleroTimeout '%s' exceeded. Snapshot '%s' state is still IN_PROGRESS", o.SecretData.VeleroTimeout, o.snapshotName())
 			}
 		case constants.OpenSearchSnapShotSuccess:
-			o.Log.Infof("Snapshot '%s' complete", o.SecretData.BackupName)
+			o.Log.Infof("Snapshot '%s' complete", o.snapshotName())
 			done = true
 		default:
-			return fmt.Errorf("Snapshot '%s' state is invalid '%s'", o.SecretData.BackupName, snapshotInfo.Snapshots[0].State)
+			return fmt.Errorf("Snapshot '%s' state is invalid '%s'", o.snapshotName(), snapshotInfo.Snapshots[0].State)
 		}
 	}
 
@@ -330,8 +408,48 @@ func (o *OpensearchImpl) CheckSnapshotProgress() error {
 	return nil
 }
 
+// WaitForSnapshotSlot waits until fewer than MaxConcurrentSnapshots snapshots are in progress in the cluster.
+// Every backup runs in its own hook process, so the snapshots in progress are the ones reported by OpenSearch.
+// Backups checking at the same time may still take their snapshots together, the limit is best effort.
+func (o *OpensearchImpl) WaitForSnapshotSlot() error {
+	if o.MaxConcurrentSnapshots <= 0 {
+		return nil
+	}
+	statusURL := fmt.Sprintf("%s/_snapshot/_status", o.BaseURL)
+	timeParse, err := time.ParseDuration(o.SecretData.VeleroTimeout)
+	if err != nil {
+		o.Log.Errorf("Unable to parse time duration ", zap.Error(err))
+		return err
+	}
+
+	var waited time.Duration
+	for {
+		var snapshotStatus types.OpenSearchSnapshotStatus
+		err := o.HTTPHelper(context.Background(), "GET", statusURL, nil, &snapshotStatus)
+		if err != nil {
+			return err
+		}
+		if len(snapshotStatus.Snapshots) < o.MaxConcurrentSnapshots {
+			return nil
+		}
+		if waited >= timeParse {
+			return fmt.Errorf("VeleroTimeout '%s' exceeded. %d snapshots are still in progress", o.SecretData.VeleroTimeout, len(snapshotStatus.Snapshots))
+		}
+		message := fmt.Sprintf("%d snapshots are in progress, the maximum is %d", len(snapshotStatus.Snapshots), o.MaxConcurrentSnapshots)
+		duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.WaitSettings, o.Log)
+		if err != nil {
+			return err
+		}
+		waited += duration
+	}
+}
+
 // DeleteData used to delete data streams before restore.
+// When only some indices are restored, only the data streams and indices which are restored are deleted.
 func (o *OpensearchImpl) DeleteData() error {
+	if o.RestoreOptions.Indices != "" {
+		return o.deleteRestoredData()
+	}
 	o.Log.Infof("Deleting data streams followed by index ..")
 	dataStreamURL := fmt.Sprintf("%s/_data_stream/*", o.BaseURL)
 	dataIndexURL := fmt.Sprintf("%s/*", o.BaseURL)
@@ -366,16 +484,162 @@ func (o *OpensearchImpl) DeleteData() error {
 	return nil
 }
 
+// deleteRestoredData deletes the data streams and indices matching the restore indices, so that the data which is not
+// restored from the snapshot is kept
+func (o *OpensearchImpl) deleteRestoredData() error {
+	patterns := strings.Split(o.RestoreOptions.Indices, ",")
+	o.Log.Infof("Deleting data streams followed by index matching '%s' ..", o.RestoreOptions.Indices)
+
+	var dataStreams types.OpenSearchDataStreams
+	err := o.HTTPHelper(context.Background(), "GET", fmt.Sprintf("%s/_data_stream", o.BaseURL), nil, &dataStreams)
+	if err != nil {
+		return err
+	}
+	var restoredDataStreams []string
+	for _, ds := range dataStreams.DataStreams {
+		if matchIndexPatterns(ds.Name, patterns) {
+			restoredDataStreams = append(restoredDataStreams, ds.Name)
+		}
+	}
+
+	var deleteResponse types.OpenSearchOperationResponse
+	if len(restoredDataStreams) > 0 {
+		dataStreamURL := fmt.Sprintf("%s/_data_stream/%s", o.BaseURL, strings.Join(restoredDataStreams, ","))
+		err = o.HTTPHelper(context.Background(), "DELETE", dataStreamURL, nil, &deleteResponse)
+		if err != nil {
+			return err
+		}
+		if !deleteResponse.Acknowledged {
+			return fmt.Errorf("Data streams deletion failure. Response = %v ", deleteResponse)
+		}
+	}
+
+	indices := o.RestoreOptions.Indices
+	if o.BasicAuthRequired() {
+		// skip deleting .opendistro_security index, even if the restore indices match it
+		indices = indices + ",-.opendistro_security"
+	}
+	// The restore indices which do not exist yet are ignored
+	dataIndexURL := fmt.Sprintf("%s/%s?ignore_unavailable=true&allow_no_indices=true", o.BaseURL, indices)
+	deleteResponse = types.OpenSearchOperationResponse{}
+	err = o.HTTPHelper(context.Background(), "DELETE", dataIndexURL, nil, &deleteResponse)
+	if err != nil {
+		return err
+	}
+	if !deleteResponse.Acknowledged {
+		return fmt.Errorf("Data index deletion failure. Response = %v ", deleteResponse)
+	}
+
+	o.Log.Infof("Data streams %v and data indexes '%s' deleted successfully !", restoredDataStreams, indices)
+	return nil
+}
+
+// matchIndexPatterns returns true if the name matches the comma separated index patterns, where a pattern prefixed with
+// '-' excludes the names it matches from the preceding patterns
+func matchIndexPatterns(name string, patterns []string) bool {
+	matched := false
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if strings.HasPrefix(pattern, "-") {
+			if ok, _ := path.Match(strings.TrimPrefix(pattern, "-"), name); ok {
+				matched = false
+			}
+			continue
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// ApplyRecoverySettings sets the configured shard recovery limits, so that the restore does not overwhelm the cluster
+func (o *OpensearchImpl) ApplyRecoverySettings() error {
+	settings := map[string]interface{}{}
+	if o.RecoverySettings.MaxBytesPerSec != "" {
+		settings[constants.RecoveryMaxBytesPerSecSetting] = o.RecoverySettings.MaxBytesPerSec
+	}
+	if o.RecoverySettings.NodeConcurrentRecoveries > 0 {
+		settings[constants.NodeConcurrentRecoveriesSetting] = o.RecoverySettings.NodeConcurrentRecoveries
+	}
+	if o.RecoverySettings.MaxConcurrentRecoveries > 0 {
+		settings[constants.NodeInitialPrimariesRecoveriesSetting] = o.RecoverySettings.MaxConcurrentRecoveries
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+	o.Log.Infof("Applying recovery settings %v", settings)
+	// The recovery settings only apply to the restore, they must not outlive a restart of the cluster
+	return o.updateClusterSettings(settings, false)
+}
+
+// ResetRecoverySettings resets the shard recovery limits set by ApplyRecoverySettings to their defaults
+func (o *OpensearchImpl) ResetRecoverySettings() error {
+	settings := map[string]interface{}{}
+	if o.RecoverySettings.MaxBytesPerSec != "" {
+		settings[constants.RecoveryMaxBytesPerSecSetting] = nil
+	}
+	if o.RecoverySettings.NodeConcurrentRecoveries > 0 {
+		settings[constants.NodeConcurrentRecoveriesSetting] = nil
+	}
+	if o.RecoverySettings.MaxConcurrentRecoveries > 0 {
+		settings[constants.NodeInitialPrimariesRecoveriesSetting] = nil
+	}
+	if len(settings) == 0 {
+		return nil
+	}
+	o.Log.Infof("Resetting recovery settings")
+	return o.updateClusterSettings(settings, false)
+}
+
+// updateClusterSettings updates persistent cluster settings if persistent, else transient cluster settings, which are
+// reset with the cluster. A nil value resets the setting to its default.
+func (o *OpensearchImpl) updateClusterSettings(settings map[string]interface{}, persistent bool) error {
+	settingsURL := fmt.Sprintf("%s/_cluster/settings", o.BaseURL)
+	var settingsResponse types.OpenSearchOperationResponse
+	payload := types.OpenSearchClusterSettingsPayload{Transient: settings}
+	if persistent {
+		payload = types.OpenSearchClusterSettingsPayload{Persistent: settings}
+	}
+	jsonBody, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	err = o.HTTPHelper(context.Background(), "PUT", settingsURL, bytes.NewBuffer(jsonBody), &settingsResponse)
+	if err != nil {
+		return err
+	}
+	if !settingsResponse.Acknowledged {
+		return fmt.Errorf("Cluster settings update failed. Response = %v ", settingsResponse)
+	}
+	return nil
+}
+
 // TriggerRestore Triggers a restore from a specified snapshot
 func (o *OpensearchImpl) TriggerRestore() error {
-	o.Log.Infof("Triggering restore with name '%s'", o.SecretData.BackupName)
-	restoreURL := fmt.Sprintf("%s/_snapshot/%s/%s/_restore", o.BaseURL, constants.OpenSearchSnapShotRepoName, o.SecretData.BackupName)
+	o.Log.Infof("Triggering restore with name '%s'", o.snapshotName())
+	restoreURL := fmt.Sprintf("%s/_snapshot/%s/%s/_restore", o.BaseURL, constants.OpenSearchSnapShotRepoName, o.snapshotName())
 	var restoreResponse types.OpenSearchSnapshotResponse
 
+	indices := constants.OpenSearchSecurityIndexExclusion
+	if o.RestoreOptions.Indices != "" {
+		// The security index is never restored
+		indices = o.RestoreOptions.Indices + "," + constants.OpenSearchSecurityIndexExclusion
+	}
 	body := map[string]interface{}{
-		"indices":            "-.opendistro_security",
+		"indices":            indices,
 		"ignore_unavailable": true,
 	}
+	if o.RestoreOptions.IncludeAliases != nil {
+		body["include_aliases"] = *o.RestoreOptions.IncludeAliases
+	}
+	if o.RestoreRenamePrefix != "" {
+		// Restore into renamed indices so that the live indices and aliases are not overwritten
+		o.Log.Infof("Restored indices and data streams will be renamed with prefix '%s'", o.RestoreRenamePrefix)
+		body["rename_pattern"] = "(.+)"
+		body["rename_replacement"] = o.RestoreRenamePrefix + "$1"
+		body["include_aliases"] = false
+	}
 	// Marshal the body map to JSON.
 	jsonBody, err := json.Marshal(body)
 	if err != nil {
@@ -393,9 +657,176 @@ func (o *OpensearchImpl) TriggerRestore() error {
 	return nil
 }
 
+// restoreIndexSettings returns the settings of the restored indices overridden while restoring
+func (o *OpensearchImpl) restoreIndexSettings() map[string]interface{} {
+	settings := map[string]interface{}{}
+	if o.RestoreIndexSettings.RefreshInterval != "" {
+		settings[constants.IndexRefreshIntervalSetting] = o.RestoreIndexSettings.RefreshInterval
+	}
+	if o.RestoreIndexSettings.NumberOfReplicas != nil {
+		settings[constants.IndexNumberOfReplicasSetting] = *o.RestoreIndexSettings.NumberOfReplicas
+	}
+	return settings
+}
+
+// ApplyRestoreIndexSettings records the settings the restored indices have in the snapshot, then overrides them with
+// the restore index settings while their shards are restored. The restore creates the restored indices with their
+// snapshot settings before it is accepted, so their settings are read once the restore is triggered.
+func (o *OpensearchImpl) ApplyRestoreIndexSettings() error {
+	settings := o.restoreIndexSettings()
+	if len(settings) == 0 {
+		return nil
+	}
+	indices, err := o.restoredIndices()
+	if err != nil {
+		return err
+	}
+	recorded := map[string]map[string]interface{}{}
+	for _, batch := range indexBatches(indices) {
+		settingsURL := fmt.Sprintf("%s/%s/_settings/%s,%s?flat_settings=true", o.BaseURL, strings.Join(batch, ","),
+			constants.IndexRefreshIntervalSetting, constants.IndexNumberOfReplicasSetting)
+		var indexSettings types.OpenSearchIndexSettings
+		err = o.HTTPHelper(context.Background(), "GET", settingsURL, nil, &indexSettings)
+		if err != nil {
+			return err
+		}
+		for index, current := range indexSettings {
+			values := map[string]interface{}{}
+			for setting := range settings {
+				// A setting which is not set is reset to its default
+				values[setting] = nil
+				if value, ok := current.Settings[setting]; ok {
+					values[setting] = value
+				}
+			}
+			recorded[index] = values
+		}
+	}
+	if len(recorded) == 0 {
+		return nil
+	}
+	o.restoredIndexSettings = recorded
+
+	restored := make([]string, 0, len(recorded))
+	for index := range recorded {
+		restored = append(restored, index)
+	}
+	sort.Strings(restored)
+	o.Log.Infof("Setting %v on the %d restored indices while restoring", settings, len(restored))
+	return o.updateIndexSettings(restored, settings)
+}
+
+// ResetRestoreIndexSettings puts back the settings the restored indices have in the snapshot, once the restore is done.
+// Only the indices whose settings were overridden by ApplyRestoreIndexSettings are updated.
+func (o *OpensearchImpl) ResetRestoreIndexSettings() error {
+	if len(o.restoredIndexSettings) == 0 {
+		return nil
+	}
+	// The indices with the same snapshot settings are updated together
+	groups := map[string][]string{}
+	groupSettings := map[string]map[string]interface{}{}
+	for index, settings := range o.restoredIndexSettings {
+		key, err := json.Marshal(settings)
+		if err != nil {
+			return err
+		}
+		groups[string(key)] = append(groups[string(key)], index)
+		groupSettings[string(key)] = settings
+	}
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		indices := groups[key]
+		sort.Strings(indices)
+		o.Log.Infof("Setting %v back on %d restored indices", groupSettings[key], len(indices))
+		if err := o.updateIndexSettings(indices, groupSettings[key]); err != nil {
+			return err
+		}
+	}
+	o.restoredIndexSettings = nil
+	return nil
+}
+
+// updateIndexSettings updates the settings of the given indices, in batches so that the request URLs stay short
+func (o *OpensearchImpl) updateIndexSettings(indices []string, settings map[string]interface{}) error {
+	jsonBody, err := json.Marshal(settings)
+	if err != nil {
+		return err
+	}
+	for _, batch := range indexBatches(indices) {
+		settingsURL := fmt.Sprintf("%s/%s/_settings", o.BaseURL, strings.Join(batch, ","))
+		var settingsResponse types.OpenSearchOperationResponse
+		err = o.HTTPHelper(context.Background(), "PUT", settingsURL, bytes.NewBuffer(jsonBody), &settingsResponse)
+		if err != nil {
+			return err
+		}
+		if !settingsResponse.Acknowledged {
+			return fmt.Errorf("Restored index settings update failed. Response = %v ", settingsResponse)
+		}
+	}
+	return nil
+}
+
+// restoredIndices returns the names of the indices restored from the snapshot, renamed with the restore rename prefix
+// if any. The backing indices of the restored data streams are restored with them, and the security index is never
+// restored.
+func (o *OpensearchImpl) restoredIndices() ([]string, error) {
+	snapshotURL := fmt.Sprintf("%s/_snapshot/%s/%s", o.BaseURL, constants.OpenSearchSnapShotRepoName, o.snapshotName())
+	var snapshotInfo types.OpenSearchSnapshotStatus
+	err := o.HTTPHelper(context.Background(), "GET", snapshotURL, nil, &snapshotInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(snapshotInfo.Snapshots) == 0 {
+		return nil, fmt.Errorf("Snapshot '%s' not found", o.snapshotName())
+	}
+	snapshot := snapshotInfo.Snapshots[0]
+	var patterns []string
+	if o.RestoreOptions.Indices != "" {
+		patterns = strings.Split(o.RestoreOptions.Indices, ",")
+	}
+	var restored []string
+	for _, index := range snapshot.Indices {
+		if index == constants.OpenSearchSecurityIndex {
+			continue
+		}
+		if patterns != nil && !matchIndexPatterns(index, patterns) && !isRestoredBackingIndex(index, snapshot.DataStreams, patterns) {
+			continue
+		}
+		restored = append(restored, o.RestoreRenamePrefix+index)
+	}
+	return restored, nil
+}
+
+// isRestoredBackingIndex returns true if the index is a backing index of a data stream matching the index patterns
+func isRestoredBackingIndex(index string, dataStreams []string, patterns []string) bool {
+	for _, dataStream := range dataStreams {
+		if strings.HasPrefix(index, constants.DataStreamBackingIndexPrefix+dataStream+"-") && matchIndexPatterns(dataStream, patterns) {
+			return true
+		}
+	}
+	return false
+}
+
+// indexBatches splits the indices in batches of at most RestoreIndexSettingsBatchSize indices
+func indexBatches(indices []string) [][]string {
+	var batches [][]string
+	for len(indices) > constants.RestoreIndexSettingsBatchSize {
+		batches = append(batches, indices[:constants.RestoreIndexSettingsBatchSize])
+		indices = indices[constants.RestoreIndexSettingsBatchSize:]
+	}
+	if len(indices) > 0 {
+		batches = append(batches, indices)
+	}
+	return batches
+}
+
 // CheckRestoreProgress checks progress of restore process, by monitoring all the data streams
 func (o *OpensearchImpl) CheckRestoreProgress() error {
-	o.Log.Infof("Checking restore progress with name '%s'", o.SecretData.BackupName)
+	o.Log.Infof("Checking restore progress with name '%s'", o.snapshotName())
 	dsURL := fmt.Sprintf("%s/_data_stream", o.BaseURL)
 	var snapshotInfo types.OpenSearchDataStreams
 
@@ -432,14 +863,14 @@ func (o *OpensearchImpl) CheckRestoreProgress() error {
 		if notGreen {
 			if timeSeconds < totalSeconds {
 				message := "Restore is in progress"
-				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.Log)
+				duration, err := utilities.WaitRandom(message, o.SecretData.VeleroTimeout, o.WaitSettings, o.Log)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				timeSeconds = timeSeconds + duration.Seconds()
 				notGreen = false
 			} else {
-				return fmt.Errorf("VeleroTimeout '%s' exceeded. Restore '%s' state is still IN_PROGRESS", o.SecretData.VeleroTimeout, o.SecretData.BackupName)
+				return fmt.Errorf("VeleroTimeout '%s' exceeded. Restore '%s' state is still IN_PROGRESS", o.SecretData.VeleroTimeout, o.snapshotName())
 			}
 		} else {
 			// This section is hit when all data streams are green
@@ -461,6 +892,11 @@ func (o *OpensearchImpl) Backup() error {
 		return err
 	}
 
+	// Overlapping backups wait for a snapshot slot, so they do not overwhelm the object store
+	err = o.WaitForSnapshotSlot()
+	if err != nil {
+		return err
+	}
 	err = o.TriggerSnapshot()
 	if err != nil {
 		return err
@@ -487,10 +923,21 @@ func (o *OpensearchImpl) Restore() error {
 		return err
 	}
 
+	err = o.ApplyRecoverySettings()
+	if err != nil {
+		return err
+	}
+	defer o.resetRecoverySettings()
+
 	err = o.TriggerRestore()
 	if err != nil {
 		return err
 	}
+	defer o.resetRestoreIndexSettings()
+	err = o.ApplyRestoreIndexSettings()
+	if err != nil {
+		return err
+	}
 
 	err = o.CheckRestoreProgress()
 	if err != nil {
@@ -500,6 +947,51 @@ func (o *OpensearchImpl) Restore() error {
 	return nil
 }
 
+// TestRestore - Top level method to invoke a test restore of opensearch.
+// The snapshot is restored into indices renamed with the test prefix, and existing data is not deleted.
+func (o *OpensearchImpl) TestRestore() error {
+	o.Log.Info("Start test restore steps ....")
+	if o.RestoreRenamePrefix == "" {
+		o.RestoreRenamePrefix = constants.TestRestoreIndexPrefix
+	}
+	err := o.RegisterSnapshotRepository()
+	if err != nil {
+		return err
+	}
+
+	err = o.ApplyRecoverySettings()
+	if err != nil {
+		return err
+	}
+	defer o.resetRecoverySettings()
+
+	err = o.TriggerRestore()
+	if err != nil {
+		return err
+	}
+	defer o.resetRestoreIndexSettings()
+	err = o.ApplyRestoreIndexSettings()
+	if err != nil {
+		return err
+	}
+
+	return o.CheckRestoreProgress()
+}
+
+// resetRecoverySettings resets the recovery settings once the restore is done, a failure is only logged
+func (o *OpensearchImpl) resetRecoverySettings() {
+	if err := o.ResetRecoverySettings(); err != nil {
+		o.Log.Errorf("Unable to reset recovery settings: %v", err)
+	}
+}
+
+// resetRestoreIndexSettings puts back the settings of the restored indices once the restore is done, a failure is only logged
+func (o *OpensearchImpl) resetRestoreIndexSettings() {
+	if err := o.ResetRestoreIndexSettings(); err != nil {
+		o.Log.Errorf("Unable to reset the settings of the restored indices: %v", err)
+	}
+}
+
 // BasicAuthRequired - whether to use basic auth or not
 func (o *OpensearchImpl) BasicAuthRequired() bool {
 	return o.BasicAuth.required
@@ -17,6 +17,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 const (
@@ -29,6 +30,7 @@ const (
 
 var (
 	fakeBasicAuth = opensearch.NewBasicAuth(false, "", "")
+	verifyURL     = fmt.Sprintf("%s/%s/_verify", snapshotURL, constants.OpenSearchSnapShotRepoName)
 )
 
 func logHelper() (*zap.SugaredLogger, string) {
@@ -90,6 +92,18 @@ func mockOpenSearchOperationResponse(error bool, w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(registerResponse)
 }
 
+func mockVerifySnapshotRepository(error bool, w http.ResponseWriter, r *http.Request) {
+	fmt.Println("Snapshot repository verify ...")
+	w.Header().Add("Content-Type", constants.HTTPContentType)
+	if error {
+		w.WriteHeader(http.StatusInternalServerError)
+		w.Write([]byte(`{"error": {"type": "repository_verification_exception", "reason": "[verrazzano-backup] path is not accessible on master node"}, "status": 500}`))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"nodes": {"node-1": {"name": "vmi-system-es-master-0"}}}`))
+}
+
 func mockReloadOpensearchSecureSettings(error bool, w http.ResponseWriter, r *http.Request) {
 	fmt.Println("Reload secure settings")
 	w.Header().Add("Content-Type", constants.HTTPContentType)
@@ -170,6 +184,8 @@ func TestMain(m *testing.M) {
 			mockEnsureOpenSearchIsReachable(false, w, r)
 		case healthURL:
 			mockEnsureOpenSearchIsHealthy(false, w, r)
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
 		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName), fmt.Sprintf("%s/*", dataStreamsURL), "/*":
 			mockOpenSearchOperationResponse(false, w, r)
 		case secureSettingsURL:
@@ -275,6 +291,116 @@ func Test_EnsureOpenSearchIsHealthy(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+// roundTripperFunc is an http.RoundTripper implemented by a function
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// Test_EnsureOpenSearchIsReachablePollInterval tests the EnsureOpenSearchIsReachable method for the following use case.
+// GIVEN OpenSearch object with a health check poll interval much shorter than the Velero timeout
+// WHEN invoked while the cluster is not reachable for the first two checks
+// THEN the cluster is checked again after each poll interval until it is reachable
+func Test_EnsureOpenSearchIsReachablePollInterval(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	checks := 0
+	client := &http.Client{Transport: roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		checks++
+		if checks <= 2 {
+			return nil, fmt.Errorf("connection refused")
+		}
+		return http.DefaultTransport.RoundTrip(r)
+	})}
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "10m",
+	}
+	o := opensearch.New(httpServer.URL, timeOutGlobal, client, &conData, log, fakeBasicAuth)
+	o.HealthCheckSettings = types.HealthCheckSettings{PollInterval: 10 * time.Millisecond, Timeout: 5 * time.Second}
+
+	start := time.Now()
+	err := o.EnsureOpenSearchIsReachable()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, checks)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// Test_EnsureOpenSearchIsHealthyPollInterval tests the EnsureOpenSearchIsHealthy method for the following use case.
+// GIVEN OpenSearch object with a health check poll interval much shorter than the Velero timeout
+// WHEN invoked while the cluster health is red for the first two checks
+// THEN the cluster health is checked again after each poll interval until it is green
+func Test_EnsureOpenSearchIsHealthyPollInterval(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case "/":
+			mockEnsureOpenSearchIsReachable(false, w, r)
+		case healthURL:
+			checks++
+			mockEnsureOpenSearchIsHealthy(checks <= 2, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "10m",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.HealthCheckSettings = types.HealthCheckSettings{PollInterval: 10 * time.Millisecond, Timeout: 5 * time.Second}
+
+	start := time.Now()
+	err := o.EnsureOpenSearchIsHealthy()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, checks)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// Test_EnsureOpenSearchIsHealthyTimeout tests the EnsureOpenSearchIsHealthy method for the following use case.
+// GIVEN OpenSearch object with a health check timeout much shorter than the Velero timeout
+// WHEN invoked while the cluster health stays red
+// THEN the cluster health is checked at each poll interval, and an error is returned once the health check timeout is exceeded
+func Test_EnsureOpenSearchIsHealthyTimeout(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	checks := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case "/":
+			mockEnsureOpenSearchIsReachable(false, w, r)
+		case healthURL:
+			checks++
+			mockEnsureOpenSearchIsHealthy(true, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "10m",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.HealthCheckSettings = types.HealthCheckSettings{PollInterval: 10 * time.Millisecond, Timeout: 100 * time.Millisecond}
+
+	start := time.Now()
+	err := o.EnsureOpenSearchIsHealthy()
+	assert.ErrorContains(t, err, "Timeout '100ms' exceeded")
+	// one check to reach the health endpoint, then one check per poll interval until the timeout
+	assert.Equal(t, 12, checks)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
 // Test_EnsureOpenSearchIsHealthy tests the EnsureOpenSearchIsHealthy method for the following use case.
 // GIVEN OpenSearch object
 // WHEN invoked with snapshot name
@@ -285,6 +411,8 @@ func Test_RegisterSnapshotRepository(t *testing.T) {
 
 	server1 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch strings.TrimSpace(r.URL.Path) {
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
 		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName):
 			mockOpenSearchOperationResponse(false, w, r)
 		default:
@@ -313,6 +441,8 @@ func Test_RegisterSnapshotRepository(t *testing.T) {
 
 	server2 := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch strings.TrimSpace(r.URL.Path) {
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
 		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName):
 			mockOpenSearchOperationResponse(true, w, r)
 		default:
@@ -327,6 +457,197 @@ func Test_RegisterSnapshotRepository(t *testing.T) {
 
 }
 
+// Test_VerifySnapshotRepository tests the VerifySnapshotRepository method for the following use case.
+// GIVEN OpenSearch object with a reachable, then an unreachable snapshot repository
+// WHEN invoked
+// THEN no error is returned for the reachable repository, and an error is returned for the unreachable repository
+func Test_VerifySnapshotRepository(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	for _, unreachable := range []bool{false, true} {
+		verified := false
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			switch r.URL.Path {
+			case verifyURL:
+				verified = true
+				mockVerifySnapshotRepository(unreachable, w, r)
+			default:
+				http.NotFoundHandler().ServeHTTP(w, r)
+			}
+		}))
+
+		conData := types.ConnectionData{
+			BackupName:    "mango",
+			VeleroTimeout: "1s",
+		}
+		o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+		err := o.VerifySnapshotRepository()
+		server.Close()
+		assert.True(t, verified)
+		if unreachable {
+			assert.ErrorContains(t, err, "verification failed")
+		} else {
+			assert.Nil(t, err)
+		}
+	}
+}
+
+// Test_RegisterSnapshotRepositoryVerify tests the RegisterSnapshotRepository method for the following use case.
+// GIVEN OpenSearch object with an unreachable snapshot repository, with and without the verification skipped
+// WHEN invoked
+// THEN the registration fails once the repository is registered, unless the verification is skipped, in which case
+// the repository is registered with verify=false and is not verified
+func Test_RegisterSnapshotRepositoryVerify(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.RequestURI())
+		switch r.URL.Path {
+		case verifyURL:
+			mockVerifySnapshotRepository(true, w, r)
+		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName):
+			mockOpenSearchOperationResponse(false, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	err := o.RegisterSnapshotRepository()
+	assert.ErrorContains(t, err, "verification failed")
+	assert.Equal(t, []string{fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName), verifyURL}, requests)
+
+	requests = nil
+	o.RepositorySettings.SkipVerify = true
+	err = o.RegisterSnapshotRepository()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{fmt.Sprintf("%s/%s?verify=false", snapshotURL, constants.OpenSearchSnapShotRepoName)}, requests)
+}
+
+// Test_RegisterSnapshotRepositoryWithSettings tests the RegisterSnapshotRepository method for the following use case.
+// GIVEN OpenSearch object with repository settings
+// WHEN invoked
+// THEN the repository settings are part of the registration request
+func Test_RegisterSnapshotRepositoryWithSettings(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var payload types.OpenSearchSnapshotRequestPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
+		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName):
+			json.NewDecoder(r.Body).Decode(&payload)
+			mockOpenSearchOperationResponse(false, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.RepositorySettings = types.SnapshotRepositorySettings{
+		ChunkSize:              "1gb",
+		MaxSnapshotBytesPerSec: "40mb",
+		MaxRestoreBytesPerSec:  "80mb",
+	}
+	err := o.RegisterSnapshotRepository()
+	assert.Nil(t, err)
+	assert.Equal(t, "1gb", payload.Settings.ChunkSize)
+	assert.Equal(t, "40mb", payload.Settings.MaxSnapshotBytesPerSec)
+	assert.Equal(t, "80mb", payload.Settings.MaxRestoreBytesPerSec)
+	assert.Equal(t, "region", payload.Settings.Region)
+}
+
+// Test_RegisterFSSnapshotRepository tests the RegisterSnapshotRepository method for the following use case.
+// GIVEN OpenSearch object with a fs repository type and location
+// WHEN invoked
+// THEN a fs repository is registered with the location, without the s3 settings
+func Test_RegisterFSSnapshotRepository(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
+		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName):
+			json.NewDecoder(r.Body).Decode(&payload)
+			mockOpenSearchOperationResponse(false, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+		BucketName:    "bucket",
+	}
+
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.RepositorySettings = types.SnapshotRepositorySettings{
+		Type:      constants.FSSnapshotRepoType,
+		Location:  "/mnt/snapshots",
+		ChunkSize: "1gb",
+	}
+	err := o.RegisterSnapshotRepository()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"type": "fs",
+		"settings": map[string]interface{}{
+			"location":   "/mnt/snapshots",
+			"chunk_size": "1gb",
+		},
+	}, payload)
+}
+
+// Test_RegisterFSSnapshotRepositoryInvalid tests the RegisterSnapshotRepository method for the following use case.
+// GIVEN OpenSearch object with a fs repository type without a location, or with an unsupported repository type
+// WHEN invoked
+// THEN an error is returned without registering a repository
+func Test_RegisterFSSnapshotRepositoryInvalid(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+	}
+
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.RepositorySettings = types.SnapshotRepositorySettings{Type: constants.FSSnapshotRepoType}
+	assert.NotNil(t, o.RegisterSnapshotRepository())
+
+	o.RepositorySettings = types.SnapshotRepositorySettings{Type: "hdfs", Location: "/mnt/snapshots"}
+	assert.NotNil(t, o.RegisterSnapshotRepository())
+}
+
 // Test_ReloadOpensearchSecureSettings tests the ReloadOpensearchSecureSettings method for the following use case.
 // GIVEN OpenSearch object
 // WHEN invoked with snapshot name
@@ -409,6 +730,35 @@ func Test_TriggerSnapshot(t *testing.T) {
 
 }
 
+// Test_TriggerSnapshotName tests the TriggerSnapshot method for the following use case.
+// GIVEN OpenSearch object with a snapshot name
+// WHEN invoked
+// THEN the snapshot is created with the snapshot name instead of the Velero backup name
+func Test_TriggerSnapshotName(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case fmt.Sprintf("%s/%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName, "daily-2023-03-21"):
+			mockTriggerSnapshotRepository(false, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.SnapshotName = "daily-2023-03-21"
+	err := o.TriggerSnapshot()
+	assert.Nil(t, err)
+}
+
 // TestCheckSnapshotProgress tests the CheckSnapshotProgress method for the following use case.
 // GIVEN OpenSearch object
 // WHEN invoked with snapshot name
@@ -479,6 +829,57 @@ func Test_DeleteDataStreams(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+// Test_DeleteDataRestoreIndices tests the DeleteData method for the following use case.
+// GIVEN OpenSearch object restoring only some indices
+// WHEN invoked with logger
+// THEN only the data streams and indices which are restored are deleted, the other data streams and indices are kept
+func Test_DeleteDataRestoreIndices(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var deletes []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == dataStreamsURL:
+			w.Header().Add("Content-Type", constants.HTTPContentType)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data_streams": [{"name": "verrazzano-application-tenant-a"}, {"name": "verrazzano-application-tenant-b"}, {"name": "verrazzano-system"}, {"name": "other"}]}`))
+		case r.Method == http.MethodDelete:
+			deletes = append(deletes, r.URL.Path)
+			if !strings.HasPrefix(r.URL.Path, dataStreamsURL) {
+				assert.Equal(t, "true", r.URL.Query().Get("ignore_unavailable"))
+			}
+			mockOpenSearchOperationResponse(false, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.RestoreOptions.Indices = "verrazzano-application-*,-verrazzano-application-tenant-b"
+	err := o.DeleteData()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		dataStreamsURL + "/verrazzano-application-tenant-a",
+		"/verrazzano-application-*,-verrazzano-application-tenant-b",
+	}, deletes)
+	assert.NotContains(t, deletes, dataStreamsURL+"/*")
+	assert.NotContains(t, deletes, "/*")
+
+	// no data stream is restored, only the restored indices are deleted
+	deletes = nil
+	o.RestoreOptions.Indices = "verrazzano-logs-2023"
+	err = o.DeleteData()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"/verrazzano-logs-2023"}, deletes)
+}
+
 // Test_TriggerSnapshot tests the TriggerRestore method for the following use case.
 // GIVEN OpenSearch object
 // WHEN invoked with snapshot name
@@ -557,6 +958,108 @@ func Test_Backup(t *testing.T) {
 	assert.Nil(t, err)
 }
 
+// Test_BackupMaxConcurrentSnapshots tests the Backup method for the following use case.
+// GIVEN OpenSearch object allowing a single snapshot in progress, and a cluster with a snapshot of another backup in
+// progress until it is polled twice
+// WHEN invoked
+// THEN the snapshot is only taken once the snapshot of the other backup is complete
+func Test_BackupMaxConcurrentSnapshots(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var statusRequests int
+	var snapshotStatusRequests []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
+		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName):
+			mockOpenSearchOperationResponse(false, w, r)
+		case fmt.Sprintf("%s/_status", snapshotURL):
+			statusRequests++
+			w.Header().Add("Content-Type", constants.HTTPContentType)
+			if statusRequests <= 2 {
+				fmt.Fprint(w, `{"snapshots": [{"snapshot": "papaya", "state": "STARTED"}]}`)
+			} else {
+				fmt.Fprint(w, `{"snapshots": []}`)
+			}
+		case fmt.Sprintf("%s/%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName, "mango"):
+			if r.Method == http.MethodPost {
+				snapshotStatusRequests = append(snapshotStatusRequests, statusRequests)
+			}
+			mockTriggerSnapshotRepository(false, w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.MaxConcurrentSnapshots = 1
+	o.WaitSettings = types.WaitSettings{Min: 10 * time.Millisecond, Max: 10 * time.Millisecond}
+	err := o.Backup()
+	assert.Nil(t, err)
+	assert.Equal(t, []int{3}, snapshotStatusRequests)
+}
+
+// Test_WaitForSnapshotSlot tests the WaitForSnapshotSlot method for the following use case.
+// GIVEN OpenSearch object with different maximums of snapshots in progress, and a cluster with a snapshot in progress
+// WHEN invoked
+// THEN an error is returned once the timeout is exceeded, unless the maximum is not reached or the snapshots are not
+// limited, in which case the snapshots in progress are not checked
+func Test_WaitForSnapshotSlot(t *testing.T) {
+	var tests = []struct {
+		name                   string
+		maxConcurrentSnapshots int
+		statusChecked          bool
+		isError                bool
+	}{
+		{"not limited", 0, false, false},
+		{"below the maximum", 2, true, false},
+		{"maximum reached", 1, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log, f := logHelper()
+			defer os.Remove(f)
+
+			var statusRequests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch strings.TrimSpace(r.URL.Path) {
+				case fmt.Sprintf("%s/_status", snapshotURL):
+					statusRequests++
+					w.Header().Add("Content-Type", constants.HTTPContentType)
+					fmt.Fprint(w, `{"snapshots": [{"snapshot": "papaya", "state": "STARTED"}]}`)
+				default:
+					http.NotFoundHandler().ServeHTTP(w, r)
+				}
+			}))
+			defer server.Close()
+
+			conData := types.ConnectionData{
+				BackupName:    "mango",
+				VeleroTimeout: "50ms",
+				RegionName:    "region",
+			}
+			o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+			o.MaxConcurrentSnapshots = tt.maxConcurrentSnapshots
+			o.WaitSettings = types.WaitSettings{Min: 10 * time.Millisecond, Max: 10 * time.Millisecond}
+			err := o.WaitForSnapshotSlot()
+			if tt.isError {
+				assert.ErrorContains(t, err, "1 snapshots are still in progress")
+			} else {
+				assert.Nil(t, err)
+			}
+			assert.Equal(t, tt.statusChecked, statusRequests > 0)
+		})
+	}
+}
+
 // Test_Restore tests the Restore method for the following use case.
 // GIVEN OpenSearch object
 // WHEN invoked with snapshot name
@@ -565,3 +1068,313 @@ func Test_Restore(t *testing.T) {
 	err := openSearch.Restore()
 	assert.Nil(t, err)
 }
+
+// Test_TestRestore tests the TestRestore method for the following use case.
+// GIVEN OpenSearch object
+// WHEN invoked with snapshot name
+// THEN restores the snapshot into renamed indices without deleting existing data
+func Test_TestRestore(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var restoreBody map[string]interface{}
+	dataDeleted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
+		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName):
+			mockOpenSearchOperationResponse(false, w, r)
+		case fmt.Sprintf("%s/*", dataStreamsURL), "/*":
+			dataDeleted = true
+			mockOpenSearchOperationResponse(false, w, r)
+		case fmt.Sprintf("%s/%s/%s/_restore", snapshotURL, constants.OpenSearchSnapShotRepoName, "mango"):
+			json.NewDecoder(r.Body).Decode(&restoreBody)
+			mockTriggerSnapshotRepository(false, w, r)
+		case dataStreamsURL:
+			mockRestoreProgress(w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	err := o.TestRestore()
+	assert.Nil(t, err)
+	assert.False(t, dataDeleted)
+	assert.Equal(t, "(.+)", restoreBody["rename_pattern"])
+	assert.Equal(t, constants.TestRestoreIndexPrefix+"$1", restoreBody["rename_replacement"])
+	assert.Equal(t, false, restoreBody["include_aliases"])
+}
+
+// Test_RestoreRecoverySettings tests the Restore method for the following use case.
+// GIVEN OpenSearch object with recovery settings
+// WHEN invoked with snapshot name
+// THEN the transient recovery settings are applied before the restore is triggered and reset afterwards
+func Test_RestoreRecoverySettings(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var settingsUpdates []types.OpenSearchClusterSettingsPayload
+	restoreTriggered := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSpace(r.URL.Path) {
+		case "/_cluster/settings":
+			var payload types.OpenSearchClusterSettingsPayload
+			json.NewDecoder(r.Body).Decode(&payload)
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, len(settingsUpdates) > 0, restoreTriggered)
+			settingsUpdates = append(settingsUpdates, payload)
+			mockOpenSearchOperationResponse(false, w, r)
+		case verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
+		case fmt.Sprintf("%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName), fmt.Sprintf("%s/*", dataStreamsURL), "/*":
+			mockOpenSearchOperationResponse(false, w, r)
+		case fmt.Sprintf("%s/%s/%s/_restore", snapshotURL, constants.OpenSearchSnapShotRepoName, "mango"):
+			restoreTriggered = true
+			mockTriggerSnapshotRepository(false, w, r)
+		case dataStreamsURL:
+			mockRestoreProgress(w, r)
+		default:
+			http.NotFoundHandler().ServeHTTP(w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.RecoverySettings = types.RestoreRecoverySettings{
+		MaxBytesPerSec:           "40mb",
+		NodeConcurrentRecoveries: 2,
+		MaxConcurrentRecoveries:  8,
+	}
+	err := o.Restore()
+	assert.Nil(t, err)
+	assert.True(t, restoreTriggered)
+	assert.Equal(t, 2, len(settingsUpdates))
+	assert.Equal(t, map[string]interface{}{
+		constants.RecoveryMaxBytesPerSecSetting:         "40mb",
+		constants.NodeConcurrentRecoveriesSetting:       float64(2),
+		constants.NodeInitialPrimariesRecoveriesSetting: float64(8),
+	}, settingsUpdates[0].Transient)
+	assert.Equal(t, map[string]interface{}{
+		constants.RecoveryMaxBytesPerSecSetting:         nil,
+		constants.NodeConcurrentRecoveriesSetting:       nil,
+		constants.NodeInitialPrimariesRecoveriesSetting: nil,
+	}, settingsUpdates[1].Transient)
+	// the recovery settings only apply to the restore, they are never persisted
+	assert.Nil(t, settingsUpdates[0].Persistent)
+	assert.Nil(t, settingsUpdates[1].Persistent)
+}
+
+// Test_TriggerRestoreOptions tests the TriggerRestore method for the following use case.
+// GIVEN OpenSearch object with and without restore options
+// WHEN invoked with snapshot name
+// THEN the restore request restores the given indices and aliases, and never the security index
+func Test_TriggerRestoreOptions(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var restoreBody map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		restoreBody = map[string]interface{}{}
+		json.NewDecoder(r.Body).Decode(&restoreBody)
+		mockTriggerSnapshotRepository(false, w, r)
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	assert.Nil(t, o.TriggerRestore())
+	assert.Equal(t, constants.OpenSearchSecurityIndexExclusion, restoreBody["indices"])
+	assert.NotContains(t, restoreBody, "include_aliases")
+
+	includeAliases := false
+	o.RestoreOptions = types.RestoreOptions{
+		Indices:        "verrazzano-system,verrazzano-application-*",
+		IncludeAliases: &includeAliases,
+	}
+	assert.Nil(t, o.TriggerRestore())
+	assert.Equal(t, "verrazzano-system,verrazzano-application-*,"+constants.OpenSearchSecurityIndexExclusion, restoreBody["indices"])
+	assert.Equal(t, false, restoreBody["include_aliases"])
+}
+
+// Test_RestoreIndexSettings tests the Restore method for the following use case.
+// GIVEN OpenSearch object with restore index settings, and a snapshot of indices with their own replicas and refresh
+// intervals
+// WHEN invoked with snapshot name
+// THEN the refresh interval and replicas of the restored indices are overridden once the restore is triggered, and the
+// settings the restored indices have in the snapshot are put back on the restored indices only once the restore is done
+func Test_RestoreIndexSettings(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	snapshotSettings := map[string]map[string]string{
+		"verrazzano-system": {
+			constants.IndexRefreshIntervalSetting:  "30s",
+			constants.IndexNumberOfReplicasSetting: "2",
+		},
+		".ds-verrazzano-application-tenant-a-000001": {
+			constants.IndexNumberOfReplicasSetting: "1",
+		},
+		".kibana_1": {
+			constants.IndexNumberOfReplicasSetting: "0",
+		},
+	}
+	prefix := ""
+	overrides := map[string]interface{}{
+		constants.IndexRefreshIntervalSetting:  "-1",
+		constants.IndexNumberOfReplicasSetting: float64(0),
+	}
+	restoreTriggered := false
+	var indexSettings map[string]map[string]interface{}
+	var settingsUpdates int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Path, "/_settings"):
+			assert.True(t, restoreTriggered, "index settings read or updated before the restore was triggered")
+			assert.Empty(t, r.URL.Query().Get("expand_wildcards"))
+			indices := strings.Split(strings.TrimPrefix(r.URL.Path[:strings.Index(r.URL.Path, "/_settings")], "/"), ",")
+			if r.Method == http.MethodGet {
+				response := map[string]interface{}{}
+				for _, index := range indices {
+					assert.True(t, strings.HasPrefix(index, prefix), index)
+					response[index] = map[string]interface{}{"settings": snapshotSettings[strings.TrimPrefix(index, prefix)]}
+				}
+				w.WriteHeader(http.StatusOK)
+				json.NewEncoder(w).Encode(response)
+				return
+			}
+			assert.Equal(t, http.MethodPut, r.Method)
+			settingsUpdates++
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			for _, index := range indices {
+				indexSettings[index] = payload
+			}
+			mockOpenSearchOperationResponse(false, w, r)
+		case r.URL.Path == verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
+		case r.URL.Path == fmt.Sprintf("%s/%s/%s", snapshotURL, constants.OpenSearchSnapShotRepoName, "mango"):
+			assert.Equal(t, http.MethodGet, r.Method)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"snapshots": [{"snapshot": "mango", "indices": ["verrazzano-system", ".ds-verrazzano-application-tenant-a-000001", ".kibana_1", ".opendistro_security"], "data_streams": ["verrazzano-application-tenant-a"], "state": "SUCCESS"}]}`))
+		case r.URL.Path == fmt.Sprintf("%s/%s/%s/_restore", snapshotURL, constants.OpenSearchSnapShotRepoName, "mango"):
+			var restoreBody map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&restoreBody)
+			assert.NotContains(t, restoreBody, "index_settings")
+			restoreTriggered = true
+			mockTriggerSnapshotRepository(false, w, r)
+		case r.URL.Path == dataStreamsURL:
+			// the settings of the restored indices are overridden while restoring
+			assert.NotEmpty(t, indexSettings)
+			for index := range indexSettings {
+				assert.Equal(t, overrides, indexSettings[index], index)
+			}
+			mockRestoreProgress(w, r)
+		default:
+			mockOpenSearchOperationResponse(false, w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	noReplicas := 0
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	o.RestoreIndexSettings = types.RestoreIndexSettings{
+		RefreshInterval:  "-1",
+		NumberOfReplicas: &noReplicas,
+	}
+	indexSettings = map[string]map[string]interface{}{}
+	err := o.Restore()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]interface{}{
+		"verrazzano-system": {
+			constants.IndexRefreshIntervalSetting:  "30s",
+			constants.IndexNumberOfReplicasSetting: "2",
+		},
+		".ds-verrazzano-application-tenant-a-000001": {
+			constants.IndexRefreshIntervalSetting:  nil,
+			constants.IndexNumberOfReplicasSetting: "1",
+		},
+		".kibana_1": {
+			constants.IndexRefreshIntervalSetting:  nil,
+			constants.IndexNumberOfReplicasSetting: "0",
+		},
+	}, indexSettings)
+	// one update to override the settings, one update per distinct snapshot settings to put them back
+	assert.Equal(t, 4, settingsUpdates)
+
+	// only the restored indices, renamed for the test restore, are updated
+	prefix = constants.TestRestoreIndexPrefix
+	restoreTriggered = false
+	indexSettings = map[string]map[string]interface{}{}
+	overrides = map[string]interface{}{constants.IndexNumberOfReplicasSetting: float64(0)}
+	o.RestoreIndexSettings.RefreshInterval = ""
+	o.RestoreOptions.Indices = "verrazzano-application-*"
+	err = o.TestRestore()
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]map[string]interface{}{
+		prefix + ".ds-verrazzano-application-tenant-a-000001": {
+			constants.IndexNumberOfReplicasSetting: "1",
+		},
+	}, indexSettings)
+}
+
+// Test_RestoreIndexSettingsNotConfigured tests the Restore method for the following use case.
+// GIVEN OpenSearch object without restore index settings
+// WHEN invoked with snapshot name
+// THEN the restore request does not override the index settings and the restored indices are not updated
+func Test_RestoreIndexSettingsNotConfigured(t *testing.T) {
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	var restoreBody map[string]interface{}
+	settingsUpdated := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == verifyURL:
+			mockVerifySnapshotRepository(false, w, r)
+		case strings.HasSuffix(r.URL.Path, "/_settings"):
+			settingsUpdated = true
+			mockOpenSearchOperationResponse(false, w, r)
+		case r.URL.Path == fmt.Sprintf("%s/%s/%s/_restore", snapshotURL, constants.OpenSearchSnapShotRepoName, "mango"):
+			json.NewDecoder(r.Body).Decode(&restoreBody)
+			mockTriggerSnapshotRepository(false, w, r)
+		case r.URL.Path == dataStreamsURL:
+			mockRestoreProgress(w, r)
+		default:
+			mockOpenSearchOperationResponse(false, w, r)
+		}
+	}))
+	defer server.Close()
+
+	conData := types.ConnectionData{
+		BackupName:    "mango",
+		VeleroTimeout: "1s",
+		RegionName:    "region",
+	}
+	o := opensearch.New(server.URL, timeOutGlobal, http.DefaultClient, &conData, log, fakeBasicAuth)
+	err := o.Restore()
+	assert.Nil(t, err)
+	assert.NotContains(t, restoreBody, "index_settings")
+	assert.False(t, settingsUpdated)
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package types
@@ -74,6 +74,7 @@ type VeleroBackup struct {
 			VeleroIoSourceClusterK8SGitversion          string `json:"velero.io/source-cluster-k8s-gitversion"`
 			VeleroIoSourceClusterK8SMajorVersion        string `json:"velero.io/source-cluster-k8s-major-version"`
 			VeleroIoSourceClusterK8SMinorVersion        string `json:"velero.io/source-cluster-k8s-minor-version"`
+			VerrazzanoIoOpenSearchSnapshotName          string `json:"verrazzano.io/opensearch-snapshot-name"`
 		} `json:"annotations"`
 		CreationTimestamp time.Time `json:"creationTimestamp"`
 		Generation        int       `json:"generation"`
@@ -148,19 +149,108 @@ type OpenSearchHealthResponse struct {
 type OpenSearchSnapshotRequestPayload struct {
 	Type     string `json:"type"`
 	Settings struct {
-		Client          string `json:"client"`
-		Bucket          string `json:"bucket"`
-		Region          string `json:"region"`
-		Endpoint        string `json:"endpoint"`
-		PathStyleAccess bool   `json:"path_style_access"`
+		Client                 string `json:"client,omitempty"`
+		Bucket                 string `json:"bucket,omitempty"`
+		Region                 string `json:"region,omitempty"`
+		Endpoint               string `json:"endpoint,omitempty"`
+		PathStyleAccess        bool   `json:"path_style_access,omitempty"`
+		Location               string `json:"location,omitempty"`
+		ChunkSize              string `json:"chunk_size,omitempty"`
+		MaxSnapshotBytesPerSec string `json:"max_snapshot_bytes_per_sec,omitempty"`
+		MaxRestoreBytesPerSec  string `json:"max_restore_bytes_per_sec,omitempty"`
 	} `json:"settings"`
 }
 
+// SnapshotRepositorySettings optional tuning settings applied when registering the snapshot repository
+type SnapshotRepositorySettings struct {
+	// Type of the snapshot repository, s3 by default
+	Type string `json:"type,omitempty"`
+	// Location of the snapshots of a fs snapshot repository, which must be listed in the path.repo of the OpenSearch nodes
+	Location               string `json:"location,omitempty"`
+	ChunkSize              string `json:"chunk_size,omitempty"`
+	MaxSnapshotBytesPerSec string `json:"max_snapshot_bytes_per_sec,omitempty"`
+	MaxRestoreBytesPerSec  string `json:"max_restore_bytes_per_sec,omitempty"`
+	// SkipVerify if set, the snapshot repository is registered with verify=false and is not verified afterwards
+	SkipVerify bool `json:"-"`
+}
+
+// RestoreRecoverySettings optional cluster recovery settings applied while a restore is in progress
+type RestoreRecoverySettings struct {
+	MaxBytesPerSec           string
+	NodeConcurrentRecoveries int
+	MaxConcurrentRecoveries  int
+}
+
+// RestoreIndexSettings optional settings of the restored indices applied while a restore is in progress, to speed up
+// the ingestion of the restored shards. The settings the restored indices have in the snapshot are put back once the
+// restore is done
+type RestoreIndexSettings struct {
+	// RefreshInterval refresh interval of the restored indices while restoring, -1 disables the refreshes. The snapshot
+	// value if empty
+	RefreshInterval string
+	// NumberOfReplicas number of replicas of the restored indices while restoring, the snapshot value if nil
+	NumberOfReplicas *int
+}
+
+// OpenSearchIndexSettings the flat settings of indices, keyed by index name
+type OpenSearchIndexSettings map[string]struct {
+	Settings map[string]string `json:"settings"`
+}
+
+// HealthCheckSettings optional settings of the OpenSearch reachability and health checks
+type HealthCheckSettings struct {
+	// PollInterval the interval between two checks, a random interval if zero
+	PollInterval time.Duration
+	// Timeout the overall time to wait for a successful check, the Velero timeout if zero
+	Timeout time.Duration
+}
+
+// NotificationPayload JSON payload posted to the notification webhook on completion of a backup or restore
+type NotificationPayload struct {
+	Operation    string `json:"operation"`
+	Status       string `json:"status"`
+	SnapshotName string `json:"snapshotName"`
+	Duration     string `json:"duration"`
+	Cluster      string `json:"cluster,omitempty"`
+	Error        string `json:"error,omitempty"`
+	// Text the summary of the notification, the only field displayed by Slack and Teams incoming webhooks
+	Text string `json:"text"`
+}
+
+// WaitSettings bounds of the random waits between two retries
+type WaitSettings struct {
+	// Min the shortest wait, the default minimum if both bounds are zero
+	Min time.Duration
+	// Max the longest wait, the default maximum if both bounds are zero
+	Max time.Duration
+}
+
+// RestoreOptions optional options of the snapshot restore request
+type RestoreOptions struct {
+	// Indices comma separated list of the indices and data streams to restore, all of them if empty
+	Indices string
+	// IncludeAliases whether to restore the aliases, the OpenSearch default if nil
+	IncludeAliases *bool
+}
+
+// OpenSearchClusterSettingsPayload struct for updating cluster settings
+type OpenSearchClusterSettingsPayload struct {
+	Persistent map[string]interface{} `json:"persistent,omitempty"`
+	Transient  map[string]interface{} `json:"transient,omitempty"`
+}
+
 // OpenSearchOperationResponse to render common operational responses
 type OpenSearchOperationResponse struct {
 	Acknowledged bool `json:"acknowledged,omitempty"`
 }
 
+// OpenSearchVerifyRepositoryResponse to render the nodes which verified a snapshot repository
+type OpenSearchVerifyRepositoryResponse struct {
+	Nodes map[string]struct {
+		Name string `json:"name"`
+	} `json:"nodes"`
+}
+
 // OpenSearchSnapshotResponse to render snapshot response
 type OpenSearchSnapshotResponse struct {
 	Accepted bool `json:"accepted,omitempty"`
@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package utilities
@@ -9,13 +9,32 @@ import (
 	"fmt"
 	"github.com/spf13/viper"
 	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/types"
 	"go.uber.org/zap"
 	"math/big"
 	"os"
+	"regexp"
 	"strings"
+	"text/template"
 	"time"
 )
 
+var byteSizeRegex = regexp.MustCompile(`^[0-9]+(b|kb|mb|gb|tb|pb)$`)
+
+var refreshIntervalRegex = regexp.MustCompile(`^(-1|[0-9]+(d|h|m|s|ms|micros|nanos))$`)
+
+var snapshotNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// snapshotNameData the values available to the snapshot name template
+type snapshotNameData struct {
+	// Date the UTC date, e.g. 2023-03-21
+	Date string
+	// Time the UTC time, e.g. 153045
+	Time string
+	// BackupName the Velero backup name
+	BackupName string
+}
+
 // CreateTempFileWithData used to create temp cloud-creds utilized for object store access
 func CreateTempFileWithData(data []byte) (string, error) {
 	file, err := os.CreateTemp(os.TempDir(), "cloud-creds-*.ini")
@@ -30,27 +49,121 @@ func CreateTempFileWithData(data []byte) (string, error) {
 	return file.Name(), nil
 }
 
-// WaitRandom generates a random number between min and max
-func WaitRandom(message, timeout string, log *zap.SugaredLogger) (int, error) {
-	randomBig, err := rand.Int(rand.Reader, big.NewInt(constants.Max))
-	if err != nil {
-		return 0, fmt.Errorf("Unable to generate random number %v", zap.Error(err))
+// ValidateWaitSettings validates that the bounds of the random waits are not negative and that the minimum does not
+// exceed the maximum
+func ValidateWaitSettings(settings types.WaitSettings) error {
+	if settings.Min < 0 || settings.Max < 0 {
+		return fmt.Errorf("Retry wait bounds cannot be negative")
+	}
+	if settings.Min > settings.Max {
+		return fmt.Errorf("Minimum retry wait '%v' cannot exceed the maximum retry wait '%v'", settings.Min, settings.Max)
+	}
+	return nil
+}
+
+// RandomWaitDuration generates a crypto safe random duration, evenly distributed between the min and max of the
+// wait settings, and capped at the timeout. The default bounds are used if neither bound is set.
+func RandomWaitDuration(settings types.WaitSettings, timeout time.Duration) (time.Duration, error) {
+	if settings.Min == 0 && settings.Max == 0 {
+		settings = types.WaitSettings{Min: constants.Min * time.Second, Max: constants.Max * time.Second}
 	}
-	randomInt := int(randomBig.Int64())
-	if randomInt < constants.Min {
-		randomInt = (constants.Min + constants.Max) / 2
+	if err := ValidateWaitSettings(settings); err != nil {
+		return 0, err
 	}
+	duration := settings.Min
+	if jitter := settings.Max - settings.Min; jitter > 0 {
+		randomBig, err := rand.Int(rand.Reader, big.NewInt(int64(jitter)+1))
+		if err != nil {
+			return 0, fmt.Errorf("Unable to generate random number %v", zap.Error(err))
+		}
+		duration += time.Duration(randomBig.Int64())
+	}
+	// handle timeouts lesser that generated min!
+	if duration > timeout {
+		duration = timeout
+	}
+	return duration, nil
+}
+
+// WaitRandom waits for a random duration within the wait settings, capped at the timeout, and returns the time waited
+func WaitRandom(message, timeout string, settings types.WaitSettings, log *zap.SugaredLogger) (time.Duration, error) {
 	timeParse, err := time.ParseDuration(timeout)
 	if err != nil {
 		return 0, fmt.Errorf("Unable to parse time duration %v", zap.Error(err))
 	}
-	// handle timeouts lesser that generated min!
-	if float64(randomInt) > timeParse.Seconds() {
-		randomInt = int(timeParse.Seconds())
+	duration, err := RandomWaitDuration(settings, timeParse)
+	if err != nil {
+		return 0, err
+	}
+	log.Infof("%v . Wait for '%v' ...", message, duration.Round(time.Millisecond))
+	time.Sleep(duration)
+	return duration, nil
+}
+
+// ValidateByteSize validates that the value is a byte size or byte rate understood by OpenSearch, e.g. 1gb, 40mb.
+// An empty value is considered valid, as it means the OpenSearch default is used.
+func ValidateByteSize(value string) error {
+	if value == "" {
+		return nil
+	}
+	if !byteSizeRegex.MatchString(value) {
+		return fmt.Errorf("Invalid byte size '%s'. It has to be a number followed by one of b, kb, mb, gb, tb or pb", value)
+	}
+	return nil
+}
+
+// ValidateRefreshInterval validates that the value is an index refresh interval understood by OpenSearch, e.g. 30s,
+// or -1 to disable the refreshes. An empty value is considered valid, as it means the refresh interval is not changed.
+func ValidateRefreshInterval(value string) error {
+	if value == "" {
+		return nil
+	}
+	if !refreshIntervalRegex.MatchString(value) {
+		return fmt.Errorf("Invalid refresh interval '%s'. It has to be -1 or a number followed by one of d, h, m, s, ms, micros or nanos", value)
+	}
+	return nil
+}
+
+// RenderSnapshotName renders the snapshot name Go template of a backup, e.g. daily-{{.Date}}, at the given time.
+// The Velero backup name is returned if the template is empty.
+func RenderSnapshotName(nameTemplate, backupName string, now time.Time) (string, error) {
+	if nameTemplate == "" {
+		return backupName, nil
+	}
+	tmpl, err := template.New("snapshot-name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return "", fmt.Errorf("Invalid snapshot name template '%s': %v", nameTemplate, err)
+	}
+	now = now.UTC()
+	data := snapshotNameData{
+		Date:       now.Format("2006-01-02"),
+		Time:       now.Format("150405"),
+		BackupName: backupName,
+	}
+	var name strings.Builder
+	if err := tmpl.Execute(&name, data); err != nil {
+		return "", fmt.Errorf("Unable to render snapshot name template '%s': %v", nameTemplate, err)
+	}
+	if !snapshotNameRegex.MatchString(name.String()) {
+		return "", fmt.Errorf("Invalid snapshot name '%s'. It has to start with a lowercase letter or a digit, followed by lowercase letters, digits, '.', '_' or '-'", name.String())
+	}
+	return name.String(), nil
+}
+
+// RestoreSnapshotName returns the name of the snapshot to restore, which has to be the literal name of the snapshot
+// taken by the backup, e.g. daily-2023-03-21, as a template rendered at restore time would name another snapshot.
+// The Velero backup name is returned if the name is empty.
+func RestoreSnapshotName(name, backupName string) (string, error) {
+	if name == "" {
+		return backupName, nil
+	}
+	if strings.Contains(name, "{{") {
+		return "", fmt.Errorf("Snapshot name '%s' is a template. The snapshot name of a restore has to be the name of the snapshot taken by the backup, e.g. daily-2023-03-21", name)
+	}
+	if !snapshotNameRegex.MatchString(name) {
+		return "", fmt.Errorf("Invalid snapshot name '%s'. It has to start with a lowercase letter or a digit, followed by lowercase letters, digits, '.', '_' or '-'", name)
 	}
-	log.Infof("%v . Wait for '%v' seconds ...", message, randomInt)
-	time.Sleep(time.Second * time.Duration(randomInt))
-	return randomInt, nil
+	return name, nil
 }
 
 // ReadTempCredsFile reads object store credentials from a temporary file for registration purpose
@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package utilities_test
@@ -8,11 +8,13 @@ import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/log"
+	model "github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/types"
 	utils "github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/utilities"
 	"go.uber.org/zap"
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func logHelper() (*zap.SugaredLogger, string) {
@@ -49,8 +51,61 @@ func TestWaitRandom(t *testing.T) {
 	log, fname := logHelper()
 	defer os.Remove(fname)
 	message := "Waiting for Verrazzano Monitoring Operator to come up"
-	_, err := utils.WaitRandom(message, "1s", log)
+	duration, err := utils.WaitRandom(message, "1s", model.WaitSettings{}, log)
 	assert.Nil(t, err)
+	assert.Equal(t, time.Second, duration)
+	duration, err = utils.WaitRandom(message, "1m", model.WaitSettings{Min: 10 * time.Millisecond, Max: 20 * time.Millisecond}, log)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, duration, 10*time.Millisecond)
+	assert.LessOrEqual(t, duration, 20*time.Millisecond)
+	_, err = utils.WaitRandom(message, "1s", model.WaitSettings{Min: time.Second, Max: time.Millisecond}, log)
+	assert.NotNil(t, err)
+}
+
+// TestRandomWaitDuration tests the RandomWaitDuration method
+// GIVEN min and max limits and a timeout
+// WHEN random wait durations are generated
+// THEN the durations stay within the limits, are spread between them and are capped at the timeout
+func TestRandomWaitDuration(t *testing.T) {
+	t.Parallel()
+	settings := model.WaitSettings{Min: 2 * time.Second, Max: 5 * time.Second}
+	distinct := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		duration, err := utils.RandomWaitDuration(settings, time.Minute)
+		assert.Nil(t, err)
+		assert.GreaterOrEqual(t, duration, settings.Min)
+		assert.LessOrEqual(t, duration, settings.Max)
+		distinct[duration] = true
+	}
+	assert.Greater(t, len(distinct), 1)
+
+	// the default bounds
+	duration, err := utils.RandomWaitDuration(model.WaitSettings{}, time.Minute)
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, duration, 10*time.Second)
+	assert.LessOrEqual(t, duration, 25*time.Second)
+
+	// equal bounds are a fixed wait
+	duration, err = utils.RandomWaitDuration(model.WaitSettings{Min: 3 * time.Second, Max: 3 * time.Second}, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, 3*time.Second, duration)
+
+	// capped at the timeout
+	duration, err = utils.RandomWaitDuration(settings, time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, duration)
+}
+
+// TestValidateWaitSettings tests the ValidateWaitSettings method
+// GIVEN min and max limits
+// WHEN the limits are validated
+// THEN an error is returned for negative limits or a min exceeding the max
+func TestValidateWaitSettings(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, utils.ValidateWaitSettings(model.WaitSettings{}))
+	assert.Nil(t, utils.ValidateWaitSettings(model.WaitSettings{Min: time.Second, Max: time.Second}))
+	assert.NotNil(t, utils.ValidateWaitSettings(model.WaitSettings{Min: -time.Second, Max: time.Second}))
+	assert.NotNil(t, utils.ValidateWaitSettings(model.WaitSettings{Min: 2 * time.Second, Max: time.Second}))
 }
 
 // TestReadTempCredsFile tests the ReadTempCredsFile method for the following use case.
@@ -102,3 +157,93 @@ func TestGetComponent(t *testing.T) {
 	assert.NotNil(t, err)
 
 }
+
+// TestValidateByteSize tests the ValidateByteSize method for the following use case.
+// GIVEN a byte size or byte rate string
+// WHEN the value is validated
+// THEN an error is returned only for invalid formats
+func TestValidateByteSize(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{"", "1gb", "40mb", "512b", "10kb"} {
+		assert.Nil(t, utils.ValidateByteSize(value), value)
+	}
+	for _, value := range []string{"40", "mb", "40 mb", "1.5gb", "-1gb", "40MB"} {
+		assert.NotNil(t, utils.ValidateByteSize(value), value)
+	}
+}
+
+// TestValidateRefreshInterval tests the ValidateRefreshInterval method for the following use case.
+// GIVEN an index refresh interval string
+// WHEN the value is validated
+// THEN an error is returned only for invalid formats
+func TestValidateRefreshInterval(t *testing.T) {
+	t.Parallel()
+	for _, value := range []string{"", "-1", "1s", "30s", "500ms", "5m"} {
+		assert.Nil(t, utils.ValidateRefreshInterval(value), value)
+	}
+	for _, value := range []string{"30", "s", "-2", "-1s", "1.5s", "30S"} {
+		assert.NotNil(t, utils.ValidateRefreshInterval(value), value)
+	}
+}
+
+// TestRenderSnapshotName tests the RenderSnapshotName method for the following use case.
+// GIVEN a snapshot name template
+// WHEN the template is rendered at a given time
+// THEN the date and time tokens are replaced, and the Velero backup name is returned for an empty template
+func TestRenderSnapshotName(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2023, time.March, 21, 15, 30, 45, 0, time.UTC)
+	tests := map[string]string{
+		"":                             "mango",
+		"daily-{{.Date}}":              "daily-2023-03-21",
+		"daily-{{.Date}}-{{.Time}}":    "daily-2023-03-21-153045",
+		"{{.BackupName}}.{{.Date}}":    "mango.2023-03-21",
+		"weekly":                       "weekly",
+		`{{.Date | printf "%.7s"}}-01`: "2023-03-01",
+	}
+	for nameTemplate, expected := range tests {
+		name, err := utils.RenderSnapshotName(nameTemplate, "mango", now)
+		assert.Nil(t, err, nameTemplate)
+		assert.Equal(t, expected, name, nameTemplate)
+	}
+
+	// the date is rendered in UTC
+	name, err := utils.RenderSnapshotName("daily-{{.Date}}", "mango", time.Date(2023, time.March, 22, 1, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)))
+	assert.Nil(t, err)
+	assert.Equal(t, "daily-2023-03-21", name)
+}
+
+// TestRenderSnapshotNameInvalid tests the RenderSnapshotName method for the following use case.
+// GIVEN an invalid snapshot name template, or a template rendering an invalid snapshot name
+// WHEN the template is rendered
+// THEN an error is returned
+func TestRenderSnapshotNameInvalid(t *testing.T) {
+	t.Parallel()
+	for _, nameTemplate := range []string{"daily-{{.Date", "daily-{{.Week}}", "Daily-{{.Date}}", "daily {{.Date}}", "_daily", "daily/{{.Date}}"} {
+		_, err := utils.RenderSnapshotName(nameTemplate, "mango", time.Now())
+		assert.NotNil(t, err, nameTemplate)
+	}
+}
+
+// TestRestoreSnapshotName tests the RestoreSnapshotName method for the following use case.
+// GIVEN the snapshot name of a restore
+// WHEN the snapshot name is resolved
+// THEN a literal snapshot name is returned, the Velero backup name is returned for an empty name, and an error is
+// returned for a template or an invalid name
+func TestRestoreSnapshotName(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"":                 "mango",
+		"daily-2023-03-21": "daily-2023-03-21",
+		"weekly":           "weekly",
+	}
+	for name, expected := range tests {
+		snapshotName, err := utils.RestoreSnapshotName(name, "mango")
+		assert.Nil(t, err, name)
+		assert.Equal(t, expected, snapshotName, name)
+	}
+	for _, name := range []string{"daily-{{.Date}}", "{{.BackupName}}", "Daily", "daily/2023-03-21"} {
+		_, err := utils.RestoreSnapshotName(name, "mango")
+		assert.NotNil(t, err, name)
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2022, Oracle and/or its affiliates.
+// Copyright (c) 2022, 2023, Oracle and/or its affiliates.
 // Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
 
 package k8s
@@ -17,8 +17,10 @@ import (
 
 type K8s interface {
 	PopulateConnData(veleroNamespace, backupName string) (*model.ConnectionData, error)
+	PopulateFSConnData(veleroNamespace, backupName string) (*model.ConnectionData, error)
 	GetObjectStoreCreds(secretName, namespace, secretKey string) (*model.ObjectStoreSecret, error)
 	GetBackup(veleroNamespace, backupName string) (*model.VeleroBackup, error)
+	AnnotateBackup(veleroNamespace, backupName, key, value string) error
 	GetBackupStorageLocation(veleroNamespace, bslName string) (*model.VeleroBackupStorageLocation, error)
 	ScaleDeployment(labelSelector, namespace, deploymentName string, replicaCount int32, log *zap.SugaredLogger) error
 	CheckDeployment(labelSelector, namespace string) (bool, error)
@@ -37,6 +39,8 @@ type K8sImpl struct {
 	K8sConfig           *rest.Config
 	CredentialProfile   string //default value `default`
 	Log                 *zap.SugaredLogger
+	// WaitSettings optional bounds of the random waits between retries
+	WaitSettings model.WaitSettings
 }
 
 func New(dclient dynamic.Interface, kclient client.Client, kclientInterface kubernetes.Interface, cfg *rest.Config, credentialProfile string, log *zap.SugaredLogger) *K8sImpl {
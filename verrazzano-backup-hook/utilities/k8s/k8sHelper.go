@@ -70,6 +70,24 @@ func (k *K8sImpl) PopulateConnData(veleroNamespace, backupName string) (*model.C
 
 }
 
+// PopulateFSConnData creates the connection object of a fs snapshot repository, which only needs the backup name and
+// the Velero timeout of the backup, and neither the backup storage location nor its object store credentials.
+func (k *K8sImpl) PopulateFSConnData(veleroNamespace, backupName string) (*model.ConnectionData, error) {
+	k.Log.Infof("Populating fs connection data from backup '%v' in namespace '%s'", backupName, veleroNamespace)
+
+	backup, err := k.GetBackup(veleroNamespace, backupName)
+	if err != nil {
+		return nil, err
+	}
+
+	var conData model.ConnectionData
+	conData.BackupName = backupName
+	// For now, we will look at the first POST hook in the first Hook in Velero Backup
+	conData.VeleroTimeout = backup.Spec.Hooks.Resources[0].Post[0].Exec.Timeout
+
+	return &conData, nil
+}
+
 // GetObjectStoreCreds fetches credentials from Velero Backup object store location.
 // This object will be pre-created before the execution of this hook
 func (k *K8sImpl) GetObjectStoreCreds(secretName, namespace, secretKey string) (*model.ObjectStoreSecret, error) {
@@ -159,6 +177,26 @@ func (k *K8sImpl) GetBackup(veleroNamespace, backupName string) (*model.VeleroBa
 	return &backup, nil
 }
 
+// AnnotateBackup sets an annotation of a Velero backup, e.g. to record the name of the snapshot taken by the backup
+func (k *K8sImpl) AnnotateBackup(veleroNamespace, backupName, key, value string) error {
+	k.Log.Infof("Annotating Velero backup '%s' in namespace '%s' with '%s=%s'", backupName, veleroNamespace, key, value)
+	gvr := schema.GroupVersionResource{
+		Group:    "velero.io",
+		Version:  "v1",
+		Resource: "backups",
+	}
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{key: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = k.DynamicK8sInterface.Resource(gvr).Namespace(veleroNamespace).Patch(context.Background(), backupName, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
 // ScaleDeployment is used to scale a deployment to specific replica count
 // labelSelectors, namespace, deploymentName are used to identify deployments
 // and specific pods associated with them.
@@ -196,7 +234,7 @@ func (k *K8sImpl) ScaleDeployment(labelSelector, namespace, deploymentName strin
 	if desiredValue > currentValue {
 		//log.Info("Scaling up pods ...")
 		message := "Wait for pods to come up"
-		_, err := futil.WaitRandom(message, timeout, k.Log)
+		_, err := futil.WaitRandom(message, timeout, k.WaitSettings, k.Log)
 		if err != nil {
 			return err
 		}
@@ -324,11 +362,11 @@ func (k *K8sImpl) CheckPodStatus(podName, namespace, checkFlag string, timeout s
 				fmt.Printf("timeSeconds = %v, totalSeconds = %v ", timeSeconds, totalSeconds)
 				if timeSeconds < totalSeconds {
 					message := fmt.Sprintf("Pod '%s' is in '%s' state", pod.Name, pod.Status.Phase)
-					duration, err := futil.WaitRandom(message, timeout, k.Log)
+					duration, err := futil.WaitRandom(message, timeout, k.WaitSettings, k.Log)
 					if err != nil {
 						return err
 					}
-					timeSeconds = timeSeconds + float64(duration)
+					timeSeconds = timeSeconds + duration.Seconds()
 
 				} else {
 					return fmt.Errorf("Timeout '%s' exceeded. Pod '%s' is still not in running state", timeout, pod.Name)
@@ -346,7 +384,7 @@ func (k *K8sImpl) CheckAllPodsAfterRestore(opensearchVar *opensearch.OpensearchV
 	timeout := futil.GetEnvWithDefault(constants.OpenSearchHealthCheckTimeoutKey, constants.OpenSearchHealthCheckTimeoutDefaultValue)
 
 	message := "Waiting for OpenSearch Operator to come up"
-	_, err := futil.WaitRandom(message, timeout, k.Log)
+	_, err := futil.WaitRandom(message, timeout, k.WaitSettings, k.Log)
 	if err != nil {
 		return err
 	}
@@ -472,6 +510,10 @@ func (k *K8sImpl) UpdateKeystore(connData *model.ConnectionData, timeout string,
 		return false, err
 	}
 	for _, pod := range esMasterPods.Items {
+		if isKeystoreMounted(&pod) { //nolint:gosec //#gosec G601
+			k.Log.Infof("Keystore of pod '%s' is mounted from a secret, skipping keystore update", pod.Name)
+			continue
+		}
 		err = k.ExecRetry(&pod, masterPodContainerName, timeout, accessKeyCmd) //nolint:gosec //#gosec G601
 		if err != nil {
 			k.Log.Errorf("Unable to exec into pod %s due to %v", pod.Name, err)
@@ -494,6 +536,10 @@ func (k *K8sImpl) UpdateKeystore(connData *model.ConnectionData, timeout string,
 	}
 
 	for _, pod := range esDataPods.Items {
+		if isKeystoreMounted(&pod) { //nolint:gosec //#gosec G601
+			k.Log.Infof("Keystore of pod '%s' is mounted from a secret, skipping keystore update", pod.Name)
+			continue
+		}
 		err = k.ExecRetry(&pod, dataPodContainerName, timeout, accessKeyCmd) //nolint:gosec //#gosec G601
 		if err != nil {
 			k.Log.Errorf("Unable to exec into pod %s due to %v", pod.Name, err)
@@ -511,6 +557,16 @@ func (k *K8sImpl) UpdateKeystore(connData *model.ConnectionData, timeout string,
 
 }
 
+// isKeystoreMounted returns true if a pre-populated keystore is mounted on the pod, which does not need to be updated
+func isKeystoreMounted(pod *v1.Pod) bool {
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Name == constants.OpenSearchKeystoreVolumeName {
+			return true
+		}
+	}
+	return false
+}
+
 func (k *K8sImpl) ExecRetry(pod *v1.Pod, container, timeout string, execCmd []string) error {
 	var timeSeconds float64
 	done := false
@@ -528,11 +584,11 @@ func (k *K8sImpl) ExecRetry(pod *v1.Pod, container, timeout string, execCmd []st
 		if err != nil {
 			if timeSeconds < totalSeconds {
 				message := fmt.Sprintf("Unable to exec into pod '%s'", pod.Name)
-				duration, err := futil.WaitRandom(message, timeout, k.Log)
+				duration, err := futil.WaitRandom(message, timeout, k.WaitSettings, k.Log)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				timeSeconds = timeSeconds + duration.Seconds()
 			} else {
 				k.Log.Errorf("Global timeout '%s' exceeded. Unable to exec into pod", timeout)
 				return err
@@ -675,11 +731,11 @@ func (k *K8sImpl) CheckBootstrapResources() error {
 		if waitForSecurityJobPod || waitForBootstrapPod {
 			fmt.Printf("timeSeconds = %v, totalSeconds = %v ", timeSeconds, totalSeconds)
 			if timeSeconds < totalSeconds {
-				duration, err := futil.WaitRandom(message, timeout, k.Log)
+				duration, err := futil.WaitRandom(message, timeout, k.WaitSettings, k.Log)
 				if err != nil {
 					return err
 				}
-				timeSeconds = timeSeconds + float64(duration)
+				timeSeconds = timeSeconds + duration.Seconds()
 
 			} else {
 				return fmt.Errorf("Timeout '%s' exceeded. Required pods for bootstrapping the cluster still doesn't exist", timeout)
@@ -704,3 +760,34 @@ func (k *K8sImpl) DeleteOpenSearchService() error {
 	}
 	return err
 }
+
+// PodTerminationPollInterval is the interval at which WaitForPodsTerminated checks the pods, to be overridden during unit tests
+var PodTerminationPollInterval = 5 * time.Second
+
+// WaitForPodsTerminated waits until all pods matching the label selector in the namespace are terminated
+// It returns an error if pods are still present once the timeout has elapsed.
+func (k *K8sImpl) WaitForPodsTerminated(labelSelector, namespace, timeout string) error {
+	k.Log.Infof("Waiting for pods with labelselector '%v' in namespace '%s' to terminate", labelSelector, namespace)
+	timeParse, err := time.ParseDuration(timeout)
+	if err != nil {
+		k.Log.Errorf("Unable to parse time duration: %v", err)
+		return err
+	}
+	deadline := time.Now().Add(timeParse)
+	listOptions := metav1.ListOptions{LabelSelector: labelSelector}
+	for {
+		pods, err := k.K8sInterface.CoreV1().Pods(namespace).List(context.TODO(), listOptions)
+		if err != nil {
+			return err
+		}
+		if len(pods.Items) == 0 {
+			k.Log.Infof("All pods with labelselector '%v' in namespace '%s' are terminated", labelSelector, namespace)
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("Timeout '%s' exceeded. %d pods with labelselector '%v' in namespace '%s' are still not terminated", timeout, len(pods.Items), labelSelector, namespace)
+		}
+		k.Log.Infof("%d pods with labelselector '%v' in namespace '%s' are still terminating", len(pods.Items), labelSelector, namespace)
+		time.Sleep(PodTerminationPollInterval)
+	}
+}
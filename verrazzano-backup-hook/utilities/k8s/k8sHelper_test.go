@@ -9,21 +9,25 @@ import (
 	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/constants"
 	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/log"
 	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/opensearch"
+	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/types"
 	kutil "github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/utilities/k8s"
 	vmofake "github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/utilities/k8s/fake"
 	"go.uber.org/zap"
 	apps "k8s.io/api/apps/v1"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"os"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"strconv"
 	"strings"
 	"sync"
 	"testing"
+	"time"
 )
 
 var (
@@ -79,6 +83,84 @@ func TestPopulateConnData(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+// TestPopulateFSConnData tests the PopulateFSConnData method for the following use case.
+// GIVEN a Velero backup name, of a backup with and without a backup storage location
+// WHEN invoked
+// THEN the connection data of the backup is returned without its backup storage location, and an error is returned if the backup does not exist
+func TestPopulateFSConnData(t *testing.T) {
+	t.Parallel()
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	backup := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Backup",
+		"metadata": map[string]interface{}{
+			"name":      "foo",
+			"namespace": constants.VeleroNameSpace,
+		},
+		"spec": map[string]interface{}{
+			"storageLocation": "default",
+			"hooks": map[string]interface{}{
+				"resources": []interface{}{
+					map[string]interface{}{
+						"post": []interface{}{
+							map[string]interface{}{
+								"exec": map[string]interface{}{"timeout": "30m"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}}
+	var clientk client.Client
+	fc := fake.NewSimpleClientset()
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), backup)
+
+	k8s := kutil.New(dclient, clientk, fc, nil, "default", log)
+	conData, err := k8s.PopulateFSConnData(constants.VeleroNameSpace, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", conData.BackupName)
+	assert.Equal(t, "30m", conData.VeleroTimeout)
+	assert.Empty(t, conData.Secret)
+	assert.Empty(t, conData.BucketName)
+
+	conData, err = k8s.PopulateFSConnData(constants.VeleroNameSpace, "bar")
+	assert.Nil(t, conData)
+	assert.Error(t, err)
+}
+
+// TestAnnotateBackup tests the AnnotateBackup method for the following use case.
+// GIVEN a Velero backup name
+// WHEN invoked with the snapshot name annotation
+// THEN the annotation is set on the backup, and an error is returned if the backup does not exist
+func TestAnnotateBackup(t *testing.T) {
+	t.Parallel()
+	log, f := logHelper()
+	defer os.Remove(f)
+
+	backup := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "velero.io/v1",
+		"kind":       "Backup",
+		"metadata": map[string]interface{}{
+			"name":      "foo",
+			"namespace": constants.VeleroNameSpace,
+		},
+	}}
+	var clientk client.Client
+	fc := fake.NewSimpleClientset()
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), backup)
+
+	k8s := kutil.New(dclient, clientk, fc, nil, "default", log)
+	assert.NoError(t, k8s.AnnotateBackup(constants.VeleroNameSpace, "foo", constants.SnapshotNameAnnotation, "daily-2023-03-21"))
+	annotated, err := k8s.GetBackup(constants.VeleroNameSpace, "foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "daily-2023-03-21", annotated.Metadata.Annotations.VerrazzanoIoOpenSearchSnapshotName)
+
+	assert.Error(t, k8s.AnnotateBackup(constants.VeleroNameSpace, "bar", constants.SnapshotNameAnnotation, "daily-2023-03-21"))
+}
+
 // TestGetBackupStorageLocation tests the GetBackupStorageLocation method for the following use case.
 // GIVEN a Velero backup storage location name
 // WHEN invoked
@@ -399,3 +481,109 @@ func TestExecRetry(t *testing.T) {
 	err := k8s.ExecRetry(pod, constants.OpenSearchDataPodContainerName, "1s", accessKeyCmd)
 	assert.Nil(t, err)
 }
+
+// TestWaitForPodsTerminated tests the WaitForPodsTerminated method for the following use case.
+// GIVEN k8s client
+// WHEN OSD is drained before a restore
+// THEN the wait succeeds once no pods match the label selector, and fails if pods are still present after the timeout
+func TestWaitForPodsTerminated(t *testing.T) {
+	defaultInterval := kutil.PodTerminationPollInterval
+	kutil.PodTerminationPollInterval = 10 * time.Millisecond
+	defer func() { kutil.PodTerminationPollInterval = defaultInterval }()
+
+	osdPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "osd",
+			Namespace: constants.VerrazzanoSystemNamespace,
+			Labels:    map[string]string{"app": "system-osd"},
+		},
+	}
+	otherPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "grafana",
+			Namespace: constants.VerrazzanoSystemNamespace,
+			Labels:    map[string]string{"app": "system-grafana"},
+		},
+	}
+
+	log, f := logHelper()
+	defer os.Remove(f)
+	var clientk client.Client
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+
+	// No OSD pods
+	fc := fake.NewSimpleClientset(&otherPod)
+	k8s := kutil.New(dclient, clientk, fc, nil, "default", log)
+	err := k8s.WaitForPodsTerminated(constants.KibanaLabelSelector, constants.VerrazzanoSystemNamespace, "1s")
+	assert.Nil(t, err)
+
+	// OSD pod never terminates
+	fc = fake.NewSimpleClientset(&osdPod, &otherPod)
+	k8s = kutil.New(dclient, clientk, fc, nil, "default", log)
+	err = k8s.WaitForPodsTerminated(constants.KibanaLabelSelector, constants.VerrazzanoSystemNamespace, "50ms")
+	assert.NotNil(t, err)
+
+	// OSD pod terminates while waiting
+	listCount := 0
+	fc.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		listCount++
+		if listCount == 3 {
+			return false, nil, fc.Tracker().Delete(v1.SchemeGroupVersion.WithResource("pods"), osdPod.Namespace, osdPod.Name)
+		}
+		return false, nil, nil
+	})
+	err = k8s.WaitForPodsTerminated(constants.KibanaLabelSelector, constants.VerrazzanoSystemNamespace, "10s")
+	assert.Nil(t, err)
+	assert.Equal(t, 3, listCount)
+
+	// Invalid timeout
+	err = k8s.WaitForPodsTerminated(constants.KibanaLabelSelector, constants.VerrazzanoSystemNamespace, "invalid")
+	assert.NotNil(t, err)
+}
+
+// TestUpdateKeystoreMounted tests the UpdateKeystore method for the following use case.
+// GIVEN k8s client
+// WHEN the OpenSearch master and data pods mount a pre-populated keystore
+// THEN the keystore of the pods is not updated by exec-ing into them
+func TestUpdateKeystoreMounted(t *testing.T) {
+	keystoreVolume := v1.Volume{
+		Name: constants.OpenSearchKeystoreVolumeName,
+		VolumeSource: v1.VolumeSource{
+			Projected: &v1.ProjectedVolumeSource{},
+		},
+	}
+	masterPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmi-system-es-master-0",
+			Namespace: constants.VerrazzanoSystemNamespace,
+			Labels:    map[string]string{"opensearch.verrazzano.io/role-master": "true"},
+		},
+		Spec: v1.PodSpec{Volumes: []v1.Volume{keystoreVolume}},
+	}
+	dataPod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "vmi-system-es-data-0",
+			Namespace: constants.VerrazzanoSystemNamespace,
+			Labels:    map[string]string{"opensearch.verrazzano.io/role-data": "true"},
+		},
+		Spec: v1.PodSpec{Volumes: []v1.Volume{keystoreVolume}},
+	}
+
+	log, f := logHelper()
+	defer os.Remove(f)
+	var clientk client.Client
+	dclient := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+	fc := fake.NewSimpleClientset(&masterPod, &dataPod)
+	// there is no rest config, so exec-ing into a pod would fail
+	k8s := kutil.New(dclient, clientk, fc, nil, "default", log)
+
+	conData := types.ConnectionData{
+		Secret: types.ObjectStoreSecret{
+			ObjectAccessKey: "alphalapha",
+			ObjectSecretKey: "betabetabeta",
+		},
+	}
+	ok, err := k8s.UpdateKeystore(&conData, "1s", opensearch.NewOpensearchVar(true))
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
@@ -0,0 +1,68 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package utilities
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/constants"
+	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/types"
+	"net/http"
+	"net/url"
+)
+
+// ValidateNotifyURL validates that the notification webhook URL is an absolute http or https URL.
+// An empty URL is considered valid, as it means no notification is sent.
+func ValidateNotifyURL(notifyURL string) error {
+	if notifyURL == "" {
+		return nil
+	}
+	u, err := url.ParseRequestURI(notifyURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("Invalid notification URL '%s'. It has to be an absolute http or https URL", notifyURL)
+	}
+	return nil
+}
+
+// NotifyWebhook posts the completion notification of a backup or restore to the webhook as JSON, with the summary
+// of the notification as text.
+// An error is returned if the webhook cannot be reached or does not accept the notification.
+func NotifyWebhook(client *http.Client, notifyURL string, payload types.NotificationPayload) error {
+	payload.Text = NotificationText(payload)
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("Unable to marshal the notification: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.NotificationTimeout)
+	defer cancel()
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, notifyURL, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("Unable to create the notification request: %v", err)
+	}
+	request.Header.Add("Content-Type", constants.HTTPContentType)
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("Unable to send the notification to '%s': %v", notifyURL, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("Notification to '%s' was rejected with status %d", notifyURL, response.StatusCode)
+	}
+	return nil
+}
+
+// NotificationText returns the summary of the completion notification of a backup or restore, e.g.
+// "Verrazzano backup of snapshot 'daily-2023-03-21' on cluster 'mango' succeeded in 1m30s"
+func NotificationText(payload types.NotificationPayload) string {
+	text := fmt.Sprintf("Verrazzano %s of snapshot '%s'", payload.Operation, payload.SnapshotName)
+	if payload.Cluster != "" {
+		text += fmt.Sprintf(" on cluster '%s'", payload.Cluster)
+	}
+	if payload.Status == constants.NotificationStatusFailure {
+		return fmt.Sprintf("%s failed after %s: %s", text, payload.Duration, payload.Error)
+	}
+	return fmt.Sprintf("%s succeeded in %s", text, payload.Duration)
+}
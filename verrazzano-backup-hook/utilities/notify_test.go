@@ -0,0 +1,97 @@
+// Copyright (C) 2023, Oracle and/or its affiliates.
+// Licensed under the Universal Permissive License v 1.0 as shown at https://oss.oracle.com/licenses/upl.
+
+package utilities_test
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/constants"
+	model "github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/types"
+	utils "github.com/verrazzano/verrazzano-monitoring-operator/verrazzano-backup-hook/utilities"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// TestNotifyWebhook tests the NotifyWebhook method for the following use case.
+// GIVEN a mock webhook server
+// WHEN the completion notification of a backup is sent
+// THEN the payload is posted to the webhook as JSON
+func TestNotifyWebhook(t *testing.T) {
+	t.Parallel()
+	var received model.NotificationPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, constants.HTTPContentType, r.Header.Get("Content-Type"))
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	payload := model.NotificationPayload{
+		Operation:    constants.BackupOperation,
+		Status:       constants.NotificationStatusSuccess,
+		SnapshotName: "daily-2023-03-21",
+		Duration:     "1m30s",
+		Cluster:      "mango",
+	}
+	err := utils.NotifyWebhook(server.Client(), server.URL, payload)
+	assert.Nil(t, err)
+	payload.Text = "Verrazzano backup of snapshot 'daily-2023-03-21' on cluster 'mango' succeeded in 1m30s"
+	assert.Equal(t, payload, received)
+}
+
+// TestNotificationText tests the NotificationText method for the following use case.
+// GIVEN the completion notification of a failed operation, with and without a cluster name
+// WHEN the summary of the notification is built
+// THEN the summary names the operation, the snapshot, the cluster if any, and the error
+func TestNotificationText(t *testing.T) {
+	t.Parallel()
+	payload := model.NotificationPayload{
+		Operation:    constants.RestoreOperation,
+		Status:       constants.NotificationStatusFailure,
+		SnapshotName: "daily-2023-03-21",
+		Duration:     "10s",
+		Error:        "snapshot not found",
+	}
+	assert.Equal(t, "Verrazzano restore of snapshot 'daily-2023-03-21' failed after 10s: snapshot not found", utils.NotificationText(payload))
+	payload.Cluster = "mango"
+	assert.Equal(t, "Verrazzano restore of snapshot 'daily-2023-03-21' on cluster 'mango' failed after 10s: snapshot not found", utils.NotificationText(payload))
+}
+
+// TestNotifyWebhookFailure tests the NotifyWebhook method for the following use case.
+// GIVEN a mock webhook server rejecting the notifications, or an unreachable webhook
+// WHEN the completion notification of a restore is sent
+// THEN an error is returned
+func TestNotifyWebhookFailure(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	payload := model.NotificationPayload{
+		Operation: constants.RestoreOperation,
+		Status:    constants.NotificationStatusFailure,
+		Error:     "snapshot not found",
+	}
+	err := utils.NotifyWebhook(server.Client(), server.URL, payload)
+	assert.ErrorContains(t, err, "rejected with status 500")
+
+	server.Close()
+	err = utils.NotifyWebhook(server.Client(), server.URL, payload)
+	assert.ErrorContains(t, err, "Unable to send the notification")
+}
+
+// TestValidateNotifyURL tests the ValidateNotifyURL method for the following use case.
+// GIVEN a notification webhook URL
+// WHEN the URL is validated
+// THEN an error is returned unless the URL is empty or an absolute http or https URL
+func TestValidateNotifyURL(t *testing.T) {
+	t.Parallel()
+	for _, notifyURL := range []string{"", "http://hooks.example.com/backup", "https://hooks.slack.com/services/T0/B0/X"} {
+		assert.Nil(t, utils.ValidateNotifyURL(notifyURL), notifyURL)
+	}
+	for _, notifyURL := range []string{"hooks.example.com", "ftp://hooks.example.com", "https://", "/backup"} {
+		assert.NotNil(t, utils.ValidateNotifyURL(notifyURL), notifyURL)
+	}
+}